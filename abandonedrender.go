@@ -0,0 +1,73 @@
+package templ
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AbandonedRenderError is passed to the ErrorHandler of a ComponentHandler when rendering is
+// abandoned because the context of the request was cancelled, e.g. because the client
+// disconnected, or the deadline of the request was exceeded. It can be used to find pages
+// that do a lot of wasted work for clients that have gone away.
+//
+//	templ.WithErrorHandler(func(r *http.Request, err error) http.Handler {
+//		var abandoned *templ.AbandonedRenderError
+//		if errors.As(err, &abandoned) {
+//			slog.Info("render abandoned", slog.String("component", abandoned.Component), slog.Int("discarded", abandoned.Discarded))
+//		}
+//		...
+//	})
+//
+// The error wraps the context error, so errors.Is(err, context.Canceled) is still true.
+type AbandonedRenderError struct {
+	// Component is the name of the generated component that was rendering when the
+	// cancellation was found, e.g. "pages.Home". It's empty if the component isn't known.
+	Component string
+	// Discarded is the number of bytes of buffered output that were thrown away. Streamed
+	// responses aren't buffered, so nothing is discarded.
+	Discarded int
+	// Err is the context error, context.Canceled or context.DeadlineExceeded.
+	Err error
+}
+
+func (e *AbandonedRenderError) Error() string {
+	component := e.Component
+	if component == "" {
+		component = "unknown component"
+	}
+	return fmt.Sprintf("templ: render abandoned in %s, discarded %d bytes: %v", component, e.Discarded, e.Err)
+}
+
+func (e *AbandonedRenderError) Unwrap() error {
+	return e.Err
+}
+
+// SetAbandonedComponent is used by generated code to record the name of the component that
+// returned a context error, see AbandonedRenderError. Only the first name is kept, which is
+// the innermost component, since errors are returned from the innermost component outwards.
+func SetAbandonedComponent(ctx context.Context, name string) {
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	if !ok || v.abandonedComponent != "" {
+		return
+	}
+	v.abandonedComponent = name
+}
+
+// isContextError returns true if err was caused by the context being cancelled, or its
+// deadline being exceeded.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// newAbandonedRenderError wraps err in an AbandonedRenderError if it's a context error.
+func newAbandonedRenderError(ctx context.Context, err error, discarded int) error {
+	if !isContextError(err) {
+		return err
+	}
+	e := &AbandonedRenderError{Discarded: discarded, Err: err}
+	if v, ok := ctx.Value(contextKey).(*contextValue); ok {
+		e.Component = v.abandonedComponent
+	}
+	return e
+}
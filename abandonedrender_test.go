@@ -0,0 +1,89 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	templruntime "github.com/a-h/templ/runtime"
+)
+
+func abandonedChild() templ.Component {
+	return templruntime.GeneratedTemplate(func(in templruntime.GeneratedComponentInput) error {
+		if err := in.Context.Err(); err != nil {
+			return err
+		}
+		_, err := io.WriteString(in.Writer, "<p>child</p>")
+		return err
+	})
+}
+
+func abandonedPage(cancel context.CancelFunc) templ.Component {
+	return templruntime.GeneratedTemplate(func(in templruntime.GeneratedComponentInput) error {
+		if _, err := io.WriteString(in.Writer, "<h1>page</h1>"); err != nil {
+			return err
+		}
+		// The client disconnects before the child is rendered.
+		cancel()
+		return abandonedChild().Render(in.Context, in.Writer)
+	})
+}
+
+func TestAbandonedRenderError(t *testing.T) {
+	serve := func(t *testing.T, options ...func(*templ.ComponentHandler)) (err error) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		options = append(options, templ.WithErrorHandler(func(r *http.Request, e error) http.Handler {
+			err = e
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+		}))
+		r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+		templ.Handler(abandonedPage(cancel), options...).ServeHTTP(httptest.NewRecorder(), r)
+		return err
+	}
+	t.Run("the error handler receives the discarded output and the active component", func(t *testing.T) {
+		err := serve(t)
+		var abandoned *templ.AbandonedRenderError
+		if !errors.As(err, &abandoned) {
+			t.Fatalf("expected an AbandonedRenderError, got %v (%T)", err, err)
+		}
+		if abandoned.Component != "templ_test.abandonedChild" {
+			t.Errorf("expected the component to be templ_test.abandonedChild, got %q", abandoned.Component)
+		}
+		if expected := len("<h1>page</h1>"); abandoned.Discarded != expected {
+			t.Errorf("expected %d bytes to be discarded, got %d", expected, abandoned.Discarded)
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the error to wrap context.Canceled, got %v", err)
+		}
+		expected := "templ: render abandoned in templ_test.abandonedChild, discarded 13 bytes: context canceled"
+		if err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+	})
+	t.Run("streamed responses don't discard output", func(t *testing.T) {
+		err := serve(t, templ.WithStreaming())
+		var abandoned *templ.AbandonedRenderError
+		if !errors.As(err, &abandoned) {
+			t.Fatalf("expected an AbandonedRenderError, got %v (%T)", err, err)
+		}
+		if abandoned.Discarded != 0 {
+			t.Errorf("expected no bytes to be discarded, got %d", abandoned.Discarded)
+		}
+		if abandoned.Component != "templ_test.abandonedChild" {
+			t.Errorf("expected the component to be templ_test.abandonedChild, got %q", abandoned.Component)
+		}
+	})
+	t.Run("the component is unknown if it wasn't generated", func(t *testing.T) {
+		err := (&templ.AbandonedRenderError{Discarded: 5, Err: context.DeadlineExceeded}).Error()
+		expected := "templ: render abandoned in unknown component, discarded 5 bytes: context deadline exceeded"
+		if err != expected {
+			t.Errorf("expected %q, got %q", expected, err)
+		}
+	})
+}
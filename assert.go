@@ -0,0 +1,98 @@
+package templ
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// assertionsEnabled is true in development mode, i.e. when the templ generate -watch command
+// is used. Assert and Require don't check anything in production.
+var assertionsEnabled = os.Getenv("TEMPL_DEV_MODE") == "true"
+
+// AssertionError is the panic value of a failed Assert or Require. Generated components
+// recover it and return it as the error of rendering, so it's handled like other render
+// errors, e.g. by the ErrorHandler of a ComponentHandler.
+type AssertionError struct {
+	// Message describes the assertion that failed.
+	Message string
+	// FileName and Line of the call to Assert or Require, usually in a generated _templ.go
+	// file. The stacktrace package can be used to find the line of the .templ file.
+	FileName string
+	Line     int
+	// Components are the names of the generated components that were being rendered,
+	// innermost first, e.g. "components.Card".
+	Components []string
+}
+
+func (e *AssertionError) Error() string {
+	msg := "templ: assertion failed"
+	if e.FileName != "" {
+		msg += fmt.Sprintf(" at %s:%d", e.FileName, e.Line)
+	}
+	if len(e.Components) > 0 {
+		msg += " in " + strings.Join(e.Components, " < ")
+	}
+	return msg + ": " + e.Message
+}
+
+// AddComponent is used by generated code to add the name of a component to the error as the
+// error is returned through the components that were being rendered.
+func (e *AssertionError) AddComponent(name string) {
+	e.Components = append(e.Components, name)
+}
+
+// Assert checks that cond is true while developing templates. It's intended to be used in the
+// Go code of templates to document and check what the template expects of its parameters.
+//
+//	templ list(items []Item) {
+//		{{ templ.Assert(len(items) > 0, "list requires at least one item") }}
+//		...
+//	}
+//
+// In development mode, i.e. when the templ generate -watch command is used, Assert panics
+// with an *AssertionError if cond is false, and the component that's being rendered returns
+// the error. In production, Assert does nothing.
+func Assert(cond bool, msg string) {
+	if !assertionsEnabled || cond {
+		return
+	}
+	panic(newAssertionError(msg))
+}
+
+// Require returns val, and checks that it isn't nil while developing templates, like Assert.
+// Values that can't be nil, e.g. strings and structs, always pass the check.
+//
+//	templ profile(p *Profile) {
+//		{{ user := templ.Require(p.User) }}
+//		<h1>{ user.Name }</h1>
+//	}
+func Require[T any](val T) T {
+	if !assertionsEnabled || !isNil(val) {
+		return val
+	}
+	panic(newAssertionError(fmt.Sprintf("required %s is nil", reflect.TypeFor[T]())))
+}
+
+func isNil(val any) bool {
+	if val == nil {
+		return true
+	}
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// newAssertionError returns an error for the caller of Assert or Require.
+func newAssertionError(msg string) *AssertionError {
+	e := &AssertionError{Message: msg}
+	if _, file, line, ok := runtime.Caller(2); ok {
+		e.FileName, e.Line = file, line
+	}
+	return e
+}
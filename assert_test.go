@@ -0,0 +1,61 @@
+package templ
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAssert(t *testing.T) {
+	expectPanic := func(t *testing.T, message string, f func()) {
+		t.Helper()
+		defer func() {
+			err, ok := recover().(error)
+			var assertErr *AssertionError
+			if !ok || !errors.As(err, &assertErr) {
+				t.Fatalf("expected an assertion error, got %v", err)
+			}
+			if assertErr.Message != message {
+				t.Errorf("expected message %q, got %q", message, assertErr.Message)
+			}
+			if !strings.HasSuffix(assertErr.FileName, "assert_test.go") {
+				t.Errorf("expected the position of the caller, got %s:%d", assertErr.FileName, assertErr.Line)
+			}
+		}()
+		f()
+	}
+	t.Run("assertions aren't checked outside of development mode", func(t *testing.T) {
+		Assert(false, "not checked")
+		if v := Require[*string](nil); v != nil {
+			t.Errorf("expected nil, got %v", v)
+		}
+	})
+	assertionsEnabled = true
+	defer func() { assertionsEnabled = false }()
+	t.Run("passing assertions do nothing", func(t *testing.T) {
+		Assert(true, "passes")
+		s := "value"
+		if v := Require(&s); v != &s {
+			t.Errorf("expected the value to be returned")
+		}
+		if v := Require(""); v != "" {
+			t.Errorf("expected an empty string, got %q", v)
+		}
+	})
+	t.Run("failed assertions panic in development mode", func(t *testing.T) {
+		expectPanic(t, "items are required", func() { Assert(false, "items are required") })
+	})
+	t.Run("nil values fail Require in development mode", func(t *testing.T) {
+		expectPanic(t, "required *string is nil", func() { Require[*string](nil) })
+		expectPanic(t, "required templ.Component is nil", func() { Require[Component](nil) })
+	})
+	t.Run("errors include the position and components", func(t *testing.T) {
+		err := &AssertionError{Message: "items are required", FileName: "list_templ.go", Line: 42}
+		err.AddComponent("components.List")
+		err.AddComponent("pages.Home")
+		expected := "templ: assertion failed at list_templ.go:42 in components.List < pages.Home: items are required"
+		if err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+	})
+}
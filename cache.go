@@ -0,0 +1,277 @@
+package templ
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheMaxEntries is the number of responses that a CacheMiddleware stores by default.
+const DefaultCacheMaxEntries = 1000
+
+// NewCacheMiddleware creates HTTP middleware that caches the successful responses of next, so
+// that pages aren't rendered for each request. Responses are stored under the key returned by
+// key, e.g. the URL path, and requests that return an empty key aren't cached.
+//
+// Cached responses are served for maxAge. Set StaleWhileRevalidate to keep serving responses
+// for longer while they're rendered again in the background.
+//
+// Responses that set cookies, have a Vary header, or have a Cache-Control header with the
+// private or no-store directives, aren't cached.
+func NewCacheMiddleware(next http.Handler, key func(r *http.Request) string, maxAge time.Duration) *CacheMiddleware {
+	return &CacheMiddleware{
+		Next:       next,
+		Key:        key,
+		MaxAge:     maxAge,
+		MaxEntries: DefaultCacheMaxEntries,
+		store:      newMemoryStore[*cacheEntry](DefaultCacheMaxEntries),
+		calls:      map[string]*cacheCall{},
+	}
+}
+
+// CacheMiddleware caches the responses of a handler, see NewCacheMiddleware.
+type CacheMiddleware struct {
+	Next http.Handler
+	// Key returns the cache key of the request. Requests with an empty key aren't cached.
+	Key func(r *http.Request) string
+	// MaxAge is how long a response is served from the cache before it's stale.
+	MaxAge time.Duration
+	// StaleWhileRevalidate is how long a stale response is served while it's rendered again
+	// in the background.
+	StaleWhileRevalidate time.Duration
+	// MaxEntries is the number of responses that are stored. When it's exceeded, the least
+	// recently used responses are removed. If it's zero or less, the number isn't limited.
+	MaxEntries int
+	// ErrorLog is the logger for panics of Next while a stale response is rendered again in
+	// the background. If it's nil, the standard logger of the log package is used.
+	ErrorLog *log.Logger
+
+	// m guards the revalidation of entries, and calls.
+	m     sync.Mutex
+	store *memoryStore[*cacheEntry]
+	// calls are the renders of responses that aren't in the cache, so that concurrent
+	// requests for the same key wait for a single render.
+	calls map[string]*cacheCall
+}
+
+type cacheEntry struct {
+	header       http.Header
+	status       int
+	body         []byte
+	created      time.Time
+	revalidating bool
+}
+
+// cacheCall is a render of a response that isn't in the cache. entry is set before done is
+// closed, if the response can be cached.
+type cacheCall struct {
+	done  chan struct{}
+	entry *cacheEntry
+}
+
+func (cm *CacheMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		cm.Next.ServeHTTP(w, r)
+		return
+	}
+	key := cm.Key(r)
+	if key == "" {
+		cm.Next.ServeHTTP(w, r)
+		return
+	}
+
+	cm.m.Lock()
+	e, ok := cm.store.get(key)
+	if ok {
+		age := cm.store.now().Sub(e.created)
+		if age < cm.MaxAge {
+			cm.m.Unlock()
+			e.write(w)
+			return
+		}
+		if age < cm.MaxAge+cm.StaleWhileRevalidate {
+			if !e.revalidating {
+				e.revalidating = true
+				rr := r.Clone(context.WithoutCancel(r.Context()))
+				rr.Method = http.MethodGet
+				go cm.revalidate(key, e, rr)
+			}
+			cm.m.Unlock()
+			e.write(w)
+			return
+		}
+	}
+	// Responses to HEAD requests don't have a body, so they can't be served to GET requests.
+	if r.Method == http.MethodHead {
+		cm.m.Unlock()
+		cm.Next.ServeHTTP(w, r)
+		return
+	}
+	if c, ok := cm.calls[key]; ok {
+		cm.m.Unlock()
+		select {
+		case <-c.done:
+		case <-r.Context().Done():
+			return
+		}
+		if c.entry != nil {
+			c.entry.write(w)
+			return
+		}
+		// The response can't be shared, e.g. because it sets a cookie.
+		cm.Next.ServeHTTP(w, r)
+		return
+	}
+	c := &cacheCall{done: make(chan struct{})}
+	cm.calls[key] = c
+	cm.m.Unlock()
+
+	// If rendering panics, waiting requests render their own responses, and the panic is
+	// passed on to the server.
+	defer func() {
+		cm.m.Lock()
+		// The call is removed if the key is invalidated while it's being rendered.
+		if cm.calls[key] == c {
+			delete(cm.calls, key)
+			if c.entry != nil {
+				cm.set(key, c.entry)
+			}
+		}
+		cm.m.Unlock()
+		close(c.done)
+	}()
+	e = cm.render(r)
+	if e.cacheable() {
+		c.entry = e
+	}
+	e.write(w)
+}
+
+// set stores the entry. cm.m must be held.
+func (cm *CacheMiddleware) set(key string, e *cacheEntry) {
+	cm.store.setMaxEntries(cm.MaxEntries)
+	cm.store.set(key, e, cm.MaxAge+cm.StaleWhileRevalidate)
+}
+
+// render renders the response of the next handler.
+func (cm *CacheMiddleware) render(r *http.Request) *cacheEntry {
+	rec := &cacheRecorder{header: http.Header{}}
+	cm.Next.ServeHTTP(rec, r)
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &cacheEntry{
+		header:  rec.header,
+		status:  status,
+		body:    rec.body.Bytes(),
+		created: cm.store.now(),
+	}
+}
+
+// revalidate renders the response again, and replaces the stale entry, unless it has been
+// invalidated in the meantime. If rendering fails, the stale entry is kept.
+//
+// Unlike renders of requests, there's no server to recover panics of the background render,
+// so they're logged instead of crashing the program.
+func (cm *CacheMiddleware) revalidate(key string, stale *cacheEntry, r *http.Request) {
+	var e *cacheEntry
+	defer func() {
+		if p := recover(); p != nil {
+			cm.logf("templ: panic revalidating cached response %q: %v\n%s", key, p, debug.Stack())
+		}
+		cm.m.Lock()
+		defer cm.m.Unlock()
+		stale.revalidating = false
+		if current, ok := cm.store.get(key); ok && current == stale && e != nil && e.cacheable() {
+			cm.set(key, e)
+		}
+	}()
+	e = cm.render(r)
+}
+
+func (cm *CacheMiddleware) logf(format string, args ...any) {
+	if cm.ErrorLog != nil {
+		cm.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// Invalidate removes the responses with the keys from the cache.
+func (cm *CacheMiddleware) Invalidate(keys ...string) {
+	cm.m.Lock()
+	defer cm.m.Unlock()
+	cm.store.delete(keys...)
+	for _, key := range keys {
+		delete(cm.calls, key)
+	}
+}
+
+// InvalidateFunc removes the responses with keys that match from the cache, e.g. all of the
+// pages under a path prefix.
+func (cm *CacheMiddleware) InvalidateFunc(match func(key string) bool) {
+	cm.m.Lock()
+	defer cm.m.Unlock()
+	cm.store.deleteFunc(match)
+	for key := range cm.calls {
+		if match(key) {
+			delete(cm.calls, key)
+		}
+	}
+}
+
+// cacheable returns true if the response can be served to other requests. Responses with a
+// Vary header depend on headers of the request that aren't part of the key, so they aren't
+// cacheable.
+func (e *cacheEntry) cacheable() bool {
+	if e.status != http.StatusOK || len(e.header.Values("Set-Cookie")) > 0 || len(e.header.Values("Vary")) > 0 {
+		return false
+	}
+	for _, v := range e.header.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			directive, _, _ = strings.Cut(strings.TrimSpace(directive), "=")
+			if strings.EqualFold(directive, "private") || strings.EqualFold(directive, "no-store") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (e *cacheEntry) write(w http.ResponseWriter) {
+	for k, v := range e.header {
+		w.Header()[k] = slices.Clone(v)
+	}
+	w.WriteHeader(e.status)
+	// Ignore write errors, like ComponentHandler does.
+	_, _ = w.Write(e.body)
+}
+
+// cacheRecorder records the response of a handler.
+type cacheRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *cacheRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *cacheRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *cacheRecorder) Write(p []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(p)
+}
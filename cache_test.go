@@ -0,0 +1,235 @@
+package templ
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheMiddleware(t *testing.T) {
+	setup := func(status int) (cm *CacheMiddleware, renders *atomic.Int32, now *time.Time, rendered chan struct{}) {
+		renders = new(atomic.Int32)
+		rendered = make(chan struct{}, 10)
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := renders.Add(1)
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("render " + strconv.Itoa(int(n))))
+			rendered <- struct{}{}
+		})
+		cm = NewCacheMiddleware(next, func(r *http.Request) string {
+			if strings.HasPrefix(r.URL.Path, "/private") {
+				return ""
+			}
+			return r.URL.Path
+		}, time.Minute)
+		now = &time.Time{}
+		cm.store.now = func() time.Time { return *now }
+		return cm, renders, now, rendered
+	}
+	get := func(t *testing.T, cm *CacheMiddleware, method, path string) string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		cm.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		if ct := w.Header().Get("Content-Type"); ct != "text/html" {
+			t.Errorf("expected the headers of the response to be cached, got Content-Type %q", ct)
+		}
+		return w.Body.String()
+	}
+	expect := func(t *testing.T, expected, actual string) {
+		t.Helper()
+		if expected != actual {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	}
+
+	t.Run("responses are cached until they're stale", func(t *testing.T) {
+		cm, _, now, _ := setup(http.StatusOK)
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		expect(t, "render 2", get(t, cm, http.MethodGet, "/b"))
+		*now = now.Add(time.Minute)
+		expect(t, "render 3", get(t, cm, http.MethodGet, "/a"))
+	})
+	t.Run("requests without a key, and other methods aren't cached", func(t *testing.T) {
+		cm, _, _, _ := setup(http.StatusOK)
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/private"))
+		expect(t, "render 2", get(t, cm, http.MethodGet, "/private"))
+		expect(t, "render 3", get(t, cm, http.MethodPost, "/a"))
+		expect(t, "render 4", get(t, cm, http.MethodGet, "/a"))
+	})
+	t.Run("unsuccessful responses aren't cached", func(t *testing.T) {
+		cm, _, _, _ := setup(http.StatusInternalServerError)
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		expect(t, "render 2", get(t, cm, http.MethodGet, "/a"))
+	})
+	t.Run("stale responses are served while they're rendered in the background", func(t *testing.T) {
+		cm, renders, now, rendered := setup(http.StatusOK)
+		cm.StaleWhileRevalidate = time.Minute
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		<-rendered
+		*now = now.Add(90 * time.Second)
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		<-rendered
+		// Wait for the revalidated response to be stored.
+		for i := 0; i < 100; i++ {
+			cm.m.Lock()
+			e, _ := cm.store.get("/a")
+			revalidating := e.revalidating
+			cm.m.Unlock()
+			if !revalidating {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		expect(t, "render 2", get(t, cm, http.MethodGet, "/a"))
+		if n := renders.Load(); n != 2 {
+			t.Errorf("expected 2 renders, got %d", n)
+		}
+		*now = now.Add(3 * time.Minute)
+		expect(t, "render 3", get(t, cm, http.MethodGet, "/a"))
+	})
+	t.Run("responses can be invalidated", func(t *testing.T) {
+		cm, _, _, _ := setup(http.StatusOK)
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		expect(t, "render 2", get(t, cm, http.MethodGet, "/b/1"))
+		expect(t, "render 3", get(t, cm, http.MethodGet, "/b/2"))
+		cm.Invalidate("/a")
+		cm.InvalidateFunc(func(key string) bool { return strings.HasPrefix(key, "/b/") })
+		expect(t, "render 4", get(t, cm, http.MethodGet, "/a"))
+		expect(t, "render 5", get(t, cm, http.MethodGet, "/b/1"))
+		expect(t, "render 6", get(t, cm, http.MethodGet, "/b/2"))
+	})
+	t.Run("panics while revalidating in the background keep the stale response", func(t *testing.T) {
+		var renders atomic.Int32
+		cm := NewCacheMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if renders.Add(1) > 1 {
+				panic("render failed")
+			}
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("render 1"))
+		}), func(r *http.Request) string { return r.URL.Path }, time.Minute)
+		cm.StaleWhileRevalidate = time.Minute
+		logged := make(chan string, 1)
+		cm.ErrorLog = log.New(logWriter(logged), "", 0)
+		now := time.Time{}
+		cm.store.now = func() time.Time { return now }
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		now = now.Add(90 * time.Second)
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		select {
+		case msg := <-logged:
+			if !strings.HasPrefix(msg, `templ: panic revalidating cached response "/a": render failed`) {
+				t.Errorf("unexpected log message %q", msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the panic to be logged")
+		}
+		// Wait for the revalidation to finish.
+		for i := 0; i < 100; i++ {
+			cm.m.Lock()
+			e, _ := cm.store.get("/a")
+			revalidating := e.revalidating
+			cm.m.Unlock()
+			if !revalidating {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+	})
+	t.Run("responses that set cookies or are private aren't cached", func(t *testing.T) {
+		for _, header := range []http.Header{
+			{"Set-Cookie": {"session=abc"}},
+			{"Vary": {"HX-Request"}},
+			{"Cache-Control": {"private"}},
+			{"Cache-Control": {"max-age=60, No-Store"}},
+		} {
+			var renders int
+			cm := NewCacheMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				renders++
+				for k, v := range header {
+					w.Header()[k] = v
+				}
+				_, _ = w.Write([]byte("render " + strconv.Itoa(renders)))
+			}), func(r *http.Request) string { return r.URL.Path }, time.Minute)
+			for i := 1; i <= 2; i++ {
+				w := httptest.NewRecorder()
+				cm.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+				expect(t, "render "+strconv.Itoa(i), w.Body.String())
+			}
+		}
+	})
+	t.Run("the least recently used responses are removed when there are too many", func(t *testing.T) {
+		cm, _, _, _ := setup(http.StatusOK)
+		cm.MaxEntries = 2
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		expect(t, "render 2", get(t, cm, http.MethodGet, "/b"))
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		expect(t, "render 3", get(t, cm, http.MethodGet, "/c"))
+		expect(t, "render 1", get(t, cm, http.MethodGet, "/a"))
+		expect(t, "render 4", get(t, cm, http.MethodGet, "/b"))
+	})
+	t.Run("concurrent requests for a response that isn't cached render it once", func(t *testing.T) {
+		var renders atomic.Int32
+		release := make(chan struct{})
+		cm := NewCacheMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := renders.Add(1)
+			<-release
+			_, _ = w.Write([]byte("render " + strconv.Itoa(int(n))))
+		}), func(r *http.Request) string { return r.URL.Path }, time.Minute)
+		var wg sync.WaitGroup
+		bodies := make([]string, 10)
+		for i := range bodies {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w := httptest.NewRecorder()
+				cm.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+				bodies[i] = w.Body.String()
+			}()
+		}
+		// Wait for the requests to wait for the first render.
+		for renders.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		for _, body := range bodies {
+			expect(t, "render 1", body)
+		}
+		if n := renders.Load(); n != 1 {
+			t.Errorf("expected 1 render, got %d", n)
+		}
+	})
+	t.Run("panics are passed on", func(t *testing.T) {
+		cm := NewCacheMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("render failed")
+		}), func(r *http.Request) string { return r.URL.Path }, time.Minute)
+		for i := 0; i < 2; i++ {
+			func() {
+				defer func() {
+					if r := recover(); r != "render failed" {
+						t.Errorf("expected the panic to be passed on, got %v", r)
+					}
+				}()
+				cm.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a", nil))
+			}()
+		}
+	})
+}
+
+// logWriter sends each message written by a log.Logger to the channel.
+type logWriter chan string
+
+func (w logWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
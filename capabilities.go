@@ -0,0 +1,68 @@
+package templ
+
+import (
+	"context"
+	"io"
+)
+
+// CapabilityJavaScript is the capability of the browser to run JavaScript.
+const CapabilityJavaScript = "javascript"
+
+// WithCapability records whether the browser supports a capability, e.g. based on a
+// request header, cookie or user agent, so that Degrade can choose what to render.
+func WithCapability(ctx context.Context, name string, supported bool) context.Context {
+	ctx, v := getContext(ctx)
+	if v.capabilities == nil {
+		v.capabilities = map[string]bool{}
+	}
+	v.capabilities[name] = supported
+	return ctx
+}
+
+// GetCapability returns whether the browser supports a capability, and whether
+// support is known.
+func GetCapability(ctx context.Context, name string) (supported, known bool) {
+	_, v := getContext(ctx)
+	supported, known = v.capabilities[name]
+	return supported, known
+}
+
+// Degrade renders enhanced if the browser supports the capability, and fallback if it
+// doesn't.
+//
+// If support is unknown, enhanced is rendered. For CapabilityJavaScript, the fallback is
+// also rendered inside a <noscript> element, so that browsers without JavaScript
+// display it.
+//
+// Both components are rendered with the same context, so children passed to Degrade
+// are available to both, allowing the enhanced and fallback variants to share the same
+// body.
+//
+//	@templ.Degrade(templ.CapabilityJavaScript, searchIsland(), searchForm()) {
+//		<label for="q">Search</label>
+//	}
+func Degrade(name string, enhanced, fallback Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		supported, known := GetCapability(ctx, name)
+		if known && !supported {
+			return fallback.Render(ctx, w)
+		}
+		// Rendering a component clears its children from the context, so keep them for the fallback.
+		children, slots := GetChildren(ctx), GetSlots(ctx)
+		if err = enhanced.Render(ctx, w); err != nil {
+			return err
+		}
+		if known || name != CapabilityJavaScript {
+			return nil
+		}
+		if _, err = io.WriteString(w, "<noscript>"); err != nil {
+			return err
+		}
+		ctx = WithSlots(WithChildren(ctx, children), slots)
+		if err = fallback.Render(ctx, w); err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, "</noscript>")
+		return err
+	})
+}
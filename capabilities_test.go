@@ -0,0 +1,86 @@
+package templ_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestDegrade(t *testing.T) {
+	variant := func(name string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			children := templ.GetChildren(ctx)
+			ctx = templ.ClearChildren(ctx)
+			if _, err := io.WriteString(w, "<"+name+">"); err != nil {
+				return err
+			}
+			if err := children.Render(ctx, w); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, "</"+name+">")
+			return err
+		})
+	}
+	body := templ.Raw("body")
+	tests := []struct {
+		name       string
+		capability string
+		ctx        func(ctx context.Context) context.Context
+		expected   string
+	}{
+		{
+			name:       "supported capabilities render the enhanced variant",
+			capability: templ.CapabilityJavaScript,
+			ctx: func(ctx context.Context) context.Context {
+				return templ.WithCapability(ctx, templ.CapabilityJavaScript, true)
+			},
+			expected: "<island>body</island>",
+		},
+		{
+			name:       "unsupported capabilities render the fallback variant",
+			capability: templ.CapabilityJavaScript,
+			ctx: func(ctx context.Context) context.Context {
+				return templ.WithCapability(ctx, templ.CapabilityJavaScript, false)
+			},
+			expected: "<form>body</form>",
+		},
+		{
+			name:       "unknown JavaScript support renders both variants, with the fallback in a noscript element",
+			capability: templ.CapabilityJavaScript,
+			ctx:        func(ctx context.Context) context.Context { return ctx },
+			expected:   "<island>body</island><noscript><form>body</form></noscript>",
+		},
+		{
+			name:       "unknown support for other capabilities renders the enhanced variant",
+			capability: "webgl",
+			ctx:        func(ctx context.Context) context.Context { return ctx },
+			expected:   "<island>body</island>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := templ.WithChildren(tt.ctx(context.Background()), body)
+			var sb strings.Builder
+			if err := templ.Degrade(tt.capability, variant("island"), variant("form")).Render(ctx, &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, sb.String())
+			}
+		})
+	}
+}
+
+func TestGetCapability(t *testing.T) {
+	ctx := context.Background()
+	if _, known := templ.GetCapability(ctx, templ.CapabilityJavaScript); known {
+		t.Error("expected capability to be unknown")
+	}
+	ctx = templ.WithCapability(ctx, templ.CapabilityJavaScript, true)
+	if supported, known := templ.GetCapability(ctx, templ.CapabilityJavaScript); !supported || !known {
+		t.Errorf("expected capability to be supported and known, got supported=%v, known=%v", supported, known)
+	}
+}
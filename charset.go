@@ -0,0 +1,156 @@
+package templ
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Charset is a character encoding that rendered output can be transcoded to, for clients
+// that can't consume UTF-8. Characters that can't be encoded are written as numeric
+// character references, e.g. &#8364; for €.
+//
+// Other encodings can be used by implementing AppendRune, e.g. with the encoders in
+// golang.org/x/text/encoding.
+type Charset struct {
+	// Name of the encoding, used in the Content-Type header and <meta charset> element.
+	Name string
+	// AppendRune appends the encoded form of r to b, and returns false if r can't be encoded.
+	AppendRune func(b []byte, r rune) ([]byte, bool)
+}
+
+var (
+	// ASCII is the US-ASCII character encoding.
+	ASCII = Charset{Name: "US-ASCII", AppendRune: appendRuneBelow(0x80)}
+	// Latin1 is the ISO-8859-1 character encoding.
+	Latin1 = Charset{Name: "ISO-8859-1", AppendRune: appendRuneBelow(0x100)}
+)
+
+// appendRuneBelow returns a function that encodes the code points below max as a single byte.
+func appendRuneBelow(max rune) func(b []byte, r rune) ([]byte, bool) {
+	return func(b []byte, r rune) ([]byte, bool) {
+		if r < 0 || r >= max {
+			return b, false
+		}
+		return append(b, byte(r)), true
+	}
+}
+
+// NewCharsetWriter returns a writer that transcodes the UTF-8 written to it to cs, and
+// writes the result to w. Flush must be called after the last write.
+//
+// The output of a component should only be transcoded if the whole document is written
+// with the writer, since the numeric character references used for characters that can't
+// be encoded aren't interpreted inside <script> and <style> elements.
+func NewCharsetWriter(w io.Writer, cs Charset) *CharsetWriter {
+	return &CharsetWriter{w: w, cs: cs}
+}
+
+// CharsetWriter transcodes UTF-8 to another character encoding, see NewCharsetWriter.
+type CharsetWriter struct {
+	w  io.Writer
+	cs Charset
+	// pending contains the start of a UTF-8 sequence that was split across writes.
+	pending []byte
+	buf     []byte
+}
+
+func (cw *CharsetWriter) Write(p []byte) (n int, err error) {
+	n = len(p)
+	if len(cw.pending) > 0 {
+		p = append(cw.pending, p...)
+		cw.pending = nil
+	}
+	cw.buf = cw.buf[:0]
+	for len(p) > 0 {
+		if !utf8.FullRune(p) {
+			cw.pending = append(cw.pending, p...)
+			break
+		}
+		r, size := utf8.DecodeRune(p)
+		p = p[size:]
+		cw.appendRune(r)
+	}
+	if _, err = cw.w.Write(cw.buf); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Flush writes the start of a UTF-8 sequence that wasn't completed by the last write as the
+// replacement character, U+FFFD.
+func (cw *CharsetWriter) Flush() error {
+	if len(cw.pending) == 0 {
+		return nil
+	}
+	cw.pending = nil
+	cw.buf = cw.buf[:0]
+	cw.appendRune(utf8.RuneError)
+	_, err := cw.w.Write(cw.buf)
+	return err
+}
+
+// appendRune appends r to the buffer, or a numeric character reference if it can't be encoded.
+func (cw *CharsetWriter) appendRune(r rune) {
+	var ok bool
+	if cw.buf, ok = cw.cs.AppendRune(cw.buf, r); !ok {
+		cw.buf = append(cw.buf, "&#"...)
+		cw.buf = strconv.AppendInt(cw.buf, int64(r), 10)
+		cw.buf = append(cw.buf, ';')
+	}
+}
+
+// WithRenderCharset sets the character encoding that the output is transcoded to, so that
+// MetaCharset renders its name. It doesn't transcode the output, see NewCharsetWriter.
+func WithRenderCharset(ctx context.Context, cs Charset) context.Context {
+	ctx, v := getContext(ctx)
+	v.charset = cs
+	return ctx
+}
+
+// GetCharset returns the character encoding set with WithRenderCharset. The Name of the
+// Charset is empty if the output is UTF-8.
+func GetCharset(ctx context.Context) Charset {
+	if ctx == nil {
+		return Charset{}
+	}
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	if !ok {
+		return Charset{}
+	}
+	return v.charset
+}
+
+// MetaCharset renders a <meta charset> element containing the name of the character
+// encoding set with WithRenderCharset, or utf-8 if none has been set.
+func MetaCharset() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		name := GetCharset(ctx).Name
+		if name == "" {
+			name = "utf-8"
+		}
+		return writeStrings(w, `<meta charset="`, EscapeString(name), `">`)
+	})
+}
+
+// charsetResponseWriter transcodes the response written by a ComponentHandler.
+type charsetResponseWriter struct {
+	http.ResponseWriter
+	cw *CharsetWriter
+}
+
+func (w *charsetResponseWriter) Write(p []byte) (n int, err error) {
+	return w.cw.Write(p)
+}
+
+func (w *charsetResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *charsetResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,166 @@
+package templ_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCharsetWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		cs       templ.Charset
+		writes   []string
+		expected string
+	}{
+		{
+			name:     "characters in the charset are encoded",
+			cs:       templ.Latin1,
+			writes:   []string{"café"},
+			expected: "caf\xe9",
+		},
+		{
+			name:     "characters outside the charset are written as character references",
+			cs:       templ.Latin1,
+			writes:   []string{"5 € 🙂"},
+			expected: "5 &#8364; &#128578;",
+		},
+		{
+			name:     "ASCII only encodes the first 128 code points",
+			cs:       templ.ASCII,
+			writes:   []string{"café"},
+			expected: "caf&#233;",
+		},
+		{
+			name:     "characters split across writes are encoded",
+			cs:       templ.Latin1,
+			writes:   []string{"caf\xc3", "\xa9 \xe2\x82", "\xac"},
+			expected: "caf\xe9 &#8364;",
+		},
+		{
+			name:     "invalid UTF-8 is replaced",
+			cs:       templ.Latin1,
+			writes:   []string{"a\xffb"},
+			expected: "a&#65533;b",
+		},
+		{
+			name:     "incomplete UTF-8 at the end is replaced when flushed",
+			cs:       templ.Latin1,
+			writes:   []string{"a\xe2\x82"},
+			expected: "a&#65533;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := templ.NewCharsetWriter(&buf, tt.cs)
+			for _, s := range tt.writes {
+				n, err := io.WriteString(w, s)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if n != len(s) {
+					t.Errorf("expected %d bytes to be written, got %d", len(s), n)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, buf.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestMetaCharset(t *testing.T) {
+	t.Run("utf-8 is the default", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := templ.MetaCharset().Render(context.Background(), &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(`<meta charset="utf-8">`, buf.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the charset in the context is used", func(t *testing.T) {
+		var buf bytes.Buffer
+		ctx := templ.WithRenderCharset(context.Background(), templ.Latin1)
+		if err := templ.MetaCharset().Render(ctx, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(`<meta charset="ISO-8859-1">`, buf.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestHandlerCharset(t *testing.T) {
+	page := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if err := templ.MetaCharset().Render(ctx, w); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "<p>Café 5€</p>")
+		return err
+	})
+	for _, streaming := range []bool{false, true} {
+		opts := []func(*templ.ComponentHandler){templ.WithCharset(templ.Latin1)}
+		if streaming {
+			opts = append(opts, templ.WithStreaming())
+		}
+		w := httptest.NewRecorder()
+		templ.Handler(page, opts...).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		if diff := cmp.Diff("text/html; charset=ISO-8859-1", w.Header().Get("Content-Type")); diff != "" {
+			t.Error(diff)
+		}
+		expected := "<meta charset=\"ISO-8859-1\"><p>Caf\xe9 5&#8364;</p>"
+		if diff := cmp.Diff(expected, w.Body.String()); diff != "" {
+			t.Errorf("streaming=%v: %s", streaming, diff)
+		}
+	}
+}
+
+func TestHandlerCharsetField(t *testing.T) {
+	page := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<p>Café</p>\xe2\x82")
+		return err
+	})
+	tests := []struct {
+		name        string
+		handler     *templ.ComponentHandler
+		contentType string
+	}{
+		{
+			name:        "the charset of the default content type is replaced",
+			handler:     &templ.ComponentHandler{Component: page, ContentType: "text/html; charset=utf-8", Charset: templ.Latin1},
+			contentType: "text/html; charset=ISO-8859-1",
+		},
+		{
+			name:        "other content types are kept",
+			handler:     &templ.ComponentHandler{Component: page, ContentType: "application/xhtml+xml", Charset: templ.Latin1},
+			contentType: "application/xhtml+xml; charset=ISO-8859-1",
+		},
+		{
+			name:        "the content type defaults to HTML",
+			handler:     &templ.ComponentHandler{Component: page, Charset: templ.Latin1},
+			contentType: "text/html; charset=ISO-8859-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if diff := cmp.Diff(tt.contentType, w.Header().Get("Content-Type")); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff("<p>Caf\xe9</p>&#65533;", w.Body.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
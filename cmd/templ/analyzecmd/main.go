@@ -0,0 +1,35 @@
+package analyzecmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+type Arguments struct {
+	// Path is the directory of the Go module, or packages, to analyze.
+	Path string
+	// Unused reports the components that aren't referenced, see FindUnused.
+	Unused bool
+}
+
+// Run analyzes the packages in the path, and writes a line to stdout for each issue found.
+// An error is returned if any issues are found, so that the command can be used in CI.
+func Run(stdout io.Writer, args Arguments) (err error) {
+	if !args.Unused {
+		return errors.New("no analysis selected, use -unused")
+	}
+	unused, err := FindUnused(args.Path)
+	if err != nil {
+		return err
+	}
+	for _, u := range unused {
+		if _, err = fmt.Fprintln(stdout, u.String()); err != nil {
+			return err
+		}
+	}
+	if len(unused) > 0 {
+		return fmt.Errorf("found %d unused components", len(unused))
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package analyzecmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+	"golang.org/x/tools/go/packages"
+)
+
+// UnusedComponent is a component that isn't referenced by any code in the analyzed packages.
+type UnusedComponent struct {
+	// Name of the component, including the receiver type of methods, e.g. "Card" or
+	// "Page.Header".
+	Name string
+	// Package is the import path of the package that contains the component.
+	Package string
+	// FileName is the templ file that declares the component, or the Go file if the templ
+	// file can't be found.
+	FileName string
+	// Line and Col of the declaration, starting at 1.
+	Line int
+	Col  int
+}
+
+func (u UnusedComponent) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s is unused", u.FileName, u.Line, u.Col, u.Name)
+}
+
+// component is a function or method, declared in a generated file, that returns a
+// templ.Component.
+type component struct {
+	UnusedComponent
+	decl *ast.FuncDecl
+	// pos identifies the declaration across the package and its test variant, which are
+	// type checked separately.
+	pos string
+}
+
+// FindUnused returns the components declared in the packages within dir that aren't
+// referenced. References are found in the type checked Go code of the packages and their
+// tests, which includes the code generated from templ files, so calls in templ files, and
+// Go code such as templ.Handler(Page()) or Page().Render(ctx, w), are both references.
+//
+// The generated code must be up to date, so run templ generate first. Components that are
+// only used by other modules, or by reflection, are reported as unused.
+func FindUnused(dir string) (unused []UnusedComponent, err error) {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:   dir,
+		Tests: true,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	var errs []string
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e.Error())
+		}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to load packages, run templ generate first:\n%s", strings.Join(errs, "\n"))
+	}
+
+	components := map[string]*component{}
+	used := map[string]bool{}
+	for _, pkg := range pkgs {
+		byObject := map[types.Object]*component{}
+		for _, f := range pkg.Syntax {
+			fileName := pkg.Fset.Position(f.Pos()).Filename
+			if !isGeneratedByTempl(f) {
+				continue
+			}
+			for _, decl := range f.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || !returnsComponent(pkg.TypesInfo, fn) {
+					continue
+				}
+				obj := pkg.TypesInfo.Defs[fn.Name]
+				if obj == nil {
+					continue
+				}
+				pos := pkg.Fset.Position(fn.Name.Pos())
+				c := &component{
+					UnusedComponent: UnusedComponent{
+						Name:     componentName(fn),
+						Package:  strings.TrimSuffix(pkg.PkgPath, "_test"),
+						FileName: fileName,
+						Line:     pos.Line,
+						Col:      pos.Column,
+					},
+					decl: fn,
+					pos:  pos.String(),
+				}
+				byObject[obj] = c
+				if _, ok := components[c.pos]; !ok {
+					components[c.pos] = c
+				}
+			}
+		}
+		// Find the references to the components of the package, and of the packages it
+		// imports, which are identified by their position.
+		for ident, obj := range pkg.TypesInfo.Uses {
+			if obj == nil || obj.Pkg() == nil {
+				continue
+			}
+			if c, ok := byObject[obj]; ok && c.decl.Pos() <= ident.Pos() && ident.Pos() < c.decl.End() {
+				// Recursive calls aren't references.
+				continue
+			}
+			if _, isFunc := obj.(*types.Func); !isFunc {
+				continue
+			}
+			used[pkg.Fset.Position(obj.Pos()).String()] = true
+		}
+	}
+
+	for pos, c := range components {
+		if used[pos] {
+			continue
+		}
+		c.UnusedComponent = templPosition(c.UnusedComponent)
+		unused = append(unused, c.UnusedComponent)
+	}
+	slices.SortFunc(unused, func(a, b UnusedComponent) int {
+		if a.FileName != b.FileName {
+			return strings.Compare(a.FileName, b.FileName)
+		}
+		return a.Line - b.Line
+	})
+	return unused, nil
+}
+
+// isGeneratedByTempl returns true if the file has the comment written at the start of
+// generated files.
+func isGeneratedByTempl(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Package {
+			return false
+		}
+		for _, c := range cg.List {
+			if strings.HasPrefix(c.Text, "// Code generated by templ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// returnsComponent returns true if the function returns a single templ.Component.
+func returnsComponent(info *types.Info, fn *ast.FuncDecl) bool {
+	results := fn.Type.Results
+	if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+		return false
+	}
+	named, ok := info.TypeOf(results.List[0].Type).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == "Component" && obj.Pkg() != nil && strings.HasSuffix(obj.Pkg().Path(), "/templ")
+}
+
+// componentName returns the name of the function, prefixed by the receiver type of methods.
+func componentName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch r := t.(type) {
+	case *ast.IndexExpr:
+		t = r.X
+	case *ast.IndexListExpr:
+		t = r.X
+	}
+	if ident, ok := t.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// templPosition returns the component with the position of the template in the templ file
+// that it was generated from, if it can be found.
+func templPosition(c UnusedComponent) UnusedComponent {
+	dir, base := filepath.Split(c.FileName)
+	i := strings.Index(base, "_templ")
+	if i < 0 {
+		return c
+	}
+	templFile := filepath.Join(dir, base[:i]+".templ")
+	if _, err := os.Stat(templFile); err != nil {
+		return c
+	}
+	tf, err := parser.Parse(templFile)
+	if err != nil {
+		return c
+	}
+	name := c.Name
+	if _, method, ok := strings.Cut(name, "."); ok {
+		name = method
+	}
+	signature := regexp.MustCompile(`^(\([^)]*\)\s*)?` + regexp.QuoteMeta(name) + `\s*[\[(]`)
+	for _, n := range tf.Nodes {
+		t, ok := n.(*parser.HTMLTemplate)
+		if !ok || !signature.MatchString(t.Expression.Value) {
+			continue
+		}
+		c.FileName = templFile
+		c.Line = int(t.Range.From.Line) + 1
+		c.Col = int(t.Range.From.Col) + 1
+		return c
+	}
+	return c
+}
@@ -0,0 +1,94 @@
+package analyzecmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/generatecmd"
+	"github.com/a-h/templ/cmd/templ/testproject"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindUnused(t *testing.T) {
+	moduleRoot, err := filepath.Abs("../../..")
+	if err != nil {
+		t.Fatalf("failed to get module root: %v", err)
+	}
+	dir, err := testproject.Create(moduleRoot)
+	if err != nil {
+		t.Fatalf("failed to create test project: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	// The remote templates don't compile, because they're used to test generation errors.
+	for _, name := range []string{"remoteparent.templ", "remoteparent_templ.go", "remotechild.templ", "remotechild_templ.go"} {
+		if err = os.Remove(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("failed to remove %s: %v", name, err)
+		}
+	}
+	files := map[string]string{
+		"cards.templ": `package main
+
+templ Card() {
+	<div>
+		@Title()
+	</div>
+}
+
+templ Title() {
+	<h1>Title</h1>
+}
+
+templ Stale() {
+	<p>Stale</p>
+}
+
+templ Rendered() {
+	<p>Rendered</p>
+}
+
+templ Recursive(n int) {
+	if n > 0 {
+		@Recursive(n - 1)
+	}
+}
+`,
+		// Go code that renders a component is a reference.
+		"handler.go": `package main
+
+import (
+	"context"
+	"io"
+)
+
+func render(w io.Writer) error {
+	return Rendered().Render(context.Background(), w)
+}
+`,
+	}
+	for name, contents := range files {
+		if err = os.WriteFile(filepath.Join(dir, name), []byte(contents), 0660); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err = generatecmd.Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir}); err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+
+	unused, err := FindUnused(dir)
+	if err != nil {
+		t.Fatalf("failed to find unused components: %v", err)
+	}
+	cards := filepath.Join(dir, "cards.templ")
+	expected := []UnusedComponent{
+		{Name: "Card", Package: "templ/testproject", FileName: cards, Line: 3, Col: 1},
+		{Name: "Stale", Package: "templ/testproject", FileName: cards, Line: 13, Col: 1},
+		{Name: "Recursive", Package: "templ/testproject", FileName: cards, Line: 21, Col: 1},
+	}
+	if diff := cmp.Diff(expected, unused); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,137 @@
+package fmtcmd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	parser "github.com/a-h/templ/parser/v2"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// LineRange is a range of lines to format, see Arguments.Range. Lines start at 1, and the end
+// line is included. The zero value is the whole file.
+type LineRange struct {
+	Start, End int
+}
+
+// IsZero returns true if the range is the whole file.
+func (r LineRange) IsZero() bool {
+	return r == LineRange{}
+}
+
+// ParseLineRange parses a range in the startLine:endLine format, e.g. 10:25.
+func ParseLineRange(s string) (r LineRange, err error) {
+	start, end, ok := strings.Cut(s, ":")
+	if !ok {
+		return r, fmt.Errorf("invalid range %q, expected startLine:endLine, e.g. 10:25", s)
+	}
+	if r.Start, err = strconv.Atoi(start); err != nil || r.Start < 1 {
+		return r, fmt.Errorf("invalid start line in range %q, expected a line number of 1 or more", s)
+	}
+	if r.End, err = strconv.Atoi(end); err != nil || r.End < r.Start {
+		return r, fmt.Errorf("invalid end line in range %q, expected a line number of %d or more", s, r.Start)
+	}
+	return r, nil
+}
+
+// FormatRange formats the lines of the templ file in the range, and leaves the rest of the file
+// as it is. Imports aren't organised.
+//
+// The declarations that have lines in the range, e.g. templates and Go code, are formatted, and
+// only the changes to lines in the range are kept. A change that spans lines in and out of the
+// range, e.g. joining the attributes of an element that starts in the range, is kept whole.
+func FormatRange(src string, t *parser.TemplateFile, r LineRange, style parser.FormatStyle) (string, error) {
+	// Positions are those of the source without the byte order mark and carriage returns.
+	out := strings.TrimPrefix(src, "\ufeff")
+	if t.CRLF {
+		out = strings.ReplaceAll(out, "\r\n", "\n")
+	}
+	// Replace from the end of the file, so that the positions of earlier nodes are unchanged.
+	for i := len(t.Nodes) - 1; i >= 0; i-- {
+		n := t.Nodes[i]
+		nr, ok := templateFileNodeRange(n)
+		if !ok {
+			continue
+		}
+		// Whitespace after a node is formatted as the space between nodes, so it's kept.
+		from := int(nr.From.Index)
+		to := from + len(strings.TrimRightFunc(out[from:nr.To.Index], unicode.IsSpace))
+		fromLine := int(nr.From.Line) + 1
+		toLine := fromLine + strings.Count(out[from:to], "\n")
+		if toLine < r.Start || fromLine > r.End {
+			continue
+		}
+		w := new(bytes.Buffer)
+		if err := n.Write(parser.NewStyleWriter(w, style), 0); err != nil {
+			return "", fmt.Errorf("formatting error: %w", err)
+		}
+		formatted := strings.TrimRightFunc(w.String(), unicode.IsSpace)
+		out = out[:from] + changesInRange(out[from:to], formatted, fromLine, r) + out[to:]
+	}
+	if t.CRLF {
+		out = strings.ReplaceAll(out, "\n", "\r\n")
+	}
+	if t.ByteOrderMark {
+		out = "\ufeff" + out
+	}
+	return out, nil
+}
+
+// changesInRange returns before, with the changes made by formatting it to after that are in
+// the range. firstLine is the line of the file that before starts on.
+func changesInRange(before, after string, firstLine int, r LineRange) string {
+	b, a := strings.SplitAfter(before, "\n"), strings.SplitAfter(after, "\n")
+	var sb strings.Builder
+	for _, op := range difflib.NewMatcherWithJunk(b, a, false, nil).GetOpCodes() {
+		if op.Tag == 'r' && op.I2-op.I1 == op.J2-op.J1 {
+			// Lines that are replaced line by line, e.g. indented, are changed separately.
+			for i := range op.I2 - op.I1 {
+				line := b[op.I1+i]
+				if inRange(firstLine+op.I1+i, firstLine+op.I1+i, r) {
+					line = a[op.J1+i]
+				}
+				sb.WriteString(line)
+			}
+			continue
+		}
+		lines := b[op.I1:op.I2]
+		// Lines are inserted after the line before them, so insertions are in the range if
+		// that line is.
+		fromLine, toLine := firstLine+op.I1, firstLine+op.I2-1
+		if op.I1 == op.I2 {
+			fromLine--
+		}
+		if op.Tag != 'e' && inRange(fromLine, toLine, r) {
+			lines = a[op.J1:op.J2]
+		}
+		for _, line := range lines {
+			sb.WriteString(line)
+		}
+	}
+	return sb.String()
+}
+
+// inRange returns true if any of the lines from fromLine to toLine are in the range.
+func inRange(fromLine, toLine int, r LineRange) bool {
+	return toLine >= r.Start && fromLine <= r.End
+}
+
+// templateFileNodeRange returns the source range of a template file node.
+func templateFileNodeRange(n parser.TemplateFileNode) (r parser.Range, ok bool) {
+	switch n := n.(type) {
+	case *parser.HTMLTemplate:
+		return n.Range, true
+	case *parser.CSSTemplate:
+		return n.Range, true
+	case *parser.ScriptTemplate:
+		return n.Range, true
+	case *parser.ContextDeclaration:
+		return n.Range, true
+	case *parser.TemplateFileGoExpression:
+		return n.Expression.Range, true
+	}
+	return r, false
+}
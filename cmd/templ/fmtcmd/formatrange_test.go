@@ -0,0 +1,125 @@
+package fmtcmd
+
+import (
+	"strings"
+	"testing"
+
+	parser "github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormatRange(t *testing.T) {
+	input := `package main
+
+templ A() {
+<div>é</div>
+}
+
+var   x = 1
+
+templ B() {
+<p>b</p>
+}
+`
+	tests := []struct {
+		name     string
+		input    string
+		r        LineRange
+		expected string
+	}{
+		{
+			name:  "only lines in the range are formatted",
+			input: input,
+			r:     LineRange{Start: 10, End: 10},
+			expected: `package main
+
+templ A() {
+<div>é</div>
+}
+
+var   x = 1
+
+templ B() {
+	<p>b</p>
+}
+`,
+		},
+		{
+			name:  "lines of declarations outside the range are unchanged",
+			input: input,
+			r:     LineRange{Start: 5, End: 7},
+			expected: `package main
+
+templ A() {
+<div>é</div>
+}
+
+var x = 1
+
+templ B() {
+<p>b</p>
+}
+`,
+		},
+		{
+			name: "lines of a declaration outside the range are unchanged",
+			input: `package main
+
+templ A() {
+<ul>
+<li>a</li>
+<li>b</li>
+</ul>
+}
+`,
+			r: LineRange{Start: 5, End: 5},
+			expected: `package main
+
+templ A() {
+<ul>
+		<li>a</li>
+<li>b</li>
+</ul>
+}
+`,
+		},
+		{
+			name:     "ranges without declarations are unchanged",
+			input:    input,
+			r:        LineRange{Start: 1, End: 2},
+			expected: input,
+		},
+		{
+			name:     "line endings are kept",
+			input:    strings.ReplaceAll(input, "\n", "\r\n"),
+			r:        LineRange{Start: 4, End: 4},
+			expected: strings.ReplaceAll("package main\n\ntempl A() {\n\t<div>é</div>\n}\n\nvar   x = 1\n\ntempl B() {\n<p>b</p>\n}\n", "\n", "\r\n"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			actual, err := FormatRange(tt.input, tf, tt.r, parser.FormatStyle{})
+			if err != nil {
+				t.Fatalf("failed to format: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseLineRange(t *testing.T) {
+	if r, err := ParseLineRange("10:25"); err != nil || r != (LineRange{Start: 10, End: 25}) {
+		t.Errorf("expected 10:25, got %v, %v", r, err)
+	}
+	for _, s := range []string{"10", "0:5", "10:5", "a:b"} {
+		if _, err := ParseLineRange(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"time"
 
 	"github.com/a-h/templ/cmd/templ/imports"
 	"github.com/a-h/templ/cmd/templ/processor"
+	"github.com/a-h/templ/config"
 	parser "github.com/a-h/templ/parser/v2"
 	"github.com/natefinch/atomic"
 )
@@ -23,12 +25,22 @@ type Arguments struct {
 	StdinFilepath string
 	Files         []string
 	WorkerCount   int
+	// Stdin formats stdin to stdout. It's the default if no files are given.
+	Stdin bool
+	// Range formats only the lines in the range, when formatting stdin.
+	Range LineRange
 }
 
 func Run(log *slog.Logger, stdin io.Reader, stdout io.Writer, args Arguments) (err error) {
+	if args.Stdin && len(args.Files) > 0 {
+		return errors.New("files can't be given when formatting stdin")
+	}
+	if !args.Range.IsZero() && len(args.Files) > 0 {
+		return errors.New("a range can only be formatted from stdin")
+	}
 	// If no files are provided, read from stdin and write to stdout.
 	if len(args.Files) == 0 {
-		out, _ := format(writeToWriter(stdout), readFromReader(stdin, args.StdinFilepath), true)
+		out, _ := format(writeToWriter(stdout), readFromReader(stdin, args.StdinFilepath), true, args.Range)
 		return out
 	}
 	process := func(fileName string) (error, bool) {
@@ -38,7 +50,7 @@ func Run(log *slog.Logger, stdin io.Reader, stdout io.Writer, args Arguments) (e
 			write = writeToWriter(stdout)
 		}
 		writeIfUnchanged := args.ToStdout
-		return format(write, read, writeIfUnchanged)
+		return format(write, read, writeIfUnchanged, LineRange{})
 	}
 	dir := args.Files[0]
 	return NewFormatter(log, dir, process, args.WorkerCount, args.FailIfChanged).Run()
@@ -141,7 +153,7 @@ func writeToFile(fileName, tgt string) error {
 	return atomic.WriteFile(fileName, bytes.NewBufferString(tgt))
 }
 
-func format(write writer, read reader, writeIfUnchanged bool) (err error, fileChanged bool) {
+func format(write writer, read reader, writeIfUnchanged bool, r LineRange) (err error, fileChanged bool) {
 	fileName, src, err := read()
 	if err != nil {
 		return err, false
@@ -151,19 +163,33 @@ func format(write writer, read reader, writeIfUnchanged bool) (err error, fileCh
 		return err, false
 	}
 	t.Filepath = fileName
-	t, err = imports.Process(t)
+	// Files without a name, e.g. stdin, use the configuration of the working directory.
+	cfg, err := config.Find(filepath.Dir(fileName))
 	if err != nil {
-		return err, false
+		return fmt.Errorf("failed to read %s: %w", config.FileName, err), false
 	}
-	w := new(bytes.Buffer)
-	if err = t.Write(w); err != nil {
-		return fmt.Errorf("formatting error: %w", err), false
+	style := cfg.Format.Style()
+	var tgt string
+	if !r.IsZero() {
+		if tgt, err = FormatRange(src, t, r, style); err != nil {
+			return err, false
+		}
+	} else {
+		t, err = imports.Process(t)
+		if err != nil {
+			return err, false
+		}
+		w := new(bytes.Buffer)
+		if err = t.WriteStyle(w, style); err != nil {
+			return fmt.Errorf("formatting error: %w", err), false
+		}
+		tgt = w.String()
 	}
 
-	fileChanged = (src != w.String())
+	fileChanged = (src != tgt)
 
 	if !writeIfUnchanged && !fileChanged {
 		return nil, fileChanged
 	}
-	return write(fileName, w.String()), fileChanged
+	return write(fileName, tgt), fileChanged
 }
@@ -79,6 +79,28 @@ func TestFormat(t *testing.T) {
 			t.Error(diff)
 		}
 	})
+	t.Run("can format a range of stdin to stdout", func(t *testing.T) {
+		stdin := strings.NewReader("package main\n\ntempl A() {\n<a></a>\n}\n\ntempl B() {\n<b></b>\n}\n")
+		stdout := new(strings.Builder)
+		if err := Run(log, stdin, stdout, Arguments{
+			Stdin: true,
+			Range: LineRange{Start: 7, End: 9},
+		}); err != nil {
+			t.Fatalf("failed to run format command: %v", err)
+		}
+		expected := "package main\n\ntempl A() {\n<a></a>\n}\n\ntempl B() {\n\t<b></b>\n}\n"
+		if diff := cmp.Diff(expected, stdout.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("ranges can't be formatted in files", func(t *testing.T) {
+		if err := Run(log, nil, nil, Arguments{
+			Files: []string{"a.templ"},
+			Range: LineRange{Start: 1, End: 2},
+		}); err == nil {
+			t.Error("expected an error")
+		}
+	})
 	t.Run("can process a single file to stdout", func(t *testing.T) {
 		tp, err := setupProjectDir()
 		if err != nil {
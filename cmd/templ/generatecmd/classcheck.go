@@ -0,0 +1,225 @@
+package generatecmd
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/internal/skipdir"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/a-h/templ/parser/v2/visitor"
+)
+
+// fileClasses are the classes defined and used by a templ file, see -check-classes.
+type fileClasses struct {
+	// cssTemplates defined in the file.
+	cssTemplates []*parser.CSSTemplate
+	// styleClasses are the class selectors of <style> elements.
+	styleClasses []string
+	// references are the constant class names of class attributes.
+	references []classReference
+	// calls are the names of the functions called in class attribute expressions, which
+	// includes the css templates that are used.
+	calls []string
+}
+
+type classReference struct {
+	name string
+	r    parser.Range
+}
+
+// findClasses returns the classes defined and used by the templ file.
+func findClasses(tf *parser.TemplateFile) (fc fileClasses) {
+	v := visitor.New()
+	v.CSSTemplate = func(n *parser.CSSTemplate) error {
+		fc.cssTemplates = append(fc.cssTemplates, n)
+		return nil
+	}
+	visitRawElement := v.RawElement
+	v.RawElement = func(n *parser.RawElement) error {
+		if strings.EqualFold(n.Name, "style") {
+			fc.styleClasses = append(fc.styleClasses, classSelectors(n.Contents)...)
+		}
+		return visitRawElement(n)
+	}
+	v.ConstantAttribute = func(n *parser.ConstantAttribute) error {
+		if k, ok := n.Key.(parser.ConstantAttributeKey); ok && strings.EqualFold(k.Name, "class") {
+			for _, name := range classNames(n.Value) {
+				fc.references = append(fc.references, classReference{name: name, r: k.NameRange})
+			}
+		}
+		return nil
+	}
+	v.ExpressionAttribute = func(n *parser.ExpressionAttribute) error {
+		if k, ok := n.Key.(parser.ConstantAttributeKey); ok && strings.EqualFold(k.Name, "class") {
+			names, calls := classExpressionNames(n.Expression.Value)
+			for _, name := range names {
+				fc.references = append(fc.references, classReference{name: name, r: n.Expression.Range})
+			}
+			fc.calls = append(fc.calls, calls...)
+		}
+		return nil
+	}
+	_ = tf.Visit(v)
+	return fc
+}
+
+// classExpressionNames returns the class names of the string literals in a class attribute
+// expression, e.g. { "btn", templ.KV("active", isActive) }, and the names of the functions
+// that are called.
+func classExpressionNames(expr string) (names, calls []string) {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	src := []byte(expr)
+	s.Init(fset.AddFile("", fset.Base(), len(src)), src, nil, 0)
+	var prev string
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		switch tok {
+		case token.STRING:
+			if value, err := strconv.Unquote(lit); err == nil {
+				names = append(names, classNames(value)...)
+			}
+		case token.LPAREN:
+			if prev != "" {
+				calls = append(calls, prev)
+			}
+		}
+		prev = ""
+		if tok == token.IDENT {
+			prev = lit
+		}
+	}
+	return names, calls
+}
+
+var (
+	cssCommentRegexp = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssPreludeRegexp = regexp.MustCompile(`[^{};]*\{`)
+	cssClassRegexp   = regexp.MustCompile(`\.(-?[_a-zA-Z][_a-zA-Z0-9-]*)`)
+	classNameRegexp  = regexp.MustCompile(`^-?[_a-zA-Z][_a-zA-Z0-9-]*$`)
+)
+
+// classSelectors returns the class names of the selectors in the CSS. Only the text before
+// each {, other than at-rules, is searched, so that values such as 1.5em aren't mistaken
+// for selectors.
+func classSelectors(css string) (names []string) {
+	css = cssCommentRegexp.ReplaceAllString(css, "")
+	for _, prelude := range cssPreludeRegexp.FindAllString(css, -1) {
+		if strings.HasPrefix(strings.TrimSpace(prelude), "@") {
+			continue
+		}
+		for _, m := range cssClassRegexp.FindAllStringSubmatch(prelude, -1) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// classNames returns the names in a class attribute value. Names that can't be written as
+// a CSS class selector without escaping, such as format strings, are skipped.
+func classNames(value string) (names []string) {
+	for _, name := range strings.Fields(value) {
+		if classNameRegexp.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// readCSSFileClasses returns the class selectors of the CSS files in dir.
+func readCSSFileClasses(dir string) (names []string, err error) {
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && skipdir.ShouldSkip(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".css" {
+			return nil
+		}
+		css, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		names = append(names, classSelectors(string(css))...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSS files: %w", err)
+	}
+	return names, nil
+}
+
+// classDiagnostic is a problem found by checkClasses in a templ file.
+type classDiagnostic struct {
+	fileName string
+	parser.Diagnostic
+}
+
+// checkClasses cross-references the classes used by the templ files with the <style>
+// elements and CSS files that define them, and the css templates with the class attributes
+// that use them. It returns a diagnostic for each class that's used but never defined, and
+// each css template that's defined but never used.
+func checkClasses(files map[string]fileClasses, cssFileClasses []string) (diags []classDiagnostic) {
+	defined := map[string]struct{}{}
+	for _, name := range cssFileClasses {
+		defined[name] = struct{}{}
+	}
+	called := map[string]struct{}{}
+	for _, fc := range files {
+		for _, name := range fc.styleClasses {
+			defined[name] = struct{}{}
+		}
+		for _, name := range fc.calls {
+			called[name] = struct{}{}
+		}
+	}
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	slices.Sort(fileNames)
+	for _, fileName := range fileNames {
+		fc := files[fileName]
+		for _, ref := range fc.references {
+			if _, ok := defined[ref.name]; ok {
+				continue
+			}
+			diags = append(diags, classDiagnostic{
+				fileName: fileName,
+				Diagnostic: parser.Diagnostic{
+					Message: fmt.Sprintf("class %q is used, but not defined in a <style> element or CSS file", ref.name),
+					Range:   ref.r,
+				},
+			})
+		}
+		for _, t := range fc.cssTemplates {
+			if _, ok := called[t.Name]; ok {
+				continue
+			}
+			diags = append(diags, classDiagnostic{
+				fileName: fileName,
+				Diagnostic: parser.Diagnostic{
+					Message: fmt.Sprintf("css template %s is defined, but not used in a class attribute", t.Name),
+					Range:   t.Range,
+				},
+			})
+		}
+	}
+	return diags
+}
@@ -0,0 +1,89 @@
+package generatecmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClassSelectors(t *testing.T) {
+	css := `/* .comment { } */
+.card, .card > .title { padding: 1.5em; }
+@media (min-width: 40.5em) {
+	.card:hover { color: red; }
+}
+.list {
+	& .item-selected { font-weight: bold; }
+}`
+	expected := []string{"card", "card", "title", "card", "list", "item-selected"}
+	if diff := cmp.Diff(expected, classSelectors(css)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClassExpressionNames(t *testing.T) {
+	names, calls := classExpressionNames(`"btn btn-%d", templ.KV("active", isActive), cardBorder(), fmt.Sprintf("col-%d", n)`)
+	if diff := cmp.Diff([]string{"btn", "active"}, names); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]string{"KV", "cardBorder", "Sprintf"}, calls); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestCheckClasses(t *testing.T) {
+	button := `package components
+
+templ Button() {
+	<style>
+		.btn-primary { color: blue; }
+	</style>
+	<button class="btn btn-primray">Save</button>
+}
+`
+	card := `package components
+
+css cardBorder() {
+	border: 1px solid black;
+}
+
+css cardShadow() {
+	box-shadow: 0 0 2px black;
+}
+
+templ Card() {
+	<div class={ "card", cardShadow() }></div>
+}
+`
+	files := map[string]fileClasses{}
+	for fileName, src := range map[string]string{"button.templ": button, "card.templ": card} {
+		tf, err := parser.ParseString(src)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", fileName, err)
+		}
+		files[fileName] = findClasses(tf)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "styles.css"), []byte(".btn { } .card { }"), 0o644); err != nil {
+		t.Fatalf("failed to write styles.css: %v", err)
+	}
+	cssFileClasses, err := readCSSFileClasses(dir)
+	if err != nil {
+		t.Fatalf("failed to read CSS files: %v", err)
+	}
+
+	var actual []string
+	for _, d := range checkClasses(files, cssFileClasses) {
+		actual = append(actual, d.fileName+": "+d.Message)
+	}
+	expected := []string{
+		`button.templ: class "btn-primray" is used, but not defined in a <style> element or CSS file`,
+		`card.templ: css template cardBorder is defined, but not used in a class attribute`,
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -23,8 +23,11 @@ import (
 	"github.com/a-h/templ/cmd/templ/generatecmd/modcheck"
 	"github.com/a-h/templ/cmd/templ/generatecmd/proxy"
 	"github.com/a-h/templ/cmd/templ/generatecmd/run"
+	"github.com/a-h/templ/cmd/templ/generatecmd/static"
 	"github.com/a-h/templ/cmd/templ/generatecmd/watcher"
+	"github.com/a-h/templ/cmd/templ/sloghandler"
 	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/lint"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/cli/browser"
 	"github.com/fsnotify/fsnotify"
@@ -69,13 +72,7 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 	}
 
 	// Configure generator.
-	var opts []generator.GenerateOpt
-	if cmd.Args.IncludeVersion {
-		opts = append(opts, generator.WithVersion(templ.Version()))
-	}
-	if cmd.Args.IncludeTimestamp {
-		opts = append(opts, generator.WithTimestamp(time.Now()))
-	}
+	opts := generatorOptions(cmd.Args)
 
 	// Check the version of the templ module.
 	if err := modcheck.Check(cmd.Args.Path); err != nil {
@@ -93,6 +90,21 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 		cmd.Args.FileWriter,
 		cmd.Args.Lazy,
 	)
+	fseh.args = &cmd.Args
+	// Syntax added after the version of templ in go.mod keeps its previous meaning.
+	fseh.templVersion, _ = modcheck.TemplVersion(cmd.Args.Path)
+	start := time.Now()
+	if cmd.Args.SummaryHandler != nil {
+		defer func() { cmd.Args.SummaryHandler(fseh.Summary(time.Since(start))) }()
+	}
+	if cmd.Args.DiagnosticsFormat != "" && cmd.Args.DiagnosticsFormat != DiagnosticsFormatText {
+		fseh.report = &diagnosticsReport{}
+		defer func() {
+			if writeErr := fseh.report.write(cmd.Args.DiagnosticsWriter, cmd.Args.DiagnosticsFormat, cmd.Args.Path); writeErr != nil && err == nil {
+				err = fmt.Errorf("failed to write diagnostics: %w", writeErr)
+			}
+		}()
+	}
 
 	// If we're processing a single file, don't bother setting up the channels/multithreaing.
 	if cmd.Args.FileName != "" {
@@ -100,17 +112,21 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 			Name: cmd.Args.FileName,
 			Op:   fsnotify.Create,
 		})
+		if err != nil {
+			fseh.report.addError(err)
+		}
 		return err
 	}
 
-	// Start timer.
-	start := time.Now()
-
 	// For the initial filesystem walk and subsequent (optional) fsnotify events.
 	events := make(chan fsnotify.Event)
 	// For errs from the watcher.
 	errs := make(chan error)
 
+	// The errgroup context is cancelled when generation completes, so keep the parent context
+	// for rendering static output.
+	parentCtx := ctx
+
 	// Start process to push events into the events channel.
 	grp, ctx := errgroup.WithContext(ctx)
 	grp.Go(func() error {
@@ -147,7 +163,8 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 			cmd.Log.Debug("Fatal error, exiting")
 			return err
 		}
-		cmd.Log.Error("Error", slog.Any("error", err))
+		cmd.Log.Error("Error", errorAttrs(err)...)
+		fseh.report.addError(err)
 		errorCount++
 	}
 
@@ -173,10 +190,77 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 		return fmt.Errorf("generation completed with %d errors", errorCount)
 	}
 
-	cmd.Log.Info("Complete", slog.Int("updates", updates), slog.Duration("duration", time.Since(start)))
+	if cmd.Args.CSSOut != "" {
+		if err = writeCSS(cmd.Args.CSSOut, fseh.CSS()); err != nil {
+			return err
+		}
+		cmd.Log.Info("Wrote CSS", slog.String("file", cmd.Args.CSSOut))
+	}
+
+	if cmd.Args.CheckClasses {
+		diags, err := fseh.ClassDiagnostics()
+		if err != nil {
+			return err
+		}
+		for _, d := range diags {
+			fseh.report.add(d.fileName, ruleCheckClasses, lint.SeverityWarning, d.Diagnostic)
+			fseh.summary.addWarning(d.fileName, ruleCheckClasses, d.Diagnostic)
+			cmd.Log.Warn(d.Message,
+				slog.String("file", d.fileName),
+				slog.String("from", fmt.Sprintf("%d:%d", d.Range.From.Line, d.Range.From.Col)),
+				slog.String("to", fmt.Sprintf("%d:%d", d.Range.To.Line, d.Range.To.Col)),
+			)
+		}
+	}
+
+	if cmd.Args.StaticOut != "" {
+		if err = cmd.renderStatic(parentCtx); err != nil {
+			return err
+		}
+	}
+
+	summary := fseh.Summary(time.Since(start))
+	cmd.Log.Info("Complete",
+		slog.Int("updates", updates),
+		slog.Int("files", len(summary.Files)),
+		slog.Int("components", summary.Components),
+		slog.Int("literals", summary.Literals),
+		slog.Int("bytes", summary.Bytes),
+		slog.Int("warnings", summary.Warnings),
+		slog.Duration("duration", summary.Duration),
+	)
+	return nil
+}
+
+// writeCSS writes the CSS rules to the file, if they've changed, so that file watchers
+// aren't triggered by each run of templ generate.
+func writeCSS(fileName string, rules []string) error {
+	var css strings.Builder
+	for _, rule := range rules {
+		css.WriteString(rule)
+		css.WriteString("\n")
+	}
+	if existing, err := os.ReadFile(fileName); err == nil && string(existing) == css.String() {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return fmt.Errorf("failed to create CSS output directory: %w", err)
+	}
+	if err := os.WriteFile(fileName, []byte(css.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write CSS: %w", err)
+	}
 	return nil
 }
 
+// renderStatic renders the exported components that don't have any parameters to HTML files.
+func (cmd Generate) renderStatic(ctx context.Context) error {
+	modDir, pkgs, err := static.Find(cmd.Args.Path)
+	if err != nil {
+		return err
+	}
+	return static.Render(ctx, cmd.Log, modDir, cmd.Args.Path, cmd.Args.StaticOut, pkgs)
+}
+
 func (cmd Generate) groupUntilNoMessagesReceivedFor100ms(postGeneration chan *GenerationEvent) (grouped *GenerationEvent, updates int, ok bool, err error) {
 	timeout := time.NewTimer(time.Hour * 24 * 365)
 loop:
@@ -381,3 +465,90 @@ func (cmd *Generate) startProxy() (p *proxy.Handler, err error) {
 	}()
 	return p, nil
 }
+
+// errorAttrs returns structured log attributes for the error, including the file, duration
+// and position of generation errors.
+func errorAttrs(err error) (attrs []any) {
+	var ge GenerationError
+	if errors.As(err, &ge) {
+		attrs = append(attrs, slog.String("file", ge.FileName), slog.Duration("duration", ge.Duration))
+	}
+	return append(attrs, sloghandler.ErrorAttrs(err)...)
+}
+
+// generatorOptions returns the generator options set by the arguments.
+func generatorOptions(args Arguments) (opts []generator.GenerateOpt) {
+	if args.IncludeVersion {
+		opts = append(opts, generator.WithVersion(templ.Version()))
+	}
+	if args.IncludeTimestamp {
+		opts = append(opts, generator.WithTimestamp(time.Now()))
+	}
+	if args.TextTransform {
+		opts = append(opts, generator.WithTextTransform())
+	}
+	if args.URLSanitizer {
+		opts = append(opts, generator.WithURLSanitizer())
+	}
+	if args.ComponentMarkers {
+		opts = append(opts, generator.WithComponentMarkers())
+	}
+	if args.TestIDs {
+		opts = append(opts, generator.WithTestIDs())
+	}
+	if args.ErrorSnapshots {
+		opts = append(opts, generator.WithErrorSnapshots())
+	}
+	if args.TemplateRegistry {
+		opts = append(opts, generator.WithTemplateRegistry())
+	}
+	if args.InputRecording {
+		opts = append(opts, generator.WithInputRecording())
+	}
+	if args.Tracing {
+		opts = append(opts, generator.WithTracing())
+	}
+	if args.StaticCSSIDs {
+		opts = append(opts, generator.WithStaticCSSIDs())
+	}
+	if args.LiteralConstants {
+		opts = append(opts, generator.WithLiteralConstants())
+	}
+	if args.LiteralBytes {
+		opts = append(opts, generator.WithLiteralBytes())
+	}
+	if len(args.StaticData) > 0 {
+		opts = append(opts, generator.WithStaticData(args.StaticData))
+	}
+	if args.CSSOut != "" {
+		opts = append(opts, generator.WithExternalCSS())
+	}
+	if args.VoidElementStyle != generator.VoidElementsHTML || len(args.VoidElementNames) > 0 {
+		opts = append(opts, generator.WithVoidElements(args.VoidElementStyle, args.VoidElementNames...))
+	}
+	if args.ScriptTranspiler != "" {
+		opts = append(opts, generator.WithScriptTranspiler(commandScriptTranspiler(args.ScriptTranspiler)))
+	}
+	if args.RuntimeImportPath != "" {
+		opts = append(opts, generator.WithRuntimeImportPath(args.RuntimeImportPath))
+	}
+	if args.Minify {
+		opts = append(opts, generator.WithMinify())
+	}
+	if args.StrictNilComponents {
+		opts = append(opts, generator.WithStrictNilComponents())
+	}
+	if args.StrictText {
+		opts = append(opts, generator.WithStrictText())
+	}
+	if len(args.ExpressionValidators) > 0 {
+		opts = append(opts, generator.WithExpressionValidators(args.ExpressionValidators...))
+	}
+	switch {
+	case args.StrictErrors:
+		opts = append(opts, generator.WithStrictErrors(args.StrictAllow...))
+	case args.Strict:
+		opts = append(opts, generator.WithStrict(args.StrictAllow...))
+	}
+	return opts
+}
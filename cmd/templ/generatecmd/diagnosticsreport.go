@@ -0,0 +1,301 @@
+package generatecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/a-h/parse"
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/lint"
+	"github.com/a-h/templ/parser/v2"
+)
+
+const (
+	// DiagnosticsFormatText logs diagnostics, see -log-format.
+	DiagnosticsFormatText = "text"
+	// DiagnosticsFormatJSON writes diagnostics to stdout as a JSON array.
+	DiagnosticsFormatJSON = "json"
+	// DiagnosticsFormatSARIF writes diagnostics to stdout as a SARIF 2.1.0 log, e.g. for
+	// GitHub code scanning.
+	DiagnosticsFormatSARIF = "sarif"
+)
+
+// The rules of diagnostics that aren't found by linters.
+const (
+	ruleGenerate     = "generate"
+	ruleDiagnostic   = "diagnostic"
+	ruleCheckClasses = "check-classes"
+)
+
+// reportedDiagnostic is a problem found in a templ file, written by -diagnostics-format.
+// Lines and columns start at 1, and are 0 if the position isn't known.
+type reportedDiagnostic struct {
+	File      string `json:"file"`
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	Line      int    `json:"line,omitempty"`
+	Col       int    `json:"col,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	EndCol    int    `json:"endCol,omitempty"`
+	sortIndex int
+}
+
+// diagnosticsReport collects the diagnostics of a run of the generate command, so that they
+// can be written in a structured format once it completes. A nil report collects nothing.
+type diagnosticsReport struct {
+	m     sync.Mutex
+	diags []reportedDiagnostic
+}
+
+func (r *diagnosticsReport) add(fileName, rule string, severity lint.Severity, d parser.Diagnostic) {
+	r.append(reportedDiagnostic{
+		File:     fileName,
+		Rule:     rule,
+		Severity: severity.String(),
+		Message:  d.Message,
+		Line:     int(d.Range.From.Line) + 1,
+		Col:      int(d.Range.From.Col) + 1,
+		EndLine:  int(d.Range.To.Line) + 1,
+		EndCol:   int(d.Range.To.Col) + 1,
+	})
+}
+
+func (r *diagnosticsReport) addLint(fileName string, diags []lint.Diagnostic) {
+	for _, d := range diags {
+		r.add(fileName, d.Rule, d.Severity, d.Diagnostic)
+	}
+}
+
+// addError adds a diagnostic for an error returned by the generate command, at the position
+// of the error if it's a parse or Go syntax error.
+func (r *diagnosticsReport) addError(err error) {
+	if r == nil {
+		return
+	}
+	// Lint errors are reported by the diagnostics of the rules.
+	var le lintError
+	if errors.As(err, &le) {
+		return
+	}
+	d := reportedDiagnostic{
+		Rule:     ruleGenerate,
+		Severity: lint.SeverityError.String(),
+		Message:  err.Error(),
+	}
+	var ge GenerationError
+	if errors.As(err, &ge) {
+		d.File = ge.FileName
+	}
+	var pe parse.ParseError
+	var el scanner.ErrorList
+	switch {
+	case errors.As(err, &pe):
+		d.Line, d.Col = pe.Pos.Line+1, pe.Pos.Col+1
+	case errors.As(err, &el) && len(el) > 0:
+		d.Line, d.Col = el[0].Pos.Line, el[0].Pos.Column
+	}
+	r.append(d)
+}
+
+func (r *diagnosticsReport) append(d reportedDiagnostic) {
+	if r == nil {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	d.sortIndex = len(r.diags)
+	r.diags = append(r.diags, d)
+}
+
+// sorted returns the diagnostics ordered by file and position, with file names relative to
+// dir, so that the output doesn't depend on the order that files were generated in.
+func (r *diagnosticsReport) sorted(dir string) (diags []reportedDiagnostic) {
+	r.m.Lock()
+	diags = slices.Clone(r.diags)
+	r.m.Unlock()
+	for i, d := range diags {
+		if d.File == "" {
+			continue
+		}
+		fileName, err := filepath.Abs(d.File)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, fileName); err == nil {
+			diags[i].File = filepath.ToSlash(rel)
+		}
+	}
+	slices.SortFunc(diags, func(a, b reportedDiagnostic) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		if a.Col != b.Col {
+			return a.Col - b.Col
+		}
+		return a.sortIndex - b.sortIndex
+	})
+	return diags
+}
+
+// write writes the diagnostics to w in the format, with file names relative to dir.
+func (r *diagnosticsReport) write(w io.Writer, format, dir string) error {
+	if r == nil {
+		return nil
+	}
+	diags := r.sorted(dir)
+	var v any
+	switch format {
+	case DiagnosticsFormatJSON:
+		if diags == nil {
+			diags = []reportedDiagnostic{}
+		}
+		v = diags
+	case DiagnosticsFormatSARIF:
+		v = newSARIFLog(diags)
+	default:
+		return fmt.Errorf("unknown diagnostics format %q", format)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// lintError is returned by generation if linters find problems with the error severity.
+type lintError int
+
+func (e lintError) Error() string {
+	return fmt.Sprintf("linters found %d errors", int(e))
+}
+
+// The subset of SARIF 2.1.0 used to report diagnostics, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func newSARIFLog(diags []reportedDiagnostic) sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "templ",
+				Version:        templ.Version(),
+				InformationURI: "https://templ.guide",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+	ruleIndexes := map[string]int{}
+	for _, d := range diags {
+		index, ok := ruleIndexes[d.Rule]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndexes[d.Rule] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: d.Rule})
+		}
+		result := sarifResult{
+			RuleID:    d.Rule,
+			RuleIndex: index,
+			Level:     sarifLevel(d.Severity),
+			Message:   sarifMessage{Text: d.Message},
+		}
+		if d.File != "" {
+			location := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: d.File},
+				},
+			}
+			if d.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{
+					StartLine:   d.Line,
+					StartColumn: d.Col,
+					EndLine:     d.EndLine,
+					EndColumn:   d.EndCol,
+				}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		run.Results = append(run.Results, result)
+	}
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
+
+// sarifLevel returns the SARIF level of a lint severity.
+func sarifLevel(severity string) string {
+	switch severity {
+	case lint.SeverityError.String():
+		return "error"
+	case lint.SeverityInfo.String():
+		return "note"
+	}
+	return "warning"
+}
@@ -14,13 +14,16 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/a-h/templ/cmd/templ/visualize"
+	"github.com/a-h/templ/config"
 	"github.com/a-h/templ/generator"
 	"github.com/a-h/templ/internal/syncmap"
 	"github.com/a-h/templ/internal/syncset"
+	"github.com/a-h/templ/lint"
 	"github.com/a-h/templ/parser/v2"
 	"github.com/a-h/templ/runtime"
 	"github.com/fsnotify/fsnotify"
@@ -59,6 +62,7 @@ func NewFSEventHandler(
 		fileNameToLastModTime: syncmap.New[string, time.Time](),
 		fileNameToError:       syncset.New[string](),
 		fileNameToOutput:      syncmap.New[string, generator.GeneratorOutput](),
+		fileNameToClasses:     syncmap.New[string, fileClasses](),
 		devMode:               devMode,
 		hashes:                syncmap.New[string, [sha256.Size]byte](),
 		genOpts:               genOpts,
@@ -66,6 +70,7 @@ func NewFSEventHandler(
 		keepOrphanedFiles:     keepOrphanedFiles,
 		writer:                fileWriter,
 		lazy:                  lazy,
+		summary:               newSummaryRecorder(),
 	}
 	return fseh
 }
@@ -77,6 +82,7 @@ type FSEventHandler struct {
 	fileNameToLastModTime *syncmap.Map[string, time.Time]
 	fileNameToError       *syncset.Set[string]
 	fileNameToOutput      *syncmap.Map[string, generator.GeneratorOutput]
+	fileNameToClasses     *syncmap.Map[string, fileClasses]
 	devMode               bool
 	hashes                *syncmap.Map[string, [sha256.Size]byte]
 	genOpts               []generator.GenerateOpt
@@ -85,8 +91,100 @@ type FSEventHandler struct {
 	keepOrphanedFiles     bool
 	writer                FileWriterFunc
 	lazy                  bool
+	// args are the arguments of the generate command, used to apply package configuration
+	// files. If nil, package configuration files are ignored.
+	args *Arguments
+	// report collects diagnostics for -diagnostics-format. If nil, they're only logged.
+	report *diagnosticsReport
+	// summary collects the statistics of each generated file, see Summary.
+	summary *summaryRecorder
+	// templVersion is the version of templ required by the go.mod file, see
+	// generator.WithTemplVersion. If it's empty, all syntax is supported.
+	templVersion string
 }
 
+// packageSettings are the settings used to generate the templ files in a directory.
+type packageSettings struct {
+	genOpts    []generator.GenerateOpt
+	fileSuffix string
+	// urlSchemes are the additional URL schemes that are accepted in constant URL attributes.
+	urlSchemes []string
+}
+
+// packageSettings returns the settings of the templ files in dir, applying the [generate]
+// table of the templ.toml file of dir, and the package configuration file in dir, if there
+// are any, see PackageConfig.
+func (h *FSEventHandler) packageSettings(dir string) (settings packageSettings, err error) {
+	settings = packageSettings{genOpts: h.genOpts, fileSuffix: DefaultFileSuffix}
+	if h.args == nil {
+		return settings, nil
+	}
+	if h.args.FileSuffix != "" {
+		settings.fileSuffix = h.args.FileSuffix
+	}
+	settings.urlSchemes = generator.AllowedURLSchemes(h.args.StrictAllow)
+	project, err := config.Find(dir)
+	if err != nil {
+		return settings, err
+	}
+	if project.Generate.FileSuffix != nil {
+		if err = validateFileSuffix(*project.Generate.FileSuffix); err != nil {
+			return settings, fmt.Errorf("%s: %w", project.FileName, err)
+		}
+	}
+	pkg, ok, err := ReadPackageConfig(dir)
+	if err != nil {
+		return settings, err
+	}
+	if project.FileName != "" || ok {
+		args := PackageConfig(project.Generate).Apply(*h.args)
+		args = pkg.Apply(args)
+		settings.genOpts = generatorOptions(args)
+		settings.urlSchemes = generator.AllowedURLSchemes(args.StrictAllow)
+		if args.FileSuffix != "" {
+			settings.fileSuffix = args.FileSuffix
+		}
+	}
+	if h.devMode && settings.fileSuffix != DefaultFileSuffix {
+		// The runtime finds the text file of a generated file from its name in watch mode.
+		return settings, fmt.Errorf("%s: the file suffix %q isn't supported in watch mode, only %q is", dir, settings.fileSuffix, DefaultFileSuffix)
+	}
+	return settings, nil
+}
+
+// templFileName returns the name of the templ file that the Go file was generated from, if it
+// has the file suffix of its package. Other generators may use the same suffix as a custom
+// suffix, so the Go file must also have been generated by templ.
+func (h *FSEventHandler) templFileName(goFileName string) (templFileName string, ok bool) {
+	suffix := DefaultFileSuffix
+	if settings, err := h.packageSettings(filepath.Dir(goFileName)); err == nil {
+		suffix = settings.fileSuffix
+	}
+	if !strings.HasSuffix(goFileName, suffix) {
+		return "", false
+	}
+	if suffix != DefaultFileSuffix && !generatedByTempl(goFileName) {
+		return "", false
+	}
+	return strings.TrimSuffix(goFileName, suffix) + ".templ", true
+}
+
+// generatedByTempl returns true if the Go file starts with the comment written by templ.
+func generatedByTempl(goFileName string) bool {
+	f, err := os.Open(goFileName)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, len(generatedComment))
+	if _, err = io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return string(header) == generatedComment
+}
+
+const generatedComment = "// Code generated by templ - DO NOT EDIT."
+
 type GenerateResult struct {
 	// WatchedFileUpdated indicates that a file matching the watch pattern was updated.
 	WatchedFileUpdated bool
@@ -97,21 +195,23 @@ type GenerateResult struct {
 }
 
 func (h *FSEventHandler) HandleEvent(ctx context.Context, event fsnotify.Event) (result GenerateResult, err error) {
-	// Handle _templ.go files.
-	if !event.Has(fsnotify.Remove) && strings.HasSuffix(event.Name, "_templ.go") {
-		_, err = os.Stat(strings.TrimSuffix(event.Name, "_templ.go") + ".templ")
-		if !os.IsNotExist(err) {
-			return GenerateResult{}, err
-		}
-		// File is orphaned.
-		if h.keepOrphanedFiles {
-			return GenerateResult{}, nil
-		}
-		h.Log.Debug("Deleting orphaned Go file", slog.String("file", event.Name))
-		if err = os.Remove(event.Name); err != nil {
-			h.Log.Warn("Failed to remove orphaned file", slog.Any("error", err))
+	// Handle generated Go files.
+	if !event.Has(fsnotify.Remove) && strings.HasSuffix(event.Name, ".go") {
+		if templFileName, ok := h.templFileName(event.Name); ok {
+			_, err = os.Stat(templFileName)
+			if !os.IsNotExist(err) {
+				return GenerateResult{}, err
+			}
+			// File is orphaned.
+			if h.keepOrphanedFiles {
+				return GenerateResult{}, nil
+			}
+			h.Log.Debug("Deleting orphaned Go file", slog.String("file", event.Name))
+			if err = os.Remove(event.Name); err != nil {
+				h.Log.Warn("Failed to remove orphaned file", slog.Any("error", err))
+			}
+			return GenerateResult{WatchedFileUpdated: false, TemplFileGoUpdated: true, TemplFileTextUpdated: false}, nil
 		}
-		return GenerateResult{WatchedFileUpdated: false, TemplFileGoUpdated: true, TemplFileTextUpdated: false}, nil
 	}
 
 	// If the file hasn't been updated since the last time we processed it, ignore it.
@@ -138,24 +238,51 @@ func (h *FSEventHandler) HandleEvent(ctx context.Context, event fsnotify.Event)
 	}
 
 	// Handle templ files.
+	settings, err := h.packageSettings(filepath.Dir(event.Name))
+	if err != nil {
+		h.fileNameToError.Set(event.Name)
+		h.summary.start(event.Name)
+		h.summary.update(event.Name, func(fs *FileSummary) { fs.Error = err.Error() })
+		return GenerateResult{}, GenerationError{
+			FileName: event.Name,
+			Err:      err,
+		}
+	}
 
 	// If the go file is newer than the templ file, skip generation, because it's up-to-date.
-	if h.lazy && goFileIsUpToDate(event.Name, fileInfo.ModTime()) {
+	if h.lazy && goFileIsUpToDate(event.Name, settings.fileSuffix, fileInfo.ModTime()) {
 		h.Log.Debug("Skipping file because the Go file is up-to-date", slog.String("file", event.Name))
 		return GenerateResult{}, nil
 	}
 
 	// Start a processor.
 	start := time.Now()
+	h.summary.start(event.Name)
+	defer h.summary.update(event.Name, func(fs *FileSummary) { fs.Duration = time.Since(start) })
 	var diag []parser.Diagnostic
-	result, diag, err = h.generate(ctx, event.Name)
+	var lintDiag []lint.Diagnostic
+	result, diag, lintDiag, err = h.generate(ctx, event.Name, settings)
+	h.report.addLint(event.Name, lintDiag)
+	h.summary.addWarnings(event.Name, lintDiag)
+	if err == nil {
+		err = h.logLintDiagnostics(event.Name, lintDiag)
+	}
 	if err != nil {
 		h.fileNameToError.Set(event.Name)
-		return result, fmt.Errorf("failed to generate code for %q: %w", event.Name, err)
+		err = fmt.Errorf("failed to generate code for %q: %w", event.Name, err)
+		h.summary.update(event.Name, func(fs *FileSummary) { fs.Error = err.Error() })
+		return result, GenerationError{
+			FileName: event.Name,
+			Duration: time.Since(start),
+			Err:      err,
+		}
 	}
 	if len(diag) > 0 {
 		for _, d := range diag {
+			h.report.add(event.Name, ruleDiagnostic, lint.SeverityWarning, d)
+			h.summary.addWarning(event.Name, ruleDiagnostic, d)
 			h.Log.Warn(d.Message,
+				slog.String("file", event.Name),
 				slog.String("from", fmt.Sprintf("%d:%d", d.Range.From.Line, d.Range.From.Col)),
 				slog.String("to", fmt.Sprintf("%d:%d", d.Range.To.Line, d.Range.To.Col)),
 			)
@@ -170,8 +297,47 @@ func (h *FSEventHandler) HandleEvent(ctx context.Context, event fsnotify.Event)
 	return result, nil
 }
 
-func goFileIsUpToDate(templFileName string, templFileLastMod time.Time) (upToDate bool) {
-	goFileName := strings.TrimSuffix(templFileName, ".templ") + "_templ.go"
+// Summary returns the statistics of the templ files generated by the handler. The duration
+// is the duration of the run that generated them.
+func (h *FSEventHandler) Summary(duration time.Duration) Summary {
+	return h.summary.summary(duration)
+}
+
+// CSS returns the CSS rules of the generated templates, ordered by file name, see
+// generator.WithExternalCSS. Duplicate rules are removed.
+func (h *FSEventHandler) CSS() (rules []string) {
+	fileNames := h.fileNameToOutput.Keys()
+	slices.Sort(fileNames)
+	seen := map[string]struct{}{}
+	for _, fileName := range fileNames {
+		output, _ := h.fileNameToOutput.Get(fileName)
+		for _, rule := range output.CSS {
+			if _, ok := seen[rule]; ok {
+				continue
+			}
+			seen[rule] = struct{}{}
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// ClassDiagnostics cross-references the classes used by the generated templates with the
+// <style> elements and CSS files that define them, see -check-classes.
+func (h *FSEventHandler) ClassDiagnostics() (diags []classDiagnostic, err error) {
+	cssFileClasses, err := readCSSFileClasses(h.dir)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]fileClasses{}
+	for _, fileName := range h.fileNameToClasses.Keys() {
+		files[fileName], _ = h.fileNameToClasses.Get(fileName)
+	}
+	return checkClasses(files, cssFileClasses), nil
+}
+
+func goFileIsUpToDate(templFileName, fileSuffix string, templFileLastMod time.Time) (upToDate bool) {
+	goFileName := strings.TrimSuffix(templFileName, ".templ") + fileSuffix
 	goFileInfo, err := os.Stat(goFileName)
 	if err != nil {
 		return false
@@ -181,42 +347,90 @@ func goFileIsUpToDate(templFileName string, templFileLastMod time.Time) (upToDat
 
 // generate Go code for a single template.
 // If a basePath is provided, the filename included in error messages is relative to it.
-func (h *FSEventHandler) generate(ctx context.Context, fileName string) (result GenerateResult, diagnostics []parser.Diagnostic, err error) {
+// logLintDiagnostics logs the problems found by linters, and returns an error if any of them
+// have the error severity.
+func (h *FSEventHandler) logLintDiagnostics(fileName string, diags []lint.Diagnostic) error {
+	var errorCount int
+	for _, d := range diags {
+		level := slog.LevelWarn
+		switch d.Severity {
+		case lint.SeverityError:
+			level = slog.LevelError
+			errorCount++
+		case lint.SeverityInfo:
+			level = slog.LevelInfo
+		}
+		h.Log.Log(context.Background(), level, d.Message,
+			slog.String("rule", d.Rule),
+			slog.String("file", fileName),
+			slog.String("from", fmt.Sprintf("%d:%d", d.Range.From.Line, d.Range.From.Col)),
+			slog.String("to", fmt.Sprintf("%d:%d", d.Range.To.Line, d.Range.To.Col)),
+		)
+	}
+	if errorCount > 0 {
+		return lintError(errorCount)
+	}
+	return nil
+}
+
+func (h *FSEventHandler) generate(ctx context.Context, fileName string, settings packageSettings) (result GenerateResult, diagnostics []parser.Diagnostic, lintDiagnostics []lint.Diagnostic, err error) {
 	t, err := parser.Parse(fileName)
 	if err != nil {
-		return GenerateResult{}, nil, fmt.Errorf("%s parsing error: %w", fileName, err)
+		return GenerateResult{}, nil, nil, fmt.Errorf("%s parsing error: %w", fileName, err)
 	}
-	targetFileName := strings.TrimSuffix(fileName, ".templ") + "_templ.go"
+	targetFileName := strings.TrimSuffix(fileName, ".templ") + settings.fileSuffix
 
 	// Only use relative filenames to the basepath for filenames in runtime error messages.
 	absFilePath, err := filepath.Abs(fileName)
 	if err != nil {
-		return GenerateResult{}, nil, fmt.Errorf("failed to get absolute path for %q: %w", fileName, err)
+		return GenerateResult{}, nil, nil, fmt.Errorf("failed to get absolute path for %q: %w", fileName, err)
 	}
 	relFilePath, err := filepath.Rel(h.dir, absFilePath)
 	if err != nil {
-		return GenerateResult{}, nil, fmt.Errorf("failed to get relative path for %q: %w", fileName, err)
+		return GenerateResult{}, nil, nil, fmt.Errorf("failed to get relative path for %q: %w", fileName, err)
 	}
 	// Convert Windows file paths to Unix-style for consistency.
 	relFilePath = filepath.ToSlash(relFilePath)
 
+	genOpts := append(slices.Clone(settings.genOpts), generator.WithFileName(relFilePath))
+	if h.templVersion != "" {
+		genOpts = append(genOpts, generator.WithTemplVersion(h.templVersion))
+	}
+	previous, hasPrevious := h.fileNameToOutput.Get(fileName)
+	// Incremental generation isn't used in dev mode, because the text file must contain
+	// only the literals of the latest output.
+	if hasPrevious && !h.devMode {
+		genOpts = append(genOpts, generator.WithPreviousOutput(previous))
+	}
+	lineDirectives := h.args != nil && h.args.LineDirectives
+	if lineDirectives {
+		genOpts = append(genOpts, generator.WithLineDirectives(filepath.Base(targetFileName)))
+	}
+
 	var b bytes.Buffer
-	generatorOutput, err := generator.Generate(t, &b, append(h.genOpts, generator.WithFileName(relFilePath))...)
+	generatorOutput, err := generator.Generate(t, &b, genOpts...)
 	if err != nil {
-		return GenerateResult{}, nil, fmt.Errorf("%s generation error: %w", fileName, err)
+		return GenerateResult{}, nil, nil, fmt.Errorf("%s generation error: %w", fileName, err)
 	}
 
 	formattedGoCode, err := format.Source(b.Bytes())
 	if err != nil {
 		err = remapErrorList(err, generatorOutput.SourceMap, fileName)
-		return GenerateResult{}, nil, fmt.Errorf("%s source formatting error %w", fileName, err)
+		return GenerateResult{}, nil, nil, fmt.Errorf("%s source formatting error %w", fileName, err)
+	}
+	if lineDirectives {
+		formattedGoCode = generator.UpdateLineDirectives(formattedGoCode, targetFileName)
+	}
+	if h.args != nil && h.args.StackTraceMaps {
+		formattedGoCode = generator.AppendLineMap(formattedGoCode, b.Bytes(), generatorOutput.SourceMap, fileName)
 	}
+	h.summary.setOutput(fileName, generatorOutput, formattedGoCode)
 
 	// Hash output, and write out the file if the goCodeHash has changed.
 	goCodeHash := sha256.Sum256(formattedGoCode)
 	if h.hashes.CompareAndSwap(targetFileName, syncmap.UpdateIfChanged, goCodeHash) {
 		if err = h.writer(targetFileName, formattedGoCode); err != nil {
-			return result, nil, fmt.Errorf("failed to write target file %q: %w", targetFileName, err)
+			return result, nil, nil, fmt.Errorf("failed to write target file %q: %w", targetFileName, err)
 		}
 	}
 
@@ -228,28 +442,39 @@ func (h *FSEventHandler) generate(ctx context.Context, fileName string) (result
 		txtHash := sha256.Sum256([]byte(joined))
 		if h.hashes.CompareAndSwap(txtFileName, syncmap.UpdateIfChanged, txtHash) {
 			if err = os.WriteFile(txtFileName, []byte(joined), 0o644); err != nil {
-				return result, nil, fmt.Errorf("failed to write string literal file %q: %w", txtFileName, err)
+				return result, nil, nil, fmt.Errorf("failed to write string literal file %q: %w", txtFileName, err)
 			}
 		}
 		// Check whether the change would require a recompilation or text update to take effect.
-		previous, hasPrevious := h.fileNameToOutput.Get(fileName)
 		if hasPrevious {
 			result.TemplFileTextUpdated = generator.HasTextChanged(previous, generatorOutput)
 			result.TemplFileGoUpdated = generator.HasGoChanged(previous, generatorOutput)
 		}
-		h.fileNameToOutput.Set(fileName, generatorOutput)
+	}
+	h.fileNameToOutput.Set(fileName, generatorOutput)
+	if h.args != nil && h.args.CheckClasses {
+		h.fileNameToClasses.Set(fileName, findClasses(t))
 	}
 
 	parsedDiagnostics, err := parser.Diagnose(t)
 	if err != nil {
-		return result, nil, fmt.Errorf("%s diagnostics error: %w", fileName, err)
+		return result, nil, nil, fmt.Errorf("%s diagnostics error: %w", fileName, err)
+	}
+	parsedDiagnostics = allowURLSchemes(t, parsedDiagnostics, settings.urlSchemes)
+	parsedDiagnostics = append(parsedDiagnostics, generatorOutput.Diagnostics...)
+	if h.args != nil && len(h.args.Lint) > 0 {
+		linter, err := lint.New(h.args.Lint...)
+		if err != nil {
+			return result, nil, nil, err
+		}
+		lintDiagnostics = linter(t)
 	}
 
 	if h.genSourceMapVis {
 		err = generateSourceMapVisualisation(ctx, fileName, targetFileName, generatorOutput.SourceMap)
 	}
 
-	return result, parsedDiagnostics, err
+	return result, parsedDiagnostics, lintDiagnostics, err
 }
 
 // Takes an error from the formatter and attempts to convert the positions reported in the target file to their positions
@@ -313,3 +538,22 @@ func generateSourceMapVisualisation(ctx context.Context, templFileName, goFileNa
 	}
 	return nil
 }
+
+// allowURLSchemes removes the diagnostics of constant URL attributes that use the allowed
+// schemes, see generator.AllowedURLSchemes.
+func allowURLSchemes(t *parser.TemplateFile, diags []parser.Diagnostic, schemes []string) []parser.Diagnostic {
+	if len(schemes) == 0 {
+		return diags
+	}
+	allowed := map[parser.Diagnostic]struct{}{}
+	for _, d := range parser.DiagnoseUnsafeURLs(t) {
+		allowed[d] = struct{}{}
+	}
+	for _, d := range parser.DiagnoseUnsafeURLs(t, schemes...) {
+		delete(allowed, d)
+	}
+	return slices.DeleteFunc(diags, func(d parser.Diagnostic) bool {
+		_, ok := allowed[d]
+		return ok
+	})
+}
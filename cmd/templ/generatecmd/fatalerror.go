@@ -1,5 +1,7 @@
 package generatecmd
 
+import "time"
+
 type FatalError struct {
 	Err error
 }
@@ -21,3 +23,18 @@ func (e FatalError) As(target any) bool {
 	_, ok := target.(*FatalError)
 	return ok
 }
+
+// GenerationError is returned when code generation fails for a file.
+type GenerationError struct {
+	FileName string
+	Duration time.Duration
+	Err      error
+}
+
+func (e GenerationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e GenerationError) Unwrap() error {
+	return e.Err
+}
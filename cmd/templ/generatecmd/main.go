@@ -9,10 +9,13 @@ import (
 	"log/slog"
 	"regexp"
 	"runtime"
+	"strings"
 
 	_ "net/http/pprof"
 
 	"github.com/a-h/templ/cmd/templ/sloghandler"
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/lint"
 )
 
 const generateUsageText = `usage: templ generate [<args>...]
@@ -33,6 +36,70 @@ Args:
     Set to false to skip inclusion of the templ version in the generated code. (default true)
   -include-timestamp
     Set to true to include the current time in the generated code.
+  -text-transform
+    Set to true to pass template text through the text transformers in the render context.
+    Use with -path to enable it for a single package.
+  -url-sanitizer
+    Set to true to sanitize the href, src, action and formaction attributes of all elements with the URL sanitizer in the render context, see templ.WithURLSanitizer.
+  -component-markers
+    Set to true to write HTML comments before and after the output of each component, for use in development.
+  -test-ids
+    Set to true to add a data-testid attribute with the name of the component to the root elements of each component, for use in end-to-end tests.
+  -error-snapshots
+    Set to true to attach the parameters of a component to the error returned when it fails to render.
+  -template-registry
+    Set to true to register exported components, so that they can be looked up by name with templ.LookupTemplate.
+  -input-recording
+    Set to true to record the parameters of a sample of component renders, using the recorder set with templ.WithInputRecorder.
+  -tracing
+    Set to true to start a span for each component render, using the tracer set with templ.WithTracing.
+    Spans are only started in programs built with the templ_trace build tag.
+  -line-directives
+    Set to true to write //line directives, so that go vet, panics and debuggers report the lines of Go expressions in templ files.
+  -stack-trace-maps
+    Set to true to add a map of the lines of the generated code to the lines of the templ file, which the runtime/stacktrace package uses to rewrite stack traces.
+  -static-css-ids
+    Set to true to calculate the class names of CSS templates that only contain constant properties during generation, and write them as constants.
+  -literal-constants
+    Set to true to write the string literals of each file as package-level constants, instead of writing them inline.
+  -literal-bytes
+    Set to true to write the string literals of each file as package-level byte slices, so that writers that don't implement io.StringWriter don't convert them on each render.
+  -static-data <file>
+    Reads build-time data from a JSON object in file, e.g. {"site.Title": "My blog"}, and evaluates expressions that only depend on constants and the data during generation.
+  -strict
+    Set to true to warn about element and attribute names that aren't in the HTML, SVG, MathML or ARIA vocabularies, and to fail generation if constant URL attributes use unsafe schemes such as javascript:.
+  -strict-errors
+    Set to true to fail generation if unknown element or attribute names are found.
+  -strict-allow <names>
+    Comma separated list of additional element and attribute names to accept in strict mode, e.g. hx-*,x-data. Names ending with a colon are URL schemes to accept in constant URL attributes, e.g. sms:.
+  -strict-nil-components
+    Set to true to return an error when a @component expression is nil, instead of rendering nothing.
+  -strict-text
+    Set to true to fail generation if text contains a bare &, < or >, instead of writing it as it is.
+  -void-elements <style>
+    Set how void elements such as <br> are written: html (<br>), xhtml (<br/>) or closed (<br></br>). (default html)
+  -void-element-names <names>
+    Comma separated list of elements to treat as void elements, in addition to the HTML void elements.
+  -runtime-import-path <path>
+    Import the templ and templ runtime packages from path, instead of github.com/a-h/templ, e.g. for a fork of templ.
+  -minify
+    Set to true to remove HTML comments from the output. Conditional comments are kept.
+  -file-suffix <suffix>
+    Set the suffix of generated Go file names, that replaces the .templ extension. (default _templ.go)
+  -script-transpiler <command>
+    Command used to transpile script templates preceded by a //templ:ts directive from TypeScript to JavaScript, e.g. "esbuild --loader=ts".
+    The TypeScript is written to the command's stdin, and the JavaScript is read from its stdout.
+  -css-out <file>
+    Writes the CSS of CSS templates that only contain constant properties to file, instead of rendering it in <style> elements.
+  -check-classes
+    Set to true to warn about classes that are used in class attributes, but not defined in a <style> element or CSS file, and css templates that aren't used.
+  -lint <names>
+    Comma separated list of lint rules to run over the templ files, e.g. a11y,no-inline-styles:error. Problems with the error severity fail generation.
+  -diagnostics-format <format>
+    Set to json or sarif to write the diagnostics of the parser, linters and -check-classes, and generation errors, to stdout once generation completes, e.g. for GitHub code scanning. (default text)
+    Diagnostics are also logged. Can't be used with -watch or -stdout.
+  -static-out <dir>
+    Renders exported components that don't have any parameters to HTML files in dir.
   -watch
     Set to true to watch the path for changes and regenerate code.
   -watch-pattern <regexp>
@@ -59,6 +126,8 @@ Args:
     Set log verbosity level to "debug". (default "info")
   -log-level
     Set log verbosity level. (default "info", options: "debug", "info", "warn", "error")
+  -log-format
+    Set log output format. Use "json" for machine-readable logs. (default "text", options: "text", "json")
   -help
     Print help and exit.
 
@@ -87,6 +156,36 @@ func NewArguments(stdout, stderr io.Writer, args []string) (cmdArgs Arguments, l
 	cmd.BoolVar(&cmdArgs.GenerateSourceMapVisualisations, "source-map-visualisations", false, "")
 	cmd.BoolVar(&cmdArgs.IncludeVersion, "include-version", true, "")
 	cmd.BoolVar(&cmdArgs.IncludeTimestamp, "include-timestamp", false, "")
+	cmd.BoolVar(&cmdArgs.TextTransform, "text-transform", false, "")
+	cmd.BoolVar(&cmdArgs.URLSanitizer, "url-sanitizer", false, "")
+	cmd.BoolVar(&cmdArgs.ComponentMarkers, "component-markers", false, "")
+	cmd.BoolVar(&cmdArgs.TestIDs, "test-ids", false, "")
+	cmd.BoolVar(&cmdArgs.ErrorSnapshots, "error-snapshots", false, "")
+	cmd.BoolVar(&cmdArgs.TemplateRegistry, "template-registry", false, "")
+	cmd.BoolVar(&cmdArgs.InputRecording, "input-recording", false, "")
+	cmd.BoolVar(&cmdArgs.Tracing, "tracing", false, "")
+	cmd.BoolVar(&cmdArgs.LineDirectives, "line-directives", false, "")
+	cmd.BoolVar(&cmdArgs.StackTraceMaps, "stack-trace-maps", false, "")
+	cmd.BoolVar(&cmdArgs.StaticCSSIDs, "static-css-ids", false, "")
+	cmd.BoolVar(&cmdArgs.LiteralConstants, "literal-constants", false, "")
+	cmd.BoolVar(&cmdArgs.LiteralBytes, "literal-bytes", false, "")
+	staticDataFlag := cmd.String("static-data", "", "")
+	cmd.BoolVar(&cmdArgs.Strict, "strict", false, "")
+	cmd.BoolVar(&cmdArgs.StrictErrors, "strict-errors", false, "")
+	strictAllowFlag := cmd.String("strict-allow", "", "")
+	cmd.BoolVar(&cmdArgs.StrictNilComponents, "strict-nil-components", false, "")
+	cmd.BoolVar(&cmdArgs.StrictText, "strict-text", false, "")
+	voidElementsFlag := cmd.String("void-elements", "html", "")
+	voidElementNamesFlag := cmd.String("void-element-names", "", "")
+	cmd.StringVar(&cmdArgs.RuntimeImportPath, "runtime-import-path", "", "")
+	cmd.BoolVar(&cmdArgs.Minify, "minify", false, "")
+	cmd.StringVar(&cmdArgs.FileSuffix, "file-suffix", DefaultFileSuffix, "")
+	cmd.StringVar(&cmdArgs.ScriptTranspiler, "script-transpiler", "", "")
+	cmd.StringVar(&cmdArgs.CSSOut, "css-out", "", "")
+	cmd.BoolVar(&cmdArgs.CheckClasses, "check-classes", false, "")
+	lintFlag := cmd.String("lint", "", "")
+	cmd.StringVar(&cmdArgs.DiagnosticsFormat, "diagnostics-format", DiagnosticsFormatText, "")
+	cmd.StringVar(&cmdArgs.StaticOut, "static-out", "", "")
 	cmd.BoolVar(&cmdArgs.Watch, "watch", false, "")
 	watchPatternFlag := cmd.String("watch-pattern", defaultWatchPattern, "")
 	cmd.BoolVar(&cmdArgs.OpenBrowser, "open-browser", true, "")
@@ -101,16 +200,62 @@ func NewArguments(stdout, stderr io.Writer, args []string) (cmdArgs Arguments, l
 	cmd.BoolVar(&cmdArgs.Lazy, "lazy", false, "")
 	verboseFlag := cmd.Bool("v", false, "")
 	logLevelFlag := cmd.String("log-level", "info", "")
+	logFormatFlag := cmd.String("log-format", sloghandler.FormatText, "")
 	helpFlag := cmd.Bool("help", false, "")
 	if err = cmd.Parse(args); err != nil {
 		return Arguments{}, nil, false, fmt.Errorf("failed to parse arguments: %w", err)
 	}
 
-	log = sloghandler.NewLogger(*logLevelFlag, *verboseFlag, stderr)
+	if *logFormatFlag != sloghandler.FormatText && *logFormatFlag != sloghandler.FormatJSON {
+		return Arguments{}, nil, false, fmt.Errorf("invalid log format %q, expected %q or %q", *logFormatFlag, sloghandler.FormatText, sloghandler.FormatJSON)
+	}
+	log = sloghandler.NewLoggerWithFormat(*logFormatFlag, *logLevelFlag, *verboseFlag, stderr)
 
 	if cmdArgs.Watch && cmdArgs.FileName != "" {
 		return Arguments{}, log, *helpFlag, fmt.Errorf("cannot watch a single file, remove the -f or -watch flag")
 	}
+	if cmdArgs.StaticOut != "" && (cmdArgs.Watch || cmdArgs.FileName != "") {
+		return Arguments{}, log, *helpFlag, fmt.Errorf("cannot render static output in watch mode or for a single file, remove the -static-out flag")
+	}
+	if cmdArgs.CSSOut != "" && (cmdArgs.Watch || cmdArgs.FileName != "" || cmdArgs.Lazy) {
+		return Arguments{}, log, *helpFlag, fmt.Errorf("the CSS of all templates is required to write a stylesheet, remove the -css-out flag, or the -watch, -f and -lazy flags")
+	}
+	if cmdArgs.CheckClasses && (cmdArgs.Watch || cmdArgs.FileName != "" || cmdArgs.Lazy) {
+		return Arguments{}, log, *helpFlag, fmt.Errorf("all templates are required to check classes, remove the -check-classes flag, or the -watch, -f and -lazy flags")
+	}
+	switch cmdArgs.DiagnosticsFormat {
+	case DiagnosticsFormatText:
+	case DiagnosticsFormatJSON, DiagnosticsFormatSARIF:
+		if cmdArgs.Watch || *toStdoutFlag {
+			return Arguments{}, log, *helpFlag, fmt.Errorf("diagnostics can't be written to stdout in watch mode, or with generated code, remove the -diagnostics-format flag, or the -watch and -stdout flags")
+		}
+		cmdArgs.DiagnosticsWriter = stdout
+	default:
+		return Arguments{}, log, *helpFlag, fmt.Errorf("invalid diagnostics format %q, expected %q, %q or %q", cmdArgs.DiagnosticsFormat, DiagnosticsFormatText, DiagnosticsFormatJSON, DiagnosticsFormatSARIF)
+	}
+	if cmdArgs.VoidElementStyle, err = generator.ParseVoidElementStyle(*voidElementsFlag); err != nil {
+		return Arguments{}, log, *helpFlag, err
+	}
+	if *voidElementNamesFlag != "" {
+		cmdArgs.VoidElementNames = strings.Split(*voidElementNamesFlag, ",")
+	}
+	if *staticDataFlag != "" {
+		if cmdArgs.StaticData, err = readStaticData(*staticDataFlag); err != nil {
+			return Arguments{}, log, *helpFlag, err
+		}
+	}
+	if *strictAllowFlag != "" {
+		cmdArgs.StrictAllow = strings.Split(*strictAllowFlag, ",")
+	}
+	if *lintFlag != "" {
+		cmdArgs.Lint = strings.Split(*lintFlag, ",")
+		if _, err = lint.New(cmdArgs.Lint...); err != nil {
+			return Arguments{}, log, *helpFlag, err
+		}
+	}
+	if err = validateFileSuffix(cmdArgs.FileSuffix); err != nil {
+		return Arguments{}, log, *helpFlag, err
+	}
 	cmdArgs.WatchPattern, err = regexp.Compile(*watchPatternFlag)
 	if err != nil {
 		return cmdArgs, log, *helpFlag, fmt.Errorf("invalid watch pattern %q: %w", *watchPatternFlag, err)
@@ -144,10 +289,49 @@ type Arguments struct {
 	GenerateSourceMapVisualisations bool
 	IncludeVersion                  bool
 	IncludeTimestamp                bool
+	TextTransform                   bool
+	URLSanitizer                    bool
+	ComponentMarkers                bool
+	TestIDs                         bool
+	ErrorSnapshots                  bool
+	TemplateRegistry                bool
+	InputRecording                  bool
+	Tracing                         bool
+	LineDirectives                  bool
+	StackTraceMaps                  bool
+	StaticCSSIDs                    bool
+	LiteralConstants                bool
+	LiteralBytes                    bool
+	StaticData                      map[string]any
+	Strict                          bool
+	StrictErrors                    bool
+	StrictAllow                     []string
+	StrictNilComponents             bool
+	StrictText                      bool
+	CSSOut                          string
+	CheckClasses                    bool
+	Lint                            []string
+	VoidElementStyle                generator.VoidElementStyle
+	VoidElementNames                []string
+	RuntimeImportPath               string
+	Minify                          bool
+	FileSuffix                      string
+	ScriptTranspiler                string
+	StaticOut                       string
 	// PPROFPort is the port to run the pprof server on.
 	PPROFPort         int
 	KeepOrphanedFiles bool
 	Lazy              bool
+	// DiagnosticsFormat is the format that diagnostics are written to DiagnosticsWriter in,
+	// see DiagnosticsFormatText, DiagnosticsFormatJSON and DiagnosticsFormatSARIF.
+	DiagnosticsFormat string
+	DiagnosticsWriter io.Writer
+	// ExpressionValidators check the Go expressions of templates. They can only be set by
+	// programs that run the generate command, not by flags.
+	ExpressionValidators []generator.ExpressionValidator
+	// SummaryHandler is called with the statistics of the run once generation completes,
+	// including when it fails. It can only be set by programs that run the generate command.
+	SummaryHandler func(Summary)
 }
 
 type ArgumentError struct {
@@ -3,15 +3,20 @@ package generatecmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
+	"log/slog"
 	"os"
 	"path"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/a-h/templ/cmd/templ/testproject"
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
 	"github.com/a-h/templ/runtime"
 	"golang.org/x/sync/errgroup"
 )
@@ -58,6 +63,496 @@ func TestGenerate(t *testing.T) {
 			t.Fatalf("templates_templ.go was not created: %v", err)
 		}
 	})
+	t.Run("can write line directives", func(t *testing.T) {
+		// templ generate -line-directives -f templates.templ
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-line-directives", "-f", path.Join(dir, "templates.templ")})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+		generated, err := os.ReadFile(path.Join(dir, "templates_templ.go"))
+		if err != nil {
+			t.Fatalf("failed to read templates_templ.go: %v", err)
+		}
+		for _, expected := range []string{"\n//line templates.templ:", "\n//line templates_templ.go:"} {
+			if !strings.Contains(string(generated), expected) {
+				t.Errorf("expected %q in the generated code:\n%s", expected, generated)
+			}
+		}
+	})
+	t.Run("can write stack trace maps", func(t *testing.T) {
+		// templ generate -stack-trace-maps -f templates.templ
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-stack-trace-maps", "-f", path.Join(dir, "templates.templ")})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+		generated, err := os.ReadFile(path.Join(dir, "templates_templ.go"))
+		if err != nil {
+			t.Fatalf("failed to read templates_templ.go: %v", err)
+		}
+		if expected := "\nvar _ = templruntime.RegisterLineMap(\"templates.templ\", "; !strings.Contains(string(generated), expected) {
+			t.Errorf("expected %q in the generated code:\n%s", expected, generated)
+		}
+	})
+	t.Run("can write string literals as constants", func(t *testing.T) {
+		// templ generate -literal-constants -f templates.templ
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-literal-constants", "-f", path.Join(dir, "templates.templ")})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+		generated, err := os.ReadFile(path.Join(dir, "templates_templ.go"))
+		if err != nil {
+			t.Fatalf("failed to read templates_templ.go: %v", err)
+		}
+		if expected := "\nconst (\n\ttempl_7745c5c3_Literal_"; !strings.Contains(string(generated), expected) {
+			t.Errorf("expected %q in the generated code:\n%s", expected, generated)
+		}
+	})
+	t.Run("can write string literals as byte slices", func(t *testing.T) {
+		// templ generate -literal-bytes -f templates.templ
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-literal-bytes", "-f", path.Join(dir, "templates.templ")})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+		generated, err := os.ReadFile(path.Join(dir, "templates_templ.go"))
+		if err != nil {
+			t.Fatalf("failed to read templates_templ.go: %v", err)
+		}
+		for _, expected := range []string{"\nvar (\n\ttempl_7745c5c3_Literal_", "templruntime.WriteBytes(templ_7745c5c3_Buffer, "} {
+			if !strings.Contains(string(generated), expected) {
+				t.Errorf("expected %q in the generated code:\n%s", expected, generated)
+			}
+		}
+	})
+	t.Run("can evaluate expressions with static data", func(t *testing.T) {
+		// templ generate -static-data data.json -path dir
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		title := "package templates\n\ntempl Title() {\n\t<title>{ site.Title + \" - Home\" }</title>\n}\n"
+		if err = os.WriteFile(path.Join(dir, "title.templ"), []byte(title), 0o644); err != nil {
+			t.Fatalf("failed to write title.templ: %v", err)
+		}
+		dataFileName := path.Join(t.TempDir(), "data.json")
+		if err = os.WriteFile(dataFileName, []byte(`{"site.Title": "My blog"}`), 0o644); err != nil {
+			t.Fatalf("failed to write data.json: %v", err)
+		}
+
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-static-data", dataFileName, "-path", dir})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+		generated, err := os.ReadFile(path.Join(dir, "title_templ.go"))
+		if err != nil {
+			t.Fatalf("failed to read title_templ.go: %v", err)
+		}
+		if expected := "<title>My blog - Home</title>"; !strings.Contains(string(generated), expected) {
+			t.Errorf("expected %q in the generated code:\n%s", expected, generated)
+		}
+	})
+	t.Run("can write the CSS of CSS templates to a stylesheet", func(t *testing.T) {
+		// templ generate -css-out styles/templ.css
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		styles := "package main\n\ncss red() {\n\tcolor: #ff0000;\n}\n\ncss width(w string) {\n\twidth: { w };\n}\n"
+		if err = os.WriteFile(path.Join(dir, "styles.templ"), []byte(styles), 0o644); err != nil {
+			t.Fatalf("failed to write styles.templ: %v", err)
+		}
+
+		cssFileName := path.Join(dir, "styles", "templ.css")
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir, "-css-out", cssFileName})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+
+		css, err := os.ReadFile(cssFileName)
+		if err != nil {
+			t.Fatalf("failed to read CSS: %v", err)
+		}
+		if expected := ".red_9b879d72{color:#ff0000;}\n"; string(css) != expected {
+			t.Errorf("expected CSS %q, got %q", expected, string(css))
+		}
+		goCode, err := os.ReadFile(path.Join(dir, "styles_templ.go"))
+		if err != nil {
+			t.Fatalf("failed to read styles_templ.go: %v", err)
+		}
+		if !strings.Contains(string(goCode), "return templ.SafeClass(templ_7745c5c3_redCSSID)") {
+			t.Errorf("expected the CSS template to return its class name, got:\n%s", goCode)
+		}
+	})
+	t.Run("applies package configuration files", func(t *testing.T) {
+		// templ generate -path dir, with a templ.yaml file in the card package.
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		cardDir := path.Join(dir, "card")
+		if err = os.Mkdir(cardDir, 0o755); err != nil {
+			t.Fatalf("failed to create card directory: %v", err)
+		}
+		card := "package card\n\ntempl Card() {\n\t<!-- Card -->\n\t<div>Card</div>\n}\n"
+		if err = os.WriteFile(path.Join(cardDir, "card.templ"), []byte(card), 0o644); err != nil {
+			t.Fatalf("failed to write card.templ: %v", err)
+		}
+		config := "minify: true\nfileSuffix: .gen.go\n"
+		if err = os.WriteFile(path.Join(cardDir, "templ.yaml"), []byte(config), 0o644); err != nil {
+			t.Fatalf("failed to write templ.yaml: %v", err)
+		}
+
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+
+		goCode, err := os.ReadFile(path.Join(cardDir, "card.gen.go"))
+		if err != nil {
+			t.Fatalf("failed to read card.gen.go: %v", err)
+		}
+		if strings.Contains(string(goCode), "<!--") {
+			t.Errorf("expected the comment to be removed, got:\n%s", goCode)
+		}
+		if _, err = os.Stat(path.Join(cardDir, "card_templ.go")); err == nil {
+			t.Error("expected card_templ.go not to be created")
+		}
+		if _, err = os.Stat(path.Join(dir, "templates_templ.go")); err != nil {
+			t.Errorf("expected packages without a config file to use the default file suffix: %v", err)
+		}
+
+		// Generated files with the custom suffix are deleted when their templ file is removed,
+		// but files with the same suffix written by other generators are kept.
+		if err = os.Remove(path.Join(cardDir, "card.templ")); err != nil {
+			t.Fatalf("failed to remove card.templ: %v", err)
+		}
+		other := "// Code generated by other - DO NOT EDIT.\n\npackage card\n"
+		if err = os.WriteFile(path.Join(cardDir, "models.gen.go"), []byte(other), 0o644); err != nil {
+			t.Fatalf("failed to write models.gen.go: %v", err)
+		}
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+		if _, err = os.Stat(path.Join(cardDir, "card.gen.go")); !os.IsNotExist(err) {
+			t.Errorf("expected the orphaned card.gen.go to be deleted, got %v", err)
+		}
+		if _, err = os.Stat(path.Join(cardDir, "models.gen.go")); err != nil {
+			t.Errorf("expected models.gen.go to be kept: %v", err)
+		}
+	})
+	t.Run("applies the generate options of templ.toml", func(t *testing.T) {
+		// templ generate -path dir, with a templ.toml file in the module, and a templ.yaml
+		// file in the list package.
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		if err = os.WriteFile(path.Join(dir, "templ.toml"), []byte("[generate]\nminify = true\n"), 0o644); err != nil {
+			t.Fatalf("failed to write templ.toml: %v", err)
+		}
+		for _, name := range []string{"card", "list"} {
+			if err = os.Mkdir(path.Join(dir, name), 0o755); err != nil {
+				t.Fatalf("failed to create %s directory: %v", name, err)
+			}
+			templ := "package " + name + "\n\ntempl Component() {\n\t<!-- Comment -->\n\t<div>Component</div>\n}\n"
+			if err = os.WriteFile(path.Join(dir, name, name+".templ"), []byte(templ), 0o644); err != nil {
+				t.Fatalf("failed to write %s.templ: %v", name, err)
+			}
+		}
+		if err = os.WriteFile(path.Join(dir, "list", "templ.yaml"), []byte("minify: false\n"), 0o644); err != nil {
+			t.Fatalf("failed to write templ.yaml: %v", err)
+		}
+
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir})
+		if err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+
+		card, err := os.ReadFile(path.Join(dir, "card", "card_templ.go"))
+		if err != nil {
+			t.Fatalf("failed to read card_templ.go: %v", err)
+		}
+		if strings.Contains(string(card), "<!--") {
+			t.Errorf("expected templ.toml to apply to subdirectories, and remove the comment, got:\n%s", card)
+		}
+		list, err := os.ReadFile(path.Join(dir, "list", "list_templ.go"))
+		if err != nil {
+			t.Fatalf("failed to read list_templ.go: %v", err)
+		}
+		if !strings.Contains(string(list), "<!-- Comment -->") {
+			t.Errorf("expected templ.yaml to override templ.toml, and keep the comment, got:\n%s", list)
+		}
+	})
+	t.Run("fails generation if an expression validator returns diagnostics", func(t *testing.T) {
+		// templ generate -path dir, with a validator that forbids fmt.Sprintf.
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+
+		stderr := new(bytes.Buffer)
+		args, log, _, err := NewArguments(io.Discard, stderr, []string{"-path", dir})
+		if err != nil {
+			t.Fatalf("failed to parse arguments: %v", err)
+		}
+		args.ExpressionValidators = []generator.ExpressionValidator{
+			func(e generator.GoExpression) []parser.Diagnostic {
+				if !strings.Contains(e.Expression.Value, "fmt.Sprintf") {
+					return nil
+				}
+				return []parser.Diagnostic{{Message: "fmt.Sprintf is not allowed", Range: e.Expression.Range}}
+			},
+		}
+		g, err := NewGenerate(log, args)
+		if err != nil {
+			t.Fatalf("failed to create generate command: %v", err)
+		}
+		err = g.Run(context.Background())
+		if err == nil {
+			t.Fatal("expected generation to fail")
+		}
+		if !strings.Contains(stderr.String(), "fmt.Sprintf is not allowed") {
+			t.Errorf("expected the diagnostic to be logged, got:\n%s", stderr.String())
+		}
+	})
+	t.Run("reports a summary of the generated files", func(t *testing.T) {
+		// templ generate -path dir -lint a11y
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		logo := "package main\n\ntempl logo() {\n\t<img src=\"logo.png\"/>\n}\n"
+		if err = os.WriteFile(path.Join(dir, "logo.templ"), []byte(logo), 0o644); err != nil {
+			t.Fatalf("failed to write logo.templ: %v", err)
+		}
+
+		args, log, _, err := NewArguments(io.Discard, io.Discard, []string{"-path", dir, "-lint", "a11y"})
+		if err != nil {
+			t.Fatalf("failed to parse arguments: %v", err)
+		}
+		var summary Summary
+		args.SummaryHandler = func(s Summary) { summary = s }
+		g, err := NewGenerate(log, args)
+		if err != nil {
+			t.Fatalf("failed to create generate command: %v", err)
+		}
+		if err = g.Run(context.Background()); err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+		var logoSummary *FileSummary
+		for i, fs := range summary.Files {
+			if path.Base(fs.FileName) == "logo.templ" {
+				logoSummary = &summary.Files[i]
+			}
+		}
+		if logoSummary == nil {
+			t.Fatalf("expected logo.templ in the summary, got %+v", summary.Files)
+		}
+		if logoSummary.Components != 1 || logoSummary.Literals != 1 || logoSummary.Bytes == 0 {
+			t.Errorf("unexpected summary of logo.templ: %+v", logoSummary)
+		}
+		if len(logoSummary.Warnings) != 1 || logoSummary.Warnings[0].Rule != "a11y" {
+			t.Errorf("expected an a11y warning, got %+v", logoSummary.Warnings)
+		}
+		if summary.Components <= logoSummary.Components || summary.Bytes <= logoSummary.Bytes || summary.Warnings != 1 || summary.Errors != 0 {
+			t.Errorf("unexpected totals: %+v", summary)
+		}
+	})
+	t.Run("logs the problems found by linters", func(t *testing.T) {
+		// templ generate -path dir -lint a11y
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		logo := "package main\n\ntempl logo() {\n\t<img src=\"logo.png\"/>\n}\n"
+		if err = os.WriteFile(path.Join(dir, "logo.templ"), []byte(logo), 0o644); err != nil {
+			t.Fatalf("failed to write logo.templ: %v", err)
+		}
+
+		stderr := new(bytes.Buffer)
+		if err = Run(context.Background(), io.Discard, stderr, []string{"-path", dir, "-lint", "a11y"}); err != nil {
+			t.Fatalf("failed to run generate command: %v", err)
+		}
+		if !strings.Contains(stderr.String(), "<img> elements must have an alt attribute") {
+			t.Errorf("expected the a11y problem to be logged, got:\n%s", stderr.String())
+		}
+	})
+	t.Run("lint rules with the error severity fail generation", func(t *testing.T) {
+		// templ generate -path dir -lint no-inline-styles:error
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		banner := "package main\n\ntempl banner() {\n\t<div style=\"color: red\">Sale</div>\n}\n"
+		if err = os.WriteFile(path.Join(dir, "banner.templ"), []byte(banner), 0o644); err != nil {
+			t.Fatalf("failed to write banner.templ: %v", err)
+		}
+
+		stderr := new(bytes.Buffer)
+		err = Run(context.Background(), io.Discard, stderr, []string{"-path", dir, "-lint", "no-inline-styles:error"})
+		if err == nil {
+			t.Fatal("expected generation to fail")
+		}
+		if !strings.Contains(stderr.String(), "rule=no-inline-styles") {
+			t.Errorf("expected the rule to be logged, got:\n%s", stderr.String())
+		}
+	})
+	t.Run("can write diagnostics as SARIF", func(t *testing.T) {
+		// templ generate -path dir -lint no-inline-styles:error -diagnostics-format sarif
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		banner := "package main\n\ntempl banner() {\n\t<div style=\"color: red\">Sale</div>\n}\n"
+		if err = os.WriteFile(path.Join(dir, "banner.templ"), []byte(banner), 0o644); err != nil {
+			t.Fatalf("failed to write banner.templ: %v", err)
+		}
+
+		stdout := new(bytes.Buffer)
+		err = Run(context.Background(), stdout, io.Discard, []string{"-path", dir, "-lint", "no-inline-styles:error", "-diagnostics-format", "sarif"})
+		if err == nil {
+			t.Fatal("expected generation to fail")
+		}
+		var log sarifLog
+		if err = json.Unmarshal(stdout.Bytes(), &log); err != nil {
+			t.Fatalf("failed to unmarshal SARIF: %v\n%s", err, stdout.String())
+		}
+		if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+			t.Fatalf("expected a single result, got:\n%s", stdout.String())
+		}
+		result := log.Runs[0].Results[0]
+		if result.RuleID != "no-inline-styles" || result.Level != "error" {
+			t.Errorf("expected an error of the no-inline-styles rule, got %q %q", result.RuleID, result.Level)
+		}
+		if len(result.Locations) != 1 {
+			t.Fatalf("expected a location, got %#v", result.Locations)
+		}
+		location := result.Locations[0].PhysicalLocation
+		if location.ArtifactLocation.URI != "banner.templ" {
+			t.Errorf("expected the path to be relative to the project, got %q", location.ArtifactLocation.URI)
+		}
+		if location.Region == nil || location.Region.StartLine != 4 {
+			t.Errorf("expected the result to start on line 4, got %#v", location.Region)
+		}
+	})
+	t.Run("can write generation errors as JSON", func(t *testing.T) {
+		// templ generate -f broken.templ -diagnostics-format json
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+		broken := "package main\n\ntempl broken() {\n\t<div>\n}\n"
+		if err = os.WriteFile(path.Join(dir, "broken.templ"), []byte(broken), 0o644); err != nil {
+			t.Fatalf("failed to write broken.templ: %v", err)
+		}
+
+		stdout := new(bytes.Buffer)
+		err = Run(context.Background(), stdout, io.Discard, []string{"-path", dir, "-f", path.Join(dir, "broken.templ"), "-diagnostics-format", "json"})
+		if err == nil {
+			t.Fatal("expected generation to fail")
+		}
+		var diags []reportedDiagnostic
+		if err = json.Unmarshal(stdout.Bytes(), &diags); err != nil {
+			t.Fatalf("failed to unmarshal diagnostics: %v\n%s", err, stdout.String())
+		}
+		if len(diags) != 1 {
+			t.Fatalf("expected a single diagnostic, got:\n%s", stdout.String())
+		}
+		d := diags[0]
+		if d.File != "broken.templ" || d.Rule != "generate" || d.Severity != "error" || d.Line == 0 {
+			t.Errorf("expected a positioned generation error in broken.templ, got %#v", d)
+		}
+	})
 	t.Run("can generate a file in watch mode", func(t *testing.T) {
 		// templ generate -f templates.templ
 		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
@@ -239,6 +734,111 @@ func TestArgs(t *testing.T) {
 			t.Fatalf("expected watch pattern to be %q, got %q", defaultWatchPattern, args.WatchPattern.String())
 		}
 	})
+	t.Run("Static output can't be rendered in watch mode", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-static-out", "dist", "-watch"})
+		if err == nil {
+			t.Fatal("expected error when static output is used with watch mode")
+		}
+	})
+	t.Run("CSS can't be written in watch mode", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-css-out", "templ.css", "-watch"})
+		if err == nil {
+			t.Fatal("expected error when CSS output is used with watch mode")
+		}
+	})
+	t.Run("Classes can't be checked in watch mode", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-check-classes", "-watch"})
+		if err == nil {
+			t.Fatal("expected error when class checking is used with watch mode")
+		}
+	})
+	t.Run("Diagnostics can't be written to stdout in watch mode", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-diagnostics-format", "sarif", "-watch"})
+		if err == nil {
+			t.Fatal("expected error when diagnostics are written in watch mode")
+		}
+	})
+	t.Run("Unknown diagnostics formats are rejected", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-diagnostics-format", "xml"})
+		if err == nil || !strings.Contains(err.Error(), `invalid diagnostics format "xml"`) {
+			t.Fatalf("expected an invalid diagnostics format error, got %v", err)
+		}
+	})
+	t.Run("Unknown lint severities are rejected", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-lint", "a11y:fatal"})
+		if err == nil || !strings.Contains(err.Error(), `unknown severity "fatal"`) {
+			t.Fatalf("expected an unknown severity error, got %v", err)
+		}
+	})
+	t.Run("Unknown linters are rejected", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-lint", "a11y,spelling"})
+		if err == nil || !strings.Contains(err.Error(), `unknown linter "spelling"`) {
+			t.Fatalf("expected an unknown linter error, got %v", err)
+		}
+	})
+	t.Run("The void element style is parsed", func(t *testing.T) {
+		args, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-void-elements", "xhtml", "-void-element-names", "br,my-icon"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if args.VoidElementStyle != generator.VoidElementsXHTML {
+			t.Errorf("expected the XHTML void element style, got %v", args.VoidElementStyle)
+		}
+		if len(args.VoidElementNames) != 2 || args.VoidElementNames[0] != "br" || args.VoidElementNames[1] != "my-icon" {
+			t.Errorf("expected void element names to be [br my-icon], got %v", args.VoidElementNames)
+		}
+	})
+	t.Run("Unknown void element styles are rejected", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-void-elements", "xml"})
+		if err == nil {
+			t.Fatal("expected error for unknown void element style")
+		}
+	})
+	t.Run("Static output is set", func(t *testing.T) {
+		args, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-static-out", "dist"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if args.StaticOut != "dist" {
+			t.Fatalf("expected static output to be %q, got %q", "dist", args.StaticOut)
+		}
+	})
+	t.Run("Static data is read from a JSON file", func(t *testing.T) {
+		fileName := path.Join(t.TempDir(), "data.json")
+		if err := os.WriteFile(fileName, []byte(`{"site.Title": "My blog", "site.Year": 2025, "site.Rating": 4.5}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		args, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-static-data", fileName})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]any{"site.Title": "My blog", "site.Year": 2025, "site.Rating": 4.5}
+		if !reflect.DeepEqual(args.StaticData, expected) {
+			t.Errorf("expected static data %v, got %v", expected, args.StaticData)
+		}
+	})
+	t.Run("Invalid static data files are rejected", func(t *testing.T) {
+		fileName := path.Join(t.TempDir(), "data.json")
+		if err := os.WriteFile(fileName, []byte(`["My blog"]`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-static-data", fileName})
+		if err == nil || !strings.Contains(err.Error(), "invalid static data") {
+			t.Fatalf("expected an invalid static data error, got %v", err)
+		}
+	})
+	t.Run("The strict allow list is split on commas", func(t *testing.T) {
+		args, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-strict", "-strict-allow", "hx-*,x-data"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !args.Strict {
+			t.Fatal("expected strict to be true")
+		}
+		if len(args.StrictAllow) != 2 || args.StrictAllow[0] != "hx-*" || args.StrictAllow[1] != "x-data" {
+			t.Fatalf("expected strict allow list to be [hx-* x-data], got %v", args.StrictAllow)
+		}
+	})
 	t.Run("If the watchPattern is set, it is checked for validity", func(t *testing.T) {
 		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-watch-pattern", "invalid[pattern"})
 		if err == nil {
@@ -272,4 +872,25 @@ func TestArgs(t *testing.T) {
 			t.Fatalf("expected command to be 'echo hello', got '%s'", args.Command)
 		}
 	})
+	t.Run("If the log format is json, logs are written as JSON", func(t *testing.T) {
+		stderr := new(bytes.Buffer)
+		_, log, _, err := NewArguments(io.Discard, stderr, []string{"-log-format", "json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		log.Info("Generated code", slog.String("file", "template.templ"))
+		var entry map[string]any
+		if err = json.Unmarshal(stderr.Bytes(), &entry); err != nil {
+			t.Fatalf("expected JSON log output, got %q: %v", stderr.String(), err)
+		}
+		if entry["file"] != "template.templ" {
+			t.Fatalf("expected file attribute to be 'template.templ', got %v", entry["file"])
+		}
+	})
+	t.Run("If the log format is invalid, an error is returned", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-log-format", "xml"})
+		if err == nil {
+			t.Fatal("expected error when log format is invalid")
+		}
+	})
 }
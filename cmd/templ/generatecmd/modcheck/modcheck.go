@@ -45,38 +45,52 @@ func WalkUp(dir string) (string, error) {
 }
 
 func Check(dir string) error {
-	dir, err := WalkUp(dir)
+	v, err := TemplVersion(dir)
 	if err != nil {
 		return err
 	}
+	if v == "" {
+		// The go.mod file is for templ itself.
+		return nil
+	}
+	cmp := semver.Compare(v, templ.Version())
+	if cmp < 0 {
+		return fmt.Errorf("generator %v is newer than templ version %v found in go.mod file, consider running `go get -u github.com/a-h/templ` to upgrade", templ.Version(), v)
+	}
+	if cmp > 0 {
+		return fmt.Errorf("generator %v is older than templ version %v found in go.mod file, consider upgrading templ CLI", templ.Version(), v)
+	}
+	return nil
+}
+
+// TemplVersion returns the version of templ required by the go.mod file of the module that
+// contains dir, or "" if the module is templ itself.
+func TemplVersion(dir string) (version string, err error) {
+	dir, err = WalkUp(dir)
+	if err != nil {
+		return "", err
+	}
 
 	// Found a go.mod file.
 	// Read it and find the templ version.
 	modFile := filepath.Join(dir, "go.mod")
 	m, err := os.ReadFile(modFile)
 	if err != nil {
-		return fmt.Errorf("failed to read go.mod file: %w", err)
+		return "", fmt.Errorf("failed to read go.mod file: %w", err)
 	}
 
 	mf, err := modfile.Parse(modFile, m, nil)
 	if err != nil {
-		return fmt.Errorf("failed to parse go.mod file: %w", err)
+		return "", fmt.Errorf("failed to parse go.mod file: %w", err)
 	}
 	if mf.Module.Mod.Path == "github.com/a-h/templ" {
 		// The go.mod file is for templ itself.
-		return nil
+		return "", nil
 	}
 	for _, r := range mf.Require {
 		if r.Mod.Path == "github.com/a-h/templ" {
-			cmp := semver.Compare(r.Mod.Version, templ.Version())
-			if cmp < 0 {
-				return fmt.Errorf("generator %v is newer than templ version %v found in go.mod file, consider running `go get -u github.com/a-h/templ` to upgrade", templ.Version(), r.Mod.Version)
-			}
-			if cmp > 0 {
-				return fmt.Errorf("generator %v is older than templ version %v found in go.mod file, consider upgrading templ CLI", templ.Version(), r.Mod.Version)
-			}
-			return nil
+			return r.Mod.Version, nil
 		}
 	}
-	return fmt.Errorf("templ not found in go.mod file, run `go get github.com/a-h/templ` to install it")
+	return "", fmt.Errorf("templ not found in go.mod file, run `go get github.com/a-h/templ` to install it")
 }
@@ -0,0 +1,183 @@
+package generatecmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/config"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileSuffix is the suffix of generated Go file names, that replaces the .templ
+// extension of the templ file.
+const DefaultFileSuffix = "_templ.go"
+
+// PackageConfigFileNames are the names of the package configuration files, see PackageConfig.
+var PackageConfigFileNames = []string{"templ.json", "templ.yaml", "templ.yml"}
+
+// PackageConfig is the generation configuration of a package, read from a templ.json or
+// templ.yaml file in the same directory as the templ files of the package. Options that
+// are set override the flags of the generate command, and the [generate] table of templ.toml,
+// for the templ files in the directory, so that packages of a monorepo can be generated
+// differently. It has the same options as templ.toml, see config.Generate.
+//
+//	{
+//		"runtimeImportPath": "example.com/fork/templ",
+//		"strict": true,
+//		"strictAllow": ["hx-*"],
+//		"minify": true,
+//		"fileSuffix": ".gen.go",
+//		"strictNilComponents": true,
+//		"strictText": true,
+//		"textTransform": true,
+//		"urlSanitizer": true,
+//		"literalConstants": true,
+//		"literalBytes": true,
+//		"staticData": {"site.Title": "My blog"}
+//	}
+type PackageConfig config.Generate
+
+// ReadPackageConfig reads the package configuration file in dir. ok is false if there isn't
+// one. It's an error for a directory to contain more than one configuration file.
+func ReadPackageConfig(dir string) (config PackageConfig, ok bool, err error) {
+	var fileName string
+	var data []byte
+	for _, name := range PackageConfigFileNames {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return config, false, fmt.Errorf("failed to read package config: %w", err)
+		}
+		if fileName != "" {
+			return config, false, fmt.Errorf("%s: only one of %s is allowed in a directory", dir, strings.Join(PackageConfigFileNames, ", "))
+		}
+		fileName, data = filepath.Join(dir, name), b
+	}
+	if fileName == "" {
+		return config, false, nil
+	}
+	if filepath.Ext(fileName) == ".json" {
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.DisallowUnknownFields()
+		d.UseNumber()
+		err = d.Decode(&config)
+	} else {
+		d := yaml.NewDecoder(bytes.NewReader(data))
+		d.KnownFields(true)
+		if err = d.Decode(&config); errors.Is(err, io.EOF) {
+			// An empty file doesn't set any options.
+			err = nil
+		}
+	}
+	if err != nil {
+		return config, false, fmt.Errorf("%s: invalid package config: %w", fileName, err)
+	}
+	if config.StaticData, err = staticDataValues(config.StaticData); err != nil {
+		return config, false, fmt.Errorf("%s: %w", fileName, err)
+	}
+	if config.FileSuffix != nil {
+		if err = validateFileSuffix(*config.FileSuffix); err != nil {
+			return config, false, fmt.Errorf("%s: %w", fileName, err)
+		}
+	}
+	return config, true, nil
+}
+
+// Apply returns a copy of args with the options that are set in the config.
+func (c PackageConfig) Apply(args Arguments) Arguments {
+	if c.RuntimeImportPath != nil {
+		args.RuntimeImportPath = *c.RuntimeImportPath
+	}
+	if c.Strict != nil {
+		args.Strict = *c.Strict
+	}
+	if c.StrictErrors != nil {
+		args.StrictErrors = *c.StrictErrors
+	}
+	if c.StrictAllow != nil {
+		args.StrictAllow = c.StrictAllow
+	}
+	if c.Minify != nil {
+		args.Minify = *c.Minify
+	}
+	if c.FileSuffix != nil {
+		args.FileSuffix = *c.FileSuffix
+	}
+	if c.StrictNilComponents != nil {
+		args.StrictNilComponents = *c.StrictNilComponents
+	}
+	if c.StrictText != nil {
+		args.StrictText = *c.StrictText
+	}
+	if c.TextTransform != nil {
+		args.TextTransform = *c.TextTransform
+	}
+	if c.URLSanitizer != nil {
+		args.URLSanitizer = *c.URLSanitizer
+	}
+	if c.LiteralConstants != nil {
+		args.LiteralConstants = *c.LiteralConstants
+	}
+	if c.LiteralBytes != nil {
+		args.LiteralBytes = *c.LiteralBytes
+	}
+	if c.StaticData != nil {
+		args.StaticData = c.StaticData
+	}
+	return args
+}
+
+// readStaticData reads the static data of the -static-data flag from a JSON object, see
+// generator.WithStaticData.
+func readStaticData(fileName string) (data map[string]any, err error) {
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static data: %w", err)
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err = d.Decode(&data); err != nil {
+		return nil, fmt.Errorf("%s: invalid static data: %w", fileName, err)
+	}
+	if data, err = staticDataValues(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return data, nil
+}
+
+// staticDataValues returns the data with JSON numbers converted to int or float64 values, as
+// they are decoded from YAML, so that integers are evaluated as integers.
+func staticDataValues(data map[string]any) (map[string]any, error) {
+	for k, v := range data {
+		n, ok := v.(json.Number)
+		if !ok {
+			continue
+		}
+		if i, err := strconv.Atoi(n.String()); err == nil {
+			data[k] = i
+			continue
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid static data %q: %w", k, err)
+		}
+		data[k] = f
+	}
+	return data, nil
+}
+
+func validateFileSuffix(suffix string) error {
+	if !strings.HasSuffix(suffix, ".go") || suffix == ".go" || strings.HasSuffix(suffix, "_test.go") || strings.ContainsAny(suffix, `/\`) {
+		return fmt.Errorf("invalid file suffix %q, expected a suffix that ends in .go, e.g. %s", suffix, DefaultFileSuffix)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package generatecmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadPackageConfig(t *testing.T) {
+	write := func(t *testing.T, files map[string]string) string {
+		dir := t.TempDir()
+		for name, contents := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+				t.Fatalf("failed to write %s: %v", name, err)
+			}
+		}
+		return dir
+	}
+	t.Run("directories without a config file are not configured", func(t *testing.T) {
+		_, ok, err := ReadPackageConfig(write(t, nil))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok {
+			t.Error("expected no config")
+		}
+	})
+	t.Run("JSON and YAML files set the same options", func(t *testing.T) {
+		jsonConfig, ok, err := ReadPackageConfig(write(t, map[string]string{
+			"templ.json": `{"runtimeImportPath": "example.com/templ", "strict": false, "strictAllow": ["hx-*"], "minify": true, "fileSuffix": ".gen.go", "strictNilComponents": true, "strictText": true, "textTransform": true, "urlSanitizer": true, "literalConstants": true, "literalBytes": true, "staticData": {"site.Title": "My blog", "site.Year": 2025}}`,
+		}))
+		if err != nil || !ok {
+			t.Fatalf("failed to read templ.json: %v", err)
+		}
+		yamlConfig, ok, err := ReadPackageConfig(write(t, map[string]string{
+			"templ.yaml": "runtimeImportPath: example.com/templ\nstrict: false\nstrictAllow:\n  - hx-*\nminify: true\nfileSuffix: .gen.go\nstrictNilComponents: true\nstrictText: true\ntextTransform: true\nurlSanitizer: true\nliteralConstants: true\nliteralBytes: true\nstaticData:\n  site.Title: My blog\n  site.Year: 2025\n",
+		}))
+		if err != nil || !ok {
+			t.Fatalf("failed to read templ.yaml: %v", err)
+		}
+		if diff := cmp.Diff(jsonConfig, yamlConfig); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("options that are set override the arguments", func(t *testing.T) {
+		config, _, err := ReadPackageConfig(write(t, map[string]string{
+			"templ.json": `{"strict": false, "minify": true, "strictNilComponents": true, "strictText": true, "textTransform": true, "literalConstants": true, "literalBytes": true}`,
+		}))
+		if err != nil {
+			t.Fatalf("failed to read templ.json: %v", err)
+		}
+		args := config.Apply(Arguments{Strict: true, StrictAllow: []string{"x-data"}, FileSuffix: DefaultFileSuffix})
+		expected := Arguments{Strict: false, StrictAllow: []string{"x-data"}, Minify: true, FileSuffix: DefaultFileSuffix, StrictNilComponents: true, StrictText: true, TextTransform: true, LiteralConstants: true, LiteralBytes: true}
+		if diff := cmp.Diff(expected, args, cmp.Comparer(func(a, b FileWriterFunc) bool { return a == nil && b == nil })); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("empty YAML files are valid", func(t *testing.T) {
+		if _, ok, err := ReadPackageConfig(write(t, map[string]string{"templ.yaml": ""})); err != nil || !ok {
+			t.Errorf("expected an empty config, got %v", err)
+		}
+	})
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name:     "unknown JSON options are an error",
+			files:    map[string]string{"templ.json": `{"minfy": true}`},
+			expected: `unknown field "minfy"`,
+		},
+		{
+			name:     "unknown YAML options are an error",
+			files:    map[string]string{"templ.yaml": "minfy: true\n"},
+			expected: "field minfy not found",
+		},
+		{
+			name:     "more than one config file is an error",
+			files:    map[string]string{"templ.json": "{}", "templ.yaml": ""},
+			expected: "only one of templ.json, templ.yaml, templ.yml is allowed",
+		},
+		{
+			name:     "file suffixes must be Go files",
+			files:    map[string]string{"templ.json": `{"fileSuffix": ".html"}`},
+			expected: `invalid file suffix ".html"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ReadPackageConfig(write(t, tt.files))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
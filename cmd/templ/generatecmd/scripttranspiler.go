@@ -0,0 +1,30 @@
+package generatecmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/a-h/templ/generator"
+)
+
+// commandScriptTranspiler returns a script transpiler that runs the command, e.g.
+// esbuild --loader=ts, with the TypeScript on stdin, and reads the JavaScript from stdout.
+func commandScriptTranspiler(command string) generator.ScriptTranspiler {
+	parts := strings.Fields(command)
+	return func(ts string) (js string, err error) {
+		if len(parts) == 0 {
+			return "", fmt.Errorf("script transpiler command is empty")
+		}
+		cmd := exec.Command(parts[0], parts[1:]...)
+		cmd.Stdin = strings.NewReader(ts)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err = cmd.Run(); err != nil {
+			return "", fmt.Errorf("%s: %w: %s", parts[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return stdout.String(), nil
+	}
+}
@@ -0,0 +1,240 @@
+// Package static renders components that don't have any parameters to HTML files, by
+// generating and running a Go program that imports the packages that contain them.
+package static
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/internal/skipdir"
+	"github.com/a-h/templ/parser/v2"
+	"golang.org/x/mod/modfile"
+)
+
+// Package is a Go package that contains components that can be rendered to static HTML.
+type Package struct {
+	// Dir is the absolute path of the package directory.
+	Dir string
+	// ImportPath of the package.
+	ImportPath string
+	// Components are the names of the exported components that don't have any parameters.
+	Components []string
+}
+
+// Find returns the packages within dir that contain exported components that don't have any
+// parameters. dir must be within a Go module.
+func Find(dir string) (modDir string, pkgs []Package, err error) {
+	if dir, err = filepath.Abs(dir); err != nil {
+		return "", nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	modDir, modPath, err := findModule(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	byDir := map[string]*Package{}
+	var dirs []string
+	err = filepath.WalkDir(dir, func(fileName string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if fileName != dir && skipdir.ShouldSkip(fileName) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(fileName, ".templ") {
+			return nil
+		}
+		names, isMain, err := findComponents(fileName)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 || isMain {
+			return nil
+		}
+		pkgDir := filepath.Dir(fileName)
+		pkg, ok := byDir[pkgDir]
+		if !ok {
+			rel, err := filepath.Rel(modDir, pkgDir)
+			if err != nil {
+				return fmt.Errorf("failed to get package path relative to module: %w", err)
+			}
+			pkg = &Package{Dir: pkgDir, ImportPath: path.Join(modPath, filepath.ToSlash(rel))}
+			byDir[pkgDir] = pkg
+			dirs = append(dirs, pkgDir)
+		}
+		pkg.Components = append(pkg.Components, names...)
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find components: %w", err)
+	}
+	slices.Sort(dirs)
+	for _, d := range dirs {
+		slices.Sort(byDir[d].Components)
+		pkgs = append(pkgs, *byDir[d])
+	}
+	return modDir, pkgs, nil
+}
+
+func findModule(dir string) (modDir, modPath string, err error) {
+	for modDir = dir; ; {
+		data, err := os.ReadFile(filepath.Join(modDir, "go.mod"))
+		if err == nil {
+			modPath = modfile.ModulePath(data)
+			if modPath == "" {
+				return "", "", fmt.Errorf("failed to read module path from %q", filepath.Join(modDir, "go.mod"))
+			}
+			return modDir, modPath, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", "", fmt.Errorf("failed to read go.mod file: %w", err)
+		}
+		parent := filepath.Dir(modDir)
+		if parent == modDir {
+			return "", "", fmt.Errorf("could not find go.mod file for %q", dir)
+		}
+		modDir = parent
+	}
+}
+
+// findComponents returns the names of the exported components in the templ file that don't
+// have any parameters, and whether the file is in a main package, which can't be imported.
+func findComponents(fileName string) (names []string, isMain bool, err error) {
+	tf, err := parser.Parse(fileName)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse %q: %w", fileName, err)
+	}
+	isMain = strings.TrimSpace(strings.TrimPrefix(tf.Package.Expression.Value, "package")) == "main"
+	for _, n := range tf.Nodes {
+		t, ok := n.(*parser.HTMLTemplate)
+		if !ok {
+			continue
+		}
+		if name, ok := parameterlessComponentName(t.Expression.Value); ok {
+			names = append(names, name)
+		}
+	}
+	return names, isMain, nil
+}
+
+func parameterlessComponentName(expr string) (name string, ok bool) {
+	f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\nfunc "+expr+" {}", 0)
+	if err != nil || len(f.Decls) == 0 {
+		return "", false
+	}
+	fn, ok := f.Decls[0].(*ast.FuncDecl)
+	if !ok || fn.Recv != nil || fn.Type.TypeParams != nil || fn.Type.Params.NumFields() > 0 || !fn.Name.IsExported() {
+		return "", false
+	}
+	return fn.Name.Name, true
+}
+
+// Render renders the components of each package to outDir, creating a directory for each
+// package, relative to baseDir, containing a file named after each component, e.g.
+// outDir/pages/Home.html.
+func Render(ctx context.Context, log *slog.Logger, modDir, baseDir, outDir string, pkgs []Package) (err error) {
+	if len(pkgs) == 0 {
+		log.Info("No components without parameters found, skipping static output")
+		return nil
+	}
+	if outDir, err = filepath.Abs(outDir); err != nil {
+		return fmt.Errorf("failed to get absolute path of output directory: %w", err)
+	}
+	program, err := generateProgram(baseDir, outDir, pkgs)
+	if err != nil {
+		return err
+	}
+	// The program must be within the module to import its packages. Directories starting with
+	// an underscore are ignored by ./... patterns, so it doesn't affect other builds.
+	programDir, err := os.MkdirTemp(modDir, "_templ_static_")
+	if err != nil {
+		return fmt.Errorf("failed to create directory for static render program: %w", err)
+	}
+	defer func() {
+		if rmErr := os.RemoveAll(programDir); rmErr != nil {
+			log.Warn("Failed to remove static render program", slog.String("dir", programDir), slog.Any("error", rmErr))
+		}
+	}()
+	if err = os.WriteFile(filepath.Join(programDir, "main.go"), program, 0o644); err != nil {
+		return fmt.Errorf("failed to write static render program: %w", err)
+	}
+
+	log.Debug("Rendering static HTML", slog.String("dir", outDir), slog.Int("packages", len(pkgs)))
+	cmd := exec.CommandContext(ctx, "go", "run", "./"+filepath.Base(programDir))
+	cmd.Dir = modDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to render static HTML: %w\n%s", err, output)
+	}
+	for _, pkg := range pkgs {
+		for _, name := range pkg.Components {
+			log.Debug("Rendered static HTML", slog.String("package", pkg.ImportPath), slog.String("component", name))
+		}
+	}
+	return nil
+}
+
+func generateProgram(baseDir, outDir string, pkgs []Package) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by templ - DO NOT EDIT.\n\n")
+	b.WriteString("package main\n\n")
+	b.WriteString("import (\n\t\"context\"\n\t\"fmt\"\n\t\"io\"\n\t\"os\"\n\t\"path/filepath\"\n\n")
+	for i, pkg := range pkgs {
+		fmt.Fprintf(&b, "\tp%d %s\n", i, strconv.Quote(pkg.ImportPath))
+	}
+	b.WriteString(")\n\n")
+	b.WriteString("type component interface {\n\tRender(ctx context.Context, w io.Writer) error\n}\n\n")
+	b.WriteString("var components = []struct {\n\tfileName string\n\tc        component\n}{\n")
+	for i, pkg := range pkgs {
+		rel, err := filepath.Rel(baseDir, pkg.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get package path relative to %q: %w", baseDir, err)
+		}
+		for _, name := range pkg.Components {
+			fileName := filepath.Join(outDir, rel, name+".html")
+			fmt.Fprintf(&b, "\t{%s, p%d.%s()},\n", strconv.Quote(fileName), i, name)
+		}
+	}
+	b.WriteString("}\n\n")
+	b.WriteString(`func main() {
+	for _, c := range components {
+		if err := render(c.fileName, c.c); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", c.fileName, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func render(fileName string, c component) (err error) {
+	if err = os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return c.Render(context.Background(), f)
+}
+`)
+	return b.Bytes(), nil
+}
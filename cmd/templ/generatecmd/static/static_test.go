@@ -0,0 +1,85 @@
+package static
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFind(t *testing.T) {
+	modDir, pkgs, err := Find("testdata")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedModDir, err := filepath.Abs("../../../..")
+	if err != nil {
+		t.Fatalf("failed to get module directory: %v", err)
+	}
+	if modDir != expectedModDir {
+		t.Errorf("expected module directory %q, got %q", expectedModDir, modDir)
+	}
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatalf("failed to get testdata directory: %v", err)
+	}
+	expected := []Package{
+		{
+			Dir:        filepath.Join(testdata, "pages"),
+			ImportPath: "github.com/a-h/templ/cmd/templ/generatecmd/static/testdata/pages",
+			Components: []string{"Home"},
+		},
+		{
+			Dir:        filepath.Join(testdata, "pages", "blog"),
+			ImportPath: "github.com/a-h/templ/cmd/templ/generatecmd/static/testdata/pages/blog",
+			Components: []string{"Index"},
+		},
+	}
+	if diff := cmp.Diff(expected, pkgs); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRender(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test that runs the go command in short mode")
+	}
+	modDir, pkgs, err := Find("testdata")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatalf("failed to get testdata directory: %v", err)
+	}
+	outDir := t.TempDir()
+	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	if err = Render(context.Background(), log, modDir, testdata, outDir, pkgs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		filepath.Join("pages", "Home.html"):          "<h1>Home</h1><footer>Footer</footer>",
+		filepath.Join("pages", "blog", "Index.html"): "<h1>Blog</h1>",
+	}
+	for fileName, expectedHTML := range expected {
+		actual, err := os.ReadFile(filepath.Join(outDir, fileName))
+		if err != nil {
+			t.Errorf("failed to read %q: %v", fileName, err)
+			continue
+		}
+		if string(actual) != expectedHTML {
+			t.Errorf("%s: expected %q, got %q", fileName, expectedHTML, string(actual))
+		}
+	}
+	matches, err := filepath.Glob(filepath.Join(modDir, "_templ_static_*"))
+	if err != nil {
+		t.Fatalf("failed to glob: %v", err)
+	}
+	if len(matches) > 0 {
+		t.Errorf("expected the render program to be removed, found %v", matches)
+	}
+}
@@ -0,0 +1,128 @@
+package generatecmd
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/lint"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// Summary describes a run of the generate command, e.g. for build dashboards, see
+// Arguments.SummaryHandler. In watch mode, it describes the latest generation of each file.
+type Summary struct {
+	// Files are the templ files that were generated, or failed to generate, ordered by name.
+	// Files that were skipped because they're unchanged aren't included.
+	Files []FileSummary `json:"files"`
+	// Components is the number of templates generated, and ReusedComponents is the number
+	// whose code was reused from the previous generation in watch mode.
+	Components       int `json:"components"`
+	ReusedComponents int `json:"reusedComponents"`
+	// Literals is the number of string literals written.
+	Literals int `json:"literals"`
+	// Bytes is the size of the generated Go code.
+	Bytes int `json:"bytes"`
+	// Warnings is the number of diagnostics that didn't fail generation, and Errors is the
+	// number of files that failed to generate.
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+	// Duration of the run.
+	Duration time.Duration `json:"duration"`
+}
+
+// FileSummary describes the generation of a templ file.
+type FileSummary struct {
+	FileName         string        `json:"fileName"`
+	Components       int           `json:"components"`
+	ReusedComponents int           `json:"reusedComponents"`
+	Literals         int           `json:"literals"`
+	Bytes            int           `json:"bytes"`
+	Duration         time.Duration `json:"duration"`
+	// Warnings are the diagnostics of the parser, linters and -check-classes that didn't fail
+	// generation.
+	Warnings []lint.Diagnostic `json:"warnings,omitempty"`
+	// Error is the reason that the file failed to generate, or "" if it was generated.
+	Error string `json:"error,omitempty"`
+}
+
+// summaryRecorder collects the FileSummary of each templ file as it's generated.
+type summaryRecorder struct {
+	m     sync.Mutex
+	files map[string]*FileSummary
+}
+
+func newSummaryRecorder() *summaryRecorder {
+	return &summaryRecorder{files: map[string]*FileSummary{}}
+}
+
+// start records that the file is being generated, replacing the summary of any previous
+// generation of the file.
+func (r *summaryRecorder) start(fileName string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.files[fileName] = &FileSummary{FileName: fileName}
+}
+
+// update calls f with the summary of the file, if the file is being generated.
+func (r *summaryRecorder) update(fileName string, f func(fs *FileSummary)) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if fs, ok := r.files[fileName]; ok {
+		f(fs)
+	}
+}
+
+func (r *summaryRecorder) setOutput(fileName string, output generator.GeneratorOutput, goCode []byte) {
+	r.update(fileName, func(fs *FileSummary) {
+		fs.Components = len(output.Symbols)
+		fs.ReusedComponents = 0
+		for _, s := range output.Symbols {
+			if s.Reused {
+				fs.ReusedComponents++
+			}
+		}
+		fs.Literals = len(output.Literals)
+		fs.Bytes = len(goCode)
+	})
+}
+
+func (r *summaryRecorder) addWarning(fileName, rule string, d parser.Diagnostic) {
+	r.addWarnings(fileName, []lint.Diagnostic{{Diagnostic: d, Rule: rule, Severity: lint.SeverityWarning}})
+}
+
+func (r *summaryRecorder) addWarnings(fileName string, diags []lint.Diagnostic) {
+	r.update(fileName, func(fs *FileSummary) {
+		for _, d := range diags {
+			if d.Severity != lint.SeverityError {
+				fs.Warnings = append(fs.Warnings, d)
+			}
+		}
+	})
+}
+
+// summary returns the summary of the files generated so far.
+func (r *summaryRecorder) summary(duration time.Duration) (s Summary) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	s.Duration = duration
+	for _, fs := range r.files {
+		f := *fs
+		f.Warnings = slices.Clone(fs.Warnings)
+		s.Files = append(s.Files, f)
+		s.Components += f.Components
+		s.ReusedComponents += f.ReusedComponents
+		s.Literals += f.Literals
+		s.Bytes += f.Bytes
+		s.Warnings += len(f.Warnings)
+		if f.Error != "" {
+			s.Errors++
+		}
+	}
+	slices.SortFunc(s.Files, func(a, b FileSummary) int {
+		return strings.Compare(a.FileName, b.FileName)
+	})
+	return s
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/a-h/templ/cmd/templ/lspcmd/httpdebug"
 	"github.com/a-h/templ/cmd/templ/lspcmd/pls"
 	"github.com/a-h/templ/cmd/templ/lspcmd/proxy"
+	"github.com/a-h/templ/lint"
 	"github.com/a-h/templ/lsp/jsonrpc2"
 	"github.com/a-h/templ/lsp/protocol"
 
@@ -29,6 +30,12 @@ type Arguments struct {
 	HTTPDebug string
 	// NoPreload disables preloading of templ files on server startup (useful for large monorepos)
 	NoPreload bool
+	// LogLevel of the log file, one of "debug", "info", "warn" or "error". Defaults to "info".
+	LogLevel string
+	// Logger to use instead of writing to the Log file.
+	Logger *slog.Logger
+	// Lint is the names of the linters to run over templ files, e.g. "a11y", see lint.New.
+	Lint []string
 }
 
 func Run(stdin io.Reader, stdout, stderr io.Writer, args Arguments) (err error) {
@@ -54,8 +61,19 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args Arguments) (err error)
 		<-signalChan // Second signal, hard exit.
 		os.Exit(2)
 	}()
+	if _, err = lint.New(args.Lint...); err != nil {
+		return err
+	}
 	log := slog.New(slog.NewJSONHandler(io.Discard, nil))
-	if args.Log != "" {
+	var level slog.Level
+	if args.LogLevel != "" {
+		if err = level.UnmarshalText([]byte(args.LogLevel)); err != nil {
+			return fmt.Errorf("invalid log level: %w", err)
+		}
+	}
+	if args.Logger != nil {
+		log = args.Logger
+	} else if args.Log != "" {
 		file, err := os.OpenFile(args.Log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
 		if err != nil {
 			return fmt.Errorf("failed to open log file: %w", err)
@@ -65,7 +83,7 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args Arguments) (err error)
 		}()
 
 		// Create a new logger with a file writer
-		log = slog.New(slog.NewJSONHandler(file, nil))
+		log = slog.New(slog.NewJSONHandler(file, &slog.HandlerOptions{Level: level}))
 		log.Debug("Logging to file", slog.String("file", args.Log))
 	}
 	templStream := jsonrpc2.NewStream(newStdRwc(log, "templStream", stdout, stdin))
@@ -106,6 +124,9 @@ func run(ctx context.Context, log *slog.Logger, templStream jsonrpc2.Stream, arg
 	log.Info("creating proxy")
 	// Create the proxy to sit between.
 	serverProxy := proxy.NewServer(log, goplsServer, cache, diagnosticCache, args.NoPreload)
+	if len(args.Lint) > 0 {
+		serverProxy.Lint, _ = lint.New(args.Lint...)
+	}
 
 	// Create templ server.
 	log.Info("creating templ server")
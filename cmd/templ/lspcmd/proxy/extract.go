@@ -0,0 +1,315 @@
+package proxy
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// extraction is the result of extracting the nodes selected in a template into a new component.
+type extraction struct {
+	// Name of the new component.
+	Name string
+	// Params of the new component, which are the variables declared outside the selection
+	// that are used within it, in the order they're declared.
+	Params []extractedParam
+	// Selection is the range of the nodes that are replaced by a call to the new component.
+	Selection lsp.Range
+	// Body of the new component.
+	Body string
+	// End of the template that contains the selection, where the new component is inserted.
+	End lsp.Position
+}
+
+type extractedParam struct {
+	Name string
+	// Type of the parameter, or "" if it isn't known.
+	Type string
+	// Decl is the position of the declaration of the variable in the generated Go code.
+	Decl lsp.Position
+}
+
+// Edits returns the edits that replace the selection with a call to the new component, and
+// add the component after the template.
+func (e extraction) Edits() []lsp.TextEdit {
+	args := make([]string, len(e.Params))
+	params := make([]string, len(e.Params))
+	for i, p := range e.Params {
+		typ := p.Type
+		if typ == "" {
+			typ = "any"
+		}
+		args[i] = p.Name
+		params[i] = p.Name + " " + typ
+	}
+	return []lsp.TextEdit{
+		{
+			Range:   e.Selection,
+			NewText: fmt.Sprintf("@%s(%s)", e.Name, strings.Join(args, ", ")),
+		},
+		{
+			Range:   lsp.Range{Start: e.End, End: e.End},
+			NewText: fmt.Sprintf("\n\ntempl %s(%s) {\n%s\n}", e.Name, strings.Join(params, ", "), e.Body),
+		},
+	}
+}
+
+// extractComponent returns the extraction of the nodes within the range of the templ file, or
+// ok=false if the range isn't a sequence of complete nodes within the body of a template, or
+// if the nodes declare variables that are used after the selection.
+//
+// The variables that the nodes use are found by type checking the generated Go code. The types
+// of template parameters are written as they're declared, but the types of other variables
+// can only be found if they're declared in the file, e.g. for _, item := range items.
+func extractComponent(templ, goCode string, sourceMap *parser.SourceMap, r lsp.Range) (e extraction, ok bool) {
+	lines := strings.Split(templ, "\n")
+	start, end, ok := trimRange(lines, r)
+	if !ok {
+		return e, false
+	}
+	e.Selection = lsp.Range{Start: start, End: end}
+	selected := textOf(lines, e.Selection)
+
+	// The selection must parse as the body of a template.
+	body, err := parser.ParseString("package p\n\ntempl p() {\n" + selected + "\n}\n")
+	if err != nil || len(body.Nodes) != 1 {
+		return e, false
+	}
+	if bt, isTemplate := body.Nodes[0].(*parser.HTMLTemplate); !isTemplate || !hasContent(bt.Children) {
+		return e, false
+	}
+
+	tf, err := parser.ParseString(templ)
+	if err != nil {
+		return e, false
+	}
+	var t *parser.HTMLTemplate
+	for _, n := range tf.Nodes {
+		if ht, isTemplate := n.(*parser.HTMLTemplate); isTemplate && isAfter(start, toLSPPosition(ht.Expression.Range.To)) && !isAfter(end, toLSPPosition(ht.Range.To)) {
+			t = ht
+			break
+		}
+	}
+	if t == nil {
+		return e, false
+	}
+	e.End = toLSPPosition(t.Range.To)
+	e.Name = newComponentName(tf)
+	e.Body = indentBody(selected, lines[start.Line])
+	if e.Params, ok = freeVariables(goCode, sourceMap, lsp.Range{Start: toLSPPosition(t.Range.From), End: e.End}, e.Selection); !ok {
+		return e, false
+	}
+	return e, true
+}
+
+// trimRange returns the range without the whitespace at its start and end.
+func trimRange(lines []string, r lsp.Range) (start, end lsp.Position, ok bool) {
+	start, end = r.Start, r.End
+	if int(end.Line) >= len(lines) || int(end.Character) > len(lines[end.Line]) || isAfter(start, end) {
+		return start, end, false
+	}
+	isSpace := func(p lsp.Position) bool {
+		line := lines[p.Line]
+		return int(p.Character) >= len(line) || line[p.Character] == ' ' || line[p.Character] == '\t'
+	}
+	for isAfter(end, start) && isSpace(start) {
+		if int(start.Character) >= len(lines[start.Line]) {
+			start = lsp.Position{Line: start.Line + 1}
+			continue
+		}
+		start.Character++
+	}
+	for isAfter(end, start) {
+		if end.Character == 0 {
+			end = lsp.Position{Line: end.Line - 1, Character: uint32(len(lines[end.Line-1]))}
+			continue
+		}
+		prev := lsp.Position{Line: end.Line, Character: end.Character - 1}
+		if !isSpace(prev) {
+			break
+		}
+		end = prev
+	}
+	return start, end, isAfter(end, start)
+}
+
+func textOf(lines []string, r lsp.Range) string {
+	if r.Start.Line == r.End.Line {
+		return lines[r.Start.Line][r.Start.Character:r.End.Character]
+	}
+	selected := []string{lines[r.Start.Line][r.Start.Character:]}
+	selected = append(selected, lines[r.Start.Line+1:r.End.Line]...)
+	selected = append(selected, lines[r.End.Line][:r.End.Character])
+	return strings.Join(selected, "\n")
+}
+
+// indentBody indents the selected text by a tab, after removing the indentation of the line
+// that the selection starts on from the lines after the first.
+func indentBody(selected, firstLine string) string {
+	indent := firstLine[:len(firstLine)-len(strings.TrimLeft(firstLine, " \t"))]
+	lines := strings.Split(selected, "\n")
+	for i, line := range lines {
+		if i > 0 {
+			line = strings.TrimPrefix(line, indent)
+		}
+		if line != "" {
+			lines[i] = "\t" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func hasContent(nodes []parser.Node) bool {
+	return slices.ContainsFunc(nodes, func(n parser.Node) bool {
+		_, isWhitespace := n.(*parser.Whitespace)
+		return !isWhitespace
+	})
+}
+
+// newComponentName returns a name for the new component that isn't used by a template in the
+// file.
+func newComponentName(tf *parser.TemplateFile) string {
+	used := func(name string) bool {
+		re := regexp.MustCompile(`^` + name + `\s*[\[(]`)
+		for _, n := range tf.Nodes {
+			if t, ok := n.(*parser.HTMLTemplate); ok && re.MatchString(t.Expression.Value) {
+				return true
+			}
+		}
+		return false
+	}
+	name := "NewComponent"
+	for i := 2; used(name); i++ {
+		name = fmt.Sprintf("NewComponent%d", i)
+	}
+	return name
+}
+
+// freeVariables returns the variables declared within the template, but outside the selection,
+// that are used within the selection. If a variable declared within the selection is used
+// after it, ok is false.
+func freeVariables(goCode string, sourceMap *parser.SourceMap, template, selection lsp.Range) (params []extractedParam, ok bool) {
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "templ.go", goCode, goparser.SkipObjectResolution)
+	if err != nil {
+		return nil, false
+	}
+	info := &types.Info{
+		Defs: map[*ast.Ident]types.Object{},
+		Uses: map[*ast.Ident]types.Object{},
+	}
+	// Imported packages aren't loaded, so their types are invalid, but the scopes of the
+	// variables of the template are still resolved.
+	conf := types.Config{
+		Importer: importerFunc(func(importPath string) (*types.Package, error) {
+			pkg := types.NewPackage(importPath, path.Base(importPath))
+			pkg.MarkComplete()
+			return pkg, nil
+		}),
+		Error: func(error) {},
+	}
+	pkg, _ := conf.Check("p", fset, []*ast.File{f}, info)
+
+	// templPosition returns the position in the templ file of a position in the Go code.
+	templPosition := func(pos token.Pos) (p lsp.Position, ok bool) {
+		goPos := fset.Position(pos)
+		src, ok := sourceMap.TargetLinesToSource[uint32(goPos.Line-1)][uint32(goPos.Column-1)]
+		return lsp.Position{Line: src.Line, Character: src.Col}, ok
+	}
+	within := func(r lsp.Range, p lsp.Position) bool {
+		return !isAfter(r.Start, p) && isAfter(r.End, p)
+	}
+	seen := map[types.Object]bool{}
+	for ident, obj := range info.Uses {
+		v, isVar := obj.(*types.Var)
+		if !isVar || v.IsField() || v.Parent() == nil || v.Parent() == pkg.Scope() || v.Parent() == types.Universe {
+			continue
+		}
+		use, ok := templPosition(ident.Pos())
+		if !ok || !within(template, use) {
+			continue
+		}
+		decl, ok := templPosition(v.Pos())
+		if !ok || !within(template, decl) {
+			// Variables of the generated code, e.g. ctx, are available in the new component.
+			continue
+		}
+		useInSelection, declInSelection := within(selection, use), within(selection, decl)
+		if !useInSelection && declInSelection {
+			return nil, false
+		}
+		if !useInSelection || declInSelection || seen[v] {
+			continue
+		}
+		seen[v] = true
+		goPos := fset.Position(v.Pos())
+		params = append(params, extractedParam{
+			Name: v.Name(),
+			Type: typeOf(fset, f, goCode, pkg, v),
+			Decl: lsp.Position{Line: uint32(goPos.Line - 1), Character: uint32(goPos.Column - 1)},
+		})
+	}
+	slices.SortFunc(params, func(a, b extractedParam) int {
+		if a.Decl.Line != b.Decl.Line {
+			return int(a.Decl.Line) - int(b.Decl.Line)
+		}
+		return int(a.Decl.Character) - int(b.Decl.Character)
+	})
+	return params, true
+}
+
+// typeOf returns the type of the variable as it's written in the parameters of a function, or
+// as found by type checking, or "" if it isn't known.
+func typeOf(fset *token.FileSet, f *ast.File, goCode string, pkg *types.Package, v *types.Var) string {
+	var declared string
+	ast.Inspect(f, func(n ast.Node) bool {
+		field, ok := n.(*ast.Field)
+		if !ok || declared != "" {
+			return declared == ""
+		}
+		for _, name := range field.Names {
+			if name.Pos() == v.Pos() {
+				declared = goCode[fset.Position(field.Type.Pos()).Offset:fset.Position(field.Type.End()).Offset]
+			}
+		}
+		return true
+	})
+	if declared != "" {
+		return declared
+	}
+	typ := types.TypeString(v.Type(), func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	})
+	if strings.Contains(typ, "invalid type") {
+		return ""
+	}
+	return typ
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// hoverType returns the type of a variable from the hover text of gopls, e.g. "var x int".
+var hoverType = regexp.MustCompile("(?m)^(?:var|field) \\S+ (.+)$")
+
+func toLSPPosition(p parser.Position) lsp.Position {
+	return lsp.Position{Line: p.Line, Character: p.Col}
+}
+
+// isAfter returns true if a is after b.
+func isAfter(a, b lsp.Position) bool {
+	return a.Line > b.Line || (a.Line == b.Line && a.Character > b.Character)
+}
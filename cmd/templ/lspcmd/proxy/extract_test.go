@@ -0,0 +1,108 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExtractComponent(t *testing.T) {
+	templ := `package main
+
+templ list(title string, items []Item) {
+	<h1>{ title }</h1>
+	<ul>
+		for _, item := range items {
+			<li class="item">
+				<span>{ item.Name }</span>
+			</li>
+		}
+	</ul>
+	{{ count := len(items) }}
+	<p>{ count }</p>
+}
+
+type Item struct {
+	Name string
+}
+`
+	tf, err := parser.ParseString(templ)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(strings.Builder)
+	op, err := generator.Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	selection := func(startLine, startCol, endLine, endCol uint32) lsp.Range {
+		return lsp.Range{
+			Start: lsp.Position{Line: startLine, Character: startCol},
+			End:   lsp.Position{Line: endLine, Character: endCol},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		selection lsp.Range
+		expected  []lsp.TextEdit
+	}{
+		{
+			name:      "template parameters are passed to the new component",
+			selection: selection(3, 0, 4, 0),
+			expected: []lsp.TextEdit{
+				{Range: selection(3, 1, 3, 19), NewText: "@NewComponent(title)"},
+				{Range: selection(13, 1, 13, 1), NewText: "\n\ntempl NewComponent(title string) {\n\t<h1>{ title }</h1>\n}"},
+			},
+		},
+		{
+			name:      "variables of loops are passed to the new component",
+			selection: selection(6, 3, 8, 8),
+			expected: []lsp.TextEdit{
+				{Range: selection(6, 3, 8, 8), NewText: "@NewComponent(item)"},
+				{Range: selection(13, 1, 13, 1), NewText: "\n\ntempl NewComponent(item Item) {\n\t<li class=\"item\">\n\t\t<span>{ item.Name }</span>\n\t</li>\n}"},
+			},
+		},
+		{
+			name:      "nested nodes are extracted",
+			selection: selection(7, 4, 7, 30),
+			expected: []lsp.TextEdit{
+				{Range: selection(7, 4, 7, 30), NewText: "@NewComponent(item)"},
+				{Range: selection(13, 1, 13, 1), NewText: "\n\ntempl NewComponent(item Item) {\n\t<span>{ item.Name }</span>\n}"},
+			},
+		},
+		{
+			name:      "partial nodes aren't extracted",
+			selection: selection(6, 3, 7, 10),
+		},
+		{
+			name:      "the declaration of the template isn't extracted",
+			selection: selection(2, 0, 3, 19),
+		},
+		{
+			name:      "variables used after the selection aren't extracted",
+			selection: selection(11, 1, 11, 26),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := extractComponent(templ, w.String(), op.SourceMap, tt.selection)
+			if !ok {
+				if tt.expected != nil {
+					t.Fatal("expected the selection to be extracted")
+				}
+				return
+			}
+			if tt.expected == nil {
+				t.Fatalf("expected the selection not to be extracted, got %+v", e.Edits())
+			}
+			if diff := cmp.Diff(tt.expected, e.Edits()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
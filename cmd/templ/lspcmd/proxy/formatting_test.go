@@ -0,0 +1,134 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRangeFormatting(t *testing.T) {
+	templ := `package main
+
+templ a() {
+<div>a</div>
+}
+
+templ b() {
+<div>
+<p>b</p>
+</div>
+}
+`
+	tests := []struct {
+		name     string
+		format   func(p *Server, uri lsp.DocumentURI) ([]lsp.TextEdit, error)
+		expected []lsp.TextEdit
+	}{
+		{
+			name: "range formatting formats the lines in the range",
+			format: func(p *Server, uri lsp.DocumentURI) ([]lsp.TextEdit, error) {
+				return p.RangeFormatting(context.Background(), &lsp.DocumentRangeFormattingParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 7},
+						End:   lsp.Position{Line: 9},
+					},
+				})
+			},
+			expected: []lsp.TextEdit{
+				{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 7},
+						End:   lsp.Position{Line: 9},
+					},
+					NewText: "\t<div>\n\t\t<p>b</p>\n",
+				},
+			},
+		},
+		{
+			name: "typing a closing brace formats the block",
+			format: func(p *Server, uri lsp.DocumentURI) ([]lsp.TextEdit, error) {
+				return p.OnTypeFormatting(context.Background(), &lsp.DocumentOnTypeFormattingParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+					Position:     lsp.Position{Line: 4, Character: 1},
+					Ch:           "}",
+				})
+			},
+			expected: []lsp.TextEdit{
+				{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 3},
+						End:   lsp.Position{Line: 4},
+					},
+					NewText: "\t<div>a</div>\n",
+				},
+			},
+		},
+		{
+			name: "typing the > of an end tag formats the element",
+			format: func(p *Server, uri lsp.DocumentURI) ([]lsp.TextEdit, error) {
+				return p.OnTypeFormatting(context.Background(), &lsp.DocumentOnTypeFormattingParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+					Position:     lsp.Position{Line: 9, Character: 6},
+					Ch:           ">",
+				})
+			},
+			expected: []lsp.TextEdit{
+				{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 7},
+						End:   lsp.Position{Line: 10},
+					},
+					NewText: "\t<div>\n\t\t<p>b</p>\n\t</div>\n",
+				},
+			},
+		},
+		{
+			name: "formatted declarations are unchanged",
+			format: func(p *Server, uri lsp.DocumentURI) ([]lsp.TextEdit, error) {
+				return p.OnTypeFormatting(context.Background(), &lsp.DocumentOnTypeFormattingParams{
+					TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+					Position:     lsp.Position{Line: 0, Character: 12},
+					Ch:           ">",
+				})
+			},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+			p := NewServer(log, nil, NewSourceMapCache(), nil, false)
+			uri := lsp.DocumentURI("untitled:test.templ")
+			p.TemplSource.Set(string(uri), NewDocument(log, templ))
+			actual, err := tt.format(p, uri)
+			if err != nil {
+				t.Fatalf("failed to format: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("documents that can't be parsed aren't formatted", func(t *testing.T) {
+		log := slog.New(slog.NewTextHandler(io.Discard, nil))
+		p := NewServer(log, nil, NewSourceMapCache(), nil, false)
+		uri := lsp.DocumentURI("untitled:test.templ")
+		p.TemplSource.Set(string(uri), NewDocument(log, "package main\n\ntempl a() {\n<div>\n}\n"))
+		actual, err := p.OnTypeFormatting(context.Background(), &lsp.DocumentOnTypeFormattingParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+			Position:     lsp.Position{Line: 3, Character: 5},
+			Ch:           ">",
+		})
+		if err != nil {
+			t.Fatalf("failed to format: %v", err)
+		}
+		if actual != nil {
+			t.Errorf("expected no edits, got %v", actual)
+		}
+	})
+}
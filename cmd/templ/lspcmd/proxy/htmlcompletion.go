@@ -0,0 +1,268 @@
+package proxy
+
+import (
+	"slices"
+	"strings"
+
+	lsp "github.com/a-h/templ/lsp/protocol"
+)
+
+type htmlCompletionKind int
+
+const (
+	htmlCompletionElement htmlCompletionKind = iota
+	htmlCompletionAttribute
+	htmlCompletionValue
+)
+
+// htmlCompletionContext is the HTML at the position that completion was requested at.
+type htmlCompletionContext struct {
+	Kind htmlCompletionKind
+	// Element is the name of the element, for attributes and values.
+	Element string
+	// Attribute is the name of the attribute, for values.
+	Attribute string
+	// Attributes are the names of the attributes of the element that are already present.
+	Attributes []string
+	// Prefix is the text that has been typed, which is replaced by the completion.
+	Prefix string
+}
+
+// maxTagLines is the number of lines searched for the start of the tag that the position is in.
+const maxTagLines = 20
+
+// getHTMLCompletionContext returns the HTML context at the position, or ok=false if the position
+// isn't within the name of an element, the name of an attribute, or a quoted attribute value.
+//
+// The templ file is likely to be invalid while it's being edited, so the text before the
+// position is scanned, instead of using the parser.
+func getHTMLCompletionContext(lines []string, pos lsp.Position) (c htmlCompletionContext, ok bool) {
+	if int(pos.Line) >= len(lines) || int(pos.Character) > len(lines[pos.Line]) {
+		return c, false
+	}
+	from := max(int(pos.Line)-maxTagLines, 0)
+	text := strings.Join(append(slices.Clone(lines[from:pos.Line]), lines[pos.Line][:pos.Character]), "\n")
+
+	// Find the start of the tag, ignoring less than signs that aren't followed by a tag name.
+	start := -1
+	for i := len(text) - 1; i >= 0 && start < 0; i-- {
+		if text[i] == '<' && (i == len(text)-1 || isTagNameStart(text[i+1])) {
+			start = i
+		}
+	}
+	if start < 0 {
+		return c, false
+	}
+	s := htmlScanner{text: text, i: start + 1}
+	c.Element = s.read(isTagNameChar)
+	if s.atEnd() {
+		return htmlCompletionContext{Kind: htmlCompletionElement, Prefix: c.Element}, true
+	}
+	for {
+		if s.read(isSpace) != "" && s.atEnd() {
+			c.Kind = htmlCompletionAttribute
+			return c, true
+		}
+		if s.atEnd() {
+			return c, false
+		}
+		switch s.peek() {
+		case '>', '/':
+			// The tag has been closed.
+			return c, false
+		case '{':
+			// Spread attributes, or an expression within a conditional attribute.
+			if !s.skipBraces() {
+				return c, false
+			}
+			continue
+		}
+		name := s.read(isAttributeNameChar)
+		if name == "" {
+			return c, false
+		}
+		if s.atEnd() {
+			c.Kind = htmlCompletionAttribute
+			c.Prefix = name
+			return c, true
+		}
+		c.Attributes = append(c.Attributes, name)
+		if s.peek() != '=' {
+			continue
+		}
+		s.i++
+		if s.atEnd() {
+			return c, false
+		}
+		switch q := s.peek(); q {
+		case '"', '\'':
+			s.i++
+			value := s.read(func(b byte) bool { return b != q })
+			if s.atEnd() {
+				c.Kind = htmlCompletionValue
+				c.Attribute = name
+				c.Prefix = value
+				return c, true
+			}
+			s.i++
+		case '{':
+			if !s.skipBraces() {
+				return c, false
+			}
+		default:
+			s.read(func(b byte) bool { return !isSpace(b) && b != '>' })
+		}
+	}
+}
+
+type htmlScanner struct {
+	text string
+	i    int
+}
+
+func (s *htmlScanner) atEnd() bool {
+	return s.i >= len(s.text)
+}
+
+func (s *htmlScanner) peek() byte {
+	return s.text[s.i]
+}
+
+func (s *htmlScanner) read(f func(b byte) bool) string {
+	from := s.i
+	for !s.atEnd() && f(s.peek()) {
+		s.i++
+	}
+	return s.text[from:s.i]
+}
+
+// skipBraces skips a Go expression within braces, returning false if the expression doesn't end
+// before the end of the text.
+func (s *htmlScanner) skipBraces() bool {
+	var depth int
+	var quote byte
+	for ; !s.atEnd(); s.i++ {
+		b := s.peek()
+		switch {
+		case quote != 0:
+			if b == '\\' && quote != '`' {
+				s.i++
+			} else if b == quote {
+				quote = 0
+			}
+		case b == '"' || b == '\'' || b == '`':
+			quote = b
+		case b == '{':
+			depth++
+		case b == '}':
+			depth--
+			if depth == 0 {
+				s.i++
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isTagNameStart(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isTagNameChar(b byte) bool {
+	return isTagNameStart(b) || (b >= '0' && b <= '9') || b == '-'
+}
+
+func isAttributeNameChar(b byte) bool {
+	return !isSpace(b) && !strings.ContainsRune(`"'>/={}`, rune(b))
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// htmlCompletion returns the HTML element names, attributes, or attribute values that are
+// valid at the position, or nil if the position isn't within HTML.
+func htmlCompletion(lines []string, pos lsp.Position) (items []lsp.CompletionItem) {
+	c, ok := getHTMLCompletionContext(lines, pos)
+	if !ok {
+		return nil
+	}
+	// Replace the text that has been typed, since editors don't agree on whether characters
+	// such as "-" are part of the word being completed.
+	r := lsp.Range{
+		Start: lsp.Position{Line: pos.Line, Character: pos.Character - uint32(len(c.Prefix))},
+		End:   pos,
+	}
+	item := func(label, newText string, kind lsp.CompletionItemKind, documentation string) lsp.CompletionItem {
+		item := lsp.CompletionItem{
+			Label:    label,
+			Kind:     kind,
+			TextEdit: &lsp.TextEditOrInsertReplaceEdit{TextEdit: &lsp.TextEdit{Range: r, NewText: newText}},
+		}
+		if documentation != "" {
+			item.Documentation = documentation
+		}
+		if newText != label {
+			item.InsertTextFormat = lsp.InsertTextFormatSnippet
+		}
+		return item
+	}
+	switch c.Kind {
+	case htmlCompletionElement:
+		for _, e := range htmlElements {
+			if strings.HasPrefix(e.Name, c.Prefix) {
+				items = append(items, item(e.Name, e.Name, lsp.CompletionItemKindProperty, e.Description))
+			}
+		}
+	case htmlCompletionAttribute:
+		attributes, ok := attributesOf(c.Element)
+		if !ok {
+			return nil
+		}
+		for _, a := range attributes {
+			if !strings.HasPrefix(a.Name, c.Prefix) || slices.Contains(c.Attributes, a.Name) {
+				continue
+			}
+			newText := a.Name
+			if !a.Boolean {
+				newText += `="$1"`
+			}
+			items = append(items, item(a.Name, newText, lsp.CompletionItemKindValue, a.Description))
+		}
+	case htmlCompletionValue:
+		attributes, ok := attributesOf(c.Element)
+		if !ok {
+			return nil
+		}
+		for _, a := range attributes {
+			if a.Name != c.Attribute {
+				continue
+			}
+			for _, v := range a.Values {
+				if strings.HasPrefix(v, c.Prefix) {
+					items = append(items, item(v, v, lsp.CompletionItemKindEnumMember, ""))
+				}
+			}
+			break
+		}
+	}
+	return items
+}
+
+// attributesOf returns the attributes of the element, followed by the global and ARIA
+// attributes. Custom elements have the global and ARIA attributes. Unknown elements, which
+// may be the result of scanning Go code such as a<count, have no attributes.
+func attributesOf(element string) (attributes []htmlAttribute, ok bool) {
+	element = strings.ToLower(element)
+	i := slices.IndexFunc(htmlElements, func(e htmlElement) bool { return e.Name == element })
+	if i < 0 && !strings.Contains(element, "-") {
+		return nil, false
+	}
+	if i >= 0 {
+		attributes = append(attributes, htmlElements[i].Attributes...)
+	}
+	attributes = append(attributes, globalAttributes...)
+	attributes = append(attributes, ariaAttributes...)
+	return attributes, true
+}
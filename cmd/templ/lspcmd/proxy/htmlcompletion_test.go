@@ -0,0 +1,227 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/google/go-cmp/cmp"
+)
+
+// linesAndPosition returns the lines of the input, and the position of the "|" within it.
+func linesAndPosition(t *testing.T, input string) ([]string, lsp.Position) {
+	t.Helper()
+	lines := strings.Split(input, "\n")
+	for i, line := range lines {
+		if col := strings.Index(line, "|"); col >= 0 {
+			lines[i] = line[:col] + line[col+1:]
+			return lines, lsp.Position{Line: uint32(i), Character: uint32(col)}
+		}
+	}
+	t.Fatalf("no position in %q", input)
+	return nil, lsp.Position{}
+}
+
+func TestGetHTMLCompletionContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected htmlCompletionContext
+		ok       bool
+	}{
+		{
+			name:     "element names are completed after a less than sign",
+			input:    "templ a() {\n\t<|",
+			expected: htmlCompletionContext{Kind: htmlCompletionElement},
+			ok:       true,
+		},
+		{
+			name:     "partial element names are completed",
+			input:    "templ a() {\n\t<div>\n\t\t<butt|",
+			expected: htmlCompletionContext{Kind: htmlCompletionElement, Prefix: "butt"},
+			ok:       true,
+		},
+		{
+			name:     "attributes are completed after the element name",
+			input:    `<input |`,
+			expected: htmlCompletionContext{Kind: htmlCompletionAttribute, Element: "input"},
+			ok:       true,
+		},
+		{
+			name:  "partial attribute names are completed, excluding attributes that are present",
+			input: "<input\n\ttype=\"text\"\n\tdisabled\n\tvalue={ value }\n\taria-la|",
+			expected: htmlCompletionContext{
+				Kind:       htmlCompletionAttribute,
+				Element:    "input",
+				Attributes: []string{"type", "disabled", "value"},
+				Prefix:     "aria-la",
+			},
+			ok: true,
+		},
+		{
+			name:  "attributes are completed after spread attributes",
+			input: `<div { attrs... } |`,
+			expected: htmlCompletionContext{
+				Kind:    htmlCompletionAttribute,
+				Element: "div",
+			},
+			ok: true,
+		},
+		{
+			name:  "attribute values are completed within quotes",
+			input: `<button class="a" type="sub|`,
+			expected: htmlCompletionContext{
+				Kind:       htmlCompletionValue,
+				Element:    "button",
+				Attribute:  "type",
+				Attributes: []string{"class", "type"},
+				Prefix:     "sub",
+			},
+			ok: true,
+		},
+		{
+			name:  "attribute values are completed within single quotes",
+			input: `<div role='|`,
+			expected: htmlCompletionContext{
+				Kind:       htmlCompletionValue,
+				Element:    "div",
+				Attribute:  "role",
+				Attributes: []string{"role"},
+			},
+			ok: true,
+		},
+		{
+			name:  "Go expressions in attribute values are not completed",
+			input: `<div class={ "a|`,
+		},
+		{
+			name:  "Go expressions in spread attributes are not completed",
+			input: `<div { attr|`,
+		},
+		{
+			name:  "element content is not completed",
+			input: `<div class="a">text |`,
+		},
+		{
+			name:  "closing tags are not completed",
+			input: `<div></d|`,
+		},
+		{
+			name:  "less than comparisons are not completed",
+			input: `if a < |`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, pos := linesAndPosition(t, tt.input)
+			actual, ok := getHTMLCompletionContext(lines, pos)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestHTMLCompletion(t *testing.T) {
+	labels := func(items []lsp.CompletionItem) (labels []string) {
+		for _, item := range items {
+			labels = append(labels, item.Label)
+		}
+		return labels
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "elements",
+			input:    `<tex|`,
+			expected: []string{"textarea"},
+		},
+		{
+			name:     "element specific attributes",
+			input:    `<a hr|`,
+			expected: []string{"href", "hreflang"},
+		},
+		{
+			name:     "global attributes",
+			input:    `<span cl|`,
+			expected: []string{"class"},
+		},
+		{
+			name:     "ARIA attributes, excluding attributes that are present",
+			input:    `<div aria-label="a" aria-l|`,
+			expected: []string{"aria-labelledby", "aria-level", "aria-live"},
+		},
+		{
+			name:     "custom elements have global attributes",
+			input:    `<my-element hidd|`,
+			expected: []string{"hidden"},
+		},
+		{
+			name:     "unknown elements have no attributes",
+			input:    `if a<count |`,
+			expected: nil,
+		},
+		{
+			name:     "enumerated attribute values",
+			input:    `<input type="ch|`,
+			expected: []string{"checkbox"},
+		},
+		{
+			name:     "ARIA roles",
+			input:    `<div role="tab|`,
+			expected: []string{"tab", "table", "tablist", "tabpanel"},
+		},
+		{
+			name:     "ARIA attribute values",
+			input:    `<div aria-live="|`,
+			expected: []string{"off", "polite", "assertive"},
+		},
+		{
+			name:     "attributes without enumerated values",
+			input:    `<a href="|`,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, pos := linesAndPosition(t, tt.input)
+			actual := labels(htmlCompletion(lines, pos))
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("completions replace the text that has been typed", func(t *testing.T) {
+		lines, pos := linesAndPosition(t, "<div>\n\t<p aria-hid|")
+		items := htmlCompletion(lines, pos)
+		expected := []lsp.CompletionItem{
+			{
+				Label:         "aria-hidden",
+				Kind:          lsp.CompletionItemKindValue,
+				Documentation: "The element isn't exposed to assistive technologies.",
+				TextEdit: &lsp.TextEditOrInsertReplaceEdit{
+					TextEdit: &lsp.TextEdit{
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 1, Character: 4},
+							End:   lsp.Position{Line: 1, Character: 12},
+						},
+						NewText: `aria-hidden="$1"`,
+					},
+				},
+				InsertTextFormat: lsp.InsertTextFormatSnippet,
+			},
+		}
+		if diff := cmp.Diff(expected, items); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,267 @@
+package proxy
+
+// htmlElement describes an HTML element for completion.
+type htmlElement struct {
+	Name        string
+	Description string
+	// Attributes are the attributes specific to the element, in addition to the global
+	// attributes.
+	Attributes []htmlAttribute
+}
+
+// htmlAttribute describes an HTML, or ARIA, attribute for completion.
+type htmlAttribute struct {
+	Name        string
+	Description string
+	// Values are the values of enumerated attributes.
+	Values []string
+	// Boolean attributes don't have a value.
+	Boolean bool
+}
+
+var boolValues = []string{"true", "false"}
+
+var (
+	hrefAttribute     = htmlAttribute{Name: "href", Description: "The URL of the linked resource."}
+	srcAttribute      = htmlAttribute{Name: "src", Description: "The URL of the embedded resource."}
+	altAttribute      = htmlAttribute{Name: "alt", Description: "Alternative text, shown when the image can't be displayed."}
+	nameAttribute     = htmlAttribute{Name: "name", Description: "The name of the control, submitted with the form data."}
+	valueAttribute    = htmlAttribute{Name: "value", Description: "The value of the element."}
+	disabledAttribute = htmlAttribute{Name: "disabled", Description: "Prevents the user from interacting with the element.", Boolean: true}
+	formAttribute     = htmlAttribute{Name: "form", Description: "The id of the form that the element belongs to."}
+	requiredAttribute = htmlAttribute{Name: "required", Description: "A value is required for the form to be submitted.", Boolean: true}
+	autofocusAttr     = htmlAttribute{Name: "autofocus", Description: "Focuses the element when the page loads.", Boolean: true}
+	widthAttribute    = htmlAttribute{Name: "width", Description: "The width of the element, in pixels."}
+	heightAttribute   = htmlAttribute{Name: "height", Description: "The height of the element, in pixels."}
+	typeAttribute     = htmlAttribute{Name: "type", Description: "The MIME type of the resource."}
+	loadingAttribute  = htmlAttribute{Name: "loading", Description: "When the browser loads the resource.", Values: []string{"eager", "lazy"}}
+	referrerAttribute = htmlAttribute{Name: "referrerpolicy", Description: "The referrer sent when fetching the resource.", Values: []string{"no-referrer", "no-referrer-when-downgrade", "origin", "origin-when-cross-origin", "same-origin", "strict-origin", "strict-origin-when-cross-origin", "unsafe-url"}}
+	crossoriginAttr   = htmlAttribute{Name: "crossorigin", Description: "How the element handles cross-origin requests.", Values: []string{"anonymous", "use-credentials"}}
+	autocompleteAttr  = htmlAttribute{Name: "autocomplete", Description: "Whether, and how, the browser fills in the value.", Values: []string{"on", "off", "name", "email", "username", "new-password", "current-password", "one-time-code", "organization", "street-address", "country", "postal-code", "tel", "url", "bday", "cc-name", "cc-number", "cc-exp"}}
+	placeholderAttr   = htmlAttribute{Name: "placeholder", Description: "A hint shown when the control has no value."}
+	readonlyAttribute = htmlAttribute{Name: "readonly", Description: "The value can't be edited.", Boolean: true}
+	targetAttribute   = htmlAttribute{Name: "target", Description: "Where to display the linked URL.", Values: []string{"_self", "_blank", "_parent", "_top"}}
+	relAttribute      = htmlAttribute{Name: "rel", Description: "The relationship of the linked resource to the document.", Values: []string{"alternate", "author", "bookmark", "canonical", "external", "help", "icon", "license", "manifest", "modulepreload", "next", "nofollow", "noopener", "noreferrer", "preconnect", "prefetch", "preload", "prev", "search", "stylesheet", "tag"}}
+	mediaAttribute    = htmlAttribute{Name: "media", Description: "The media query that the resource applies to."}
+	srcsetAttribute   = htmlAttribute{Name: "srcset", Description: "Image candidates for different display densities or widths."}
+	sizesAttribute    = htmlAttribute{Name: "sizes", Description: "The width of the image for media conditions."}
+	colspanAttribute  = htmlAttribute{Name: "colspan", Description: "The number of columns that the cell spans."}
+	rowspanAttribute  = htmlAttribute{Name: "rowspan", Description: "The number of rows that the cell spans."}
+	mediaControlAttrs = []htmlAttribute{
+		srcAttribute,
+		{Name: "controls", Description: "Shows the browser's playback controls.", Boolean: true},
+		{Name: "autoplay", Description: "Starts playback as soon as possible.", Boolean: true},
+		{Name: "loop", Description: "Restarts playback at the end.", Boolean: true},
+		{Name: "muted", Description: "Mutes the audio by default.", Boolean: true},
+		{Name: "preload", Description: "What to load before playback starts.", Values: []string{"none", "metadata", "auto"}},
+		crossoriginAttr,
+	}
+)
+
+// globalAttributes can be used on all elements.
+var globalAttributes = []htmlAttribute{
+	{Name: "accesskey", Description: "A keyboard shortcut that focuses or activates the element."},
+	{Name: "autocapitalize", Description: "How text input is capitalized.", Values: []string{"off", "none", "on", "sentences", "words", "characters"}},
+	autofocusAttr,
+	{Name: "class", Description: "A space separated list of the classes of the element."},
+	{Name: "contenteditable", Description: "Whether the content can be edited by the user.", Values: []string{"true", "false", "plaintext-only"}},
+	{Name: "dir", Description: "The direction of the text.", Values: []string{"ltr", "rtl", "auto"}},
+	{Name: "draggable", Description: "Whether the element can be dragged.", Values: boolValues},
+	{Name: "enterkeyhint", Description: "The label of the enter key on virtual keyboards.", Values: []string{"enter", "done", "go", "next", "previous", "search", "send"}},
+	{Name: "hidden", Description: "The element isn't relevant, so it isn't rendered.", Values: []string{"hidden", "until-found"}},
+	{Name: "id", Description: "An identifier that's unique in the document."},
+	{Name: "inert", Description: "Prevents the user from interacting with the element and its children.", Boolean: true},
+	{Name: "inputmode", Description: "The kind of virtual keyboard to show.", Values: []string{"none", "text", "decimal", "numeric", "tel", "search", "email", "url"}},
+	{Name: "is", Description: "The name of the customized built-in element."},
+	{Name: "lang", Description: "The language of the element's content."},
+	{Name: "nonce", Description: "A cryptographic nonce used by Content Security Policy."},
+	{Name: "part", Description: "The part names of the element, for styling from outside a shadow tree."},
+	{Name: "popover", Description: "Makes the element a popover.", Values: []string{"auto", "manual", "hint"}},
+	{Name: "role", Description: "The ARIA role of the element.", Values: ariaRoles},
+	{Name: "slot", Description: "The slot of a shadow tree that the element is assigned to."},
+	{Name: "spellcheck", Description: "Whether the element is checked for spelling errors.", Values: boolValues},
+	{Name: "style", Description: "CSS declarations applied to the element."},
+	{Name: "tabindex", Description: "Whether, and in which order, the element can be focused."},
+	{Name: "title", Description: "Advisory information about the element, e.g. shown as a tooltip."},
+	{Name: "translate", Description: "Whether the content is translated.", Values: []string{"yes", "no"}},
+}
+
+// ariaRoles are the values of the role attribute.
+var ariaRoles = []string{
+	"alert", "alertdialog", "application", "article", "banner", "button", "cell", "checkbox",
+	"columnheader", "combobox", "complementary", "contentinfo", "definition", "dialog",
+	"document", "feed", "figure", "form", "grid", "gridcell", "group", "heading", "img", "link",
+	"list", "listbox", "listitem", "log", "main", "marquee", "math", "menu", "menubar",
+	"menuitem", "menuitemcheckbox", "menuitemradio", "meter", "navigation", "none", "note",
+	"option", "presentation", "progressbar", "radio", "radiogroup", "region", "row", "rowgroup",
+	"rowheader", "scrollbar", "search", "searchbox", "separator", "slider", "spinbutton",
+	"status", "switch", "tab", "table", "tablist", "tabpanel", "term", "textbox", "timer",
+	"toolbar", "tooltip", "tree", "treegrid", "treeitem",
+}
+
+// ariaAttributes are the ARIA states and properties, which can be used on all elements.
+var ariaAttributes = []htmlAttribute{
+	{Name: "aria-activedescendant", Description: "The id of the active descendant of a composite widget."},
+	{Name: "aria-atomic", Description: "Whether assistive technologies present the whole region when it changes.", Values: boolValues},
+	{Name: "aria-autocomplete", Description: "How input suggestions are shown.", Values: []string{"none", "inline", "list", "both"}},
+	{Name: "aria-busy", Description: "The element is being updated.", Values: boolValues},
+	{Name: "aria-checked", Description: "The checked state of checkboxes, radio buttons and other widgets.", Values: []string{"true", "false", "mixed"}},
+	{Name: "aria-colcount", Description: "The number of columns in a table or grid."},
+	{Name: "aria-colindex", Description: "The column index of the element in a table or grid."},
+	{Name: "aria-colspan", Description: "The number of columns spanned by a cell."},
+	{Name: "aria-controls", Description: "The ids of the elements that the element controls."},
+	{Name: "aria-current", Description: "The element is the current item within a set.", Values: []string{"page", "step", "location", "date", "time", "true", "false"}},
+	{Name: "aria-describedby", Description: "The ids of the elements that describe the element."},
+	{Name: "aria-description", Description: "A description of the element."},
+	{Name: "aria-details", Description: "The ids of the elements that provide details about the element."},
+	{Name: "aria-disabled", Description: "The element is perceivable, but disabled.", Values: boolValues},
+	{Name: "aria-errormessage", Description: "The id of the element that provides an error message."},
+	{Name: "aria-expanded", Description: "Whether the element, or the element it controls, is expanded.", Values: boolValues},
+	{Name: "aria-flowto", Description: "The ids of the next elements in an alternate reading order."},
+	{Name: "aria-haspopup", Description: "The type of popup that the element triggers.", Values: []string{"false", "true", "menu", "listbox", "tree", "grid", "dialog"}},
+	{Name: "aria-hidden", Description: "The element isn't exposed to assistive technologies.", Values: boolValues},
+	{Name: "aria-invalid", Description: "The value of the element isn't valid.", Values: []string{"false", "true", "grammar", "spelling"}},
+	{Name: "aria-keyshortcuts", Description: "The keyboard shortcuts that activate or focus the element."},
+	{Name: "aria-label", Description: "A label for the element."},
+	{Name: "aria-labelledby", Description: "The ids of the elements that label the element."},
+	{Name: "aria-level", Description: "The hierarchical level of the element."},
+	{Name: "aria-live", Description: "How updates to the region are announced.", Values: []string{"off", "polite", "assertive"}},
+	{Name: "aria-modal", Description: "The element is modal when displayed.", Values: boolValues},
+	{Name: "aria-multiline", Description: "A textbox accepts multiple lines of input.", Values: boolValues},
+	{Name: "aria-multiselectable", Description: "More than one item can be selected.", Values: boolValues},
+	{Name: "aria-orientation", Description: "The orientation of the element.", Values: []string{"horizontal", "vertical", "undefined"}},
+	{Name: "aria-owns", Description: "The ids of elements that are children of the element, but not in the DOM."},
+	{Name: "aria-placeholder", Description: "A hint shown when the control has no value."},
+	{Name: "aria-posinset", Description: "The position of the element in a set of items."},
+	{Name: "aria-pressed", Description: "The pressed state of toggle buttons.", Values: []string{"true", "false", "mixed"}},
+	{Name: "aria-readonly", Description: "The element isn't editable, but is otherwise operable.", Values: boolValues},
+	{Name: "aria-relevant", Description: "The changes to a live region that are announced.", Values: []string{"additions", "additions text", "all", "removals", "text"}},
+	{Name: "aria-required", Description: "User input is required.", Values: boolValues},
+	{Name: "aria-roledescription", Description: "A human readable description of the role of the element."},
+	{Name: "aria-rowcount", Description: "The number of rows in a table or grid."},
+	{Name: "aria-rowindex", Description: "The row index of the element in a table or grid."},
+	{Name: "aria-rowspan", Description: "The number of rows spanned by a cell."},
+	{Name: "aria-selected", Description: "The selected state of the element.", Values: boolValues},
+	{Name: "aria-setsize", Description: "The number of items in the set that the element belongs to."},
+	{Name: "aria-sort", Description: "The sort order of a table or grid column.", Values: []string{"none", "ascending", "descending", "other"}},
+	{Name: "aria-valuemax", Description: "The maximum value of a range widget."},
+	{Name: "aria-valuemin", Description: "The minimum value of a range widget."},
+	{Name: "aria-valuenow", Description: "The current value of a range widget."},
+	{Name: "aria-valuetext", Description: "A human readable alternative of aria-valuenow."},
+}
+
+// htmlElements are the elements of the HTML living standard, excluding obsolete elements.
+var htmlElements = []htmlElement{
+	{Name: "a", Description: "A hyperlink.", Attributes: []htmlAttribute{hrefAttribute, targetAttribute, relAttribute, {Name: "download", Description: "Downloads the URL instead of navigating to it."}, {Name: "hreflang", Description: "The language of the linked resource."}, {Name: "ping", Description: "URLs notified when the link is followed."}, referrerAttribute, typeAttribute}},
+	{Name: "abbr", Description: "An abbreviation or acronym."},
+	{Name: "address", Description: "Contact information."},
+	{Name: "area", Description: "An area of an image map.", Attributes: []htmlAttribute{altAttribute, {Name: "coords", Description: "The coordinates of the area."}, {Name: "shape", Description: "The shape of the area.", Values: []string{"rect", "circle", "poly", "default"}}, hrefAttribute, targetAttribute, relAttribute}},
+	{Name: "article", Description: "A self-contained composition."},
+	{Name: "aside", Description: "Content that's indirectly related to the main content."},
+	{Name: "audio", Description: "Sound content.", Attributes: mediaControlAttrs},
+	{Name: "b", Description: "Text that's brought to attention, without extra importance."},
+	{Name: "base", Description: "The base URL of relative URLs in the document.", Attributes: []htmlAttribute{hrefAttribute, targetAttribute}},
+	{Name: "bdi", Description: "Text that's isolated from the direction of the surrounding text."},
+	{Name: "bdo", Description: "Overrides the direction of the text."},
+	{Name: "blockquote", Description: "A quotation from another source.", Attributes: []htmlAttribute{{Name: "cite", Description: "The URL of the source of the quotation."}}},
+	{Name: "body", Description: "The content of the document."},
+	{Name: "br", Description: "A line break."},
+	{Name: "button", Description: "A button.", Attributes: []htmlAttribute{{Name: "type", Description: "The behavior of the button.", Values: []string{"submit", "reset", "button"}}, disabledAttribute, formAttribute, nameAttribute, valueAttribute, {Name: "formaction", Description: "The URL that the form is submitted to."}, {Name: "formmethod", Description: "The HTTP method used to submit the form.", Values: []string{"get", "post", "dialog"}}, {Name: "formnovalidate", Description: "The form isn't validated when it's submitted.", Boolean: true}, {Name: "formtarget", Description: "Where to display the response.", Values: []string{"_self", "_blank", "_parent", "_top"}}, {Name: "popovertarget", Description: "The id of the popover that the button controls."}, {Name: "popovertargetaction", Description: "The action performed on the popover.", Values: []string{"hide", "show", "toggle"}}}},
+	{Name: "canvas", Description: "A bitmap drawn with scripts.", Attributes: []htmlAttribute{widthAttribute, heightAttribute}},
+	{Name: "caption", Description: "The title of a table."},
+	{Name: "cite", Description: "The title of a creative work."},
+	{Name: "code", Description: "A fragment of computer code."},
+	{Name: "col", Description: "A column of a table.", Attributes: []htmlAttribute{{Name: "span", Description: "The number of columns."}}},
+	{Name: "colgroup", Description: "A group of columns of a table.", Attributes: []htmlAttribute{{Name: "span", Description: "The number of columns."}}},
+	{Name: "data", Description: "Content with a machine-readable value.", Attributes: []htmlAttribute{valueAttribute}},
+	{Name: "datalist", Description: "Options for other controls."},
+	{Name: "dd", Description: "The description of a term in a description list."},
+	{Name: "del", Description: "Text that has been deleted.", Attributes: []htmlAttribute{{Name: "cite", Description: "The URL that explains the change."}, {Name: "datetime", Description: "The date and time of the change."}}},
+	{Name: "details", Description: "A widget that shows information when opened.", Attributes: []htmlAttribute{{Name: "open", Description: "The details are shown.", Boolean: true}, nameAttribute}},
+	{Name: "dfn", Description: "The defining instance of a term."},
+	{Name: "dialog", Description: "A dialog box.", Attributes: []htmlAttribute{{Name: "open", Description: "The dialog is shown.", Boolean: true}}},
+	{Name: "div", Description: "A generic container for flow content."},
+	{Name: "dl", Description: "A description list."},
+	{Name: "dt", Description: "A term in a description list."},
+	{Name: "em", Description: "Emphasized text."},
+	{Name: "embed", Description: "External content.", Attributes: []htmlAttribute{srcAttribute, typeAttribute, widthAttribute, heightAttribute}},
+	{Name: "fieldset", Description: "A group of controls within a form.", Attributes: []htmlAttribute{disabledAttribute, formAttribute, nameAttribute}},
+	{Name: "figcaption", Description: "The caption of a figure."},
+	{Name: "figure", Description: "Self-contained content, e.g. an image with a caption."},
+	{Name: "footer", Description: "The footer of the nearest sectioning content."},
+	{Name: "form", Description: "A form that submits information.", Attributes: []htmlAttribute{{Name: "action", Description: "The URL that the form is submitted to."}, {Name: "method", Description: "The HTTP method used to submit the form.", Values: []string{"get", "post", "dialog"}}, {Name: "enctype", Description: "The MIME type of the submitted data.", Values: []string{"application/x-www-form-urlencoded", "multipart/form-data", "text/plain"}}, {Name: "novalidate", Description: "The form isn't validated when it's submitted.", Boolean: true}, targetAttribute, nameAttribute, autocompleteAttr, {Name: "accept-charset", Description: "The character encodings accepted by the server."}, relAttribute}},
+	{Name: "h1", Description: "A level 1 section heading."},
+	{Name: "h2", Description: "A level 2 section heading."},
+	{Name: "h3", Description: "A level 3 section heading."},
+	{Name: "h4", Description: "A level 4 section heading."},
+	{Name: "h5", Description: "A level 5 section heading."},
+	{Name: "h6", Description: "A level 6 section heading."},
+	{Name: "head", Description: "Metadata about the document."},
+	{Name: "header", Description: "Introductory content, e.g. headings and navigation."},
+	{Name: "hgroup", Description: "A heading grouped with secondary content."},
+	{Name: "hr", Description: "A thematic break between paragraphs."},
+	{Name: "html", Description: "The root element of the document.", Attributes: []htmlAttribute{{Name: "xmlns", Description: "The XML namespace of the document."}}},
+	{Name: "i", Description: "Text in an alternate voice or mood."},
+	{Name: "iframe", Description: "A nested browsing context.", Attributes: []htmlAttribute{srcAttribute, {Name: "srcdoc", Description: "The HTML content of the frame."}, nameAttribute, {Name: "sandbox", Description: "Restrictions applied to the content of the frame.", Values: []string{"allow-downloads", "allow-forms", "allow-modals", "allow-popups", "allow-same-origin", "allow-scripts", "allow-top-navigation"}}, {Name: "allow", Description: "The permissions policy of the frame."}, {Name: "allowfullscreen", Description: "The frame can be shown fullscreen.", Boolean: true}, widthAttribute, heightAttribute, loadingAttribute, referrerAttribute}},
+	{Name: "img", Description: "An image.", Attributes: []htmlAttribute{srcAttribute, altAttribute, srcsetAttribute, sizesAttribute, widthAttribute, heightAttribute, loadingAttribute, {Name: "decoding", Description: "How the image is decoded.", Values: []string{"sync", "async", "auto"}}, {Name: "fetchpriority", Description: "The priority of fetching the image.", Values: []string{"high", "low", "auto"}}, crossoriginAttr, referrerAttribute, {Name: "usemap", Description: "The image map of the image."}, {Name: "ismap", Description: "The image is part of a server-side image map.", Boolean: true}}},
+	{Name: "input", Description: "A form control.", Attributes: []htmlAttribute{{Name: "type", Description: "The type of control.", Values: []string{"button", "checkbox", "color", "date", "datetime-local", "email", "file", "hidden", "image", "month", "number", "password", "radio", "range", "reset", "search", "submit", "tel", "text", "time", "url", "week"}}, nameAttribute, valueAttribute, placeholderAttr, requiredAttribute, disabledAttribute, readonlyAttribute, {Name: "checked", Description: "The control is checked.", Boolean: true}, autocompleteAttr, formAttribute, {Name: "accept", Description: "The types of file that are accepted."}, {Name: "capture", Description: "The camera used to capture media.", Values: []string{"user", "environment"}}, {Name: "list", Description: "The id of a datalist of suggested values."}, {Name: "max", Description: "The maximum value."}, {Name: "maxlength", Description: "The maximum length of the value."}, {Name: "min", Description: "The minimum value."}, {Name: "minlength", Description: "The minimum length of the value."}, {Name: "multiple", Description: "More than one value can be entered.", Boolean: true}, {Name: "pattern", Description: "A regular expression that the value must match."}, {Name: "size", Description: "The width of the control, in characters."}, {Name: "step", Description: "The granularity of the value."}, srcAttribute, altAttribute, widthAttribute, heightAttribute}},
+	{Name: "ins", Description: "Text that has been inserted.", Attributes: []htmlAttribute{{Name: "cite", Description: "The URL that explains the change."}, {Name: "datetime", Description: "The date and time of the change."}}},
+	{Name: "kbd", Description: "Text entered by the user, e.g. with a keyboard."},
+	{Name: "label", Description: "A caption of a control.", Attributes: []htmlAttribute{{Name: "for", Description: "The id of the labeled control."}}},
+	{Name: "legend", Description: "The caption of a fieldset."},
+	{Name: "li", Description: "An item of a list.", Attributes: []htmlAttribute{valueAttribute}},
+	{Name: "link", Description: "The relationship to an external resource, e.g. a stylesheet.", Attributes: []htmlAttribute{hrefAttribute, relAttribute, {Name: "as", Description: "The type of content being preloaded.", Values: []string{"audio", "document", "embed", "fetch", "font", "image", "object", "script", "style", "track", "video", "worker"}}, typeAttribute, mediaAttribute, sizesAttribute, crossoriginAttr, {Name: "integrity", Description: "The hash of the resource, used to verify it."}, referrerAttribute, {Name: "hreflang", Description: "The language of the linked resource."}}},
+	{Name: "main", Description: "The dominant content of the body."},
+	{Name: "map", Description: "An image map.", Attributes: []htmlAttribute{nameAttribute}},
+	{Name: "mark", Description: "Text that's highlighted for reference."},
+	{Name: "menu", Description: "A list of commands."},
+	{Name: "meta", Description: "Metadata that can't be represented by other elements.", Attributes: []htmlAttribute{{Name: "name", Description: "The name of the metadata.", Values: []string{"application-name", "author", "description", "generator", "keywords", "referrer", "theme-color", "color-scheme", "viewport", "robots"}}, {Name: "content", Description: "The value of the metadata."}, {Name: "charset", Description: "The character encoding of the document.", Values: []string{"utf-8"}}, {Name: "http-equiv", Description: "A pragma directive.", Values: []string{"content-security-policy", "content-type", "default-style", "refresh", "x-ua-compatible"}}, mediaAttribute}},
+	{Name: "meter", Description: "A scalar value within a known range.", Attributes: []htmlAttribute{valueAttribute, {Name: "min", Description: "The lower bound of the range."}, {Name: "max", Description: "The upper bound of the range."}, {Name: "low", Description: "The upper bound of the low end of the range."}, {Name: "high", Description: "The lower bound of the high end of the range."}, {Name: "optimum", Description: "The optimal value."}}},
+	{Name: "nav", Description: "A section of navigation links."},
+	{Name: "noscript", Description: "Content shown when scripts are disabled."},
+	{Name: "object", Description: "An external resource.", Attributes: []htmlAttribute{{Name: "data", Description: "The URL of the resource."}, typeAttribute, nameAttribute, formAttribute, widthAttribute, heightAttribute}},
+	{Name: "ol", Description: "An ordered list.", Attributes: []htmlAttribute{{Name: "reversed", Description: "The list is in descending order.", Boolean: true}, {Name: "start", Description: "The number of the first item."}, {Name: "type", Description: "The kind of marker.", Values: []string{"1", "a", "A", "i", "I"}}}},
+	{Name: "optgroup", Description: "A group of options.", Attributes: []htmlAttribute{{Name: "label", Description: "The name of the group."}, disabledAttribute}},
+	{Name: "option", Description: "An option of a select or datalist.", Attributes: []htmlAttribute{valueAttribute, {Name: "label", Description: "The text of the option."}, {Name: "selected", Description: "The option is selected.", Boolean: true}, disabledAttribute}},
+	{Name: "output", Description: "The result of a calculation or user action.", Attributes: []htmlAttribute{{Name: "for", Description: "The ids of the elements that contributed to the result."}, formAttribute, nameAttribute}},
+	{Name: "p", Description: "A paragraph."},
+	{Name: "picture", Description: "Alternative versions of an image."},
+	{Name: "pre", Description: "Preformatted text."},
+	{Name: "progress", Description: "The progress of a task.", Attributes: []htmlAttribute{valueAttribute, {Name: "max", Description: "The amount of work that the task requires."}}},
+	{Name: "q", Description: "An inline quotation.", Attributes: []htmlAttribute{{Name: "cite", Description: "The URL of the source of the quotation."}}},
+	{Name: "rp", Description: "Parentheses shown by browsers that don't support ruby annotations."},
+	{Name: "rt", Description: "The text of a ruby annotation."},
+	{Name: "ruby", Description: "A ruby annotation."},
+	{Name: "s", Description: "Text that's no longer accurate or relevant."},
+	{Name: "samp", Description: "Sample output of a computer program."},
+	{Name: "script", Description: "A script.", Attributes: []htmlAttribute{srcAttribute, {Name: "type", Description: "The type of script.", Values: []string{"module", "importmap", "text/javascript"}}, {Name: "async", Description: "The script is run as soon as it's available.", Boolean: true}, {Name: "defer", Description: "The script is run after the document has been parsed.", Boolean: true}, {Name: "nomodule", Description: "The script isn't run by browsers that support modules.", Boolean: true}, crossoriginAttr, {Name: "integrity", Description: "The hash of the script, used to verify it."}, referrerAttribute}},
+	{Name: "search", Description: "A section of search or filtering controls."},
+	{Name: "section", Description: "A generic section of a document."},
+	{Name: "select", Description: "A control that provides a menu of options.", Attributes: []htmlAttribute{nameAttribute, {Name: "multiple", Description: "More than one option can be selected.", Boolean: true}, {Name: "size", Description: "The number of visible options."}, requiredAttribute, disabledAttribute, autocompleteAttr, formAttribute}},
+	{Name: "slot", Description: "A placeholder in a web component.", Attributes: []htmlAttribute{nameAttribute}},
+	{Name: "small", Description: "Side comments, e.g. fine print."},
+	{Name: "source", Description: "A media resource of a picture, audio or video element.", Attributes: []htmlAttribute{srcAttribute, srcsetAttribute, sizesAttribute, typeAttribute, mediaAttribute, widthAttribute, heightAttribute}},
+	{Name: "span", Description: "A generic inline container."},
+	{Name: "strong", Description: "Text of strong importance."},
+	{Name: "style", Description: "Style information.", Attributes: []htmlAttribute{mediaAttribute, {Name: "blocking", Description: "The operations blocked on fetching the styles.", Values: []string{"render"}}}},
+	{Name: "sub", Description: "Subscript text."},
+	{Name: "summary", Description: "The summary of a details element."},
+	{Name: "sup", Description: "Superscript text."},
+	{Name: "table", Description: "Tabular data."},
+	{Name: "tbody", Description: "The body rows of a table."},
+	{Name: "td", Description: "A data cell of a table.", Attributes: []htmlAttribute{colspanAttribute, rowspanAttribute, {Name: "headers", Description: "The ids of the header cells of the cell."}}},
+	{Name: "template", Description: "Content that isn't rendered, but can be cloned by scripts.", Attributes: []htmlAttribute{{Name: "shadowrootmode", Description: "Creates a declarative shadow root.", Values: []string{"open", "closed"}}}},
+	{Name: "textarea", Description: "A multi-line text control.", Attributes: []htmlAttribute{nameAttribute, {Name: "rows", Description: "The number of visible lines."}, {Name: "cols", Description: "The width of the control, in characters."}, placeholderAttr, requiredAttribute, disabledAttribute, readonlyAttribute, {Name: "maxlength", Description: "The maximum length of the value."}, {Name: "minlength", Description: "The minimum length of the value."}, {Name: "wrap", Description: "How the text is wrapped when it's submitted.", Values: []string{"hard", "soft", "off"}}, autocompleteAttr, formAttribute}},
+	{Name: "tfoot", Description: "The summary rows of a table."},
+	{Name: "th", Description: "A header cell of a table.", Attributes: []htmlAttribute{colspanAttribute, rowspanAttribute, {Name: "headers", Description: "The ids of the header cells of the cell."}, {Name: "scope", Description: "The cells that the header relates to.", Values: []string{"row", "col", "rowgroup", "colgroup"}}, {Name: "abbr", Description: "A short description of the cell."}}},
+	{Name: "thead", Description: "The header rows of a table."},
+	{Name: "time", Description: "A time or date.", Attributes: []htmlAttribute{{Name: "datetime", Description: "The machine-readable time or date."}}},
+	{Name: "title", Description: "The title of the document."},
+	{Name: "tr", Description: "A row of a table."},
+	{Name: "track", Description: "A text track of a video or audio element.", Attributes: []htmlAttribute{srcAttribute, {Name: "kind", Description: "How the track is used.", Values: []string{"subtitles", "captions", "descriptions", "chapters", "metadata"}}, {Name: "srclang", Description: "The language of the track."}, {Name: "label", Description: "The title of the track."}, {Name: "default", Description: "The track is enabled by default.", Boolean: true}}},
+	{Name: "u", Description: "Text with an unarticulated annotation."},
+	{Name: "ul", Description: "An unordered list."},
+	{Name: "var", Description: "A variable in a mathematical expression or program."},
+	{Name: "video", Description: "A video.", Attributes: append([]htmlAttribute{{Name: "poster", Description: "An image shown before the video plays."}, {Name: "playsinline", Description: "The video plays inline, instead of fullscreen.", Boolean: true}, widthAttribute, heightAttribute}, mediaControlAttrs...)},
+	{Name: "wbr", Description: "A line break opportunity."},
+}
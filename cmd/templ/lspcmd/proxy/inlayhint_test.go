@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvertInlayHints(t *testing.T) {
+	templ := `package main
+
+templ page(items []string) {
+	{{ count := len(items) }}
+	<p>{ strings.Repeat("x", count) }</p>
+}
+`
+	tf, err := parser.ParseString(templ)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(strings.Builder)
+	op, err := generator.Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	// position returns the position of the end of the text in the generated code, or of the
+	// start of the text if start is true.
+	position := func(text string, start bool) lsp.Position {
+		for i, line := range strings.Split(w.String(), "\n") {
+			if col := strings.Index(line, text); col >= 0 {
+				if !start {
+					col += len(text)
+				}
+				return lsp.Position{Line: uint32(i), Character: uint32(col)}
+			}
+		}
+		t.Fatalf("%q not found in generated code", text)
+		return lsp.Position{}
+	}
+	typeHint := lsp.InlayHint{
+		Position: position("count", false),
+		Label:    " int",
+		Kind:     lsp.InlayHintKindType,
+		TextEdits: []lsp.TextEdit{
+			{
+				Range:   lsp.Range{Start: position("count", false), End: position("count", false)},
+				NewText: " int",
+			},
+		},
+	}
+	parameterHint := lsp.InlayHint{
+		Position: position(`"x"`, true),
+		Label:    "s:",
+		Kind:     lsp.InlayHintKindParameter,
+	}
+	generatedCodeHint := lsp.InlayHint{
+		Position: position("templ_7745c5c3_Err", true),
+		Label:    " error",
+		Kind:     lsp.InlayHintKindType,
+	}
+	hints := []lsp.InlayHint{typeHint, parameterHint, generatedCodeHint}
+
+	t.Run("hints of expressions are mapped to the templ file", func(t *testing.T) {
+		all := lsp.Range{End: lsp.Position{Line: 10}}
+		actual := convertInlayHints(op.SourceMap, all, hints)
+		expected := []lsp.InlayHint{
+			{
+				Position: lsp.Position{Line: 3, Character: 9},
+				Label:    " int",
+				Kind:     lsp.InlayHintKindType,
+				TextEdits: []lsp.TextEdit{
+					{
+						Range:   lsp.Range{Start: lsp.Position{Line: 3, Character: 9}, End: lsp.Position{Line: 3, Character: 9}},
+						NewText: " int",
+					},
+				},
+			},
+			{
+				Position: lsp.Position{Line: 4, Character: 21},
+				Label:    "s:",
+				Kind:     lsp.InlayHintKindParameter,
+			},
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("hints outside the range are dropped", func(t *testing.T) {
+		line4 := lsp.Range{Start: lsp.Position{Line: 4}, End: lsp.Position{Line: 4, Character: 100}}
+		actual := convertInlayHints(op.SourceMap, line4, hints)
+		if len(actual) != 1 || actual[0].Label != "s:" {
+			t.Errorf("expected only the parameter hint, got %+v", actual)
+		}
+	})
+}
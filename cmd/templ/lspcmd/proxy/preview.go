@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/generator"
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// htmlPreview returns the HTML that's rendered by the element whose name is at the position,
+// or by the template whose templ keyword is at the position, if its content is constant. The
+// range is that of the element name, or the keyword.
+func htmlPreview(tf *parser.TemplateFile, pos lsp.Position) (html string, r lsp.Range, ok bool) {
+	within := func(pr parser.Range) bool {
+		return !isAfter(toLSPPosition(pr.From), pos) && !isAfter(pos, toLSPPosition(pr.To))
+	}
+	var nodes []parser.Node
+	parser.Walk(tf, func(c *parser.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *parser.HTMLTemplate:
+			keyword := parser.Range{From: n.Range.From, To: n.Range.From}
+			keyword.To.Col += uint32(len("templ"))
+			if within(keyword) {
+				nodes, r = n.Children, lsp.Range{Start: toLSPPosition(keyword.From), End: toLSPPosition(keyword.To)}
+				return false
+			}
+		case *parser.Element:
+			if within(n.NameRange) {
+				nodes, r = []parser.Node{n}, lsp.Range{Start: toLSPPosition(n.NameRange.From), End: toLSPPosition(n.NameRange.To)}
+				return false
+			}
+		}
+		return nodes == nil
+	})
+	if nodes == nil {
+		return "", r, false
+	}
+	html, ok = constantHTML(nodes)
+	return html, r, ok
+}
+
+// constantHTML returns the HTML that the generated code writes for the nodes, with whitespace
+// normalized as it is when rendered, or ok=false if the nodes contain Go expressions, or call
+// other components.
+func constantHTML(nodes []parser.Node) (html string, ok bool) {
+	ok = true
+	for _, n := range nodes {
+		parser.Walk(n, func(c *parser.Cursor) bool {
+			switch n := c.Node().(type) {
+			case *parser.Element, *parser.RawElement, *parser.Text, *parser.Whitespace, *parser.HTMLComment, *parser.DocType:
+			case *parser.ConstantAttribute:
+				if _, isConstant := n.Key.(parser.ConstantAttributeKey); !isConstant {
+					ok = false
+				}
+			case *parser.BoolConstantAttribute:
+				if _, isConstant := n.Key.(parser.ConstantAttributeKey); !isConstant {
+					ok = false
+				}
+			default:
+				ok = false
+			}
+			return ok
+		})
+	}
+	if !ok {
+		return "", false
+	}
+	tf := &parser.TemplateFile{
+		Package: parser.Package{Expression: parser.Expression{Value: "package preview"}},
+		Nodes: []parser.TemplateFileNode{
+			&parser.HTMLTemplate{Expression: parser.Expression{Value: "Preview()"}, Children: nodes},
+		},
+	}
+	op, err := generator.Generate(tf, io.Discard)
+	if err != nil {
+		return "", false
+	}
+	var sb strings.Builder
+	for _, literal := range op.Literals {
+		s, err := strconv.Unquote(`"` + literal + `"`)
+		if err != nil {
+			return "", false
+		}
+		sb.WriteString(s)
+	}
+	// The space after the last node is rendered before the next node, so it isn't part of the
+	// nodes.
+	return strings.TrimRight(sb.String(), " \t\n"), true
+}
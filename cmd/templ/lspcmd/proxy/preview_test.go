@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHTMLPreview(t *testing.T) {
+	templ := `package main
+
+templ constant() {
+	<ul class="a &amp; b" data-x='y"z'>
+		<li>
+			Hello,   world
+		</li>
+		<li><br/></li>
+	</ul>
+}
+
+templ dynamic(name string) {
+	<div>
+		<p>Constant</p>
+		<p>{ name }</p>
+		@constant()
+	</div>
+}
+`
+	tf, err := parser.ParseString(templ)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	tests := []struct {
+		name         string
+		pos          lsp.Position
+		expectedHTML string
+		expectedOK   bool
+	}{
+		{
+			name:         "elements with constant content are rendered",
+			pos:          lsp.Position{Line: 3, Character: 2},
+			expectedHTML: `<ul class="a &amp; b" data-x='y"z'><li>Hello,   world</li><li><br></li></ul>`,
+			expectedOK:   true,
+		},
+		{
+			name:         "templates with constant content are rendered from the templ keyword",
+			pos:          lsp.Position{Line: 2, Character: 3},
+			expectedHTML: `<ul class="a &amp; b" data-x='y"z'><li>Hello,   world</li><li><br></li></ul>`,
+			expectedOK:   true,
+		},
+		{
+			name:         "constant elements within templates with expressions are rendered",
+			pos:          lsp.Position{Line: 13, Character: 3},
+			expectedHTML: `<p>Constant</p>`,
+			expectedOK:   true,
+		},
+		{
+			name: "elements containing expressions are not rendered",
+			pos:  lsp.Position{Line: 14, Character: 3},
+		},
+		{
+			name: "elements that call components are not rendered",
+			pos:  lsp.Position{Line: 12, Character: 2},
+		},
+		{
+			name: "text is not rendered",
+			pos:  lsp.Position{Line: 5, Character: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, _, ok := htmlPreview(tf, tt.pos)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if diff := cmp.Diff(tt.expectedHTML, html); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestHoverHTMLPreview(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	p := NewServer(log, nil, NewSourceMapCache(), nil, false)
+	uri := lsp.DocumentURI("file:///test.templ")
+	p.TemplSource.Set(string(uri), NewDocument(log, "package main\n\ntempl a() {\n\t<p>a</p>\n}\n"))
+	actual, err := p.Hover(context.Background(), &lsp.HoverParams{
+		TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: uri},
+			Position:     lsp.Position{Line: 3, Character: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to hover: %v", err)
+	}
+	expected := &lsp.Hover{
+		Contents: lsp.MarkupContent{
+			Kind:  lsp.Markdown,
+			Value: "Renders:\n\n```html\n<p>a</p>\n```",
+		},
+		Range: &lsp.Range{
+			Start: lsp.Position{Line: 3, Character: 2},
+			End:   lsp.Position{Line: 3, Character: 3},
+		},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
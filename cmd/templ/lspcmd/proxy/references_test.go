@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReferences(t *testing.T) {
+	templ := `package main
+
+templ card(title string) {
+	<div>{ title }</div>
+}
+
+templ page() {
+	@card("a")
+	@card("b")
+}
+`
+	tf, err := parser.ParseString(templ)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(strings.Builder)
+	op, err := generator.Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	cache := NewSourceMapCache()
+	cache.Set("file:///app/page.templ", op.SourceMap)
+	p := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, cache, nil, false)
+	p.GoSource["file:///app/page.templ"] = w.String()
+
+	// Find every occurrence of the name in the generated code, including the occurrences that
+	// aren't written in the templ file, like gopls.
+	var goReferences []lsp.Location
+	var goDeclaration lsp.Position
+	for i, line := range strings.Split(w.String(), "\n") {
+		col := strings.Index(line, "card(")
+		if col < 0 {
+			continue
+		}
+		if strings.HasPrefix(line, "func ") {
+			goDeclaration = lsp.Position{Line: uint32(i), Character: uint32(col)}
+		}
+		goReferences = append(goReferences, lsp.Location{
+			URI: "file:///app/page_templ.go",
+			Range: lsp.Range{
+				Start: lsp.Position{Line: uint32(i), Character: uint32(col)},
+				End:   lsp.Position{Line: uint32(i), Character: uint32(col + len("card"))},
+			},
+		})
+	}
+	location := func(uri lsp.DocumentURI, line, col uint32) lsp.Location {
+		return lsp.Location{
+			URI: uri,
+			Range: lsp.Range{
+				Start: lsp.Position{Line: line, Character: col},
+				End:   lsp.Position{Line: line, Character: col + uint32(len("card"))},
+			},
+		}
+	}
+
+	t.Run("references are found from the templ keyword of a declaration", func(t *testing.T) {
+		ok, goURI, pos := p.declarationPosition("file:///app/page.templ", lsp.Position{Line: 2, Character: 2})
+		if !ok {
+			t.Fatal("expected the declaration to be found")
+		}
+		if goURI != "file:///app/page_templ.go" {
+			t.Errorf("expected the Go file, got %q", goURI)
+		}
+		if diff := cmp.Diff(goDeclaration, pos); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("positions outside declarations aren't found", func(t *testing.T) {
+		if ok, _, _ := p.declarationPosition("file:///app/page.templ", lsp.Position{Line: 3, Character: 2}); ok {
+			t.Error("expected no declaration")
+		}
+	})
+	t.Run("references in templ and Go files are returned", func(t *testing.T) {
+		goCall := location("file:///app/main.go", 10, 2)
+		actual := p.convertReferences(append(goReferences, goCall))
+		expected := []lsp.Location{
+			location("file:///app/page.templ", 2, 6),
+			location("file:///app/page.templ", 7, 2),
+			location("file:///app/page.templ", 8, 2),
+			goCall,
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
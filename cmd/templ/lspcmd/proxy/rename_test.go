@@ -0,0 +1,185 @@
+package proxy
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvertWorkspaceEdit(t *testing.T) {
+	templ := `package main
+
+css red() {
+	color: red;
+}
+
+script greet() {
+	alert("hello");
+}
+
+templ page() {
+	<button class={ red() } onclick={ greet() }>Greet</button>
+}
+
+templ card(title string) {
+	if title != "" {
+		<h1>{ title }</h1>
+	}
+}
+`
+	tf, err := parser.ParseString(templ)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(strings.Builder)
+	op, err := generator.Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	cache := NewSourceMapCache()
+	cache.Set("file:///app/page.templ", op.SourceMap)
+	p := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, cache, nil, false)
+
+	// Rename every occurrence of a name in the generated code, including the occurrences that
+	// aren't written in the templ file.
+	rename := func(name, newName string) (edits []lsp.TextEdit) {
+		for i, line := range strings.Split(w.String(), "\n") {
+			for col := strings.Index(line, name); col >= 0; {
+				edits = append(edits, lsp.TextEdit{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: uint32(i), Character: uint32(col)},
+						End:   lsp.Position{Line: uint32(i), Character: uint32(col + len(name))},
+					},
+					NewText: newName,
+				})
+				next := strings.Index(line[col+len(name):], name)
+				if next < 0 {
+					break
+				}
+				col += len(name) + next
+			}
+		}
+		return edits
+	}
+	edit := func(line, col, length uint32, newText string) lsp.TextEdit {
+		return lsp.TextEdit{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: line, Character: col},
+				End:   lsp.Position{Line: line, Character: col + length},
+			},
+			NewText: newText,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		input    *lsp.WorkspaceEdit
+		expected *lsp.WorkspaceEdit
+	}{
+		{
+			name: "css template names are renamed in the templ file",
+			input: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					"file:///app/page_templ.go": rename("red", "danger"),
+				},
+			},
+			expected: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					"file:///app/page.templ": {
+						edit(2, 4, 3, "danger"),
+						edit(11, 17, 3, "danger"),
+					},
+				},
+			},
+		},
+		{
+			name: "script template names are renamed in the templ file",
+			input: &lsp.WorkspaceEdit{
+				DocumentChanges: []lsp.TextDocumentEdit{
+					{
+						TextDocument: lsp.OptionalVersionedTextDocumentIdentifier{
+							TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "file:///app/page_templ.go"},
+						},
+						Edits: rename("greet", "welcome"),
+					},
+				},
+			},
+			expected: &lsp.WorkspaceEdit{
+				DocumentChanges: []lsp.TextDocumentEdit{
+					{
+						TextDocument: lsp.OptionalVersionedTextDocumentIdentifier{
+							TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "file:///app/page.templ"},
+						},
+						Edits: []lsp.TextEdit{
+							edit(6, 7, 5, "welcome"),
+							edit(11, 35, 5, "welcome"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "parameters are renamed in the templ file",
+			input: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					"file:///app/page_templ.go": rename("title", "heading"),
+				},
+			},
+			expected: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					"file:///app/page.templ": {
+						edit(14, 11, 5, "heading"),
+						edit(15, 4, 5, "heading"),
+						edit(16, 8, 5, "heading"),
+					},
+				},
+			},
+		},
+		{
+			name: "edits of Go files are kept",
+			input: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					"file:///app/main.go": {edit(10, 2, 3, "danger")},
+				},
+			},
+			expected: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					"file:///app/main.go": {edit(10, 2, 3, "danger")},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := p.convertWorkspaceEdit(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestConvertWorkspaceEditOfClosedFile(t *testing.T) {
+	p := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, NewSourceMapCache(), nil, false)
+	_, err := p.convertWorkspaceEdit(&lsp.WorkspaceEdit{
+		Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+			"file:///app/main.go":       {{NewText: "page"}},
+			"file:///app/page_templ.go": {{NewText: "page"}},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error, because the source map of the templ file isn't available")
+	}
+	if !strings.Contains(err.Error(), "file:///app/page.templ") {
+		t.Errorf("expected the error to include the templ file, got %q", err.Error())
+	}
+}
@@ -0,0 +1,402 @@
+package proxy
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// semanticTokensLegend is the legend of the semantic tokens of templ files. The index of a
+// token type, or the bit of a modifier, is its position in the legend.
+var semanticTokensLegend = lsp.SemanticTokensLegend{
+	TokenTypes: []lsp.SemanticTokenTypes{
+		lsp.SemanticTokenKeyword,
+		lsp.SemanticTokenFunction,
+		lsp.SemanticTokenVariable,
+		lsp.SemanticTokenParameter,
+		lsp.SemanticTokenType,
+		lsp.SemanticTokenProperty,
+		lsp.SemanticTokenString,
+		lsp.SemanticTokenNumber,
+		lsp.SemanticTokenComment,
+	},
+	TokenModifiers: []lsp.SemanticTokenModifiers{
+		lsp.SemanticTokenModifierDeclaration,
+	},
+}
+
+// semanticToken is a token of a templ file, from the Start to the End byte offset of the
+// source.
+type semanticToken struct {
+	Start, End  int
+	Type        lsp.SemanticTokenTypes
+	Declaration bool
+}
+
+// semanticTokens returns the tokens of the templ keywords, component references, Go code,
+// attribute keys and string literals of the template file, which was parsed from src.
+//
+// Go code is tokenized by scanning it, so it's highlighted even if the generated code can't
+// be type checked.
+func semanticTokens(src string, tf *parser.TemplateFile) (tokens []semanticToken) {
+	t := &semanticTokenizer{src: src}
+	parser.Walk(tf, func(c *parser.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *parser.Package:
+			t.goCode(n.Expression, false)
+		case *parser.TemplateFileGoExpression:
+			t.goCode(n.Expression, false)
+		case *parser.HTMLTemplate:
+			t.keywordAt(n.Range.From, "templ")
+			t.signature(n.Expression)
+			if n.Extends != nil {
+				t.keywordBefore(int(n.Extends.Range.From.Index), "extends")
+				t.goCode(*n.Extends, true)
+			}
+		case *parser.CSSTemplate:
+			t.keywordAt(n.Range.From, "css")
+			t.signature(n.Expression)
+		case *parser.ScriptTemplate:
+			t.keywordAt(n.Range.From, "script")
+			// The signature is the name, and the parameters within parentheses.
+			end := min(int(n.Parameters.Range.To.Index)+1, len(src))
+			t.signature(parser.Expression{
+				Value: src[n.Name.Range.From.Index:end],
+				Range: parser.Range{From: n.Name.Range.From},
+			})
+		case *parser.ContextDeclaration:
+			t.keywordAt(n.Range.From, "templ")
+			t.add(n.Name.Range, lsp.SemanticTokenVariable, true)
+			t.goCode(n.Type, false)
+		case *parser.ConstantAttribute:
+			t.attributeKey(n.Key)
+			if key, ok := n.Key.(parser.ConstantAttributeKey); ok {
+				t.attributeValue(int(key.NameRange.To.Index))
+			}
+		case *parser.BoolConstantAttribute:
+			t.attributeKey(n.Key)
+		case *parser.ExpressionAttribute:
+			t.attributeKey(n.Key)
+			t.goCode(n.Expression, false)
+		case *parser.BoolExpressionAttribute:
+			t.attributeKey(n.Key)
+			t.goCode(n.Expression, false)
+		case *parser.SpreadAttributes:
+			t.goCode(n.Expression, false)
+		case *parser.ConditionalAttribute:
+			t.keywordBefore(int(n.Expression.Range.From.Index), "if")
+			t.goCode(n.Expression, false)
+		case *parser.IfExpression:
+			t.keywordBefore(int(n.Expression.Range.From.Index), "if")
+			t.goCode(n.Expression, false)
+			for _, elseIf := range n.ElseIfs {
+				if i, ok := t.keywordBefore(int(elseIf.Expression.Range.From.Index), "if"); ok {
+					t.keywordBefore(i, "else")
+				}
+				t.goCode(elseIf.Expression, false)
+			}
+		case *parser.ForExpression:
+			t.keywordBefore(int(n.Expression.Range.From.Index), "for")
+			t.goCode(n.Expression, false)
+		case *parser.SwitchExpression:
+			t.keywordBefore(int(n.Expression.Range.From.Index), "switch")
+			t.goCode(n.Expression, false)
+			// The expressions of cases include the case and default keywords.
+			for _, c := range n.Cases {
+				t.goCode(c.Expression, false)
+			}
+		case *parser.CacheExpression:
+			t.keywordBefore(int(n.Expression.Range.From.Index), "cache")
+			t.goCode(n.Expression, false)
+		case *parser.SlotDefinition:
+			t.keywordBefore(int(n.NameRange.From.Index), "slot")
+			t.add(n.NameRange, lsp.SemanticTokenString, false)
+		case *parser.BlockDefinition:
+			t.keywordBefore(int(n.NameRange.From.Index), "block")
+			t.add(n.NameRange, lsp.SemanticTokenString, false)
+		case *parser.FragmentDefinition:
+			t.keywordBefore(int(n.NameRange.From.Index), "fragment")
+			t.add(n.NameRange, lsp.SemanticTokenString, false)
+		case *parser.SlotExpression:
+			t.add(n.NameRange, lsp.SemanticTokenString, false)
+		case *parser.CallTemplateExpression:
+			t.goCode(n.Expression, true)
+		case *parser.TemplElementExpression:
+			t.goCode(n.Expression, true)
+		case *parser.StringExpression:
+			t.goCode(n.Expression, false)
+		case *parser.GoCode:
+			t.goCode(n.Expression, false)
+		case *parser.GoComment:
+			t.add(n.Range, lsp.SemanticTokenComment, false)
+		case *parser.HTMLComment:
+			t.add(n.Range, lsp.SemanticTokenComment, false)
+		}
+		return true
+	})
+	// The else keyword of if expressions isn't part of the parsed expressions.
+	for _, m := range elseKeyword.FindAllStringSubmatchIndex(src, -1) {
+		t.tokens = append(t.tokens, semanticToken{Start: m[2], End: m[3], Type: lsp.SemanticTokenKeyword})
+	}
+	return t.tokens
+}
+
+var elseKeyword = regexp.MustCompile(`(?m)^[ \t]*}[ \t]*(else)[ \t]*{`)
+
+type semanticTokenizer struct {
+	src    string
+	tokens []semanticToken
+}
+
+func (t *semanticTokenizer) add(r parser.Range, typ lsp.SemanticTokenTypes, declaration bool) {
+	t.tokens = append(t.tokens, semanticToken{Start: int(r.From.Index), End: int(r.To.Index), Type: typ, Declaration: declaration})
+}
+
+// keywordAt adds the keyword if it's at the position, e.g. at the start of a declaration.
+func (t *semanticTokenizer) keywordAt(p parser.Position, keyword string) {
+	start := int(p.Index)
+	if strings.HasPrefix(t.src[min(start, len(t.src)):], keyword) {
+		t.tokens = append(t.tokens, semanticToken{Start: start, End: start + len(keyword), Type: lsp.SemanticTokenKeyword})
+	}
+}
+
+// keywordBefore adds the keyword if it's before the index, ignoring whitespace and quotes, and
+// returns the index of the start of the keyword.
+func (t *semanticTokenizer) keywordBefore(index int, keyword string) (start int, ok bool) {
+	before := strings.TrimRight(t.src[:min(index, len(t.src))], " \t\r\n\"`")
+	if !strings.HasSuffix(before, keyword) {
+		return index, false
+	}
+	start = len(before) - len(keyword)
+	if start > 0 && isIdentChar(before[start-1]) {
+		return index, false
+	}
+	t.tokens = append(t.tokens, semanticToken{Start: start, End: len(before), Type: lsp.SemanticTokenKeyword})
+	return start, true
+}
+
+func (t *semanticTokenizer) attributeKey(key parser.AttributeKey) {
+	switch key := key.(type) {
+	case parser.ConstantAttributeKey:
+		t.add(key.NameRange, lsp.SemanticTokenProperty, false)
+	case parser.ExpressionAttributeKey:
+		t.goCode(key.Expression, false)
+	}
+}
+
+// attributeValue adds the quoted value of a constant attribute, which follows its key.
+func (t *semanticTokenizer) attributeValue(keyEnd int) {
+	rest := t.src[min(keyEnd, len(t.src)):]
+	trimmed := strings.TrimLeft(rest, " \t\r\n")
+	if !strings.HasPrefix(trimmed, "=") {
+		return
+	}
+	trimmed = strings.TrimLeft(trimmed[1:], " \t\r\n")
+	if trimmed == "" || (trimmed[0] != '"' && trimmed[0] != '\'') {
+		return
+	}
+	end := strings.IndexByte(trimmed[1:], trimmed[0])
+	if end < 0 {
+		return
+	}
+	start := keyEnd + len(rest) - len(trimmed)
+	t.tokens = append(t.tokens, semanticToken{Start: start, End: start + end + 2, Type: lsp.SemanticTokenString})
+}
+
+// signature adds the tokens of the signature of a template, e.g. Name(a string), or
+// (p Page) Name[T any](a T).
+func (t *semanticTokenizer) signature(e parser.Expression) {
+	const prefix = "package p\nfunc "
+	f, err := goparser.ParseFile(token.NewFileSet(), "", prefix+e.Value+" {}", goparser.SkipObjectResolution)
+	if err != nil || len(f.Decls) != 1 {
+		t.goCode(e, false)
+		return
+	}
+	fn, ok := f.Decls[0].(*ast.FuncDecl)
+	if !ok {
+		t.goCode(e, false)
+		return
+	}
+	// Offsets of the identifiers within the signature.
+	overrides := map[int]semanticToken{}
+	offset := func(ident *ast.Ident) int {
+		// The file set starts at 1.
+		return int(ident.Pos()) - 1 - len(prefix)
+	}
+	overrides[offset(fn.Name)] = semanticToken{Type: lsp.SemanticTokenFunction, Declaration: true}
+	for _, fields := range []*ast.FieldList{fn.Recv, fn.Type.TypeParams, fn.Type.Params} {
+		if fields == nil {
+			continue
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				overrides[offset(name)] = semanticToken{Type: lsp.SemanticTokenParameter, Declaration: true}
+			}
+			ast.Inspect(field.Type, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok {
+					overrides[offset(ident)] = semanticToken{Type: lsp.SemanticTokenType}
+				}
+				return true
+			})
+		}
+	}
+	t.scanGo(e, false, overrides)
+}
+
+// goCode adds the tokens of Go code. If isComponent is true, the code is a component
+// reference, e.g. @pkg.Component(x) or @items..., and the name of the component is added as a
+// function.
+func (t *semanticTokenizer) goCode(e parser.Expression, isComponent bool) {
+	t.scanGo(e, isComponent, nil)
+}
+
+func (t *semanticTokenizer) scanGo(e parser.Expression, isComponent bool, overrides map[int]semanticToken) {
+	type scanned struct {
+		offset int
+		tok    token.Token
+		lit    string
+	}
+	var toks []scanned
+	var s scanner.Scanner
+	file := token.NewFileSet().AddFile("", -1, len(e.Value))
+	s.Init(file, []byte(e.Value), func(token.Position, string) {}, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		toks = append(toks, scanned{offset: file.Offset(pos), tok: tok, lit: lit})
+	}
+	component := -1
+	if isComponent {
+		// The component is the last identifier before the arguments.
+		for i, tok := range toks {
+			if tok.tok == token.LPAREN || tok.tok == token.LBRACK {
+				break
+			}
+			if tok.tok == token.IDENT {
+				component = i
+			}
+		}
+	}
+	base := int(e.Range.From.Index)
+	for i, tok := range toks {
+		st := semanticToken{Start: base + tok.offset, End: base + tok.offset + len(tok.lit)}
+		if tok.tok.IsKeyword() {
+			st.End = st.Start + len(tok.tok.String())
+		}
+		if o, ok := overrides[tok.offset]; ok {
+			st.Type, st.Declaration = o.Type, o.Declaration
+			t.tokens = append(t.tokens, st)
+			continue
+		}
+		switch {
+		case tok.tok.IsKeyword():
+			st.Type = lsp.SemanticTokenKeyword
+		case tok.tok == token.STRING || tok.tok == token.CHAR:
+			st.Type = lsp.SemanticTokenString
+		case tok.tok == token.INT || tok.tok == token.FLOAT || tok.tok == token.IMAG:
+			st.Type = lsp.SemanticTokenNumber
+		case tok.tok == token.COMMENT:
+			st.Type = lsp.SemanticTokenComment
+		case tok.tok == token.IDENT && (i == component || (i+1 < len(toks) && toks[i+1].tok == token.LPAREN)):
+			st.Type = lsp.SemanticTokenFunction
+		case tok.tok == token.IDENT && predeclaredTypes[tok.lit]:
+			st.Type = lsp.SemanticTokenType
+		case tok.tok == token.IDENT:
+			st.Type = lsp.SemanticTokenVariable
+		default:
+			continue
+		}
+		t.tokens = append(t.tokens, st)
+	}
+}
+
+var predeclaredTypes = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true,
+	"complex128": true, "error": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+func isIdentChar(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// encodeSemanticTokens returns the tokens in the relative format of the LSP, where each token
+// is 5 integers: the line, relative to the previous token, the start character, relative to
+// the previous token if it's on the same line, the length, the token type, and the modifiers.
+//
+// Tokens that overlap an earlier token are dropped, and tokens that span multiple lines are
+// split, since not all clients support them. If r isn't nil, only the tokens that start within
+// the range are returned.
+func encodeSemanticTokens(src string, tokens []semanticToken, r *lsp.Range) (data []uint32) {
+	tokens = slices.Clone(tokens)
+	slices.SortStableFunc(tokens, func(a, b semanticToken) int {
+		return a.Start - b.Start
+	})
+	lineStarts := []int{0}
+	for i := range len(src) {
+		if src[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	position := func(offset int) lsp.Position {
+		line := sort.Search(len(lineStarts), func(i int) bool { return lineStarts[i] > offset }) - 1
+		return lsp.Position{Line: uint32(line), Character: utf16Len(src[lineStarts[line]:offset])}
+	}
+	data = []uint32{}
+	var prev lsp.Position
+	var prevEnd int
+	for _, tok := range tokens {
+		if tok.Start < prevEnd || tok.End <= tok.Start || tok.End > len(src) {
+			continue
+		}
+		prevEnd = tok.End
+		typ := uint32(slices.Index(semanticTokensLegend.TokenTypes, tok.Type))
+		var modifiers uint32
+		if tok.Declaration {
+			modifiers = 1 << slices.Index(semanticTokensLegend.TokenModifiers, lsp.SemanticTokenModifierDeclaration)
+		}
+		for start := tok.Start; start < tok.End; {
+			end, next := tok.End, tok.End
+			if i := strings.IndexByte(src[start:tok.End], '\n'); i >= 0 {
+				end, next = start+i, start+i+1
+			}
+			text := strings.TrimRight(src[start:end], "\r")
+			from := position(start)
+			if text != "" && (r == nil || (!isAfter(r.Start, from) && isAfter(r.End, from))) {
+				deltaStart := from.Character
+				if from.Line == prev.Line {
+					deltaStart -= prev.Character
+				}
+				data = append(data, from.Line-prev.Line, deltaStart, utf16Len(text), typ, modifiers)
+				prev = from
+			}
+			start = next
+		}
+	}
+	return data
+}
+
+// utf16Len returns the length of the string in UTF-16 code units, which LSP positions use.
+func utf16Len(s string) (n uint32) {
+	for _, r := range s {
+		n++
+		if r >= 0x10000 {
+			n++
+		}
+	}
+	return n
+}
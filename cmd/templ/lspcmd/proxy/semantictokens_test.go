@@ -0,0 +1,174 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// decodeSemanticTokens returns the tokens as "line:col text type", with a " declaration"
+// suffix for declarations.
+func decodeSemanticTokens(src string, data []uint32) (tokens []string) {
+	lines := strings.Split(src, "\n")
+	var line, col uint32
+	for i := 0; i+4 < len(data); i += 5 {
+		if data[i] > 0 {
+			col = 0
+		}
+		line += data[i]
+		col += data[i+1]
+		token := fmt.Sprintf("%d:%d %s %s", line, col, lines[line][col:col+data[i+2]], semanticTokensLegend.TokenTypes[data[i+3]])
+		if data[i+4] != 0 {
+			token += " declaration"
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func TestSemanticTokens(t *testing.T) {
+	src := `package main
+
+templ Page(title string, items []Item) {
+	<div class="list" id={ title } hidden?={ len(items) == 0 }>
+		// Items.
+		if len(items) > 0 {
+			for _, item := range items {
+				@components.Card(item.Name)
+			}
+		} else {
+			{ "none" }
+		}
+	</div>
+}
+`
+	tf, err := parser.ParseString(src)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	expected := []string{
+		"0:0 package keyword",
+		"0:8 main variable",
+		"2:0 templ keyword",
+		"2:6 Page function declaration",
+		"2:11 title parameter declaration",
+		"2:17 string type",
+		"2:25 items parameter declaration",
+		"2:33 Item type",
+		"3:6 class property",
+		"3:12 \"list\" string",
+		"3:19 id property",
+		"3:24 title variable",
+		"3:32 hidden property",
+		"3:42 len function",
+		"3:46 items variable",
+		"3:56 0 number",
+		"4:2 // Items. comment",
+		"5:2 if keyword",
+		"5:5 len function",
+		"5:9 items variable",
+		"5:18 0 number",
+		"6:3 for keyword",
+		"6:7 _ variable",
+		"6:10 item variable",
+		"6:18 range keyword",
+		"6:24 items variable",
+		"7:5 components variable",
+		"7:16 Card function",
+		"7:21 item variable",
+		"7:26 Name variable",
+		"9:4 else keyword",
+		"10:5 \"none\" string",
+	}
+	actual := decodeSemanticTokens(src, encodeSemanticTokens(src, semanticTokens(src, tf), nil))
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+
+	t.Run("tokens can be limited to a range", func(t *testing.T) {
+		r := &lsp.Range{
+			Start: lsp.Position{Line: 9},
+			End:   lsp.Position{Line: 11},
+		}
+		data := encodeSemanticTokens(src, semanticTokens(src, tf), r)
+		// The first token is relative to the start of the document.
+		expected := []string{
+			"9:4 else keyword",
+			"10:5 \"none\" string",
+		}
+		if diff := cmp.Diff(expected, decodeSemanticTokens(src, data)); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestSemanticTokensOfTemplates(t *testing.T) {
+	src := "package main\n\ncss red() {\n\tcolor: red;\n}\n\nscript alert(msg string) {\n\talert(msg);\n}\n\ntempl (p Page) Header() extends Base() {\n}\n"
+	tf, err := parser.ParseString(src)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	expected := []string{
+		"0:0 package keyword",
+		"0:8 main variable",
+		"2:0 css keyword",
+		"2:4 red function declaration",
+		"6:0 script keyword",
+		"6:7 alert function declaration",
+		"6:13 msg parameter declaration",
+		"6:17 string type",
+		"10:0 templ keyword",
+		"10:7 p parameter declaration",
+		"10:9 Page type",
+		"10:15 Header function declaration",
+		"10:24 extends keyword",
+		"10:32 Base function",
+	}
+	actual := decodeSemanticTokens(src, encodeSemanticTokens(src, semanticTokens(src, tf), nil))
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestEncodeSemanticTokens(t *testing.T) {
+	src := "a `b\nc` 😀 d"
+	tokens := []semanticToken{
+		{Start: 0, End: 1, Type: lsp.SemanticTokenVariable},
+		// Overlapping tokens are dropped.
+		{Start: 0, End: 1, Type: lsp.SemanticTokenKeyword},
+		// Multi-line tokens are split.
+		{Start: 2, End: 7, Type: lsp.SemanticTokenString},
+		// Characters are counted in UTF-16 code units.
+		{Start: 13, End: 14, Type: lsp.SemanticTokenVariable},
+	}
+	expected := []uint32{
+		0, 0, 1, 2, 0,
+		0, 2, 2, 6, 0,
+		1, 0, 2, 6, 0,
+		0, 6, 1, 2, 0,
+	}
+	if diff := cmp.Diff(expected, encodeSemanticTokens(src, tokens, nil)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSemanticTokensOfIncompleteSignatures(t *testing.T) {
+	for _, signature := range []string{"templ foo(", "templ foo() extends", "templ foo() extends {"} {
+		t.Run(signature, func(t *testing.T) {
+			src := "package main\n\n" + signature + "\n"
+			tf, _ := parser.ParsePartial(src)
+			expected := []string{
+				"0:0 package keyword",
+				"0:8 main variable",
+			}
+			actual := decodeSemanticTokens(src, encodeSemanticTokens(src, semanticTokens(src, tf), nil))
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -5,17 +5,21 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
-	"github.com/a-h/parse"
 	"github.com/a-h/templ/internal/lazyloader"
+	"github.com/a-h/templ/lint"
 	lsp "github.com/a-h/templ/lsp/protocol"
 	"github.com/a-h/templ/lsp/uri"
 
 	"github.com/a-h/templ"
+	"github.com/a-h/templ/cmd/templ/fmtcmd"
 	"github.com/a-h/templ/cmd/templ/imports"
+	"github.com/a-h/templ/config"
 	"github.com/a-h/templ/generator"
 	"github.com/a-h/templ/parser/v2"
 )
@@ -35,13 +39,17 @@ import (
 // inverse operation - to put the file names back, and readjust any
 // character positions.
 type Server struct {
-	Log                *slog.Logger
-	Target             lsp.Server
-	SourceMapCache     *SourceMapCache
-	DiagnosticCache    *DiagnosticCache
-	TemplSource        *DocumentContents
-	GoSource           map[string]string
-	NoPreload          bool
+	Log             *slog.Logger
+	Target          lsp.Server
+	SourceMapCache  *SourceMapCache
+	DiagnosticCache *DiagnosticCache
+	TemplSource     *DocumentContents
+	GoSource        map[string]string
+	NoPreload       bool
+	// Lint checks templ files, and its diagnostics are published with their severity, see lint.New.
+	Lint lint.Linter
+	// Deprecations are the deprecated templates of each templ file, by URI.
+	Deprecations       map[string][]generator.Deprecation
 	preLoadURIs        []*lsp.DidOpenTextDocumentParams
 	templDocLazyLoader lazyloader.TemplDocLazyLoader
 }
@@ -55,6 +63,7 @@ func NewServer(log *slog.Logger, target lsp.Server, cache *SourceMapCache, diagn
 		TemplSource:     newDocumentContents(log),
 		GoSource:        make(map[string]string),
 		NoPreload:       noPreload,
+		Deprecations:    make(map[string][]generator.Deprecation),
 	}
 }
 
@@ -130,57 +139,132 @@ func (p *Server) convertGoRangeToTemplRange(templURI lsp.DocumentURI, input lsp.
 	return
 }
 
-// parseTemplate parses the templ file content, and notifies the end user via the LSP about how it went.
-func (p *Server) parseTemplate(ctx context.Context, uri uri.URI, templateText string) (template *parser.TemplateFile, ok bool, err error) {
-	template, err = parser.ParseString(templateText)
-	if err != nil {
-		msg := &lsp.PublishDiagnosticsParams{
-			URI: uri,
-			Diagnostics: []lsp.Diagnostic{
-				{
-					Severity: lsp.DiagnosticSeverityError,
-					Code:     "",
-					Source:   "templ",
-					Message:  err.Error(),
-				},
-			},
+// convertWorkspaceEdit converts the edits of generated Go files in a workspace edit from gopls,
+// e.g. a rename, to edits of the templ files they were generated from. This includes the names
+// of css and script templates, and the expressions that use them, e.g. class={ name() }.
+//
+// Edits of generated code that isn't written in a templ file are dropped, because the code is
+// updated when the templ file is generated again. If the source map of a templ file isn't
+// available, because the file was closed, an error is returned rather than applying part of
+// the edit, which would leave the templ files inconsistent until they're fixed by hand.
+func (p *Server) convertWorkspaceEdit(input *lsp.WorkspaceEdit) (output *lsp.WorkspaceEdit, err error) {
+	output = &lsp.WorkspaceEdit{
+		ChangeAnnotations: input.ChangeAnnotations,
+	}
+	if input.Changes != nil {
+		output.Changes = make(map[lsp.DocumentURI][]lsp.TextEdit, len(input.Changes))
+	}
+	var unavailable []string
+	for uri, edits := range input.Changes {
+		isTemplURI, templURI := convertTemplGoToTemplURI(uri)
+		if !isTemplURI {
+			output.Changes[uri] = edits
+			continue
 		}
-		if pe, isParserError := err.(parse.ParseError); isParserError {
-			msg.Diagnostics[0].Range = lsp.Range{
-				Start: lsp.Position{
-					Line:      uint32(pe.Pos.Line),
-					Character: uint32(pe.Pos.Col),
-				},
-				End: lsp.Position{
-					Line:      uint32(pe.Pos.Line),
-					Character: uint32(pe.Pos.Col),
-				},
-			}
+		var ok bool
+		if edits, ok = p.convertGoTextEdits(templURI, edits); !ok {
+			unavailable = append(unavailable, string(templURI))
+			continue
 		}
-		msg.Diagnostics = p.DiagnosticCache.AddGoDiagnostics(string(uri), msg.Diagnostics)
-		err = lsp.ClientFromContext(ctx).PublishDiagnostics(ctx, msg)
-		if err != nil {
-			p.Log.Error("failed to publish error diagnostics", slog.Any("error", err))
+		if len(edits) > 0 {
+			output.Changes[templURI] = edits
 		}
-		// If the template was even partially parsed, it's still potentially useful.
-		if template != nil {
-			template.Filepath = string(uri)
+	}
+	for _, dc := range input.DocumentChanges {
+		isTemplURI, templURI := convertTemplGoToTemplURI(dc.TextDocument.URI)
+		if !isTemplURI {
+			output.DocumentChanges = append(output.DocumentChanges, dc)
+			continue
 		}
-		return
+		var ok bool
+		if dc.Edits, ok = p.convertGoTextEdits(templURI, dc.Edits); !ok {
+			unavailable = append(unavailable, string(templURI))
+			continue
+		}
+		if len(dc.Edits) == 0 {
+			continue
+		}
+		// The version of the Go file isn't the version of the templ file.
+		dc.TextDocument.URI, dc.TextDocument.Version = templURI, nil
+		output.DocumentChanges = append(output.DocumentChanges, dc)
 	}
-	template.Filepath = string(uri)
-	parsedDiagnostics, err := parser.Diagnose(template)
+	if len(unavailable) > 0 {
+		slices.Sort(unavailable)
+		return nil, fmt.Errorf("cannot update %s, open the files and try again", strings.Join(unavailable, ", "))
+	}
+	return output, nil
+}
+
+// convertGoTextEdits converts edits of a generated Go file to edits of the templ file. Edits
+// that aren't within Go code written in the templ file are dropped. If the source map of the
+// templ file isn't available, ok is false.
+func (p *Server) convertGoTextEdits(templURI lsp.DocumentURI, edits []lsp.TextEdit) (output []lsp.TextEdit, ok bool) {
+	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
+	if !ok {
+		p.Log.Warn("go->templ: sourcemap not found in cache", slog.String("uri", string(templURI)))
+		return nil, false
+	}
+	seen := make(map[lsp.Range]struct{}, len(edits))
+	for _, e := range edits {
+		src, ok := sourceMap.SourceRangeFromTarget(parser.Range{
+			From: parser.NewPosition(0, e.Range.Start.Line, e.Range.Start.Character),
+			To:   parser.NewPosition(0, e.Range.End.Line, e.Range.End.Character),
+		})
+		if !ok {
+			p.Log.Info("go->templ: dropping edit of generated code", slog.Any("range", e.Range))
+			continue
+		}
+		e.Range = lsp.Range{
+			Start: lsp.Position{Line: src.From.Line, Character: src.From.Col},
+			End:   lsp.Position{Line: src.To.Line, Character: src.To.Col},
+		}
+		// Expressions can be written to the generated code more than once.
+		if _, ok := seen[e.Range]; ok {
+			continue
+		}
+		seen[e.Range] = struct{}{}
+		output = append(output, e)
+	}
+	return output, true
+}
+
+// deprecationDiagnostics returns a diagnostic for each use of a deprecated template of the
+// package in the templ file, shown with strikethrough by editors. The deprecated templates of
+// the file are updated first.
+func (p *Server) deprecationDiagnostics(uri uri.URI, template *parser.TemplateFile) (diagnostics []lsp.Diagnostic) {
+	deprecations, err := generator.Deprecations(template)
 	if err != nil {
-		return
+		// Invalid directives are reported when the template is generated.
+		p.Log.Info("deprecations", slog.Any("error", err))
+	}
+	p.Deprecations[string(uri)] = deprecations
+	var pkg []generator.Deprecation
+	for u, d := range p.Deprecations {
+		if path.Dir(u) == path.Dir(string(uri)) {
+			pkg = append(pkg, d...)
+		}
 	}
-	ok = true
-	if len(parsedDiagnostics) > 0 {
+	for _, d := range generator.DeprecatedUses(template, pkg) {
+		diagnostic := templDiagnostic(d, lsp.DiagnosticSeverityHint, "deprecated")
+		diagnostic.Tags = []lsp.DiagnosticTag{lsp.DiagnosticTagDeprecated}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// parseTemplate parses the templ file content, and notifies the end user via the LSP about how it went.
+// If the template has syntax errors, the parts of it that could be parsed are returned, so that
+// completions and symbols keep working while it's being edited.
+func (p *Server) parseTemplate(ctx context.Context, uri uri.URI, templateText string) (template *parser.TemplateFile, ok bool, err error) {
+	template, syntaxErrors := parser.ParsePartial(templateText)
+	template.Filepath = string(uri)
+	if len(syntaxErrors) > 0 {
 		msg := &lsp.PublishDiagnosticsParams{
 			URI: uri,
 		}
-		for _, d := range parsedDiagnostics {
+		for _, d := range syntaxErrors {
 			msg.Diagnostics = append(msg.Diagnostics, lsp.Diagnostic{
-				Severity: lsp.DiagnosticSeverityWarning,
+				Severity: lsp.DiagnosticSeverityError,
 				Code:     "",
 				Source:   "templ",
 				Message:  d.Message,
@@ -203,6 +287,33 @@ func (p *Server) parseTemplate(ctx context.Context, uri uri.URI, templateText st
 		}
 		return
 	}
+	parsedDiagnostics, err := parser.Diagnose(template)
+	if err != nil {
+		return
+	}
+	diagnostics := make([]lsp.Diagnostic, 0, len(parsedDiagnostics))
+	for _, d := range parsedDiagnostics {
+		diagnostics = append(diagnostics, templDiagnostic(d, lsp.DiagnosticSeverityWarning, ""))
+	}
+	if p.Lint != nil {
+		for _, d := range p.Lint(template) {
+			diagnostics = append(diagnostics, templDiagnostic(d.Diagnostic, lsp.DiagnosticSeverity(d.Severity), d.Rule))
+		}
+	}
+	diagnostics = append(diagnostics, p.deprecationDiagnostics(uri, template)...)
+	ok = true
+	if len(diagnostics) > 0 {
+		msg := &lsp.PublishDiagnosticsParams{
+			URI:         uri,
+			Diagnostics: diagnostics,
+		}
+		msg.Diagnostics = p.DiagnosticCache.AddGoDiagnostics(string(uri), msg.Diagnostics)
+		err = lsp.ClientFromContext(ctx).PublishDiagnostics(ctx, msg)
+		if err != nil {
+			p.Log.Error("failed to publish error diagnostics", slog.Any("error", err))
+		}
+		return
+	}
 	// Clear templ diagnostics.
 	p.DiagnosticCache.ClearTemplDiagnostics(string(uri))
 	err = lsp.ClientFromContext(ctx).PublishDiagnostics(ctx, &lsp.PublishDiagnosticsParams{
@@ -217,6 +328,26 @@ func (p *Server) parseTemplate(ctx context.Context, uri uri.URI, templateText st
 	return
 }
 
+// templDiagnostic converts a diagnostic of a templ file to an LSP diagnostic.
+func templDiagnostic(d parser.Diagnostic, severity lsp.DiagnosticSeverity, code string) lsp.Diagnostic {
+	return lsp.Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Source:   "templ",
+		Message:  d.Message,
+		Range: lsp.Range{
+			Start: lsp.Position{
+				Line:      uint32(d.Range.From.Line),
+				Character: uint32(d.Range.From.Col),
+			},
+			End: lsp.Position{
+				Line:      uint32(d.Range.To.Line),
+				Character: uint32(d.Range.To.Col),
+			},
+		},
+	}
+}
+
 func (p *Server) Initialize(ctx context.Context, params *lsp.InitializeParams) (result *lsp.InitializeResult, err error) {
 	p.Log.Info("client -> server: Initialize")
 	defer p.Log.Info("client -> server: Initialize end")
@@ -228,15 +359,23 @@ func (p *Server) Initialize(ctx context.Context, params *lsp.InitializeParams) (
 	if result.Capabilities.CompletionProvider == nil {
 		result.Capabilities.CompletionProvider = &lsp.CompletionOptions{}
 	}
-	result.Capabilities.CompletionProvider.TriggerCharacters = append(result.Capabilities.CompletionProvider.TriggerCharacters, "{", "<")
+	result.Capabilities.CompletionProvider.TriggerCharacters = append(result.Capabilities.CompletionProvider.TriggerCharacters, "{", "<", "\"")
 	// Remove all the gopls commands.
 	if result.Capabilities.ExecuteCommandProvider == nil {
 		result.Capabilities.ExecuteCommandProvider = &lsp.ExecuteCommandOptions{}
 	}
 	result.Capabilities.ExecuteCommandProvider.Commands = []string{}
 	result.Capabilities.DocumentFormattingProvider = true
-	result.Capabilities.SemanticTokensProvider = nil
-	result.Capabilities.DocumentRangeFormattingProvider = false
+	result.Capabilities.SemanticTokensProvider = &lsp.SemanticTokensOptions{
+		Legend: semanticTokensLegend,
+		Range:  true,
+		Full:   true,
+	}
+	result.Capabilities.DocumentRangeFormattingProvider = true
+	result.Capabilities.DocumentOnTypeFormattingProvider = &lsp.DocumentOnTypeFormattingOptions{
+		FirstTriggerCharacter: "}",
+		MoreTriggerCharacter:  []string{">"},
+	}
 	result.Capabilities.TextDocumentSync = lsp.TextDocumentSyncOptions{
 		OpenClose:         true,
 		Change:            lsp.TextDocumentSyncKindFull,
@@ -379,17 +518,20 @@ func (p *Server) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (
 		return p.Target.CodeAction(ctx, params)
 	}
 	templURI := params.TextDocument.URI
+	var updatedResults []lsp.CodeAction
+	if action, ok := p.extractComponentAction(ctx, templURI, params.Range); ok {
+		updatedResults = append(updatedResults, action)
+	}
 	var ok bool
 	if params.Range, ok = p.convertTemplRangeToGoRange(templURI, params.Range); !ok {
 		// Don't pass the request to gopls if the range is not within a Go code block.
-		return
+		return updatedResults, nil
 	}
 	params.TextDocument.URI = goURI
 	result, err = p.Target.CodeAction(ctx, params)
 	if err != nil {
 		return
 	}
-	var updatedResults []lsp.CodeAction
 	// Filter out commands that are not yet supported.
 	// For example, "Fill Struct" runs the `gopls.apply_fix` command.
 	// This command has a set of arguments, including Fix, Range and URI.
@@ -418,6 +560,56 @@ func (p *Server) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (
 	return updatedResults, nil
 }
 
+// extractComponentAction returns a refactoring that extracts the selected nodes of a template
+// into a new component, if the selection is a sequence of complete nodes.
+func (p *Server) extractComponentAction(ctx context.Context, templURI lsp.DocumentURI, r lsp.Range) (action lsp.CodeAction, ok bool) {
+	if r.Start == r.End {
+		return action, false
+	}
+	doc, ok := p.TemplSource.Get(string(templURI))
+	if !ok {
+		return action, false
+	}
+	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
+	if !ok {
+		return action, false
+	}
+	e, ok := extractComponent(doc.String(), p.GoSource[string(templURI)], sourceMap, r)
+	if !ok {
+		return action, false
+	}
+	// The types of variables that aren't template parameters, e.g. the items of loops over
+	// imported types, are found with gopls.
+	_, goURI := convertTemplToGoURI(templURI)
+	for i, param := range e.Params {
+		if param.Type != "" {
+			continue
+		}
+		hover, err := p.Target.Hover(ctx, &lsp.HoverParams{
+			TextDocumentPositionParams: lsp.TextDocumentPositionParams{
+				TextDocument: lsp.TextDocumentIdentifier{URI: goURI},
+				Position:     param.Decl,
+			},
+		})
+		if err != nil || hover == nil {
+			p.Log.Info("extract component: type not found", slog.String("param", param.Name), slog.Any("error", err))
+			continue
+		}
+		if m := hoverType.FindStringSubmatch(hover.Contents.Value); m != nil {
+			e.Params[i].Type = m[1]
+		}
+	}
+	return lsp.CodeAction{
+		Title: "Extract component",
+		Kind:  lsp.RefactorExtract,
+		Edit: &lsp.WorkspaceEdit{
+			Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+				templURI: e.Edits(),
+			},
+		},
+	}, true
+}
+
 func (p *Server) CodeLens(ctx context.Context, params *lsp.CodeLensParams) (result []lsp.CodeLens, err error) {
 	p.Log.Info("client -> server: CodeLens")
 	defer p.Log.Info("client -> server: CodeLens end")
@@ -475,18 +667,32 @@ func (p *Server) ColorPresentation(ctx context.Context, params *lsp.ColorPresent
 func (p *Server) Completion(ctx context.Context, params *lsp.CompletionParams) (result *lsp.CompletionList, err error) {
 	p.Log.Info("client -> server: Completion")
 	defer p.Log.Info("client -> server: Completion end")
+	templURI := params.TextDocument.URI
+	var htmlItems []lsp.CompletionItem
+	if doc, ok := p.TemplSource.Get(string(templURI)); ok {
+		htmlItems = htmlCompletion(doc.Lines, params.Position)
+	}
 	if params.Context != nil && params.Context.TriggerCharacter == "<" {
 		result = &lsp.CompletionList{
 			Items: htmlSnippets,
 		}
+		// Add the elements that don't have a snippet.
+		for _, item := range htmlItems {
+			if !slices.ContainsFunc(htmlSnippets, func(s lsp.CompletionItem) bool { return s.Label == item.Label }) {
+				result.Items = append(result.Items, item)
+			}
+		}
 		return
 	}
 	// Get the sourcemap from the cache.
-	templURI := params.TextDocument.URI
 	var ok bool
 	ok, params.TextDocument.URI, params.Position = p.updatePosition(templURI, params.Position)
 	if !ok {
-		return nil, nil
+		// The position isn't within Go code, so complete HTML instead.
+		if len(htmlItems) == 0 {
+			return nil, nil
+		}
+		return &lsp.CompletionList{Items: htmlItems}, nil
 	}
 
 	// Ensure that Go source is available.
@@ -995,7 +1201,7 @@ func (p *Server) Formatting(ctx context.Context, params *lsp.DocumentFormattingP
 		return
 	}
 	w := new(strings.Builder)
-	err = template.Write(w)
+	err = template.WriteStyle(w, p.formatStyle(params.TextDocument.URI))
 	if err != nil {
 		p.Log.Error("handleFormatting: faled to write template", slog.Any("error", err))
 		return
@@ -1012,15 +1218,30 @@ func (p *Server) Formatting(ctx context.Context, params *lsp.DocumentFormattingP
 	return
 }
 
+// formatStyle returns the style that the templ file is formatted with, set by the templ.toml
+// file of its project, see config.Find.
+func (p *Server) formatStyle(templURI lsp.DocumentURI) parser.FormatStyle {
+	if !strings.HasPrefix(string(templURI), uri.FileScheme+"://") {
+		return config.Default().Format.Style()
+	}
+	cfg, err := config.Find(filepath.Dir(uri.URI(templURI).Filename()))
+	if err != nil {
+		p.Log.Error("failed to read formatting configuration, using the default", slog.Any("error", err))
+		return config.Default().Format.Style()
+	}
+	return cfg.Format.Style()
+}
+
 func (p *Server) Hover(ctx context.Context, params *lsp.HoverParams) (result *lsp.Hover, err error) {
 	p.Log.Info("client -> server: Hover")
 	defer p.Log.Info("client -> server: Hover end")
 	// Rewrite the request.
 	templURI := params.TextDocument.URI
+	templPosition := params.Position
 	var ok bool
 	ok, params.TextDocument.URI, params.Position = p.updatePosition(params.TextDocument.URI, params.Position)
 	if !ok {
-		return nil, nil
+		return p.htmlPreviewHover(templURI, templPosition), nil
 	}
 	// Call gopls.
 	result, err = p.Target.Hover(ctx, params)
@@ -1037,6 +1258,30 @@ func (p *Server) Hover(ctx context.Context, params *lsp.HoverParams) (result *ls
 	return
 }
 
+// htmlPreviewHover returns a hover that shows the HTML rendered by the element, or template,
+// at the position, if its content is constant.
+func (p *Server) htmlPreviewHover(templURI lsp.DocumentURI, pos lsp.Position) *lsp.Hover {
+	d, ok := p.TemplSource.Get(string(templURI))
+	if !ok {
+		return nil
+	}
+	tf, err := parser.ParseString(d.String())
+	if err != nil {
+		return nil
+	}
+	html, r, ok := htmlPreview(tf, pos)
+	if !ok {
+		return nil
+	}
+	return &lsp.Hover{
+		Contents: lsp.MarkupContent{
+			Kind:  lsp.Markdown,
+			Value: "Renders:\n\n```html\n" + html + "\n```",
+		},
+		Range: &r,
+	}
+}
+
 func (p *Server) Implementation(ctx context.Context, params *lsp.ImplementationParams) (result []lsp.Location, err error) {
 	p.Log.Info("client -> server: Implementation")
 	defer p.Log.Info("client -> server: Implementation end")
@@ -1066,38 +1311,71 @@ func (p *Server) Implementation(ctx context.Context, params *lsp.ImplementationP
 func (p *Server) OnTypeFormatting(ctx context.Context, params *lsp.DocumentOnTypeFormattingParams) (result []lsp.TextEdit, err error) {
 	p.Log.Info("client -> server: OnTypeFormatting")
 	defer p.Log.Info("client -> server: OnTypeFormatting end")
-	templURI := params.TextDocument.URI
-	// Rewrite the request.
-	var ok bool
-	ok, params.TextDocument.URI, params.Position = p.updatePosition(params.TextDocument.URI, params.Position)
+	// Typing the } or > that closes a block or element formats the lines of the block.
+	d, ok := p.TemplSource.Get(string(params.TextDocument.URI))
 	if !ok {
 		return nil, nil
 	}
-	// Get the response.
-	result, err = p.Target.OnTypeFormatting(ctx, params)
-	if err != nil {
-		return
-	}
-	if result == nil {
-		return
-	}
-	// Rewrite the response.
-	for i, r := range result {
-		r.Range = p.convertGoRangeToTemplRange(templURI, r.Range)
-		result[i] = r
+	line := int(params.Position.Line)
+	r := fmtcmd.LineRange{Start: blockStartLine(d.String(), line, params.Ch) + 1, End: line + 1}
+	return p.formatRange(params.TextDocument.URI, r), nil
+}
+
+// blockStartLine returns the line of the start of the block that's closed by the } or > typed
+// on the line, i.e. the line of the { that matches it, or of the start tag of the element that
+// it closes. If there isn't one, the line itself is returned. Lines are numbered from 0.
+func blockStartLine(src string, line int, ch string) int {
+	lines := strings.Split(src, "\n")
+	if line >= len(lines) {
+		return line
+	}
+	var open, close *regexp.Regexp
+	switch ch {
+	case "}":
+		open, close = regexp.MustCompile(`\{`), regexp.MustCompile(`\}`)
+	case ">":
+		m := endTagExpression.FindAllStringSubmatch(lines[line], -1)
+		if m == nil {
+			return line
+		}
+		name := regexp.QuoteMeta(m[len(m)-1][1])
+		open, close = regexp.MustCompile(`<`+name+`[\s/>]`), regexp.MustCompile(`</`+name+`\s*>`)
+	default:
+		return line
+	}
+	depth := 0
+	for l := line; l >= 0; l-- {
+		// Count the tokens of the line from its end.
+		opens, closes := open.FindAllStringIndex(lines[l], -1), close.FindAllStringIndex(lines[l], -1)
+		for len(opens) > 0 || len(closes) > 0 {
+			if len(closes) > 0 && (len(opens) == 0 || closes[len(closes)-1][0] > opens[len(opens)-1][0]) {
+				closes = closes[:len(closes)-1]
+				depth++
+				continue
+			}
+			opens = opens[:len(opens)-1]
+			if depth--; depth <= 0 {
+				return l
+			}
+		}
 	}
-	return
+	return line
 }
 
+var endTagExpression = regexp.MustCompile(`</([a-zA-Z][^\s/>]*)\s*>`)
+
 func (p *Server) PrepareRename(ctx context.Context, params *lsp.PrepareRenameParams) (result *lsp.Range, err error) {
 	p.Log.Info("client -> server: PrepareRename")
 	defer p.Log.Info("client -> server: PrepareRename end")
 	templURI := params.TextDocument.URI
 	// Rewrite the request.
 	var ok bool
-	ok, params.TextDocument.URI, params.Position = p.updatePosition(params.TextDocument.URI, params.Position)
+	ok, params.TextDocument.URI, params.Position = p.updatePosition(templURI, params.Position)
 	if !ok {
-		return nil, nil
+		// Rename a template from its keyword, e.g. templ or css.
+		if ok, params.TextDocument.URI, params.Position = p.declarationPosition(templURI, params.Position); !ok {
+			return nil, nil
+		}
 	}
 	// Get the response.
 	result, err = p.Target.PrepareRename(ctx, params)
@@ -1107,43 +1385,88 @@ func (p *Server) PrepareRename(ctx context.Context, params *lsp.PrepareRenamePar
 	if result == nil {
 		return
 	}
-	// Rewrite the response.
-	output := p.convertGoRangeToTemplRange(templURI, *result)
+	// Rewrite the response. Identifiers that aren't written in the templ file can't be renamed.
+	output, ok := p.convertGoReferenceRange(templURI, *result)
+	if !ok {
+		return nil, nil
+	}
 	return &output, nil
 }
 
 func (p *Server) RangeFormatting(ctx context.Context, params *lsp.DocumentRangeFormattingParams) (result []lsp.TextEdit, err error) {
 	p.Log.Info("client -> server: RangeFormatting")
 	defer p.Log.Info("client -> server: RangeFormatting end")
-	templURI := params.TextDocument.URI
-	// Rewrite the request.
-	var isTemplURI bool
-	isTemplURI, params.TextDocument.URI = convertTemplToGoURI(params.TextDocument.URI)
-	if !isTemplURI {
-		err = fmt.Errorf("not a templ file")
-		return
+	r := fmtcmd.LineRange{Start: int(params.Range.Start.Line) + 1, End: int(params.Range.End.Line) + 1}
+	// A selection of whole lines ends at the start of the next line.
+	if params.Range.End.Character == 0 && params.Range.End.Line > params.Range.Start.Line {
+		r.End--
 	}
-	// Call gopls.
-	result, err = p.Target.RangeFormatting(ctx, params)
+	return p.formatRange(params.TextDocument.URI, r), nil
+}
+
+// formatRange formats the lines in the range, leaving the rest of the document unchanged, and
+// returns an edit of the lines that changed. Documents that can't be parsed, e.g. while a block
+// is being typed, aren't formatted.
+func (p *Server) formatRange(templURI lsp.DocumentURI, r fmtcmd.LineRange) []lsp.TextEdit {
+	d, ok := p.TemplSource.Get(string(templURI))
+	if !ok {
+		return nil
+	}
+	src := d.String()
+	template, err := parser.ParseString(src)
 	if err != nil {
-		return
+		p.Log.Info("formatRange: skipping document that can't be parsed", slog.Any("error", err))
+		return nil
 	}
-	// Rewrite the response.
-	for i, r := range result {
-		r.Range = p.convertGoRangeToTemplRange(templURI, r.Range)
-		result[i] = r
+	formatted, err := fmtcmd.FormatRange(src, template, r, p.formatStyle(templURI))
+	if err != nil {
+		p.Log.Error("formatRange: failed to format", slog.Any("error", err))
+		return nil
 	}
-	return result, err
+	edit, changed := changedLinesEdit(src, formatted)
+	if !changed {
+		return nil
+	}
+	d.Replace(formatted)
+	return []lsp.TextEdit{edit}
+}
+
+// changedLinesEdit returns an edit that replaces the lines that differ between before and after,
+// so that the position of the cursor is kept when the lines before it are unchanged.
+func changedLinesEdit(before, after string) (edit lsp.TextEdit, changed bool) {
+	if before == after {
+		return edit, false
+	}
+	b, a := strings.SplitAfter(before, "\n"), strings.SplitAfter(after, "\n")
+	var prefix int
+	for prefix < len(b) && prefix < len(a) && b[prefix] == a[prefix] {
+		prefix++
+	}
+	var suffix int
+	for suffix < len(b)-prefix && suffix < len(a)-prefix && b[len(b)-1-suffix] == a[len(a)-1-suffix] {
+		suffix++
+	}
+	return lsp.TextEdit{
+		Range: lsp.Range{
+			Start: lsp.Position{Line: uint32(prefix)},
+			End:   lsp.Position{Line: uint32(len(b) - suffix)},
+		},
+		NewText: strings.Join(a[prefix:len(a)-suffix], ""),
+	}, true
 }
 
 func (p *Server) References(ctx context.Context, params *lsp.ReferenceParams) (result []lsp.Location, err error) {
 	p.Log.Info("client -> server: References")
 	defer p.Log.Info("client -> server: References end")
 	// Rewrite the request.
+	templURI := params.TextDocument.URI
 	var ok bool
-	ok, params.TextDocument.URI, params.Position = p.updatePosition(params.TextDocument.URI, params.Position)
+	ok, params.TextDocument.URI, params.Position = p.updatePosition(templURI, params.Position)
 	if !ok {
-		return nil, nil
+		// Find the references of a template from its keyword, e.g. templ or css.
+		if ok, params.TextDocument.URI, params.Position = p.declarationPosition(templURI, params.Position); !ok {
+			return nil, nil
+		}
 	}
 	// Call gopls.
 	result, err = p.Target.References(ctx, params)
@@ -1151,22 +1474,125 @@ func (p *Server) References(ctx context.Context, params *lsp.ReferenceParams) (r
 		return
 	}
 	// Rewrite the response.
-	for i, r := range result {
+	return p.convertReferences(result), nil
+}
+
+// goDeclarationName matches the start of the Go declaration of a template, up to its name.
+var goDeclarationName = regexp.MustCompile(`^(?:func|var)\s+(?:\([^)]*\)\s*)?`)
+
+// declarationPosition returns the position of the name of the generated Go function, or
+// variable, of the template declared on the line of the templ file position, using the symbol
+// ranges of the source map.
+func (p *Server) declarationPosition(templURI lsp.DocumentURI, current lsp.Position) (ok bool, goURI lsp.DocumentURI, updated lsp.Position) {
+	var isTemplFile bool
+	if isTemplFile, goURI = convertTemplToGoURI(templURI); !isTemplFile {
+		return false, templURI, current
+	}
+	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
+	if !ok {
+		return false, templURI, current
+	}
+	var symbol parser.Range
+	ok = false
+	for col, tgt := range sourceMap.SourceSymbolRangeToTarget[current.Line] {
+		if col <= current.Character {
+			symbol, ok = tgt, true
+			break
+		}
+	}
+	if !ok {
+		return false, templURI, current
+	}
+	goLines := strings.Split(p.GoSource[string(templURI)], "\n")
+	if int(symbol.From.Line) >= len(goLines) || int(symbol.From.Col) > len(goLines[symbol.From.Line]) {
+		return false, templURI, current
+	}
+	prefix := goDeclarationName.FindString(goLines[symbol.From.Line][symbol.From.Col:])
+	if prefix == "" {
+		return false, templURI, current
+	}
+	updated.Line = symbol.From.Line
+	updated.Character = symbol.From.Col + uint32(len(prefix))
+	return true, goURI, updated
+}
+
+// convertReferences converts the locations of references in generated Go files from gopls to
+// locations in the templ files they were generated from, so that a component's references
+// include both the @Component expressions of templ files and the Go code that calls it.
+//
+// References within generated code that isn't written in a templ file are dropped.
+func (p *Server) convertReferences(input []lsp.Location) (output []lsp.Location) {
+	seen := make(map[lsp.Location]struct{}, len(input))
+	for i, r := range input {
 		isTemplURI, templURI := convertTemplGoToTemplURI(r.URI)
 		if isTemplURI {
-			p.Log.Info(fmt.Sprintf("references-%d - range conversion for %s", i, r.URI))
-			r.URI, r.Range = templURI, p.convertGoRangeToTemplRange(templURI, r.Range)
+			var ok bool
+			if r.Range, ok = p.convertGoReferenceRange(templURI, r.Range); !ok {
+				p.Log.Info(fmt.Sprintf("references-%d: dropping reference in generated code", i), slog.Any("range", r.Range))
+				continue
+			}
+			r.URI = templURI
+		}
+		// Expressions can be written to the generated code more than once.
+		if _, ok := seen[r]; ok {
+			continue
 		}
+		seen[r] = struct{}{}
 		p.Log.Info(fmt.Sprintf("references-%d: %+v", i, r))
-		result[i] = r
+		output = append(output, r)
 	}
-	return result, err
+	return output
+}
+
+// convertGoReferenceRange converts the range of an identifier in a generated Go file to the
+// range in the templ file.
+func (p *Server) convertGoReferenceRange(templURI lsp.DocumentURI, input lsp.Range) (output lsp.Range, ok bool) {
+	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
+	if !ok {
+		p.Log.Warn("go->templ: sourcemap not found in cache", slog.String("uri", string(templURI)))
+		return input, false
+	}
+	tgt := parser.Range{
+		From: parser.NewPosition(0, input.Start.Line, input.Start.Character),
+		To:   parser.NewPosition(0, input.End.Line, input.End.Character),
+	}
+	if src, ok := sourceMap.SourceRangeFromTarget(tgt); ok {
+		return lsp.Range{
+			Start: lsp.Position{Line: src.From.Line, Character: src.From.Col},
+			End:   lsp.Position{Line: src.To.Line, Character: src.To.Col},
+		}, true
+	}
+	// The end of an identifier at the end of an expression, e.g. @Component, isn't mapped.
+	from, ok := sourceMap.TargetLinesToSource[input.Start.Line][input.Start.Character]
+	if !ok || input.Start.Line != input.End.Line {
+		return input, false
+	}
+	return lsp.Range{
+		Start: lsp.Position{Line: from.Line, Character: from.Col},
+		End:   lsp.Position{Line: from.Line, Character: from.Col + input.End.Character - input.Start.Character},
+	}, true
 }
 
 func (p *Server) Rename(ctx context.Context, params *lsp.RenameParams) (result *lsp.WorkspaceEdit, err error) {
 	p.Log.Info("client -> server: Rename")
 	defer p.Log.Info("client -> server: Rename end")
-	return p.Target.Rename(ctx, params)
+	// Rewrite the request.
+	templURI := params.TextDocument.URI
+	var ok bool
+	ok, params.TextDocument.URI, params.Position = p.updatePosition(templURI, params.Position)
+	if !ok {
+		if ok, params.TextDocument.URI, params.Position = p.declarationPosition(templURI, params.Position); !ok {
+			return nil, nil
+		}
+	}
+	// Call gopls, which renames the identifier in the generated code of templ files, and in the
+	// Go code that uses it.
+	result, err = p.Target.Rename(ctx, params)
+	if err != nil || result == nil {
+		return
+	}
+	// Rewrite the response.
+	return p.convertWorkspaceEdit(result)
 }
 
 func (p *Server) SignatureHelp(ctx context.Context, params *lsp.SignatureHelpParams) (result *lsp.SignatureHelp, err error) {
@@ -1284,34 +1710,36 @@ func (p *Server) OutgoingCalls(ctx context.Context, params *lsp.CallHierarchyOut
 func (p *Server) SemanticTokensFull(ctx context.Context, params *lsp.SemanticTokensParams) (result *lsp.SemanticTokens, err error) {
 	p.Log.Info("client -> server: SemanticTokensFull")
 	defer p.Log.Info("client -> server: SemanticTokensFull end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
-	if !isTemplFile {
-		return nil, nil
-	}
-	params.TextDocument.URI = goURI
-	return p.Target.SemanticTokensFull(ctx, params)
+	return p.semanticTokens(params.TextDocument.URI, nil), nil
 }
 
 func (p *Server) SemanticTokensFullDelta(ctx context.Context, params *lsp.SemanticTokensDeltaParams) (result any /* SemanticTokens | SemanticTokensDelta */, err error) {
 	p.Log.Info("client -> server: SemanticTokensFullDelta")
 	defer p.Log.Info("client -> server: SemanticTokensFullDelta end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
-	if !isTemplFile {
-		return nil, nil
-	}
-	params.TextDocument.URI = goURI
-	return p.Target.SemanticTokensFullDelta(ctx, params)
+	// Deltas aren't supported, so return all of the tokens.
+	return p.semanticTokens(params.TextDocument.URI, nil), nil
 }
 
 func (p *Server) SemanticTokensRange(ctx context.Context, params *lsp.SemanticTokensRangeParams) (result *lsp.SemanticTokens, err error) {
 	p.Log.Info("client -> server: SemanticTokensRange")
 	defer p.Log.Info("client -> server: SemanticTokensRange end")
-	isTemplFile, goURI := convertTemplToGoURI(params.TextDocument.URI)
-	if !isTemplFile {
-		return nil, nil
+	return p.semanticTokens(params.TextDocument.URI, &params.Range), nil
+}
+
+// semanticTokens returns the semantic tokens of the templ file, within the range if it isn't
+// nil. The file is parsed partially, so that it's highlighted while it's being edited.
+func (p *Server) semanticTokens(templURI lsp.DocumentURI, r *lsp.Range) *lsp.SemanticTokens {
+	d, ok := p.TemplSource.Get(string(templURI))
+	if !ok {
+		return nil
+	}
+	// The parser removes the byte order mark and carriage returns, and positions are those of
+	// the remaining source.
+	src := strings.ReplaceAll(strings.TrimPrefix(d.String(), "\ufeff"), "\r\n", "\n")
+	tf, _ := parser.ParsePartial(src)
+	return &lsp.SemanticTokens{
+		Data: encodeSemanticTokens(src, semanticTokens(src, tf), r),
 	}
-	params.TextDocument.URI = goURI
-	return p.Target.SemanticTokensRange(ctx, params)
 }
 
 func (p *Server) SemanticTokensRefresh(ctx context.Context) (err error) {
@@ -1338,6 +1766,77 @@ func (p *Server) Moniker(ctx context.Context, params *lsp.MonikerParams) (result
 	return p.Target.Moniker(ctx, params)
 }
 
+func (p *Server) InlayHint(ctx context.Context, params *lsp.InlayHintParams) (result []lsp.InlayHint, err error) {
+	p.Log.Info("client -> server: InlayHint")
+	defer p.Log.Info("client -> server: InlayHint end")
+	templURI := params.TextDocument.URI
+	isTemplFile, goURI := convertTemplToGoURI(templURI)
+	if !isTemplFile {
+		return p.Target.InlayHint(ctx, params)
+	}
+	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
+	if !ok {
+		p.Log.Warn("go->templ: sourcemap not found in cache", slog.String("uri", string(templURI)))
+		return nil, nil
+	}
+	// The range of the templ file can't be mapped to a range of the generated code, because the
+	// code isn't in the same order, so the hints of the whole file are requested and filtered.
+	templRange := params.Range
+	params.TextDocument.URI = goURI
+	params.Range = lsp.Range{
+		End: lsp.Position{Line: uint32(strings.Count(p.GoSource[string(templURI)], "\n") + 1)},
+	}
+	hints, err := p.Target.InlayHint(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return convertInlayHints(sourceMap, templRange, hints), nil
+}
+
+// convertInlayHints converts the inlay hints of a generated Go file to the hints of the Go
+// expressions written in the templ file, within the range. Hints of generated code are dropped.
+func convertInlayHints(sourceMap *parser.SourceMap, templRange lsp.Range, hints []lsp.InlayHint) (result []lsp.InlayHint) {
+	for _, h := range hints {
+		pos, ok := sourcePositionFromTarget(sourceMap, h.Position)
+		if !ok || !isRangeWithin(templRange, lsp.Range{Start: pos, End: pos}) {
+			continue
+		}
+		h.Position = pos
+		// Edits that insert the hint, e.g. a type, are only kept if they can all be mapped.
+		var edits []lsp.TextEdit
+		for _, e := range h.TextEdits {
+			start, startOK := sourcePositionFromTarget(sourceMap, e.Range.Start)
+			end, endOK := sourcePositionFromTarget(sourceMap, e.Range.End)
+			if !startOK || !endOK {
+				edits = nil
+				break
+			}
+			e.Range = lsp.Range{Start: start, End: end}
+			edits = append(edits, e)
+		}
+		h.TextEdits = edits
+		result = append(result, h)
+	}
+	return result
+}
+
+// sourcePositionFromTarget maps a position in generated Go code to the templ file. Unlike
+// SourceMap.SourcePositionFromTarget, the position must be within, or at the end of, a Go
+// expression written in the templ file, because hints are often placed after an identifier.
+func sourcePositionFromTarget(sourceMap *parser.SourceMap, tgt lsp.Position) (src lsp.Position, ok bool) {
+	if pos, ok := sourceMap.TargetLinesToSource[tgt.Line][tgt.Character]; ok {
+		return lsp.Position{Line: pos.Line, Character: pos.Col}, true
+	}
+	if tgt.Character == 0 {
+		return src, false
+	}
+	pos, ok := sourceMap.TargetLinesToSource[tgt.Line][tgt.Character-1]
+	if !ok {
+		return src, false
+	}
+	return lsp.Position{Line: pos.Line, Character: pos.Col + 1}, true
+}
+
 func (p *Server) Request(ctx context.Context, method string, params any) (result any, err error) {
 	p.Log.Info("client -> server: Request")
 	defer p.Log.Info("client -> server: Request end")
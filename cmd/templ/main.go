@@ -8,13 +8,17 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
 
 	"github.com/a-h/templ"
+	"github.com/a-h/templ/cmd/templ/analyzecmd"
 	"github.com/a-h/templ/cmd/templ/fmtcmd"
 	"github.com/a-h/templ/cmd/templ/generatecmd"
 	"github.com/a-h/templ/cmd/templ/infocmd"
 	"github.com/a-h/templ/cmd/templ/lspcmd"
+	"github.com/a-h/templ/cmd/templ/migratecmd"
+	"github.com/a-h/templ/cmd/templ/profilecmd"
 	"github.com/a-h/templ/cmd/templ/sloghandler"
 	"github.com/fatih/color"
 )
@@ -35,8 +39,11 @@ See docs at https://templ.guide
 commands:
   generate   Generates Go code from templ files
   fmt        Formats templ files
+  migrate    Rewrites templ files with rules, e.g. to rename components
+  analyze    Reports issues found across templ and Go code, e.g. unused components
   lsp        Starts a language server for templ files
   info       Displays information about the templ environment
+  profile    Reports the time and allocations of components in a profile
   version    Prints the version
 `
 
@@ -52,8 +59,14 @@ func run(stdin io.Reader, stdout, stderr io.Writer, args []string) (code int) {
 		return generateCmd(stdout, stderr, args[2:])
 	case "fmt":
 		return fmtCmd(stdin, stdout, stderr, args[2:])
+	case "migrate":
+		return migrateCmd(stdout, stderr, args[2:])
+	case "analyze":
+		return analyzeCmd(stdout, stderr, args[2:])
 	case "lsp":
 		return lspCmd(stdin, stdout, stderr, args[2:])
+	case "profile":
+		return profileCmd(stdout, stderr, args[2:])
 	case "version", "--version":
 		_, _ = fmt.Fprintln(stdout, templ.Version())
 		return 0
@@ -156,6 +169,10 @@ Format stdin to stdout:
 
   templ fmt < header.templ
 
+Format lines 10 to 25 of stdin to stdout, e.g. to format a selection in an editor:
+
+  templ fmt -stdin -range 10:25 < header.templ
+
 Format file or directory to stdout:
 
   templ fmt -stdout FILE
@@ -166,6 +183,11 @@ Args:
   -stdin-filepath
     Provides the formatter with filepath context when using -stdout.
     Required for organising imports.
+  -stdin
+    Format stdin to stdout. This is the default if no files are given.
+  -range <startLine:endLine>
+    Format only the lines in the range, when formatting stdin.
+    The rest of the file, including imports, is written as it is.
   -v
     Set log verbosity level to "debug". (default "info")
   -log-level
@@ -187,6 +209,8 @@ func fmtCmd(stdin io.Reader, stdout, stderr io.Writer, args []string) (code int)
 	failIfChanged := cmd.Bool("fail", false, "")
 	stdoutFlag := cmd.Bool("stdout", false, "")
 	stdinFilepath := cmd.String("stdin-filepath", "", "")
+	stdinFlag := cmd.Bool("stdin", false, "")
+	rangeFlag := cmd.String("range", "", "")
 	err := cmd.Parse(args)
 	if err != nil {
 		_, _ = fmt.Fprint(stderr, fmtUsageText)
@@ -196,6 +220,17 @@ func fmtCmd(stdin io.Reader, stdout, stderr io.Writer, args []string) (code int)
 		_, _ = fmt.Fprint(stdout, fmtUsageText)
 		return
 	}
+	var lineRange fmtcmd.LineRange
+	if *rangeFlag != "" {
+		if lineRange, err = fmtcmd.ParseLineRange(*rangeFlag); err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			return 64 // EX_USAGE
+		}
+	}
+	if (*stdinFlag || *rangeFlag != "") && cmd.NArg() > 0 {
+		_, _ = fmt.Fprintln(stderr, "files can't be given with -stdin or -range, which format stdin")
+		return 64 // EX_USAGE
+	}
 
 	log := sloghandler.NewLogger(*logLevelFlag, *verboseFlag, stderr)
 
@@ -205,8 +240,171 @@ func fmtCmd(stdin io.Reader, stdout, stderr io.Writer, args []string) (code int)
 		WorkerCount:   *workerCountFlag,
 		StdinFilepath: *stdinFilepath,
 		FailIfChanged: *failIfChanged,
+		Stdin:         *stdinFlag,
+		Range:         lineRange,
+	})
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+const migrateUsageText = `usage: templ migrate [<args> ...] -rules <file> [<path>]
+
+Rewrites the templ files in a directory with the rules in a JSON file, e.g. to
+roll out changes to the components of a design system.
+
+  {
+    "rules": [
+      { "rename_component": { "from": "oldui.Button", "to": "ui.Button" } },
+      { "rename_attribute": { "element": "button", "from": "kind", "to": "variant" } },
+      { "wrap_element": { "element": "table", "with": "div", "attributes": { "class": "table-scroll" } } }
+    ]
+  }
+
+Preview the changes as a diff:
+
+  templ migrate -rules rules.json -dry-run .
+
+Args:
+  -rules <file>
+    The JSON file of rules to apply. (required)
+  -dry-run
+    Prints a diff of the changes instead of writing them. (default false)
+  -v
+    Set log verbosity level to "debug". (default "info")
+  -log-level
+    Set log verbosity level. (default "info", options: "debug", "info", "warn", "error")
+  -w
+    Number of workers to use when migrating files. (default runtime.NumCPUs).
+  -help
+    Print help and exit.
+`
+
+func migrateCmd(stdout, stderr io.Writer, args []string) (code int) {
+	cmd := flag.NewFlagSet("migrate", flag.ExitOnError)
+	rulesFlag := cmd.String("rules", "", "")
+	dryRunFlag := cmd.Bool("dry-run", false, "")
+	workerCountFlag := cmd.Int("w", runtime.NumCPU(), "")
+	verboseFlag := cmd.Bool("v", false, "")
+	logLevelFlag := cmd.String("log-level", "info", "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args)
+	if err != nil || (!*helpFlag && (*rulesFlag == "" || cmd.NArg() > 1)) {
+		_, _ = fmt.Fprint(stderr, migrateUsageText)
+		return 64 // EX_USAGE
+	}
+	if *helpFlag {
+		_, _ = fmt.Fprint(stdout, migrateUsageText)
+		return
+	}
+	path := "."
+	if cmd.NArg() == 1 {
+		path = cmd.Arg(0)
+	}
+
+	log := sloghandler.NewLogger(*logLevelFlag, *verboseFlag, stderr)
+
+	err = migratecmd.Run(log, stdout, migratecmd.Arguments{
+		Path:        path,
+		RulesFile:   *rulesFlag,
+		DryRun:      *dryRunFlag,
+		WorkerCount: *workerCountFlag,
+	})
+	if err != nil {
+		_, _ = color.New(color.FgRed).Fprint(stderr, "(✗) ")
+		_, _ = fmt.Fprintln(stderr, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+const analyzeUsageText = `usage: templ analyze [<args> ...] [<path>]
+
+Analyzes the templ files, and the Go code that uses them, of the packages in a
+directory. The generated code must be up to date, so run templ generate first.
+
+Report components that aren't called by templates or Go code, e.g. to find
+stale templates that can be deleted:
+
+  templ analyze -unused .
+
+Args:
+  -unused
+    Reports the components that are never referenced. (required)
+  -help
+    Print help and exit.
+`
+
+func analyzeCmd(stdout, stderr io.Writer, args []string) (code int) {
+	cmd := flag.NewFlagSet("analyze", flag.ExitOnError)
+	unusedFlag := cmd.Bool("unused", false, "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args)
+	if err != nil || (!*helpFlag && (!*unusedFlag || cmd.NArg() > 1)) {
+		_, _ = fmt.Fprint(stderr, analyzeUsageText)
+		return 64 // EX_USAGE
+	}
+	if *helpFlag {
+		_, _ = fmt.Fprint(stdout, analyzeUsageText)
+		return
+	}
+	path := "."
+	if cmd.NArg() == 1 {
+		path = cmd.Arg(0)
+	}
+
+	err = analyzecmd.Run(stdout, analyzecmd.Arguments{
+		Path:   path,
+		Unused: *unusedFlag,
 	})
 	if err != nil {
+		_, _ = color.New(color.FgRed).Fprint(stderr, "(✗) ")
+		_, _ = fmt.Fprintln(stderr, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+const profileUsageText = `usage: templ profile [<args> ...] <profile>
+
+Reports the time or allocations spent rendering each component, and each type
+of template node, from a CPU or memory profile written by runtime/pprof.
+
+Write a profile of a benchmark that renders components:
+
+  go test -bench . -cpuprofile cpu.out
+  templ profile cpu.out
+
+  go test -bench . -memprofile mem.out -memprofilerate 1
+  templ profile -sample-type alloc_space mem.out
+
+The .templ files must be available at the paths they were built from.
+
+Args:
+  -sample-type
+    The type of sample to report, e.g. cpu, alloc_space or alloc_objects. (default: the default sample type of the profile)
+  -folded
+    Output the stacks in the folded format used by flame graph tools, such as speedscope and flamegraph.pl. (default false)
+  -help
+    Print help and exit.
+`
+
+func profileCmd(stdout, stderr io.Writer, args []string) (code int) {
+	cmdArgs, help, err := profilecmd.NewArguments(args)
+	if err != nil {
+		_, _ = fmt.Fprint(stderr, profileUsageText)
+		return 64 // EX_USAGE
+	}
+	if help {
+		_, _ = fmt.Fprint(stdout, profileUsageText)
+		return
+	}
+
+	err = profilecmd.Run(stdout, cmdArgs)
+	if err != nil {
+		_, _ = color.New(color.FgRed).Fprint(stderr, "(✗) ")
+		_, _ = fmt.Fprintln(stderr, "Command failed: "+err.Error())
 		return 1
 	}
 	return 0
@@ -219,6 +417,8 @@ Starts a language server for templ.
 Args:
   -log string
     The file to log templ LSP output to, or leave empty to disable logging.
+  -log-level
+    Set log verbosity level. (default "info", options: "debug", "info", "warn", "error")
   -goplsLog string
     The file to log gopls output, or leave empty to disable logging.
   -goplsRPCTrace
@@ -233,11 +433,14 @@ Args:
     Enable http debug server by setting a listen address (e.g. localhost:7474)
   -no-preload
     Disable preloading of templ files on server startup and use custom GOPACKAGESDRIVER for lazy loading (useful for large monorepos). GOPACKAGESDRIVER environment variable must be set.
+  -lint <names>
+    Comma separated list of lint rules to run over templ files, e.g. a11y,no-inline-styles:error.
 `
 
 func lspCmd(stdin io.Reader, stdout, stderr io.Writer, args []string) (code int) {
 	cmd := flag.NewFlagSet("lsp", flag.ExitOnError)
 	logFlag := cmd.String("log", "", "")
+	logLevelFlag := cmd.String("log-level", "info", "")
 	goplsLog := cmd.String("goplsLog", "", "")
 	goplsRPCTrace := cmd.Bool("goplsRPCTrace", false, "")
 	goplsRemote := cmd.String("gopls-remote", "", "")
@@ -245,6 +448,7 @@ func lspCmd(stdin io.Reader, stdout, stderr io.Writer, args []string) (code int)
 	pprofFlag := cmd.Bool("pprof", false, "")
 	httpDebugFlag := cmd.String("http", "", "")
 	noPreloadFlag := cmd.Bool("no-preload", false, "")
+	lintFlag := cmd.String("lint", "", "")
 	err := cmd.Parse(args)
 	if err != nil {
 		_, _ = fmt.Fprint(stderr, lspUsageText)
@@ -254,15 +458,21 @@ func lspCmd(stdin io.Reader, stdout, stderr io.Writer, args []string) (code int)
 		_, _ = fmt.Fprint(stdout, lspUsageText)
 		return
 	}
+	var lint []string
+	if *lintFlag != "" {
+		lint = strings.Split(*lintFlag, ",")
+	}
 
 	err = lspcmd.Run(stdin, stdout, stderr, lspcmd.Arguments{
 		Log:           *logFlag,
+		LogLevel:      *logLevelFlag,
 		GoplsLog:      *goplsLog,
 		GoplsRPCTrace: *goplsRPCTrace,
 		GoplsRemote:   *goplsRemote,
 		PPROF:         *pprofFlag,
 		HTTPDebug:     *httpDebugFlag,
 		NoPreload:     *noPreloadFlag && os.Getenv("GOPACKAGESDRIVER") != "",
+		Lint:          lint,
 	})
 	if err != nil {
 		_, _ = fmt.Fprintln(stderr, err.Error())
@@ -59,12 +59,48 @@ func TestMain(t *testing.T) {
 			expectedStdout: lspUsageText,
 			expectedCode:   0,
 		},
+		{
+			name:           `"templ migrate --help" prints usage`,
+			args:           []string{"templ", "migrate", "--help"},
+			expectedStdout: migrateUsageText,
+			expectedCode:   0,
+		},
+		{
+			name:           `"templ migrate" without rules prints usage`,
+			args:           []string{"templ", "migrate", "."},
+			expectedStderr: migrateUsageText,
+			expectedCode:   64,
+		},
+		{
+			name:           `"templ analyze --help" prints usage`,
+			args:           []string{"templ", "analyze", "--help"},
+			expectedStdout: analyzeUsageText,
+			expectedCode:   0,
+		},
+		{
+			name:           `"templ analyze" without an analysis prints usage`,
+			args:           []string{"templ", "analyze", "."},
+			expectedStderr: analyzeUsageText,
+			expectedCode:   64,
+		},
 		{
 			name:           `"templ info --help" prints usage`,
 			args:           []string{"templ", "info", "--help"},
 			expectedStdout: infoUsageText,
 			expectedCode:   0,
 		},
+		{
+			name:           `"templ profile --help" prints usage`,
+			args:           []string{"templ", "profile", "--help"},
+			expectedStdout: profileUsageText,
+			expectedCode:   0,
+		},
+		{
+			name:           `"templ profile" without a profile prints usage`,
+			args:           []string{"templ", "profile"},
+			expectedStderr: profileUsageText,
+			expectedCode:   64,
+		},
 	}
 
 	for _, test := range tests {
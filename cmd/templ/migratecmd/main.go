@@ -0,0 +1,141 @@
+package migratecmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ/cmd/templ/imports"
+	"github.com/a-h/templ/cmd/templ/processor"
+	"github.com/a-h/templ/config"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/natefinch/atomic"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+type Arguments struct {
+	// Path is the file or directory of templ files to migrate.
+	Path string
+	// RulesFile is the JSON file of rules to apply, see Rules.
+	RulesFile string
+	// DryRun prints a diff of the changes instead of writing them.
+	DryRun      bool
+	WorkerCount int
+}
+
+// Run applies the rules to the templ files in the path. Files that the rules don't change are
+// left as they are, and files that are changed are formatted, like templ fmt.
+func Run(log *slog.Logger, stdout io.Writer, args Arguments) (err error) {
+	f, err := os.Open(args.RulesFile)
+	if err != nil {
+		return fmt.Errorf("failed to open rules: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	rules, err := ParseRules(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args.RulesFile, err)
+	}
+	if args.WorkerCount == 0 {
+		args.WorkerCount = runtime.NumCPU()
+	}
+
+	var m sync.Mutex
+	diffs := map[string]string{}
+	process := func(fileName string) (error, bool) {
+		src, tgt, changed, err := migrate(fileName, rules)
+		if err != nil || !changed {
+			return err, false
+		}
+		if !args.DryRun {
+			return atomic.WriteFile(fileName, bytes.NewBufferString(tgt)), true
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(src),
+			B:        difflib.SplitLines(tgt),
+			FromFile: fileName,
+			ToFile:   fileName,
+			Context:  3,
+		})
+		if err != nil {
+			return err, false
+		}
+		m.Lock()
+		defer m.Unlock()
+		diffs[fileName] = diff
+		return nil, true
+	}
+
+	start := time.Now()
+	results := make(chan processor.Result)
+	go processor.Process(args.Path, process, args.WorkerCount, results)
+	var errs []error
+	var count, changed int
+	for r := range results {
+		count++
+		if r.Error != nil {
+			log.Error(r.FileName, slog.Any("error", r.Error))
+			errs = append(errs, r.Error)
+			continue
+		}
+		if r.ChangesMade {
+			changed++
+			log.Debug("Migrated", slog.String("file", r.FileName), slog.Duration("duration", r.Duration))
+		}
+	}
+
+	// Diffs are written in the order of the file names, so that the output is the same each time.
+	fileNames := make([]string, 0, len(diffs))
+	for fileName := range diffs {
+		fileNames = append(fileNames, fileName)
+	}
+	slices.Sort(fileNames)
+	for _, fileName := range fileNames {
+		if _, err = io.WriteString(stdout, diffs[fileName]); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Migrate Complete", slog.Int("count", count), slog.Int("errors", len(errs)), slog.Int("changed", changed), slog.Bool("dryRun", args.DryRun), slog.Duration("duration", time.Since(start)))
+	if err = errors.Join(errs...); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	return nil
+}
+
+// migrate returns the source of the file, and the source after the rules are applied.
+func migrate(fileName string, rules Rules) (src, tgt string, changed bool, err error) {
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to read file %q: %w", fileName, err)
+	}
+	src = string(b)
+	t, err := parser.ParseString(src)
+	if err != nil {
+		return src, "", false, err
+	}
+	t.Filepath = fileName
+	if !rules.Apply(t) {
+		return src, src, false, nil
+	}
+	cfg, err := config.Find(filepath.Dir(fileName))
+	if err != nil {
+		return src, "", false, fmt.Errorf("failed to read %s: %w", config.FileName, err)
+	}
+	if t, err = imports.Process(t); err != nil {
+		return src, "", false, err
+	}
+	w := new(bytes.Buffer)
+	if err = t.WriteStyle(w, cfg.Format.Style()); err != nil {
+		return src, "", false, fmt.Errorf("formatting error: %w", err)
+	}
+	tgt = w.String()
+	return src, tgt, src != tgt, nil
+}
@@ -0,0 +1,167 @@
+package migratecmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+const rulesJSON = `{
+	"rules": [
+		{ "rename_component": { "from": "oldui.Button", "to": "ui.Button" } },
+		{ "rename_attribute": { "element": "button", "from": "kind", "to": "variant" } },
+		{ "wrap_element": { "element": "table", "with": "div", "attributes": { "class": "table-scroll" } } }
+	]
+}`
+
+func TestRules(t *testing.T) {
+	rules, err := ParseRules(strings.NewReader(rulesJSON))
+	if err != nil {
+		t.Fatalf("failed to parse rules: %v", err)
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "components are renamed",
+			input: `templ page() {
+	@oldui.Button("Save")
+	@oldui.ButtonGroup() {
+		@oldui.Button("Cancel")
+	}
+}`,
+			expected: `templ page() {
+	@ui.Button("Save")
+	@oldui.ButtonGroup() {
+		@ui.Button("Cancel")
+	}
+}`,
+		},
+		{
+			name: "attributes of matching elements are renamed",
+			input: `templ page(primary bool) {
+	<button kind="primary" if primary {
+		disabled?={ primary }
+	}>Save</button>
+	<a kind="link">Cancel</a>
+}`,
+			expected: `templ page(primary bool) {
+	<button
+		variant="primary"
+		if primary {
+			disabled?={ primary }
+		}
+	>Save</button>
+	<a kind="link">Cancel</a>
+}`,
+		},
+		{
+			name: "elements are wrapped once",
+			input: `templ page() {
+	<table></table>
+	<div class="table-scroll">
+		<table></table>
+	</div>
+}`,
+			expected: `templ page() {
+	<div class="table-scroll">
+		<table></table>
+	</div>
+	<div class="table-scroll">
+		<table></table>
+	</div>
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString("package main\n\n" + tt.input + "\n")
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			if !rules.Apply(tf) {
+				t.Error("expected the template to be changed")
+			}
+			w := new(strings.Builder)
+			if err := tf.Write(w); err != nil {
+				t.Fatalf("failed to write template: %v", err)
+			}
+			if diff := cmp.Diff("package main\n\n"+tt.expected+"\n", w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("invalid rules are an error", func(t *testing.T) {
+		for _, input := range []string{
+			`{ "rules": [{}] }`,
+			`{ "rules": [{ "rename_component": { "from": "a" } }] }`,
+			`{ "rules": [{ "rename_component": { "from": "a", "to": "b" }, "wrap_element": { "element": "a", "with": "b" } }] }`,
+			`{ "rules": [{ "remove_element": { "element": "a" } }] }`,
+		} {
+			if _, err := ParseRules(strings.NewReader(input)); err == nil {
+				t.Errorf("expected an error for %s", input)
+			}
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	rulesFile := filepath.Join(dir, "rules.json")
+	input := "package main\n\ntempl page() {\n\t@oldui.Button(\"Save\")\n}\n"
+	unchanged := "package main\n\ntempl other() {\n\t<p>Other</p>\n}\n"
+	for name, contents := range map[string]string{
+		"rules.json":  rulesJSON,
+		"page.templ":  input,
+		"other.templ": unchanged,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0660); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	readFile := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		return string(b)
+	}
+
+	t.Run("dry runs print a diff without changing files", func(t *testing.T) {
+		stdout := new(strings.Builder)
+		if err := Run(log, stdout, Arguments{Path: dir, RulesFile: rulesFile, DryRun: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, expected := range []string{"-\t@oldui.Button(\"Save\")\n", "+\t@ui.Button(\"Save\")\n"} {
+			if !strings.Contains(stdout.String(), expected) {
+				t.Errorf("expected %q in the diff, got:\n%s", expected, stdout.String())
+			}
+		}
+		if strings.Contains(stdout.String(), "other.templ") {
+			t.Errorf("unexpected diff of an unchanged file:\n%s", stdout.String())
+		}
+		if readFile("page.templ") != input {
+			t.Error("expected the file to be unchanged")
+		}
+	})
+	t.Run("files are rewritten", func(t *testing.T) {
+		if err := Run(log, io.Discard, Arguments{Path: dir, RulesFile: rulesFile}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(strings.Replace(input, "oldui.", "ui.", 1), readFile("page.templ")); diff != "" {
+			t.Error(diff)
+		}
+		if readFile("other.templ") != unchanged {
+			t.Error("expected the unchanged file to be left as it is")
+		}
+	})
+}
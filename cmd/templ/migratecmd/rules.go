@@ -0,0 +1,227 @@
+package migratecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// Rules are the rewrites applied to templ files, read from a JSON file.
+//
+//	{
+//		"rules": [
+//			{ "rename_component": { "from": "oldui.Button", "to": "ui.Button" } },
+//			{ "rename_attribute": { "element": "button", "from": "kind", "to": "variant" } },
+//			{ "wrap_element": { "element": "table", "with": "div", "attributes": { "class": "table-scroll" } } }
+//		]
+//	}
+//
+// The rules are applied in order, so a rule sees the changes made by the rules before it.
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a single rewrite. Exactly one of its fields must be set.
+type Rule struct {
+	RenameComponent *RenameComponent `json:"rename_component,omitempty"`
+	RenameAttribute *RenameAttribute `json:"rename_attribute,omitempty"`
+	WrapElement     *WrapElement     `json:"wrap_element,omitempty"`
+}
+
+// RenameComponent renames the component of template calls, e.g. @oldui.Button("Save") becomes
+// @ui.Button("Save"). The templates themselves aren't renamed, and imports aren't changed.
+type RenameComponent struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RenameAttribute renames an attribute of elements. If Element is empty, the attribute is
+// renamed on all elements.
+type RenameAttribute struct {
+	Element string `json:"element,omitempty"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// WrapElement wraps elements in a new element, with the given constant attributes. Elements
+// that are already the only child of a wrapper element aren't wrapped again, so the rule can
+// be applied more than once.
+type WrapElement struct {
+	Element    string            `json:"element"`
+	With       string            `json:"with"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// ParseRules reads rules from JSON. Unknown fields are an error.
+func ParseRules(r io.Reader) (rules Rules, err error) {
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err = d.Decode(&rules); err != nil {
+		return rules, fmt.Errorf("invalid rules: %w", err)
+	}
+	for i, rule := range rules.Rules {
+		if err = rule.validate(); err != nil {
+			return rules, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	return rules, nil
+}
+
+func (r Rule) validate() error {
+	var set int
+	if r.RenameComponent != nil {
+		set++
+		if r.RenameComponent.From == "" || r.RenameComponent.To == "" {
+			return errors.New("rename_component requires from and to")
+		}
+	}
+	if r.RenameAttribute != nil {
+		set++
+		if r.RenameAttribute.From == "" || r.RenameAttribute.To == "" {
+			return errors.New("rename_attribute requires from and to")
+		}
+	}
+	if r.WrapElement != nil {
+		set++
+		if r.WrapElement.Element == "" || r.WrapElement.With == "" {
+			return errors.New("wrap_element requires element and with")
+		}
+	}
+	if set != 1 {
+		return errors.New("expected one of rename_component, rename_attribute or wrap_element")
+	}
+	return nil
+}
+
+// Apply rewrites the template file with the rules, and returns true if it was changed.
+func (rules Rules) Apply(tf *parser.TemplateFile) (changed bool) {
+	for _, r := range rules.Rules {
+		var fn func(c *parser.Cursor) bool
+		switch {
+		case r.RenameComponent != nil:
+			fn = r.RenameComponent.rewrite(&changed)
+		case r.RenameAttribute != nil:
+			fn = r.RenameAttribute.rewrite(&changed)
+		case r.WrapElement != nil:
+			fn = r.WrapElement.rewrite(&changed)
+		}
+		parser.Rewrite(tf, fn)
+	}
+	return changed
+}
+
+func (r *RenameComponent) rewrite(changed *bool) func(c *parser.Cursor) bool {
+	// The name must be followed by the arguments, or type arguments, of the call.
+	call := regexp.MustCompile(`^` + regexp.QuoteMeta(r.From) + `\s*[(\[]`)
+	rename := func(e *parser.Expression) {
+		if call.MatchString(e.Value) {
+			e.Value = r.To + strings.TrimPrefix(e.Value, r.From)
+			*changed = true
+		}
+	}
+	return func(c *parser.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *parser.TemplElementExpression:
+			rename(&n.Expression)
+		case *parser.CallTemplateExpression:
+			rename(&n.Expression)
+		}
+		return true
+	}
+}
+
+func (r *RenameAttribute) rewrite(changed *bool) func(c *parser.Cursor) bool {
+	return func(c *parser.Cursor) bool {
+		var key *parser.AttributeKey
+		switch n := c.Node().(type) {
+		case *parser.ConstantAttribute:
+			key = &n.Key
+		case *parser.BoolConstantAttribute:
+			key = &n.Key
+		case *parser.ExpressionAttribute:
+			key = &n.Key
+		case *parser.BoolExpressionAttribute:
+			key = &n.Key
+		default:
+			return true
+		}
+		k, ok := (*key).(parser.ConstantAttributeKey)
+		if !ok || k.Name != r.From {
+			return true
+		}
+		if r.Element != "" && !strings.EqualFold(elementName(c.Parents()), r.Element) {
+			return true
+		}
+		k.Name = r.To
+		*key = k
+		*changed = true
+		return true
+	}
+}
+
+// elementName returns the name of the innermost element of the parents, which is the element
+// of an attribute, including the attributes of conditional attributes.
+func elementName(parents []parser.Visitable) string {
+	for i := len(parents) - 1; i >= 0; i-- {
+		switch p := parents[i].(type) {
+		case *parser.Element:
+			return p.Name
+		case *parser.RawElement:
+			return p.Name
+		case *parser.ScriptElement:
+			return "script"
+		case *parser.ConditionalAttribute:
+			continue
+		}
+		return ""
+	}
+	return ""
+}
+
+func (r *WrapElement) rewrite(changed *bool) func(c *parser.Cursor) bool {
+	keys := make([]string, 0, len(r.Attributes))
+	for k := range r.Attributes {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return func(c *parser.Cursor) bool {
+		e, ok := c.Node().(*parser.Element)
+		if !ok || !strings.EqualFold(e.Name, r.Element) {
+			return true
+		}
+		if p, ok := c.Parent().(*parser.Element); ok && strings.EqualFold(p.Name, r.With) && isOnlyChild(p, e) {
+			return true
+		}
+		wrapper := &parser.Element{
+			Name:           r.With,
+			Children:       []parser.Node{e},
+			IndentChildren: true,
+			TrailingSpace:  e.TrailingSpace,
+		}
+		for _, k := range keys {
+			wrapper.Attributes = append(wrapper.Attributes, &parser.ConstantAttribute{
+				Key:   parser.ConstantAttributeKey{Name: k},
+				Value: r.Attributes[k],
+			})
+		}
+		c.Replace(wrapper)
+		*changed = true
+		return false
+	}
+}
+
+// isOnlyChild returns true if n is the only child of the element, ignoring whitespace.
+func isOnlyChild(e *parser.Element, n parser.Node) bool {
+	for _, child := range e.Children {
+		if _, isWhitespace := child.(*parser.Whitespace); !isWhitespace && child != n {
+			return false
+		}
+	}
+	return true
+}
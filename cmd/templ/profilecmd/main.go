@@ -0,0 +1,67 @@
+package profilecmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/a-h/templ/renderprofile"
+)
+
+type Arguments struct {
+	// Path of the profile written by runtime/pprof.
+	Path string
+	// SampleType to report, e.g. cpu or alloc_space. The default sample type of the profile
+	// is used if it's empty.
+	SampleType string
+	// Folded writes the stacks in the folded format used by flame graph tools.
+	Folded bool
+}
+
+// NewArguments parses the arguments of the profile command. The path of the profile is the
+// only positional argument, and is required unless help is requested.
+func NewArguments(args []string) (cmdArgs Arguments, help bool, err error) {
+	cmd := flag.NewFlagSet("profile", flag.ContinueOnError)
+	cmd.SetOutput(io.Discard)
+	cmd.StringVar(&cmdArgs.SampleType, "sample-type", "", "")
+	cmd.BoolVar(&cmdArgs.Folded, "folded", false, "")
+	cmd.BoolVar(&help, "help", false, "")
+	if err = cmd.Parse(args); err != nil {
+		return cmdArgs, false, err
+	}
+	if help {
+		return cmdArgs, true, nil
+	}
+	if cmd.NArg() != 1 {
+		return cmdArgs, false, errors.New("expected the path of one profile")
+	}
+	cmdArgs.Path = cmd.Arg(0)
+	return cmdArgs, false, nil
+}
+
+// Run writes a report of the time or allocations spent in each component, and each type of
+// template node, in the profile.
+func Run(stdout io.Writer, args Arguments) error {
+	f, err := os.Open(args.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	p, err := renderprofile.Read(f)
+	if err != nil {
+		return err
+	}
+	r, err := p.Report(args.SampleType)
+	if err != nil {
+		return err
+	}
+	if len(r.Stacks) == 0 {
+		return fmt.Errorf("the profile doesn't contain any %s samples in templ components", r.SampleType.Type)
+	}
+	if args.Folded {
+		return r.WriteFolded(stdout)
+	}
+	return r.WriteText(stdout)
+}
@@ -0,0 +1,130 @@
+package profilecmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	testscriptexpressions "github.com/a-h/templ/generator/test-script-expressions"
+)
+
+// writeProfile writes the allocations made while rendering a component to a file, in the same
+// way that go test -memprofile does.
+func writeProfile(t *testing.T) (fileName string) {
+	previousRate := runtime.MemProfileRate
+	runtime.MemProfileRate = 1
+	defer func() { runtime.MemProfileRate = previousRate }()
+	for range 100 {
+		if err := testscriptexpressions.AllTests().Render(context.Background(), io.Discard); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+	}
+	// Allocations are added to the profile by garbage collection.
+	runtime.GC()
+	fileName = filepath.Join(t.TempDir(), "mem.out")
+	f, err := os.Create(fileName)
+	if err != nil {
+		t.Fatalf("failed to create profile: %v", err)
+	}
+	defer f.Close()
+	if err = pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+		t.Fatalf("failed to write profile: %v", err)
+	}
+	return fileName
+}
+
+func TestArgs(t *testing.T) {
+	t.Run("the profile and flags are parsed", func(t *testing.T) {
+		args, help, err := NewArguments([]string{"-sample-type", "alloc_space", "-folded", "mem.out"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if help {
+			t.Error("expected help to be false")
+		}
+		expected := Arguments{Path: "mem.out", SampleType: "alloc_space", Folded: true}
+		if args != expected {
+			t.Errorf("expected %+v, got %+v", expected, args)
+		}
+	})
+	t.Run("help doesn't need a profile", func(t *testing.T) {
+		_, help, err := NewArguments([]string{"-help"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !help {
+			t.Error("expected help to be true")
+		}
+	})
+	t.Run("a profile is required", func(t *testing.T) {
+		if _, _, err := NewArguments([]string{"-folded"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("only one profile can be reported", func(t *testing.T) {
+		if _, _, err := NewArguments([]string{"cpu.out", "mem.out"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("unknown flags are an error", func(t *testing.T) {
+		if _, _, err := NewArguments([]string{"-unknown", "cpu.out"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	fileName := writeProfile(t)
+
+	t.Run("the components in the profile are reported", func(t *testing.T) {
+		stdout := new(strings.Builder)
+		if err := Run(stdout, Arguments{Path: fileName, SampleType: "alloc_space"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, expected := range []string{"alloc_space: ", "Component", "test-script-expressions.Script"} {
+			if !strings.Contains(stdout.String(), expected) {
+				t.Errorf("expected %q in the report, got:\n%s", expected, stdout.String())
+			}
+		}
+	})
+	t.Run("the stacks can be folded", func(t *testing.T) {
+		stdout := new(strings.Builder)
+		if err := Run(stdout, Arguments{Path: fileName, SampleType: "alloc_space", Folded: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(stdout.String(), "test-script-expressions.AllTests;test-script-expressions.Script") {
+			t.Errorf("expected the folded stacks of the components, got:\n%s", stdout.String())
+		}
+	})
+	t.Run("unknown sample types are an error", func(t *testing.T) {
+		err := Run(io.Discard, Arguments{Path: fileName, SampleType: "cpu"})
+		if err == nil || !strings.Contains(err.Error(), `sample type "cpu" not found`) {
+			t.Errorf("expected an unknown sample type error, got %v", err)
+		}
+	})
+	t.Run("profiles without components are an error", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "goroutine.out")
+		f, err := os.Create(fileName)
+		if err != nil {
+			t.Fatalf("failed to create profile: %v", err)
+		}
+		if err = pprof.Lookup("goroutine").WriteTo(f, 0); err != nil {
+			t.Fatalf("failed to write profile: %v", err)
+		}
+		_ = f.Close()
+		expected := "the profile doesn't contain any goroutine samples in templ components"
+		if err = Run(io.Discard, Arguments{Path: fileName}); err == nil || err.Error() != expected {
+			t.Errorf("expected error %q, got %v", expected, err)
+		}
+	})
+	t.Run("missing profiles are an error", func(t *testing.T) {
+		if err := Run(io.Discard, Arguments{Path: filepath.Join(t.TempDir(), "missing.out")}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package sloghandler
+
+import (
+	"errors"
+	"go/scanner"
+	"log/slog"
+
+	"github.com/a-h/parse"
+)
+
+// ErrorAttrs returns the error as a log attribute, along with the line and column of parse
+// and Go syntax errors, so that build tools can locate the error without parsing the message.
+// Positions match those in the error message.
+func ErrorAttrs(err error) (attrs []any) {
+	attrs = append(attrs, slog.Any("error", err))
+	var pe parse.ParseError
+	if errors.As(err, &pe) {
+		return append(attrs, slog.Int("line", pe.Pos.Line+1), slog.Int("col", pe.Pos.Col))
+	}
+	var el scanner.ErrorList
+	if errors.As(err, &el) && len(el) > 0 {
+		return append(attrs, slog.Int("line", el[0].Pos.Line), slog.Int("col", el[0].Pos.Column))
+	}
+	return attrs
+}
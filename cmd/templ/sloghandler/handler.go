@@ -11,6 +11,18 @@ import (
 )
 
 func NewLogger(logLevel string, verbose bool, stderr io.Writer) *slog.Logger {
+	return NewLoggerWithFormat(FormatText, logLevel, verbose, stderr)
+}
+
+const (
+	// FormatText writes human readable log lines.
+	FormatText = "text"
+	// FormatJSON writes a JSON object per log line, for consumption by build tools.
+	FormatJSON = "json"
+)
+
+// NewLoggerWithFormat creates a logger that writes in the given format, see FormatText and FormatJSON.
+func NewLoggerWithFormat(format, logLevel string, verbose bool, stderr io.Writer) *slog.Logger {
 	if verbose {
 		logLevel = "debug"
 	}
@@ -23,10 +35,14 @@ func NewLogger(logLevel string, verbose bool, stderr io.Writer) *slog.Logger {
 	case "error":
 		level = slog.LevelError.Level()
 	}
-	return slog.New(NewHandler(stderr, &slog.HandlerOptions{
+	opts := &slog.HandlerOptions{
 		AddSource: logLevel == "debug",
 		Level:     level,
-	}))
+	}
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(stderr, opts))
+	}
+	return slog.New(NewHandler(stderr, opts))
 }
 
 var _ slog.Handler = &Handler{}
@@ -28,7 +28,7 @@ func RemoteInclusionTest() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = Remote.Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(Remote).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -57,7 +57,7 @@ func Remote2() templ.Component {
 			templ_7745c5c3_Var2 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = Remote.Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(Remote).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -0,0 +1,266 @@
+// Package config reads templ.toml, the project configuration of templ, which sets the style
+// that templ files are formatted with, and the options that they're generated with.
+//
+//	[format]
+//	indent = "spaces"
+//	indent_width = 2
+//	max_inline_attributes = 3
+//	max_line_length = 100
+//	attribute_quote = "double"
+//	attribute_wrap = "auto"
+//
+//	[generate]
+//	strict = true
+//	strict_allow = ["hx-*"]
+//	minify = true
+//
+// The configuration applies to the templ files in the directory of the templ.toml file, and
+// its subdirectories, see Find. The format table is read by templ fmt, templ migrate and the
+// LSP, and the generate table is read by templ generate.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// FileName is the name of the project configuration file.
+const FileName = "templ.toml"
+
+// Config is the project configuration.
+type Config struct {
+	// FileName is the path of the templ.toml file that the configuration was read from, or ""
+	// if the default configuration is used.
+	FileName string
+	// Format is the style that templ files are formatted with.
+	Format Format
+	// Generate is the options that templ files are generated with.
+	Generate Generate
+}
+
+// The indentation styles of Format.Indent.
+const (
+	IndentTabs   = "tabs"
+	IndentSpaces = "spaces"
+)
+
+// The quote styles of Format.AttributeQuote.
+const (
+	QuotePreserve = "preserve"
+	QuoteDouble   = "double"
+	QuoteSingle   = "single"
+)
+
+// The attribute wrapping styles of Format.AttributeWrap.
+const (
+	WrapPreserve = "preserve"
+	WrapAuto     = "auto"
+)
+
+// DefaultIndentWidth is the number of spaces each level is indented with, if the indentation
+// style is spaces, and no width is set.
+const DefaultIndentWidth = 2
+
+// Format is the [format] table of templ.toml.
+type Format struct {
+	// Indent is the indentation style, IndentTabs or IndentSpaces. The default is tabs.
+	Indent string
+	// IndentWidth is the number of spaces each level is indented with, if Indent is
+	// IndentSpaces.
+	IndentWidth int
+	// MaxInlineAttributes is the maximum number of attributes that an element can have on a
+	// single line, or 0 for no maximum.
+	MaxInlineAttributes int
+	// MaxLineLength is the maximum length of the start tag of an element before its
+	// attributes are written on separate lines, or 0 for no maximum.
+	MaxLineLength int
+	// AttributeQuote is the quote character of constant attribute values, QuotePreserve,
+	// QuoteDouble or QuoteSingle. The default is to preserve the quote character.
+	AttributeQuote string
+	// AttributeWrap is whether attributes written on separate lines are kept on separate
+	// lines, WrapPreserve, or joined if they fit within the limits, WrapAuto. The default is
+	// to preserve them.
+	AttributeWrap string
+}
+
+// Generate is the [generate] table of templ.toml. It's also the format of the package
+// configuration files of templ generate, templ.json and templ.yaml, which override it for the
+// templ files in their directory. Options that are set override the flags of templ generate,
+// and options that aren't set are nil.
+type Generate struct {
+	// RuntimeImportPath overrides the -runtime-import-path flag.
+	RuntimeImportPath *string `json:"runtimeImportPath" yaml:"runtimeImportPath" toml:"runtime_import_path"`
+	// Strict overrides the -strict flag.
+	Strict *bool `json:"strict" yaml:"strict" toml:"strict"`
+	// StrictErrors overrides the -strict-errors flag.
+	StrictErrors *bool `json:"strictErrors" yaml:"strictErrors" toml:"strict_errors"`
+	// StrictAllow overrides the -strict-allow flag.
+	StrictAllow []string `json:"strictAllow" yaml:"strictAllow" toml:"strict_allow"`
+	// Minify overrides the -minify flag.
+	Minify *bool `json:"minify" yaml:"minify" toml:"minify"`
+	// FileSuffix overrides the -file-suffix flag.
+	FileSuffix *string `json:"fileSuffix" yaml:"fileSuffix" toml:"file_suffix"`
+	// StrictNilComponents overrides the -strict-nil-components flag.
+	StrictNilComponents *bool `json:"strictNilComponents" yaml:"strictNilComponents" toml:"strict_nil_components"`
+	// StrictText overrides the -strict-text flag.
+	StrictText *bool `json:"strictText" yaml:"strictText" toml:"strict_text"`
+	// TextTransform overrides the -text-transform flag.
+	TextTransform *bool `json:"textTransform" yaml:"textTransform" toml:"text_transform"`
+	// URLSanitizer overrides the -url-sanitizer flag.
+	URLSanitizer *bool `json:"urlSanitizer" yaml:"urlSanitizer" toml:"url_sanitizer"`
+	// LiteralConstants overrides the -literal-constants flag.
+	LiteralConstants *bool `json:"literalConstants" yaml:"literalConstants" toml:"literal_constants"`
+	// LiteralBytes overrides the -literal-bytes flag.
+	LiteralBytes *bool `json:"literalBytes" yaml:"literalBytes" toml:"literal_bytes"`
+	// StaticData overrides the data read from the file of the -static-data flag.
+	StaticData map[string]any `json:"staticData" yaml:"staticData" toml:"static_data"`
+}
+
+// Default returns the default configuration, used if there's no templ.toml file.
+func Default() Config {
+	return Config{
+		Format: Format{
+			Indent:         IndentTabs,
+			AttributeQuote: QuotePreserve,
+			AttributeWrap:  WrapPreserve,
+		},
+	}
+}
+
+// Style returns the parser style that formats templ files as configured.
+func (f Format) Style() (style parser.FormatStyle) {
+	if f.Indent == IndentSpaces {
+		style.IndentWidth = f.IndentWidth
+	}
+	style.MaxInlineAttributes = f.MaxInlineAttributes
+	style.MaxLineLength = f.MaxLineLength
+	switch f.AttributeQuote {
+	case QuoteDouble:
+		style.AttributeQuote = parser.QuoteDouble
+	case QuoteSingle:
+		style.AttributeQuote = parser.QuoteSingle
+	}
+	if f.AttributeWrap == WrapAuto {
+		style.AttributeWrap = parser.WrapAuto
+	}
+	return style
+}
+
+// Find returns the configuration of the templ files in dir, read from the templ.toml file in
+// dir or its nearest parent directory. The search stops at the root of the Go module, i.e.
+// the directory that contains go.mod. If no templ.toml file is found, the default
+// configuration is returned.
+func Find(dir string) (c Config, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return c, err
+	}
+	for {
+		fileName := filepath.Join(dir, FileName)
+		if _, err = os.Stat(fileName); err == nil {
+			return Load(fileName)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return c, err
+		}
+		if _, err = os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return Default(), nil
+}
+
+// Load reads the configuration from a templ.toml file.
+func Load(fileName string) (c Config, err error) {
+	src, err := os.ReadFile(fileName)
+	if err != nil {
+		return c, err
+	}
+	if c, err = Parse(string(src)); err != nil {
+		return c, fmt.Errorf("%s: %w", fileName, err)
+	}
+	c.FileName = fileName
+	return c, nil
+}
+
+// file is the structure of a templ.toml file. Pointers distinguish options that aren't set.
+type file struct {
+	Format struct {
+		Indent              *string `toml:"indent"`
+		IndentWidth         *int    `toml:"indent_width"`
+		MaxInlineAttributes *int    `toml:"max_inline_attributes"`
+		MaxLineLength       *int    `toml:"max_line_length"`
+		AttributeQuote      *string `toml:"attribute_quote"`
+		AttributeWrap       *string `toml:"attribute_wrap"`
+	} `toml:"format"`
+	Generate Generate `toml:"generate"`
+}
+
+// Parse parses the contents of a templ.toml file. Unknown keys are an error.
+func Parse(src string) (c Config, err error) {
+	var f file
+	md, err := toml.Decode(src, &f)
+	if err != nil {
+		return c, err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return c, fmt.Errorf("%s: unknown option", undecoded[0])
+	}
+	c = Default()
+	if err = setEnum(&c.Format.Indent, f.Format.Indent, IndentTabs, IndentSpaces); err != nil {
+		return c, fmt.Errorf("format.indent: %w", err)
+	}
+	if c.Format.Indent == IndentSpaces {
+		c.Format.IndentWidth = DefaultIndentWidth
+	}
+	if err = setInt(&c.Format.IndentWidth, f.Format.IndentWidth, 1); err != nil {
+		return c, fmt.Errorf("format.indent_width: %w", err)
+	}
+	if err = setInt(&c.Format.MaxInlineAttributes, f.Format.MaxInlineAttributes, 0); err != nil {
+		return c, fmt.Errorf("format.max_inline_attributes: %w", err)
+	}
+	if err = setInt(&c.Format.MaxLineLength, f.Format.MaxLineLength, 0); err != nil {
+		return c, fmt.Errorf("format.max_line_length: %w", err)
+	}
+	if err = setEnum(&c.Format.AttributeQuote, f.Format.AttributeQuote, QuotePreserve, QuoteDouble, QuoteSingle); err != nil {
+		return c, fmt.Errorf("format.attribute_quote: %w", err)
+	}
+	if err = setEnum(&c.Format.AttributeWrap, f.Format.AttributeWrap, WrapPreserve, WrapAuto); err != nil {
+		return c, fmt.Errorf("format.attribute_wrap: %w", err)
+	}
+	c.Generate = f.Generate
+	return c, nil
+}
+
+func setEnum(dst *string, v *string, options ...string) error {
+	if v == nil {
+		return nil
+	}
+	for _, o := range options {
+		if *v == o {
+			*dst = *v
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown value %q, expected one of %q", *v, options)
+}
+
+func setInt(dst *int, v *int, minimum int) error {
+	if v == nil {
+		return nil
+	}
+	if *v < minimum {
+		return fmt.Errorf("expected %d or more, got %d", minimum, *v)
+	}
+	*dst = *v
+	return nil
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected Format
+	}{
+		{
+			name:     "empty files use the default configuration",
+			src:      "",
+			expected: Default().Format,
+		},
+		{
+			name: "all options can be set",
+			src: `# Formatting style.
+[format]
+indent = "spaces" # Use spaces.
+indent_width = 4
+max_inline_attributes = 3
+max_line_length = 1_000
+attribute_quote = 'single'
+attribute_wrap = "auto"
+`,
+			expected: Format{
+				Indent:              IndentSpaces,
+				IndentWidth:         4,
+				MaxInlineAttributes: 3,
+				MaxLineLength:       1000,
+				AttributeQuote:      QuoteSingle,
+				AttributeWrap:       WrapAuto,
+			},
+		},
+		{
+			name: "inline tables are supported",
+			src:  "format = { indent = \"spaces\", indent_width = 4 }\n",
+			expected: Format{
+				Indent:         IndentSpaces,
+				IndentWidth:    4,
+				AttributeQuote: QuotePreserve,
+				AttributeWrap:  WrapPreserve,
+			},
+		},
+		{
+			name: "spaces have a default width",
+			src:  "[format]\nindent = \"spaces\"\n",
+			expected: Format{
+				Indent:         IndentSpaces,
+				IndentWidth:    DefaultIndentWidth,
+				AttributeQuote: QuotePreserve,
+				AttributeWrap:  WrapPreserve,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, c.Format); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseGenerate(t *testing.T) {
+	c, err := Parse(`[generate]
+strict = true
+strict_allow = ["hx-*"]
+file_suffix = ".gen.go"
+
+[generate.static_data]
+"site.Title" = "My blog"
+"site.Year" = 2025
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	strict, fileSuffix := true, ".gen.go"
+	expected := Generate{
+		Strict:      &strict,
+		StrictAllow: []string{"hx-*"},
+		FileSuffix:  &fileSuffix,
+		StaticData:  map[string]any{"site.Title": "My blog", "site.Year": int64(2025)},
+	}
+	if diff := cmp.Diff(expected, c.Generate); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(Default().Format, c.Format); diff != "" {
+		t.Errorf("expected the default format:\n%s", diff)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "unknown options",
+			src:      "[format]\nindent = \"tabs\"\ntabs = true\n",
+			expected: "format.tabs: unknown option",
+		},
+		{
+			name:     "unknown generate options",
+			src:      "[generate]\nminfy = true\n",
+			expected: "generate.minfy: unknown option",
+		},
+		{
+			name:     "widths must be positive",
+			src:      "[format]\nindent_width = 0\n",
+			expected: "format.indent_width: expected 1 or more, got 0",
+		},
+		{
+			name:     "unknown values",
+			src:      "[format]\nindent = \"tab\"\n",
+			expected: `format.indent: unknown value "tab", expected one of ["tabs" "spaces"]`,
+		},
+		{
+			name:     "values of the wrong type",
+			src:      "[format]\nmax_line_length = \"100\"\n",
+			expected: `toml: line 2 (last key "format.max_line_length"): incompatible types: TOML value has type string; destination has type integer`,
+		},
+		{
+			name:     "invalid syntax",
+			src:      "[format\n",
+			expected: `toml: line 2: expected '.' or ']' to end table name, but got '\n' instead`,
+		},
+		{
+			name:     "duplicate keys",
+			src:      "[format]\nindent_width = 2\nindent_width = 4\n",
+			expected: `toml: line 3 (last key "format.indent_width"): Key 'format.indent_width' has already been defined.`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	write := func(t *testing.T, name, contents string) {
+		t.Helper()
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(t, "go.mod", "module example.com/app\n")
+	write(t, "components/button/button.templ", "")
+
+	t.Run("the default configuration is used without a templ.toml file", func(t *testing.T) {
+		c, err := Find(filepath.Join(dir, "components", "button"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(Default(), c); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("the configuration is read from the nearest parent directory", func(t *testing.T) {
+		write(t, FileName, "[format]\nindent = \"spaces\"\n")
+		c, err := Find(filepath.Join(dir, "components", "button"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.FileName != filepath.Join(dir, FileName) {
+			t.Errorf("expected the configuration of the module, got %q", c.FileName)
+		}
+		expected := parser.FormatStyle{IndentWidth: DefaultIndentWidth}
+		if diff := cmp.Diff(expected, c.Format.Style()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package templ
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DataAttributes are data-* attributes, e.g. for the hooks of JavaScript code. Keys are
+// written with the data- prefix, so they can be spread onto an element without each key
+// repeating it.
+//
+//	<div { templ.DataAttributes{"userId": user.ID, "role": "admin"}... }>
+//
+// renders <div data-role="admin" data-user-id="123">.
+//
+// Keys are converted in the same way as the keys of the dataset property in JavaScript, so
+// camelCase keys are written in kebab case, e.g. userId is written as data-user-id. Other
+// characters that aren't allowed in attribute names are replaced with dashes.
+//
+// Values are rendered in the same way as Attributes, except that maps, slices and structs are
+// encoded as JSON. Attributes are sorted by key.
+type DataAttributes map[string]any
+
+var _ Attributer = DataAttributes{}
+
+// Items returns the data-* attributes in key sorted order.
+func (a DataAttributes) Items() []KeyValue[string, any] {
+	items := make([]KeyValue[string, any], 0, len(a))
+	for k, v := range a {
+		items = append(items, KeyValue[string, any]{Key: dataAttributeKey(k), Value: dataAttributeValue(v)})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Key < items[j].Key
+	})
+	return items
+}
+
+// dataAttributeKey returns the data-* attribute name of a key.
+func dataAttributeKey(key string) string {
+	key = strings.TrimPrefix(key, "data-")
+	var sb strings.Builder
+	sb.WriteString("data-")
+	for _, r := range key {
+		switch {
+		case 'A' <= r && r <= 'Z':
+			sb.WriteByte('-')
+			sb.WriteRune(unicode.ToLower(r))
+		case ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || r == '-' || r == '_' || r == '.':
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
+
+// dataAttributeValue encodes maps, slices and structs as JSON, since RenderAttributes
+// doesn't render them.
+func dataAttributeValue(v any) any {
+	switch v.(type) {
+	case KeyValue[string, bool], KeyValue[bool, bool]:
+		return v
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		return string(b)
+	}
+	return v
+}
@@ -0,0 +1,90 @@
+package templ_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestDataAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes templ.DataAttributes
+		expected   string
+	}{
+		{
+			name: "keys are prefixed with data- and sorted",
+			attributes: templ.DataAttributes{
+				"role":  "admin",
+				"count": 3,
+				"open":  true,
+			},
+			expected: ` data-count="3" data-open data-role="admin"`,
+		},
+		{
+			name: "camelCase keys are converted to kebab case",
+			attributes: templ.DataAttributes{
+				"userId": 123,
+			},
+			expected: ` data-user-id="123"`,
+		},
+		{
+			name: "keys that already have the data- prefix are not prefixed again",
+			attributes: templ.DataAttributes{
+				"data-action": "save",
+			},
+			expected: ` data-action="save"`,
+		},
+		{
+			name: "characters that aren't allowed in attribute names are replaced",
+			attributes: templ.DataAttributes{
+				`x" onclick="alert(1)`: "value",
+			},
+			expected: ` data-x--onclick--alert-1-="value"`,
+		},
+		{
+			name: "values are escaped",
+			attributes: templ.DataAttributes{
+				"title": `"quoted" & <tagged>`,
+			},
+			expected: ` data-title="&#34;quoted&#34; &amp; &lt;tagged&gt;"`,
+		},
+		{
+			name: "maps, slices and structs are encoded as JSON",
+			attributes: templ.DataAttributes{
+				"ids":    []int{1, 2},
+				"config": map[string]any{"a": 1},
+				"point":  struct{ X int }{X: 1},
+			},
+			expected: ` data-config="{&#34;a&#34;:1}" data-ids="[1,2]" data-point="{&#34;X&#34;:1}"`,
+		},
+		{
+			name: "false and nil values are not rendered",
+			attributes: templ.DataAttributes{
+				"hidden": false,
+				"name":   (*string)(nil),
+			},
+			expected: ``,
+		},
+		{
+			name: "KeyValue values are rendered",
+			attributes: templ.DataAttributes{
+				"state": templ.KV("active", true),
+			},
+			expected: ` data-state="active"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := templ.RenderAttributes(context.Background(), &buf, tt.attributes); err != nil {
+				t.Fatalf("RenderAttributes failed: %v", err)
+			}
+			if actual := buf.String(); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
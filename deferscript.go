@@ -0,0 +1,104 @@
+package templ
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// deferredScripts are the scripts registered with DeferScript, see DeferredScripts.
+type deferredScripts struct {
+	m       sync.Mutex
+	scripts []string
+}
+
+func (d *deferredScripts) add(script string) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.scripts = append(d.scripts, script)
+}
+
+// len returns the number of scripts registered, or 0 if d is nil.
+func (d *deferredScripts) len() int {
+	if d == nil {
+		return 0
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	return len(d.scripts)
+}
+
+// truncate discards the scripts registered after the first n, e.g. by children whose output
+// was discarded by an ErrorBoundary.
+func (d *deferredScripts) truncate(n int) {
+	if d == nil {
+		return
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+	if n < len(d.scripts) {
+		d.scripts = d.scripts[:n]
+	}
+}
+
+// DeferredScripts returns a component that renders its children, followed by the scripts
+// registered by them with DeferScript, in the order they were registered. Wrap the content of
+// the <body> element with it, so that inline scripts are written just before </body>, and
+// the browser can display the page before it parses them.
+//
+//	<body>
+//		@templ.DeferredScripts() {
+//			@page()
+//		}
+//	</body>
+func DeferredScripts() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		children := GetChildren(ctx)
+		ctx, v := getContext(ClearChildren(ctx))
+		previous := v.deferredScripts
+		d := &deferredScripts{}
+		v.deferredScripts = d
+		err = children.Render(ctx, w)
+		v.deferredScripts = previous
+		if err != nil {
+			return err
+		}
+		for _, script := range d.scripts {
+			if _, err = io.WriteString(w, script); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeferScript returns a component that renders its children, e.g. an inline <script>
+// element, at the end of the nearest DeferredScripts component, instead of where it's used.
+// Outside of a DeferredScripts component, and within <template> elements, the children are
+// rendered in place.
+//
+//	@templ.DeferScript() {
+//		<script>
+//			initCarousel(document.getElementById("carousel"));
+//		</script>
+//	}
+//
+// Deferred scripts run in the order they're registered, after any scripts that aren't
+// deferred, so they mustn't be used by scripts that aren't deferred.
+func DeferScript() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		children := GetChildren(ctx)
+		ctx = ClearChildren(ctx)
+		_, v := getContext(ctx)
+		if v.deferredScripts == nil {
+			return children.Render(ctx, w)
+		}
+		buf := GetBuffer()
+		defer ReleaseBuffer(buf)
+		if err = children.Render(ctx, buf); err != nil {
+			return err
+		}
+		v.deferredScripts.add(buf.String())
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestDeferScript(t *testing.T) {
+	deferred := func(script string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return templ.DeferScript().Render(templ.WithChildren(ctx, templ.Raw("<script>"+script+"</script>")), w)
+		})
+	}
+	list := func(components ...templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			for _, c := range components {
+				if err := c.Render(ctx, w); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return errors.New("failed")
+	})
+	tests := []struct {
+		name     string
+		c        templ.Component
+		expected string
+	}{
+		{
+			name:     "scripts are rendered in place without DeferredScripts",
+			c:        list(templ.Raw("<p>a</p>"), deferred("a()"), templ.Raw("<p>b</p>")),
+			expected: "<p>a</p><script>a()</script><p>b</p>",
+		},
+		{
+			name: "scripts are rendered after the children of DeferredScripts, in order",
+			c: templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				children := list(templ.Raw("<p>a</p>"), deferred("a()"), templ.Raw("<p>b</p>"), deferred("b()"))
+				return templ.DeferredScripts().Render(templ.WithChildren(ctx, children), w)
+			}),
+			expected: "<p>a</p><p>b</p><script>a()</script><script>b()</script>",
+		},
+		{
+			name: "scripts of children that fail within an error boundary are discarded",
+			c: templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				boundary := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+					return templ.ErrorBoundary(templ.Raw("<p>fallback</p>")).Render(templ.WithChildren(ctx, list(deferred("b()"), failing)), w)
+				})
+				return templ.DeferredScripts().Render(templ.WithChildren(ctx, list(deferred("a()"), boundary)), w)
+			}),
+			expected: "<p>fallback</p><script>a()</script>",
+		},
+		{
+			name: "scripts within template elements are rendered in place",
+			c: templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				template := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+					return deferred("t()").Render(templ.WithTemplateScope(ctx), w)
+				})
+				return templ.DeferredScripts().Render(templ.WithChildren(ctx, list(deferred("a()"), template)), w)
+			}),
+			expected: "<script>t()</script><script>a()</script>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.c.Render(context.Background(), &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, sb.String())
+			}
+		})
+	}
+}
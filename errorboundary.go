@@ -0,0 +1,55 @@
+package templ
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+)
+
+const errorBoundaryContextKey = contextKeyType(1)
+
+// ErrorBoundary renders its children, or the fallback if rendering the children returns an
+// error. The output of the children is buffered, so that partial output is discarded if
+// rendering fails, and templ.Flush has no effect within the boundary.
+//
+// The fallback can get the error with ErrorBoundaryError. Errors caused by the context being
+// cancelled aren't caught.
+//
+//	@templ.ErrorBoundary(widgetUnavailable()) {
+//		@weatherWidget(location)
+//	}
+func ErrorBoundary(fallback Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		children := GetChildren(ctx)
+		ctx = ClearChildren(ctx)
+		ctx, v := getContext(ctx)
+		deferred := v.deferredScripts.len()
+		preloads := v.headManager.len()
+		// The CSS, scripts and OnceHandle content rendered by the children is discarded if
+		// they fail, so it must be rendered again by later components.
+		ss, onceHandles := maps.Clone(v.ss), maps.Clone(v.onceHandles)
+		buf := GetBuffer()
+		defer ReleaseBuffer(buf)
+		if err = children.Render(ctx, buf); err == nil {
+			_, err = w.Write(buf.Bytes())
+			return err
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		// Discard the scripts deferred, the assets preloaded and the content marked as
+		// rendered by the children, along with their output.
+		v.deferredScripts.truncate(deferred)
+		v.headManager.truncate(preloads)
+		v.ss, v.onceHandles = ss, onceHandles
+		return fallback.Render(context.WithValue(ctx, errorBoundaryContextKey, err), w)
+	})
+}
+
+// ErrorBoundaryError returns the error caught by the nearest ErrorBoundary, for use in the
+// fallback component. It returns nil outside of a fallback.
+func ErrorBoundaryError(ctx context.Context) error {
+	err, _ := ctx.Value(errorBoundaryContextKey).(error)
+	return err
+}
@@ -0,0 +1,108 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestErrorBoundary(t *testing.T) {
+	errFailed := errors.New("failed")
+	partial := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, err := io.WriteString(w, "<p>partial"); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	fallback := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<p>fallback: "+templ.ErrorBoundaryError(ctx).Error()+"</p>")
+		return err
+	})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "children are rendered if they don't return an error",
+			ctx:      templ.WithChildren(context.Background(), templ.Raw("<p>ok</p>")),
+			expected: "<p>ok</p>",
+		},
+		{
+			name:     "partial output is discarded and the fallback is rendered if the children return an error",
+			ctx:      templ.WithChildren(context.Background(), partial),
+			expected: "<p>fallback: failed</p>",
+		},
+		{
+			name:     "nothing is rendered without children",
+			ctx:      context.Background(),
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := templ.ErrorBoundary(fallback).Render(tt.ctx, &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, sb.String())
+			}
+		})
+	}
+	t.Run("errors from the fallback are returned", func(t *testing.T) {
+		ctx := templ.WithChildren(context.Background(), partial)
+		err := templ.ErrorBoundary(partial).Render(ctx, io.Discard)
+		if !errors.Is(err, errFailed) {
+			t.Errorf("expected the fallback error, got %v", err)
+		}
+	})
+	t.Run("context cancellation is not caught", func(t *testing.T) {
+		cancelled := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return context.Canceled
+		})
+		ctx := templ.WithChildren(context.Background(), cancelled)
+		err := templ.ErrorBoundary(fallback).Render(ctx, io.Discard)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+	t.Run("CSS and OnceHandle content rendered by failing children is rendered again", func(t *testing.T) {
+		class := templ.ComponentCSSClass{ID: "red", Class: templ.SafeCSS(".red{color:red;}")}
+		once := templ.NewOnceHandle(templ.WithComponent(templ.Raw("<script>once</script>")))
+		content := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if err := templ.RenderCSSItems(ctx, w, class); err != nil {
+				return err
+			}
+			return once.Once().Render(ctx, w)
+		})
+		failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if err := content.Render(ctx, w); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		ctx := templ.InitializeContext(context.Background())
+		var sb strings.Builder
+		if err := templ.ErrorBoundary(templ.NopComponent).Render(templ.WithChildren(ctx, failing), &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := content.Render(ctx, &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `<style type="text/css">.red{color:red;}</style><script>once</script>`
+		if sb.String() != expected {
+			t.Errorf("expected %q, got %q", expected, sb.String())
+		}
+	})
+	t.Run("the error is not available outside of a fallback", func(t *testing.T) {
+		if err := templ.ErrorBoundaryError(context.Background()); err != nil {
+			t.Errorf("expected nil, got %v", err)
+		}
+	})
+}
@@ -17,6 +17,18 @@ func sayHello() templ.ComponentScript {
 	}
 }
 
+// sayHelloBind returns the sayHello script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func sayHelloBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_sayHello_6bd3`,
+		Function: `function __templ_sayHello_6bd3(){alert("Hello")
+}`,
+		Call:       templ.SafeScriptBind(`__templ_sayHello_6bd3`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_sayHello_6bd3`, params...),
+	}
+}
+
 func template() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -38,7 +50,7 @@ func template() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = sayHello().Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(sayHello()).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
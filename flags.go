@@ -0,0 +1,100 @@
+package templ
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FlagProvider resolves feature flags, e.g. using LaunchDarkly, ConfigCat, or environment
+// variables, see WithFlagProvider.
+type FlagProvider interface {
+	// Enabled returns whether the flag is enabled. The context can be used to target the flag,
+	// e.g. at the user of the request. Providers that fall back to a default value when the
+	// flag can't be evaluated should return the default and a nil error.
+	Enabled(ctx context.Context, flag string) (bool, error)
+}
+
+// FlagProviderFunc is a function that implements FlagProvider.
+type FlagProviderFunc func(ctx context.Context, flag string) (bool, error)
+
+// Enabled calls f(ctx, flag).
+func (f FlagProviderFunc) Enabled(ctx context.Context, flag string) (bool, error) {
+	return f(ctx, flag)
+}
+
+// EnvFlagProvider returns a FlagProvider that reads flags from environment variables. The name
+// of the variable is the prefix followed by the flag in upper case, with dashes and dots
+// replaced by underscores, e.g. FEATURE_NEW_NAV for the prefix "FEATURE_" and the flag
+// "new-nav". Flags are enabled if the variable is set to a true value accepted by
+// strconv.ParseBool, e.g. "1" or "true".
+func EnvFlagProvider(prefix string) FlagProvider {
+	replacer := strings.NewReplacer("-", "_", ".", "_")
+	return FlagProviderFunc(func(ctx context.Context, flag string) (bool, error) {
+		value, ok := os.LookupEnv(prefix + replacer.Replace(strings.ToUpper(flag)))
+		if !ok || value == "" {
+			return false, nil
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid value %q: %w", value, err)
+		}
+		return enabled, nil
+	})
+}
+
+// WithFlagProvider sets the provider used to resolve feature flags, see Flagged.
+func WithFlagProvider(ctx context.Context, p FlagProvider) context.Context {
+	ctx, v := getContext(ctx)
+	v.flagProvider = p
+	v.flags = nil
+	return ctx
+}
+
+// FlagEnabled returns whether the feature flag is enabled. Each flag is resolved once per
+// context, so that a page that checks a flag in several places renders consistently. Flags
+// are disabled if there's no provider in the context.
+func FlagEnabled(ctx context.Context, flag string) (bool, error) {
+	ctx, v := getContext(ctx)
+	if v.flagProvider == nil {
+		return false, nil
+	}
+	if enabled, ok := v.flags[flag]; ok {
+		return enabled, nil
+	}
+	enabled, err := v.flagProvider.Enabled(ctx, flag)
+	if err != nil {
+		return false, fmt.Errorf("templ: failed to resolve feature flag %q: %w", flag, err)
+	}
+	if v.flags == nil {
+		v.flags = map[string]bool{}
+	}
+	v.flags[flag] = enabled
+	return enabled, nil
+}
+
+// Flagged renders enabled if the feature flag is enabled, and disabled if it isn't. Either
+// component can be nil to render nothing.
+//
+//	@templ.Flagged("new-nav", newNav(), oldNav())
+//
+// If the provider returns an error, it's returned by Render.
+func Flagged(flag string, enabled, disabled Component) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		on, err := FlagEnabled(ctx, flag)
+		if err != nil {
+			return err
+		}
+		c := disabled
+		if on {
+			c = enabled
+		}
+		if c == nil {
+			return nil
+		}
+		return c.Render(ctx, w)
+	})
+}
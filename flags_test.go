@@ -0,0 +1,117 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestFlagged(t *testing.T) {
+	newNav, oldNav := templ.Raw("<nav>new</nav>"), templ.Raw("<nav>old</nav>")
+	flags := templ.FlagProviderFunc(func(ctx context.Context, flag string) (bool, error) {
+		switch flag {
+		case "new-nav":
+			return true, nil
+		case "broken":
+			return false, errors.New("provider unavailable")
+		}
+		return false, nil
+	})
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		c        templ.Component
+		expected string
+	}{
+		{
+			name:     "enabled flags render the enabled component",
+			ctx:      templ.WithFlagProvider(context.Background(), flags),
+			c:        templ.Flagged("new-nav", newNav, oldNav),
+			expected: "<nav>new</nav>",
+		},
+		{
+			name:     "disabled flags render the disabled component",
+			ctx:      templ.WithFlagProvider(context.Background(), flags),
+			c:        templ.Flagged("other", newNav, oldNav),
+			expected: "<nav>old</nav>",
+		},
+		{
+			name:     "flags are disabled if there's no provider",
+			ctx:      context.Background(),
+			c:        templ.Flagged("new-nav", newNav, oldNav),
+			expected: "<nav>old</nav>",
+		},
+		{
+			name:     "nil components render nothing",
+			ctx:      templ.WithFlagProvider(context.Background(), flags),
+			c:        templ.Flagged("other", newNav, nil),
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := tt.c.Render(tt.ctx, &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, sb.String())
+			}
+		})
+	}
+	t.Run("provider errors are returned", func(t *testing.T) {
+		ctx := templ.WithFlagProvider(context.Background(), flags)
+		err := templ.Flagged("broken", newNav, oldNav).Render(ctx, &strings.Builder{})
+		if err == nil || !strings.Contains(err.Error(), "provider unavailable") {
+			t.Errorf("expected the provider error, got %v", err)
+		}
+	})
+	t.Run("flags are resolved once per context", func(t *testing.T) {
+		var calls int
+		ctx := templ.WithFlagProvider(context.Background(), templ.FlagProviderFunc(func(ctx context.Context, flag string) (bool, error) {
+			calls++
+			return calls == 1, nil
+		}))
+		c := templ.Join(templ.Flagged("new-nav", newNav, oldNav), templ.Flagged("new-nav", newNav, oldNav))
+		var sb strings.Builder
+		if err := c.Render(ctx, &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := "<nav>new</nav><nav>new</nav>"; sb.String() != expected {
+			t.Errorf("expected %q, got %q", expected, sb.String())
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 call to the provider, got %d", calls)
+		}
+	})
+}
+
+func TestEnvFlagProvider(t *testing.T) {
+	t.Setenv("FEATURE_NEW_NAV", "true")
+	t.Setenv("FEATURE_CHECKOUT_V2", "0")
+	t.Setenv("FEATURE_INVALID", "maybe")
+	p := templ.EnvFlagProvider("FEATURE_")
+	tests := []struct {
+		flag     string
+		expected bool
+	}{
+		{flag: "new-nav", expected: true},
+		{flag: "checkout.v2", expected: false},
+		{flag: "unset", expected: false},
+	}
+	for _, tt := range tests {
+		enabled, err := p.Enabled(context.Background(), tt.flag)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.flag, err)
+		}
+		if enabled != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.flag, tt.expected, enabled)
+		}
+	}
+	if _, err := p.Enabled(context.Background(), "invalid"); err == nil {
+		t.Error("expected an error for an invalid value")
+	}
+}
@@ -15,6 +15,12 @@ func RenderFragments(ctx context.Context, w io.Writer, c Component, ids ...any)
 	return c.Render(ctx, io.Discard)
 }
 
+// RenderFragment renders the fragment with the id to w, discarding the rest of the output of
+// the component, e.g. to render the @fragment "row" { ... } region of a template.
+func RenderFragment(ctx context.Context, w io.Writer, c Component, id any) error {
+	return RenderFragments(ctx, w, c, id)
+}
+
 type fragmentContextKeyType int
 
 const fragmentContextKey fragmentContextKeyType = iota
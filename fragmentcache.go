@@ -0,0 +1,109 @@
+package templ
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+)
+
+// FragmentCache stores the output of regions of templates, see CachedFragment.
+type FragmentCache interface {
+	// Get returns the output stored under the key, if it hasn't expired.
+	Get(key string) (output []byte, ok bool)
+	// Set stores the output under the key for the time to live.
+	Set(key string, output []byte, ttl time.Duration)
+}
+
+// DefaultFragmentCache is used by CachedFragment if there's no cache in the context, see
+// WithFragmentCache.
+var DefaultFragmentCache FragmentCache = NewMemoryFragmentCache()
+
+// WithFragmentCache sets the cache used by CachedFragment. If c is nil, fragments aren't
+// cached.
+func WithFragmentCache(ctx context.Context, c FragmentCache) context.Context {
+	ctx, v := getContext(ctx)
+	v.fragmentCache = c
+	v.fragmentCacheSet = true
+	return ctx
+}
+
+func getFragmentCache(ctx context.Context) FragmentCache {
+	if v, ok := ctx.Value(contextKey).(*contextValue); ok && v.fragmentCacheSet {
+		return v.fragmentCache
+	}
+	return DefaultFragmentCache
+}
+
+// CachedFragment renders its children, and stores their output in the fragment cache under
+// the key for the time to live, so that later renders with the same key write the stored
+// output instead of rendering the children again. It's used by cache blocks in templates.
+//
+//	cache("categories:" + locale, time.Minute) {
+//		@categoryMenu(categories)
+//	}
+//
+// The key must include everything the output depends on, e.g. the language of the user.
+// Output is only stored if the children render without an error, and isn't cached if the
+// key is empty, or the time to live is zero or less.
+//
+// Side effects of rendering the children aren't repeated when the stored output is used, so
+// scripts deferred with DeferScript, assets preloaded with Preload, and components rendered
+// with a OnceHandle, shouldn't be used within a cached fragment.
+func CachedFragment(key string, ttl time.Duration) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		children := GetChildren(ctx)
+		ctx = ClearChildren(ctx)
+		if children == nil {
+			return nil
+		}
+		cache := getFragmentCache(ctx)
+		if cache == nil || key == "" || ttl <= 0 {
+			return children.Render(ctx, w)
+		}
+		if output, ok := cache.Get(key); ok {
+			_, err = w.Write(output)
+			return err
+		}
+		buf := GetBuffer()
+		defer ReleaseBuffer(buf)
+		if err = children.Render(ctx, buf); err != nil {
+			return err
+		}
+		cache.Set(key, bytes.Clone(buf.Bytes()), ttl)
+		_, err = w.Write(buf.Bytes())
+		return err
+	})
+}
+
+// NewMemoryFragmentCache creates a FragmentCache that stores output in memory.
+func NewMemoryFragmentCache() *MemoryFragmentCache {
+	return &MemoryFragmentCache{store: newMemoryStore[[]byte](0)}
+}
+
+// MemoryFragmentCache is a FragmentCache that stores output in memory. Expired entries are
+// removed as new entries are added.
+type MemoryFragmentCache struct {
+	store *memoryStore[[]byte]
+}
+
+// Get returns the output stored under the key, if it hasn't expired.
+func (c *MemoryFragmentCache) Get(key string) (output []byte, ok bool) {
+	return c.store.get(key)
+}
+
+// Set stores the output under the key for the time to live.
+func (c *MemoryFragmentCache) Set(key string, output []byte, ttl time.Duration) {
+	c.store.set(key, output, ttl)
+}
+
+// Invalidate removes the output stored under the keys.
+func (c *MemoryFragmentCache) Invalidate(keys ...string) {
+	c.store.delete(keys...)
+}
+
+// InvalidateFunc removes the output stored under keys that match, e.g. all of the fragments
+// with a prefix.
+func (c *MemoryFragmentCache) InvalidateFunc(match func(key string) bool) {
+	c.store.deleteFunc(match)
+}
@@ -0,0 +1,92 @@
+package templ
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCachedFragment(t *testing.T) {
+	setup := func() (ctx context.Context, cache *MemoryFragmentCache, now *time.Time, renders *int) {
+		cache = NewMemoryFragmentCache()
+		now = &time.Time{}
+		cache.store.now = func() time.Time { return *now }
+		renders = new(int)
+		return WithFragmentCache(context.Background(), cache), cache, now, renders
+	}
+	render := func(t *testing.T, ctx context.Context, key string, ttl time.Duration, renders *int) string {
+		t.Helper()
+		children := ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			*renders++
+			_, err := io.WriteString(w, "render "+strconv.Itoa(*renders))
+			return err
+		})
+		var sb strings.Builder
+		if err := CachedFragment(key, ttl).Render(WithChildren(ctx, children), &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return sb.String()
+	}
+	expect := func(t *testing.T, expected, actual string) {
+		t.Helper()
+		if expected != actual {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	}
+
+	t.Run("output is cached until it expires", func(t *testing.T) {
+		ctx, _, now, renders := setup()
+		expect(t, "render 1", render(t, ctx, "a", time.Minute, renders))
+		expect(t, "render 1", render(t, ctx, "a", time.Minute, renders))
+		expect(t, "render 2", render(t, ctx, "b", time.Minute, renders))
+		*now = now.Add(time.Minute)
+		expect(t, "render 3", render(t, ctx, "a", time.Minute, renders))
+	})
+	t.Run("empty keys, and zero time to live aren't cached", func(t *testing.T) {
+		ctx, _, _, renders := setup()
+		expect(t, "render 1", render(t, ctx, "", time.Minute, renders))
+		expect(t, "render 2", render(t, ctx, "", time.Minute, renders))
+		expect(t, "render 3", render(t, ctx, "a", 0, renders))
+		expect(t, "render 4", render(t, ctx, "a", 0, renders))
+	})
+	t.Run("caching can be disabled with a nil cache", func(t *testing.T) {
+		ctx := WithFragmentCache(context.Background(), nil)
+		renders := new(int)
+		expect(t, "render 1", render(t, ctx, "a", time.Minute, renders))
+		expect(t, "render 2", render(t, ctx, "a", time.Minute, renders))
+	})
+	t.Run("output can be invalidated", func(t *testing.T) {
+		ctx, cache, _, renders := setup()
+		expect(t, "render 1", render(t, ctx, "menu:en", time.Minute, renders))
+		expect(t, "render 2", render(t, ctx, "menu:fr", time.Minute, renders))
+		expect(t, "render 3", render(t, ctx, "footer", time.Minute, renders))
+		cache.Invalidate("footer")
+		cache.InvalidateFunc(func(key string) bool { return strings.HasPrefix(key, "menu:") })
+		expect(t, "render 4", render(t, ctx, "menu:en", time.Minute, renders))
+		expect(t, "render 5", render(t, ctx, "menu:fr", time.Minute, renders))
+		expect(t, "render 6", render(t, ctx, "footer", time.Minute, renders))
+	})
+	t.Run("errors aren't cached", func(t *testing.T) {
+		ctx, cache, _, _ := setup()
+		expectedErr := errors.New("failed")
+		children := ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, "partial")
+			return expectedErr
+		})
+		var sb strings.Builder
+		err := CachedFragment("a", time.Minute).Render(WithChildren(ctx, children), &sb)
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("expected %v, got %v", expectedErr, err)
+		}
+		if sb.Len() != 0 {
+			t.Errorf("expected partial output not to be written, got %q", sb.String())
+		}
+		if _, ok := cache.Get("a"); ok {
+			t.Error("expected the output not to be cached")
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// writeContextDeclaration writes the functions to set and get a value declared with
+// `templ ctx Name Type`, e.g. SetTheme and GetTheme.
+func (g *generator) writeContextDeclaration(n *parser.ContextDeclaration) (err error) {
+	if n == nil {
+		return errors.New("context declaration is nil")
+	}
+	setName, getName := contextAccessorNames(n.Name.Value)
+	var tgtSymbolRange parser.Range
+	r, err := g.w.Write("// " + setName + " returns a copy of ctx with the " + n.Name.Value + " value set, and " + getName + " returns it.\n")
+	if err != nil {
+		return err
+	}
+	tgtSymbolRange.From = r.From
+	if _, err = g.w.Write("var " + setName + ", " + getName + " = templruntime.ContextValue["); err != nil {
+		return err
+	}
+	if r, err = g.w.Write(n.Type.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(n.Type, r)
+	if r, err = g.w.Write("]()\n\n"); err != nil {
+		return err
+	}
+
+	// Keep a track of symbol ranges for the LSP.
+	tgtSymbolRange.To = r.To
+	g.sourceMap.AddSymbolRange(n.Range, tgtSymbolRange)
+	return nil
+}
+
+// contextAccessorNames returns the names of the functions that set and get a context value,
+// which are exported if the name of the value is exported.
+func contextAccessorNames(name string) (setName, getName string) {
+	first, size := utf8.DecodeRuneInString(name)
+	if !unicode.IsUpper(first) {
+		return "set" + strings.ToUpper(name[:size]) + name[size:], "get" + strings.ToUpper(name[:size]) + name[size:]
+	}
+	return "Set" + name, "Get" + name
+}
@@ -0,0 +1,23 @@
+package generator
+
+import "testing"
+
+func TestContextAccessorNames(t *testing.T) {
+	tests := []struct {
+		name            string
+		expectedSetName string
+		expectedGetName string
+	}{
+		{name: "Theme", expectedSetName: "SetTheme", expectedGetName: "GetTheme"},
+		{name: "theme", expectedSetName: "setTheme", expectedGetName: "getTheme"},
+		{name: "_theme", expectedSetName: "set_theme", expectedGetName: "get_theme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setName, getName := contextAccessorNames(tt.name)
+			if setName != tt.expectedSetName || getName != tt.expectedGetName {
+				t.Errorf("expected %s and %s, got %s and %s", tt.expectedSetName, tt.expectedGetName, setName, getName)
+			}
+		})
+	}
+}
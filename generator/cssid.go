@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"github.com/a-h/templ/internal/cssid"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithStaticCSSIDs calculates the class names of CSS templates that only contain constant
+// properties when the code is generated, and writes them as package-level constants named
+// after the template, e.g. templ_7745c5c3_redCSSID for css red(). The class names are
+// calculated in the same way as templ.CSSID, so they're the same as the names calculated at
+// runtime.
+func WithStaticCSSIDs() GenerateOpt {
+	return func(g *generator) error {
+		g.options.StaticCSSIDs = true
+		return nil
+	}
+}
+
+// WithExternalCSS makes CSS templates that only contain constant properties return their
+// class name, instead of a class that renders a <style> element, and adds their CSS to the
+// output, so that it can be served as a stylesheet. It implies WithStaticCSSIDs.
+func WithExternalCSS() GenerateOpt {
+	return func(g *generator) error {
+		g.options.StaticCSSIDs = true
+		g.options.ExternalCSS = true
+		return nil
+	}
+}
+
+// staticCSSID returns the class name of a CSS template that only contains constant properties.
+func staticCSSID(n *parser.CSSTemplate, plan *cssPlan) string {
+	return cssid.Calculate(n.Name, renderCSS(plan.source, plan.constantBlocks(), ""))
+}
+
+func (g *generator) writeStaticCSSID(n *parser.CSSTemplate, plan *cssPlan) (constName string, err error) {
+	constName = "templ_7745c5c3_" + n.Name + "CSSID"
+	if _, err = g.w.Write("// " + constName + " is the class name of the " + n.Name + " CSS template.\n"); err != nil {
+		return "", err
+	}
+	if _, err = g.w.Write("const " + constName + " = " + createGoString(staticCSSID(n, plan)) + "\n\n"); err != nil {
+		return "", err
+	}
+	return constName, nil
+}
+
+func (g *generator) writeStaticCSSBody(indentLevel int, n *parser.CSSTemplate, plan *cssPlan, constName string) (err error) {
+	class := renderCSS(plan.class, plan.constantBlocks(), staticCSSID(n, plan))
+	if g.options.ExternalCSS {
+		for _, k := range plan.keyframes {
+			g.css = append(g.css, k.rule)
+		}
+		g.css = append(g.css, class)
+		_, err = g.w.WriteIndent(indentLevel, "return templ.SafeClass("+constName+")\n")
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "return templ.ComponentCSSClass{\n"); err != nil {
+		return err
+	}
+	indentLevel++
+	if _, err = g.w.WriteIndent(indentLevel, "ID: "+constName+",\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "Class: templ.SafeCSS("+createGoString(class)+"),\n"); err != nil {
+		return err
+	}
+	if err = g.writeCSSKeyframesField(indentLevel, n, plan); err != nil {
+		return err
+	}
+	indentLevel--
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
+}
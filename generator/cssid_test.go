@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorStaticCSSIDs(t *testing.T) {
+	input := `package main
+
+css primary() {
+	color: #ff0000;
+}
+
+css loading(percent int) {
+	width: { fmt.Sprintf("%d%%", percent) };
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err := Generate(tf, w, WithStaticCSSIDs()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	actual := w.String()
+	for _, expected := range []string{
+		"const templ_7745c5c3_primaryCSSID = `primary_9b879d72`",
+		"ID: templ_7745c5c3_primaryCSSID,",
+		"Class: templ.SafeCSS(`.primary_9b879d72{color:#ff0000;}`),",
+		"templ_7745c5c3_CSSID := templ.CSSID(`loading`, templ_7745c5c3_CSSBuilder.String())",
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("expected %q in output, got:\n%s", expected, actual)
+		}
+	}
+	if strings.Contains(actual, "loadingCSSID") {
+		t.Errorf("expected no constant for CSS templates with expressions, got:\n%s", actual)
+	}
+}
+
+func TestGeneratorExternalCSS(t *testing.T) {
+	input := `package main
+
+css primary() {
+	color: #ff0000;
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	op, err := Generate(tf, w, WithExternalCSS())
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	if !strings.Contains(w.String(), "return templ.SafeClass(templ_7745c5c3_primaryCSSID)") {
+		t.Errorf("expected the class name to be returned, got:\n%s", w.String())
+	}
+	if len(op.CSS) != 1 || op.CSS[0] != ".primary_9b879d72{color:#ff0000;}" {
+		t.Errorf("expected the CSS to be in the output, got %v", op.CSS)
+	}
+}
@@ -0,0 +1,312 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// cssSegment is part of the CSS of a CSS template. It's either literal text, the ID of the
+// class, or the declarations of a block.
+type cssSegment struct {
+	text  string
+	id    bool
+	block int
+}
+
+// cssPlan describes the CSS of a CSS template, so that the declarations of the template and
+// its nested rules can be written separately, and then combined into a rule set scoped under
+// the ID of the class.
+type cssPlan struct {
+	// blocks contains the declarations of the template, followed by the declarations of each
+	// nested rule.
+	blocks [][]parser.CSSProperty
+	// source of the CSS, in the nested form used in the template, used to calculate the ID.
+	source []cssSegment
+	// class contains the CSS with the nested rules scoped under the ID.
+	class []cssSegment
+	// keyframes contains the @keyframes rules of the template.
+	keyframes []cssKeyframes
+}
+
+// cssKeyframes is a @keyframes rule of a CSS template. The animation is renamed to its ID,
+// so that it doesn't clash with animations declared in other templates.
+type cssKeyframes struct {
+	name string
+	id   string
+	rule string
+}
+
+func newCSSPlan(properties []parser.CSSProperty) (p *cssPlan, err error) {
+	p = &cssPlan{}
+	var rest []parser.CSSProperty
+	for _, prop := range properties {
+		r, ok := prop.(*parser.CSSRule)
+		if !ok {
+			rest = append(rest, prop)
+			continue
+		}
+		name, ok := r.KeyframesName()
+		if !ok {
+			rest = append(rest, prop)
+			continue
+		}
+		k, err := newCSSKeyframes(name, r)
+		if err != nil {
+			return nil, err
+		}
+		p.keyframes = append(p.keyframes, k)
+		// Include the keyframes in the ID of the class, since they're rendered with it.
+		p.source = append(p.source, cssSegment{text: k.rule, block: -1})
+	}
+	if err = p.addBlock(rest, []string{"&"}, nil); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func newCSSKeyframes(name string, r *parser.CSSRule) (k cssKeyframes, err error) {
+	var sb strings.Builder
+	for _, prop := range r.Properties {
+		step, ok := prop.(*parser.CSSRule)
+		if !ok || step.IsAtRule() {
+			return k, fmt.Errorf("@keyframes %s: expected a keyframe selector, e.g. from, to or 50%%", name)
+		}
+		sb.WriteString(step.Selector)
+		sb.WriteString("{")
+		for _, sp := range step.Properties {
+			c, ok := sp.(*parser.ConstantCSSProperty)
+			if !ok {
+				return k, fmt.Errorf("@keyframes %s: only constant properties are supported", name)
+			}
+			sb.WriteString(c.String(true))
+		}
+		sb.WriteString("}")
+	}
+	k.name = name
+	k.id = templ.CSSID(name, sb.String())
+	k.rule = "@keyframes " + k.id + "{" + sb.String() + "}"
+	return k, nil
+}
+
+// renameAnimations replaces the names of the keyframes of the plan in animation properties
+// with their IDs.
+func (p *cssPlan) renameAnimations(prop parser.CSSProperty) parser.CSSProperty {
+	c, ok := prop.(*parser.ConstantCSSProperty)
+	if !ok || len(p.keyframes) == 0 || (c.Name != "animation" && c.Name != "animation-name") {
+		return prop
+	}
+	var sb strings.Builder
+	var start int
+	for i := 0; i <= len(c.Value); i++ {
+		if i < len(c.Value) && !strings.ContainsRune(" \t\n,", rune(c.Value[i])) {
+			continue
+		}
+		word := c.Value[start:i]
+		for _, k := range p.keyframes {
+			if k.name == word {
+				word = k.id
+				break
+			}
+		}
+		sb.WriteString(word)
+		if i < len(c.Value) {
+			sb.WriteByte(c.Value[i])
+		}
+		start = i + 1
+	}
+	return &parser.ConstantCSSProperty{Name: c.Name, Value: sb.String()}
+}
+
+// addBlock adds the declarations in properties, and the nested rules, to the plan. Each
+// selector contains & in the place of the class.
+func (p *cssPlan) addBlock(properties []parser.CSSProperty, selectors, atRules []string) error {
+	var declarations []parser.CSSProperty
+	var rules []*parser.CSSRule
+	for _, prop := range properties {
+		if r, ok := prop.(*parser.CSSRule); ok {
+			if _, ok := r.KeyframesName(); ok {
+				return errors.New("@keyframes rules must be at the top level of a CSS template")
+			}
+			rules = append(rules, r)
+			continue
+		}
+		declarations = append(declarations, p.renameAnimations(prop))
+	}
+	block := len(p.blocks)
+	p.blocks = append(p.blocks, declarations)
+	p.source = append(p.source, cssSegment{block: block})
+
+	// Always write the rule of the template itself, even if it's empty.
+	if len(declarations) > 0 || block == 0 {
+		for _, atRule := range atRules {
+			p.class = append(p.class, cssSegment{text: atRule + "{", block: -1})
+		}
+		p.addSelectors(selectors)
+		p.class = append(p.class, cssSegment{text: "{", block: -1}, cssSegment{block: block}, cssSegment{text: "}", block: -1})
+		for range atRules {
+			p.class = append(p.class, cssSegment{text: "}", block: -1})
+		}
+	}
+
+	for _, r := range rules {
+		p.source = append(p.source, cssSegment{text: r.Selector + "{", block: -1})
+		var err error
+		if r.IsAtRule() {
+			err = p.addBlock(r.Properties, selectors, append(append([]string{}, atRules...), r.Selector))
+		} else {
+			err = p.addBlock(r.Properties, nestSelectors(selectors, r.Selector), atRules)
+		}
+		if err != nil {
+			return err
+		}
+		p.source = append(p.source, cssSegment{text: "}", block: -1})
+	}
+	return nil
+}
+
+// addSelectors adds the comma separated selectors to the class, replacing & with the class.
+func (p *cssPlan) addSelectors(selectors []string) {
+	for i, selector := range selectors {
+		if i > 0 {
+			p.class = append(p.class, cssSegment{text: ",", block: -1})
+		}
+		for j, part := range strings.Split(selector, "&") {
+			if j > 0 {
+				p.class = append(p.class, cssSegment{text: ".", block: -1}, cssSegment{id: true, block: -1})
+			}
+			if part != "" {
+				p.class = append(p.class, cssSegment{text: part, block: -1})
+			}
+		}
+	}
+}
+
+// nestSelectors returns the selectors of a nested rule. A nested selector that contains &
+// has it replaced by the parent selector, otherwise it matches descendants of the parent.
+func nestSelectors(parents []string, nested string) (selectors []string) {
+	for _, parent := range parents {
+		for _, s := range strings.Split(nested, ",") {
+			s = strings.TrimSpace(s)
+			if strings.Contains(s, "&") {
+				selectors = append(selectors, strings.ReplaceAll(s, "&", parent))
+				continue
+			}
+			selectors = append(selectors, parent+" "+s)
+		}
+	}
+	return selectors
+}
+
+// isConstant returns true if all of the declarations in the plan are constant.
+func (p *cssPlan) isConstant() bool {
+	for _, block := range p.blocks {
+		for _, prop := range block {
+			if _, ok := prop.(*parser.ConstantCSSProperty); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// constantBlocks returns the CSS of each block of a constant plan.
+func (p *cssPlan) constantBlocks() (blocks []string) {
+	for _, block := range p.blocks {
+		var sb strings.Builder
+		for _, prop := range block {
+			sb.WriteString(prop.(*parser.ConstantCSSProperty).String(true))
+		}
+		blocks = append(blocks, sb.String())
+	}
+	return blocks
+}
+
+// renderCSS joins the segments, using the CSS of each block, and the class ID.
+func renderCSS(segments []cssSegment, blocks []string, id string) string {
+	var sb strings.Builder
+	for _, s := range segments {
+		switch {
+		case s.id:
+			sb.WriteString(id)
+		case s.block >= 0:
+			sb.WriteString(blocks[s.block])
+		default:
+			sb.WriteString(s.text)
+		}
+	}
+	return sb.String()
+}
+
+// cssExpression returns a Go expression that joins the segments, e.g.
+// `.` + templ_7745c5c3_CSSID + `{` + templ_7745c5c3_CSSBuilder.String() + `}`.
+func cssExpression(segments []cssSegment) string {
+	var parts []string
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, createGoString(text.String()))
+			text.Reset()
+		}
+	}
+	for _, s := range segments {
+		switch {
+		case s.id:
+			flush()
+			parts = append(parts, "templ_7745c5c3_CSSID")
+		case s.block >= 0:
+			flush()
+			parts = append(parts, cssBuilderName(s.block)+".String()")
+		default:
+			text.WriteString(s.text)
+		}
+	}
+	flush()
+	return strings.Join(parts, " + ")
+}
+
+// cssBuilderName returns the name of the variable that the declarations of the block are
+// written to.
+func cssBuilderName(block int) string {
+	if block == 0 {
+		return "templ_7745c5c3_CSSBuilder"
+	}
+	return "templ_7745c5c3_CSSBuilder" + strconv.Itoa(block)
+}
+
+// writeCSSKeyframes writes the @keyframes rules of a CSS template as a package-level variable,
+// so that the classes returned by the template can be compared. Keyframes of templates that
+// are written to an external stylesheet aren't needed at runtime.
+func (g *generator) writeCSSKeyframes(n *parser.CSSTemplate, plan *cssPlan, isStatic bool) (err error) {
+	if len(plan.keyframes) == 0 || (isStatic && g.options.ExternalCSS) {
+		return nil
+	}
+	varName := n.Name + "CSSKeyframes"
+	if _, err = g.w.Write("// " + varName + " are the @keyframes rules of the " + n.Name + " CSS template.\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.Write("var " + varName + " = []templ.CSSKeyframes{\n"); err != nil {
+		return err
+	}
+	for _, k := range plan.keyframes {
+		if _, err = g.w.WriteIndent(1, fmt.Sprintf("{Name: %s, ID: %s, Rule: templ.SafeCSS(%s)},\n", createGoString(k.name), createGoString(k.id), createGoString(k.rule))); err != nil {
+			return err
+		}
+	}
+	_, err = g.w.Write("}\n\n")
+	return err
+}
+
+// writeCSSKeyframesField writes the Keyframes field of the templ.ComponentCSSClass returned by
+// a CSS template.
+func (g *generator) writeCSSKeyframesField(indentLevel int, n *parser.CSSTemplate, plan *cssPlan) (err error) {
+	if len(plan.keyframes) == 0 {
+		return nil
+	}
+	_, err = g.w.WriteIndent(indentLevel, "Keyframes: &"+n.Name+"CSSKeyframes,\n")
+	return err
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorCSSKeyframes(t *testing.T) {
+	t.Run("keyframes are written to the external stylesheet", func(t *testing.T) {
+		tf, err := parser.ParseString(`package main
+
+css spinner() {
+	animation: spin 1s, fade 2s;
+	@keyframes spin {
+		to {
+			rotate: 360deg;
+		}
+	}
+}`)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		op, err := Generate(tf, new(bytes.Buffer), WithExternalCSS())
+		if err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if len(op.CSS) != 2 {
+			t.Fatalf("expected the keyframes and the class in the output, got %v", op.CSS)
+		}
+		if !strings.HasPrefix(op.CSS[0], "@keyframes spin_") {
+			t.Errorf("expected the keyframes to be renamed, got %q", op.CSS[0])
+		}
+		if !strings.Contains(op.CSS[1], "{animation:spin_") || !strings.Contains(op.CSS[1], ", fade 2s;}") {
+			t.Errorf("expected only the declared animation to be renamed, got %q", op.CSS[1])
+		}
+	})
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "keyframes can't be nested",
+			input: `package main
+
+css spinner() {
+	&:hover {
+		@keyframes spin {
+			to {
+				rotate: 360deg;
+			}
+		}
+	}
+}`,
+			expected: "css spinner: @keyframes rules must be at the top level of a CSS template",
+		},
+		{
+			name: "keyframes can't contain expressions",
+			input: `package main
+
+css spinner(deg string) {
+	@keyframes spin {
+		to {
+			rotate: { deg };
+		}
+	}
+}`,
+			expected: "css spinner: @keyframes spin: only constant properties are supported",
+		},
+		{
+			name: "keyframes must contain keyframe selectors",
+			input: `package main
+
+css spinner() {
+	@keyframes spin {
+		rotate: 360deg;
+	}
+}`,
+			expected: "css spinner: @keyframes spin: expected a keyframe selector, e.g. from, to or 50%",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			_, err = Generate(tf, new(bytes.Buffer))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
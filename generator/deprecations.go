@@ -0,0 +1,195 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/a-h/templ/parser/v2/visitor"
+)
+
+// Deprecation is a template, or a parameter of a template, that's deprecated by a
+// //templ:deprecated directive before the template.
+//
+//	//templ:deprecated Use Card instead.
+//	templ Panel(title string) {
+//
+//	//templ:deprecated size: Use variant instead.
+//	templ Button(label string, size int, variant string) {
+//
+// The generator writes a // Deprecated: comment for deprecated templates, so that Go tools
+// report their use. A template can have a directive for each deprecated parameter.
+type Deprecation struct {
+	// Template is the name of the template.
+	Template string
+	// Parameter is the name of the deprecated parameter, or "" if the template is deprecated.
+	Parameter string
+	// ParameterIndex is the index of the deprecated parameter.
+	ParameterIndex int
+	// Variadic is true if the deprecated parameter is variadic.
+	Variadic bool
+	// Message explains what to use instead.
+	Message string
+	// Range of the directive in the templ file.
+	Range parser.Range
+}
+
+func (d Deprecation) String() string {
+	if d.Parameter != "" {
+		return fmt.Sprintf("parameter %s of %s is deprecated: %s", d.Parameter, d.Template, d.Message)
+	}
+	return fmt.Sprintf("%s is deprecated: %s", d.Template, d.Message)
+}
+
+// Deprecations returns the deprecated templates and template parameters of the file. Only the
+// templates of the file can be deprecated, not methods.
+func Deprecations(tf *parser.TemplateFile) (deprecations []Deprecation, err error) {
+	for i := range tf.Nodes {
+		d, err := templateDeprecations(tf.Nodes, i)
+		if err != nil {
+			return deprecations, err
+		}
+		deprecations = append(deprecations, d...)
+	}
+	return deprecations, nil
+}
+
+// templateDeprecations returns the deprecations of the template at nodeIdx.
+func templateDeprecations(nodes []parser.TemplateFileNode, nodeIdx int) (deprecations []Deprecation, err error) {
+	t, ok := nodes[nodeIdx].(*parser.HTMLTemplate)
+	if !ok {
+		return nil, nil
+	}
+	var fn *ast.FuncDecl
+	for _, d := range nodeDirectives(nodes, nodeIdx) {
+		if d.name != "deprecated" {
+			continue
+		}
+		if fn == nil {
+			if fn, ok = parseTemplateSignature(t.Expression.Value); !ok {
+				// Invalid signatures are reported when the generated code is compiled.
+				return nil, nil
+			}
+			if fn.Recv != nil {
+				return nil, fmt.Errorf("%d:%d: //templ:deprecated directives can't be used on methods", d.r.From.Line+1, d.r.From.Col+1)
+			}
+		}
+		if d.value == "" {
+			return nil, fmt.Errorf("%d:%d: //templ:deprecated directive requires a message, e.g. //templ:deprecated Use Card instead.", d.r.From.Line+1, d.r.From.Col+1)
+		}
+		deprecations = append(deprecations, newDeprecation(fn, d))
+	}
+	return deprecations, nil
+}
+
+// newDeprecation returns the deprecation of a directive. If the message starts with the name
+// of a parameter and a colon, the parameter is deprecated, otherwise the template is.
+func newDeprecation(fn *ast.FuncDecl, d directive) Deprecation {
+	dep := Deprecation{Template: fn.Name.Name, Message: d.value, Range: d.r}
+	name, message, ok := strings.Cut(d.value, ":")
+	if !ok || strings.ContainsAny(name, " \t") {
+		return dep
+	}
+	var index int
+	for _, field := range fn.Type.Params.List {
+		for _, n := range field.Names {
+			if n.Name == name {
+				_, variadic := field.Type.(*ast.Ellipsis)
+				dep.Parameter, dep.ParameterIndex, dep.Variadic = name, index, variadic
+				dep.Message = strings.TrimSpace(message)
+				return dep
+			}
+			index++
+		}
+	}
+	return dep
+}
+
+// writeDeprecatedComment writes a // Deprecated: comment before a deprecated template, and
+// notes for its deprecated parameters.
+func (g *generator) writeDeprecatedComment(nodeIdx int) (err error) {
+	deprecations, err := templateDeprecations(g.tf.Nodes, nodeIdx)
+	if err != nil || len(deprecations) == 0 {
+		return err
+	}
+	// The parameters are noted before the template, because a // Deprecated: paragraph
+	// deprecates the whole function.
+	var template *Deprecation
+	for _, d := range deprecations {
+		if d.Parameter == "" {
+			template = &d
+			continue
+		}
+		if _, err = g.w.Write("//\n// The " + d.Parameter + " parameter is deprecated: " + d.Message + "\n"); err != nil {
+			return err
+		}
+	}
+	if template != nil {
+		if _, err = g.w.Write("//\n// Deprecated: " + template.Message + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeprecatedUses returns a diagnostic for each use of a deprecated template in the file, e.g.
+// @Panel("Title"), and each argument passed to a deprecated parameter. Only the calls of
+// templates in the same package are checked, i.e. calls without a package name.
+func DeprecatedUses(tf *parser.TemplateFile, deprecations []Deprecation) (diags []parser.Diagnostic) {
+	if len(deprecations) == 0 {
+		return nil
+	}
+	byTemplate := make(map[string][]Deprecation, len(deprecations))
+	for _, d := range deprecations {
+		byTemplate[d.Template] = append(byTemplate[d.Template], d)
+	}
+	check := func(e parser.Expression) {
+		ge, ok := parseGoExpression(ExpressionKindComponent, e)
+		if !ok {
+			return
+		}
+		call, ok := ge.Node.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		fun := call.Fun
+		switch f := fun.(type) {
+		case *ast.IndexExpr:
+			fun = f.X
+		case *ast.IndexListExpr:
+			fun = f.X
+		}
+		name, ok := fun.(*ast.Ident)
+		if !ok {
+			return
+		}
+		for _, d := range byTemplate[name.Name] {
+			if d.Parameter == "" {
+				diags = append(diags, parser.Diagnostic{Message: d.String(), Range: ge.Range(name)})
+				continue
+			}
+			for i, arg := range call.Args {
+				if i == d.ParameterIndex || (d.Variadic && i > d.ParameterIndex) {
+					diags = append(diags, parser.Diagnostic{Message: d.String(), Range: ge.Range(arg)})
+				}
+			}
+		}
+	}
+	v := visitor.New()
+	visitTemplElement := v.TemplElementExpression
+	v.TemplElementExpression = func(n *parser.TemplElementExpression) error {
+		check(n.Expression)
+		return visitTemplElement(n)
+	}
+	v.CallTemplateExpression = func(n *parser.CallTemplateExpression) error {
+		check(n.Expression)
+		return nil
+	}
+	for _, n := range tf.Nodes {
+		if t, ok := n.(*parser.HTMLTemplate); ok {
+			_ = t.Visit(v)
+		}
+	}
+	return diags
+}
@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGeneratorDeprecations(t *testing.T) {
+	input := `package main
+
+// Panel is a box with a title.
+//templ:deprecated Use Card instead.
+templ Panel(title string) {
+	<div>{ title }</div>
+}
+
+//templ:deprecated size: Use variant instead.
+templ Button(label string, size int, variant string) {
+	<button>{ label }</button>
+}
+
+templ Page() {
+	@Panel("Title")
+	@Button("Save", 2, "primary")
+	@Button("Cancel", 1, "secondary") {
+		<span>Child</span>
+	}
+}
+`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("deprecated templates have a Deprecated comment", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		for _, expected := range []string{
+			"// Panel is a box with a title.\n//templ:deprecated Use Card instead.\n//\n// Deprecated: Use Card instead.\nfunc Panel(",
+			"//templ:deprecated size: Use variant instead.\n//\n// The size parameter is deprecated: Use variant instead.\nfunc Button(",
+		} {
+			if !strings.Contains(w.String(), expected) {
+				t.Errorf("expected %q in output, got:\n%s", expected, w.String())
+			}
+		}
+	})
+
+	deprecations, err := Deprecations(tf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Run("deprecations are read from directives", func(t *testing.T) {
+		expected := []Deprecation{
+			{
+				Template: "Panel",
+				Message:  "Use Card instead.",
+				Range:    parser.Range{From: parser.NewPosition(46, 3, 0), To: parser.NewPosition(82, 3, 36)},
+			},
+			{
+				Template:       "Button",
+				Parameter:      "size",
+				ParameterIndex: 1,
+				Message:        "Use variant instead.",
+				Range:          parser.Range{From: parser.NewPosition(136, 8, 0), To: parser.NewPosition(181, 8, 45)},
+			},
+		}
+		if diff := cmp.Diff(expected, deprecations); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("uses of deprecated templates and parameters are reported", func(t *testing.T) {
+		var actual []string
+		for _, d := range DeprecatedUses(tf, deprecations) {
+			actual = append(actual, d.Message+" "+input[d.Range.From.Index:d.Range.To.Index])
+		}
+		expected := []string{
+			"Panel is deprecated: Use Card instead. Panel",
+			"parameter size of Button is deprecated: Use variant instead. 2",
+			"parameter size of Button is deprecated: Use variant instead. 1",
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("directives require a message", func(t *testing.T) {
+		tf, err := parser.ParseString("package main\n\n//templ:deprecated\ntempl Panel() {\n}\n")
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		_, err = Generate(tf, new(bytes.Buffer))
+		if err == nil || !strings.Contains(err.Error(), "requires a message") {
+			t.Errorf("expected an error, got %v", err)
+		}
+	})
+}
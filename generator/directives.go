@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// directivePrefix is the prefix of comments that configure code generation for the
+// template that follows them, e.g. //templ:buffer off
+const directivePrefix = "//templ:"
+
+// templateDirectives returns the //templ: directives in the Go comments directly before
+// the template node at nodeIdx. Directives are returned as a map of name to value.
+func (g *generator) templateDirectives(nodeIdx int) (directives map[string]string) {
+	directives = map[string]string{}
+	for _, d := range nodeDirectives(g.tf.Nodes, nodeIdx) {
+		directives[d.name] = d.value
+	}
+	return directives
+}
+
+// directive is a //templ: directive, e.g. //templ:buffer off.
+type directive struct {
+	name  string
+	value string
+	// r is the range of the directive in the templ file.
+	r parser.Range
+}
+
+// nodeDirectives returns the //templ: directives in the comment block directly before the
+// node at nodeIdx, in order. Comments that are separated from the node by a blank line or
+// Go code don't apply to it.
+func nodeDirectives(nodes []parser.TemplateFileNode, nodeIdx int) (directives []directive) {
+	if nodeIdx == 0 {
+		return nil
+	}
+	prev, ok := nodes[nodeIdx-1].(*parser.TemplateFileGoExpression)
+	if !ok {
+		return nil
+	}
+	lines := strings.Split(prev.Expression.Value, "\n")
+	// The value of the Go code doesn't include the blank lines after it.
+	if prev.Expression.Range.From.Line+uint32(len(lines)) != nodeLine(nodes[nodeIdx]) {
+		return nil
+	}
+	// Find the comment block at the end of the Go code.
+	first := len(lines)
+	for first > 0 && strings.HasPrefix(strings.TrimSpace(lines[first-1]), "//") {
+		first--
+	}
+	from := prev.Expression.Range.From
+	for i, line := range lines {
+		start := from
+		from.Index += int64(len(line)) + 1
+		from.Line++
+		from.Col = 0
+		trimmed := strings.TrimSpace(line)
+		if i < first || !strings.HasPrefix(trimmed, directivePrefix) {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		start.Index += int64(indent)
+		start.Col += uint32(indent)
+		end := start
+		end.Index += int64(len(trimmed))
+		end.Col += uint32(len(trimmed))
+		name, value, _ := strings.Cut(strings.TrimPrefix(trimmed, directivePrefix), " ")
+		directives = append(directives, directive{
+			name:  name,
+			value: strings.TrimSpace(value),
+			r:     parser.Range{From: start, To: end},
+		})
+	}
+	return directives
+}
+
+// nodeLine returns the line that the template node starts on, or 0 if it isn't a template.
+func nodeLine(n parser.TemplateFileNode) uint32 {
+	switch n := n.(type) {
+	case *parser.HTMLTemplate:
+		return n.Range.From.Line
+	case *parser.ScriptTemplate:
+		return n.Range.From.Line
+	case *parser.CSSTemplate:
+		return n.Range.From.Line
+	}
+	return 0
+}
+
+type bufferMode string
+
+const (
+	// bufferModeAuto uses the runtime buffer unless the writer is already a buffer.
+	bufferModeAuto bufferMode = ""
+	// bufferModeOff writes directly to the destination writer.
+	bufferModeOff bufferMode = "off"
+	// bufferModeOn always uses a new buffer, which is flushed when the template returns.
+	bufferModeOn bufferMode = "on"
+)
+
+func parseBufferMode(directives map[string]string) (bufferMode, error) {
+	v, ok := directives["buffer"]
+	if !ok {
+		return bufferModeAuto, nil
+	}
+	switch bufferMode(v) {
+	case bufferModeOff, bufferModeOn:
+		return bufferMode(v), nil
+	}
+	return bufferModeAuto, fmt.Errorf("invalid //templ:buffer directive value %q, expected \"on\" or \"off\"", v)
+}
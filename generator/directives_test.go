@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestNodeDirectives(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+	}{
+		{
+			name:     "directives directly before the template apply to it",
+			input:    "package main\n\n//templ:buffer off\ntempl Page() {\n}\n",
+			expected: map[string]string{"buffer": "off"},
+		},
+		{
+			name:     "directives in the comment block before the template apply to it",
+			input:    "package main\n\nvar x = 1\n\n// Page is a page.\n//templ:buffer on\n// It's buffered.\ntempl Page() {\n}\n",
+			expected: map[string]string{"buffer": "on"},
+		},
+		{
+			name:     "directives separated from the template by a blank line don't apply to it",
+			input:    "package main\n\n//templ:buffer off\n\ntempl Page() {\n}\n",
+			expected: map[string]string{},
+		},
+		{
+			name:     "directives separated from the template by Go code don't apply to it",
+			input:    "package main\n\n//templ:buffer off\nvar x = 1\n\ntempl Page() {\n}\n",
+			expected: map[string]string{},
+		},
+		{
+			name:     "directives before Go code directly before the template don't apply to it",
+			input:    "package main\n\n//templ:buffer off\nvar x = 1\ntempl Page() {\n}\n",
+			expected: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			g := &generator{tf: tf}
+			actual := g.templateDirectives(len(tf.Nodes) - 1)
+			if len(actual) != len(tt.expected) || actual["buffer"] != tt.expected["buffer"] {
+				t.Errorf("expected directives %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
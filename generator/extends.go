@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"strconv"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// writeExtends writes a template that extends a layout, e.g. templ Page() extends Base() { ... }.
+//
+// The layout is rendered with the template's blocks as slots, and its other nodes as children.
+// Slots passed to the template take precedence over its blocks, so that a block can be
+// overridden by a template that extends the template in turn.
+func (g *generator) writeExtends(indentLevel int, t *parser.HTMLTemplate) error {
+	children := make([]parser.Node, len(t.Children))
+	for i, n := range t.Children {
+		if b, ok := n.(*parser.BlockDefinition); ok {
+			n = &parser.SlotDefinition{Name: b.Name, NameRange: b.NameRange, Children: b.Children}
+		}
+		children[i] = n
+	}
+	n := &parser.TemplElementExpression{Expression: *t.Extends, Children: children}
+	return g.writeBlockTemplElementExpression(indentLevel, n, g.slotsVar)
+}
+
+// writeFragmentDefinition writes a fragment, e.g. @fragment "row" { ... }, as a call to
+// templ.Fragment, so that templ.RenderFragment can render its children on their own.
+func (g *generator) writeFragmentDefinition(indentLevel int, n *parser.FragmentDefinition) error {
+	e := &parser.TemplElementExpression{
+		Expression: parser.Expression{Value: "templ.Fragment(" + strconv.Quote(n.Name) + ")", Range: n.NameRange},
+		Children:   n.Children,
+	}
+	return g.writeBlockTemplElementExpression(indentLevel, e, "")
+}
+
+// writeCacheExpression writes a cache block, e.g. cache(key, ttl) { ... }, as a call to
+// templ.CachedFragment, so that the output of its children is stored in the fragment cache.
+func (g *generator) writeCacheExpression(indentLevel int, n *parser.CacheExpression) error {
+	e := &parser.TemplElementExpression{
+		Expression: parser.Expression{Value: "templ.CachedFragment(" + n.Expression.Value + ")", Range: n.Expression.Range},
+		Children:   n.Children,
+	}
+	return g.writeBlockTemplElementExpression(indentLevel, e, "")
+}
@@ -5,10 +5,12 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"go/constant"
 	"html"
 	"io"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -17,6 +19,7 @@ import (
 	_ "embed"
 
 	"github.com/a-h/templ/parser/v2"
+	"github.com/a-h/templ/parser/v2/visitor"
 )
 
 type GenerateOpt func(g *generator) error
@@ -59,10 +62,69 @@ func WithSkipCodeGeneratedComment() GenerateOpt {
 	}
 }
 
+// WithLiteralConstants collects the string literals of the file into package-level constants
+// and references them by name in the render functions, instead of writing them inline.
+// Constant names are derived from the filename, so WithFileName should be used when more
+// than one file in a package is generated with this option.
+func WithLiteralConstants() GenerateOpt {
+	return func(g *generator) error {
+		g.options.LiteralConstants = true
+		return nil
+	}
+}
+
+// WithLiteralBytes collects the string literals of the file into package-level byte slices,
+// like WithLiteralConstants, and writes them with templruntime.WriteBytes, so that writers
+// that don't implement io.StringWriter don't need to convert them to bytes on each render.
+func WithLiteralBytes() GenerateOpt {
+	return func(g *generator) error {
+		g.options.LiteralBytes = true
+		return nil
+	}
+}
+
+// WithRuntimeImportPath sets the import path of the templ package used by the generated
+// code, e.g. for a fork of templ. The runtime package is imported from the runtime
+// directory of the path.
+func WithRuntimeImportPath(path string) GenerateOpt {
+	return func(g *generator) error {
+		g.options.RuntimeImportPath = strings.TrimSuffix(path, "/")
+		return nil
+	}
+}
+
+// WithTextTransform passes the text of the templates through the text transformers
+// in the render context, see templ.WithTextTransformers. Text inside script and
+// style elements is not transformed.
+func WithTextTransform() GenerateOpt {
+	return func(g *generator) error {
+		g.options.TextTransform = true
+		return nil
+	}
+}
+
+// WithURLSanitizer sanitizes the expression values of the href, src, action and formaction
+// attributes of all elements with the URL sanitizer in the render context, see
+// templ.WithURLSanitizer. By default, only the href attributes of <a> and <link> elements,
+// the action attributes of <form> elements and the data attributes of <object> elements
+// are sanitized, with templ.URL.
+func WithURLSanitizer() GenerateOpt {
+	return func(g *generator) error {
+		g.options.URLSanitizer = true
+		return nil
+	}
+}
+
 type GeneratorOutput struct {
 	Options   GeneratorOptions  `json:"meta"`
 	SourceMap *parser.SourceMap `json:"sourceMap"`
 	Literals  []string          `json:"literals"`
+	// Symbols contains the code generated for each template, see WithPreviousOutput.
+	Symbols []GeneratedSymbol `json:"symbols"`
+	// Diagnostics contains the unknown element and attribute names found, see WithStrict.
+	Diagnostics []parser.Diagnostic `json:"diagnostics"`
+	// CSS contains the CSS of each constant CSS template, see WithExternalCSS.
+	CSS []string `json:"css"`
 }
 
 type GeneratorOptions struct {
@@ -74,6 +136,56 @@ type GeneratorOptions struct {
 	SkipCodeGeneratedComment bool
 	// GeneratedDate to include as a comment.
 	GeneratedDate string
+	// LiteralConstants writes string literals as package-level constants.
+	LiteralConstants bool
+	// LiteralBytes writes string literals as package-level byte slices.
+	LiteralBytes bool
+	// TextTransform passes text through the text transformers in the render context.
+	TextTransform bool
+	// URLSanitizer sanitizes URL attributes with the sanitizer in the render context.
+	URLSanitizer bool
+	// ComponentMarkers writes HTML comments around the output of each component.
+	ComponentMarkers bool
+	// ErrorSnapshots attaches the parameters of a component to rendering errors.
+	ErrorSnapshots bool
+	// TemplateRegistry registers exported templates for lookup by name.
+	TemplateRegistry bool
+	// InputRecording records the parameters of sampled component renders.
+	InputRecording bool
+	// Tracing starts a span for each component render.
+	Tracing bool
+	// Strict checks element and attribute names against the HTML vocabulary.
+	Strict bool
+	// StrictErrors returns an error if unknown names are found, instead of diagnostics.
+	StrictErrors bool
+	// StrictAllow lists additional element and attribute names accepted in strict mode.
+	StrictAllow []string
+	// StaticCSSIDs writes the class names of constant CSS templates as constants.
+	StaticCSSIDs bool
+	// ExternalCSS moves the CSS of constant CSS templates to the output, see WithExternalCSS.
+	ExternalCSS bool
+	// VoidElementStyle is how void elements are written, see WithVoidElements.
+	VoidElementStyle VoidElementStyle
+	// VoidElements are added to the list of HTML void elements.
+	VoidElements []string
+	// RuntimeImportPath is the import path of the templ package, see WithRuntimeImportPath.
+	RuntimeImportPath string
+	// Minify removes HTML comments from the output, see WithMinify.
+	Minify bool
+	// TestIDs adds data-testid attributes to the root elements of components.
+	TestIDs bool
+	// StrictNilComponents returns an error when rendering a nil component, see WithStrictNilComponents.
+	StrictNilComponents bool
+	// StrictText returns an error if text contains a bare &, < or >, see WithStrictText.
+	StrictText bool
+	// LineDirectives is the name of the generated file that //line directives map the code
+	// generated by templ back to, see WithLineDirectives. If it's empty, no directives are written.
+	LineDirectives string
+	// TemplVersion is the version of templ that the generated code is compiled with, see
+	// WithTemplVersion.
+	TemplVersion string
+	// StaticData is the build-time data used to evaluate expressions, see WithStaticData.
+	StaticData map[string]any
 }
 
 // HasGoChanged returns true if the Go code has changed between the previous and updated GeneratorOutput.
@@ -88,6 +200,66 @@ func HasGoChanged(previous, updated GeneratorOutput) bool {
 	if previous.Options.SkipCodeGeneratedComment != updated.Options.SkipCodeGeneratedComment {
 		return true
 	}
+	if previous.Options.LiteralConstants != updated.Options.LiteralConstants {
+		return true
+	}
+	if previous.Options.LiteralBytes != updated.Options.LiteralBytes {
+		return true
+	}
+	if previous.Options.TextTransform != updated.Options.TextTransform {
+		return true
+	}
+	if previous.Options.URLSanitizer != updated.Options.URLSanitizer {
+		return true
+	}
+	if previous.Options.ComponentMarkers != updated.Options.ComponentMarkers {
+		return true
+	}
+	if previous.Options.ErrorSnapshots != updated.Options.ErrorSnapshots {
+		return true
+	}
+	if previous.Options.TemplateRegistry != updated.Options.TemplateRegistry {
+		return true
+	}
+	if previous.Options.InputRecording != updated.Options.InputRecording {
+		return true
+	}
+	if previous.Options.Tracing != updated.Options.Tracing {
+		return true
+	}
+	if previous.Options.StaticCSSIDs != updated.Options.StaticCSSIDs {
+		return true
+	}
+	if previous.Options.ExternalCSS != updated.Options.ExternalCSS {
+		return true
+	}
+	if previous.Options.VoidElementStyle != updated.Options.VoidElementStyle {
+		return true
+	}
+	if !slices.Equal(previous.Options.VoidElements, updated.Options.VoidElements) {
+		return true
+	}
+	if previous.Options.RuntimeImportPath != updated.Options.RuntimeImportPath {
+		return true
+	}
+	if previous.Options.Minify != updated.Options.Minify {
+		return true
+	}
+	if previous.Options.TestIDs != updated.Options.TestIDs {
+		return true
+	}
+	if previous.Options.StrictNilComponents != updated.Options.StrictNilComponents {
+		return true
+	}
+	if previous.Options.LineDirectives != updated.Options.LineDirectives {
+		return true
+	}
+	if previous.Options.TemplVersion != updated.Options.TemplVersion {
+		return true
+	}
+	if !reflect.DeepEqual(previous.Options.StaticData, updated.Options.StaticData) {
+		return true
+	}
 	// We don't check the generated date as it's not used for determining if the file has changed.
 	// If the number of literals has changed, we need to recompile.
 	if len(previous.Literals) != len(updated.Literals) {
@@ -126,11 +298,47 @@ func Generate(template *parser.TemplateFile, w io.Writer, opts ...GenerateOpt) (
 		w:         NewRangeWriter(w),
 		sourceMap: parser.NewSourceMap(),
 	}
+	g.w.UseSourceMap(g.sourceMap)
 	for _, opt := range opts {
 		if err = opt(g); err != nil {
 			return
 		}
 	}
+	switch {
+	case g.options.LiteralBytes:
+		g.w.UseLiteralBytes(literalConstantPrefix(g.options.FileName, template.Filepath))
+	case g.options.LiteralConstants:
+		g.w.UseLiteralConstants(literalConstantPrefix(g.options.FileName, template.Filepath))
+	}
+	if g.target == nil {
+		g.target = HTMLTarget{
+			VoidElementStyle: g.options.VoidElementStyle,
+			VoidElements:     g.options.VoidElements,
+		}
+	}
+	if g.options.LineDirectives != "" {
+		if g.options.FileName == "" {
+			return op, errors.New("line directives require the name of the templ file, see WithFileName")
+		}
+		g.w.UseLineDirectives(g.options.LineDirectives)
+	}
+	op.Diagnostics, err = g.checkVocabulary()
+	if err != nil {
+		return op, err
+	}
+	if err = g.checkURLs(); err != nil {
+		return op, err
+	}
+	if err = g.checkStrictText(); err != nil {
+		return op, err
+	}
+	op.Diagnostics = append(op.Diagnostics, g.checkTemplVersion()...)
+	validationDiagnostics, err := g.validateExpressions()
+	op.Diagnostics = append(op.Diagnostics, validationDiagnostics...)
+	if err != nil {
+		return op, err
+	}
+	g.startIncremental()
 	err = g.generate()
 	if err != nil {
 		return op, err
@@ -138,6 +346,8 @@ func Generate(template *parser.TemplateFile, w io.Writer, opts ...GenerateOpt) (
 	op.Options = g.options
 	op.SourceMap = g.sourceMap
 	op.Literals = g.w.Literals
+	op.Symbols = g.symbols
+	op.CSS = g.css
 	return op, nil
 }
 
@@ -147,6 +357,39 @@ type generator struct {
 	sourceMap   *parser.SourceMap
 	variableID  int
 	childrenVar string
+	// slotsVar is the variable that holds the slots of the template, if it uses any.
+	slotsVar string
+	// bufferMode of the template being generated, set by the //templ:buffer directive.
+	bufferMode bufferMode
+	// staticData used for partial evaluation, see WithStaticData.
+	staticData map[string]constant.Value
+	// staticBound are the names declared by the template being generated, which aren't
+	// evaluated with static data, see boundNames.
+	staticBound map[string]bool
+	// previous output used for incremental generation, see WithPreviousOutput.
+	previous        *GeneratorOutput
+	previousSymbols map[string]GeneratedSymbol
+	symbols         []GeneratedSymbol
+	// css of the constant CSS templates, see WithExternalCSS.
+	css []string
+	// scriptTranspiler compiles TypeScript script templates, see WithScriptTranspiler.
+	scriptTranspiler ScriptTranspiler
+	// expressionValidators check the Go expressions of templates, see WithExpressionValidators.
+	expressionValidators []ExpressionValidator
+	// testIDs of the root elements of the template being generated, see WithTestIDs.
+	testIDs map[*parser.Element]string
+	// target is the output format, see WithTarget.
+	target Target
+	// nodeWriters write nodes in place of the generator, by node type, see WithNodeWriter.
+	nodeWriters map[reflect.Type]NodeWriter
+	// activeNodeWriters are the types of the node writers that are running.
+	activeNodeWriters map[reflect.Type]bool
+	// nonceVar is the variable that records whether the spread or conditional attributes of the
+	// <script> or <style> element being generated set a nonce, see writeElementAttributesWithNonce.
+	nonceVar string
+	// testIDVar is the variable that records whether the spread attributes of the root element
+	// being generated set a data-testid attribute, see writeElementAttributesWithTestID.
+	testIDVar string
 
 	options GeneratorOptions
 }
@@ -176,6 +419,12 @@ func (g *generator) generate() (err error) {
 	if err = g.writeBlankAssignmentForRuntimeImport(); err != nil {
 		return
 	}
+	if err = g.writeTemplateRegistry(); err != nil {
+		return
+	}
+	if err = g.writeLiteralConstants(); err != nil {
+		return
+	}
 	return err
 }
 
@@ -235,10 +484,14 @@ func (g *generator) writePackage() error {
 func (g *generator) writeImports() error {
 	var err error
 	// Always import templ because it's the interface type of all templates.
-	if _, err = g.w.Write("import \"github.com/a-h/templ\"\n"); err != nil {
+	templImport, runtimeImport := `"github.com/a-h/templ"`, `templruntime "github.com/a-h/templ/runtime"`
+	if path := g.options.RuntimeImportPath; path != "" {
+		templImport, runtimeImport = "templ "+strconv.Quote(path), "templruntime "+strconv.Quote(path+"/runtime")
+	}
+	if _, err = g.w.Write("import " + templImport + "\n"); err != nil {
 		return err
 	}
-	if _, err = g.w.Write("import templruntime \"github.com/a-h/templ/runtime\"\n"); err != nil {
+	if _, err = g.w.Write("import " + runtimeImport + "\n"); err != nil {
 		return err
 	}
 	if _, err = g.w.Write("\n"); err != nil {
@@ -255,7 +508,7 @@ func (g *generator) writeTemplateNodes() error {
 				return err
 			}
 		case *parser.HTMLTemplate:
-			if err := g.writeTemplate(i, n); err != nil {
+			if err := g.writeTemplateSymbol(i, n); err != nil {
 				return err
 			}
 		case *parser.CSSTemplate:
@@ -263,9 +516,17 @@ func (g *generator) writeTemplateNodes() error {
 				return err
 			}
 		case *parser.ScriptTemplate:
-			if err := g.writeScript(n); err != nil {
+			if err := g.writeScript(i, n); err != nil {
+				return err
+			}
+		case *parser.ContextDeclaration:
+			if err := g.writeContextDeclaration(n); err != nil {
 				return err
 			}
+		case *parser.ErrorNode:
+			// The source couldn't be parsed, but the rest of the file is generated, so that
+			// the LSP can use it.
+			continue
 		default:
 			return fmt.Errorf("unknown node type: %v", reflect.TypeOf(n))
 		}
@@ -282,6 +543,24 @@ func (g *generator) writeCSS(n *parser.CSSTemplate) error {
 	var err error
 	var indentLevel int
 
+	plan, err := newCSSPlan(n.Properties)
+	if err != nil {
+		return fmt.Errorf("css %s: %w", n.Name, err)
+	}
+	isStatic := g.options.StaticCSSIDs && plan.isConstant()
+	if err = g.writeCSSKeyframes(n, plan, isStatic); err != nil {
+		return err
+	}
+	var staticCSSID string
+	if isStatic {
+		if staticCSSID, err = g.writeStaticCSSID(n, plan); err != nil {
+			return err
+		}
+	}
+
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
 	// func
 	if r, err = g.w.Write("func "); err != nil {
 		return err
@@ -295,36 +574,46 @@ func (g *generator) writeCSS(n *parser.CSSTemplate) error {
 	if _, err = g.w.Write(" templ.CSSClass {\n"); err != nil {
 		return err
 	}
-	{
-		indentLevel++
-		// templ_7745c5c3_CSSBuilder := templruntim.GetBuilder()
-		if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_CSSBuilder := templruntime.GetBuilder()\n"); err != nil {
+	if isStatic {
+		if err = g.writeStaticCSSBody(indentLevel+1, n, plan, staticCSSID); err != nil {
 			return err
 		}
-		for _, p := range n.Properties {
-			switch p := p.(type) {
-			case *parser.ConstantCSSProperty:
-				// Constant CSS property values are not sanitized.
-				if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_CSSBuilder.WriteString("+createGoString(p.String(true))+")\n"); err != nil {
-					return err
-				}
-			case *parser.ExpressionCSSProperty:
-				// templ_7745c5c3_CSSBuilder.WriteString(templ.SanitizeCSS('name', p.Expression()))
-				if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_CSSBuilder.WriteString(string(templ.SanitizeCSS(`%s`, ", p.Name)); err != nil {
-					return err
-				}
-				if r, err = g.w.Write(p.Value.Expression.Value); err != nil {
-					return err
-				}
-				g.sourceMap.Add(p.Value.Expression, r)
-				if _, err = g.w.Write(")))\n"); err != nil {
-					return err
+	} else {
+		indentLevel++
+		for i, block := range plan.blocks {
+			builder := cssBuilderName(i)
+			// templ_7745c5c3_CSSBuilder := templruntim.GetBuilder()
+			if _, err = g.w.WriteIndent(indentLevel, builder+" := templruntime.GetBuilder()\n"); err != nil {
+				return err
+			}
+			for _, p := range block {
+				switch p := p.(type) {
+				case *parser.ConstantCSSProperty:
+					// Constant CSS property values are not sanitized.
+					if _, err = g.w.WriteIndent(indentLevel, builder+".WriteString("+createGoString(p.String(true))+")\n"); err != nil {
+						return err
+					}
+				case *parser.ExpressionCSSProperty:
+					if err = g.writeLineDirective(p.Value.Expression.Range); err != nil {
+						return err
+					}
+					// templ_7745c5c3_CSSBuilder.WriteString(templ.SanitizeCSS('name', p.Expression()))
+					if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("%s.WriteString(string(templ.SanitizeCSS(`%s`, ", builder, p.Name)); err != nil {
+						return err
+					}
+					if r, err = g.w.Write(p.Value.Expression.Value); err != nil {
+						return err
+					}
+					g.sourceMap.Add(p.Value.Expression, r)
+					if _, err = g.w.Write(")))\n"); err != nil {
+						return err
+					}
+				default:
+					return fmt.Errorf("unknown CSS property type: %v", reflect.TypeOf(p))
 				}
-			default:
-				return fmt.Errorf("unknown CSS property type: %v", reflect.TypeOf(p))
 			}
 		}
-		if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_CSSID := templ.CSSID(`%s`, templ_7745c5c3_CSSBuilder.String())\n", n.Name)); err != nil {
+		if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_CSSID := templ.CSSID(`%s`, %s)\n", n.Name, cssExpression(plan.source))); err != nil {
 			return err
 		}
 		// return templ.CSS {
@@ -338,7 +627,10 @@ func (g *generator) writeCSS(n *parser.CSSTemplate) error {
 				return err
 			}
 			// Class: templ.SafeCSS(".cssID{" + templ.CSSBuilder.String() + "}"),
-			if _, err = g.w.WriteIndent(indentLevel, "Class: templ.SafeCSS(`.` + templ_7745c5c3_CSSID + `{` + templ_7745c5c3_CSSBuilder.String() + `}`),\n"); err != nil {
+			if _, err = g.w.WriteIndent(indentLevel, "Class: templ.SafeCSS("+cssExpression(plan.class)+"),\n"); err != nil {
+				return err
+			}
+			if err = g.writeCSSKeyframesField(indentLevel, n, plan); err != nil {
 				return err
 			}
 			indentLevel--
@@ -366,6 +658,9 @@ func (g *generator) writeGoExpression(n *parser.TemplateFileGoExpression) (err e
 	}
 	var tgtSymbolRange parser.Range
 
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
 	r, err := g.w.Write(n.Expression.Value)
 	if err != nil {
 		return err
@@ -393,6 +688,18 @@ func (g *generator) writeGoExpression(n *parser.TemplateFileGoExpression) (err e
 }
 
 func (g *generator) writeTemplBuffer(indentLevel int) (err error) {
+	switch g.bufferMode {
+	case bufferModeOff:
+		// templ_7745c5c3_Buffer := templruntime.GetUnbufferedWriter(templ_7745c5c3_W)
+		_, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Buffer := templruntime.GetUnbufferedWriter(templ_7745c5c3_W)\n")
+		return err
+	case bufferModeOn:
+		// templ_7745c5c3_Buffer := templruntime.GetNewBuffer(templ_7745c5c3_W)
+		if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Buffer := templruntime.GetNewBuffer(templ_7745c5c3_W)\n"); err != nil {
+			return err
+		}
+		return g.writeReleaseBuffer(indentLevel)
+	}
 	// templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)\n"); err != nil {
 		return err
@@ -408,40 +715,43 @@ func (g *generator) writeTemplBuffer(indentLevel int) (err error) {
 	if _, err = g.w.WriteIndent(indentLevel, "if !templ_7745c5c3_IsBuffer {\n"); err != nil {
 		return err
 	}
+	if err = g.writeReleaseBuffer(indentLevel + 1); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+		return err
+	}
+	return
+}
+
+func (g *generator) writeReleaseBuffer(indentLevel int) (err error) {
+	if _, err = g.w.WriteIndent(indentLevel, "defer func() {\n"); err != nil {
+		return err
+	}
 	{
 		indentLevel++
-		if _, err = g.w.WriteIndent(indentLevel, "defer func() {\n"); err != nil {
+		if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)\n"); err != nil {
+			return err
+		}
+		if _, err = g.w.WriteIndent(indentLevel, "if templ_7745c5c3_Err == nil {\n"); err != nil {
 			return err
 		}
 		{
 			indentLevel++
-			if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)\n"); err != nil {
-				return err
-			}
-			if _, err = g.w.WriteIndent(indentLevel, "if templ_7745c5c3_Err == nil {\n"); err != nil {
-				return err
-			}
-			{
-				indentLevel++
-				if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ_7745c5c3_BufErr\n"); err != nil {
-					return err
-				}
-				indentLevel--
-			}
-			if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+			if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ_7745c5c3_BufErr\n"); err != nil {
 				return err
 			}
 			indentLevel--
 		}
-		if _, err = g.w.WriteIndent(indentLevel, "}()\n"); err != nil {
+		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 			return err
 		}
 		indentLevel--
 	}
-	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+	if _, err = g.w.WriteIndent(indentLevel, "}()\n"); err != nil {
 		return err
 	}
-	return
+	return nil
 }
 
 func (g *generator) writeTemplate(nodeIdx int, t *parser.HTMLTemplate) error {
@@ -453,6 +763,19 @@ func (g *generator) writeTemplate(nodeIdx int, t *parser.HTMLTemplate) error {
 	var err error
 	var indentLevel int
 
+	if g.bufferMode, err = parseBufferMode(g.templateDirectives(nodeIdx)); err != nil {
+		return err
+	}
+	if g.staticData != nil {
+		g.staticBound = boundNames(t)
+	}
+	if err = g.writeDeprecatedComment(nodeIdx); err != nil {
+		return err
+	}
+
+	if err = g.writeLineDirective(t.Expression.Range); err != nil {
+		return err
+	}
 	// func
 	if r, err = g.w.Write("func "); err != nil {
 		return err
@@ -490,9 +813,18 @@ func (g *generator) writeTemplate(nodeIdx int, t *parser.HTMLTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 			return err
 		}
+		if err = g.writeStartRender(indentLevel, t); err != nil {
+			return err
+		}
 		if err := g.writeTemplBuffer(indentLevel); err != nil {
 			return err
 		}
+		if err = g.writeErrorSnapshot(indentLevel, t); err != nil {
+			return err
+		}
+		if err = g.writeRecordInputs(indentLevel, t); err != nil {
+			return err
+		}
 		// ctx = templ.InitializeContext(ctx)
 		if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.InitializeContext(ctx)\n"); err != nil {
 			return err
@@ -518,12 +850,34 @@ func (g *generator) writeTemplate(nodeIdx int, t *parser.HTMLTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 			return err
 		}
+		g.slotsVar = ""
+		if t.Extends != nil || usesSlots(t.Children) {
+			g.slotsVar = g.createVariableName()
+			// templ_7745c5c3_Var2 := templ.GetSlots(ctx)
+			if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("%s := templ.GetSlots(ctx)\n", g.slotsVar)); err != nil {
+				return err
+			}
+		}
 		// ctx = templ.ClearChildren(children)
+		// The slots are cleared with the children, so that they aren't passed to components
+		// further down the tree, even by templates that don't use slots.
 		if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.ClearChildren(ctx)\n"); err != nil {
 			return err
 		}
+		if err = g.writeComponentMarker(indentLevel, "Begin", t); err != nil {
+			return err
+		}
 		// Nodes.
-		if err = g.writeNodes(indentLevel, stripWhitespace(t.Children), nil); err != nil {
+		g.collectTestIDs(t)
+		if t.Extends != nil {
+			err = g.writeExtends(indentLevel, t)
+		} else {
+			err = g.writeNodes(indentLevel, stripWhitespace(t.Children), nil)
+		}
+		if err != nil {
+			return err
+		}
+		if err = g.writeComponentMarker(indentLevel, "End", t); err != nil {
 			return err
 		}
 		// return nil
@@ -557,6 +911,23 @@ func (g *generator) writeTemplate(nodeIdx int, t *parser.HTMLTemplate) error {
 	return nil
 }
 
+// usesSlots returns true if the nodes contain any { slot "name" } expressions or blocks.
+func usesSlots(nodes []parser.Node) (ok bool) {
+	v := visitor.New()
+	v.SlotExpression = func(n *parser.SlotExpression) error {
+		ok = true
+		return nil
+	}
+	v.BlockDefinition = func(n *parser.BlockDefinition) error {
+		ok = true
+		return nil
+	}
+	for _, n := range nodes {
+		_ = n.Visit(v)
+	}
+	return ok
+}
+
 func stripWhitespace(input []parser.Node) (output []parser.Node) {
 	for i, n := range input {
 		if _, isWhiteSpace := n.(*parser.Whitespace); !isWhiteSpace {
@@ -606,6 +977,12 @@ func (g *generator) writeNodes(indentLevel int, nodes []parser.Node, next parser
 }
 
 func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.Node) (err error) {
+	if fn, ok := g.nodeWriter(current); ok {
+		if err = g.writeNodeWithWriter(indentLevel, current, fn); err != nil {
+			return err
+		}
+		return g.writeTrailingWhitespace(indentLevel, current, next)
+	}
 	switch n := current.(type) {
 	case *parser.DocType:
 		err = g.writeDocType(indentLevel, n)
@@ -615,6 +992,16 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 		err = g.writeComment(indentLevel, n)
 	case *parser.ChildrenExpression:
 		err = g.writeChildrenExpression(indentLevel)
+	case *parser.SlotExpression:
+		err = g.writeSlotExpression(indentLevel, n)
+	case *parser.BlockDefinition:
+		err = g.writeBlockDefinition(indentLevel, n)
+	case *parser.FragmentDefinition:
+		err = g.writeFragmentDefinition(indentLevel, n)
+	case *parser.CacheExpression:
+		err = g.writeCacheExpression(indentLevel, n)
+	case *parser.SlotDefinition:
+		err = fmt.Errorf("slot %q: slot definitions must be placed directly within a templ element, e.g. @layout() { slot %s { ... } }", n.Name, n.Name)
 	case *parser.RawElement:
 		err = g.writeRawElement(indentLevel, n)
 	case *parser.ScriptElement:
@@ -636,14 +1023,28 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 	case *parser.Whitespace:
 		err = g.writeWhitespace(indentLevel, n)
 	case *parser.Text:
-		err = g.writeText(indentLevel, n)
+		if g.options.TextTransform {
+			err = g.writeTransformedText(indentLevel, n)
+		} else {
+			err = g.writeText(indentLevel, n)
+		}
+	case *parser.TextBlock:
+		err = g.writeTextBlock(indentLevel, n)
 	case *parser.GoComment:
 		// Do not render Go comments in the output HTML.
 		return
 	default:
 		return fmt.Errorf("unhandled type: %v", reflect.TypeOf(n))
 	}
-	// Write trailing whitespace, if there is a next node that might need the space.
+	if err != nil {
+		return err
+	}
+	return g.writeTrailingWhitespace(indentLevel, current, next)
+}
+
+// writeTrailingWhitespace writes the trailing whitespace of the current node, if there is a
+// next node that might need the space.
+func (g *generator) writeTrailingWhitespace(indentLevel int, current parser.Node, next parser.Node) (err error) {
 	// If the next node is inline or text, we might need it.
 	// If the current node is a block element, we don't need it.
 	needed := (isInlineOrText(current) && isInlineOrText(next))
@@ -652,7 +1053,7 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 			return err
 		}
 	}
-	return
+	return nil
 }
 
 func isInlineOrText(next parser.Node) bool {
@@ -669,9 +1070,13 @@ func isInlineOrText(next parser.Node) bool {
 	case *parser.ForExpression:
 		return true
 	case *parser.Element:
-		return !n.IsBlockElement()
+		// Template elements are formatted as blocks, but they can be used within text, e.g.
+		// within a <p> element, and whitespace around them is kept.
+		return !n.IsBlockElement() || strings.EqualFold(n.Name, "template")
 	case *parser.Text:
 		return true
+	case *parser.TextBlock:
+		return true
 	case *parser.StringExpression:
 		return true
 	}
@@ -694,10 +1099,7 @@ func (g *generator) writeWhitespaceTrailer(indentLevel int, n parser.TrailingSpa
 }
 
 func (g *generator) writeDocType(indentLevel int, n *parser.DocType) (err error) {
-	if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf("<!doctype %s>", escapeQuotes(n.Value))); err != nil {
-		return err
-	}
-	return nil
+	return g.writeTargetLiteral(indentLevel, g.target.DocType(n.Value))
 }
 
 func escapeQuotes(s string) string {
@@ -707,6 +1109,9 @@ func escapeQuotes(s string) string {
 
 func (g *generator) writeIfExpression(indentLevel int, n *parser.IfExpression, nextNode parser.Node) (err error) {
 	var r parser.Range
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
 	// if
 	if _, err = g.w.WriteIndent(indentLevel, `if `); err != nil {
 		return err
@@ -728,6 +1133,9 @@ func (g *generator) writeIfExpression(indentLevel int, n *parser.IfExpression, n
 		indentLevel--
 	}
 	for _, elseIf := range n.ElseIfs {
+		if err = g.writeLineDirective(elseIf.Expression.Range); err != nil {
+			return err
+		}
 		// } else if {
 		if _, err = g.w.WriteIndent(indentLevel, `} else if `); err != nil {
 			return err
@@ -771,6 +1179,9 @@ func (g *generator) writeIfExpression(indentLevel int, n *parser.IfExpression, n
 
 func (g *generator) writeSwitchExpression(indentLevel int, n *parser.SwitchExpression, next parser.Node) (err error) {
 	var r parser.Range
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
 	// switch
 	if _, err = g.w.WriteIndent(indentLevel, `switch `); err != nil {
 		return err
@@ -787,6 +1198,9 @@ func (g *generator) writeSwitchExpression(indentLevel int, n *parser.SwitchExpre
 
 	if len(n.Cases) > 0 {
 		for _, c := range n.Cases {
+			if err = g.writeLineDirective(c.Expression.Range); err != nil {
+				return err
+			}
 			// case x:
 			// default:
 			if r, err = g.w.WriteIndent(indentLevel, c.Expression.Value); err != nil {
@@ -818,16 +1232,96 @@ func (g *generator) writeChildrenExpression(indentLevel int) (err error) {
 }
 
 func (g *generator) writeTemplElementExpression(indentLevel int, n *parser.TemplElementExpression) (err error) {
+	if n.Spread {
+		if !g.supports(spreadComponentsVersion) {
+			// Before spreads were added, @items... was @items followed by text.
+			if err = g.writeSelfClosingTemplElementExpression(indentLevel, n); err != nil {
+				return err
+			}
+			return g.writeText(indentLevel, &parser.Text{Value: "..."})
+		}
+		return g.writeSpreadTemplElementExpression(indentLevel, n)
+	}
 	if len(n.Children) == 0 {
 		return g.writeSelfClosingTemplElementExpression(indentLevel, n)
 	}
-	return g.writeBlockTemplElementExpression(indentLevel, n)
+	return g.writeBlockTemplElementExpression(indentLevel, n, "")
 }
 
-func (g *generator) writeBlockTemplElementExpression(indentLevel int, n *parser.TemplElementExpression) (err error) {
-	var r parser.Range
+// writeBlockTemplElementExpression writes the templ element. If inheritedSlots is set, the
+// slots in the variable are passed to the component, taking precedence over the slot
+// definitions of the element.
+func (g *generator) writeBlockTemplElementExpression(indentLevel int, n *parser.TemplElementExpression, inheritedSlots string) (err error) {
+	var children []parser.Node
+	var slots []*parser.SlotDefinition
+	for _, child := range n.Children {
+		if slot, isSlot := child.(*parser.SlotDefinition); isSlot {
+			slots = append(slots, slot)
+			continue
+		}
+		children = append(children, child)
+	}
 	childrenName := g.createVariableName()
-	if _, err = g.w.WriteIndent(indentLevel, childrenName+" := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {\n"); err != nil {
+	if err = g.writeChildrenComponent(indentLevel, childrenName, children); err != nil {
+		return err
+	}
+	// templ.WithChildren(ctx, children)
+	renderCtx := "templ.WithChildren(ctx, " + childrenName + ")"
+	if len(slots) == 0 && inheritedSlots != "" {
+		// templ.WithSlots(templ.WithChildren(ctx, children), templ_7745c5c3_Var2)
+		renderCtx = "templ.WithSlots(" + renderCtx + ", " + inheritedSlots + ")"
+	}
+	if len(slots) > 0 {
+		slotNames := make([]string, len(slots))
+		for i, slot := range slots {
+			slotNames[i] = g.createVariableName()
+			if err = g.writeChildrenComponent(indentLevel, slotNames[i], slot.Children); err != nil {
+				return err
+			}
+		}
+		// templ.WithSlots(templ.WithChildren(ctx, children), templ.Slots{"header": slot})
+		var sb strings.Builder
+		sb.WriteString("templ.WithSlots(" + renderCtx + ", ")
+		if inheritedSlots != "" {
+			// templ_7745c5c3_Var2.Merge(templ.Slots{"header": slot})
+			sb.WriteString(inheritedSlots + ".Merge(")
+		}
+		sb.WriteString("templ.Slots{")
+		for i, slot := range slots {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(strconv.Quote(slot.Name) + ": " + slotNames[i])
+		}
+		sb.WriteString("}")
+		if inheritedSlots != "" {
+			sb.WriteString(")")
+		}
+		sb.WriteString(")")
+		renderCtx = sb.String()
+	}
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
+		return err
+	}
+	if err = g.writeComponentExpression(n.Expression); err != nil {
+		return err
+	}
+	// .Render(templ.WithChildren(ctx, children), templ_7745c5c3_Buffer)
+	if _, err = g.w.Write(".Render(" + renderCtx + ", templ_7745c5c3_Buffer)\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeChildrenComponent writes a component that renders the nodes to a variable.
+func (g *generator) writeChildrenComponent(indentLevel int, name string, nodes []parser.Node) (err error) {
+	if _, err = g.w.WriteIndent(indentLevel, name+" := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {\n"); err != nil {
 		return err
 	}
 	indentLevel++
@@ -841,7 +1335,7 @@ func (g *generator) writeBlockTemplElementExpression(indentLevel int, n *parser.
 	if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.InitializeContext(ctx)\n"); err != nil {
 		return err
 	}
-	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children), nil); err != nil {
+	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(nodes), nil); err != nil {
 		return err
 	}
 	// return nil
@@ -852,35 +1346,102 @@ func (g *generator) writeBlockTemplElementExpression(indentLevel int, n *parser.
 	if _, err = g.w.WriteIndent(indentLevel, "})\n"); err != nil {
 		return err
 	}
-	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
+	return nil
+}
+
+func (g *generator) writeSlotExpression(indentLevel int, n *parser.SlotExpression) (err error) {
+	if g.slotsVar == "" {
+		return errors.New("slot expression used outside of a template")
+	}
+	// templ_7745c5c3_Err = templ_7745c5c3_Var2.Get("header").Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_Err = %s.Get(%s).Render(ctx, templ_7745c5c3_Buffer)\n", g.slotsVar, strconv.Quote(n.Name))); err != nil {
 		return err
 	}
-	if r, err = g.w.Write(n.Expression.Value); err != nil {
+	return g.writeErrorHandler(indentLevel)
+}
+
+func (g *generator) writeBlockDefinition(indentLevel int, n *parser.BlockDefinition) (err error) {
+	if g.slotsVar == "" {
+		return errors.New("block used outside of a template")
+	}
+	// if templ_7745c5c3_Var2.Has("title") {
+	if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("if %s.Has(%s) {\n", g.slotsVar, strconv.Quote(n.Name))); err != nil {
 		return err
 	}
-	g.sourceMap.Add(n.Expression, r)
-	// .Render(templ.WithChildren(ctx, children), templ_7745c5c3_Buffer)
-	if _, err = g.w.Write(".Render(templ.WithChildren(ctx, " + childrenName + "), templ_7745c5c3_Buffer)\n"); err != nil {
+	{
+		indentLevel++
+		// templ_7745c5c3_Err = templ_7745c5c3_Var2.Get("title").Render(ctx, templ_7745c5c3_Buffer)
+		if _, err = g.w.WriteIndent(indentLevel, fmt.Sprintf("templ_7745c5c3_Err = %s.Get(%s).Render(ctx, templ_7745c5c3_Buffer)\n", g.slotsVar, strconv.Quote(n.Name))); err != nil {
+			return err
+		}
+		if err = g.writeErrorHandler(indentLevel); err != nil {
+			return err
+		}
+		indentLevel--
+	}
+	// } else {
+	if _, err = g.w.WriteIndent(indentLevel, "} else {\n"); err != nil {
 		return err
 	}
-	if err = g.writeErrorHandler(indentLevel); err != nil {
+	{
+		indentLevel++
+		if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children), nil); err != nil {
+			return err
+		}
+		indentLevel--
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n *parser.TemplElementExpression) (err error) {
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
 	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
 		return err
 	}
 	// Template expression.
+	if err = g.writeComponentExpression(n.Expression); err != nil {
+		return err
+	}
+	// .Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.Write(".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeSpreadTemplElementExpression writes @items..., which renders each component of a slice
+// of any type that implements templ.Component.
+func (g *generator) writeSpreadTemplElementExpression(indentLevel int, n *parser.TemplElementExpression) (err error) {
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
+	// templruntime.Spread(
+	prefix, suffix := "templruntime.Spread(", ")"
+	if g.options.StrictNilComponents {
+		// templruntime.SpreadStrict(
+		prefix = "templruntime.SpreadStrict("
+		// , "items", "template.templ", 3, 2)
+		suffix = ", " + strconv.Quote(n.Expression.Value) + ", " + createGoString(g.options.FileName) + ", " + strconv.Itoa(int(n.Expression.Range.From.Line+1)) + ", " + strconv.Itoa(int(n.Expression.Range.From.Col)) + ")"
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = "+prefix); err != nil {
+		return err
+	}
+	// Template expression.
 	var r parser.Range
 	if r, err = g.w.Write(n.Expression.Value); err != nil {
 		return err
 	}
 	g.sourceMap.Add(n.Expression, r)
-	// .Render(ctx, templ_7745c5c3_Buffer)
-	if _, err = g.w.Write(".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
+	// ).Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.Write(suffix + ".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
 		return err
 	}
 	if err = g.writeErrorHandler(indentLevel); err != nil {
@@ -890,15 +1451,16 @@ func (g *generator) writeSelfClosingTemplElementExpression(indentLevel int, n *p
 }
 
 func (g *generator) writeCallTemplateExpression(indentLevel int, n *parser.CallTemplateExpression) (err error) {
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
 	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = `); err != nil {
 		return err
 	}
 	// Template expression.
-	var r parser.Range
-	if r, err = g.w.Write(n.Expression.Value); err != nil {
+	if err = g.writeComponentExpression(n.Expression); err != nil {
 		return err
 	}
-	g.sourceMap.Add(n.Expression, r)
 	// .Render(ctx, templ_7745c5c3_Buffer)
 	if _, err = g.w.Write(".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
 		return err
@@ -911,6 +1473,9 @@ func (g *generator) writeCallTemplateExpression(indentLevel int, n *parser.CallT
 
 func (g *generator) writeForExpression(indentLevel int, n *parser.ForExpression, next parser.Node) (err error) {
 	var r parser.Range
+	if err = g.writeLineDirective(n.Expression.Range); err != nil {
+		return err
+	}
 	// for
 	if _, err = g.w.WriteIndent(indentLevel, `for `); err != nil {
 		return err
@@ -976,9 +1541,9 @@ func (g *generator) writeExpressionErrorHandler(indentLevel int, expression pars
 }
 
 func (g *generator) writeElement(indentLevel int, n *parser.Element) (err error) {
-	if len(n.Attributes) == 0 {
+	if _, hasTestID := g.testIDs[n]; (len(n.Attributes) == 0 && !hasTestID) || !g.target.Attributes() {
 		// <div>
-		if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`<%s>`, html.EscapeString(n.Name))); err != nil {
+		if err = g.writeTargetLiteral(indentLevel, g.target.StartTag(n.Name)+g.target.StartTagEnd(n)); err != nil {
 			return err
 		}
 	} else {
@@ -992,27 +1557,32 @@ func (g *generator) writeElement(indentLevel int, n *parser.Element) (err error)
 			return err
 		}
 		// <div
-		if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`<%s`, html.EscapeString(n.Name))); err != nil {
+		if err = g.writeTargetLiteral(indentLevel, g.target.StartTag(n.Name)); err != nil {
 			return err
 		}
-		if err = g.writeElementAttributes(indentLevel, n.Name, attrs); err != nil {
+		if err = g.writeElementAttributesWithTestID(indentLevel, n, attrs); err != nil {
 			return err
 		}
 		// >
-		if _, err = g.w.WriteStringLiteral(indentLevel, `>`); err != nil {
+		if err = g.writeTargetLiteral(indentLevel, g.target.StartTagEnd(n)); err != nil {
 			return err
 		}
 	}
 	// Skip children and close tag for void elements.
-	if n.IsVoidElement() && len(n.Children) == 0 {
+	if !g.target.HasEndTag(n) {
 		return nil
 	}
 	// Children.
-	if err = g.writeNodes(indentLevel, stripWhitespace(n.Children), nil); err != nil {
+	if strings.EqualFold(n.Name, "template") {
+		err = g.writeTemplateElementChildren(indentLevel, stripWhitespace(n.Children))
+	} else {
+		err = g.writeNodes(indentLevel, stripWhitespace(n.Children), nil)
+	}
+	if err != nil {
 		return err
 	}
 	// </div>
-	if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`</%s>`, html.EscapeString(n.Name))); err != nil {
+	if err = g.writeTargetLiteral(indentLevel, g.target.EndTag(n)); err != nil {
 		return err
 	}
 	return err
@@ -1029,6 +1599,9 @@ func (g *generator) writeAttributeCSS(indentLevel int, attr *parser.ExpressionAt
 	// The expression can either be expecting a templ.Classes call, or an expression that returns
 	// var templ_7745c5c3_CSSClasses = []any{
 	classesName := g.createVariableName()
+	if err = g.writeLineDirective(attr.Expression.Range); err != nil {
+		return
+	}
 	if _, err = g.w.WriteIndent(indentLevel, "var "+classesName+" = []any{"); err != nil {
 		return
 	}
@@ -1089,8 +1662,10 @@ func (g *generator) writeElementCSS(indentLevel int, attrs []parser.Attribute) (
 	return g.writeAttributesCSS(indentLevel, attrs)
 }
 
+// isScriptAttribute returns true if the attribute value is JavaScript, i.e. it's an event
+// handler, or an HTMX hx-on attribute in any of its forms.
 func isScriptAttribute(name string) bool {
-	for _, prefix := range []string{"on", "hx-on:"} {
+	for _, prefix := range []string{"on", "hx-on:", "hx-on-", "data-hx-on:", "data-hx-on-"} {
 		if strings.HasPrefix(name, prefix) {
 			return true
 		}
@@ -1150,6 +1725,9 @@ func (g *generator) writeAttributeKey(indentLevel int, attr parser.AttributeKey)
 		if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string\n"); err != nil {
 			return err
 		}
+		if err = g.writeLineDirective(attr.Expression.Range); err != nil {
+			return err
+		}
 		// vn, templ_7745c5c3_Err = templ.JoinStringErrs(
 		if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.JoinStringErrs("); err != nil {
 			return err
@@ -1198,6 +1776,15 @@ func (g *generator) writeConstantAttribute(indentLevel int, attr *parser.Constan
 }
 
 func (g *generator) writeBoolExpressionAttribute(indentLevel int, attr *parser.BoolExpressionAttribute) (err error) {
+	if value, ok := g.evaluateStaticBool(attr.Expression.Value); ok {
+		if !value {
+			return nil
+		}
+		return g.writeAttributeKey(indentLevel, attr.Key)
+	}
+	if err = g.writeLineDirective(attr.Expression.Range); err != nil {
+		return err
+	}
 	// if
 	if _, err = g.w.WriteIndent(indentLevel, `if `); err != nil {
 		return err
@@ -1228,12 +1815,21 @@ func (g *generator) writeBoolExpressionAttribute(indentLevel int, attr *parser.B
 
 func (g *generator) writeExpressionAttributeValueURL(indentLevel int, attr *parser.ExpressionAttribute) (err error) {
 	vn := g.createVariableName()
+	// The expression may return an error as well as the URL, so it can't be passed to a
+	// function with ctx. With URL sanitizers, the URL is sanitized when it's written instead.
+	urlType, join, value := "templ.SafeURL", "templ.JoinURLErrs(", vn
+	if g.options.URLSanitizer {
+		urlType, join, value = "templ.ContextURL", "templ.JoinContextURLErrs(", vn+".Sanitize(ctx)"
+	}
 	// var vn templ.SafeURL
-	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" templ.SafeURL\n"); err != nil {
+	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" "+urlType+"\n"); err != nil {
+		return err
+	}
+	if err = g.writeLineDirective(attr.Expression.Range); err != nil {
 		return err
 	}
 	// vn, templ_7745c5c3_Err = templ.JoinURLErrs(
-	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.JoinURLErrs("); err != nil {
+	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = "+join); err != nil {
 		return err
 	}
 	// p.Name()
@@ -1252,7 +1848,7 @@ func (g *generator) writeExpressionAttributeValueURL(indentLevel int, attr *pars
 		return err
 	}
 	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(vn)
-	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString("+vn+"))\n"); err != nil {
+	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString("+value+"))\n"); err != nil {
 		return err
 	}
 	return g.writeErrorHandler(indentLevel)
@@ -1261,6 +1857,9 @@ func (g *generator) writeExpressionAttributeValueURL(indentLevel int, attr *pars
 func (g *generator) writeExpressionAttributeValueScript(indentLevel int, attr *parser.ExpressionAttribute) (err error) {
 	// It's a JavaScript handler, and requires special handling, because we expect a JavaScript expression.
 	vn := g.createVariableName()
+	if err = g.writeLineDirective(attr.Expression.Range); err != nil {
+		return err
+	}
 	// var vn templ.ComponentScript =
 	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" templ.ComponentScript = "); err != nil {
 		return err
@@ -1281,14 +1880,21 @@ func (g *generator) writeExpressionAttributeValueScript(indentLevel int, attr *p
 }
 
 func (g *generator) writeExpressionAttributeValueDefault(indentLevel int, attr *parser.ExpressionAttribute) (err error) {
+	if value, ok := g.evaluateStatic(attr.Expression.Value); ok {
+		_, err = g.w.WriteStringLiteralSource(indentLevel, escapeQuotes(html.EscapeString(value)), attr.Expression)
+		return err
+	}
 	var r parser.Range
 	vn := g.createVariableName()
 	// var vn string
 	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string\n"); err != nil {
 		return err
 	}
-	// vn, templ_7745c5c3_Err = templ.JoinStringErrs(
-	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.JoinStringErrs("); err != nil {
+	if err = g.writeLineDirective(attr.Expression.Range); err != nil {
+		return err
+	}
+	// vn, templ_7745c5c3_Err = templ.JoinAttrErrs(
+	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.JoinAttrErrs("); err != nil {
 		return err
 	}
 	// p.Name()
@@ -1320,6 +1926,9 @@ func (g *generator) writeExpressionAttributeValueStyle(indentLevel int, attr *pa
 	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineDirective(attr.Expression.Range); err != nil {
+		return err
+	}
 	// vn, templ_7745c5c3_Err = templruntime.SanitizeStyleAttributeValues(
 	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templruntime.SanitizeStyleAttributeValues("); err != nil {
 		return err
@@ -1356,7 +1965,7 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 	}
 	attrKey := html.EscapeString(attr.Key.String())
 	// Value.
-	if isExpressionAttributeValueURL(elementName, attrKey) {
+	if isExpressionAttributeValueURL(elementName, attrKey) || g.options.URLSanitizer && isURLAttribute(attrKey) {
 		if err := g.writeExpressionAttributeValueURL(indentLevel, attr); err != nil {
 			return err
 		}
@@ -1381,6 +1990,12 @@ func (g *generator) writeExpressionAttribute(indentLevel int, elementName string
 }
 
 func (g *generator) writeSpreadAttributes(indentLevel int, attr *parser.SpreadAttributes) (err error) {
+	if err = g.writeLineDirective(attr.Expression.Range); err != nil {
+		return err
+	}
+	if g.nonceVar != "" || g.testIDVar != "" {
+		return g.writeCheckedSpreadAttributes(indentLevel, attr)
+	}
 	// templ.RenderAttributes(ctx, w, spreadAttrs)
 	if _, err = g.w.WriteIndent(indentLevel, `templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, `); err != nil {
 		return err
@@ -1401,7 +2016,53 @@ func (g *generator) writeSpreadAttributes(indentLevel int, attr *parser.SpreadAt
 	return nil
 }
 
+// writeCheckedSpreadAttributes writes spread attributes, and records whether they include a
+// nonce or data-testid attribute, see writeElementAttributesWithNonce and
+// writeElementAttributesWithTestID.
+func (g *generator) writeCheckedSpreadAttributes(indentLevel int, attr *parser.SpreadAttributes) (err error) {
+	// var templ_7745c5c3_Var2 templ.Attributer = spreadAttrs
+	vn := g.createVariableName()
+	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" templ.Attributer = "); err != nil {
+		return err
+	}
+	var r parser.Range
+	if r, err = g.w.Write(attr.Expression.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(attr.Expression, r)
+	if _, err = g.w.Write("\n"); err != nil {
+		return err
+	}
+	// templ.RenderAttributes(ctx, w, templ_7745c5c3_Var2)
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderAttributes(ctx, templ_7745c5c3_Buffer, "+vn+")\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	for _, check := range []struct{ variable, name string }{{g.nonceVar, "nonce"}, {g.testIDVar, "data-testid"}} {
+		if check.variable == "" {
+			continue
+		}
+		// if templ.HasAttribute(templ_7745c5c3_Var2, "nonce") {
+		if _, err = g.w.WriteIndent(indentLevel, "if templ.HasAttribute("+vn+", \""+check.name+"\") {\n"); err != nil {
+			return err
+		}
+		if _, err = g.w.WriteIndent(indentLevel+1, check.variable+" = true\n"); err != nil {
+			return err
+		}
+		// }
+		if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (g *generator) writeConditionalAttribute(indentLevel int, elementName string, attr *parser.ConditionalAttribute) (err error) {
+	if err = g.writeLineDirective(attr.Expression.Range); err != nil {
+		return err
+	}
 	// if
 	if _, err = g.w.WriteIndent(indentLevel, `if `); err != nil {
 		return err
@@ -1421,6 +2082,9 @@ func (g *generator) writeConditionalAttribute(indentLevel int, elementName strin
 		if err = g.writeElementAttributes(indentLevel, elementName, attr.Then); err != nil {
 			return err
 		}
+		if err = g.writeNonceSet(indentLevel, attr.Then); err != nil {
+			return err
+		}
 		indentLevel--
 	}
 	if len(attr.Else) > 0 {
@@ -1433,6 +2097,9 @@ func (g *generator) writeConditionalAttribute(indentLevel int, elementName strin
 			if err = g.writeElementAttributes(indentLevel, elementName, attr.Else); err != nil {
 				return err
 			}
+			if err = g.writeNonceSet(indentLevel, attr.Else); err != nil {
+				return err
+			}
 			indentLevel--
 		}
 	}
@@ -1443,6 +2110,19 @@ func (g *generator) writeConditionalAttribute(indentLevel int, elementName strin
 	return nil
 }
 
+// writeNonceSet records that the attributes of a conditional attribute branch include a nonce,
+// see writeElementAttributesWithNonce.
+func (g *generator) writeNonceSet(indentLevel int, attrs []parser.Attribute) (err error) {
+	if g.nonceVar == "" {
+		return nil
+	}
+	if always, _ := nonceAttribute(attrs); !always {
+		return nil
+	}
+	_, err = g.w.WriteIndent(indentLevel, g.nonceVar+" = true\n")
+	return err
+}
+
 func (g *generator) writeElementAttributes(indentLevel int, name string, attrs []parser.Attribute) (err error) {
 	for _, attr := range attrs {
 		switch attr := attr.(type) {
@@ -1466,7 +2146,24 @@ func (g *generator) writeElementAttributes(indentLevel int, name string, attrs [
 }
 
 func (g *generator) writeRawElement(indentLevel int, n *parser.RawElement) (err error) {
-	if len(n.Attributes) == 0 {
+	if !g.target.RawElements() {
+		return nil
+	}
+	if strings.EqualFold(n.Name, "style") {
+		// <style nonce="...">
+		if err = g.writeElementScript(indentLevel, n.Attributes); err != nil {
+			return err
+		}
+		if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`<%s`, html.EscapeString(n.Name))); err != nil {
+			return err
+		}
+		if err = g.writeElementAttributesWithNonce(indentLevel, n.Name, n.Attributes); err != nil {
+			return err
+		}
+		if _, err = g.w.WriteStringLiteral(indentLevel, `>`); err != nil {
+			return err
+		}
+	} else if len(n.Attributes) == 0 {
 		// <div>
 		if _, err = g.w.WriteStringLiteral(indentLevel, fmt.Sprintf(`<%s>`, html.EscapeString(n.Name))); err != nil {
 			return err
@@ -1500,27 +2197,24 @@ func (g *generator) writeRawElement(indentLevel int, n *parser.RawElement) (err
 }
 
 func (g *generator) writeScriptElement(indentLevel int, n *parser.ScriptElement) (err error) {
-	if len(n.Attributes) == 0 {
-		// <div>
-		if _, err = g.w.WriteStringLiteral(indentLevel, `<script>`); err != nil {
-			return err
-		}
-	} else {
-		// <script></script>
-		if err = g.writeElementScript(indentLevel, n.Attributes); err != nil {
-			return err
-		}
-		// <div
-		if _, err = g.w.WriteStringLiteral(indentLevel, "<script"); err != nil {
-			return err
-		}
-		if err = g.writeElementAttributes(indentLevel, "script", n.Attributes); err != nil {
-			return err
-		}
-		// >
-		if _, err = g.w.WriteStringLiteral(indentLevel, `>`); err != nil {
-			return err
-		}
+	if !g.target.RawElements() {
+		return nil
+	}
+	// <script></script>
+	if err = g.writeElementScript(indentLevel, n.Attributes); err != nil {
+		return err
+	}
+	// <script
+	if _, err = g.w.WriteStringLiteral(indentLevel, "<script"); err != nil {
+		return err
+	}
+	// nonce="..."
+	if err = g.writeElementAttributesWithNonce(indentLevel, "script", n.Attributes); err != nil {
+		return err
+	}
+	// >
+	if _, err = g.w.WriteStringLiteral(indentLevel, `>`); err != nil {
+		return err
 	}
 	// Contents.
 	for _, c := range n.Contents {
@@ -1535,6 +2229,84 @@ func (g *generator) writeScriptElement(indentLevel int, n *parser.ScriptElement)
 	return err
 }
 
+// writeElementAttributesWithNonce writes the attributes of a <script> or <style> element,
+// followed by the CSP nonce from the context, unless the element has its own nonce attribute.
+// Whether spread and conditional attributes include a nonce is only known when the element is
+// rendered, so it's recorded in a variable while they're written.
+func (g *generator) writeElementAttributesWithNonce(indentLevel int, name string, attrs []parser.Attribute) (err error) {
+	always, maybe := nonceAttribute(attrs)
+	if always {
+		return g.writeElementAttributes(indentLevel, name, attrs)
+	}
+	if !maybe {
+		if err = g.writeElementAttributes(indentLevel, name, attrs); err != nil {
+			return err
+		}
+		return g.writeNonceAttribute(indentLevel)
+	}
+	// var templ_7745c5c3_Var1 bool
+	nonceVar := g.createVariableName()
+	if _, err = g.w.WriteIndent(indentLevel, "var "+nonceVar+" bool\n"); err != nil {
+		return err
+	}
+	previous := g.nonceVar
+	g.nonceVar = nonceVar
+	err = g.writeElementAttributes(indentLevel, name, attrs)
+	g.nonceVar = previous
+	if err != nil {
+		return err
+	}
+	// if !templ_7745c5c3_Var1 {
+	if _, err = g.w.WriteIndent(indentLevel, "if !"+nonceVar+" {\n"); err != nil {
+		return err
+	}
+	if err = g.writeNonceAttribute(indentLevel + 1); err != nil {
+		return err
+	}
+	// }
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
+}
+
+// nonceAttribute returns whether the attributes always include a nonce attribute, and whether
+// their spread or conditional attributes may include one.
+func nonceAttribute(attrs []parser.Attribute) (always, maybe bool) {
+	for _, attr := range attrs {
+		var key parser.AttributeKey
+		switch attr := attr.(type) {
+		case *parser.ConstantAttribute:
+			key = attr.Key
+		case *parser.BoolConstantAttribute:
+			key = attr.Key
+		case *parser.ExpressionAttribute:
+			key = attr.Key
+		case *parser.BoolExpressionAttribute:
+			key = attr.Key
+		case *parser.SpreadAttributes:
+			maybe = true
+		case *parser.ConditionalAttribute:
+			thenAlways, thenMaybe := nonceAttribute(attr.Then)
+			elseAlways, elseMaybe := nonceAttribute(attr.Else)
+			if thenAlways || thenMaybe || elseAlways || elseMaybe {
+				maybe = true
+			}
+		}
+		if k, ok := key.(parser.ConstantAttributeKey); ok && strings.EqualFold(k.Name, "nonce") {
+			always = true
+		}
+	}
+	return always, maybe
+}
+
+// writeNonceAttribute writes the CSP nonce from the context as an attribute.
+func (g *generator) writeNonceAttribute(indentLevel int) (err error) {
+	// templ.RenderNonceAttribute(ctx, w)
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = templ.RenderNonceAttribute(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
+		return err
+	}
+	return g.writeErrorHandler(indentLevel)
+}
+
 func (g *generator) writeScriptContents(indentLevel int, c parser.ScriptContents) (err error) {
 	if c.Value != nil {
 		if *c.Value == "" {
@@ -1554,6 +2326,9 @@ func (g *generator) writeScriptContents(indentLevel int, c parser.ScriptContents
 		if c.InsideStringLiteral {
 			fnCall = "templruntime.ScriptContentInsideStringLiteral"
 		}
+		if err = g.writeLineDirective(c.GoCode.Expression.Range); err != nil {
+			return err
+		}
 		if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err := "+fnCall+"("); err != nil {
 			return err
 		}
@@ -1594,19 +2369,11 @@ func (g *generator) writeScriptContents(indentLevel int, c parser.ScriptContents
 }
 
 func (g *generator) writeComment(indentLevel int, c *parser.HTMLComment) (err error) {
-	// <!--
-	if _, err = g.w.WriteStringLiteral(indentLevel, "<!--"); err != nil {
-		return err
-	}
-	// Contents.
-	if err = g.writeText(indentLevel, &parser.Text{Value: c.Contents}); err != nil {
-		return err
-	}
-	// -->
-	if _, err = g.w.WriteStringLiteral(indentLevel, "-->"); err != nil {
-		return err
+	if g.options.Minify && !isConditionalComment(c) {
+		return nil
 	}
-	return err
+	// <!-- Contents -->
+	return g.writeTargetLiteral(indentLevel, g.target.Comment(c.Contents))
 }
 
 func (g *generator) createVariableName() string {
@@ -1619,6 +2386,9 @@ func (g *generator) writeGoCode(indentLevel int, e parser.Expression) (err error
 		return
 	}
 	var r parser.Range
+	if err = g.writeLineDirective(e.Range); err != nil {
+		return err
+	}
 	if r, err = g.w.WriteIndent(indentLevel, e.Value+"\n"); err != nil {
 		return err
 	}
@@ -1630,12 +2400,21 @@ func (g *generator) writeStringExpression(indentLevel int, e parser.Expression)
 	if strings.TrimSpace(e.Value) == "" {
 		return
 	}
+	if value, ok := g.evaluateStatic(e.Value); ok && !g.options.TextTransform {
+		if s := g.target.EscapeString(value); s != "" {
+			_, err = g.w.WriteStringLiteralSource(indentLevel, escapeQuotes(s), e)
+		}
+		return err
+	}
 	var r parser.Range
 	vn := g.createVariableName()
 	// var vn string
 	if _, err = g.w.WriteIndent(indentLevel, "var "+vn+" string\n"); err != nil {
 		return err
 	}
+	if err = g.writeLineDirective(e.Range); err != nil {
+		return err
+	}
 	// vn, templ_7745c5c3_Err = templ.JoinStringErrs(
 	if _, err = g.w.WriteIndent(indentLevel, vn+", templ_7745c5c3_Err = templ.JoinStringErrs("); err != nil {
 		return err
@@ -1656,8 +2435,11 @@ func (g *generator) writeStringExpression(indentLevel int, e parser.Expression)
 		return err
 	}
 
-	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(vn)
-	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString("+vn+"))\n"); err != nil {
+	if g.options.TextTransform {
+		vn = "templ.TransformText(ctx, " + vn + ")"
+	}
+	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(vn))
+	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("+g.target.EscapeExpression(vn)+")\n"); err != nil {
 		return err
 	}
 	if err = g.writeErrorHandler(indentLevel); err != nil {
@@ -1678,8 +2460,27 @@ func (g *generator) writeWhitespace(indentLevel int, n *parser.Whitespace) (err
 }
 
 func (g *generator) writeText(indentLevel int, n *parser.Text) (err error) {
-	_, err = g.w.WriteStringLiteral(indentLevel, escapeQuotes(n.Value))
-	return err
+	return g.writeTargetLiteral(indentLevel, g.target.Text(n.Value))
+}
+
+// writeTextBlock writes the text of a text block. Unlike text, the contents of a text block
+// aren't parsed as HTML, so they're escaped.
+func (g *generator) writeTextBlock(indentLevel int, n *parser.TextBlock) (err error) {
+	return g.writeTargetLiteral(indentLevel, g.target.EscapeString(n.Value))
+}
+
+// writeTransformedText writes text that is passed through the text transformers at runtime.
+// Text is unescaped before it's transformed, and escaped again afterwards.
+func (g *generator) writeTransformedText(indentLevel int, n *parser.Text) (err error) {
+	if strings.TrimSpace(n.Value) == "" {
+		return g.writeText(indentLevel, n)
+	}
+	text := strconv.Quote(html.UnescapeString(n.Value))
+	// _, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ.TransformText(ctx, "text")))
+	if _, err = g.w.WriteIndent(indentLevel, "_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("+g.target.EscapeExpression("templ.TransformText(ctx, "+text+")")+")\n"); err != nil {
+		return err
+	}
+	return g.writeErrorHandler(indentLevel)
 }
 
 func createGoString(s string) string {
@@ -1696,7 +2497,7 @@ func createGoString(s string) string {
 	return sb.String()
 }
 
-func (g *generator) writeScript(t *parser.ScriptTemplate) error {
+func (g *generator) writeScript(nodeIdx int, t *parser.ScriptTemplate) error {
 	if t == nil {
 		return errors.New("script template is nil")
 	}
@@ -1704,7 +2505,17 @@ func (g *generator) writeScript(t *parser.ScriptTemplate) error {
 	var tgtSymbolRange parser.Range
 	var err error
 	var indentLevel int
+	fn := functionName(t.Name.Value, t.Value)
+	params := stripTypes(t.Parameters.Value)
+	// Function: `function scriptName(a, b, c){` + `constantScriptValue` + `}`,
+	function, err := g.scriptFunction(t.Name.Value, fn, params, strings.TrimLeftFunc(t.Value, unicode.IsSpace), g.templateDirectives(nodeIdx))
+	if err != nil {
+		return err
+	}
 
+	if err = g.writeLineDirective(t.Name.Range); err != nil {
+		return err
+	}
 	// func
 	if r, err = g.w.Write("func "); err != nil {
 		return err
@@ -1728,50 +2539,66 @@ func (g *generator) writeScript(t *parser.ScriptTemplate) error {
 		return err
 	}
 	indentLevel++
+	// Call: templ.SafeScript(scriptName, a, b, c)
+	// CallInline: templ.SafeScriptInline(scriptName, a, b, c)
+	if err = g.writeComponentScript(indentLevel, fn, function, "templ.SafeScript("+createGoString(fn)+", "+params+")", "templ.SafeScriptInline("+createGoString(fn)+", "+params+")"); err != nil {
+		return err
+	}
+	indentLevel--
+	// }
+	if r, err = g.w.WriteIndent(indentLevel, "}\n\n"); err != nil {
+		return err
+	}
+
+	// Keep track of the symbol range for the LSP.
+	tgtSymbolRange.To = r.To
+	g.sourceMap.AddSymbolRange(t.Range, tgtSymbolRange)
+
+	// func scriptNameBind(params ...any) templ.ComponentScript {
+	bindName := t.Name.Value + "Bind"
+	if _, err = g.w.Write("// " + bindName + " returns the " + t.Name.Value + " script with params as its first arguments, followed\n" +
+		"// by the arguments of the event handler that calls it.\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.Write("func " + bindName + "(params ...any) templ.ComponentScript {\n"); err != nil {
+		return err
+	}
+	// Call: templ.SafeScriptBind(scriptName, params...)
+	// CallInline: templ.SafeScriptInline(scriptName, params...)
+	if err = g.writeComponentScript(1, fn, function, "templ.SafeScriptBind("+createGoString(fn)+", params...)", "templ.SafeScriptInline("+createGoString(fn)+", params...)"); err != nil {
+		return err
+	}
+	_, err = g.w.Write("}\n\n")
+	return err
+}
+
+// writeComponentScript writes a return statement for the templ.ComponentScript of a script
+// template, with the Go expressions of its calls.
+func (g *generator) writeComponentScript(indentLevel int, fn, function, call, callInline string) (err error) {
 	// return templ.ComponentScript{
 	if _, err = g.w.WriteIndent(indentLevel, "return templ.ComponentScript{\n"); err != nil {
 		return err
 	}
 	{
 		indentLevel++
-		fn := functionName(t.Name.Value, t.Value)
-		goFn := createGoString(fn)
 		// Name: "scriptName",
-		if _, err = g.w.WriteIndent(indentLevel, "Name: "+goFn+",\n"); err != nil {
+		if _, err = g.w.WriteIndent(indentLevel, "Name: "+createGoString(fn)+",\n"); err != nil {
 			return err
 		}
-		// Function: `function scriptName(a, b, c){` + `constantScriptValue` + `}`,
-		prefix := "function " + fn + "(" + stripTypes(t.Parameters.Value) + "){"
-		body := strings.TrimLeftFunc(t.Value, unicode.IsSpace)
-		suffix := "}"
-		if _, err = g.w.WriteIndent(indentLevel, "Function: "+createGoString(prefix+body+suffix)+",\n"); err != nil {
+		if _, err = g.w.WriteIndent(indentLevel, "Function: "+createGoString(function)+",\n"); err != nil {
 			return err
 		}
-		// Call: templ.SafeScript(scriptName, a, b, c)
-		if _, err = g.w.WriteIndent(indentLevel, "Call: templ.SafeScript("+goFn+", "+stripTypes(t.Parameters.Value)+"),\n"); err != nil {
+		if _, err = g.w.WriteIndent(indentLevel, "Call: "+call+",\n"); err != nil {
 			return err
 		}
-		// CallInline: templ.SafeScriptInline(scriptName, a, b, c)
-		if _, err = g.w.WriteIndent(indentLevel, "CallInline: templ.SafeScriptInline("+goFn+", "+stripTypes(t.Parameters.Value)+"),\n"); err != nil {
+		if _, err = g.w.WriteIndent(indentLevel, "CallInline: "+callInline+",\n"); err != nil {
 			return err
 		}
 		indentLevel--
 	}
 	// }
-	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
-		return err
-	}
-	indentLevel--
-	// }
-	if r, err = g.w.WriteIndent(indentLevel, "}\n\n"); err != nil {
-		return err
-	}
-
-	// Keep track of the symbol range for the LSP.
-	tgtSymbolRange.To = r.To
-	g.sourceMap.AddSymbolRange(t.Range, tgtSymbolRange)
-
-	return nil
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
 }
 
 // writeBlankAssignmentForRuntimeImport writes out a blank identifier assignment.
@@ -1785,6 +2612,40 @@ func (g *generator) writeBlankAssignmentForRuntimeImport() error {
 	return nil
 }
 
+// writeLiteralConstants writes out the string literals collected by the RangeWriter
+// when the LiteralConstants or LiteralBytes option is set.
+func (g *generator) writeLiteralConstants() (err error) {
+	if len(g.w.Constants) == 0 {
+		return nil
+	}
+	decl, valueFormat := "const", "%s = \"%s\"\n"
+	if g.options.LiteralBytes {
+		decl, valueFormat = "var", "%s = []byte(\"%s\")\n"
+	}
+	if _, err = g.w.Write("\n\n" + decl + " (\n"); err != nil {
+		return err
+	}
+	for _, c := range g.w.Constants {
+		if _, err = g.w.WriteIndent(1, fmt.Sprintf(valueFormat, c.Name, c.Value)); err != nil {
+			return err
+		}
+	}
+	if _, err = g.w.Write(")"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// literalConstantPrefix returns a prefix for literal constant names that is unique to the
+// file, so that constants from multiple files in the same package don't collide.
+func literalConstantPrefix(fileName, filePath string) string {
+	if fileName == "" {
+		fileName = filepath.Base(filePath)
+	}
+	h := sha256.Sum256([]byte(fileName))
+	return "templ_7745c5c3_Literal_" + hex.EncodeToString(h[:])[0:8] + "_"
+}
+
 func functionName(name string, body string) string {
 	h := sha256.New()
 	h.Write([]byte(body))
@@ -1802,6 +2663,16 @@ func stripTypes(parameters string) string {
 	return strings.Join(variableNames, ", ")
 }
 
+// isURLAttribute returns true for the attributes that are sanitized on any element, if
+// URL sanitizers are enabled.
+func isURLAttribute(attrName string) bool {
+	switch strings.ToLower(attrName) {
+	case "href", "src", "action", "formaction":
+		return true
+	}
+	return false
+}
+
 func isExpressionAttributeValueURL(elementName, attrName string) bool {
 	switch elementName {
 	case "a", "link":
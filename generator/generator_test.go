@@ -2,6 +2,8 @@ package generator
 
 import (
 	"bytes"
+	"go/format"
+	"strings"
 	"testing"
 
 	"github.com/a-h/templ/parser/v2"
@@ -71,6 +73,55 @@ templ Hello(name string) {
 	}
 }
 
+func TestGeneratorSourceMapFoldedExpressions(t *testing.T) {
+	input := `package main
+
+templ Hello() {
+	<p title={ "a" + "b" }>{ "hello" }</p>
+}`
+	tests := []struct {
+		name     string
+		opts     []GenerateOpt
+		expected []string
+	}{
+		{
+			name:     "expressions are mapped to their text in the literal",
+			expected: []string{`ab\">hello</p>`, `hello</p>`},
+		},
+		{
+			name:     "expressions are mapped to the literal constant",
+			opts:     []GenerateOpt{WithLiteralConstants()},
+			expected: []string{"templ_7745c5c3_Literal_", "templ_7745c5c3_Literal_"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			w := new(bytes.Buffer)
+			op, err := Generate(tf, w, tt.opts...)
+			if err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			lines := strings.Split(w.String(), "\n")
+			for i, src := range []parser.Position{
+				{Line: 3, Col: 12},
+				{Line: 3, Col: 26},
+			} {
+				tgt, ok := op.SourceMap.TargetPositionFromSource(src.Line, src.Col)
+				if !ok {
+					t.Fatalf("expected the expression at %d:%d to be mapped", src.Line, src.Col)
+				}
+				if actual := lines[tgt.Line][tgt.Col:]; !strings.HasPrefix(actual, tt.expected[i]) {
+					t.Errorf("expected the expression at %d:%d to be mapped to %q, got %q", src.Line, src.Col, tt.expected[i], actual)
+				}
+			}
+		})
+	}
+}
+
 func TestIsExpressionAttributeValueURL(t *testing.T) {
 	testCases := []struct {
 		elementName    string
@@ -105,3 +156,158 @@ func TestIsExpressionAttributeValueURL(t *testing.T) {
 		}
 	}
 }
+
+func TestGeneratorLiteralConstants(t *testing.T) {
+	input := `package main
+
+templ Hello(name string) {
+	<div>Hello</div>
+	{ name }
+	<div>Hello</div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	w := new(bytes.Buffer)
+	op, err := Generate(tf, w, WithFileName("hello.templ"), WithLiteralConstants())
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	if len(op.Literals) != 2 {
+		t.Errorf("expected 2 literals, got %d: %#v", len(op.Literals), op.Literals)
+	}
+	constantName := literalConstantPrefix("hello.templ", "") + "1"
+	if !strings.Contains(w.String(), constantName+` = "<div>Hello</div>"`) {
+		t.Errorf("expected constant declaration for %q, got:\n%s", constantName, w.String())
+	}
+	if strings.Count(w.String(), `"<div>Hello</div>"`) != 1 {
+		t.Errorf("expected identical literals to share a constant, got:\n%s", w.String())
+	}
+	if strings.Count(w.String(), constantName+")") != 2 {
+		t.Errorf("expected the constant to be referenced twice, got:\n%s", w.String())
+	}
+}
+
+func TestGeneratorLiteralBytes(t *testing.T) {
+	input := `package main
+
+templ Hello(name string) {
+	<div>Hello</div>
+	{ name }
+	<div>Hello</div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	w := new(bytes.Buffer)
+	if _, err = Generate(tf, w, WithFileName("hello.templ"), WithLiteralBytes()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	name := literalConstantPrefix("hello.templ", "") + "1"
+	if !strings.Contains(w.String(), name+` = []byte("<div>Hello</div>")`) {
+		t.Errorf("expected byte slice declaration for %q, got:\n%s", name, w.String())
+	}
+	if strings.Count(w.String(), "templruntime.WriteBytes(templ_7745c5c3_Buffer, ") != 2 {
+		t.Errorf("expected literals to be written with WriteBytes, got:\n%s", w.String())
+	}
+}
+
+func TestGeneratorTextTransform(t *testing.T) {
+	input := `package main
+
+templ Hello(name string) {
+	<p title="Don't">Don't &amp; { name }</p>
+	<script>const s = "Don't";</script>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	w := new(bytes.Buffer)
+	op, err := Generate(tf, w, WithFileName("hello.templ"), WithTextTransform())
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if !op.Options.TextTransform {
+		t.Error("expected the TextTransform option to be set in the output")
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	if !strings.Contains(w.String(), `templ.EscapeString(templ.TransformText(ctx, "Don't & "))`) {
+		t.Errorf("expected text to be unescaped and transformed, got:\n%s", w.String())
+	}
+	if !strings.Contains(w.String(), `templ.EscapeString(templ.TransformText(ctx, templ_7745c5c3_Var`) {
+		t.Errorf("expected string expressions to be transformed, got:\n%s", w.String())
+	}
+	if strings.Count(w.String(), "templ.TransformText(") != 2 {
+		t.Errorf("expected attributes and script contents not to be transformed, got:\n%s", w.String())
+	}
+}
+
+func TestGeneratorURLSanitizer(t *testing.T) {
+	input := `package main
+
+templ Links(url string) {
+	<a href={ url }>Link</a>
+	<img src={ url }/>
+	<button formaction={ url }>Go</button>
+	<p title={ url }>Text</p>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	w := new(bytes.Buffer)
+	op, err := Generate(tf, w, WithURLSanitizer())
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if !op.Options.URLSanitizer {
+		t.Error("expected the URLSanitizer option to be set in the output")
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	if strings.Count(w.String(), "templ.JoinContextURLErrs(url)") != 3 || strings.Count(w.String(), ".Sanitize(ctx)") != 3 {
+		t.Errorf("expected the href, src and formaction attributes to be sanitized with the context sanitizer, got:\n%s", w.String())
+	}
+	if strings.Contains(w.String(), "templ.JoinURLErrs(") {
+		t.Errorf("expected templ.JoinURLErrs not to be used, got:\n%s", w.String())
+	}
+}
+
+func TestGeneratorRuntimeImportPath(t *testing.T) {
+	tf, err := parser.ParseString("package main\n\ntempl Hello() {\n\t<p>Hello</p>\n}\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err := Generate(tf, w, WithRuntimeImportPath("example.com/fork/templ/")); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	if !strings.Contains(w.String(), `import templ "example.com/fork/templ"`) {
+		t.Errorf("expected the templ package to be imported from the fork, got:\n%s", w.String())
+	}
+	if !strings.Contains(w.String(), `import templruntime "example.com/fork/templ/runtime"`) {
+		t.Errorf("expected the runtime package to be imported from the fork, got:\n%s", w.String())
+	}
+	if strings.Contains(w.String(), "github.com/a-h/templ") {
+		t.Errorf("expected no imports of github.com/a-h/templ, got:\n%s", w.String())
+	}
+}
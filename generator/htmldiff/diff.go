@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/a-h/htmlformat"
 	"github.com/a-h/templ"
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/html"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -86,3 +88,28 @@ func DiffCtx(ctx context.Context, input templ.Component, expected string) (forma
 
 	return actual.String(), cmp.Diff(expected, actual.String()), errors.Join(renderErr, closeErr, processingErr)
 }
+
+// NormalizeAttributeOrder sorts the attributes of each element in the HTML by name, so
+// that HTML can be compared without depending on the order that attributes were written in.
+func NormalizeAttributeOrder(s string) (normalized string, err error) {
+	var sb strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err = z.Err(); err == io.EOF {
+				return sb.String(), nil
+			}
+			return "", err
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			sb.Write(z.Raw())
+			continue
+		}
+		t := z.Token()
+		sort.SliceStable(t.Attr, func(i, j int) bool {
+			return t.Attr[i].Key < t.Attr[j].Key
+		})
+		sb.WriteString(t.String())
+	}
+}
@@ -0,0 +1,38 @@
+package htmldiff
+
+import "testing"
+
+func TestNormalizeAttributeOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "attributes are sorted by name",
+			input:    `<div id="a" class="b" data-x="c">text</div>`,
+			expected: `<div class="b" data-x="c" id="a">text</div>`,
+		},
+		{
+			name:     "boolean and self-closing elements are sorted",
+			input:    `<input type="checkbox" checked name="a"/>`,
+			expected: `<input checked="" name="a" type="checkbox"/>`,
+		},
+		{
+			name:     "text and comments are unchanged",
+			input:    `<!-- b a --><p>a &amp; b</p>`,
+			expected: `<!-- b a --><p>a &amp; b</p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NormalizeAttributeOrder(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// GeneratedSymbol is the Go code generated for a single template.
+type GeneratedSymbol struct {
+	// Name of the template, including any receiver and parameters.
+	Name string `json:"name"`
+	// Hash of the template's parse tree and directives.
+	Hash string `json:"hash"`
+	// Source range of the template in the templ file.
+	Source parser.Range `json:"source"`
+	// Target range of the generated code in the output.
+	Target parser.Range `json:"target"`
+	// Code generated for the template.
+	Code string `json:"code"`
+	// Reused is true if the code was copied from the previous output by incremental generation.
+	Reused bool `json:"reused"`
+}
+
+// WithPreviousOutput enables incremental generation. The code generated for each template in
+// the previous output is reused if the template is unchanged, and only the templates that have
+// changed are regenerated.
+//
+// Templates that have moved to a different line are regenerated, because the generated code
+// contains line numbers. If the generator options have changed, all templates are regenerated.
+func WithPreviousOutput(previous GeneratorOutput) GenerateOpt {
+	return func(g *generator) error {
+		g.previous = &previous
+		return nil
+	}
+}
+
+// startIncremental prepares the generator to reuse the code of templates from the previous
+// output. It must be called after all options have been applied.
+func (g *generator) startIncremental() {
+	if g.previous == nil || g.previous.SourceMap == nil {
+		return
+	}
+	// Literal constants and byte slices are numbered across the whole file, and line directives contain lines
+	// of the generated file, so can't be reused. The output of other targets isn't recorded in
+	// the options, and nor is the output of node writers.
+	if _, isHTML := g.target.(HTMLTarget); !isHTML || len(g.nodeWriters) > 0 {
+		return
+	}
+	if !reflect.DeepEqual(g.previous.Options, g.options) || g.options.LiteralConstants || g.options.LiteralBytes || g.options.LineDirectives != "" {
+		return
+	}
+	g.previousSymbols = make(map[string]GeneratedSymbol, len(g.previous.Symbols))
+	for _, s := range g.previous.Symbols {
+		g.previousSymbols[s.Name] = s
+	}
+	// Reused code refers to literals by index, so keep the previous literals and append new ones.
+	g.w.Literals = append([]string{}, g.previous.Literals...)
+	g.w.index = len(g.w.Literals)
+}
+
+// writeTemplateSymbol writes the template, reusing the previously generated code if the
+// template hasn't changed.
+func (g *generator) writeTemplateSymbol(nodeIdx int, t *parser.HTMLTemplate) (err error) {
+	symbol := GeneratedSymbol{
+		Name:   t.Expression.Value,
+		Hash:   g.templateHash(nodeIdx, t),
+		Source: t.Range,
+	}
+	symbol.Target.From = g.w.Current
+	previous, ok := g.previousSymbols[symbol.Name]
+	if ok && previous.Hash == symbol.Hash && previous.Target.From.Col == 0 && symbol.Target.From.Col == 0 {
+		if _, err = g.w.Write(previous.Code); err != nil {
+			return err
+		}
+		g.copySourceMap(previous, symbol)
+		symbol.Reused = true
+		symbol.Code = previous.Code
+	} else {
+		g.w.StartCapture()
+		err = g.writeTemplate(nodeIdx, t)
+		symbol.Code = g.w.StopCapture()
+		if err != nil {
+			return err
+		}
+	}
+	symbol.Target.To = g.w.Current
+	g.symbols = append(g.symbols, symbol)
+	return nil
+}
+
+// templateHash returns a hash of everything that the code generated for a template depends on.
+// Source indexes are excluded, because they aren't included in the generated code, so that
+// changes to a previous line of the file don't cause the template to be regenerated.
+func (g *generator) templateHash(nodeIdx int, t *parser.HTMLTemplate) string {
+	h := sha256.New()
+	writeHash(h, reflect.ValueOf(t))
+	for _, d := range nodeDirectives(g.tf.Nodes, nodeIdx) {
+		fmt.Fprintln(h, d.name, d.value)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+var positionType = reflect.TypeOf(parser.Position{})
+
+func writeHash(h hash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprint(h, "nil;")
+			return
+		}
+		fmt.Fprintf(h, "%s;", v.Elem().Type())
+		writeHash(h, v.Elem())
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type() == positionType && v.Type().Field(i).Name == "Index" {
+				continue
+			}
+			writeHash(h, v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(h, "%d[", v.Len())
+		for i := range v.Len() {
+			writeHash(h, v.Index(i))
+		}
+		fmt.Fprint(h, "]")
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		fmt.Fprintf(h, "%d{", len(keys))
+		for _, k := range keys {
+			writeHash(h, k)
+			writeHash(h, v.MapIndex(k))
+		}
+		fmt.Fprint(h, "}")
+	case reflect.String:
+		fmt.Fprintf(h, "%q;", v.String())
+	default:
+		fmt.Fprintf(h, "%v;", v)
+	}
+}
+
+// copySourceMap copies the source map entries of a reused template from the previous output,
+// adjusting them for the new positions of the template in the source and target.
+func (g *generator) copySourceMap(previous, current GeneratedSymbol) {
+	src, dst := g.previous.SourceMap, g.sourceMap
+	srcIndexDelta := current.Source.From.Index - previous.Source.From.Index
+	tgtLineDelta := int64(current.Target.From.Line) - int64(previous.Target.From.Line)
+	tgtIndexDelta := current.Target.From.Index - previous.Target.From.Index
+	shiftSource := func(p parser.Position) parser.Position {
+		p.Index += srcIndexDelta
+		return p
+	}
+	shiftTarget := func(p parser.Position) parser.Position {
+		p.Line = uint32(int64(p.Line) + tgtLineDelta)
+		p.Index += tgtIndexDelta
+		return p
+	}
+	inSource := func(line uint32) bool {
+		return line >= previous.Source.From.Line && line <= previous.Source.To.Line
+	}
+	inTarget := func(line uint32) bool {
+		return line >= previous.Target.From.Line && line < previous.Target.To.Line ||
+			line == previous.Target.To.Line && previous.Target.To.Col > 0
+	}
+	for line, cols := range src.SourceLinesToTarget {
+		if !inSource(line) {
+			continue
+		}
+		m := make(map[uint32]parser.Position, len(cols))
+		for col, p := range cols {
+			m[col] = shiftTarget(p)
+		}
+		dst.SourceLinesToTarget[line] = m
+	}
+	for line, cols := range src.TargetLinesToSource {
+		if !inTarget(line) {
+			continue
+		}
+		m := make(map[uint32]parser.Position, len(cols))
+		for col, p := range cols {
+			m[col] = shiftSource(p)
+		}
+		dst.TargetLinesToSource[shiftTarget(parser.Position{Line: line}).Line] = m
+	}
+	for line, cols := range src.SourceSymbolRangeToTarget {
+		if !inSource(line) {
+			continue
+		}
+		m := make(map[uint32]parser.Range, len(cols))
+		for col, r := range cols {
+			m[col] = parser.Range{From: shiftTarget(r.From), To: shiftTarget(r.To)}
+		}
+		dst.SourceSymbolRangeToTarget[line] = m
+	}
+	for line, cols := range src.TargetSymbolRangeToSource {
+		if !inTarget(line) {
+			continue
+		}
+		m := make(map[uint32]parser.Range, len(cols))
+		for col, r := range cols {
+			m[col] = parser.Range{From: shiftSource(r.From), To: shiftSource(r.To)}
+		}
+		dst.TargetSymbolRangeToSource[shiftTarget(parser.Position{Line: line}).Line] = m
+	}
+}
@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGenerateIncremental(t *testing.T) {
+	generate := func(t *testing.T, input string, opts ...GenerateOpt) (GeneratorOutput, string) {
+		t.Helper()
+		tf, err := parser.ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		w := new(bytes.Buffer)
+		op, err := Generate(tf, w, append([]GenerateOpt{WithFileName("incremental.templ")}, opts...)...)
+		if err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if _, err = format.Source(w.Bytes()); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		return op, w.String()
+	}
+	reused := func(op GeneratorOutput) (names []string) {
+		for _, s := range op.Symbols {
+			if s.Reused {
+				names = append(names, s.Name)
+			}
+		}
+		return names
+	}
+	v1 := `package main
+
+templ A() {
+	<div>A</div>
+}
+
+templ B() {
+	<div>B</div>
+}
+
+templ C(name string) {
+	<div>{ name }</div>
+}
+`
+	previous, _ := generate(t, v1)
+
+	t.Run("unchanged templates are reused", func(t *testing.T) {
+		v2 := strings.Replace(v1, "<div>B</div>", "<div>B updated</div>", 1)
+		op, output := generate(t, v2, WithPreviousOutput(previous))
+		if got := strings.Join(reused(op), ","); got != "A(),C(name string)" {
+			t.Errorf("expected A and C to be reused, got %q", got)
+		}
+		if !strings.Contains(output, "B updated") {
+			t.Errorf("expected updated template to be regenerated, got:\n%s", output)
+		}
+		// The source map of reused templates must point at the new location of the code.
+		tgt, ok := op.SourceMap.TargetPositionFromSource(11, 8)
+		if !ok {
+			t.Fatalf("expected source map entry for name expression")
+		}
+		if got := output[tgt.Index : tgt.Index+4]; got != "name" {
+			t.Errorf("expected source map to point at %q, got %q", "name", got)
+		}
+	})
+	t.Run("templates that move to a different line are regenerated", func(t *testing.T) {
+		v3 := strings.Replace(v1, "<div>A</div>", "<div>A</div>\n\t<div>A</div>", 1)
+		op, _ := generate(t, v3, WithPreviousOutput(previous))
+		if got := reused(op); len(got) != 0 {
+			t.Errorf("expected no templates to be reused, got %v", got)
+		}
+	})
+	t.Run("templates aren't reused with literal byte slices", func(t *testing.T) {
+		previous, _ := generate(t, v1, WithLiteralBytes())
+		v2 := strings.Replace(v1, "<div>A</div>", "<div>A updated</div>", 1)
+		op, output := generate(t, v2, WithPreviousOutput(previous), WithLiteralBytes())
+		if got := reused(op); len(got) != 0 {
+			t.Errorf("expected no templates to be reused, got %v", got)
+		}
+		if !strings.Contains(output, "A updated") || !strings.Contains(output, "<div>B</div>") {
+			t.Errorf("expected the literals of all templates to be written, got:\n%s", output)
+		}
+	})
+	t.Run("changed options regenerate all templates", func(t *testing.T) {
+		op, _ := generate(t, v1, WithPreviousOutput(previous), WithSkipCodeGeneratedComment())
+		if got := reused(op); len(got) != 0 {
+			t.Errorf("expected no templates to be reused, got %v", got)
+		}
+	})
+	t.Run("changed static data regenerates all templates", func(t *testing.T) {
+		input := "package main\n\ntempl Title() {\n\t<title>{ site.Title }</title>\n}\n"
+		previous, _ := generate(t, input, WithStaticData(map[string]any{"site.Title": "Old title"}))
+		op, output := generate(t, input, WithPreviousOutput(previous), WithStaticData(map[string]any{"site.Title": "New title"}))
+		if got := reused(op); len(got) != 0 {
+			t.Errorf("expected no templates to be reused, got %v", got)
+		}
+		if !strings.Contains(output, "New title") {
+			t.Errorf("expected the new static data to be used, got:\n%s", output)
+		}
+		if !HasGoChanged(previous, op) {
+			t.Error("expected the Go code to have changed")
+		}
+	})
+}
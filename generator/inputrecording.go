@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithInputRecording records the parameters of a component as it renders, if the render is
+// sampled by the recorder in the render context, see templ.WithInputRecorder.
+func WithInputRecording() GenerateOpt {
+	return func(g *generator) error {
+		g.options.InputRecording = true
+		return nil
+	}
+}
+
+func (g *generator) writeRecordInputs(indentLevel int, t *parser.HTMLTemplate) (err error) {
+	if !g.options.InputRecording {
+		return nil
+	}
+	name := componentName(t.Expression.Value)
+	if pkg := packageName(g.tf.Package.Expression.Value); pkg != "" {
+		name = pkg + "." + name
+	}
+	args := []string{"ctx", strconv.Quote(name), createGoString(g.options.FileName)}
+	for _, name := range templateParamNames(t.Expression.Value) {
+		args = append(args, strconv.Quote(name), name)
+	}
+	_, err = g.w.WriteIndent(indentLevel, "templ.RecordInputs("+strings.Join(args, ", ")+")\n")
+	return err
+}
@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorInputRecording(t *testing.T) {
+	input := `package pages
+
+templ Card(title string, _ int) {
+	<div>{ title }</div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err := Generate(tf, w, WithFileName("card.templ"), WithInputRecording()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	expected := "templ.RecordInputs(ctx, \"pages.Card\", `card.templ`, \"title\", title)"
+	if !strings.Contains(w.String(), expected) {
+		t.Errorf("expected inputs to be recorded, got:\n%s", w.String())
+	}
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strconv"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithLineDirectives writes //line directives before the Go expressions of the templ file,
+// so that the Go toolchain, e.g. go vet, the stack traces of panics, and debuggers, reports
+// their lines in the templ file instead of the generated file. The code that templ generates
+// around them is mapped back to goFileName, the name of the generated file, e.g.
+// "index_templ.go". The templ file name must be set with WithFileName.
+//
+// Formatting the generated code can add lines, so use UpdateLineDirectives after formatting.
+func WithLineDirectives(goFileName string) GenerateOpt {
+	return func(g *generator) error {
+		if goFileName == "" {
+			return errors.New("line directives require the name of the generated file")
+		}
+		g.options.LineDirectives = filepath.Base(goFileName)
+		return nil
+	}
+}
+
+// writeLineDirective maps the next line of the generated code to the line of the templ file
+// that r starts on, if line directives are enabled.
+func (g *generator) writeLineDirective(r parser.Range) error {
+	if g.options.LineDirectives == "" {
+		return nil
+	}
+	return g.w.WriteLineDirective(filepath.Base(g.options.FileName), int(r.From.Line)+1)
+}
+
+// UpdateLineDirectives updates the line numbers of the directives that map the code generated
+// by templ back to goFileName, e.g. after src has been formatted, see WithLineDirectives.
+func UpdateLineDirectives(src []byte, goFileName string) []byte {
+	prefix := []byte("//line " + filepath.Base(goFileName) + ":")
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	var out bytes.Buffer
+	out.Grow(len(src))
+	for i, line := range lines {
+		if bytes.HasPrefix(line, prefix) {
+			out.Write(prefix)
+			out.WriteString(strconv.Itoa(i+2) + ":1\n")
+			continue
+		}
+		out.Write(line)
+	}
+	return out.Bytes()
+}
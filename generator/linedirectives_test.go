@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorLineDirectives(t *testing.T) {
+	input := `package main
+
+func greeting(name string) string {
+	return "Hello, " + name
+}
+
+templ Greeting(name string, items []string) {
+	<div class={ classes(name) }>
+		{ greeting(name) }
+	</div>
+	for _, item := range items {
+		if item != "" {
+			{ item }
+		}
+	}
+	@card(name)
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	// positions returns the positions of the identifiers that the file declares or calls.
+	positions := func(t *testing.T, src []byte) map[string][]token.Position {
+		fset := token.NewFileSet()
+		f, err := goparser.ParseFile(fset, "greeting_templ.go", src, goparser.ParseComments)
+		if err != nil {
+			t.Fatalf("failed to parse generated code: %v\n%s", err, src)
+		}
+		positions := map[string][]token.Position{}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok {
+				positions[id.Name] = append(positions[id.Name], fset.Position(id.Pos()))
+			}
+			return true
+		})
+		return positions
+	}
+
+	t.Run("Go expressions are mapped to the templ file", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w, WithFileName("components/greeting.templ"), WithLineDirectives("greeting_templ.go")); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		formatted, err := format.Source(w.Bytes())
+		if err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		formatted = UpdateLineDirectives(formatted, "greeting_templ.go")
+		actual := positions(t, formatted)
+		expected := map[string]int{
+			"greeting": 3,
+			"Greeting": 7,
+			"classes":  8,
+			"item":     11,
+			"card":     16,
+		}
+		for name, line := range expected {
+			p := actual[name][0]
+			if p.Filename != "greeting.templ" || p.Line != line {
+				t.Errorf("expected %s to be at greeting.templ:%d, got %s", name, line, p)
+			}
+		}
+		// The call to greeting is within the template, on line 9.
+		if p := actual["greeting"][1]; p.Filename != "greeting.templ" || p.Line != 9 {
+			t.Errorf("expected the call to greeting to be at greeting.templ:9, got %s", p)
+		}
+		// The code generated by templ is mapped back to the generated file.
+		lines := bytes.Split(formatted, []byte("\n"))
+		for _, p := range actual["templ_7745c5c3_Buffer"] {
+			if p.Filename != "greeting_templ.go" {
+				continue
+			}
+			if !bytes.Contains(lines[p.Line-1], []byte("templ_7745c5c3_Buffer")) {
+				t.Errorf("expected line %d of the generated file to contain templ_7745c5c3_Buffer, got %q", p.Line, lines[p.Line-1])
+			}
+		}
+	})
+	t.Run("the templ file name is required", func(t *testing.T) {
+		if _, err := Generate(tf, new(bytes.Buffer), WithLineDirectives("greeting_templ.go")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+	t.Run("no directives are written by default", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w, WithFileName("greeting.templ")); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if bytes.Contains(w.Bytes(), []byte("//line ")) {
+			t.Errorf("unexpected line directive in the output:\n%s", w.String())
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"bytes"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// AppendLineMap appends a map of the lines of the formatted code to the lines of the templ file
+// that it was generated from. The map is registered with the runtime when the program starts,
+// so that stack traces can be rewritten without the templ file, see the runtime/stacktrace
+// package.
+//
+// unformatted is the code written by Generate, which the source map refers to, and formatted
+// is the code after it was formatted. The map is appended to the end of the code, so it
+// doesn't change the lines of the code before it, e.g. after UpdateLineDirectives.
+func AppendLineMap(formatted, unformatted []byte, sourceMap *parser.SourceMap, templFileName string) []byte {
+	unformattedLines := strings.Split(string(unformatted), "\n")
+	toUnformatted := matchLines(formatted, unformatted)
+	var sb strings.Builder
+	previous := 0
+	for line := range bytes.Count(formatted, []byte("\n")) + 1 {
+		templLine := 0
+		if u, ok := toUnformatted[line]; ok {
+			templLine = templLineOf(sourceMap, u, len(unformattedLines[u]))
+		}
+		// Each entry applies to the lines that follow it, up to the next entry.
+		if templLine == previous {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.Itoa(line + 1))
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(templLine))
+		previous = templLine
+	}
+	var out bytes.Buffer
+	out.Grow(len(formatted) + sb.Len() + 100)
+	out.Write(formatted)
+	out.WriteString("\nvar _ = templruntime.RegisterLineMap(")
+	out.WriteString(strconv.Quote(filepath.Base(templFileName)))
+	out.WriteString(", ")
+	out.WriteString(strconv.Quote(sb.String()))
+	out.WriteString(")\n")
+	return out.Bytes()
+}
+
+// templLineOf returns the line of the templ file, numbered from 1, that the zero based line of
+// the generated code was generated from, or 0 if it wasn't generated from the templ file.
+func templLineOf(sourceMap *parser.SourceMap, line, length int) int {
+	if pos, ok := sourceMap.SourcePositionFromTarget(uint32(line), uint32(length)); ok {
+		return int(pos.Line) + 1
+	}
+	// The line doesn't contain a Go expression from the template, so use the line of the
+	// template that contains it.
+	for srcLine, cols := range sourceMap.SourceSymbolRangeToTarget {
+		for _, tgt := range cols {
+			if uint32(line) >= tgt.From.Line && uint32(line) <= tgt.To.Line {
+				return int(srcLine) + 1
+			}
+		}
+	}
+	return 0
+}
+
+// matchLines maps the zero based lines of the formatted code to the lines of the unformatted
+// code. Both contain the same tokens, but formatting moves them between lines.
+func matchLines(formatted, unformatted []byte) map[int]int {
+	formattedTokens, unformattedTokens := tokenLines(formatted), tokenLines(unformatted)
+	m := make(map[int]int, len(formattedTokens))
+	for i := 0; i < len(formattedTokens) && i < len(unformattedTokens); i++ {
+		if _, ok := m[formattedTokens[i]]; !ok {
+			m[formattedTokens[i]] = unformattedTokens[i]
+		}
+	}
+	return m
+}
+
+// tokenLines returns the zero based line of each token in the Go source.
+func tokenLines(src []byte) (lines []int) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, 0)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return lines
+		}
+		// Skip the semicolons inserted at the end of lines, since formatting changes them.
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		lines = append(lines, fset.Position(pos).Line-1)
+	}
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestAppendLineMap(t *testing.T) {
+	input := `package main
+
+templ Greeting(name string) {
+	<div>
+		{ name }
+	</div>
+}
+`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var buf bytes.Buffer
+	op, err := Generate(tf, &buf)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		t.Fatalf("failed to format: %v", err)
+	}
+	code := AppendLineMap(formatted, buf.Bytes(), op.SourceMap, "components/greeting.templ")
+	if !bytes.HasPrefix(code, formatted) {
+		t.Fatal("expected the code before the line map not to change")
+	}
+	if _, err = format.Source(code); err != nil {
+		t.Fatalf("expected valid Go code: %v\n%s", err, code)
+	}
+	m := regexp.MustCompile(`var _ = templruntime\.RegisterLineMap\("greeting\.templ", "([^"]*)"\)\n$`).FindSubmatch(code)
+	if m == nil {
+		t.Fatalf("expected the line map to be registered:\n%s", code)
+	}
+	// lookup returns the templ line of the line of the generated code, like the runtime does.
+	lookup := func(line int) (templLine int) {
+		for _, entry := range strings.Split(string(m[1]), ",") {
+			goLine, tl, _ := strings.Cut(entry, ":")
+			if g, _ := strconv.Atoi(goLine); g > line {
+				break
+			}
+			templLine, _ = strconv.Atoi(tl)
+		}
+		return templLine
+	}
+	lines := strings.Split(string(formatted), "\n")
+	for i, line := range lines {
+		var expected int
+		switch {
+		case strings.Contains(line, "(name)"):
+			expected = 5
+		case strings.HasPrefix(line, "func Greeting("), strings.Contains(line, "GeneratedTemplate(func"):
+			expected = 3
+		case strings.HasPrefix(line, "import "):
+			expected = 0
+		default:
+			continue
+		}
+		if actual := lookup(i + 1); actual != expected {
+			t.Errorf("line %d %q: expected templ line %d, got %d", i+1, line, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithComponentMarkers writes HTML comments before and after the output of each component,
+// e.g. <!-- templ:Begin Card --> and <!-- templ:End Card -->, so that browser developer
+// tools and HTML diffing tools can attribute regions of the DOM to components.
+//
+// The markers are intended for development, and shouldn't be used in production.
+func WithComponentMarkers() GenerateOpt {
+	return func(g *generator) error {
+		g.options.ComponentMarkers = true
+		return nil
+	}
+}
+
+func (g *generator) writeComponentMarker(indentLevel int, marker string, t *parser.HTMLTemplate) (err error) {
+	if !g.options.ComponentMarkers {
+		return nil
+	}
+	_, err = g.w.WriteStringLiteral(indentLevel, "<!-- templ:"+marker+" "+componentName(t.Expression.Value)+" -->")
+	return err
+}
+
+// componentName returns the name of the template, e.g. "Card" for "Card(title string)",
+// or "Page.Render" for "(p Page) Render()".
+func componentName(expr string) string {
+	fn, ok := parseTemplateSignature(expr)
+	if !ok {
+		return "unknown"
+	}
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	return receiverTypeName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return "unknown"
+}
+
+// parseTemplateSignature parses the expression of a template, e.g. "Card(title string)", as
+// the declaration of a Go function.
+func parseTemplateSignature(expr string) (fn *ast.FuncDecl, ok bool) {
+	f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\nfunc "+expr+" {}", 0)
+	if err != nil || len(f.Decls) == 0 {
+		return nil, false
+	}
+	fn, ok = f.Decls[0].(*ast.FuncDecl)
+	return fn, ok
+}
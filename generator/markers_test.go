@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestComponentName(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: "Card(title string)", expected: "Card"},
+		{expr: "List[T any](items []T)", expected: "List"},
+		{expr: "(p Page) Render()", expected: "Page.Render"},
+		{expr: "(p *Page) Render()", expected: "Page.Render"},
+		{expr: "(p Page[T]) Render()", expected: "Page.Render"},
+		{expr: "(p Pair[K, V]) Render()", expected: "Pair.Render"},
+		{expr: "not valid", expected: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if actual := componentName(tt.expr); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGeneratorComponentMarkers(t *testing.T) {
+	input := `package main
+
+templ Card(title string) {
+	<div>{ title }</div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("markers are written when enabled", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w, WithComponentMarkers()); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if _, err = format.Source(w.Bytes()); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		if !strings.Contains(w.String(), `"<!-- templ:Begin Card --><div>"`) {
+			t.Errorf("expected begin marker, got:\n%s", w.String())
+		}
+		if !strings.Contains(w.String(), `"</div><!-- templ:End Card -->"`) {
+			t.Errorf("expected end marker, got:\n%s", w.String())
+		}
+	})
+	t.Run("markers are not written by default", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if strings.Contains(w.String(), "templ:Begin") {
+			t.Errorf("unexpected marker, got:\n%s", w.String())
+		}
+	})
+}
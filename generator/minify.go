@@ -0,0 +1,22 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithMinify removes HTML comments from the output. Whitespace is already collapsed by the
+// generator. Conditional comments, e.g. <!--[if IE]>, are kept because they change how
+// some browsers render the page.
+func WithMinify() GenerateOpt {
+	return func(g *generator) error {
+		g.options.Minify = true
+		return nil
+	}
+}
+
+func isConditionalComment(c *parser.HTMLComment) bool {
+	contents := strings.TrimSpace(c.Contents)
+	return strings.HasPrefix(contents, "[if") || strings.HasPrefix(contents, "<![endif]")
+}
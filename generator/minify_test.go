@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorMinify(t *testing.T) {
+	input := `package main
+
+templ Page() {
+	<!-- Navigation -->
+	<nav>Home</nav>
+	<!--[if IE]><p>Upgrade your browser</p><![endif]-->
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("comments are removed when enabled", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w, WithMinify()); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if _, err = format.Source(w.Bytes()); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		if strings.Contains(w.String(), "Navigation") {
+			t.Errorf("expected the comment to be removed, got:\n%s", w.String())
+		}
+		if !strings.Contains(w.String(), "[if IE]") {
+			t.Errorf("expected the conditional comment to be kept, got:\n%s", w.String())
+		}
+	})
+	t.Run("comments are kept by default", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if !strings.Contains(w.String(), "Navigation") {
+			t.Errorf("expected the comment to be kept, got:\n%s", w.String())
+		}
+	})
+}
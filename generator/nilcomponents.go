@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"strconv"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithStrictNilComponents makes @component return templ.ErrNilComponent if component is
+// nil, instead of rendering nothing. The error includes the expression and its position.
+func WithStrictNilComponents() GenerateOpt {
+	return func(g *generator) error {
+		g.options.StrictNilComponents = true
+		return nil
+	}
+}
+
+// writeComponentExpression writes the component expression of a templ element, wrapped so
+// that a nil component renders nothing, or returns an error in strict mode.
+func (g *generator) writeComponentExpression(e parser.Expression) (err error) {
+	// templruntime.NilSafe(
+	prefix, suffix := "templruntime.NilSafe(", ")"
+	if g.options.StrictNilComponents {
+		// templruntime.NilStrict(
+		prefix = "templruntime.NilStrict("
+		// , "header", "template.templ", 3, 2)
+		suffix = ", " + strconv.Quote(e.Value) + ", " + createGoString(g.options.FileName) + ", " + strconv.Itoa(int(e.Range.From.Line+1)) + ", " + strconv.Itoa(int(e.Range.From.Col)) + ")"
+	}
+	if _, err = g.w.Write(prefix); err != nil {
+		return err
+	}
+	var r parser.Range
+	if r, err = g.w.Write(e.Value); err != nil {
+		return err
+	}
+	g.sourceMap.Add(e, r)
+	_, err = g.w.Write(suffix)
+	return err
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorNilComponents(t *testing.T) {
+	input := `package main
+
+templ Card(header templ.Component) {
+	<div>
+		@header
+	</div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("nil components render nothing by default", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if _, err = format.Source(w.Bytes()); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		expected := `templ_7745c5c3_Err = templruntime.NilSafe(header).Render(ctx, templ_7745c5c3_Buffer)`
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected %q in the output:\n%s", expected, w.String())
+		}
+	})
+	t.Run("nil components return an error in strict mode", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w, WithFileName("card.templ"), WithStrictNilComponents()); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if _, err = format.Source(w.Bytes()); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		expected := "templ_7745c5c3_Err = templruntime.NilStrict(header, \"header\", `card.templ`, 5, 3).Render(ctx, templ_7745c5c3_Buffer)"
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected %q in the output:\n%s", expected, w.String())
+		}
+	})
+}
+
+func TestGeneratorSpreadComponents(t *testing.T) {
+	input := `package main
+
+templ List(items []templ.Component) {
+	<ul>
+		@items...
+	</ul>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []GenerateOpt
+		expected string
+	}{
+		{
+			name:     "nil components are skipped by default",
+			expected: `templ_7745c5c3_Err = templruntime.Spread(items).Render(ctx, templ_7745c5c3_Buffer)`,
+		},
+		{
+			name:     "nil components return an error in strict mode",
+			opts:     []GenerateOpt{WithFileName("list.templ"), WithStrictNilComponents()},
+			expected: "templ_7745c5c3_Err = templruntime.SpreadStrict(items, \"items\", `list.templ`, 5, 3).Render(ctx, templ_7745c5c3_Buffer)",
+		},
+		{
+			name:     "versions of templ without spreads render the component and text",
+			opts:     []GenerateOpt{WithTemplVersion("v0.3.924")},
+			expected: `templ_7745c5c3_Err = templruntime.NilSafe(items).Render(ctx, templ_7745c5c3_Buffer)`,
+		},
+		{
+			name:     "versions of templ with spreads render each component",
+			opts:     []GenerateOpt{WithTemplVersion(spreadComponentsVersion)},
+			expected: `templ_7745c5c3_Err = templruntime.Spread(items).Render(ctx, templ_7745c5c3_Buffer)`,
+		},
+		{
+			name:     "pseudo-versions of templ with spreads render each component",
+			opts:     []GenerateOpt{WithTemplVersion("v0.3.928-0.20251015120000-abcdef123456")},
+			expected: `templ_7745c5c3_Err = templruntime.Spread(items).Render(ctx, templ_7745c5c3_Buffer)`,
+		},
+		{
+			name:     "pseudo-versions of templ without spreads render the component and text",
+			opts:     []GenerateOpt{WithTemplVersion("v0.3.927-0.20250101120000-abcdef123456")},
+			expected: `templ_7745c5c3_Err = templruntime.NilSafe(items).Render(ctx, templ_7745c5c3_Buffer)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			if _, err := Generate(tf, w, tt.opts...); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			if _, err = format.Source(w.Bytes()); err != nil {
+				t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+			}
+			if !strings.Contains(w.String(), tt.expected) {
+				t.Errorf("expected %q in the output:\n%s", tt.expected, w.String())
+			}
+		})
+	}
+	t.Run("spreads that aren't supported by the version of templ are reported", func(t *testing.T) {
+		op, err := Generate(tf, new(bytes.Buffer), WithTemplVersion("v0.3.924"))
+		if err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if len(op.Diagnostics) != 1 || !strings.HasPrefix(op.Diagnostics[0].Message, `@items... renders @items followed by the text "..."`) {
+			t.Errorf("expected a diagnostic for the spread, got %v", op.Diagnostics)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// NodeWriter writes a node of a template in place of the generator, e.g. to rewrite <img>
+// elements as <picture> elements, or to add attributes to elements. To write the node as
+// usual, or to write other nodes in its place, call write. The writer isn't called for the
+// nodes that it writes, including their children.
+type NodeWriter func(n parser.Node, write func(n parser.Node) error) error
+
+// WithNodeWriter writes the nodes of templates that have the same type as node with fn, e.g.
+// WithNodeWriter(&parser.Element{}, fn) to extend how elements are written. Each node type
+// can have one writer.
+func WithNodeWriter(node parser.Node, fn NodeWriter) GenerateOpt {
+	return func(g *generator) error {
+		t := reflect.TypeOf(node)
+		if _, exists := g.nodeWriters[t]; exists {
+			return fmt.Errorf("node writer: %v already has a node writer", t)
+		}
+		if g.nodeWriters == nil {
+			g.nodeWriters = make(map[reflect.Type]NodeWriter)
+			g.activeNodeWriters = make(map[reflect.Type]bool)
+		}
+		g.nodeWriters[t] = fn
+		return nil
+	}
+}
+
+// nodeWriter returns the writer of the node, unless it's already writing a node.
+func (g *generator) nodeWriter(n parser.Node) (fn NodeWriter, ok bool) {
+	t := reflect.TypeOf(n)
+	if g.activeNodeWriters[t] {
+		return nil, false
+	}
+	fn, ok = g.nodeWriters[t]
+	return fn, ok
+}
+
+func (g *generator) writeNodeWithWriter(indentLevel int, n parser.Node, fn NodeWriter) (err error) {
+	t := reflect.TypeOf(n)
+	g.activeNodeWriters[t] = true
+	defer delete(g.activeNodeWriters, t)
+	return fn(n, func(n parser.Node) error {
+		return g.writeNode(indentLevel, n, nil)
+	})
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorNodeWriters(t *testing.T) {
+	input := `package main
+
+templ Page() {
+	<a href="/">Home</a>
+	<img src="/cat.jpg"/>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	pictures := func(n parser.Node, write func(n parser.Node) error) error {
+		img := n.(*parser.Element)
+		if img.Name != "img" {
+			return write(n)
+		}
+		return write(&parser.Element{
+			Name: "picture",
+			Children: []parser.Node{
+				&parser.Element{
+					Name: "source",
+					Attributes: []parser.Attribute{
+						&parser.ConstantAttribute{Key: parser.ConstantAttributeKey{Name: "srcset"}, Value: "/cat.webp"},
+					},
+				},
+				img,
+			},
+		})
+	}
+	tracking := func(n parser.Node, write func(n parser.Node) error) error {
+		a := n.(*parser.Element)
+		if a.Name != "a" {
+			return write(n)
+		}
+		tracked := *a
+		tracked.Attributes = append(parser.CopyAttributes(a.Attributes), &parser.ConstantAttribute{
+			Key:   parser.ConstantAttributeKey{Name: "data-track"},
+			Value: "link",
+		})
+		return write(&tracked)
+	}
+	tests := []struct {
+		name     string
+		opts     []GenerateOpt
+		expected string
+	}{
+		{
+			name:     "elements are written as usual without node writers",
+			expected: `<a href=\"/\">Home</a> <img src=\"/cat.jpg\">`,
+		},
+		{
+			name:     "node writers can replace elements",
+			opts:     []GenerateOpt{WithNodeWriter(&parser.Element{}, pictures)},
+			expected: `<a href=\"/\">Home</a> <picture><source srcset=\"/cat.webp\"><img src=\"/cat.jpg\"></picture>`,
+		},
+		{
+			name:     "node writers can add attributes",
+			opts:     []GenerateOpt{WithNodeWriter(&parser.Element{}, tracking)},
+			expected: `<a href=\"/\" data-track=\"link\">Home</a> <img src=\"/cat.jpg\">`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			if _, err := Generate(tf, w, tt.opts...); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			if !strings.Contains(w.String(), tt.expected) {
+				t.Errorf("expected %s in output, got:\n%s", tt.expected, w.String())
+			}
+		})
+	}
+	t.Run("errors from node writers are returned", func(t *testing.T) {
+		expected := errors.New("unsupported element")
+		_, err := Generate(tf, new(bytes.Buffer), WithNodeWriter(&parser.Element{}, func(n parser.Node, write func(n parser.Node) error) error {
+			return expected
+		}))
+		if !errors.Is(err, expected) {
+			t.Errorf("expected %v, got %v", expected, err)
+		}
+	})
+	t.Run("each node type can have one writer", func(t *testing.T) {
+		_, err := Generate(tf, new(bytes.Buffer), WithNodeWriter(&parser.Element{}, pictures), WithNodeWriter(&parser.Element{}, tracking))
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,313 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	goparser "go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithStaticData enables partial evaluation of the template for static site generation.
+// String expressions and attribute values that depend only on constants and the provided
+// build-time data are evaluated at generation time, and the result is written as a literal.
+//
+// Keys in data are Go identifiers or selectors, e.g. "Title" or "site.Title". Values must
+// be strings, booleans, or numbers. Expressions that can't be evaluated are generated as usual.
+// Keys aren't used in templates that declare a parameter or variable with the same name as
+// the first identifier of the key, because the name refers to the variable.
+func WithStaticData(data map[string]any) GenerateOpt {
+	return func(g *generator) error {
+		g.options.StaticData = data
+		g.staticData = make(map[string]constant.Value, len(data))
+		for k, v := range data {
+			cv, err := constantValueOf(v)
+			if err != nil {
+				return fmt.Errorf("static data %q: %w", k, err)
+			}
+			g.staticData[k] = cv
+		}
+		return nil
+	}
+}
+
+func constantValueOf(v any) (cv constant.Value, err error) {
+	switch v := v.(type) {
+	case string:
+		return constant.MakeString(v), nil
+	case bool:
+		return constant.MakeBool(v), nil
+	case int:
+		return constant.MakeInt64(int64(v)), nil
+	case int8:
+		return constant.MakeInt64(int64(v)), nil
+	case int16:
+		return constant.MakeInt64(int64(v)), nil
+	case int32:
+		return constant.MakeInt64(int64(v)), nil
+	case int64:
+		return constant.MakeInt64(v), nil
+	case uint:
+		return constant.MakeUint64(uint64(v)), nil
+	case uint8:
+		return constant.MakeUint64(uint64(v)), nil
+	case uint16:
+		return constant.MakeUint64(uint64(v)), nil
+	case uint32:
+		return constant.MakeUint64(uint64(v)), nil
+	case uint64:
+		return constant.MakeUint64(v), nil
+	case float32:
+		return constant.MakeFloat64(float64(v)), nil
+	case float64:
+		return constant.MakeFloat64(v), nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", v)
+}
+
+// evaluateStatic attempts to evaluate the Go expression using only constants and static data.
+// If the expression can't be evaluated, ok is false.
+func (g *generator) evaluateStatic(expression string) (s string, ok bool) {
+	expr, err := goparser.ParseExpr(strings.TrimSpace(expression))
+	if err != nil {
+		return "", false
+	}
+	if g.staticData == nil {
+		// Without static data, string literals are still evaluated, so that they're written
+		// with the surrounding literals, instead of being written separately at runtime.
+		return evaluateStringLiteral(expr)
+	}
+	v, ok := g.evaluateStaticExpr(expr)
+	if !ok {
+		return "", false
+	}
+	return constantString(v)
+}
+
+// evaluateStringLiteral evaluates string literals, and concatenations of them, e.g. " " or
+// "a" + `b`. Other expressions, including identifiers, which may not be constant, aren't
+// evaluated.
+func evaluateStringLiteral(expr ast.Expr) (s string, ok bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(expr.Value)
+		return s, err == nil
+	case *ast.ParenExpr:
+		return evaluateStringLiteral(expr.X)
+	case *ast.BinaryExpr:
+		if expr.Op != token.ADD {
+			return "", false
+		}
+		x, ok := evaluateStringLiteral(expr.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := evaluateStringLiteral(expr.Y)
+		if !ok {
+			return "", false
+		}
+		return x + y, true
+	}
+	return "", false
+}
+
+// evaluateStaticBool attempts to evaluate the Go expression to a boolean.
+func (g *generator) evaluateStaticBool(expression string) (value bool, ok bool) {
+	if g.staticData == nil {
+		return false, false
+	}
+	expr, err := goparser.ParseExpr(strings.TrimSpace(expression))
+	if err != nil {
+		return false, false
+	}
+	v, ok := g.evaluateStaticExpr(expr)
+	if !ok || v.Kind() != constant.Bool {
+		return false, false
+	}
+	return constant.BoolVal(v), true
+}
+
+func (g *generator) evaluateStaticExpr(expr ast.Expr) (v constant.Value, ok bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		v = constant.MakeFromLiteral(expr.Value, expr.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.ParenExpr:
+		return g.evaluateStaticExpr(expr.X)
+	case *ast.Ident, *ast.SelectorExpr:
+		name, ok := selectorName(expr)
+		if !ok {
+			return nil, false
+		}
+		if root, _, _ := strings.Cut(name, "."); g.staticBound[root] {
+			return nil, false
+		}
+		if name == "true" || name == "false" {
+			if _, shadowed := g.staticData[name]; !shadowed {
+				return constant.MakeBool(name == "true"), true
+			}
+		}
+		v, ok = g.staticData[name]
+		return v, ok
+	case *ast.UnaryExpr:
+		x, ok := g.evaluateStaticExpr(expr.X)
+		if !ok {
+			return nil, false
+		}
+		return safeConstantOp(func() constant.Value { return constant.UnaryOp(expr.Op, x, 0) })
+	case *ast.BinaryExpr:
+		x, ok := g.evaluateStaticExpr(expr.X)
+		if !ok {
+			return nil, false
+		}
+		y, ok := g.evaluateStaticExpr(expr.Y)
+		if !ok {
+			return nil, false
+		}
+		switch expr.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return safeConstantOp(func() constant.Value { return constant.MakeBool(constant.Compare(x, expr.Op, y)) })
+		case token.SHL, token.SHR:
+			s, ok := constant.Uint64Val(y)
+			if !ok {
+				return nil, false
+			}
+			return safeConstantOp(func() constant.Value { return constant.Shift(x, expr.Op, uint(s)) })
+		}
+		op := expr.Op
+		if op == token.QUO && x.Kind() == constant.Int && y.Kind() == constant.Int {
+			// Integer division.
+			op = token.QUO_ASSIGN
+		}
+		return safeConstantOp(func() constant.Value { return constant.BinaryOp(x, op, y) })
+	}
+	return nil, false
+}
+
+// safeConstantOp runs a go/constant operation, which panics on mismatched operand kinds.
+func safeConstantOp(f func() constant.Value) (v constant.Value, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, ok = nil, false
+		}
+	}()
+	v = f()
+	return v, v.Kind() != constant.Unknown
+}
+
+func selectorName(expr ast.Expr) (name string, ok bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name, true
+	case *ast.SelectorExpr:
+		prefix, ok := selectorName(expr.X)
+		if !ok {
+			return "", false
+		}
+		return prefix + "." + expr.Sel.Name, true
+	}
+	return "", false
+}
+
+// constantString formats the value in the same way as fmt.Sprint would at runtime.
+func constantString(v constant.Value) (s string, ok bool) {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v), true
+	case constant.Bool:
+		return fmt.Sprint(constant.BoolVal(v)), true
+	case constant.Int:
+		if i, exact := constant.Int64Val(v); exact {
+			return fmt.Sprint(i), true
+		}
+		if u, exact := constant.Uint64Val(v); exact {
+			return fmt.Sprint(u), true
+		}
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return fmt.Sprint(f), true
+	}
+	return "", false
+}
+
+// boundNames returns the names declared by the template, i.e. its receiver and parameters,
+// and the variables declared by its Go code, e.g. "item" in "for _, item := range items".
+// Names are returned whether or not they're in scope at a given expression, so that static
+// data is never used in place of a variable.
+func boundNames(t *parser.HTMLTemplate) map[string]bool {
+	names := map[string]bool{}
+	if fn, ok := parseTemplateSignature(t.Expression.Value); ok {
+		addBoundNames(fn, names)
+	}
+	var walk func(nodes []parser.Node)
+	walk = func(nodes []parser.Node) {
+		for _, n := range nodes {
+			switch n := n.(type) {
+			case *parser.ForExpression:
+				addStatementBoundNames("for "+n.Expression.Value+" {}", names)
+			case *parser.IfExpression:
+				addStatementBoundNames("if "+n.Expression.Value+" {}", names)
+				for _, elseIf := range n.ElseIfs {
+					addStatementBoundNames("if "+elseIf.Expression.Value+" {}", names)
+					walk(elseIf.Then)
+				}
+			case *parser.SwitchExpression:
+				addStatementBoundNames("switch "+n.Expression.Value+" {}", names)
+			case *parser.GoCode:
+				addStatementBoundNames(n.Expression.Value, names)
+			}
+			if c, ok := n.(parser.CompositeNode); ok {
+				walk(c.ChildNodes())
+			}
+		}
+	}
+	walk(t.Children)
+	return names
+}
+
+func addStatementBoundNames(stmts string, names map[string]bool) {
+	f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\nfunc _() {\n"+stmts+"\n}", 0)
+	if err != nil {
+		return
+	}
+	addBoundNames(f, names)
+}
+
+// addBoundNames adds the names declared in the node to names.
+func addBoundNames(node ast.Node, names map[string]bool) {
+	addIdents := func(exprs ...ast.Expr) {
+		for _, e := range exprs {
+			if id, ok := e.(*ast.Ident); ok {
+				names[id.Name] = true
+			}
+		}
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				addIdents(n.Lhs...)
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.DEFINE {
+				addIdents(n.Key, n.Value)
+			}
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				names[id.Name] = true
+			}
+		case *ast.Field:
+			for _, id := range n.Names {
+				names[id.Name] = true
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"bytes"
+	goparser "go/parser"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithStaticData(t *testing.T) {
+	input := `package main
+
+templ Page(name string) {
+	<h1 title={ site.Title + " - Home" } hidden?={ Draft }>{ site.Title }</h1>
+	<p>{ Count * 2 }</p>
+	<p>{ name }</p>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	w := new(bytes.Buffer)
+	op, err := Generate(tf, w, WithStaticData(map[string]any{
+		"site.Title": "Tom & Jerry",
+		"Draft":      false,
+		"Count":      21,
+	}))
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	literals := strings.Join(op.Literals, "")
+	expected := `<h1 title=\"Tom &amp; Jerry - Home\">Tom &amp; Jerry</h1><p>42</p><p>`
+	if !strings.HasPrefix(literals, expected) {
+		t.Errorf("expected literals to start with %q, got %q", expected, literals)
+	}
+	if strings.Contains(w.String(), "site.Title") {
+		t.Errorf("expected static expressions to be evaluated, got:\n%s", w.String())
+	}
+	if !strings.Contains(w.String(), "templ.JoinStringErrs(name)") {
+		t.Errorf("expected dynamic expression to be generated, got:\n%s", w.String())
+	}
+}
+
+func TestWithStaticDataDoesNotReplaceVariables(t *testing.T) {
+	input := `package main
+
+templ Page(Title string, site Site) {
+	<h1>{ Title }</h1>
+	<h2>{ site.Title }</h2>
+	for _, Count := range counts {
+		<p>{ Count }</p>
+	}
+	{{ Draft := true }}
+	<p hidden?={ Draft }>{ Footer }</p>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	w := new(bytes.Buffer)
+	_, err = Generate(tf, w, WithStaticData(map[string]any{
+		"Title":      "Static title",
+		"site.Title": "Static site",
+		"Count":      1,
+		"Draft":      false,
+		"Footer":     "Static footer",
+	}))
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	for _, expected := range []string{"templ.JoinStringErrs(Title)", "templ.JoinStringErrs(site.Title)", "templ.JoinStringErrs(Count)", "if Draft {", "Static footer"} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected %q in the output:\n%s", expected, w.String())
+		}
+	}
+	for _, unexpected := range []string{"Static title", "Static site"} {
+		if strings.Contains(w.String(), unexpected) {
+			t.Errorf("expected parameters not to be replaced by static data, got %q in the output:\n%s", unexpected, w.String())
+		}
+	}
+}
+
+func TestWithStaticDataUnsupportedType(t *testing.T) {
+	tf, err := parser.ParseString("package main\n")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	_, err = Generate(tf, new(bytes.Buffer), WithStaticData(map[string]any{
+		"Items": []string{"a"},
+	}))
+	if err == nil {
+		t.Error("expected error for unsupported static data type, got nil")
+	}
+}
+
+func TestStringLiteralsAreCoalesced(t *testing.T) {
+	input := `package main
+
+templ Page(name string) {
+	<p data-x={ "a" + ` + "`<b>`" + ` }>{ name }{ " " }{ ("&") }</p>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	w := new(bytes.Buffer)
+	op, err := Generate(tf, w)
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	expected := []string{`<p data-x=\"a&lt;b&gt;\">`, ` &amp;</p>`}
+	if diff := cmp.Diff(expected, op.Literals); diff != "" {
+		t.Error(diff)
+	}
+	if strings.Count(w.String(), "templ.JoinStringErrs(") != 1 {
+		t.Errorf("expected only the name to be written at runtime, got:\n%s", w.String())
+	}
+}
+
+func TestEvaluateStringLiteral(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+		ok       bool
+	}{
+		{expr: `" "`, expected: " ", ok: true},
+		{expr: "`raw`", expected: "raw", ok: true},
+		{expr: `("a" + "b") + "c"`, expected: "abc", ok: true},
+		{expr: `name`, ok: false},
+		{expr: `"a" + name`, ok: false},
+		{expr: `1`, ok: false},
+		{expr: `'a'`, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := goparser.ParseExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("failed to parse expression: %v", err)
+			}
+			actual, ok := evaluateStringLiteral(expr)
+			if ok != tt.ok || actual != tt.expected {
+				t.Errorf("expected %q, %v, got %q, %v", tt.expected, tt.ok, actual, ok)
+			}
+		})
+	}
+}
@@ -25,6 +25,114 @@ type RangeWriter struct {
 	index    int
 	builder  *strings.Builder
 	Literals []string
+
+	// Literal constants.
+	constantPrefix string
+	constantNames  map[string]string
+	constantBytes  bool
+	Constants      []LiteralConstant
+
+	// Expressions evaluated into the current literal, see WriteStringLiteralSource.
+	sourceMap      *parser.SourceMap
+	literalSources []literalSource
+
+	// capture records the output while it's set, see StartCapture.
+	capture *strings.Builder
+
+	// Line directives, see UseLineDirectives.
+	goFileName string
+	// inDirective is true from a line directive until something is written after it.
+	inDirective bool
+	// restoreDirective is true if the next line must be mapped back to the Go file.
+	restoreDirective bool
+}
+
+// UseLineDirectives configures the RangeWriter to map the lines that follow the Go code of
+// each line directive back to goFileName, see WriteLineDirective.
+func (rw *RangeWriter) UseLineDirectives(goFileName string) {
+	rw.goFileName = goFileName
+}
+
+// WriteLineDirective writes a //line directive that maps the next line to the line of
+// fileName. Once the next line has been written, the following line is mapped back to the
+// Go file.
+func (rw *RangeWriter) WriteLineDirective(fileName string, line int) (err error) {
+	if rw.inLiteral {
+		if _, err = rw.closeLiteral(0); err != nil {
+			return err
+		}
+	}
+	rw.restoreDirective = false
+	// Line directives must start at the beginning of a line.
+	if rw.Current.Col != 0 {
+		if _, err = rw.write("\n"); err != nil {
+			return err
+		}
+	}
+	if _, err = rw.write("//line " + fileName + ":" + strconv.Itoa(line) + "\n"); err != nil {
+		return err
+	}
+	rw.inDirective = true
+	return nil
+}
+
+// StartCapture starts recording everything written, until StopCapture is called.
+func (rw *RangeWriter) StartCapture() {
+	rw.capture = &strings.Builder{}
+}
+
+// StopCapture stops recording, and returns everything written since StartCapture.
+func (rw *RangeWriter) StopCapture() (s string) {
+	if rw.capture == nil {
+		return ""
+	}
+	s = rw.capture.String()
+	rw.capture = nil
+	return s
+}
+
+// UseSourceMap configures the RangeWriter to add the expressions written with
+// WriteStringLiteralSource to sm, once the literal that contains them has been written.
+func (rw *RangeWriter) UseSourceMap(sm *parser.SourceMap) {
+	rw.sourceMap = sm
+}
+
+// literalSource is an expression that was evaluated into a literal, at offset bytes from the
+// start of the literal.
+type literalSource struct {
+	expression parser.Expression
+	offset     int
+}
+
+// LiteralConstant is a string literal that has been extracted to a package-level constant.
+type LiteralConstant struct {
+	Name  string
+	Value string
+}
+
+// UseLiteralConstants configures the RangeWriter to reference string literals by the name of
+// a package-level constant instead of writing them inline. Identical literals share a constant.
+func (rw *RangeWriter) UseLiteralConstants(prefix string) {
+	rw.constantPrefix = prefix
+	rw.constantNames = map[string]string{}
+}
+
+// UseLiteralBytes configures the RangeWriter to reference string literals by the name of a
+// package-level byte slice, which is written with templruntime.WriteBytes. Identical literals
+// share a variable.
+func (rw *RangeWriter) UseLiteralBytes(prefix string) {
+	rw.UseLiteralConstants(prefix)
+	rw.constantBytes = true
+}
+
+func (rw *RangeWriter) literalConstantName(literal string) string {
+	if name, ok := rw.constantNames[literal]; ok {
+		return name
+	}
+	name := rw.constantPrefix + strconv.Itoa(len(rw.Constants)+1)
+	rw.constantNames[literal] = name
+	rw.Constants = append(rw.Constants, LiteralConstant{Name: name, Value: literal})
+	return name
 }
 
 func (rw *RangeWriter) closeLiteral(indent int) (r parser.Range, err error) {
@@ -33,17 +141,33 @@ func (rw *RangeWriter) closeLiteral(indent int) (r parser.Range, err error) {
 
 	var sb strings.Builder
 	sb.WriteString(strings.Repeat("\t", indent))
-	sb.WriteString(`templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, `)
+	if rw.constantBytes {
+		sb.WriteString(`templ_7745c5c3_Err = templruntime.WriteBytes(templ_7745c5c3_Buffer, `)
+	} else {
+		sb.WriteString(`templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, `)
+	}
 	sb.WriteString(strconv.Itoa(rw.index))
-	sb.WriteString(`, "`)
 	literal := rw.builder.String()
 	rw.Literals = append(rw.Literals, literal)
-	sb.WriteString(literal)
 	rw.builder.Reset()
-	sb.WriteString(`")`)
-	sb.WriteString("\n")
+	var arg, end string
+	if rw.constantNames != nil {
+		sb.WriteString(`, `)
+		arg, end = rw.literalConstantName(literal), ")\n"
+	} else {
+		sb.WriteString(`, "`)
+		arg, end = literal, "\")\n"
+	}
 
-	if _, err := rw.write(sb.String()); err != nil {
+	if _, err = rw.write(sb.String()); err != nil {
+		return r, err
+	}
+	argRange, err := rw.write(arg)
+	if err != nil {
+		return r, err
+	}
+	rw.addLiteralSources(argRange, rw.constantNames != nil)
+	if _, err = rw.write(end); err != nil {
 		return r, err
 	}
 
@@ -70,6 +194,34 @@ func (rw *RangeWriter) WriteStringLiteral(level int, s string) (r parser.Range,
 	return
 }
 
+// WriteStringLiteralSource writes s, which was evaluated from src, as part of a string
+// literal, see WriteStringLiteral. If a source map is in use, src is mapped to the position
+// of s in the literal, or to the name of the literal constant.
+func (rw *RangeWriter) WriteStringLiteralSource(level int, s string, src parser.Expression) (r parser.Range, err error) {
+	if rw.sourceMap != nil {
+		rw.literalSources = append(rw.literalSources, literalSource{expression: src, offset: rw.builder.Len()})
+	}
+	return rw.WriteStringLiteral(level, s)
+}
+
+// addLiteralSources adds the expressions evaluated into the literal written to r to the
+// source map.
+func (rw *RangeWriter) addLiteralSources(r parser.Range, constant bool) {
+	for _, ls := range rw.literalSources {
+		tgt := r
+		if !constant {
+			tgt.From.Index += int64(ls.offset)
+			tgt.From.Col += uint32(ls.offset)
+		}
+		// The literal is written on a single line, so only the first line of the expression
+		// can be mapped to it.
+		src := ls.expression
+		src.Value, _, _ = strings.Cut(src.Value, "\n")
+		rw.sourceMap.Add(src, tgt)
+	}
+	rw.literalSources = rw.literalSources[:0]
+}
+
 func (rw *RangeWriter) Write(s string) (r parser.Range, err error) {
 	if rw.inLiteral {
 		if _, err = rw.closeLiteral(0); err != nil {
@@ -80,11 +232,25 @@ func (rw *RangeWriter) Write(s string) (r parser.Range, err error) {
 }
 
 func (rw *RangeWriter) write(s string) (r parser.Range, err error) {
+	if rw.restoreDirective && rw.Current.Col == 0 && s != "" {
+		rw.restoreDirective = false
+		// The directive is on the current line, so the line after it is the one after that.
+		if _, err = rw.write("//line " + rw.goFileName + ":" + strconv.Itoa(int(rw.Current.Line)+2) + ":1\n"); err != nil {
+			return r, err
+		}
+	}
+	if rw.inDirective && s != "" {
+		rw.inDirective = false
+		rw.restoreDirective = true
+	}
 	r.From = parser.Position{
 		Index: rw.Current.Index,
 		Line:  rw.Current.Line,
 		Col:   rw.Current.Col,
 	}
+	if rw.capture != nil {
+		rw.capture.WriteString(s)
+	}
 	utf8Bytes := make([]byte, 4)
 	for _, c := range s {
 		rlen := utf8.EncodeRune(utf8Bytes, c)
@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithTemplateRegistry registers the exported templates of the file, so that they can be
+// looked up and created by name with templ.LookupTemplate, e.g. to render components
+// selected in a CMS. Methods and generic templates aren't registered.
+func WithTemplateRegistry() GenerateOpt {
+	return func(g *generator) error {
+		g.options.TemplateRegistry = true
+		return nil
+	}
+}
+
+// registeredTemplate is a template that's added to the registry.
+type registeredTemplate struct {
+	name     string
+	params   []registeredParam
+	variadic bool
+}
+
+type registeredParam struct {
+	name string
+	typ  string
+}
+
+func (g *generator) writeTemplateRegistry() (err error) {
+	if !g.options.TemplateRegistry {
+		return nil
+	}
+	var templates []registeredTemplate
+	for _, n := range g.tf.Nodes {
+		t, ok := n.(*parser.HTMLTemplate)
+		if !ok {
+			continue
+		}
+		if rt, ok := newRegisteredTemplate(t.Expression.Value); ok {
+			templates = append(templates, rt)
+		}
+	}
+	if len(templates) == 0 {
+		return nil
+	}
+	if _, err = g.w.Write("\n\nfunc init() {\n"); err != nil {
+		return err
+	}
+	for _, t := range templates {
+		for _, line := range t.lines() {
+			if _, err = g.w.WriteIndent(1, line); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = g.w.Write("}")
+	return err
+}
+
+// newRegisteredTemplate returns the template to register for the template expression, if
+// it's an exported function that isn't generic.
+func newRegisteredTemplate(expr string) (t registeredTemplate, ok bool) {
+	fn, ok := parseTemplateSignature(expr)
+	if !ok || fn.Recv != nil || fn.Type.TypeParams != nil || !fn.Name.IsExported() {
+		return t, false
+	}
+	t.name = fn.Name.Name
+	for _, field := range fn.Type.Params.List {
+		typ := field.Type
+		if ellipsis, isVariadic := typ.(*ast.Ellipsis); isVariadic {
+			typ = &ast.ArrayType{Elt: ellipsis.Elt}
+			t.variadic = true
+		}
+		p := registeredParam{typ: types.ExprString(typ)}
+		if len(field.Names) == 0 {
+			t.params = append(t.params, p)
+			continue
+		}
+		for _, name := range field.Names {
+			p.name = name.Name
+			t.params = append(t.params, p)
+		}
+	}
+	return t, true
+}
+
+func (t registeredTemplate) lines() []string {
+	name := strconv.Quote(t.name)
+	params := make([]string, len(t.params))
+	args := make([]string, len(t.params))
+	for i, p := range t.params {
+		params[i] = fmt.Sprintf("{Name: %s, Type: %s}", strconv.Quote(p.name), strconv.Quote(p.typ))
+		args[i] = fmt.Sprintf("templ_7745c5c3_Arg%d", i)
+	}
+	call := t.name + "(" + strings.Join(args, ", ")
+	if t.variadic {
+		call += "..."
+	}
+	call += ")"
+	lines := []string{
+		"templruntime.RegisterTemplate(templ.TemplateInfo{\n",
+		"\tName:   " + name + ",\n",
+		"\tParams: []templ.TemplateParam{" + strings.Join(params, ", ") + "},\n",
+		"\tNew: func(templ_7745c5c3_Args ...any) (templ.Component, error) {\n",
+		fmt.Sprintf("\t\tif templ_7745c5c3_Err := templruntime.CheckArgCount(%s, templ_7745c5c3_Args, %d); templ_7745c5c3_Err != nil {\n", name, len(t.params)),
+		"\t\t\treturn nil, templ_7745c5c3_Err\n",
+		"\t\t}\n",
+	}
+	for i, p := range t.params {
+		lines = append(lines,
+			fmt.Sprintf("\t\t%s, templ_7745c5c3_Err := templruntime.Arg[%s](%s, %s, templ_7745c5c3_Args[%d])\n", args[i], p.typ, name, strconv.Quote(p.name), i),
+			"\t\tif templ_7745c5c3_Err != nil {\n",
+			"\t\t\treturn nil, templ_7745c5c3_Err\n",
+			"\t\t}\n",
+		)
+	}
+	return append(lines,
+		"\t\treturn "+call+", nil\n",
+		"\t},\n",
+		"})\n",
+	)
+}
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewRegisteredTemplate(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected registeredTemplate
+		ok       bool
+	}{
+		{expr: "Card()", expected: registeredTemplate{name: "Card"}, ok: true},
+		{
+			expr: "Card(title string, items []models.Item)",
+			expected: registeredTemplate{name: "Card", params: []registeredParam{
+				{name: "title", typ: "string"},
+				{name: "items", typ: "[]models.Item"},
+			}},
+			ok: true,
+		},
+		{
+			expr: "Tags(a, b string, tags ...string)",
+			expected: registeredTemplate{name: "Tags", variadic: true, params: []registeredParam{
+				{name: "a", typ: "string"},
+				{name: "b", typ: "string"},
+				{name: "tags", typ: "[]string"},
+			}},
+			ok: true,
+		},
+		{expr: "card()", ok: false},
+		{expr: "List[T any](items []T)", ok: false},
+		{expr: "(p Page) Render()", ok: false},
+		{expr: "not valid", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			actual, ok := newRegisteredTemplate(tt.expr)
+			if ok != tt.ok {
+				t.Fatalf("expected ok to be %v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tt.expected, actual, cmp.AllowUnexported(registeredTemplate{}, registeredParam{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGeneratorTemplateRegistry(t *testing.T) {
+	input := `package main
+
+templ Card(title string, tags ...string) {
+	<div>{ title }</div>
+}
+
+templ hidden() {
+	<div></div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err := Generate(tf, w, WithTemplateRegistry()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	for _, expected := range []string{
+		`Name:   "Card",`,
+		`templruntime.Arg[[]string]("Card", "tags", templ_7745c5c3_Args[1])`,
+		`return Card(templ_7745c5c3_Arg0, templ_7745c5c3_Arg1...), nil`,
+	} {
+		if !strings.Contains(w.String(), expected) {
+			t.Errorf("expected %q in generated code, got:\n%s", expected, w.String())
+		}
+	}
+	if strings.Contains(w.String(), `"hidden"`) {
+		t.Errorf("expected unexported templates not to be registered, got:\n%s", w.String())
+	}
+}
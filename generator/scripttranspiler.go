@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScriptTranspiler compiles the TypeScript of a script template to JavaScript. The source is a
+// function declaration, e.g. function __templ_greet_1a2b(name: any){...}, and the result must
+// declare a function with the same name.
+type ScriptTranspiler func(ts string) (js string, err error)
+
+// WithScriptTranspiler sets the transpiler used for script templates that are preceded by a
+// //templ:ts directive, so that they can be written in TypeScript and type checked.
+func WithScriptTranspiler(fn ScriptTranspiler) GenerateOpt {
+	return func(g *generator) error {
+		g.scriptTranspiler = fn
+		return nil
+	}
+}
+
+// scriptFunction returns the JavaScript function declaration of a script template, transpiling
+// the body from TypeScript if the template has a //templ:ts directive.
+func (g *generator) scriptFunction(name, fn, params, body string, directives map[string]string) (string, error) {
+	if _, ok := directives["ts"]; !ok {
+		return "function " + fn + "(" + params + "){" + body + "}", nil
+	}
+	if g.scriptTranspiler == nil {
+		return "", fmt.Errorf("script %s: the //templ:ts directive requires a script transpiler", name)
+	}
+	// Annotate the parameters, since their types are only known in Go.
+	var tsParams []string
+	if strings.TrimSpace(params) != "" {
+		for _, p := range strings.Split(params, ",") {
+			tsParams = append(tsParams, strings.TrimSpace(p)+": any")
+		}
+	}
+	js, err := g.scriptTranspiler("function " + fn + "(" + strings.Join(tsParams, ", ") + "){" + body + "}")
+	if err != nil {
+		return "", fmt.Errorf("script %s: failed to transpile TypeScript: %w", name, err)
+	}
+	js = strings.TrimSpace(js)
+	if !strings.Contains(js, "function "+fn+"(") {
+		return "", fmt.Errorf("script %s: the transpiled JavaScript doesn't declare the %s function", name, fn)
+	}
+	return js, nil
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorScriptTranspiler(t *testing.T) {
+	input := `package main
+
+//templ:ts
+script greet(name string) {
+	const message: string = "Hello, " + name;
+	alert(message);
+}
+
+script plain(name string) {
+	alert(name);
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	// stripTypes is a transpiler that only understands the annotations used in the test.
+	stripTypes := func(ts string) (string, error) {
+		if strings.Contains(ts, "alert(name)") {
+			t.Errorf("expected only scripts with the //templ:ts directive to be transpiled, got %q", ts)
+		}
+		return strings.NewReplacer(": any", "", ": string", "").Replace(ts) + "\n", nil
+	}
+
+	t.Run("scripts with the directive are transpiled", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w, WithScriptTranspiler(stripTypes)); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		actual := w.String()
+		if strings.Contains(actual, ": string") {
+			t.Errorf("expected the TypeScript to be transpiled, got:\n%s", actual)
+		}
+		if !strings.Contains(actual, "(name){const message = \"Hello, \" + name;") {
+			t.Errorf("expected the transpiled JavaScript in the output, got:\n%s", actual)
+		}
+	})
+	t.Run("scripts with the directive require a transpiler", func(t *testing.T) {
+		_, err := Generate(tf, new(bytes.Buffer))
+		if err == nil || err.Error() != "script greet: the //templ:ts directive requires a script transpiler" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("transpiler errors are returned", func(t *testing.T) {
+		errTranspile := errors.New("type error")
+		_, err := Generate(tf, new(bytes.Buffer), WithScriptTranspiler(func(ts string) (string, error) {
+			return "", errTranspile
+		}))
+		if !errors.Is(err, errTranspile) {
+			t.Errorf("expected the transpiler error, got %v", err)
+		}
+	})
+	t.Run("the transpiled JavaScript must declare the function", func(t *testing.T) {
+		_, err := Generate(tf, new(bytes.Buffer), WithScriptTranspiler(func(ts string) (string, error) {
+			return "(() => {})()", nil
+		}))
+		if err == nil || !strings.Contains(err.Error(), "doesn't declare the __templ_greet_") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithErrorSnapshots attaches a snapshot of the parameters of a component to the error
+// returned when it fails to render, see templ.SnapshotError. The parameters are serialized
+// to JSON only if rendering fails.
+func WithErrorSnapshots() GenerateOpt {
+	return func(g *generator) error {
+		g.options.ErrorSnapshots = true
+		return nil
+	}
+}
+
+func (g *generator) writeErrorSnapshot(indentLevel int, t *parser.HTMLTemplate) (err error) {
+	if !g.options.ErrorSnapshots {
+		return nil
+	}
+	args := []string{"templ_7745c5c3_Err", strconv.Quote(componentName(t.Expression.Value)), createGoString(g.options.FileName)}
+	for _, name := range templateParamNames(t.Expression.Value) {
+		args = append(args, strconv.Quote(name), name)
+	}
+	lines := []string{
+		"defer func() {\n",
+		"\tif templ_7745c5c3_Err != nil {\n",
+		"\t\ttempl_7745c5c3_Err = templ.WithErrorSnapshot(" + strings.Join(args, ", ") + ")\n",
+		"\t}\n",
+		"}()\n",
+	}
+	for _, line := range lines {
+		if _, err = g.w.WriteIndent(indentLevel, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateParamNames returns the names of the receiver and parameters of a template.
+func templateParamNames(expr string) (names []string) {
+	fn, ok := parseTemplateSignature(expr)
+	if !ok {
+		return nil
+	}
+	if fn.Recv != nil {
+		for _, field := range fn.Recv.List {
+			for _, name := range field.Names {
+				if name.Name != "_" {
+					names = append(names, name.Name)
+				}
+			}
+		}
+	}
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				names = append(names, name.Name)
+			}
+		}
+	}
+	return names
+}
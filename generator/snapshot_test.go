@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTemplateParamNames(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{expr: "Card()", expected: nil},
+		{expr: "Card(title string, count int)", expected: []string{"title", "count"}},
+		{expr: "Card(a, b string, _ int)", expected: []string{"a", "b"}},
+		{expr: "List[T any](items ...T)", expected: []string{"items"}},
+		{expr: "(p Page) Render(title string)", expected: []string{"p", "title"}},
+		{expr: "not valid", expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, templateParamNames(tt.expr)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestGeneratorErrorSnapshots(t *testing.T) {
+	input := `package main
+
+templ Card(title string, _ int) {
+	<div>{ title }</div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err := Generate(tf, w, WithFileName("card.templ"), WithErrorSnapshots()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	expected := "templ.WithErrorSnapshot(templ_7745c5c3_Err, \"Card\", `card.templ`, \"title\", title)"
+	if !strings.Contains(w.String(), expected) {
+		t.Errorf("expected snapshot to be captured, got:\n%s", w.String())
+	}
+}
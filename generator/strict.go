@@ -0,0 +1,252 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithStrict checks element and attribute names against the HTML, SVG, MathML and ARIA
+// vocabularies, and adds a diagnostic to the output for each unknown name, e.g. to catch
+// typos such as <tabel> at build time.
+//
+// Names in allow are also accepted. A name ending with * accepts any name with that prefix,
+// e.g. hx-* for htmx attributes. Custom elements, data-* attributes, and attributes with
+// expression keys are always accepted.
+//
+// Constant href, src, action and formaction attributes that use an unsafe URL scheme, e.g.
+// javascript:, are returned as errors, see parser.DiagnoseUnsafeURLs. Entries of allow that
+// end with a colon are URL schemes that are also accepted, e.g. sms: for projects that allow
+// it with templ.AllowURLSchemes.
+func WithStrict(allow ...string) GenerateOpt {
+	return func(g *generator) error {
+		g.options.Strict = true
+		g.options.StrictAllow = append(g.options.StrictAllow, allow...)
+		return nil
+	}
+}
+
+// WithStrictErrors is like WithStrict, but returns an error if any unknown names are found.
+func WithStrictErrors(allow ...string) GenerateOpt {
+	return func(g *generator) error {
+		g.options.StrictErrors = true
+		return WithStrict(allow...)(g)
+	}
+}
+
+// checkVocabulary returns a diagnostic for each element or attribute name in the template
+// file that isn't in the vocabulary or the allow list.
+func (g *generator) checkVocabulary() (diags []parser.Diagnostic, err error) {
+	if !g.options.Strict {
+		return nil, nil
+	}
+	v := newVocabulary(g.options.StrictAllow)
+	for _, n := range g.tf.Nodes {
+		if t, ok := n.(*parser.HTMLTemplate); ok {
+			v.checkNodes(t.Children, &diags)
+		}
+	}
+	if !g.options.StrictErrors {
+		return diags, nil
+	}
+	var errs []error
+	for _, d := range diags {
+		errs = append(errs, fmt.Errorf("%d:%d: %s", d.Range.From.Line+1, d.Range.From.Col+1, d.Message))
+	}
+	return diags, errors.Join(errs...)
+}
+
+// checkURLs returns an error for each constant URL attribute that uses an unsafe scheme.
+func (g *generator) checkURLs() error {
+	if !g.options.Strict {
+		return nil
+	}
+	var errs []error
+	for _, d := range parser.DiagnoseUnsafeURLs(g.tf, AllowedURLSchemes(g.options.StrictAllow)...) {
+		errs = append(errs, fmt.Errorf("%d:%d: %s", d.Range.From.Line+1, d.Range.From.Col+1, d.Message))
+	}
+	return errors.Join(errs...)
+}
+
+// AllowedURLSchemes returns the entries of a strict allow list that are URL schemes, i.e. that
+// end with a colon.
+func AllowedURLSchemes(allow []string) (schemes []string) {
+	for _, name := range allow {
+		if name = strings.TrimSpace(name); strings.HasSuffix(name, ":") {
+			schemes = append(schemes, name)
+		}
+	}
+	return schemes
+}
+
+type vocabulary struct {
+	allow    map[string]struct{}
+	prefixes []string
+}
+
+func newVocabulary(allow []string) (v vocabulary) {
+	v.allow = map[string]struct{}{}
+	for _, name := range allow {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if prefix, ok := strings.CutSuffix(name, "*"); ok {
+			v.prefixes = append(v.prefixes, prefix)
+			continue
+		}
+		v.allow[name] = struct{}{}
+	}
+	return v
+}
+
+func (v vocabulary) isAllowed(name string) bool {
+	if _, ok := v.allow[name]; ok {
+		return true
+	}
+	for _, prefix := range v.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v vocabulary) checkNodes(nodes []parser.Node, diags *[]parser.Diagnostic) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *parser.Element:
+			v.checkElement(n, diags)
+			v.checkAttributes(n.Name, n.Attributes, diags)
+		case *parser.ScriptElement:
+			v.checkAttributes("script", n.Attributes, diags)
+		case *parser.RawElement:
+			v.checkAttributes(n.Name, n.Attributes, diags)
+		}
+		if c, ok := n.(parser.CompositeNode); ok {
+			v.checkNodes(c.ChildNodes(), diags)
+		}
+	}
+}
+
+func (v vocabulary) checkElement(e *parser.Element, diags *[]parser.Diagnostic) {
+	name := strings.ToLower(e.Name)
+	// Names that contain a hyphen are custom elements.
+	if strings.Contains(name, "-") || v.isAllowed(name) {
+		return
+	}
+	if _, ok := knownElements[name]; ok {
+		return
+	}
+	*diags = append(*diags, parser.Diagnostic{
+		Message: "unknown element <" + e.Name + ">" + suggest(name, knownElements),
+		Range:   e.NameRange,
+	})
+}
+
+func (v vocabulary) checkAttributes(elementName string, attrs []parser.Attribute, diags *[]parser.Diagnostic) {
+	for _, attr := range attrs {
+		var key parser.AttributeKey
+		switch attr := attr.(type) {
+		case *parser.ConstantAttribute:
+			key = attr.Key
+		case *parser.BoolConstantAttribute:
+			key = attr.Key
+		case *parser.ExpressionAttribute:
+			key = attr.Key
+		case *parser.BoolExpressionAttribute:
+			key = attr.Key
+		case *parser.ConditionalAttribute:
+			v.checkAttributes(elementName, attr.Then, diags)
+			v.checkAttributes(elementName, attr.Else, diags)
+			continue
+		default:
+			continue
+		}
+		k, ok := key.(parser.ConstantAttributeKey)
+		if !ok {
+			continue
+		}
+		name := strings.ToLower(k.Name)
+		if v.isKnownAttribute(name) {
+			continue
+		}
+		*diags = append(*diags, parser.Diagnostic{
+			Message: fmt.Sprintf("unknown attribute %q on <%s>%s", k.Name, elementName, suggest(name, knownAttributes)),
+			Range:   k.NameRange,
+		})
+	}
+}
+
+func (v vocabulary) isKnownAttribute(name string) bool {
+	if strings.HasPrefix(name, "data-") || v.isAllowed(name) {
+		return true
+	}
+	if event, ok := strings.CutPrefix(name, "on"); ok {
+		if _, ok := knownEvents[event]; ok {
+			return true
+		}
+	}
+	_, ok := knownAttributes[name]
+	return ok
+}
+
+const maxSuggestionDistance = 2
+
+// suggest returns a hint containing the closest known name, if one is close enough to be a typo.
+func suggest(name string, known map[string]struct{}) string {
+	candidates := make([]string, 0, len(known))
+	for k := range known {
+		candidates = append(candidates, k)
+	}
+	sort.Strings(candidates)
+	best, bestDistance, bestPrefix := "", maxSuggestionDistance+1, 0
+	for _, c := range candidates {
+		d := editDistance(name, c)
+		if d > maxSuggestionDistance || d > bestDistance {
+			continue
+		}
+		// Prefer names that start the same way, since typos are more common at the end of a word.
+		p := commonPrefixLength(name, c)
+		if d < bestDistance || p > bestPrefix {
+			best, bestDistance, bestPrefix = c, d, p
+		}
+	}
+	if best == "" || bestDistance >= len(name) {
+		return ""
+	}
+	return ", did you mean " + best + "?"
+}
+
+func commonPrefixLength(a, b string) (n int) {
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions
+// of adjacent characters needed to change a into b.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGeneratorStrict(t *testing.T) {
+	input := `package main
+
+templ List(items []string) {
+	<tabel clas="items" hx-get="/items" data-id="1" aria-label="Items" onclick="go()">
+		for _, item := range items {
+			<my-row if true { x-data="{}" } { templ.Attributes{"z": item}... }>{ item }</my-row>
+		}
+	</tabel>
+	<svg viewBox="0 0 10 10"><clipPath id="c"></clipPath></svg>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("unknown names are returned as diagnostics", func(t *testing.T) {
+		op, err := Generate(tf, new(bytes.Buffer), WithStrict("hx-*"))
+		if err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		var actual []string
+		for _, d := range op.Diagnostics {
+			actual = append(actual, d.Message)
+		}
+		expected := []string{
+			"unknown element <tabel>, did you mean table?",
+			`unknown attribute "clas" on <tabel>, did you mean class?`,
+			`unknown attribute "x-data" on <my-row>, did you mean data?`,
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+		if op.Diagnostics[0].Range.From.Line != 3 || op.Diagnostics[0].Range.From.Col != 2 {
+			t.Errorf("expected the diagnostic to point at the element name, got %v", op.Diagnostics[0].Range)
+		}
+	})
+	t.Run("allowed names aren't reported", func(t *testing.T) {
+		op, err := Generate(tf, new(bytes.Buffer), WithStrict("tabel", "CLAS", "hx-*", "x-*"))
+		if err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if len(op.Diagnostics) != 0 {
+			t.Errorf("expected no diagnostics, got %v", op.Diagnostics)
+		}
+	})
+	t.Run("unknown names are errors with WithStrictErrors", func(t *testing.T) {
+		_, err := Generate(tf, new(bytes.Buffer), WithStrictErrors("hx-*", "x-*"))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		expected := "4:3: unknown element <tabel>, did you mean table?\n4:9: unknown attribute \"clas\" on <tabel>, did you mean class?"
+		if diff := cmp.Diff(expected, err.Error()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("names aren't checked by default", func(t *testing.T) {
+		op, err := Generate(tf, new(bytes.Buffer))
+		if err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if len(op.Diagnostics) != 0 {
+			t.Errorf("expected no diagnostics, got %v", op.Diagnostics)
+		}
+	})
+}
+
+func TestGeneratorStrictURLs(t *testing.T) {
+	input := `package main
+
+templ Link() {
+	<a href="javascript:alert(1)">Click</a>
+	<a href="https://example.com">Home</a>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("unsafe URL schemes are errors in strict mode", func(t *testing.T) {
+		_, err := Generate(tf, new(bytes.Buffer), WithStrict())
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		expected := "4:5: <a href> uses the unsafe URL scheme \"javascript:\", which isn't sanitized in constant attributes"
+		if diff := cmp.Diff(expected, err.Error()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("unsafe URL schemes aren't errors by default", func(t *testing.T) {
+		if _, err := Generate(tf, new(bytes.Buffer)); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+	})
+	t.Run("allowed URL schemes aren't errors in strict mode", func(t *testing.T) {
+		tf, err := parser.ParseString(`package main
+
+templ Link() {
+	<a href="sms:+15550100">Text</a>
+}`)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		if _, err := Generate(tf, new(bytes.Buffer), WithStrict()); err == nil {
+			t.Fatal("expected an error without sms: in the allow list")
+		}
+		if _, err := Generate(tf, new(bytes.Buffer), WithStrict("hx-*", "sms:")); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "tabel", expected: ", did you mean table?"},
+		{name: "dvi", expected: ", did you mean div?"},
+		{name: "completelyunknown", expected: ""},
+		{name: "zz", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := suggest(tt.name, knownElements); !strings.EqualFold(actual, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithStrictText returns an error if the text of a template contains an &, < or > character
+// that isn't part of a character reference, e.g. &amp;, so that templates are valid XML
+// fragments as written, e.g. for XHTML. Use character references or string expressions,
+// e.g. { "&" }, instead.
+func WithStrictText() GenerateOpt {
+	return func(g *generator) error {
+		g.options.StrictText = true
+		return nil
+	}
+}
+
+var characterReference = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]*|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+
+var textCharacterReferences = map[byte]string{
+	'&': "&amp;",
+	'<': "&lt;",
+	'>': "&gt;",
+}
+
+// checkStrictText returns an error for each bare &, < or > character in the text of the
+// templates.
+func (g *generator) checkStrictText() error {
+	if !g.options.StrictText {
+		return nil
+	}
+	var errs []error
+	for _, n := range g.tf.Nodes {
+		if t, ok := n.(*parser.HTMLTemplate); ok {
+			checkStrictTextNodes(t.Children, &errs)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkStrictTextNodes(nodes []parser.Node, errs *[]error) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *parser.Text:
+			// Text nodes don't span lines, so the column is the offset within the text.
+			checkStrictTextLine(n.Value, int(n.Range.From.Line)+1, int(n.Range.From.Col)+1, "text", "", errs)
+		case *parser.TextBlock:
+			// The lines of the value start on the line after the opening delimiter, after
+			// the shared indentation. Text blocks are escaped, so character references
+			// aren't written as they are.
+			for i, line := range strings.Split(n.Value, "\n") {
+				checkStrictTextLine(line, int(n.Range.From.Line)+i+2, n.Indent+1, "text block", " outside the text block", errs)
+			}
+		}
+		if c, ok := n.(parser.CompositeNode); ok {
+			checkStrictTextNodes(c.ChildNodes(), errs)
+		}
+	}
+}
+
+// checkStrictTextLine returns an error for each bare &, < or > character in a line of text
+// that starts at the line and column, numbered from 1.
+func checkStrictTextLine(text string, line, col int, kind, where string, errs *[]error) {
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		ref, ok := textCharacterReferences[c]
+		if !ok {
+			continue
+		}
+		if c == '&' {
+			if m := characterReference.FindString(text[i:]); m != "" {
+				i += len(m) - 1
+				continue
+			}
+		}
+		*errs = append(*errs, fmt.Errorf("%d:%d: bare %q in %s, use %s or a string expression%s, e.g. { %q }", line, col+i, c, kind, ref, where, string(c)))
+	}
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorStrictText(t *testing.T) {
+	input := `package main
+
+templ Prices() {
+	<p>Fish &amp; chips &#163;5 &#x20AC;6 &copy;</p>
+	if true {
+		<p>Salt & vinegar, 2 > 1</p>
+	}
+	<p>{ "&" }</p>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("bare characters are accepted by default", func(t *testing.T) {
+		if _, err := Generate(tf, new(bytes.Buffer)); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+	})
+	t.Run("bare characters are errors in strict text mode", func(t *testing.T) {
+		_, err := Generate(tf, new(bytes.Buffer), WithStrictText())
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		expected := `6:11: bare '&' in text, use &amp; or a string expression, e.g. { "&" }` + "\n" +
+			`6:24: bare '>' in text, use &gt; or a string expression, e.g. { ">" }`
+		if err.Error() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, err.Error())
+		}
+	})
+	t.Run("text blocks are checked", func(t *testing.T) {
+		tf, err := parser.ParseString(`package main
+
+templ Poem() {
+	<pre>
+		"""
+		Roses &amp; violets,
+		  a < b
+		"""
+	</pre>
+}`)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		_, err = Generate(tf, new(bytes.Buffer), WithStrictText())
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		expected := `7:7: bare '<' in text block, use &lt; or a string expression outside the text block, e.g. { "<" }`
+		if err.Error() != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, err.Error())
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package generator
+
+import "strings"
+
+// nameSet returns a set of the lowercase names in the whitespace separated list.
+func nameSet(names ...string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, list := range names {
+		for _, name := range strings.Fields(list) {
+			set[strings.ToLower(name)] = struct{}{}
+		}
+	}
+	return set
+}
+
+const htmlElements = `a abbr address area article aside audio b base bdi bdo blockquote body br button
+canvas caption cite code col colgroup data datalist dd del details dfn dialog div dl dt em embed
+fieldset figcaption figure footer form h1 h2 h3 h4 h5 h6 head header hgroup hr html i iframe img
+input ins kbd label legend li link main map mark menu meta meter nav noscript object ol optgroup
+option output p param picture pre progress q rp rt ruby s samp script search section select
+selectedcontent slot small source span strong style sub summary sup table tbody td template
+textarea tfoot th thead time title tr track u ul var video wbr`
+
+const svgElements = `svg animate animateMotion animateTransform circle clipPath defs desc ellipse
+feBlend feColorMatrix feComponentTransfer feComposite feConvolveMatrix feDiffuseLighting
+feDisplacementMap feDistantLight feDropShadow feFlood feFuncA feFuncB feFuncG feFuncR
+feGaussianBlur feImage feMerge feMergeNode feMorphology feOffset fePointLight
+feSpecularLighting feSpotLight feTile feTurbulence filter foreignObject g image line
+linearGradient marker mask metadata mpath path pattern polygon polyline radialGradient rect set
+stop switch symbol text textPath tspan use view`
+
+const mathMLElements = `math annotation maction menclose merror mfrac mi mmultiscripts mn mo
+mover mpadded mphantom mprescripts mroot mrow ms mspace msqrt mstyle msub msubsup msup mtable
+mtd mtext mtr munder munderover semantics`
+
+var knownElements = nameSet(htmlElements, svgElements, mathMLElements)
+
+const htmlAttributes = `accesskey autocapitalize autocorrect autofocus class contenteditable dir
+draggable enterkeyhint exportparts hidden id inert inputmode is itemid itemprop itemref itemscope
+itemtype lang nonce part popover role slot spellcheck style tabindex title translate
+writingsuggestions
+abbr accept accept-charset action allow allowfullscreen alpha alt as async autocomplete autoplay
+blocking charset checked cite closedby colorspace cols colspan command commandfor content
+controls coords crossorigin data datetime decoding default defer dirname disabled download
+enctype fetchpriority for form formaction formenctype formmethod formnovalidate formtarget
+headers height high href hreflang http-equiv imagesizes imagesrcset integrity ismap kind label
+list loading loop low max maxlength media method min minlength multiple muted name nomodule
+novalidate open optimum pattern ping placeholder playsinline popovertarget popovertargetaction
+poster preload readonly referrerpolicy rel required reversed rows rowspan sandbox scope
+selected shadowrootclonable shadowrootdelegatesfocus shadowrootmode shadowrootserializable
+shape size sizes span src srcdoc srclang srcset start step target type usemap value width wrap
+xmlns xmlns:xlink xml:lang xml:space`
+
+const ariaAttributes = `aria-activedescendant aria-atomic aria-autocomplete aria-braillelabel
+aria-brailleroledescription aria-busy aria-checked aria-colcount aria-colindex aria-colindextext
+aria-colspan aria-controls aria-current aria-describedby aria-description aria-details
+aria-disabled aria-dropeffect aria-errormessage aria-expanded aria-flowto aria-grabbed
+aria-haspopup aria-hidden aria-invalid aria-keyshortcuts aria-label aria-labelledby aria-level
+aria-live aria-modal aria-multiline aria-multiselectable aria-orientation aria-owns
+aria-placeholder aria-posinset aria-pressed aria-readonly aria-relevant aria-required
+aria-roledescription aria-rowcount aria-rowindex aria-rowindextext aria-rowspan aria-selected
+aria-setsize aria-sort aria-valuemax aria-valuemin aria-valuenow aria-valuetext`
+
+const svgAttributes = `alignment-baseline attributeName attributeType baseFrequency
+baseline-shift begin bias by calcMode clip clip-path clip-rule clipPathUnits color
+color-interpolation color-interpolation-filters cursor cx cy d diffuseConstant direction
+display divisor dominant-baseline dur dx dy edgeMode elevation end exponent fill fill-opacity
+fill-rule filter filterUnits flood-color flood-opacity font-family font-size font-size-adjust
+font-stretch font-style font-variant font-weight fr from fx fy gradientTransform gradientUnits
+image-rendering in in2 intercept k k1 k2 k3 k4 kernelMatrix kernelUnitLength keyPoints
+keySplines keyTimes lengthAdjust letter-spacing lighting-color limitingConeAngle marker-end
+marker-mid marker-start markerHeight markerUnits markerWidth mask mask-type maskContentUnits
+maskUnits mode numOctaves offset opacity operator order orient origin overflow paint-order path
+pathLength patternContentUnits patternTransform patternUnits pointer-events points pointsAtX
+pointsAtY pointsAtZ preserveAlpha preserveAspectRatio primitiveUnits r radius refX refY
+repeatCount repeatDur restart result rotate rx ry scale seed shape-rendering side spacing
+specularConstant specularExponent spreadMethod startOffset stdDeviation stitchTiles stop-color
+stop-opacity stroke stroke-dasharray stroke-dashoffset stroke-linecap stroke-linejoin
+stroke-miterlimit stroke-opacity stroke-width surfaceScale systemLanguage tableValues targetX
+targetY text-anchor text-decoration text-rendering textLength to transform transform-origin
+unicode-bidi values vector-effect version viewBox visibility word-spacing writing-mode x x1 x2
+xChannelSelector xlink:href xlink:title y y1 y2 yChannelSelector z zoomAndPan`
+
+const mathMLAttributes = `accent accentunder columnalign columnlines columnspacing displaystyle
+encoding fence largeop linethickness lspace mathbackground mathcolor mathsize mathvariant
+maxsize minsize movablelimits notation rowalign rowlines rowspacing rspace scriptlevel
+separator stretchy symmetric voffset`
+
+var knownAttributes = nameSet(htmlAttributes, ariaAttributes, svgAttributes, mathMLAttributes)
+
+// knownEvents are the names of events, without the "on" prefix of their event handler attributes.
+var knownEvents = nameSet(`abort afterprint animationcancel animationend animationiteration
+animationstart auxclick beforeinput beforematch beforeprint beforetoggle beforeunload blur cancel
+canplay canplaythrough change click close command contextlost contextmenu contextrestored copy
+cuechange cut dblclick drag dragend dragenter dragleave dragover dragstart drop durationchange
+emptied ended error focus focusin focusout formdata fullscreenchange fullscreenerror
+gotpointercapture hashchange input invalid keydown keypress keyup languagechange load
+loadeddata loadedmetadata loadstart lostpointercapture message messageerror mousedown
+mouseenter mouseleave mousemove mouseout mouseover mouseup offline online pagehide pagereveal
+pageshow pageswap paste pause play playing pointercancel pointerdown pointerenter pointerleave
+pointermove pointerout pointerover pointerup popstate progress ratechange rejectionhandled reset
+resize scroll scrollend securitypolicyviolation seeked seeking select selectionchange
+selectstart slotchange stalled storage submit suspend timeupdate toggle touchcancel touchend
+touchmove touchstart transitioncancel transitionend transitionrun transitionstart
+unhandledrejection unload volumechange waiting wheel`)
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"html"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// Target is the output format that templates are generated for. The generator walks the nodes
+// of templates, and writes the Go code of control flow, components and expressions, while the
+// target decides the output of elements, text and comments. See WithTarget.
+//
+// Constant strings returned by a target are written to the output as is.
+type Target interface {
+	// StartTag returns the output written before the attributes of an element, e.g. <div.
+	StartTag(name string) string
+	// StartTagEnd returns the output written after the attributes of an element, e.g. > or />.
+	StartTagEnd(n *parser.Element) string
+	// HasEndTag returns true if the children and end tag of the element are written.
+	HasEndTag(n *parser.Element) bool
+	// EndTag returns the end tag of an element, e.g. </div>.
+	EndTag(n *parser.Element) string
+	// Attributes returns true if the attributes of elements are written. If it's false, the
+	// attribute expressions aren't evaluated.
+	Attributes() bool
+	// RawElements returns true if <script> and <style> elements are written.
+	RawElements() bool
+	// Text returns the output of text, e.g. the text within an element. Text is HTML, so it
+	// can contain character references such as &amp;.
+	Text(s string) string
+	// EscapeString returns the output of a constant string, e.g. the value of a text block, or
+	// a string expression that was evaluated during generation.
+	EscapeString(s string) string
+	// EscapeExpression returns the Go expression that converts the value of the Go string
+	// expression to output, e.g. templ.EscapeString(s).
+	EscapeExpression(expr string) string
+	// Comment returns the output of a HTML comment, or "" to omit it.
+	Comment(contents string) string
+	// DocType returns the output of a doctype, or "" to omit it.
+	DocType(value string) string
+}
+
+// WithTarget sets the output format that templates are generated for. The default is HTML.
+func WithTarget(t Target) GenerateOpt {
+	return func(g *generator) error {
+		g.target = t
+		return nil
+	}
+}
+
+// HTMLTarget generates HTML, the default target.
+type HTMLTarget struct {
+	// VoidElementStyle is how void elements are written, see WithVoidElements.
+	VoidElementStyle VoidElementStyle
+	// VoidElements are the names of elements that are written as void elements, in addition
+	// to the HTML void elements.
+	VoidElements []string
+}
+
+func (t HTMLTarget) StartTag(name string) string {
+	return "<" + html.EscapeString(name)
+}
+
+func (t HTMLTarget) StartTagEnd(n *parser.Element) string {
+	if t.VoidElementStyle == VoidElementsXHTML && t.isVoidElement(n) {
+		return "/>"
+	}
+	return ">"
+}
+
+func (t HTMLTarget) HasEndTag(n *parser.Element) bool {
+	return t.VoidElementStyle == VoidElementsClosed || !t.isVoidElement(n)
+}
+
+func (t HTMLTarget) EndTag(n *parser.Element) string {
+	return "</" + html.EscapeString(n.Name) + ">"
+}
+
+func (t HTMLTarget) Attributes() bool { return true }
+
+func (t HTMLTarget) RawElements() bool { return true }
+
+func (t HTMLTarget) Text(s string) string { return s }
+
+func (t HTMLTarget) EscapeString(s string) string { return html.EscapeString(s) }
+
+func (t HTMLTarget) EscapeExpression(expr string) string {
+	return "templ.EscapeString(" + expr + ")"
+}
+
+func (t HTMLTarget) Comment(contents string) string {
+	return "<!--" + contents + "-->"
+}
+
+func (t HTMLTarget) DocType(value string) string {
+	return "<!doctype " + value + ">"
+}
+
+// TextTarget generates plain text, e.g. for the text part of an email. Tags, attributes,
+// comments, and <script> and <style> elements are omitted, and character references are
+// decoded. Block elements and <br> elements end with a new line.
+//
+// Variables that are only used by attributes are unused in the generated code.
+type TextTarget struct{}
+
+func (TextTarget) StartTag(name string) string { return "" }
+
+func (TextTarget) StartTagEnd(n *parser.Element) string {
+	if strings.EqualFold(n.Name, "br") {
+		return "\n"
+	}
+	return ""
+}
+
+func (TextTarget) HasEndTag(n *parser.Element) bool {
+	return len(n.Children) > 0 || !n.IsVoidElement()
+}
+
+func (TextTarget) EndTag(n *parser.Element) string {
+	if n.IsBlockElement() {
+		return "\n"
+	}
+	return ""
+}
+
+func (TextTarget) Attributes() bool { return false }
+
+func (TextTarget) RawElements() bool { return false }
+
+func (TextTarget) Text(s string) string { return html.UnescapeString(s) }
+
+func (TextTarget) EscapeString(s string) string { return s }
+
+func (TextTarget) EscapeExpression(expr string) string { return expr }
+
+func (TextTarget) Comment(contents string) string { return "" }
+
+func (TextTarget) DocType(value string) string { return "" }
+
+// writeTargetLiteral writes output returned by the target, skipping empty output.
+func (g *generator) writeTargetLiteral(indentLevel int, s string) (err error) {
+	if s == "" {
+		return nil
+	}
+	_, err = g.w.WriteStringLiteral(indentLevel, escapeQuotes(s))
+	return err
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorTarget(t *testing.T) {
+	input := `package main
+
+templ Email(name string) {
+	<!DOCTYPE html>
+	<style>p { color: red; }</style>
+	<!-- Greeting. -->
+	<h1 class="title">Hello, { name }</h1>
+	<p>Fish &amp; chips<br/>{ "&" }</p>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	tests := []struct {
+		name        string
+		opts        []GenerateOpt
+		expected    []string
+		notExpected []string
+	}{
+		{
+			name: "templates are generated as HTML by default",
+			expected: []string{
+				`<!doctype html><style`,
+				`>p { color: red; }</style><!-- Greeting. --><h1 class=\"title\">Hello, `,
+				`templ.EscapeString(templ_7745c5c3_Var2)`,
+				`</h1><p>Fish &amp; chips<br>&amp;</p>`,
+			},
+		},
+		{
+			name: "templates can be generated as plain text",
+			opts: []GenerateOpt{WithTarget(TextTarget{})},
+			expected: []string{
+				`"Hello, "`,
+				`WriteString(templ_7745c5c3_Var2)`,
+				`"\nFish & chips\n&\n"`,
+			},
+			notExpected: []string{"<", "templ.EscapeString", "Greeting", "color"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			if _, err := Generate(tf, w, tt.opts...); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			for _, s := range tt.expected {
+				if !strings.Contains(w.String(), s) {
+					t.Errorf("expected %s in output, got:\n%s", s, w.String())
+				}
+			}
+			for _, s := range tt.notExpected {
+				if strings.Contains(w.String(), s) {
+					t.Errorf("unexpected %s in output, got:\n%s", s, w.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"github.com/a-h/templ/parser/v2"
+	"github.com/a-h/templ/parser/v2/visitor"
+)
+
+// writeTemplateElementChildren writes the contents of a <template> element. The contents are
+// inert until they're cloned, or attached as a declarative shadow root, so they're rendered
+// with a context that doesn't share the CSS, scripts and once handles rendered in the document,
+// see templ.WithTemplateScope.
+func (g *generator) writeTemplateElementChildren(indentLevel int, nodes []parser.Node) (err error) {
+	if !usesRenderedOnceState(nodes) {
+		return g.writeNodes(indentLevel, nodes, nil)
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "{\n"); err != nil {
+		return err
+	}
+	indentLevel++
+	if _, err = g.w.WriteIndent(indentLevel, "ctx := templ.WithTemplateScope(ctx)\n"); err != nil {
+		return err
+	}
+	if _, err = g.w.WriteIndent(indentLevel, "_ = ctx\n"); err != nil {
+		return err
+	}
+	if err = g.writeNodes(indentLevel, nodes, nil); err != nil {
+		return err
+	}
+	indentLevel--
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
+}
+
+// usesRenderedOnceState returns true if the nodes may render content that's only rendered once
+// per context, i.e. components, CSS classes, and script handlers.
+func usesRenderedOnceState(nodes []parser.Node) (ok bool) {
+	v := visitor.New()
+	v.TemplElementExpression = func(n *parser.TemplElementExpression) error {
+		ok = true
+		return nil
+	}
+	v.CallTemplateExpression = func(n *parser.CallTemplateExpression) error {
+		ok = true
+		return nil
+	}
+	v.ChildrenExpression = func(n *parser.ChildrenExpression) error {
+		ok = true
+		return nil
+	}
+	v.ExpressionAttribute = func(n *parser.ExpressionAttribute) error {
+		ok = true
+		return nil
+	}
+	v.SpreadAttributes = func(n *parser.SpreadAttributes) error {
+		ok = true
+		return nil
+	}
+	for _, n := range nodes {
+		_ = n.Visit(v)
+	}
+	return ok
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+	"golang.org/x/mod/semver"
+)
+
+// WithTemplVersion sets the version of templ that the generated code is compiled with, i.e.
+// the version of github.com/a-h/templ required by the go.mod file of the module, e.g.
+// v0.3.924. Syntax added in later versions keeps the meaning that it had before, so that
+// upgrading the templ CLI doesn't change the output of templates until the module upgrades
+// templ too.
+//
+// If the version isn't set, or isn't a valid semantic version, all syntax is supported.
+func WithTemplVersion(v string) GenerateOpt {
+	return func(g *generator) error {
+		g.options.TemplVersion = v
+		return nil
+	}
+}
+
+// spreadComponentsVersion is the first version of templ that renders a slice of components
+// with @components.... Before it, @components... rendered the component, followed by the
+// text "...".
+const spreadComponentsVersion = "v0.3.928"
+
+// supports returns true if the version of templ that the code is compiled with has the
+// syntax that was added in version v.
+//
+// Pre-release and pseudo-versions are compared as the version that they precede, e.g.
+// v0.3.928-0.20251015120000-abcdef123456 is a commit after v0.3.927, so it supports the
+// syntax of v0.3.928.
+func (g *generator) supports(v string) bool {
+	current := g.options.TemplVersion
+	if !semver.IsValid(current) {
+		return true
+	}
+	if pre := semver.Prerelease(current); pre != "" {
+		current = strings.TrimSuffix(semver.Canonical(current), pre)
+	}
+	return semver.Compare(current, v) >= 0
+}
+
+// checkTemplVersion returns a diagnostic for each use of syntax that isn't supported by the
+// version of templ, and keeps its previous meaning.
+func (g *generator) checkTemplVersion() (diags []parser.Diagnostic) {
+	if g.supports(spreadComponentsVersion) {
+		return nil
+	}
+	for _, n := range g.tf.Nodes {
+		if t, ok := n.(*parser.HTMLTemplate); ok {
+			checkSpreadComponents(t.Children, &diags)
+		}
+	}
+	return diags
+}
+
+func checkSpreadComponents(nodes []parser.Node, diags *[]parser.Diagnostic) {
+	for _, n := range nodes {
+		if e, ok := n.(*parser.TemplElementExpression); ok && e.Spread {
+			*diags = append(*diags, parser.Diagnostic{
+				Message: fmt.Sprintf("@%s... renders @%s followed by the text \"...\", upgrade github.com/a-h/templ to %s or later in go.mod to render each component of the slice", e.Expression.Value, e.Expression.Value, spreadComponentsVersion),
+				Range:   e.Expression.Range,
+			})
+		}
+		if c, ok := n.(parser.CompositeNode); ok {
+			checkSpreadComponents(c.ChildNodes(), diags)
+		}
+	}
+}
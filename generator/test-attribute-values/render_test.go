@@ -0,0 +1,24 @@
+package testattributevalues
+
+import (
+	_ "embed"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := form(3, 9.99, 10, true, 90*time.Second)
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
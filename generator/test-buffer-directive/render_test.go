@@ -0,0 +1,58 @@
+package testbufferdirective
+
+import (
+	"context"
+	_ "embed"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render()
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+type countingWriter struct {
+	writes int
+	sb     strings.Builder
+}
+
+func (w *countingWriter) Write(p []byte) (n int, err error) {
+	w.writes++
+	return w.sb.Write(p)
+}
+
+func TestUnbufferedWritesDirectly(t *testing.T) {
+	w := new(countingWriter)
+	if err := unbuffered("name").Render(context.Background(), w); err != nil {
+		t.Fatal(err)
+	}
+	if w.writes < 3 {
+		t.Errorf("expected each part of the output to be written directly, got %d writes", w.writes)
+	}
+	if w.sb.String() != "<div>name</div>" {
+		t.Errorf("unexpected output %q", w.sb.String())
+	}
+}
+
+func TestBufferedWritesOnce(t *testing.T) {
+	w := new(countingWriter)
+	if err := buffered().Render(context.Background(), w); err != nil {
+		t.Fatal(err)
+	}
+	if w.writes != 1 {
+		t.Errorf("expected a single write, got %d", w.writes)
+	}
+}
@@ -0,0 +1,45 @@
+package testcacheblock
+
+import (
+	"context"
+	_ "embed"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	ctx := templ.WithFragmentCache(context.Background(), templ.NewMemoryFragmentCache())
+
+	component := Menu("en", []string{"Books", "Games"})
+	_, diff, err := htmldiff.DiffCtx(ctx, component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+
+	// The second render uses the cached output, so the new categories aren't rendered.
+	var sb strings.Builder
+	if err = Menu("en", []string{"Music"}).Render(ctx, &sb); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sb.String(), "Music") || !strings.Contains(sb.String(), "Books") {
+		t.Errorf("expected the cached output to be rendered, got %s", sb.String())
+	}
+
+	// Other keys are rendered.
+	sb.Reset()
+	if err = Menu("fr", []string{"Musique"}).Render(ctx, &sb); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), "Musique") {
+		t.Errorf("expected the fr menu to be rendered, got %s", sb.String())
+	}
+}
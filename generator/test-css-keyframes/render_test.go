@@ -0,0 +1,31 @@
+package testcsskeyframes
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := TestComponent()
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestAnimationName(t *testing.T) {
+	c := spinner().(templ.ComponentCSSClass)
+	if actual := c.AnimationName("spin"); actual != "spin_39c25648" {
+		t.Errorf("expected the animation name to be spin_39c25648, got %q", actual)
+	}
+}
@@ -0,0 +1,25 @@
+package testcssnesting
+
+import (
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCSSNesting(t *testing.T) {
+	actual := button("red").(templ.ComponentCSSClass)
+	id := actual.ID
+	expected := "." + id + "{color:red;padding:4px;}" +
+		"." + id + ":hover,." + id + ":focus{color:#ffffff;}" +
+		"." + id + " > .icon{width:16px;}" +
+		"." + id + " > .icon:hover{width:20px;}" +
+		"@media (max-width: 600px){." + id + "{padding:2px;}}" +
+		"@media (max-width: 600px){." + id + " .icon{display:none;}}"
+	if diff := cmp.Diff(expected, string(actual.Class)); diff != "" {
+		t.Error(diff)
+	}
+	if other := button("blue").(templ.ComponentCSSClass); other.ID == id {
+		t.Errorf("expected different IDs for different CSS, got %q", id)
+	}
+}
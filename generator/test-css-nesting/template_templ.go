@@ -0,0 +1,31 @@
+// Code generated by templ - DO NOT EDIT.
+
+package testcssnesting
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+func button(color string) templ.CSSClass {
+	templ_7745c5c3_CSSBuilder := templruntime.GetBuilder()
+	templ_7745c5c3_CSSBuilder.WriteString(string(templ.SanitizeCSS(`color`, color)))
+	templ_7745c5c3_CSSBuilder.WriteString(`padding:4px;`)
+	templ_7745c5c3_CSSBuilder1 := templruntime.GetBuilder()
+	templ_7745c5c3_CSSBuilder1.WriteString(`color:#ffffff;`)
+	templ_7745c5c3_CSSBuilder2 := templruntime.GetBuilder()
+	templ_7745c5c3_CSSBuilder2.WriteString(`width:16px;`)
+	templ_7745c5c3_CSSBuilder3 := templruntime.GetBuilder()
+	templ_7745c5c3_CSSBuilder3.WriteString(`width:20px;`)
+	templ_7745c5c3_CSSBuilder4 := templruntime.GetBuilder()
+	templ_7745c5c3_CSSBuilder4.WriteString(`padding:2px;`)
+	templ_7745c5c3_CSSBuilder5 := templruntime.GetBuilder()
+	templ_7745c5c3_CSSBuilder5.WriteString(`display:none;`)
+	templ_7745c5c3_CSSID := templ.CSSID(`button`, templ_7745c5c3_CSSBuilder.String()+`&:hover, &:focus{`+templ_7745c5c3_CSSBuilder1.String()+`}> .icon{`+templ_7745c5c3_CSSBuilder2.String()+`&:hover{`+templ_7745c5c3_CSSBuilder3.String()+`}}@media (max-width: 600px){`+templ_7745c5c3_CSSBuilder4.String()+`.icon{`+templ_7745c5c3_CSSBuilder5.String()+`}}`)
+	return templ.ComponentCSSClass{
+		ID:    templ_7745c5c3_CSSID,
+		Class: templ.SafeCSS(`.` + templ_7745c5c3_CSSID + `{` + templ_7745c5c3_CSSBuilder.String() + `}.` + templ_7745c5c3_CSSID + `:hover,.` + templ_7745c5c3_CSSID + `:focus{` + templ_7745c5c3_CSSBuilder1.String() + `}.` + templ_7745c5c3_CSSID + ` > .icon{` + templ_7745c5c3_CSSBuilder2.String() + `}.` + templ_7745c5c3_CSSID + ` > .icon:hover{` + templ_7745c5c3_CSSBuilder3.String() + `}@media (max-width: 600px){.` + templ_7745c5c3_CSSID + `{` + templ_7745c5c3_CSSBuilder4.String() + `}}@media (max-width: 600px){.` + templ_7745c5c3_CSSID + ` .icon{` + templ_7745c5c3_CSSBuilder5.String() + `}}`),
+	}
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -1,10 +1,12 @@
 package testfragment
 
 import (
+	"context"
 	_ "embed"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/a-h/templ"
@@ -78,3 +80,26 @@ func Test(t *testing.T) {
 		})
 	}
 }
+
+func TestFragmentDefinition(t *testing.T) {
+	t.Run("the whole template is rendered by default", func(t *testing.T) {
+		w := new(strings.Builder)
+		if err := List([]string{"a", "b"}).Render(context.Background(), w); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		expected := `<h1>Items</h1><ul><li>a</li><li>b</li></ul>`
+		if w.String() != expected {
+			t.Errorf("expected %q, got %q", expected, w.String())
+		}
+	})
+	t.Run("fragments can be rendered by name", func(t *testing.T) {
+		w := new(strings.Builder)
+		if err := templ.RenderFragment(context.Background(), w, List([]string{"a", "b"}), "item"); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		expected := `<li>a</li><li>b</li>`
+		if w.String() != expected {
+			t.Errorf("expected %q, got %q", expected, w.String())
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package testjsonscript
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJSONScript(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    templ.Component
+		expected string
+	}{
+		{
+			name:     "expressions are JSON encoded",
+			input:    props(product{Name: "</script><b>", Tags: []string{"a & b"}}),
+			expected: `<script type="application/json" id="props">{"name":"\u003c/script\u003e\u003cb\u003e","tags":["a \u0026 b"]}</script>`,
+		},
+		{
+			name:     "expressions in JSON strings are escaped",
+			input:    structuredData(`"Quoted" </script>`),
+			expected: `<script type="application/ld+json">{"@type": "Product", "name": "\u0022Quoted\u0022 \u003c\/script\u003e"}</script>`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			if err := test.input.Render(context.Background(), w); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(test.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -17,4 +17,16 @@ func withParameters(a string, b string, c int) templ.ComponentScript {
 	}
 }
 
+// withParametersBind returns the withParameters script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func withParametersBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_withParameters_1056`,
+		Function: `function __templ_withParameters_1056(a, b, c){console.log(a, b, c);
+}`,
+		Call:       templ.SafeScriptBind(`__templ_withParameters_1056`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_withParameters_1056`, params...),
+	}
+}
+
 var _ = templruntime.GeneratedTemplate
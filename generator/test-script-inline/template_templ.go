@@ -17,6 +17,18 @@ func withParameters(a string, b string, c int) templ.ComponentScript {
 	}
 }
 
+// withParametersBind returns the withParameters script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func withParametersBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_withParameters_1056`,
+		Function: `function __templ_withParameters_1056(a, b, c){console.log(a, b, c);
+}`,
+		Call:       templ.SafeScriptBind(`__templ_withParameters_1056`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_withParameters_1056`, params...),
+	}
+}
+
 func withoutParameters() templ.ComponentScript {
 	return templ.ComponentScript{
 		Name: `__templ_withoutParameters_6bbf`,
@@ -27,6 +39,18 @@ func withoutParameters() templ.ComponentScript {
 	}
 }
 
+// withoutParametersBind returns the withoutParameters script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func withoutParametersBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_withoutParameters_6bbf`,
+		Function: `function __templ_withoutParameters_6bbf(){alert("hello");
+}`,
+		Call:       templ.SafeScriptBind(`__templ_withoutParameters_6bbf`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_withoutParameters_6bbf`, params...),
+	}
+}
+
 func InlineJavascript(a string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -48,19 +72,19 @@ func InlineJavascript(a string) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = withoutParameters().Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(withoutParameters()).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = withParameters(a, "test", 123).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(withParameters(a, "test", 123)).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = withoutParameters().Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(withoutParameters()).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = withParameters(a, "test", 123).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(withParameters(a, "test", 123)).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -17,6 +17,18 @@ func withParameters(a string, b string, c int) templ.ComponentScript {
 	}
 }
 
+// withParametersBind returns the withParameters script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func withParametersBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_withParameters_1056`,
+		Function: `function __templ_withParameters_1056(a, b, c){console.log(a, b, c);
+}`,
+		Call:       templ.SafeScriptBind(`__templ_withParameters_1056`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_withParameters_1056`, params...),
+	}
+}
+
 func withoutParameters() templ.ComponentScript {
 	return templ.ComponentScript{
 		Name: `__templ_withoutParameters_6bbf`,
@@ -27,6 +39,18 @@ func withoutParameters() templ.ComponentScript {
 	}
 }
 
+// withoutParametersBind returns the withoutParameters script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func withoutParametersBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_withoutParameters_6bbf`,
+		Function: `function __templ_withoutParameters_6bbf(){alert("hello");
+}`,
+		Call:       templ.SafeScriptBind(`__templ_withoutParameters_6bbf`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_withoutParameters_6bbf`, params...),
+	}
+}
+
 func onClick() templ.ComponentScript {
 	return templ.ComponentScript{
 		Name: `__templ_onClick_657d`,
@@ -37,6 +61,18 @@ func onClick() templ.ComponentScript {
 	}
 }
 
+// onClickBind returns the onClick script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func onClickBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_onClick_657d`,
+		Function: `function __templ_onClick_657d(){alert("clicked");
+}`,
+		Call:       templ.SafeScriptBind(`__templ_onClick_657d`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_onClick_657d`, params...),
+	}
+}
+
 func Button(text string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -111,6 +147,18 @@ func withComment() templ.ComponentScript {
 	}
 }
 
+// withCommentBind returns the withComment script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func withCommentBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_withComment_9cf8`,
+		Function: `function __templ_withComment_9cf8(){//'
+}`,
+		Call:       templ.SafeScriptBind(`__templ_withComment_9cf8`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_withComment_9cf8`, params...),
+	}
+}
+
 func ThreeButtons() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -132,11 +180,11 @@ func ThreeButtons() templ.Component {
 			templ_7745c5c3_Var5 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = Button("A").Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(Button("A")).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = Button("B").Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(Button("B")).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -161,7 +209,7 @@ func ThreeButtons() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = Conditional(true).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.NilSafe(Conditional(true)).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -179,6 +227,18 @@ func conditionalScript() templ.ComponentScript {
 	}
 }
 
+// conditionalScriptBind returns the conditionalScript script with params as its first arguments, followed
+// by the arguments of the event handler that calls it.
+func conditionalScriptBind(params ...any) templ.ComponentScript {
+	return templ.ComponentScript{
+		Name: `__templ_conditionalScript_de41`,
+		Function: `function __templ_conditionalScript_de41(){alert("conditional");
+}`,
+		Call:       templ.SafeScriptBind(`__templ_conditionalScript_de41`, params...),
+		CallInline: templ.SafeScriptInline(`__templ_conditionalScript_de41`, params...),
+	}
+}
+
 func Conditional(show bool) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -0,0 +1,36 @@
+package testslots
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render("Slots")
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSlotsAreNotPassedToNestedComponents(t *testing.T) {
+	// The wrapper doesn't use slots, so the header slot isn't passed on to the layout.
+	expected := `<header></header><main><p>Nested</p></main><footer></footer>`
+
+	diff, err := htmldiff.Diff(nested(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
@@ -136,3 +136,38 @@ func TestNumericAttributeTypes(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+type disabled bool
+
+type label string
+
+//go:embed expected_boolean_attributes.html
+var expectedBooleanAttributes string
+
+func TestBooleanAttributes(t *testing.T) {
+	t.Parallel()
+	component := BasicTemplate(templ.Attributes{
+		// Should not render, as the value is false.
+		"disabled": false,
+		// Should render as `hidden`, as the value is true.
+		"hidden": true,
+		// Should not render, as the value of the named type is false.
+		"inert": disabled(false),
+		// Should render as `required`, as the value of the named type is true.
+		"required": ptr(disabled(true)),
+		// Should not render a nil pointer to a named type.
+		"readonly": nilPtr[disabled](),
+		// Should render as `aria-label="Close"`.
+		"aria-label": ptr(label("Close")),
+		// Should not render a nil pointer to a named string type.
+		"title": nilPtr[label](),
+	})
+
+	diff, err := htmldiff.Diff(component, expectedBooleanAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	_ "embed"
 
+	"github.com/a-h/templ"
 	"github.com/a-h/templ/generator/htmldiff"
 )
 
@@ -22,3 +23,27 @@ func Test(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestSpread(t *testing.T) {
+	component := items([]templ.Component{wrapper(1), nil, wrapper(2)})
+
+	diff, err := htmldiff.Diff(component, `<ul><div id="1"></div><div id="2"></div></ul>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSpreadOfTypedSlice(t *testing.T) {
+	component := labels([]label{"a", "b"})
+
+	diff, err := htmldiff.Diff(component, `<ul><li>a</li><li>b</li></ul>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,35 @@
+package templateelement
+
+import (
+	"context"
+	_ "embed"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render()
+
+	diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestWhitespaceAroundTemplateElements(t *testing.T) {
+	var sb strings.Builder
+	if err := render().Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), "Rows are cloned from <template") || !strings.Contains(sb.String(), "</template> the template.") {
+		t.Errorf("expected whitespace around the template element to be kept, got:\n%s", sb.String())
+	}
+}
@@ -0,0 +1,39 @@
+package testtextblock
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTextBlock(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    templ.Component
+		expected string
+	}{
+		{
+			name:     "line breaks and indentation are kept, and text is escaped",
+			input:    poem("anon"),
+			expected: "<pre>Roses are &lt;red&gt;,\n  violets &amp; blue.\n\n    -- anon</pre>",
+		},
+		{
+			name:     "indentation relative to the closing delimiter is kept",
+			input:    code(),
+			expected: "<pre><code>\tfunc main() {\n\t\tfmt.Println(&#34;Hello&#34;)\n\t}</code></pre>",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			if err := test.input.Render(context.Background(), w); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			if diff := cmp.Diff(test.expected, w.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package testtexttransform
+
+import (
+	"context"
+	_ "embed"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render("/don't")
+
+	quotes := strings.NewReplacer(`'`, "’")
+	ctx := templ.WithTextTransformers(context.Background(), quotes.Replace)
+	_, diff, err := htmldiff.DiffCtx(ctx, component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,26 @@
+package testurlsanitizer
+
+import (
+	"context"
+	_ "embed"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render("magnet:?xt=urn:btih:1")
+
+	ctx := templ.WithURLSanitizer(context.Background(), templ.AllowURLSchemes("magnet"))
+	_, diff, err := htmldiff.DiffCtx(ctx, component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Error(diff)
+	}
+}
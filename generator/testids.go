@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"html"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithTestIDs adds a data-testid attribute to the root elements of each component, e.g.
+// data-testid="Card", so that end-to-end tests can find the output of a component without
+// adding attributes to the templates. Root elements that already have a data-testid
+// attribute aren't changed, including those whose spread attributes contain one when
+// they're rendered.
+//
+// The attributes are intended for development and test builds, and shouldn't be used in
+// production.
+func WithTestIDs() GenerateOpt {
+	return func(g *generator) error {
+		g.options.TestIDs = true
+		return nil
+	}
+}
+
+// collectTestIDs sets the test IDs of the root elements of the template, i.e. the elements
+// that aren't within another element, including those within if, switch and for statements.
+func (g *generator) collectTestIDs(t *parser.HTMLTemplate) {
+	g.testIDs = nil
+	if !g.options.TestIDs || t.Extends != nil {
+		return
+	}
+	g.testIDs = map[*parser.Element]string{}
+	g.collectRootElements(t.Children, componentName(t.Expression.Value))
+}
+
+func (g *generator) collectRootElements(nodes []parser.Node, name string) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *parser.Element:
+			if !hasAttribute(n.Attributes, "data-testid") {
+				g.testIDs[n] = name
+			}
+		case *parser.IfExpression:
+			g.collectRootElements(n.Then, name)
+			for _, elseIf := range n.ElseIfs {
+				g.collectRootElements(elseIf.Then, name)
+			}
+			g.collectRootElements(n.Else, name)
+		case *parser.SwitchExpression:
+			for _, c := range n.Cases {
+				g.collectRootElements(c.Children, name)
+			}
+		case *parser.ForExpression:
+			g.collectRootElements(n.Children, name)
+		case *parser.FragmentDefinition:
+			g.collectRootElements(n.Children, name)
+		case *parser.CacheExpression:
+			g.collectRootElements(n.Children, name)
+		}
+	}
+}
+
+// hasAttribute returns true if the attributes include the named attribute, including
+// within conditional attributes.
+func hasAttribute(attrs []parser.Attribute, name string) bool {
+	for _, attr := range attrs {
+		var key parser.AttributeKey
+		switch attr := attr.(type) {
+		case *parser.ConstantAttribute:
+			key = attr.Key
+		case *parser.BoolConstantAttribute:
+			key = attr.Key
+		case *parser.ExpressionAttribute:
+			key = attr.Key
+		case *parser.BoolExpressionAttribute:
+			key = attr.Key
+		case *parser.ConditionalAttribute:
+			if hasAttribute(attr.Then, name) || hasAttribute(attr.Else, name) {
+				return true
+			}
+			continue
+		default:
+			continue
+		}
+		if k, ok := key.(parser.ConstantAttributeKey); ok && strings.EqualFold(k.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSpreadAttributes returns true if the attributes include spread attributes, including
+// within conditional attributes.
+func hasSpreadAttributes(attrs []parser.Attribute) bool {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case *parser.SpreadAttributes:
+			return true
+		case *parser.ConditionalAttribute:
+			if hasSpreadAttributes(attr.Then) || hasSpreadAttributes(attr.Else) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeElementAttributesWithTestID writes the attributes of an element, followed by its test ID,
+// if it has one. Whether spread attributes include a data-testid attribute is only known when
+// the element is rendered, so it's recorded in a variable while they're written.
+func (g *generator) writeElementAttributesWithTestID(indentLevel int, n *parser.Element, attrs []parser.Attribute) (err error) {
+	if _, ok := g.testIDs[n]; !ok || !hasSpreadAttributes(attrs) {
+		if err = g.writeElementAttributes(indentLevel, n.Name, attrs); err != nil {
+			return err
+		}
+		return g.writeTestID(indentLevel, n)
+	}
+	// var templ_7745c5c3_Var1 bool
+	testIDVar := g.createVariableName()
+	if _, err = g.w.WriteIndent(indentLevel, "var "+testIDVar+" bool\n"); err != nil {
+		return err
+	}
+	previous := g.testIDVar
+	g.testIDVar = testIDVar
+	err = g.writeElementAttributes(indentLevel, n.Name, attrs)
+	g.testIDVar = previous
+	if err != nil {
+		return err
+	}
+	// if !templ_7745c5c3_Var1 {
+	if _, err = g.w.WriteIndent(indentLevel, "if !"+testIDVar+" {\n"); err != nil {
+		return err
+	}
+	if err = g.writeTestID(indentLevel+1, n); err != nil {
+		return err
+	}
+	// }
+	_, err = g.w.WriteIndent(indentLevel, "}\n")
+	return err
+}
+
+func (g *generator) writeTestID(indentLevel int, n *parser.Element) (err error) {
+	name, ok := g.testIDs[n]
+	if !ok {
+		return nil
+	}
+	_, err = g.w.WriteStringLiteral(indentLevel, ` data-testid=\"`+escapeQuotes(html.EscapeString(name))+`\"`)
+	return err
+}
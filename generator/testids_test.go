@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorTestIDs(t *testing.T) {
+	input := `package main
+
+templ Card(title string, items []string) {
+	<div class="card">
+		<h2>{ title }</h2>
+	</div>
+	for _, item := range items {
+		<p>{ item }</p>
+	}
+	if title == "" {
+		<span data-testid="empty">Empty</span>
+	}
+	@Layout() {
+		<main>Content</main>
+	}
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	t.Run("test IDs are added to root elements when enabled", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w, WithTestIDs()); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if _, err = format.Source(w.Bytes()); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		for _, expected := range []string{
+			`<div class=\"card\" data-testid=\"Card\"><h2>`,
+			`"<p data-testid=\"Card\">"`,
+			`<span data-testid=\"empty\">Empty</span>`,
+			`"<main>Content</main>"`,
+		} {
+			if !strings.Contains(w.String(), expected) {
+				t.Errorf("expected %s, got:\n%s", expected, w.String())
+			}
+		}
+		if count := strings.Count(w.String(), `data-testid=\"Card\"`); count != 2 {
+			t.Errorf("expected 2 test IDs, got %d:\n%s", count, w.String())
+		}
+	})
+	t.Run("test IDs are only added if spread attributes don't contain one", func(t *testing.T) {
+		tf, err := parser.ParseString(`package main
+
+templ Button(attrs templ.Attributes) {
+	<button { attrs... }>Click</button>
+}`)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w, WithTestIDs()); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if _, err = format.Source(w.Bytes()); err != nil {
+			t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+		}
+		for _, expected := range []string{
+			`if templ.HasAttribute(templ_7745c5c3_Var3, "data-testid") {`,
+			`if !templ_7745c5c3_Var2 {`,
+			`templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " data-testid=\"Button\"")`,
+		} {
+			if !strings.Contains(w.String(), expected) {
+				t.Errorf("expected %s, got:\n%s", expected, w.String())
+			}
+		}
+	})
+	t.Run("test IDs are not added by default", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		if _, err := Generate(tf, w); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		if strings.Contains(w.String(), `data-testid=\"Card\"`) {
+			t.Errorf("expected no test IDs, got:\n%s", w.String())
+		}
+	})
+}
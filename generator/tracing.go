@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithTracing starts a span for each component as it renders, using the tracer in the render
+// context, see templ.WithTracing. The spans are only started if the program is built with the
+// templ_trace build tag, so the generated code can be used in builds without tracing.
+func WithTracing() GenerateOpt {
+	return func(g *generator) error {
+		g.options.Tracing = true
+		return nil
+	}
+}
+
+func (g *generator) writeStartRender(indentLevel int, t *parser.HTMLTemplate) (err error) {
+	if !g.options.Tracing {
+		return nil
+	}
+	name := componentName(t.Expression.Value)
+	if pkg := packageName(g.tf.Package.Expression.Value); pkg != "" {
+		name = pkg + "." + name
+	}
+	line := strconv.Itoa(int(t.Range.From.Line) + 1)
+	if _, err = g.w.WriteIndent(indentLevel, "ctx, templ_7745c5c3_EndRender := templruntime.StartRender(ctx, "+strconv.Quote(name)+", "+createGoString(g.options.FileName)+", "+line+")\n"); err != nil {
+		return err
+	}
+	_, err = g.w.WriteIndent(indentLevel, "defer func() { templ_7745c5c3_EndRender(templ_7745c5c3_Err) }()\n")
+	return err
+}
+
+// packageName returns the name of the package in a package expression, e.g. "pages" for
+// "package pages".
+func packageName(expr string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(expr), "package"))
+}
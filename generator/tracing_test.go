@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorTracing(t *testing.T) {
+	input := `package pages
+
+templ Card(title string) {
+	<div>{ title }</div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	w := new(bytes.Buffer)
+	if _, err := Generate(tf, w, WithFileName("card.templ"), WithTracing()); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	if _, err = format.Source(w.Bytes()); err != nil {
+		t.Fatalf("generated code is not valid Go: %v\n%s", err, w.String())
+	}
+	expected := "ctx, templ_7745c5c3_EndRender := templruntime.StartRender(ctx, \"pages.Card\", `card.templ`, 3)"
+	if !strings.Contains(w.String(), expected) {
+		t.Errorf("expected span to be started, got:\n%s", w.String())
+	}
+}
@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/a-h/templ/parser/v2/visitor"
+)
+
+// ExpressionKind is where a Go expression appears in a template.
+type ExpressionKind string
+
+const (
+	// ExpressionKindString is a string expression, e.g. { name }.
+	ExpressionKindString ExpressionKind = "string"
+	// ExpressionKindAttribute is an attribute value, e.g. <a href={ url }>, or spread attributes.
+	ExpressionKindAttribute ExpressionKind = "attribute"
+	// ExpressionKindComponent is a component, e.g. @Card(item).
+	ExpressionKindComponent ExpressionKind = "component"
+	// ExpressionKindIf is the condition of an if statement, else if statement, or conditional attribute.
+	ExpressionKindIf ExpressionKind = "if"
+	// ExpressionKindFor is the header of a for statement, e.g. for _, item := range items.
+	ExpressionKindFor ExpressionKind = "for"
+	// ExpressionKindSwitch is the header of a switch statement.
+	ExpressionKindSwitch ExpressionKind = "switch"
+	// ExpressionKindGoCode is a block of Go code, e.g. {{ total := sum(items) }}.
+	ExpressionKindGoCode ExpressionKind = "code"
+	// ExpressionKindScript is a Go expression within a script element, e.g. <script>var x = {{ value }};</script>.
+	ExpressionKindScript ExpressionKind = "script"
+)
+
+// GoExpression is a Go expression of a template, passed to an ExpressionValidator.
+type GoExpression struct {
+	Kind ExpressionKind
+	// Expression is the source of the expression, and its range in the templ file.
+	Expression parser.Expression
+	// Node is the parsed Go. It's an ast.Expr for string, attribute, component and script
+	// expressions, or an *ast.CompositeLit containing the values if there's more than one,
+	// e.g. { name, err }. It's an *ast.BlockStmt for Go code, and an *ast.IfStmt, *ast.ForStmt,
+	// *ast.RangeStmt, *ast.SwitchStmt or *ast.TypeSwitchStmt with an empty body for the
+	// headers of statements.
+	Node ast.Node
+
+	fset   *token.FileSet
+	offset int
+}
+
+// Range returns the range of a node of the expression in the templ file.
+func (e GoExpression) Range(n ast.Node) parser.Range {
+	return parser.Range{From: e.position(n.Pos()), To: e.position(n.End())}
+}
+
+func (e GoExpression) position(pos token.Pos) parser.Position {
+	offset := e.fset.Position(pos).Offset - e.offset
+	offset = max(0, min(offset, len(e.Expression.Value)))
+	before := e.Expression.Value[:offset]
+	p := e.Expression.Range.From
+	p.Index += int64(offset)
+	if lines := strings.Count(before, "\n"); lines > 0 {
+		p.Line += uint32(lines)
+		p.Col = 0
+		before = before[strings.LastIndex(before, "\n")+1:]
+	}
+	p.Col += uint32(len(before))
+	return p
+}
+
+// ExpressionValidator checks a Go expression of a template, e.g. to forbid calling time.Now()
+// in templates, and returns a diagnostic for each problem found. Use GoExpression.Range to
+// get the range of a node.
+type ExpressionValidator func(e GoExpression) []parser.Diagnostic
+
+// WithExpressionValidators adds validators that check the Go expressions of templates.
+// Generation fails if a validator returns any diagnostics.
+func WithExpressionValidators(validators ...ExpressionValidator) GenerateOpt {
+	return func(g *generator) error {
+		g.expressionValidators = append(g.expressionValidators, validators...)
+		return nil
+	}
+}
+
+// validateExpressions runs the expression validators against the templates of the file.
+func (g *generator) validateExpressions() (diags []parser.Diagnostic, err error) {
+	if len(g.expressionValidators) == 0 {
+		return nil, nil
+	}
+	validate := func(kind ExpressionKind, e parser.Expression) {
+		ge, ok := parseGoExpression(kind, e)
+		if !ok {
+			// Invalid Go is reported when the generated code is compiled.
+			return
+		}
+		for _, validator := range g.expressionValidators {
+			diags = append(diags, validator(ge)...)
+		}
+	}
+	v := visitor.New()
+	visitIf, visitFor, visitSwitch := v.IfExpression, v.ForExpression, v.SwitchExpression
+	visitConditionalAttribute, visitTemplElement, visitScript := v.ConditionalAttribute, v.TemplElementExpression, v.ScriptElement
+	v.StringExpression = func(n *parser.StringExpression) error {
+		validate(ExpressionKindString, n.Expression)
+		return nil
+	}
+	v.ExpressionAttribute = func(n *parser.ExpressionAttribute) error {
+		validate(ExpressionKindAttribute, n.Expression)
+		return nil
+	}
+	v.BoolExpressionAttribute = func(n *parser.BoolExpressionAttribute) error {
+		validate(ExpressionKindAttribute, n.Expression)
+		return nil
+	}
+	v.SpreadAttributes = func(n *parser.SpreadAttributes) error {
+		validate(ExpressionKindAttribute, n.Expression)
+		return nil
+	}
+	v.ConditionalAttribute = func(n *parser.ConditionalAttribute) error {
+		validate(ExpressionKindIf, n.Expression)
+		return visitConditionalAttribute(n)
+	}
+	v.TemplElementExpression = func(n *parser.TemplElementExpression) error {
+		validate(ExpressionKindComponent, n.Expression)
+		return visitTemplElement(n)
+	}
+	v.CallTemplateExpression = func(n *parser.CallTemplateExpression) error {
+		validate(ExpressionKindComponent, n.Expression)
+		return nil
+	}
+	v.IfExpression = func(n *parser.IfExpression) error {
+		validate(ExpressionKindIf, n.Expression)
+		for _, elseIf := range n.ElseIfs {
+			validate(ExpressionKindIf, elseIf.Expression)
+		}
+		return visitIf(n)
+	}
+	v.ForExpression = func(n *parser.ForExpression) error {
+		validate(ExpressionKindFor, n.Expression)
+		return visitFor(n)
+	}
+	v.SwitchExpression = func(n *parser.SwitchExpression) error {
+		validate(ExpressionKindSwitch, n.Expression)
+		return visitSwitch(n)
+	}
+	v.GoCode = func(n *parser.GoCode) error {
+		validate(ExpressionKindGoCode, n.Expression)
+		return nil
+	}
+	v.ScriptElement = func(n *parser.ScriptElement) error {
+		for _, c := range n.Contents {
+			if c.GoCode != nil {
+				validate(ExpressionKindScript, c.GoCode.Expression)
+			}
+		}
+		return visitScript(n)
+	}
+	for _, n := range g.tf.Nodes {
+		if t, ok := n.(*parser.HTMLTemplate); ok {
+			if err = t.Visit(v); err != nil {
+				return diags, err
+			}
+		}
+	}
+	var errs []error
+	for _, d := range diags {
+		errs = append(errs, fmt.Errorf("%d:%d: %s", d.Range.From.Line+1, d.Range.From.Col+1, d.Message))
+	}
+	return diags, errors.Join(errs...)
+}
+
+// parseGoExpression parses the expression within a function body, so that statements and
+// expressions can be parsed in the same way.
+func parseGoExpression(kind ExpressionKind, e parser.Expression) (ge GoExpression, ok bool) {
+	const header = "package p\nfunc _() {\n"
+	var prefix, suffix string
+	switch kind {
+	case ExpressionKindIf, ExpressionKindFor, ExpressionKindSwitch:
+		prefix, suffix = string(kind)+" ", " {}"
+	case ExpressionKindGoCode:
+		prefix, suffix = "", ""
+	default:
+		prefix, suffix = "_ = []any{", "}"
+	}
+	src := header + prefix + e.Value + suffix + "\n}\n"
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "", src, goparser.SkipObjectResolution)
+	if err != nil || len(f.Decls) != 1 {
+		return ge, false
+	}
+	body := f.Decls[0].(*ast.FuncDecl).Body
+	ge = GoExpression{
+		Kind:       kind,
+		Expression: e,
+		fset:       fset,
+		offset:     len(header) + len(prefix),
+	}
+	switch kind {
+	case ExpressionKindIf, ExpressionKindFor, ExpressionKindSwitch:
+		if len(body.List) != 1 {
+			return ge, false
+		}
+		ge.Node = body.List[0]
+	case ExpressionKindGoCode:
+		ge.Node = body
+	default:
+		if len(body.List) != 1 {
+			return ge, false
+		}
+		assign, ok := body.List[0].(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 {
+			return ge, false
+		}
+		lit, ok := assign.Rhs[0].(*ast.CompositeLit)
+		if !ok {
+			return ge, false
+		}
+		ge.Node = lit
+		if len(lit.Elts) == 1 {
+			ge.Node = lit.Elts[0]
+		}
+	}
+	return ge, true
+}
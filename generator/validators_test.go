@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// forbidTimeNow is an example validator that forbids calls to time.Now().
+func forbidTimeNow(e GoExpression) (diags []parser.Diagnostic) {
+	ast.Inspect(e.Node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Now" {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "time" {
+				diags = append(diags, parser.Diagnostic{
+					Message: "time.Now() is not allowed in templates, pass the time as a parameter",
+					Range:   e.Range(call),
+				})
+			}
+		}
+		return true
+	})
+	return diags
+}
+
+func TestGeneratorExpressionValidators(t *testing.T) {
+	t.Run("validators are called for each kind of expression", func(t *testing.T) {
+		input := `package main
+
+templ Page(items []string, attrs templ.Attributes) {
+	{{ count := len(items) }}
+	<div class={ "list" } hidden?={ count == 0 } { attrs... } if count > 1 { data-many }>
+		for _, item := range items {
+			{ item }
+		}
+		if count == 0 {
+			None
+		} else if count == 1 {
+			One
+		}
+		switch count {
+			case 0:
+		}
+		@Card(count)
+	</div>
+	<script>var count = {{ count }};</script>
+}`
+		tf, err := parser.ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		var actual []string
+		record := func(e GoExpression) []parser.Diagnostic {
+			if e.Node == nil {
+				t.Errorf("expected the %s expression %q to be parsed", e.Kind, e.Expression.Value)
+			}
+			actual = append(actual, string(e.Kind)+": "+e.Expression.Value)
+			return nil
+		}
+		if _, err = Generate(tf, new(bytes.Buffer), WithExpressionValidators(record)); err != nil {
+			t.Fatalf("failed to generate: %v", err)
+		}
+		expected := []string{
+			"code: count := len(items)",
+			`attribute: "list"`,
+			"attribute: count == 0",
+			"attribute: attrs",
+			"if: count > 1",
+			"for: _, item := range items",
+			"string: item",
+			"if: count == 0",
+			"if: count == 1",
+			"switch: count",
+			"component: Card(count)",
+			"script: count",
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("diagnostics fail generation with the position of the node", func(t *testing.T) {
+		input := `package main
+
+templ Page() {
+	<p>
+		Updated at { time.Now().Format(time.Kitchen) }
+	</p>
+	<p>{ "safe" }</p>
+}`
+		tf, err := parser.ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		op, err := Generate(tf, new(bytes.Buffer), WithExpressionValidators(forbidTimeNow))
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+		if !strings.Contains(err.Error(), "5:16: time.Now() is not allowed in templates") {
+			t.Errorf("expected the error to contain the position, got %v", err)
+		}
+		expected := []parser.Diagnostic{
+			{
+				Message: "time.Now() is not allowed in templates, pass the time as a parameter",
+				Range: parser.Range{
+					From: parser.Position{Index: 49, Line: 4, Col: 15},
+					To:   parser.Position{Index: 59, Line: 4, Col: 25},
+				},
+			},
+		}
+		if diff := cmp.Diff(expected, op.Diagnostics); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("multiline Go code is mapped to the templ file", func(t *testing.T) {
+		input := `package main
+
+templ Page() {
+	{{
+		today := time.Now()
+	}}
+	<p>{ today.String() }</p>
+}`
+		tf, err := parser.ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		op, _ := Generate(tf, new(bytes.Buffer), WithExpressionValidators(forbidTimeNow))
+		if len(op.Diagnostics) != 1 {
+			t.Fatalf("expected 1 diagnostic, got %#v", op.Diagnostics)
+		}
+		expected := parser.Position{Index: 44, Line: 4, Col: 11}
+		if diff := cmp.Diff(expected, op.Diagnostics[0].Range.From); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
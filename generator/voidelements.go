@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// VoidElementStyle is how void elements, such as <br>, are written.
+type VoidElementStyle int
+
+const (
+	// VoidElementsHTML writes void elements without a closing tag, e.g. <br>.
+	VoidElementsHTML VoidElementStyle = iota
+	// VoidElementsXHTML writes void elements as self-closing tags, e.g. <br/>.
+	VoidElementsXHTML
+	// VoidElementsClosed writes void elements with a closing tag, e.g. <br></br>.
+	VoidElementsClosed
+)
+
+// ParseVoidElementStyle parses the name of a VoidElementStyle, i.e. html, xhtml or closed.
+func ParseVoidElementStyle(s string) (VoidElementStyle, error) {
+	switch s {
+	case "html":
+		return VoidElementsHTML, nil
+	case "xhtml":
+		return VoidElementsXHTML, nil
+	case "closed":
+		return VoidElementsClosed, nil
+	}
+	return VoidElementsHTML, fmt.Errorf("unknown void element style %q, expected html, xhtml or closed", s)
+}
+
+// WithVoidElements sets how void elements that don't have any children are written, for
+// frontend libraries that don't accept HTML syntax.
+//
+// If names are provided, they're added to the list of HTML void elements, e.g. to write custom
+// elements as self-closing tags.
+func WithVoidElements(style VoidElementStyle, names ...string) GenerateOpt {
+	return func(g *generator) error {
+		g.options.VoidElementStyle = style
+		for _, name := range names {
+			g.options.VoidElements = append(g.options.VoidElements, strings.ToLower(strings.TrimSpace(name)))
+		}
+		return nil
+	}
+}
+
+func (t HTMLTarget) isVoidElement(n *parser.Element) bool {
+	if len(n.Children) > 0 {
+		return false
+	}
+	return n.IsVoidElement() || slices.Contains(t.VoidElements, strings.ToLower(n.Name))
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestGeneratorVoidElements(t *testing.T) {
+	input := `package main
+
+templ Form() {
+	<br/><input type="text"/><my-icon/><div></div>
+}`
+	tf, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	tests := []struct {
+		name     string
+		opts     []GenerateOpt
+		expected string
+	}{
+		{
+			name:     "void elements are written as HTML by default",
+			expected: `<br><input type=\"text\"><my-icon></my-icon><div></div>`,
+		},
+		{
+			name:     "void elements can be written as XHTML",
+			opts:     []GenerateOpt{WithVoidElements(VoidElementsXHTML)},
+			expected: `<br/><input type=\"text\"/><my-icon></my-icon><div></div>`,
+		},
+		{
+			name:     "void elements can be closed",
+			opts:     []GenerateOpt{WithVoidElements(VoidElementsClosed)},
+			expected: `<br></br><input type=\"text\"></input><my-icon></my-icon><div></div>`,
+		},
+		{
+			name:     "custom elements are added to the HTML void elements",
+			opts:     []GenerateOpt{WithVoidElements(VoidElementsXHTML, "My-Icon")},
+			expected: `<br/><input type=\"text\"/><my-icon/><div></div>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			if _, err := Generate(tf, w, tt.opts...); err != nil {
+				t.Fatalf("failed to generate: %v", err)
+			}
+			if !strings.Contains(w.String(), tt.expected) {
+				t.Errorf("expected %s in output, got:\n%s", tt.expected, w.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Package watcher watches a directory for changes to templ files, and regenerates the
+// Go code for each file that changes.
+//
+// It provides the same behaviour as `templ generate --watch` for use in build tools
+// that would otherwise need to run the templ CLI.
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/internal/skipdir"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/fsnotify/fsnotify"
+)
+
+// EventType is the outcome of processing a templ file.
+type EventType string
+
+const (
+	// EventGenerated is reported when Go code was generated for a templ file.
+	EventGenerated EventType = "generated"
+	// EventFailed is reported when a templ file could not be parsed or generated.
+	EventFailed EventType = "failed"
+	// EventSkipped is reported when a templ file is unchanged since it was last generated.
+	EventSkipped EventType = "skipped"
+)
+
+// Event describes the result of processing a single templ file.
+type Event struct {
+	Type EventType
+	// FileName is the absolute path of the templ file.
+	FileName string
+	// OutputFileName is the absolute path of the generated Go file.
+	OutputFileName string
+	// Diagnostics are warnings found in the templ file.
+	Diagnostics []parser.Diagnostic
+	// Err is set when Type is EventFailed.
+	Err error
+	// Duration is the time taken to process the file.
+	Duration time.Duration
+}
+
+// Options configures Run.
+type Options struct {
+	// GenerateOpts are passed to the generator for each file. The file name option is
+	// set automatically, relative to the watched directory.
+	GenerateOpts []generator.GenerateOpt
+	// OnEvent is called after each file is processed. Calls are made sequentially from
+	// the goroutine that called Run.
+	OnEvent func(Event)
+	// Debounce is the time to wait for further changes to a file before regenerating it.
+	// Defaults to 100ms.
+	Debounce time.Duration
+	// Log receives structured logs of each file that is processed. Defaults to discarding logs.
+	Log *slog.Logger
+}
+
+// Run generates Go code for all templ files in dir, then watches dir and its
+// subdirectories, regenerating only the templ files that change. Run blocks until the
+// context is cancelled or the file system watcher fails.
+func Run(ctx context.Context, dir string, opts Options) (err error) {
+	if opts.Debounce == 0 {
+		opts.Debounce = 100 * time.Millisecond
+	}
+	if opts.Log == nil {
+		opts.Log = slog.New(slog.NewJSONHandler(io.Discard, nil))
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return fmt.Errorf("failed to get absolute path for %q: %w", dir, err)
+	}
+	fsnw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer fsnw.Close()
+
+	w := &watcher{
+		dir:     dir,
+		opts:    opts,
+		hashes:  make(map[string][sha256.Size]byte),
+		outputs: make(map[string]generator.GeneratorOutput),
+		changed: make(chan string),
+		timers:  make(map[string]*time.Timer),
+	}
+
+	// Start watching before the initial generation, so that changes aren't missed.
+	if err = w.add(fsnw, dir); err != nil {
+		return err
+	}
+	templFiles, err := findTemplFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, fileName := range templFiles {
+		if ctx.Err() != nil {
+			return nil
+		}
+		w.process(fileName)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.stopTimers()
+			return nil
+		case fileName := <-w.changed:
+			w.process(fileName)
+		case event, ok := <-fsnw.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if err = w.add(fsnw, event.Name); err != nil {
+					return err
+				}
+			}
+			if !strings.HasSuffix(event.Name, ".templ") {
+				continue
+			}
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				delete(w.hashes, event.Name)
+				delete(w.outputs, event.Name)
+				continue
+			}
+			w.debounce(ctx, event.Name)
+		case err, ok := <-fsnw.Errors:
+			if !ok {
+				return nil
+			}
+			w.stopTimers()
+			return fmt.Errorf("watcher error: %w", err)
+		}
+	}
+}
+
+type watcher struct {
+	dir  string
+	opts Options
+	// hashes of the templ file contents that were last generated, keyed by file name.
+	hashes map[string][sha256.Size]byte
+	// outputs of the last generation, used for incremental generation.
+	outputs map[string]generator.GeneratorOutput
+	changed chan string
+	timers  map[string]*time.Timer
+}
+
+// add watches dir and all of its subdirectories. Files are ignored.
+func (w *watcher) add(fsnw *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != w.dir && skipdir.ShouldSkip(path) {
+			return filepath.SkipDir
+		}
+		return fsnw.Add(path)
+	})
+}
+
+func (w *watcher) debounce(ctx context.Context, fileName string) {
+	if t, ok := w.timers[fileName]; ok {
+		t.Reset(w.opts.Debounce)
+		return
+	}
+	w.timers[fileName] = time.AfterFunc(w.opts.Debounce, func() {
+		select {
+		case w.changed <- fileName:
+		case <-ctx.Done():
+		}
+	})
+}
+
+func (w *watcher) stopTimers() {
+	for _, t := range w.timers {
+		t.Stop()
+	}
+}
+
+func (w *watcher) process(fileName string) {
+	start := time.Now()
+	e := w.generate(fileName)
+	e.FileName = fileName
+	e.OutputFileName = strings.TrimSuffix(fileName, ".templ") + "_templ.go"
+	e.Duration = time.Since(start)
+	w.log(e)
+	if w.opts.OnEvent != nil {
+		w.opts.OnEvent(e)
+	}
+}
+
+func (w *watcher) log(e Event) {
+	attrs := []any{slog.String("file", e.FileName), slog.Duration("duration", e.Duration)}
+	switch e.Type {
+	case EventGenerated:
+		w.opts.Log.Debug("Generated code", attrs...)
+		for _, d := range e.Diagnostics {
+			w.opts.Log.Warn(d.Message, slog.String("file", e.FileName), slog.Int("line", int(d.Range.From.Line)+1), slog.Int("col", int(d.Range.From.Col)))
+		}
+	case EventSkipped:
+		w.opts.Log.Debug("Skipping file because it wasn't updated", attrs...)
+	case EventFailed:
+		w.opts.Log.Error("Failed to generate code", append(attrs, slog.Any("error", e.Err))...)
+	}
+}
+
+func (w *watcher) generate(fileName string) (e Event) {
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return Event{Type: EventFailed, Err: fmt.Errorf("failed to read %q: %w", fileName, err)}
+	}
+	hash := sha256.Sum256(contents)
+	if previous, ok := w.hashes[fileName]; ok && previous == hash {
+		return Event{Type: EventSkipped}
+	}
+	// Forget the hash until generation succeeds, so that a failed file is retried.
+	delete(w.hashes, fileName)
+
+	t, err := parser.ParseString(string(contents))
+	if err != nil {
+		return Event{Type: EventFailed, Err: fmt.Errorf("%s parsing error: %w", fileName, err)}
+	}
+
+	relFilePath, err := filepath.Rel(w.dir, fileName)
+	if err != nil {
+		return Event{Type: EventFailed, Err: fmt.Errorf("failed to get relative path for %q: %w", fileName, err)}
+	}
+	relFilePath = filepath.ToSlash(relFilePath)
+
+	genOpts := append(slices.Clone(w.opts.GenerateOpts), generator.WithFileName(relFilePath))
+	if previous, ok := w.outputs[fileName]; ok {
+		genOpts = append(genOpts, generator.WithPreviousOutput(previous))
+	}
+	var b bytes.Buffer
+	output, err := generator.Generate(t, &b, genOpts...)
+	if err != nil {
+		return Event{Type: EventFailed, Err: fmt.Errorf("%s generation error: %w", fileName, err)}
+	}
+	w.outputs[fileName] = output
+	formattedGoCode, err := format.Source(b.Bytes())
+	if err != nil {
+		return Event{Type: EventFailed, Err: fmt.Errorf("%s source formatting error: %w", fileName, err)}
+	}
+	targetFileName := strings.TrimSuffix(fileName, ".templ") + "_templ.go"
+	if err = os.WriteFile(targetFileName, formattedGoCode, 0o644); err != nil {
+		return Event{Type: EventFailed, Err: fmt.Errorf("failed to write target file %q: %w", targetFileName, err)}
+	}
+	w.hashes[fileName] = hash
+
+	diagnostics, err := parser.Diagnose(t)
+	if err != nil {
+		return Event{Type: EventFailed, Err: fmt.Errorf("%s diagnostics error: %w", fileName, err)}
+	}
+	return Event{Type: EventGenerated, Diagnostics: diagnostics}
+}
+
+func findTemplFiles(dir string) (fileNames []string, err error) {
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && path != dir && skipdir.ShouldSkip(path) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".templ") {
+			fileNames = append(fileNames, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find templ files in %q: %w", dir, err)
+	}
+	return fileNames, nil
+}
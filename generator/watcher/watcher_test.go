@@ -0,0 +1,90 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	templFileName := filepath.Join(dir, "template.templ")
+	goFileName := filepath.Join(dir, "template_templ.go")
+	write := func(contents string) {
+		t.Helper()
+		if err := os.WriteFile(templFileName, []byte(contents), 0o644); err != nil {
+			t.Fatalf("failed to write templ file: %v", err)
+		}
+	}
+	write("package main\n\ntempl Hello() {\n\t<div>Hello</div>\n}\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan Event, 10)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- Run(ctx, dir, Options{
+			OnEvent:  func(e Event) { events <- e },
+			Debounce: 10 * time.Millisecond,
+		})
+	}()
+	next := func(expected EventType) Event {
+		t.Helper()
+		select {
+		case e := <-events:
+			if e.Type != expected {
+				t.Fatalf("expected %q event, got %q: %v", expected, e.Type, e.Err)
+			}
+			if e.FileName != templFileName {
+				t.Errorf("expected file name %q, got %q", templFileName, e.FileName)
+			}
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %q event", expected)
+			return Event{}
+		}
+	}
+
+	t.Run("existing files are generated on start", func(t *testing.T) {
+		e := next(EventGenerated)
+		if e.OutputFileName != goFileName {
+			t.Errorf("expected output file name %q, got %q", goFileName, e.OutputFileName)
+		}
+		assertGoFileContains(t, goFileName, "<div>Hello</div>")
+	})
+	t.Run("changed files are regenerated", func(t *testing.T) {
+		write("package main\n\ntempl Hello() {\n\t<div>Updated</div>\n}\n")
+		next(EventGenerated)
+		assertGoFileContains(t, goFileName, "<div>Updated</div>")
+	})
+	t.Run("unchanged files are skipped", func(t *testing.T) {
+		write("package main\n\ntempl Hello() {\n\t<div>Updated</div>\n}\n")
+		next(EventSkipped)
+	})
+	t.Run("invalid files fail", func(t *testing.T) {
+		write("package main\n\ntempl Hello() {\n\t<div>\n}\n")
+		e := next(EventFailed)
+		if e.Err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func assertGoFileContains(t *testing.T, fileName, expected string) {
+	t.Helper()
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(contents), expected) {
+		t.Errorf("expected generated file to contain %q, got:\n%s", expected, contents)
+	}
+}
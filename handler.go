@@ -1,6 +1,7 @@
 package templ
 
 import (
+	"mime"
 	"net/http"
 )
 
@@ -12,12 +13,20 @@ type ComponentHandler struct {
 	ErrorHandler   func(r *http.Request, err error) http.Handler
 	StreamResponse bool
 	FragmentIDs    []any
+	// Charset that the response is transcoded to. The response is UTF-8 if the Name is empty.
+	// The charset parameter of the ContentType is set to the Name.
+	Charset Charset
+	// PrettyPrint re-indents the response, see NewPrettyWriter.
+	PrettyPrint bool
+	// Hints adds the RequestHints of the request to the render context, see GetRequestHints.
+	Hints bool
 }
 
 const componentHandlerErrorMessage = "templ: failed to render template"
 
-func (ch *ComponentHandler) handleRenderErr(w http.ResponseWriter, r *http.Request, err error) {
+func (ch *ComponentHandler) handleRenderErr(w http.ResponseWriter, r *http.Request, err error, discarded int) {
 	if ch.ErrorHandler != nil {
+		err = newAbandonedRenderError(r.Context(), err, discarded)
 		w.Header().Set("Content-Type", ch.ContentType)
 		ch.ErrorHandler(r, err).ServeHTTP(w, r)
 		return
@@ -33,7 +42,7 @@ func (ch *ComponentHandler) ServeHTTPBufferedFragment(w http.ResponseWriter, r *
 
 	// Render the component into io.Discard, but use the buffer for fragments.
 	if err := RenderFragments(r.Context(), buf, ch.Component, ch.FragmentIDs...); err != nil {
-		ch.handleRenderErr(w, r, err)
+		ch.handleRenderErr(w, r, err, buf.Len())
 		return
 	}
 
@@ -55,7 +64,7 @@ func (ch *ComponentHandler) ServeHTTPBufferedComplete(w http.ResponseWriter, r *
 
 	// Render the component into the buffer.
 	if err := ch.Component.Render(r.Context(), buf); err != nil {
-		ch.handleRenderErr(w, r, err)
+		ch.handleRenderErr(w, r, err, buf.Len())
 		return
 	}
 
@@ -92,7 +101,7 @@ func (ch *ComponentHandler) ServeHTTPStreamed(w http.ResponseWriter, r *http.Req
 
 		// Render the component into io.Discard, but use the buffer for fragments.
 		if err := RenderFragments(r.Context(), w, ch.Component, ch.FragmentIDs...); err != nil {
-			ch.handleRenderErr(w, r, err)
+			ch.handleRenderErr(w, r, err, 0)
 			return
 		}
 		return
@@ -100,13 +109,34 @@ func (ch *ComponentHandler) ServeHTTPStreamed(w http.ResponseWriter, r *http.Req
 
 	// Render the component into the buffer.
 	if err := ch.Component.Render(r.Context(), w); err != nil {
-		ch.handleRenderErr(w, r, err)
+		ch.handleRenderErr(w, r, err, 0)
 		return
 	}
 }
 
 // ServeHTTP implements the http.Handler interface.
 func (ch ComponentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Initialize the context before rendering, so that the component that was being rendered
+	// when the render was abandoned is recorded in it, see AbandonedRenderError.
+	r = r.WithContext(InitializeContext(r.Context()))
+	if ch.Hints {
+		addRequestHintsVary(w.Header())
+		r = r.WithContext(WithRequestHints(r.Context(), NewRequestHints(r)))
+	}
+	if ch.Charset.Name != "" {
+		ch.ContentType = contentTypeWithCharset(ch.ContentType, ch.Charset.Name)
+		cw := NewCharsetWriter(w, ch.Charset)
+		w = &charsetResponseWriter{ResponseWriter: w, cw: cw}
+		r = r.WithContext(WithRenderCharset(r.Context(), ch.Charset))
+		// Ignore the write error, like the other writes to the response.
+		defer func() { _ = cw.Flush() }()
+	}
+	if ch.PrettyPrint {
+		pw := NewPrettyWriter(w)
+		w = &prettyResponseWriter{ResponseWriter: w, pw: pw}
+		// Ignore the write error, like the other writes to the response.
+		defer func() { _ = pw.Flush() }()
+	}
 	if ch.StreamResponse {
 		ch.ServeHTTPStreamed(w, r)
 		return
@@ -140,6 +170,42 @@ func WithContentType(contentType string) func(*ComponentHandler) {
 	}
 }
 
+// WithCharset transcodes the response returned by the ComponentHandler from UTF-8 to cs,
+// and sets the charset of the Content-Type header. Use MetaCharset to render a matching
+// <meta charset> element.
+func WithCharset(cs Charset) func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.Charset = cs
+	}
+}
+
+// contentTypeWithCharset returns the content type with its charset parameter set to charset.
+func contentTypeWithCharset(contentType, charset string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/html", map[string]string{}
+	}
+	params["charset"] = charset
+	return mime.FormatMediaType(mediaType, params)
+}
+
+// WithHints adds the RequestHints of each request to the render context of the
+// ComponentHandler, and adds the headers that they're read from to the Vary header of the
+// response, see GetRequestHints.
+func WithHints() func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.Hints = true
+	}
+}
+
+// WithPrettyPrint re-indents the HTML returned by the ComponentHandler, to make it readable
+// during development, see NewPrettyWriter.
+func WithPrettyPrint() func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.PrettyPrint = true
+	}
+}
+
 // WithErrorHandler sets the error handler used if rendering fails.
 func WithErrorHandler(eh func(r *http.Request, err error) http.Handler) func(*ComponentHandler) {
 	return func(ch *ComponentHandler) {
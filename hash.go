@@ -0,0 +1,28 @@
+package templ
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+)
+
+// Hash renders the component and returns the hex encoded SHA-256 hash of its output, e.g. to
+// use as a cache key or ETag. The output is written to the hash as it's rendered, so it isn't
+// held in memory.
+func Hash(ctx context.Context, c Component) (string, error) {
+	sum, err := HashWith(ctx, c, sha256.New())
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// HashWith renders the component to h, and returns the resulting hash, e.g. to use a faster
+// hash function than SHA-256 for cache keys that aren't exposed to clients.
+func HashWith(ctx context.Context, c Component, h hash.Hash) ([]byte, error) {
+	if err := c.Render(ctx, h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
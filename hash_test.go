@@ -0,0 +1,60 @@
+package templ_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestHash(t *testing.T) {
+	text := func(s string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		})
+	}
+
+	t.Run("the hash is the SHA-256 of the output", func(t *testing.T) {
+		actual, err := templ.Hash(context.Background(), text("<div>Hello</div>"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sum := sha256.Sum256([]byte("<div>Hello</div>"))
+		if expected := hex.EncodeToString(sum[:]); actual != expected {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	})
+	t.Run("different output has a different hash", func(t *testing.T) {
+		a, _ := templ.Hash(context.Background(), text("a"))
+		b, _ := templ.Hash(context.Background(), text("b"))
+		if a == b {
+			t.Errorf("expected different hashes, got %q", a)
+		}
+	})
+	t.Run("rendering errors are returned", func(t *testing.T) {
+		errRender := errors.New("render failed")
+		failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return errRender
+		})
+		if _, err := templ.Hash(context.Background(), failing); !errors.Is(err, errRender) {
+			t.Errorf("expected render error, got %v", err)
+		}
+	})
+	t.Run("other hash functions can be used", func(t *testing.T) {
+		actual, err := templ.HashWith(context.Background(), text("<div>Hello</div>"), fnv.New64a())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		h := fnv.New64a()
+		_, _ = h.Write([]byte("<div>Hello</div>"))
+		if expected := h.Sum(nil); string(actual) != string(expected) {
+			t.Errorf("expected %x, got %x", expected, actual)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package templ
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// headManager collects the assets registered with Preload, and writes them at the position of
+// the HeadPreloads placeholder, see HeadManager.
+type headManager struct {
+	m        sync.Mutex
+	preloads []preload
+	// w is the writer of the HeadManager. Output is written to it until the placeholder is
+	// rendered, and then to after, so that the preloads can be written before it.
+	w      io.Writer
+	placed bool
+	after  bytes.Buffer
+}
+
+type preload struct {
+	href string
+	as   string
+}
+
+func (h *headManager) Write(p []byte) (n int, err error) {
+	if h.placed {
+		return h.after.Write(p)
+	}
+	return h.w.Write(p)
+}
+
+func (h *headManager) add(p preload) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	for _, existing := range h.preloads {
+		if existing == p {
+			return
+		}
+	}
+	h.preloads = append(h.preloads, p)
+}
+
+// len returns the number of assets registered, or 0 if h is nil.
+func (h *headManager) len() int {
+	if h == nil {
+		return 0
+	}
+	h.m.Lock()
+	defer h.m.Unlock()
+	return len(h.preloads)
+}
+
+// truncate discards the assets registered after the first n, e.g. by children whose output
+// was discarded by an ErrorBoundary.
+func (h *headManager) truncate(n int) {
+	if h == nil {
+		return
+	}
+	h.m.Lock()
+	defer h.m.Unlock()
+	if n < len(h.preloads) {
+		h.preloads = h.preloads[:n]
+	}
+}
+
+// HeadManager returns a component that renders its children, and writes a <link rel="preload">
+// element for each asset registered with Preload, e.g. by CriticalImage, at the position of
+// the HeadPreloads placeholder. Wrap the <html> element with it, and put the placeholder in
+// the <head> element, so that the browser starts to download the assets that are used by the
+// page before it parses the <body>.
+//
+//	@templ.HeadManager() {
+//		<html>
+//			<head>
+//				@templ.HeadPreloads()
+//			</head>
+//			<body>
+//				@templ.CriticalImage("/hero.jpg", "Our products")
+//			</body>
+//		</html>
+//	}
+//
+// Output before the placeholder is written as it's rendered, and output after it is buffered
+// until the children have been rendered.
+func HeadManager() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		children := GetChildren(ctx)
+		ctx, v := getContext(ClearChildren(ctx))
+		if children == nil {
+			return nil
+		}
+		previous := v.headManager
+		h := &headManager{w: w}
+		v.headManager = h
+		err = children.Render(ctx, h)
+		v.headManager = previous
+		if err != nil || !h.placed {
+			return err
+		}
+		for _, p := range h.preloads {
+			if err = writePreload(w, p); err != nil {
+				return err
+			}
+		}
+		_, err = w.Write(h.after.Bytes())
+		return err
+	})
+}
+
+func writePreload(w io.Writer, p preload) error {
+	crossorigin := ""
+	// Fonts are always fetched in CORS mode, so the preload must be too, to be used.
+	if p.as == "font" {
+		crossorigin = " crossorigin"
+	}
+	return writeStrings(w, `<link rel="preload" href="`, EscapeString(string(URL(p.href))), `" as="`, EscapeString(p.as), `"`, crossorigin, `>`)
+}
+
+// HeadPreloads returns the placeholder that the <link rel="preload"> elements of the nearest
+// HeadManager are written at. Outside of a HeadManager, it renders nothing.
+func HeadPreloads() Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		_, v := getContext(ctx)
+		h := v.headManager
+		if h == nil || h.placed {
+			return nil
+		}
+		// Write the buffered output of the parent components to the HeadManager, so that it's
+		// written before the preloads.
+		if f, ok := w.(flusherError); ok {
+			if err = f.Flush(); err != nil {
+				return err
+			}
+		}
+		h.placed = true
+		return nil
+	})
+}
+
+// Preload registers an asset that the page uses, to be preloaded by the nearest HeadManager.
+// as is the type of the asset, e.g. "image", "script", "style" or "font". Each asset is
+// only preloaded once, and outside of a HeadManager, nothing is preloaded.
+func Preload(ctx context.Context, href, as string) {
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	if !ok || v.headManager == nil {
+		return
+	}
+	v.headManager.add(preload{href: href, as: as})
+}
+
+// CriticalImage renders an <img> element, and preloads the image, see HeadManager. Use it for
+// images that are visible when the page loads, e.g. the hero image of a landing page.
+func CriticalImage(src, alt string, attrs ...Attributer) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		Preload(ctx, src, "image")
+		if err = writeStrings(w, `<img src="`, EscapeString(string(URL(src))), `" alt="`, EscapeString(alt), `"`); err != nil {
+			return err
+		}
+		if err = renderAttributers(ctx, w, attrs); err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, ">")
+		return err
+	})
+}
+
+// CriticalScript renders a <script> element that loads the script at src, and preloads it,
+// see HeadManager.
+func CriticalScript(src string, attrs ...Attributer) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		Preload(ctx, src, "script")
+		if err = writeStrings(w, `<script src="`, EscapeString(string(URL(src))), `"`); err != nil {
+			return err
+		}
+		if err = RenderNonceAttribute(ctx, w); err != nil {
+			return err
+		}
+		if err = renderAttributers(ctx, w, attrs); err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, "></script>")
+		return err
+	})
+}
+
+// CriticalStylesheet renders a <link rel="stylesheet"> element, and preloads the stylesheet,
+// see HeadManager. It's useful for stylesheets that are linked from the <body>, e.g. by a
+// component, since the preload is written in the <head>.
+func CriticalStylesheet(href string, attrs ...Attributer) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		Preload(ctx, href, "style")
+		if err = writeStrings(w, `<link rel="stylesheet" href="`, EscapeString(string(URL(href))), `"`); err != nil {
+			return err
+		}
+		if err = renderAttributers(ctx, w, attrs); err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, ">")
+		return err
+	})
+}
+
+func renderAttributers(ctx context.Context, w io.Writer, attrs []Attributer) error {
+	for _, a := range attrs {
+		if err := RenderAttributes(ctx, w, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
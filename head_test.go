@@ -0,0 +1,102 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestHeadManager(t *testing.T) {
+	list := func(components ...templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			for _, c := range components {
+				if err := c.Render(ctx, w); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	managed := func(children templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return templ.HeadManager().Render(templ.WithChildren(ctx, children), w)
+		})
+	}
+	preload := func(href, as string) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			templ.Preload(ctx, href, as)
+			return nil
+		})
+	}
+	failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return errors.New("failed")
+	})
+	tests := []struct {
+		name     string
+		c        templ.Component
+		ctx      context.Context
+		expected string
+	}{
+		{
+			name:     "helpers render their elements without a HeadManager",
+			c:        list(templ.HeadPreloads(), templ.CriticalImage("/a.jpg", "A"), templ.CriticalStylesheet("/a.css"), templ.CriticalScript("/a.js")),
+			expected: `<img src="/a.jpg" alt="A"><link rel="stylesheet" href="/a.css"><script src="/a.js"></script>`,
+		},
+		{
+			name:     "preloads are written at the placeholder, once per asset",
+			c:        managed(list(templ.Raw("<head>"), templ.HeadPreloads(), templ.Raw("</head>"), templ.CriticalImage("/a.jpg", "A"), templ.CriticalImage("/a.jpg", "B"))),
+			expected: `<head><link rel="preload" href="/a.jpg" as="image"></head><img src="/a.jpg" alt="A"><img src="/a.jpg" alt="B">`,
+		},
+		{
+			name:     "nothing is preloaded without a placeholder",
+			c:        managed(list(templ.Raw("<head></head>"), templ.CriticalImage("/a.jpg", "A"))),
+			expected: `<head></head><img src="/a.jpg" alt="A">`,
+		},
+		{
+			name:     "fonts are preloaded in CORS mode",
+			c:        managed(list(templ.HeadPreloads(), preload("/font.woff2", "font"))),
+			expected: `<link rel="preload" href="/font.woff2" as="font" crossorigin>`,
+		},
+		{
+			name:     "unsafe URLs are sanitized",
+			c:        managed(list(templ.HeadPreloads(), preload("javascript:alert(1)", "script"))),
+			expected: `<link rel="preload" href="about:invalid#TemplFailedSanitizationURL" as="script">`,
+		},
+		{
+			name:     "scripts have the CSP nonce",
+			c:        templ.CriticalScript("/a.js", templ.Attributes{"defer": true}),
+			ctx:      templ.WithNonce(context.Background(), "abc"),
+			expected: `<script src="/a.js" nonce="abc" defer></script>`,
+		},
+		{
+			name: "preloads of children that fail within an error boundary are discarded",
+			c: managed(list(
+				templ.HeadPreloads(),
+				preload("/a.jpg", "image"),
+				templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+					return templ.ErrorBoundary(templ.Raw("<p>fallback</p>")).Render(templ.WithChildren(ctx, list(preload("/b.jpg", "image"), failing)), w)
+				}),
+			)),
+			expected: `<link rel="preload" href="/a.jpg" as="image"><p>fallback</p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			var sb strings.Builder
+			if err := tt.c.Render(ctx, &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, sb.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package hx provides typed builders for HTMX attributes, for use as spread attributes.
+//
+//	<button { hx.Merge(hx.Post("/contacts"), hx.Target("#contacts"), hx.Swap(hx.SwapBeforeEnd))... }>Add</button>
+package hx
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// Get issues a GET request to the URL.
+func Get(url string) templ.Attributes {
+	return templ.Attributes{"hx-get": url}
+}
+
+// Post issues a POST request to the URL.
+func Post(url string) templ.Attributes {
+	return templ.Attributes{"hx-post": url}
+}
+
+// Put issues a PUT request to the URL.
+func Put(url string) templ.Attributes {
+	return templ.Attributes{"hx-put": url}
+}
+
+// Patch issues a PATCH request to the URL.
+func Patch(url string) templ.Attributes {
+	return templ.Attributes{"hx-patch": url}
+}
+
+// Delete issues a DELETE request to the URL.
+func Delete(url string) templ.Attributes {
+	return templ.Attributes{"hx-delete": url}
+}
+
+// Target sets the element that the response is swapped into, using a CSS selector or an
+// extended selector such as "closest tr".
+func Target(selector string) templ.Attributes {
+	return templ.Attributes{"hx-target": selector}
+}
+
+// Select selects the content of the response to swap in, using a CSS selector.
+func Select(selector string) templ.Attributes {
+	return templ.Attributes{"hx-select": selector}
+}
+
+// SwapStrategy is how the response is swapped into the target.
+type SwapStrategy string
+
+const (
+	SwapInnerHTML   SwapStrategy = "innerHTML"
+	SwapOuterHTML   SwapStrategy = "outerHTML"
+	SwapTextContent SwapStrategy = "textContent"
+	SwapBeforeBegin SwapStrategy = "beforebegin"
+	SwapAfterBegin  SwapStrategy = "afterbegin"
+	SwapBeforeEnd   SwapStrategy = "beforeend"
+	SwapAfterEnd    SwapStrategy = "afterend"
+	SwapDelete      SwapStrategy = "delete"
+	SwapNone        SwapStrategy = "none"
+)
+
+// Swap sets how the response is swapped into the target. Modifiers, such as
+// "transition:true" or "scroll:top", are appended to the strategy.
+func Swap(strategy SwapStrategy, modifiers ...string) templ.Attributes {
+	return templ.Attributes{"hx-swap": strings.Join(append([]string{string(strategy)}, modifiers...), " ")}
+}
+
+// SwapOOB marks the element to be swapped in out of band, replacing the element with the
+// same id, wherever it is in the page.
+func SwapOOB() templ.Attributes {
+	return templ.Attributes{"hx-swap-oob": "true"}
+}
+
+// SwapOOBWith marks the element to be swapped in out of band using the strategy, and
+// optionally a CSS selector of the target, e.g. SwapOOBWith(SwapBeforeEnd, "#messages").
+func SwapOOBWith(strategy SwapStrategy, selector string) templ.Attributes {
+	value := string(strategy)
+	if selector != "" {
+		value += ":" + selector
+	}
+	return templ.Attributes{"hx-swap-oob": value}
+}
+
+// Trigger sets the events that trigger the request, e.g. "click", "keyup changed delay:500ms".
+func Trigger(events ...string) templ.Attributes {
+	return templ.Attributes{"hx-trigger": strings.Join(events, ", ")}
+}
+
+// Confirm shows a confirmation dialog with the message before issuing the request.
+func Confirm(message string) templ.Attributes {
+	return templ.Attributes{"hx-confirm": message}
+}
+
+// Include includes the values of the elements matching the CSS selector in the request.
+func Include(selector string) templ.Attributes {
+	return templ.Attributes{"hx-include": selector}
+}
+
+// Indicator sets the element that has the htmx-request class added during the request.
+func Indicator(selector string) templ.Attributes {
+	return templ.Attributes{"hx-indicator": selector}
+}
+
+// PushURL pushes the URL into the browser history. Use "true" to push the request URL.
+func PushURL(url string) templ.Attributes {
+	return templ.Attributes{"hx-push-url": url}
+}
+
+// ReplaceURL replaces the current URL in the browser history. Use "true" to use the request URL.
+func ReplaceURL(url string) templ.Attributes {
+	return templ.Attributes{"hx-replace-url": url}
+}
+
+// Boost converts the links and forms within the element to use AJAX requests.
+func Boost(enabled bool) templ.Attributes {
+	return templ.Attributes{"hx-boost": strconv.FormatBool(enabled)}
+}
+
+// Vals adds values to the request. The values are JSON encoded.
+func Vals(values map[string]string) templ.Attributes {
+	return templ.Attributes{"hx-vals": jsonObject(values)}
+}
+
+// Headers adds headers to the request. The headers are JSON encoded.
+func Headers(headers map[string]string) templ.Attributes {
+	return templ.Attributes{"hx-headers": jsonObject(headers)}
+}
+
+func jsonObject(m map[string]string) string {
+	// Marshalling a map of strings can't fail.
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+// On handles an event with inline JavaScript, e.g. On("htmx:after-request", "this.reset()").
+func On(event, script string) templ.Attributes {
+	return templ.Attributes{"hx-on:" + event: script}
+}
+
+// Merge combines attributes into a single set of attributes. If more than one set of
+// attributes contains the same key, the last value is used.
+func Merge(attrs ...templ.Attributes) templ.Attributes {
+	merged := templ.Attributes{}
+	for _, a := range attrs {
+		for k, v := range a {
+			merged[k] = v
+		}
+	}
+	return merged
+}
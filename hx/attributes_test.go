@@ -0,0 +1,65 @@
+package hx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    templ.Attributes
+		expected templ.Attributes
+	}{
+		{name: "Get", input: Get("/contacts"), expected: templ.Attributes{"hx-get": "/contacts"}},
+		{name: "Post", input: Post("/contacts"), expected: templ.Attributes{"hx-post": "/contacts"}},
+		{name: "Put", input: Put("/contacts/1"), expected: templ.Attributes{"hx-put": "/contacts/1"}},
+		{name: "Patch", input: Patch("/contacts/1"), expected: templ.Attributes{"hx-patch": "/contacts/1"}},
+		{name: "Delete", input: Delete("/contacts/1"), expected: templ.Attributes{"hx-delete": "/contacts/1"}},
+		{name: "Target", input: Target("closest tr"), expected: templ.Attributes{"hx-target": "closest tr"}},
+		{name: "Select", input: Select("#content"), expected: templ.Attributes{"hx-select": "#content"}},
+		{name: "Swap", input: Swap(SwapOuterHTML), expected: templ.Attributes{"hx-swap": "outerHTML"}},
+		{name: "Swap with modifiers", input: Swap(SwapBeforeEnd, "transition:true", "scroll:bottom"), expected: templ.Attributes{"hx-swap": "beforeend transition:true scroll:bottom"}},
+		{name: "SwapOOB", input: SwapOOB(), expected: templ.Attributes{"hx-swap-oob": "true"}},
+		{name: "SwapOOBWith", input: SwapOOBWith(SwapBeforeEnd, "#messages"), expected: templ.Attributes{"hx-swap-oob": "beforeend:#messages"}},
+		{name: "SwapOOBWith without a selector", input: SwapOOBWith(SwapInnerHTML, ""), expected: templ.Attributes{"hx-swap-oob": "innerHTML"}},
+		{name: "Trigger", input: Trigger("click", "keyup changed delay:500ms"), expected: templ.Attributes{"hx-trigger": "click, keyup changed delay:500ms"}},
+		{name: "Confirm", input: Confirm("Are you sure?"), expected: templ.Attributes{"hx-confirm": "Are you sure?"}},
+		{name: "Include", input: Include("[name='email']"), expected: templ.Attributes{"hx-include": "[name='email']"}},
+		{name: "Indicator", input: Indicator("#spinner"), expected: templ.Attributes{"hx-indicator": "#spinner"}},
+		{name: "PushURL", input: PushURL("true"), expected: templ.Attributes{"hx-push-url": "true"}},
+		{name: "ReplaceURL", input: ReplaceURL("/contacts"), expected: templ.Attributes{"hx-replace-url": "/contacts"}},
+		{name: "Boost", input: Boost(false), expected: templ.Attributes{"hx-boost": "false"}},
+		{name: "Vals", input: Vals(map[string]string{"id": "1"}), expected: templ.Attributes{"hx-vals": `{"id":"1"}`}},
+		{name: "Headers", input: Headers(map[string]string{"X-CSRF-Token": "abc"}), expected: templ.Attributes{"hx-headers": `{"X-CSRF-Token":"abc"}`}},
+		{name: "On", input: On("htmx:after-request", "this.reset()"), expected: templ.Attributes{"hx-on:htmx:after-request": "this.reset()"}},
+		{
+			name:     "Merge uses the last value of duplicate keys",
+			input:    Merge(Get("/a"), Target("#a"), Get("/b")),
+			expected: templ.Attributes{"hx-get": "/b", "hx-target": "#a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, tt.input); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestAttributesRender(t *testing.T) {
+	var sb strings.Builder
+	attrs := Merge(Post("/contacts?a=1&b=2"), Target("#contacts"), Swap(SwapBeforeEnd))
+	if err := templ.RenderAttributes(context.Background(), &sb, attrs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := ` hx-post="/contacts?a=1&amp;b=2" hx-swap="beforeend" hx-target="#contacts"`
+	if sb.String() != expected {
+		t.Errorf("expected %q, got %q", expected, sb.String())
+	}
+}
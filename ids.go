@@ -0,0 +1,67 @@
+package templ
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// FieldIDs are matched element IDs for a form control and the elements that describe it, so
+// that they can be related with the for, aria-labelledby and aria-describedby attributes
+// without passing IDs between components.
+//
+//	{{ ids := templ.NewFieldIDs(ctx, "email") }}
+//	<label id={ ids.Label } for={ ids.Control }>Email</label>
+//	<input id={ ids.Control } aria-describedby={ ids.DescribedBy(err != nil) }/>
+//	<p id={ ids.Description }>We'll never share your email.</p>
+//	if err != nil {
+//		<p id={ ids.Error }>{ err.Error() }</p>
+//	}
+type FieldIDs struct {
+	// Control is the ID of the input, select or textarea element.
+	Control string
+	// Label is the ID of the label element.
+	Label string
+	// Description is the ID of the element that describes the control.
+	Description string
+	// Error is the ID of the element that contains the error message of the control.
+	Error string
+}
+
+// NewFieldIDs returns IDs that are unique within the render, e.g. email-1, email-1-label,
+// email-1-description and email-1-error for the prefix email. The IDs are numbered in the order
+// that they're created, so a component renders the same IDs each time the page is rendered.
+func NewFieldIDs(ctx context.Context, prefix string) FieldIDs {
+	id := NewID(ctx, prefix)
+	return FieldIDs{
+		Control:     id,
+		Label:       id + "-label",
+		Description: id + "-description",
+		Error:       id + "-error",
+	}
+}
+
+// DescribedBy returns the value of the aria-describedby attribute of the control, which lists
+// the description, followed by the error message if hasError is true.
+func (ids FieldIDs) DescribedBy(hasError bool) string {
+	if hasError {
+		return ids.Description + " " + ids.Error
+	}
+	return ids.Description
+}
+
+// NewID returns an element ID that is unique within the render, made from the prefix and the
+// number of IDs that have been created with the prefix, e.g. dialog-1, dialog-2. If prefix is
+// empty, "templ" is used. The ctx must be the context passed to the component being rendered.
+func NewID(ctx context.Context, prefix string) string {
+	_, v := getContext(ctx)
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		prefix = "templ"
+	}
+	if v.idCounts == nil {
+		v.idCounts = map[string]int{}
+	}
+	v.idCounts[prefix]++
+	return prefix + "-" + strconv.Itoa(v.idCounts[prefix])
+}
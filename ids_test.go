@@ -0,0 +1,51 @@
+package templ_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewFieldIDs(t *testing.T) {
+	field := func(name string, hasError bool) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			ids := templ.NewFieldIDs(ctx, name)
+			_, err := io.WriteString(w, `<label id="`+ids.Label+`" for="`+ids.Control+`"></label>`+
+				`<input id="`+ids.Control+`" aria-describedby="`+ids.DescribedBy(hasError)+`">`)
+			return err
+		})
+	}
+	c := templ.Join(field("email", false), field("email", true), field("name", false))
+
+	render := func() string {
+		var sb strings.Builder
+		if err := c.Render(templ.InitializeContext(context.Background()), &sb); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		return sb.String()
+	}
+	expected := `<label id="email-1-label" for="email-1"></label><input id="email-1" aria-describedby="email-1-description">` +
+		`<label id="email-2-label" for="email-2"></label><input id="email-2" aria-describedby="email-2-description email-2-error">` +
+		`<label id="name-1-label" for="name-1"></label><input id="name-1" aria-describedby="name-1-description">`
+	if diff := cmp.Diff(expected, render()); diff != "" {
+		t.Error(diff)
+	}
+	t.Run("IDs are the same each time the component is rendered", func(t *testing.T) {
+		if diff := cmp.Diff(expected, render()); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestNewID(t *testing.T) {
+	ctx := templ.InitializeContext(context.Background())
+	actual := []string{templ.NewID(ctx, "dialog"), templ.NewID(ctx, ""), templ.NewID(ctx, "dialog")}
+	expected := []string{"dialog-1", "templ-1", "dialog-2"}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
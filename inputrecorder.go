@@ -0,0 +1,249 @@
+package templ
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/a-h/templ/internal/funcname"
+)
+
+// InputRecording is a recording of the parameters and context values of a component render,
+// so that the render can be replayed locally, see InputRecorder.
+type InputRecording struct {
+	// Package is the import path of the package that contains the component.
+	Package string `json:"package"`
+	// Component name, e.g. "Card", or "Page.Render" for a method.
+	Component string `json:"component"`
+	// FileName of the template file.
+	FileName string `json:"fileName"`
+	// Time the component was rendered.
+	Time time.Time `json:"time"`
+	// Params of the component, including the receiver of a method, in declaration order.
+	Params []SnapshotParam `json:"params"`
+	// Nonce is the CSP nonce in the context, see WithNonce.
+	Nonce string `json:"nonce,omitempty"`
+	// Capabilities of the browser in the context, see WithCapability.
+	Capabilities map[string]bool `json:"capabilities,omitempty"`
+	// RequestHints in the context, see WithRequestHints.
+	RequestHints RequestHints `json:"requestHints"`
+}
+
+// Param unmarshals the value of the named parameter into v.
+func (r InputRecording) Param(name string, v any) error {
+	return ErrorSnapshot{Component: r.Component, Params: r.Params}.Param(name, v)
+}
+
+// Context returns a copy of ctx that contains the recorded context values.
+func (r InputRecording) Context(ctx context.Context) context.Context {
+	ctx = WithNonce(ctx, r.Nonce)
+	for name, supported := range r.Capabilities {
+		ctx = WithCapability(ctx, name, supported)
+	}
+	return WithRequestHints(ctx, r.RequestHints)
+}
+
+// Replay renders the component again with the recorded parameters and context values. The
+// component is created with the template registry, so the package that contains it must be
+// imported, and generated with the -template-registry flag, see LookupTemplate.
+func (r InputRecording) Replay(ctx context.Context, w io.Writer) error {
+	t, ok := LookupTemplate(r.Package, r.Component)
+	if !ok {
+		return fmt.Errorf("templ: %s.%s is not registered, generate it with -template-registry and import the package", r.Package, r.Component)
+	}
+	values := make(map[string]any, len(r.Params))
+	for _, p := range r.Params {
+		if p.Error != "" {
+			return fmt.Errorf("templ: parameter %q was not captured: %s", p.Name, p.Error)
+		}
+		// The generated code unmarshals the JSON into the type of the parameter.
+		values[p.Name] = p.Value
+	}
+	c, err := t.Bind(values)
+	if err != nil {
+		return err
+	}
+	return c.Render(r.Context(ctx), w)
+}
+
+// ReadInputRecordings reads the recordings written by InputRecorder.WriteTo.
+func ReadInputRecordings(r io.Reader) (recordings []InputRecording, err error) {
+	d := json.NewDecoder(bufio.NewReader(r))
+	for d.More() {
+		var rec InputRecording
+		if err = d.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("templ: failed to read input recording %d: %w", len(recordings)+1, err)
+		}
+		recordings = append(recordings, rec)
+	}
+	return recordings, nil
+}
+
+// DefaultInputRecordingLimit is the number of recordings kept by an InputRecorder that
+// doesn't have a limit.
+const DefaultInputRecordingLimit = 100
+
+// InputRecorder records the inputs of a sample of the component renders in production, so
+// that they can be exported and replayed locally, e.g. to reproduce a layout bug, see
+// WithInputRecorder.
+//
+// Only components generated with the -input-recording flag are recorded.
+type InputRecorder struct {
+	// Components to record, e.g. "Card", or "pages.Card" to include the package name. All
+	// components are recorded if it's empty.
+	Components []string
+	// SampleRate is the fraction of the renders of each component that are recorded, from 0
+	// to 1.
+	SampleRate float64
+	// Limit is the number of recordings that are kept, the oldest recordings are discarded.
+	// If it's zero, DefaultInputRecordingLimit is used.
+	Limit int
+
+	m          sync.Mutex
+	recordings []InputRecording
+}
+
+func (r *InputRecorder) sample(name string) bool {
+	if r.SampleRate <= 0 || (r.SampleRate < 1 && rand.Float64() >= r.SampleRate) {
+		return false
+	}
+	if len(r.Components) == 0 {
+		return true
+	}
+	_, component, _ := strings.Cut(name, ".")
+	for _, c := range r.Components {
+		if c == name || c == component {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *InputRecorder) add(rec InputRecording) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	limit := r.Limit
+	if limit <= 0 {
+		limit = DefaultInputRecordingLimit
+	}
+	if len(r.recordings) >= limit {
+		r.recordings = append(r.recordings[:0], r.recordings[len(r.recordings)-limit+1:]...)
+	}
+	r.recordings = append(r.recordings, rec)
+}
+
+// Recordings returns the recordings, oldest first.
+func (r *InputRecorder) Recordings() []InputRecording {
+	r.m.Lock()
+	defer r.m.Unlock()
+	recordings := make([]InputRecording, len(r.recordings))
+	copy(recordings, r.recordings)
+	return recordings
+}
+
+// WriteTo writes the recordings to w as JSON, one recording per line, see ReadInputRecordings.
+func (r *InputRecorder) WriteTo(w io.Writer) (n int64, err error) {
+	for _, rec := range r.Recordings() {
+		b, err := json.Marshal(rec)
+		if err != nil {
+			return n, err
+		}
+		written, err := w.Write(append(b, '\n'))
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ServeHTTP exports the recordings as a download, see WriteTo. Recordings contain the
+// parameters of components, so the handler should only be available to administrators.
+func (r *InputRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="templ-recordings.jsonl"`)
+	_, _ = r.WriteTo(w)
+}
+
+// inputRecorderUsed is set when a recorder is first added to a context, so that rendering
+// doesn't need to check the context for a recorder unless one has been used.
+var inputRecorderUsed atomic.Bool
+
+// WithInputRecorder adds a recorder to the context, that records the inputs of a sample of
+// the components rendered with the context.
+func WithInputRecorder(ctx context.Context, r *InputRecorder) context.Context {
+	inputRecorderUsed.Store(true)
+	ctx, v := getContext(ctx)
+	v.inputRecorder = r
+	return ctx
+}
+
+// GetInputRecorder returns the recorder in the context, or nil if there isn't one.
+func GetInputRecorder(ctx context.Context) *InputRecorder {
+	if !inputRecorderUsed.Load() {
+		return nil
+	}
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	if !ok {
+		return nil
+	}
+	return v.inputRecorder
+}
+
+// RecordInputs is used by generated code to record the inputs of a component, if it's
+// sampled by the recorder in the context. name is the package qualified name of the
+// component, e.g. "pages.Card", and params are pairs of parameter names and values.
+func RecordInputs(ctx context.Context, name, fileName string, params ...any) {
+	r := GetInputRecorder(ctx)
+	if r == nil || !r.sample(name) {
+		return
+	}
+	_, component, _ := strings.Cut(name, ".")
+	rec := InputRecording{
+		Package:   callerPackage(),
+		Component: component,
+		FileName:  fileName,
+		Time:      time.Now(),
+		Params:    make([]SnapshotParam, 0, len(params)/2),
+	}
+	for i := 0; i+1 < len(params); i += 2 {
+		p := SnapshotParam{Name: fmt.Sprint(params[i])}
+		var jsonErr error
+		if p.Value, jsonErr = json.Marshal(params[i+1]); jsonErr != nil {
+			p.Error = jsonErr.Error()
+		}
+		rec.Params = append(rec.Params, p)
+	}
+	v := ctx.Value(contextKey).(*contextValue)
+	rec.Nonce = v.nonce
+	if len(v.capabilities) > 0 {
+		rec.Capabilities = make(map[string]bool, len(v.capabilities))
+		for name, supported := range v.capabilities {
+			rec.Capabilities[name] = supported
+		}
+	}
+	rec.RequestHints = v.requestHints
+	r.add(rec)
+}
+
+// callerPackage returns the import path of the package of the caller of RecordInputs.
+func callerPackage() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	return funcname.Package(fn.Name())
+}
@@ -0,0 +1,198 @@
+package templ_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	templruntime "github.com/a-h/templ/runtime"
+	"github.com/google/go-cmp/cmp"
+)
+
+type recordedItem struct {
+	Name  string
+	Price int
+}
+
+// recordedCard is rendered like generated code with the -input-recording flag.
+func recordedCard(title string, items []recordedItem) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		templ.RecordInputs(ctx, "templ_test.recordedCard", "card.templ", "title", title, "items", items)
+		hints := templ.GetRequestHints(ctx)
+		_, err := fmt.Fprintf(w, "<h1 nonce=%q>%s</h1><p>%d items, %s</p>", templ.GetNonce(ctx), title, len(items), hints.Locale())
+		return err
+	})
+}
+
+func init() {
+	templ.RegisterTemplate(templ.TemplateInfo{
+		Package: "github.com/a-h/templ_test",
+		Name:    "recordedCard",
+		Params:  []templ.TemplateParam{{Name: "title", Type: "string"}, {Name: "items", Type: "[]recordedItem"}},
+		New: func(args ...any) (templ.Component, error) {
+			title, err := templruntime.Arg[string]("recordedCard", "title", args[0])
+			if err != nil {
+				return nil, err
+			}
+			items, err := templruntime.Arg[[]recordedItem]("recordedCard", "items", args[1])
+			if err != nil {
+				return nil, err
+			}
+			return recordedCard(title, items), nil
+		},
+	})
+}
+
+func TestInputRecorder(t *testing.T) {
+	items := []recordedItem{{Name: "Apple", Price: 30}, {Name: "Pear", Price: 45}}
+
+	t.Run("renders without a recorder are not recorded", func(t *testing.T) {
+		if err := recordedCard("Fruit", items).Render(context.Background(), io.Discard); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	t.Run("the parameters and context values of sampled renders are recorded", func(t *testing.T) {
+		r := &templ.InputRecorder{SampleRate: 1}
+		ctx := templ.WithInputRecorder(context.Background(), r)
+		ctx = templ.WithNonce(ctx, "abc")
+		ctx = templ.WithCapability(ctx, "popover", true)
+		ctx = templ.WithRequestHints(ctx, templ.RequestHints{Languages: []string{"fr"}})
+		if err := recordedCard("Fruit", items).Render(ctx, io.Discard); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		recordings := r.Recordings()
+		if len(recordings) != 1 {
+			t.Fatalf("expected 1 recording, got %d", len(recordings))
+		}
+		rec := recordings[0]
+		if rec.Package != "github.com/a-h/templ_test" || rec.Component != "recordedCard" || rec.FileName != "card.templ" {
+			t.Errorf("unexpected component: %s.%s in %s", rec.Package, rec.Component, rec.FileName)
+		}
+		if rec.Time.IsZero() {
+			t.Error("expected the time to be recorded")
+		}
+		var actualItems []recordedItem
+		if err := rec.Param("items", &actualItems); err != nil {
+			t.Fatalf("failed to read items: %v", err)
+		}
+		if diff := cmp.Diff(items, actualItems); diff != "" {
+			t.Error(diff)
+		}
+		if rec.Nonce != "abc" || !rec.Capabilities["popover"] || rec.RequestHints.Locale() != "fr" {
+			t.Errorf("unexpected context values: %+v", rec)
+		}
+	})
+	t.Run("renders are not recorded if the sample rate is zero", func(t *testing.T) {
+		r := &templ.InputRecorder{}
+		ctx := templ.WithInputRecorder(context.Background(), r)
+		if err := recordedCard("Fruit", items).Render(ctx, io.Discard); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := len(r.Recordings()); n != 0 {
+			t.Errorf("expected no recordings, got %d", n)
+		}
+	})
+	t.Run("only the selected components are recorded", func(t *testing.T) {
+		for _, tt := range []struct {
+			components []string
+			expected   int
+		}{
+			{components: []string{"recordedCard"}, expected: 1},
+			{components: []string{"templ_test.recordedCard"}, expected: 1},
+			{components: []string{"other.recordedCard"}, expected: 0},
+			{components: []string{"Page"}, expected: 0},
+		} {
+			r := &templ.InputRecorder{Components: tt.components, SampleRate: 1}
+			ctx := templ.WithInputRecorder(context.Background(), r)
+			if err := recordedCard("Fruit", items).Render(ctx, io.Discard); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n := len(r.Recordings()); n != tt.expected {
+				t.Errorf("%v: expected %d recordings, got %d", tt.components, tt.expected, n)
+			}
+		}
+	})
+	t.Run("the oldest recordings are discarded when the limit is reached", func(t *testing.T) {
+		r := &templ.InputRecorder{SampleRate: 1, Limit: 2}
+		ctx := templ.WithInputRecorder(context.Background(), r)
+		for _, title := range []string{"A", "B", "C"} {
+			if err := recordedCard(title, nil).Render(ctx, io.Discard); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		var titles []string
+		for _, rec := range r.Recordings() {
+			var title string
+			if err := rec.Param("title", &title); err != nil {
+				t.Fatalf("failed to read title: %v", err)
+			}
+			titles = append(titles, title)
+		}
+		if diff := cmp.Diff([]string{"B", "C"}, titles); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestInputRecordingReplay(t *testing.T) {
+	r := &templ.InputRecorder{SampleRate: 1}
+	ctx := templ.WithInputRecorder(context.Background(), r)
+	ctx = templ.WithNonce(ctx, "abc")
+	ctx = templ.WithRequestHints(ctx, templ.RequestHints{Languages: []string{"fr"}})
+	var expected bytes.Buffer
+	if err := recordedCard("Fruit", []recordedItem{{Name: "Apple", Price: 30}}).Render(ctx, &expected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Export the recordings, as an administrator would.
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/recordings", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	recordings, err := templ.ReadInputRecordings(w.Body)
+	if err != nil {
+		t.Fatalf("failed to read recordings: %v", err)
+	}
+	if len(recordings) != 1 {
+		t.Fatalf("expected 1 recording, got %d", len(recordings))
+	}
+
+	var actual bytes.Buffer
+	if err := recordings[0].Replay(context.Background(), &actual); err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if diff := cmp.Diff(expected.String(), actual.String()); diff != "" {
+		t.Error(diff)
+	}
+
+	t.Run("unregistered components return an error", func(t *testing.T) {
+		rec := templ.InputRecording{Package: "example.com/missing", Component: "Card"}
+		err := rec.Replay(context.Background(), io.Discard)
+		if err == nil || !strings.Contains(err.Error(), "example.com/missing.Card is not registered") {
+			t.Errorf("expected registration error, got %v", err)
+		}
+	})
+	t.Run("parameters that weren't captured return an error", func(t *testing.T) {
+		rec := recordings[0]
+		rec.Params = []templ.SnapshotParam{{Name: "title", Error: "json: unsupported type"}}
+		err := rec.Replay(context.Background(), io.Discard)
+		if err == nil || !strings.Contains(err.Error(), `parameter "title" was not captured`) {
+			t.Errorf("expected capture error, got %v", err)
+		}
+	})
+	t.Run("parameters of the wrong type return an error", func(t *testing.T) {
+		rec := recordings[0]
+		rec.Params = []templ.SnapshotParam{{Name: "title", Value: json.RawMessage(`123`)}}
+		err := rec.Replay(context.Background(), io.Discard)
+		if err == nil || !strings.Contains(err.Error(), `recordedCard parameter "title"`) {
+			t.Errorf("expected type error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+// Package cssid calculates the class names of CSS templates, so that the names calculated by
+// the generator are the same as the names calculated at runtime.
+package cssid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Calculate returns the name, followed by an underscore and the first 8 characters of the hex
+// encoded SHA-256 hash of the CSS, so the same CSS always results in the same ID.
+func Calculate(name string, css string) string {
+	sum := sha256.Sum256([]byte(css))
+	hs := hex.EncodeToString(sum[:])[0:8] // NOTE: See issue #978. Minimum recommended hs length is 6.
+	// Benchmarking showed this was fastest, and with fewest allocations (1).
+	// Using strings.Builder (2 allocs).
+	// Using fmt.Sprintf (3 allocs).
+	return name + "_" + hs
+}
@@ -0,0 +1,23 @@
+// Package funcname parses the names of functions returned by runtime.FuncForPC, so that the
+// runtime and the input recorder agree on the package of generated code.
+package funcname
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Package returns the import path of the package of the function with the name, e.g.
+// "github.com/example/app/pages" for "github.com/example/app/pages.Card.func1". Dots in the
+// last element of the import path are escaped in function names, e.g. "gopkg.in/yaml%2ev3".
+func Package(name string) string {
+	dir := ""
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		dir, name = name[:i+1], name[i+1:]
+	}
+	pkg, _, _ := strings.Cut(name, ".")
+	if unescaped, err := url.PathUnescape(pkg); err == nil {
+		pkg = unescaped
+	}
+	return dir + pkg
+}
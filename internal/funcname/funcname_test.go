@@ -0,0 +1,23 @@
+package funcname
+
+import "testing"
+
+func TestPackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "github.com/example/app/pages.Card.func1", expected: "github.com/example/app/pages"},
+		{name: "github.com/example/app/pages.init.0", expected: "github.com/example/app/pages"},
+		{name: "gopkg.in/example%2ev3.Card", expected: "gopkg.in/example.v3"},
+		{name: "gopkg.in/example%2ev3.init.0", expected: "gopkg.in/example.v3"},
+		{name: "main.Card", expected: "main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Package(tt.name); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
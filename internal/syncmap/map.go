@@ -27,6 +27,17 @@ func (m *Map[K, V]) Set(key K, value V) {
 	m.m[key] = value
 }
 
+// Keys returns the keys of the map, in no particular order.
+func (m *Map[K, V]) Keys() []K {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]K, 0, len(m.m))
+	for k := range m.m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 func (m *Map[K, V]) Delete(key K) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -18,6 +18,18 @@ func TestMap(t *testing.T) {
 			t.Error("Expected key 'key1' to be deleted")
 		}
 	})
+	t.Run("Can list keys", func(t *testing.T) {
+		m := New[string, int]()
+		m.Set("key1", 1)
+		m.Set("key2", 2)
+		keys := m.Keys()
+		if len(keys) != 2 {
+			t.Fatalf("Expected 2 keys, got %v", keys)
+		}
+		if keys[0] == keys[1] {
+			t.Errorf("Expected distinct keys, got %v", keys)
+		}
+	})
 	t.Run("CompareAndSwap", func(t *testing.T) {
 		t.Run("Swaps if condition is met", func(t *testing.T) {
 			m := New[string, int]()
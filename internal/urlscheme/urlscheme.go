@@ -0,0 +1,25 @@
+// Package urlscheme contains the URL schemes that templ.URL accepts, so that the runtime and
+// the diagnostics of constant URL attributes agree.
+package urlscheme
+
+import "strings"
+
+// Safe are the schemes that templ.URL accepts.
+var Safe = []string{"http", "https", "mailto", "tel", "ftp", "ftps"}
+
+// IsSafe returns true if the scheme is one of the Safe schemes, or one of the allowed schemes.
+// Schemes are compared without regard to case, and allowed schemes may end with a colon,
+// e.g. "sms:".
+func IsSafe(scheme string, allow ...string) bool {
+	for _, s := range Safe {
+		if strings.EqualFold(scheme, s) {
+			return true
+		}
+	}
+	for _, s := range allow {
+		if strings.EqualFold(scheme, strings.TrimSuffix(s, ":")) {
+			return true
+		}
+	}
+	return false
+}
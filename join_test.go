@@ -51,6 +51,11 @@ func TestJoin(t *testing.T) {
 			input:          []templ.Component{hello, world},
 			expectedOutput: "HelloWorld",
 		},
+		{
+			name:           "nil components are skipped",
+			input:          []templ.Component{hello, nil, world},
+			expectedOutput: "HelloWorld",
+		},
 		{
 			name:           "components are rendered in order, and errors returned",
 			input:          []templ.Component{hello, err},
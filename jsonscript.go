@@ -11,6 +11,9 @@ var _ Component = JSONScriptElement{}
 
 // JSONScript renders a JSON object inside a script element.
 // e.g. <script type="application/json">{"foo":"bar"}</script>
+//
+// The <, > and & characters in the JSON are written as Unicode escape sequences, so that
+// strings in the data can't end the script element, e.g. with "</script>".
 func JSONScript(id string, data any) JSONScriptElement {
 	return JSONScriptElement{
 		ID:    id,
@@ -43,6 +43,11 @@ func TestJSONScriptElement(t *testing.T) {
 			e:        templ.JSONScript("idt", data).WithType("application/ld+json"),
 			expected: "<script id=\"idt\" type=\"application/ld+json\">{\"foo\":\"bar\"}\n</script>",
 		},
+		{
+			name:     "data that could end the script element is escaped",
+			e:        templ.JSONScript("ide", map[string]any{"html": "</script><!-- & \u2028"}),
+			expected: "<script id=\"ide\" type=\"application/json\">{\"html\":\"\\u003c/script\\u003e\\u003c!-- \\u0026 \\u2028\"}\n</script>",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,62 @@
+package templ
+
+import (
+	"context"
+	"io"
+)
+
+// The functions in this file were called by code generated by templ v0.1.x (v1 syntax).
+// They're implemented using the current runtime, so that packages containing v1 generated
+// code can be built against the current version of templ while they're migrated, without
+// regenerating all of them at once.
+
+// InitializeRenderedItemsContext initializes the context used to track the CSS classes and
+// scripts that have been rendered.
+//
+// Deprecated: Use InitializeContext. Regenerate the code with the current version of templ.
+func InitializeRenderedItemsContext(ctx context.Context) context.Context {
+	return InitializeContext(ctx)
+}
+
+// RenderedCSSClassesFromContext initializes the context used to track the CSS classes that
+// have been rendered, and reports whether it was already initialized.
+//
+// Deprecated: Use InitializeContext. Regenerate the code with the current version of templ.
+func RenderedCSSClassesFromContext(ctx context.Context) (context.Context, bool) {
+	return initializeLegacyContext(ctx)
+}
+
+// RenderedScriptsFromContext initializes the context used to track the scripts that have
+// been rendered, and reports whether it was already initialized.
+//
+// Deprecated: Use InitializeContext. Regenerate the code with the current version of templ.
+func RenderedScriptsFromContext(ctx context.Context) (context.Context, bool) {
+	return initializeLegacyContext(ctx)
+}
+
+func initializeLegacyContext(ctx context.Context) (context.Context, bool) {
+	if _, ok := ctx.Value(contextKey).(*contextValue); ok {
+		return ctx, true
+	}
+	return InitializeContext(ctx), false
+}
+
+// RenderCSS renders a <style> element containing the CSS classes that haven't already been
+// rendered.
+//
+// Deprecated: Use RenderCSSItems. Regenerate the code with the current version of templ.
+func RenderCSS(ctx context.Context, w io.Writer, classes []CSSClass) (err error) {
+	items := make([]any, len(classes))
+	for i, c := range classes {
+		items[i] = c
+	}
+	return RenderCSSItems(ctx, w, items...)
+}
+
+// RenderScripts renders a <script> element containing the scripts that haven't already been
+// rendered.
+//
+// Deprecated: Use RenderScriptItems. Regenerate the code with the current version of templ.
+func RenderScripts(ctx context.Context, w io.Writer, scripts ...ComponentScript) (err error) {
+	return RenderScriptItems(ctx, w, scripts...)
+}
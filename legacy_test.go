@@ -0,0 +1,67 @@
+package templ_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+// legacyComponent is written in the style of code generated by templ v0.1.x.
+func legacyComponent() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		ctx, _ = templ.RenderedCSSClassesFromContext(ctx)
+		ctx, _ = templ.RenderedScriptsFromContext(ctx)
+		err = templ.RenderCSS(ctx, w, []templ.CSSClass{templ.ComponentCSSClass{ID: "red", Class: templ.SafeCSS(".red{color:red;}")}})
+		if err != nil {
+			return err
+		}
+		err = templ.RenderScripts(ctx, w, templ.ComponentScript{Name: "s1", Function: "function s1() {}"})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, `<div class="red">`+templ.EscapeString("<legacy>")+`</div>`)
+		return err
+	})
+}
+
+func TestLegacyGeneratedCode(t *testing.T) {
+	ctx := templ.InitializeRenderedItemsContext(context.Background())
+	if _, initialized := templ.RenderedCSSClassesFromContext(ctx); !initialized {
+		t.Error("expected the context to be initialized")
+	}
+
+	var buf bytes.Buffer
+	if err := legacyComponent().Render(ctx, &buf); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if err := legacyComponent().Render(ctx, &buf); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	expected := `<style type="text/css">.red{color:red;}</style><script>function s1() {}</script><div class="red">&lt;legacy&gt;</div>` +
+		`<div class="red">&lt;legacy&gt;</div>`
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLegacyGeneratedCodeInCurrentComponent(t *testing.T) {
+	// Legacy components share the CSS classes rendered by current components.
+	c := templ.Join(
+		templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return templ.RenderCSSItems(ctx, w, templ.ComponentCSSClass{ID: "red", Class: templ.SafeCSS(".red{color:red;}")})
+		}),
+		legacyComponent(),
+	)
+	var buf bytes.Buffer
+	if err := c.Render(templ.InitializeContext(context.Background()), &buf); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	expected := `<style type="text/css">.red{color:red;}</style><script>function s1() {}</script><div class="red">&lt;legacy&gt;</div>`
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Error(diff)
+	}
+}
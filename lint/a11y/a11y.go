@@ -0,0 +1,246 @@
+// Package a11y checks templ files for common accessibility problems, e.g. images without
+// alternative text, so that they're found when templates are generated, or in the editor.
+//
+//	tf, err := parser.Parse("page.templ")
+//	if err != nil {
+//		return err
+//	}
+//	for _, d := range a11y.Lint(tf) {
+//		fmt.Printf("page.templ:%d:%d: %s\n", d.Range.From.Line+1, d.Range.From.Col+1, d.Message)
+//	}
+//
+// Only constant attributes are checked. Attributes that may be set by expressions, e.g.
+// { attrs... }, are assumed to be correct, so that there are no false positives.
+package a11y
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/a-h/templ/parser/v2/visitor"
+)
+
+// Lint returns a diagnostic for each accessibility problem found in the templates of the file.
+//
+// The following problems are reported:
+//
+//   - <img> elements without an alt attribute.
+//   - <button> elements without text, an aria-label, aria-labelledby or title attribute.
+//   - tabindex attributes that aren't integers, or are greater than 0.
+//   - <label for> attributes that don't match the id of an element in the file, or match an
+//     element that isn't a form control. Labels aren't checked if ids are set by expressions,
+//     or the file renders other components, since they may render the element.
+func Lint(tf *parser.TemplateFile) (diags []parser.Diagnostic) {
+	var labels []*parser.Element
+	ids := map[string]*parser.Element{}
+	// dynamicIDs is true if an id may be set by an expression, or rendered by another component,
+	// so that labels can't be checked.
+	var dynamicIDs bool
+	v := visitor.New()
+	visitElement := v.Element
+	v.Element = func(n *parser.Element) error {
+		attrs := collectAttributes(n.Attributes)
+		diags = append(diags, lintElement(n, attrs)...)
+		if id, ok := attrs.constant["id"]; ok {
+			ids[id.value] = n
+		}
+		if attrs.dynamic || attrs.expressions["id"] {
+			dynamicIDs = true
+		}
+		if strings.EqualFold(n.Name, "label") {
+			labels = append(labels, n)
+		}
+		return visitElement(n)
+	}
+	visitTemplElement := v.TemplElementExpression
+	v.TemplElementExpression = func(n *parser.TemplElementExpression) error {
+		dynamicIDs = true
+		return visitTemplElement(n)
+	}
+	v.CallTemplateExpression = func(n *parser.CallTemplateExpression) error {
+		dynamicIDs = true
+		return nil
+	}
+	v.ChildrenExpression = func(n *parser.ChildrenExpression) error {
+		dynamicIDs = true
+		return nil
+	}
+	v.SlotExpression = func(n *parser.SlotExpression) error {
+		dynamicIDs = true
+		return nil
+	}
+	_ = tf.Visit(v)
+	if dynamicIDs {
+		return diags
+	}
+	for _, label := range labels {
+		f, ok := collectAttributes(label.Attributes).constant["for"]
+		if !ok {
+			continue
+		}
+		target, ok := ids[f.value]
+		if !ok {
+			diags = append(diags, parser.Diagnostic{
+				Message: fmt.Sprintf("<label for=%q> doesn't match the id of an element", f.value),
+				Range:   f.r,
+			})
+			continue
+		}
+		if !labelable[strings.ToLower(target.Name)] {
+			diags = append(diags, parser.Diagnostic{
+				Message: fmt.Sprintf("<label for=%q> refers to a <%s> element, which isn't a form control", f.value, target.Name),
+				Range:   f.r,
+			})
+		}
+	}
+	return diags
+}
+
+// labelable elements can be associated with a <label>.
+var labelable = map[string]bool{
+	"button":   true,
+	"input":    true,
+	"meter":    true,
+	"output":   true,
+	"progress": true,
+	"select":   true,
+	"textarea": true,
+}
+
+func lintElement(n *parser.Element, attrs attributes) (diags []parser.Diagnostic) {
+	name := strings.ToLower(n.Name)
+	if name == "img" && !attrs.has("alt") {
+		diags = append(diags, parser.Diagnostic{
+			Message: `<img> elements must have an alt attribute, use alt="" for decorative images`,
+			Range:   n.NameRange,
+		})
+	}
+	if name == "button" && !attrs.has("aria-label") && !attrs.has("aria-labelledby") && !attrs.has("title") && !hasText(n.Children) {
+		diags = append(diags, parser.Diagnostic{
+			Message: "<button> elements must have an accessible name, add text, or an aria-label attribute",
+			Range:   n.NameRange,
+		})
+	}
+	if tabindex, ok := attrs.constant["tabindex"]; ok {
+		i, err := strconv.Atoi(strings.TrimSpace(tabindex.value))
+		if err != nil {
+			diags = append(diags, parser.Diagnostic{
+				Message: fmt.Sprintf("tabindex must be an integer, got %q", tabindex.value),
+				Range:   tabindex.r,
+			})
+		} else if i > 0 {
+			diags = append(diags, parser.Diagnostic{
+				Message: "avoid tabindex values greater than 0, which change the tab order of the page, use 0 or -1",
+				Range:   tabindex.r,
+			})
+		}
+	}
+	return diags
+}
+
+// hasText returns true if the nodes may render text that can be used as an accessible name.
+func hasText(nodes []parser.Node) bool {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *parser.Whitespace, *parser.HTMLComment, *parser.GoComment:
+			continue
+		case *parser.Text:
+			if strings.TrimSpace(n.Value) != "" {
+				return true
+			}
+		case *parser.TextBlock:
+			if strings.TrimSpace(n.Value) != "" {
+				return true
+			}
+		case *parser.Element:
+			attrs := collectAttributes(n.Attributes)
+			if hidden, ok := attrs.constant["aria-hidden"]; ok && hidden.value == "true" {
+				continue
+			}
+			if strings.EqualFold(n.Name, "img") {
+				if alt, ok := attrs.constant["alt"]; !ok || strings.TrimSpace(alt.value) != "" {
+					return true
+				}
+				continue
+			}
+			if attrs.has("aria-label") || hasText(n.Children) {
+				return true
+			}
+		case *parser.RawElement, *parser.ScriptElement:
+			continue
+		default:
+			// Expressions, components and statements may render text.
+			return true
+		}
+	}
+	return false
+}
+
+type attribute struct {
+	value string
+	r     parser.Range
+}
+
+// attributes of an element, by lower case name.
+type attributes struct {
+	// constant attributes, including boolean attributes, which have an empty value.
+	constant map[string]attribute
+	// expressions are attributes with constant names, and values set by expressions.
+	expressions map[string]bool
+	// dynamic is true if attributes may be added by spread attributes, or attributes with
+	// expression keys.
+	dynamic bool
+}
+
+// has returns true if the attribute is set, or may be set.
+func (a attributes) has(name string) bool {
+	_, ok := a.constant[name]
+	return ok || a.expressions[name] || a.dynamic
+}
+
+// collectAttributes returns the attributes of an element. Attributes set in either branch of
+// a conditional attribute are included.
+func collectAttributes(attrs []parser.Attribute) (a attributes) {
+	a.constant = map[string]attribute{}
+	a.expressions = map[string]bool{}
+	var collect func(attrs []parser.Attribute)
+	collect = func(attrs []parser.Attribute) {
+		for _, attr := range attrs {
+			switch attr := attr.(type) {
+			case *parser.ConstantAttribute:
+				if k, ok := attr.Key.(parser.ConstantAttributeKey); ok {
+					a.constant[strings.ToLower(k.Name)] = attribute{value: attr.Value, r: k.NameRange}
+					continue
+				}
+				a.dynamic = true
+			case *parser.BoolConstantAttribute:
+				if k, ok := attr.Key.(parser.ConstantAttributeKey); ok {
+					a.constant[strings.ToLower(k.Name)] = attribute{r: k.NameRange}
+					continue
+				}
+				a.dynamic = true
+			case *parser.ExpressionAttribute:
+				if k, ok := attr.Key.(parser.ConstantAttributeKey); ok {
+					a.expressions[strings.ToLower(k.Name)] = true
+					continue
+				}
+				a.dynamic = true
+			case *parser.BoolExpressionAttribute:
+				if k, ok := attr.Key.(parser.ConstantAttributeKey); ok {
+					a.expressions[strings.ToLower(k.Name)] = true
+					continue
+				}
+				a.dynamic = true
+			case *parser.SpreadAttributes:
+				a.dynamic = true
+			case *parser.ConditionalAttribute:
+				collect(attr.Then)
+				collect(attr.Else)
+			}
+		}
+	}
+	collect(attrs)
+	return a
+}
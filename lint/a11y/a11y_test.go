@@ -0,0 +1,100 @@
+package a11y
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name: "images without alt attributes are reported",
+			input: `<img src="a.png"/>
+<img src="b.png" alt=""/>
+<img src="c.png" alt={ caption }/>
+<img src="d.png" { attrs... }/>`,
+			expected: []string{`4:2: <img> elements must have an alt attribute, use alt="" for decorative images`},
+		},
+		{
+			name:  "alt attributes in conditional attributes are found",
+			input: `<img src="a.png" if decorative { alt="" } else { alt="Chart" }/>`,
+		},
+		{
+			name: "buttons without an accessible name are reported",
+			input: `<button><svg class="icon"></svg></button>
+<button><span aria-hidden="true">×</span></button>
+<button><img src="x.png" alt=""/></button>
+<button>Save</button>
+<button aria-label="Close"><svg></svg></button>
+<button>{ label }</button>
+<button>@icon("save")</button>
+<button><img src="x.png" alt="Save"/></button>`,
+			expected: []string{
+				"4:2: <button> elements must have an accessible name, add text, or an aria-label attribute",
+				"5:2: <button> elements must have an accessible name, add text, or an aria-label attribute",
+				"6:2: <button> elements must have an accessible name, add text, or an aria-label attribute",
+			},
+		},
+		{
+			name: "positive and invalid tabindex values are reported",
+			input: `<div tabindex="0"></div>
+<div tabindex="-1"></div>
+<div tabindex="2"></div>
+<div tabindex="first"></div>`,
+			expected: []string{
+				"6:6: avoid tabindex values greater than 0, which change the tab order of the page, use 0 or -1",
+				`7:6: tabindex must be an integer, got "first"`,
+			},
+		},
+		{
+			name: "labels that don't match a form control are reported",
+			input: `<label for="email">Email</label>
+<input id="email" type="email"/>
+<label for="name">Name</label>
+<input id="nmae" type="text"/>
+<label for="title">Title</label>
+<h1 id="title">Title</h1>`,
+			expected: []string{
+				`6:8: <label for="name"> doesn't match the id of an element`,
+				`8:8: <label for="title"> refers to a <h1> element, which isn't a form control`,
+			},
+		},
+		{
+			name: "labels aren't checked if ids are set by expressions",
+			input: `<label for="name">Name</label>
+<input id={ id } type="text"/>`,
+		},
+		{
+			name: "labels aren't checked if other components are rendered",
+			input: `<label for="name">Name</label>
+@textInput("name")`,
+		},
+		{
+			name: "labels aren't checked if components are called",
+			input: `<label for="name">Name</label>
+{! textInput("name") }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString("package main\n\ntempl Page() {\n" + tt.input + "\n}\n")
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			var actual []string
+			for _, d := range Lint(tf) {
+				actual = append(actual, fmt.Sprintf("%d:%d: %s", d.Range.From.Line+1, d.Range.From.Col+1, d.Message))
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Package lint runs rules over parsed templ files, e.g. the accessibility checks of the a11y
+// package, or house rules such as banning inline styles. It's used by the -lint flag of the
+// generate and lsp commands.
+//
+// Rules are registered by name. Custom rules can be registered with Register, and run by
+// building a command that calls generatecmd.Run, so that they're available to -lint.
+//
+//	func main() {
+//		lint.Register(lint.BannedElements("no-iframes", lint.SeverityError, "iframe"))
+//		if err := generatecmd.Run(context.Background(), os.Stdout, os.Stderr, os.Args[1:]); err != nil {
+//			os.Exit(1)
+//		}
+//	}
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ/lint/a11y"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// Severity of a problem found by a rule. The values match the diagnostic severities of the
+// language server protocol.
+type Severity int
+
+const (
+	// SeverityError problems fail templ generate.
+	SeverityError Severity = iota + 1
+	// SeverityWarning problems are logged by templ generate.
+	SeverityWarning
+	// SeverityInfo problems are logged by templ generate as information.
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity parses "error", "warning" or "info".
+func ParseSeverity(s string) (Severity, error) {
+	switch s {
+	case "error":
+		return SeverityError, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "info":
+		return SeverityInfo, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q, expected one of error, warning, info", s)
+}
+
+// Rule checks a templ file.
+type Rule interface {
+	// Name of the rule, e.g. "no-inline-styles", used to select it with the -lint flag.
+	Name() string
+	// Severity of the problems found by the rule, unless it's overridden, see New.
+	Severity() Severity
+	// Check returns a diagnostic for each problem found in the file, with the range of the
+	// templ source that caused it.
+	Check(tf *parser.TemplateFile) []parser.Diagnostic
+}
+
+// NewRule returns a rule that calls check.
+func NewRule(name string, severity Severity, check func(tf *parser.TemplateFile) []parser.Diagnostic) Rule {
+	return funcRule{name: name, severity: severity, check: check}
+}
+
+type funcRule struct {
+	name     string
+	severity Severity
+	check    func(tf *parser.TemplateFile) []parser.Diagnostic
+}
+
+func (r funcRule) Name() string                                      { return r.name }
+func (r funcRule) Severity() Severity                                { return r.severity }
+func (r funcRule) Check(tf *parser.TemplateFile) []parser.Diagnostic { return r.check(tf) }
+
+// Diagnostic is a problem found by a rule.
+type Diagnostic struct {
+	parser.Diagnostic
+	// Rule is the name of the rule that found the problem.
+	Rule     string
+	Severity Severity
+}
+
+// Linter checks a templ file with the rules selected by New.
+type Linter func(tf *parser.TemplateFile) []Diagnostic
+
+var (
+	rulesMutex sync.RWMutex
+	rules      = map[string]Rule{}
+)
+
+func init() {
+	Register(NewRule("a11y", SeverityWarning, a11y.Lint))
+	Register(NoInlineStyles("no-inline-styles", SeverityWarning))
+	Register(RequiredAttribute("img-lazy-loading", SeverityWarning, "img", "loading", "lazy"))
+	Register(BannedElements("obsolete-elements", SeverityWarning, "blink", "center", "font", "marquee"))
+}
+
+// Register makes a rule available to New. It panics if a rule with the same name has
+// already been registered.
+func Register(r Rule) {
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+	if _, ok := rules[r.Name()]; ok {
+		panic(fmt.Sprintf("lint: rule %q is already registered", r.Name()))
+	}
+	rules[r.Name()] = r
+}
+
+// Names returns the names of the registered rules, in alphabetical order.
+func Names() (names []string) {
+	rulesMutex.RLock()
+	defer rulesMutex.RUnlock()
+	for name := range rules {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// New returns a linter that runs the named rules, e.g. "a11y". The severity of a rule can
+// be overridden by adding it after a colon, e.g. "no-inline-styles:error". An error is
+// returned if a name or severity isn't known.
+func New(specs ...string) (Linter, error) {
+	type selectedRule struct {
+		rule     Rule
+		severity Severity
+	}
+	var selected []selectedRule
+	for _, spec := range specs {
+		name, severityName, hasSeverity := strings.Cut(spec, ":")
+		rulesMutex.RLock()
+		r, ok := rules[name]
+		rulesMutex.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown linter %q, expected one of %s", name, strings.Join(Names(), ", "))
+		}
+		severity := r.Severity()
+		if hasSeverity {
+			var err error
+			if severity, err = ParseSeverity(severityName); err != nil {
+				return nil, fmt.Errorf("linter %q: %w", name, err)
+			}
+		}
+		selected = append(selected, selectedRule{rule: r, severity: severity})
+	}
+	return func(tf *parser.TemplateFile) (diags []Diagnostic) {
+		for _, s := range selected {
+			for _, d := range s.rule.Check(tf) {
+				diags = append(diags, Diagnostic{Diagnostic: d, Rule: s.rule.Name(), Severity: s.severity})
+			}
+		}
+		return diags
+	}, nil
+}
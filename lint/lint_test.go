@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("the named linters are run", func(t *testing.T) {
+		l, err := New("a11y")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tf, err := parser.ParseString("package main\n\ntempl logo() {\n\t<img src=\"logo.png\"/>\n}\n")
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		if diags := l(tf); len(diags) != 1 {
+			t.Errorf("expected 1 diagnostic, got %v", diags)
+		}
+	})
+	t.Run("diagnostics have the name and severity of the rule", func(t *testing.T) {
+		l, err := New("no-inline-styles", "obsolete-elements:error")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tf, err := parser.ParseString("package main\n\ntempl banner() {\n\t<marquee style=\"color: red\">Sale</marquee>\n}\n")
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		diags := l(tf)
+		if len(diags) != 2 {
+			t.Fatalf("expected 2 diagnostics, got %v", diags)
+		}
+		if diags[0].Rule != "no-inline-styles" || diags[0].Severity != SeverityWarning {
+			t.Errorf("expected a no-inline-styles warning, got %s %s", diags[0].Rule, diags[0].Severity)
+		}
+		if diags[1].Rule != "obsolete-elements" || diags[1].Severity != SeverityError {
+			t.Errorf("expected an obsolete-elements error, got %s %s", diags[1].Rule, diags[1].Severity)
+		}
+	})
+	t.Run("unknown linters are an error", func(t *testing.T) {
+		_, err := New("a11y", "spelling")
+		if err == nil || !strings.Contains(err.Error(), `unknown linter "spelling", expected one of a11y, img-lazy-loading`) {
+			t.Errorf("expected an unknown linter error, got %v", err)
+		}
+	})
+	t.Run("unknown severities are an error", func(t *testing.T) {
+		_, err := New("a11y:fatal")
+		if err == nil || !strings.Contains(err.Error(), `linter "a11y": unknown severity "fatal"`) {
+			t.Errorf("expected an unknown severity error, got %v", err)
+		}
+	})
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("registered rules can be used", func(t *testing.T) {
+		Register(BannedElements("test-no-iframes", SeverityError, "iframe"))
+		defer func() {
+			rulesMutex.Lock()
+			delete(rules, "test-no-iframes")
+			rulesMutex.Unlock()
+		}()
+		if _, err := New("test-no-iframes"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("rules can't be registered twice", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		Register(NoInlineStyles("no-inline-styles", SeverityError))
+	})
+}
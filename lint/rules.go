@@ -0,0 +1,153 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/a-h/templ/parser/v2/visitor"
+)
+
+// NoInlineStyles returns a rule that reports style attributes, so that styles are kept in
+// stylesheets.
+func NoInlineStyles(name string, severity Severity) Rule {
+	return NewRule(name, severity, func(tf *parser.TemplateFile) (diags []parser.Diagnostic) {
+		visitElements(tf, func(n *parser.Element) {
+			for _, attr := range findAttributes(n.Attributes, "style") {
+				diags = append(diags, parser.Diagnostic{
+					Message: fmt.Sprintf("<%s> has an inline style attribute, use a class instead", n.Name),
+					Range:   attr.NameRange,
+				})
+			}
+		})
+		return diags
+	})
+}
+
+// RequiredAttribute returns a rule that reports elements that don't set the attribute to
+// value, e.g. RequiredAttribute("img-lazy-loading", SeverityWarning, "img", "loading", "lazy").
+// If value is empty, the attribute can have any value. Elements with spread attributes are
+// assumed to set the attribute.
+func RequiredAttribute(name string, severity Severity, element, attribute, value string) Rule {
+	return NewRule(name, severity, func(tf *parser.TemplateFile) (diags []parser.Diagnostic) {
+		visitElements(tf, func(n *parser.Element) {
+			if !strings.EqualFold(n.Name, element) || hasDynamicAttributes(n.Attributes) {
+				return
+			}
+			attrs := findAttributes(n.Attributes, attribute)
+			if len(attrs) == 0 {
+				message := fmt.Sprintf("<%s> elements must have the %s attribute", element, attribute)
+				if value != "" {
+					message = fmt.Sprintf("<%s> elements must have %s=%q", element, attribute, value)
+				}
+				diags = append(diags, parser.Diagnostic{Message: message, Range: n.NameRange})
+				return
+			}
+			for _, attr := range attrs {
+				if value == "" || attr.Value == nil || *attr.Value == value {
+					continue
+				}
+				diags = append(diags, parser.Diagnostic{
+					Message: fmt.Sprintf("<%s> elements must have %s=%q, got %q", element, attribute, value, *attr.Value),
+					Range:   attr.NameRange,
+				})
+			}
+		})
+		return diags
+	})
+}
+
+// BannedElements returns a rule that reports the use of the elements, e.g. <marquee>.
+func BannedElements(name string, severity Severity, elements ...string) Rule {
+	return NewRule(name, severity, func(tf *parser.TemplateFile) (diags []parser.Diagnostic) {
+		visitElements(tf, func(n *parser.Element) {
+			if slices.ContainsFunc(elements, func(e string) bool { return strings.EqualFold(e, n.Name) }) {
+				diags = append(diags, parser.Diagnostic{
+					Message: fmt.Sprintf("<%s> elements aren't allowed", n.Name),
+					Range:   n.NameRange,
+				})
+			}
+		})
+		return diags
+	})
+}
+
+func visitElements(tf *parser.TemplateFile, f func(n *parser.Element)) {
+	v := visitor.New()
+	visitElement := v.Element
+	v.Element = func(n *parser.Element) error {
+		f(n)
+		return visitElement(n)
+	}
+	_ = tf.Visit(v)
+}
+
+// foundAttribute is an attribute with a constant name.
+type foundAttribute struct {
+	NameRange parser.Range
+	// Value of the attribute, or nil if it's set by an expression.
+	Value *string
+}
+
+// findAttributes returns the attributes with the name, including those in either branch of
+// conditional attributes.
+func findAttributes(attrs []parser.Attribute, name string) (found []foundAttribute) {
+	for _, attr := range attrs {
+		var key parser.AttributeKey
+		var value *string
+		switch attr := attr.(type) {
+		case *parser.ConstantAttribute:
+			key, value = attr.Key, &attr.Value
+		case *parser.BoolConstantAttribute:
+			empty := ""
+			key, value = attr.Key, &empty
+		case *parser.ExpressionAttribute:
+			key = attr.Key
+		case *parser.BoolExpressionAttribute:
+			key = attr.Key
+		case *parser.ConditionalAttribute:
+			found = append(found, findAttributes(attr.Then, name)...)
+			found = append(found, findAttributes(attr.Else, name)...)
+			continue
+		default:
+			continue
+		}
+		if k, ok := key.(parser.ConstantAttributeKey); ok && strings.EqualFold(k.Name, name) {
+			found = append(found, foundAttribute{NameRange: k.NameRange, Value: value})
+		}
+	}
+	return found
+}
+
+// hasDynamicAttributes returns true if attributes may be added by spread attributes, or
+// attributes with expression keys.
+func hasDynamicAttributes(attrs []parser.Attribute) bool {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case *parser.SpreadAttributes:
+			return true
+		case *parser.ConditionalAttribute:
+			if hasDynamicAttributes(attr.Then) || hasDynamicAttributes(attr.Else) {
+				return true
+			}
+		case *parser.ConstantAttribute:
+			if _, ok := attr.Key.(parser.ConstantAttributeKey); !ok {
+				return true
+			}
+		case *parser.BoolConstantAttribute:
+			if _, ok := attr.Key.(parser.ConstantAttributeKey); !ok {
+				return true
+			}
+		case *parser.ExpressionAttribute:
+			if _, ok := attr.Key.(parser.ConstantAttributeKey); !ok {
+				return true
+			}
+		case *parser.BoolExpressionAttribute:
+			if _, ok := attr.Key.(parser.ConstantAttributeKey); !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package lint
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		input    string
+		expected []string
+	}{
+		{
+			name: "inline styles are reported",
+			rule: NoInlineStyles("no-inline-styles", SeverityWarning),
+			input: `<div style="color: red"></div>
+<div style={ styles }></div>
+<div if highlight { style="color: red" }></div>
+<div class="red"></div>`,
+			expected: []string{
+				"4:6: <div> has an inline style attribute, use a class instead",
+				"5:6: <div> has an inline style attribute, use a class instead",
+				"6:21: <div> has an inline style attribute, use a class instead",
+			},
+		},
+		{
+			name: "required attributes are reported if they're missing or have a different value",
+			rule: RequiredAttribute("img-lazy-loading", SeverityWarning, "img", "loading", "lazy"),
+			input: `<img src="a.png"/>
+<img src="b.png" loading="eager"/>
+<img src="c.png" loading="lazy"/>
+<img src="d.png" loading={ loading }/>
+<img src="e.png" { attrs... }/>`,
+			expected: []string{
+				`4:2: <img> elements must have loading="lazy"`,
+				`5:18: <img> elements must have loading="lazy", got "eager"`,
+			},
+		},
+		{
+			name: "required attributes can have any value if the value is empty",
+			rule: RequiredAttribute("form-action", SeverityWarning, "form", "action", ""),
+			input: `<form></form>
+<form action="/save"></form>`,
+			expected: []string{
+				"4:2: <form> elements must have the action attribute",
+			},
+		},
+		{
+			name: "banned elements are reported",
+			rule: BannedElements("obsolete-elements", SeverityWarning, "center", "marquee"),
+			input: `<center>Welcome</center>
+<marquee>Sale</marquee>
+<div>Hello</div>`,
+			expected: []string{
+				"4:2: <center> elements aren't allowed",
+				"5:2: <marquee> elements aren't allowed",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString("package main\n\ntempl Page() {\n" + tt.input + "\n}\n")
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			var actual []string
+			for _, d := range tt.rule.Check(tf) {
+				actual = append(actual, fmt.Sprintf("%d:%d: %s", d.Range.From.Line+1, d.Range.From.Col+1, d.Message))
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
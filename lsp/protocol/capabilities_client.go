@@ -358,6 +358,11 @@ type TextDocumentClientCapabilities struct {
 	//
 	// @since 3.16.0.
 	Moniker *MonikerClientCapabilities `json:"moniker,omitempty"`
+
+	// InlayHint capabilities specific to the "textDocument/inlayHint" request.
+	//
+	// @since 3.17.0.
+	InlayHint *InlayHintClientCapabilities `json:"inlayHint,omitempty"`
 }
 
 // TextDocumentSyncClientCapabilities defines which synchronization capabilities the client supports.
@@ -122,6 +122,11 @@ type ServerCapabilities struct {
 	// @since 3.16.0.
 	MonikerProvider any `json:"monikerProvider,omitempty"` // TODO(zchee): bool | *MonikerOptions | *MonikerRegistrationOptions
 
+	// InlayHintProvider is the server provides inlay hints.
+	//
+	// @since 3.17.0.
+	InlayHintProvider any `json:"inlayHintProvider,omitempty"` // bool | *InlayHintOptions
+
 	// Experimental server capabilities.
 	Experimental any `json:"experimental,omitempty"`
 }
@@ -431,6 +436,23 @@ type LinkedEditingRangeRegistrationOptions struct {
 // @since 3.16.0.
 type SemanticTokensOptions struct {
 	WorkDoneProgressOptions
+
+	// Legend is the legend used by the server.
+	Legend SemanticTokensLegend `json:"legend"`
+
+	// Range is the server supports providing semantic tokens for a specific range of a document.
+	Range any `json:"range,omitempty"` // bool | struct{}
+
+	// Full is the server supports providing semantic tokens for a full document.
+	Full any `json:"full,omitempty"` // bool | *SemanticTokensFullOptions
+}
+
+// SemanticTokensFullOptions options of the semantic tokens of a full document.
+//
+// @since 3.16.0.
+type SemanticTokensFullOptions struct {
+	// Delta is the server supports deltas for full documents.
+	Delta bool `json:"delta,omitempty"`
 }
 
 // SemanticTokensRegistrationOptions registration option of semantic tokens provider server capabilities.
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2021 The Go Language Server Authors
+// SPDX-License-Identifier: BSD-3-Clause
+
+package protocol
+
+import "strconv"
+
+// InlayHintParams params of the InlayHint request.
+//
+// @since 3.17.0.
+type InlayHintParams struct {
+	WorkDoneProgressParams
+
+	// TextDocument is the text document.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// Range is the visible document range for which inlay hints should be computed.
+	Range Range `json:"range"`
+}
+
+// InlayHintKind is the kind of an inlay hint.
+//
+// @since 3.17.0.
+type InlayHintKind uint32
+
+const (
+	// InlayHintKindType is an inlay hint that is for a type annotation.
+	InlayHintKindType InlayHintKind = 1
+
+	// InlayHintKindParameter is an inlay hint that is for a parameter.
+	InlayHintKindParameter InlayHintKind = 2
+)
+
+// String implements fmt.Stringer.
+func (k InlayHintKind) String() string {
+	switch k {
+	case InlayHintKindType:
+		return "Type"
+	case InlayHintKindParameter:
+		return "Parameter"
+	default:
+		return strconv.FormatFloat(float64(k), 'f', -10, 64)
+	}
+}
+
+// InlayHint is inlay hint information.
+//
+// @since 3.17.0.
+type InlayHint struct {
+	// Position is the position of this hint.
+	Position Position `json:"position"`
+
+	// Label is the label of this hint. A human readable string or an array of
+	// InlayHintLabelPart label parts.
+	Label any `json:"label"` // string | []InlayHintLabelPart
+
+	// Kind is the kind of this hint. Can be omitted in which case the client
+	// should fall back to a reasonable default.
+	Kind InlayHintKind `json:"kind,omitempty"`
+
+	// TextEdits are optional text edits that are performed when accepting this inlay hint.
+	TextEdits []TextEdit `json:"textEdits,omitempty"`
+
+	// Tooltip is the tooltip text when you hover over this item.
+	Tooltip any `json:"tooltip,omitempty"` // string | MarkupContent
+
+	// PaddingLeft renders padding before the hint.
+	PaddingLeft bool `json:"paddingLeft,omitempty"`
+
+	// PaddingRight renders padding after the hint.
+	PaddingRight bool `json:"paddingRight,omitempty"`
+
+	// Data is a data entry field that is preserved on an inlay hint between
+	// a `textDocument/inlayHint` and a `inlayHint/resolve` request.
+	Data any `json:"data,omitempty"`
+}
+
+// InlayHintLabelPart is an inlay hint label part allows for interactive and composite labels
+// of inlay hints.
+//
+// @since 3.17.0.
+type InlayHintLabelPart struct {
+	// Value is the value of this label part.
+	Value string `json:"value"`
+
+	// Tooltip is the tooltip text when you hover over this label part.
+	Tooltip any `json:"tooltip,omitempty"` // string | MarkupContent
+
+	// Location is an optional source code location that represents this label part.
+	Location *Location `json:"location,omitempty"`
+
+	// Command is an optional command for this label part.
+	Command *Command `json:"command,omitempty"`
+}
+
+// InlayHintClientCapabilities capabilities specific to the "textDocument/inlayHint" request.
+//
+// @since 3.17.0.
+type InlayHintClientCapabilities struct {
+	// DynamicRegistration whether inlay hints support dynamic registration.
+	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+
+	// ResolveSupport indicates which properties a client can resolve lazily on an inlay hint.
+	ResolveSupport *InlayHintClientCapabilitiesResolveSupport `json:"resolveSupport,omitempty"`
+}
+
+// InlayHintClientCapabilitiesResolveSupport is the properties a client can resolve lazily.
+//
+// @since 3.17.0.
+type InlayHintClientCapabilitiesResolveSupport struct {
+	// Properties are the properties that a client can resolve lazily.
+	Properties []string `json:"properties"`
+}
+
+// InlayHintOptions option of inlay hint provider server capabilities.
+//
+// @since 3.17.0.
+type InlayHintOptions struct {
+	WorkDoneProgressOptions
+
+	// ResolveProvider is the server provides support to resolve additional
+	// information for an inlay hint item.
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+}
@@ -758,6 +758,18 @@ func serverDispatch(ctx context.Context, logger *slog.Logger, server Server, rep
 
 		return true, reply(ctx, resp, err)
 
+	case MethodInlayHint: // request
+		defer logger.Debug(MethodInlayHint, slog.Any("error", err))
+
+		var params InlayHintParams
+		if err := dec.Decode(&params); err != nil {
+			return true, replyParseError(ctx, reply, err)
+		}
+
+		resp, err := server.InlayHint(ctx, &params)
+
+		return true, reply(ctx, resp, err)
+
 	case MethodMoniker: // request
 		defer logger.Debug(MethodMoniker, slog.Any("error", err))
 
@@ -836,6 +848,7 @@ type Server interface {
 	SemanticTokensRefresh(ctx context.Context) (err error)
 	LinkedEditingRange(ctx context.Context, params *LinkedEditingRangeParams) (result *LinkedEditingRanges, err error)
 	Moniker(ctx context.Context, params *MonikerParams) (result []Moniker, err error)
+	InlayHint(ctx context.Context, params *InlayHintParams) (result []InlayHint, err error)
 	Request(ctx context.Context, method string, params any) (result any, err error)
 }
 
@@ -1020,6 +1033,9 @@ const (
 
 	// MethodMoniker method name of "textDocument/moniker".
 	MethodMoniker = "textDocument/moniker"
+
+	// MethodInlayHint method name of "textDocument/inlayHint".
+	MethodInlayHint = "textDocument/inlayHint"
 )
 
 // server implements a Language Server Protocol server.
@@ -1879,6 +1895,20 @@ func (s *server) Moniker(ctx context.Context, params *MonikerParams) (result []M
 	return result, nil
 }
 
+// InlayHint is the inlay hints request is sent from the client to the server to compute inlay hints for a given text document range.
+//
+// @since 3.17.0.
+func (s *server) InlayHint(ctx context.Context, params *InlayHintParams) (result []InlayHint, err error) {
+	s.logger.Debug("call " + MethodInlayHint)
+	defer s.logger.Debug("end "+MethodInlayHint, slog.Any("error", err))
+
+	if err := Call(ctx, s.Conn, MethodInlayHint, params, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // Request sends a request from the client to the server that non-compliant with the Language Server Protocol specifications.
 func (s *server) Request(ctx context.Context, method string, params any) (any, error) {
 	s.logger.Debug("call " + method)
@@ -0,0 +1,113 @@
+package templ
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// memoryStore stores values in memory until they expire. It's the store of the fragment cache,
+// and the cache middleware.
+//
+// If maxEntries is greater than zero, the least recently used entries are removed when it's
+// exceeded. Expired entries are removed as new entries are added.
+type memoryStore[V any] struct {
+	m          sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// recent contains the entries, most recently used first.
+	recent list.List
+	// sets is the number of entries added since expired entries were last removed.
+	sets int
+	now  func() time.Time
+}
+
+type memoryStoreEntry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+func newMemoryStore[V any](maxEntries int) *memoryStore[V] {
+	return &memoryStore[V]{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		now:        time.Now,
+	}
+}
+
+// get returns the value stored under the key, if it hasn't expired.
+func (s *memoryStore[V]) get(key string) (value V, ok bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return value, false
+	}
+	e := el.Value.(*memoryStoreEntry[V])
+	if !s.now().Before(e.expires) {
+		s.remove(el)
+		return value, false
+	}
+	s.recent.MoveToFront(el)
+	return e.value, true
+}
+
+// set stores the value under the key for the time to live.
+func (s *memoryStore[V]) set(key string, value V, ttl time.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	now := s.now()
+	// Remove expired entries once the number of entries added since they were last removed
+	// is half the number of entries, so the cost of removing them is spread over sets.
+	s.sets++
+	if s.sets*2 >= len(s.entries) {
+		for _, el := range s.entries {
+			if !now.Before(el.Value.(*memoryStoreEntry[V]).expires) {
+				s.remove(el)
+			}
+		}
+		s.sets = 0
+	}
+	if el, ok := s.entries[key]; ok {
+		s.remove(el)
+	}
+	s.entries[key] = s.recent.PushFront(&memoryStoreEntry[V]{key: key, value: value, expires: now.Add(ttl)})
+	for s.maxEntries > 0 && len(s.entries) > s.maxEntries {
+		s.remove(s.recent.Back())
+	}
+}
+
+// setMaxEntries sets the maximum number of entries, which is applied when values are set.
+func (s *memoryStore[V]) setMaxEntries(n int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.maxEntries = n
+}
+
+// delete removes the values stored under the keys.
+func (s *memoryStore[V]) delete(keys ...string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for _, key := range keys {
+		if el, ok := s.entries[key]; ok {
+			s.remove(el)
+		}
+	}
+}
+
+// deleteFunc removes the values stored under keys that match.
+func (s *memoryStore[V]) deleteFunc(match func(key string) bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for key, el := range s.entries {
+		if match(key) {
+			s.remove(el)
+		}
+	}
+}
+
+func (s *memoryStore[V]) remove(el *list.Element) {
+	delete(s.entries, el.Value.(*memoryStoreEntry[V]).key)
+	s.recent.Remove(el)
+}
@@ -0,0 +1,70 @@
+package templ
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	setup := func(maxEntries int) (s *memoryStore[string], now *time.Time) {
+		s = newMemoryStore[string](maxEntries)
+		now = &time.Time{}
+		s.now = func() time.Time { return *now }
+		return s, now
+	}
+	t.Run("values are returned until they expire", func(t *testing.T) {
+		s, now := setup(0)
+		s.set("a", "a", time.Minute)
+		if v, ok := s.get("a"); !ok || v != "a" {
+			t.Errorf("expected a, got %q, %v", v, ok)
+		}
+		*now = now.Add(time.Minute)
+		if _, ok := s.get("a"); ok {
+			t.Error("expected the value to have expired")
+		}
+	})
+	t.Run("expired entries are removed as entries are added", func(t *testing.T) {
+		s, now := setup(0)
+		s.set("a", "a", time.Minute)
+		s.set("b", "b", time.Hour)
+		*now = now.Add(time.Minute)
+		s.set("c", "c", time.Minute)
+		s.set("d", "d", time.Minute)
+		if _, ok := s.entries["a"]; ok {
+			t.Error("expected the expired entry to be removed")
+		}
+		if len(s.entries) != 3 {
+			t.Errorf("expected 3 entries, got %d", len(s.entries))
+		}
+	})
+	t.Run("the least recently used entries are removed when the store is full", func(t *testing.T) {
+		s, _ := setup(2)
+		s.set("a", "a", time.Hour)
+		s.set("b", "b", time.Hour)
+		s.get("a")
+		s.set("c", "c", time.Hour)
+		if _, ok := s.get("b"); ok {
+			t.Error("expected the least recently used entry to be removed")
+		}
+		if _, ok := s.get("a"); !ok {
+			t.Error("expected the recently used entry to be kept")
+		}
+		if len(s.entries) != 2 || s.recent.Len() != 2 {
+			t.Errorf("expected 2 entries, got %d", len(s.entries))
+		}
+	})
+	t.Run("entries can be deleted", func(t *testing.T) {
+		s, _ := setup(0)
+		s.set("a", "a", time.Hour)
+		s.set("b/1", "b", time.Hour)
+		s.set("b/2", "b", time.Hour)
+		s.delete("a")
+		s.deleteFunc(func(key string) bool { return key == "b/1" })
+		if len(s.entries) != 1 || s.recent.Len() != 1 {
+			t.Errorf("expected 1 entry, got %d", len(s.entries))
+		}
+		if _, ok := s.get("b/2"); !ok {
+			t.Error("expected b/2 to be kept")
+		}
+	})
+}
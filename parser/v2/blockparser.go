@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+)
+
+// block title {
+var blockDefinitionStartParser = parse.All(
+	parse.OptionalWhitespace,
+	parse.String("block "),
+	parse.OptionalWhitespace,
+)
+
+var blockDefinition parse.Parser[Node] = blockDefinitionParser{}
+
+type blockDefinitionParser struct{}
+
+func (blockDefinitionParser) Parse(pi *parse.Input) (n Node, matched bool, err error) {
+	start := pi.Index()
+	if _, matched, err = blockDefinitionStartParser.Parse(pi); err != nil || !matched {
+		pi.Seek(start)
+		return nil, false, err
+	}
+
+	// The block name must be a Go identifier, followed by an open brace.
+	// If it isn't, this is just text that starts with "block".
+	nameStart := pi.Index()
+	name, ok, err := slotNameParser.Parse(pi)
+	if err != nil || !ok {
+		pi.Seek(start)
+		return nil, false, err
+	}
+	r := &BlockDefinition{
+		Name:      name,
+		NameRange: NewRange(pi.PositionAt(nameStart), pi.Position()),
+	}
+	if _, matched, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !matched {
+		pi.Seek(start)
+		return nil, false, err
+	}
+
+	// Node contents.
+	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "block closing brace")
+	var nodes Nodes
+	if nodes, matched, err = tnp.Parse(pi); err != nil || !matched {
+		r.Children = nodes.Nodes
+		return r, true, parse.Error("block: expected nodes, but none were found", pi.Position())
+	}
+	r.Children = nodes.Nodes
+
+	// Read the required closing brace.
+	if _, matched, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("block: "+unterminatedMissingEnd, pi.Position())
+	}
+
+	return r, true, nil
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBlockDefinitionParser(t *testing.T) {
+	input := `block title {
+	Default title
+}`
+	pi := parse.NewInput(input)
+	result, ok, err := blockDefinition.Parse(pi)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", pi.Index())
+	}
+	bd, isBlock := result.(*BlockDefinition)
+	if !isBlock {
+		t.Fatalf("expected *BlockDefinition, got %T", result)
+	}
+	expectedRange := Range{
+		From: Position{Index: 6, Line: 0, Col: 6},
+		To:   Position{Index: 11, Line: 0, Col: 11},
+	}
+	if diff := cmp.Diff(expectedRange, bd.NameRange); diff != "" {
+		t.Error(diff)
+	}
+	if bd.Name != "title" {
+		t.Errorf("expected name %q, got %q", "title", bd.Name)
+	}
+	if len(stripWhitespaceNodes(bd.Children)) != 1 {
+		t.Errorf("expected a single child, got %#v", bd.Children)
+	}
+}
+
+func TestBlockDefinitionParserText(t *testing.T) {
+	for _, input := range []string{"block party", "block", "block 123 {\n}"} {
+		t.Run(input, func(t *testing.T) {
+			pi := parse.NewInput(input)
+			_, ok, err := blockDefinition.Parse(pi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok {
+				t.Error("expected text not to be parsed as a block definition")
+			}
+			if pi.Index() != 0 {
+				t.Errorf("expected the input not to be consumed, got index %d", pi.Index())
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
+)
+
+// cache(key, ttl) {
+var cacheExpression parse.Parser[Node] = cacheExpressionParser{}
+
+type cacheExpressionParser struct{}
+
+func (cacheExpressionParser) Parse(pi *parse.Input) (n Node, matched bool, err error) {
+	start := pi.Index()
+	if !peekPrefix(pi, "cache(") {
+		return nil, false, nil
+	}
+
+	// Read the call, e.g. cache(key, ttl), to find the end of its arguments.
+	src, _ := pi.Peek(-1)
+	_, end, err := goexpression.TemplExpression(src)
+	if err != nil || end <= len("cache(") || src[end-1] != ')' {
+		// This is text that starts with "cache(".
+		return nil, false, nil
+	}
+	pi.Take(len("cache("))
+	from := pi.Position()
+	args := src[len("cache(") : end-1]
+	pi.Take(len(args))
+	r := &CacheExpression{
+		Expression: NewExpression(args, from, pi.Position()),
+	}
+	pi.Take(len(")"))
+
+	// Eat " {\n". If there's no block, this is text that starts with a call to cache.
+	if _, matched, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !matched {
+		pi.Seek(start)
+		return nil, false, nil
+	}
+
+	// Node contents.
+	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "cache closing brace")
+	var nodes Nodes
+	if nodes, matched, err = tnp.Parse(pi); err != nil || !matched {
+		r.Children = nodes.Nodes
+		return r, true, parse.Error("cache: expected nodes, but none were found", pi.Position())
+	}
+	r.Children = nodes.Nodes
+
+	// Read the required closing brace.
+	if _, matched, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("cache: "+unterminatedMissingEnd, pi.Position())
+	}
+
+	return r, true, nil
+}
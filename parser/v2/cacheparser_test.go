@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCacheExpressionParser(t *testing.T) {
+	input := `cache("menu:" + locale, time.Minute) {
+	<ul></ul>
+}`
+	pi := parse.NewInput(input)
+	result, ok, err := cacheExpression.Parse(pi)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", pi.Index())
+	}
+	ce, isCache := result.(*CacheExpression)
+	if !isCache {
+		t.Fatalf("expected *CacheExpression, got %T", result)
+	}
+	expected := Expression{
+		Value: `"menu:" + locale, time.Minute`,
+		Range: Range{
+			From: Position{Index: 6, Line: 0, Col: 6},
+			To:   Position{Index: 35, Line: 0, Col: 35},
+		},
+	}
+	if diff := cmp.Diff(expected, ce.Expression); diff != "" {
+		t.Error(diff)
+	}
+	if len(stripWhitespaceNodes(ce.Children)) != 1 {
+		t.Errorf("expected a single child element, got %#v", ce.Children)
+	}
+}
+
+func TestCacheExpressionParserText(t *testing.T) {
+	for _, input := range []string{"cache", "cache the result", "cache(key) is a function", "caches(key) {\n}"} {
+		t.Run(input, func(t *testing.T) {
+			pi := parse.NewInput(input)
+			_, ok, err := cacheExpression.Parse(pi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok {
+				t.Error("expected text not to be parsed as a cache expression")
+			}
+			if pi.Index() != 0 {
+				t.Errorf("expected the input not to be consumed, got index %d", pi.Index())
+			}
+		})
+	}
+}
+
+func TestCacheExpressionParserErrors(t *testing.T) {
+	_, ok, err := cacheExpression.Parse(parse.NewInput("cache(key, ttl) {\n<ul></ul>"))
+	if !ok {
+		t.Error("expected a match")
+	}
+	if err == nil {
+		t.Error("expected an error, got nil")
+	}
+}
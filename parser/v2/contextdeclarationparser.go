@@ -0,0 +1,51 @@
+package parser
+
+import (
+	goparser "go/parser"
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+var contextDeclarationNameParser = ExpressionOf(parse.StringFrom(
+	parse.Any(parse.Letter, parse.Rune('_')),
+	parse.StringFrom(parse.AtMost(1000, parse.Any(parse.Letter, parse.ZeroToNine, parse.Rune('_')))),
+))
+
+var horizontalWhitespace = parse.StringFrom(parse.OneOrMore(parse.RuneIn(" \t")))
+
+// templ ctx Theme Theme
+var contextDeclarationParser = parse.Func(func(pi *parse.Input) (r *ContextDeclaration, matched bool, err error) {
+	start := pi.Position()
+	if _, matched, err = parse.String("templ ctx ").Parse(pi); err != nil || !matched {
+		pi.Seek(start.Index)
+		return nil, false, err
+	}
+	r = &ContextDeclaration{}
+
+	// Once we have the prefix, we must have a name and a type.
+	_, _, _ = horizontalWhitespace.Parse(pi)
+	if r.Name, matched, err = contextDeclarationNameParser.Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("templ ctx: expected name, e.g. `templ ctx Theme Theme`", pi.Position())
+	}
+	if _, matched, err = horizontalWhitespace.Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("templ ctx: expected type after name, e.g. `templ ctx Theme Theme`", pi.Position())
+	}
+
+	// The rest of the line is the type.
+	from := pi.Position()
+	line, _, err := stringUntilNewLineOrEOF.Parse(pi)
+	if err != nil {
+		return r, true, err
+	}
+	typ := strings.TrimRightFunc(line, func(r rune) bool { return r == ' ' || r == '\t' || r == '\r' })
+	if typ == "" {
+		return r, true, parse.Error("templ ctx: expected type after name, e.g. `templ ctx Theme Theme`", from)
+	}
+	if _, err = goparser.ParseExpr(typ); err != nil {
+		return r, true, parse.Error("templ ctx: invalid type: "+err.Error(), from)
+	}
+	r.Type = NewExpression(typ, from, pi.PositionAt(from.Index+len(typ)))
+	r.Range = NewRange(start, pi.Position())
+	return r, true, nil
+})
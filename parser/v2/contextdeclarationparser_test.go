@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContextDeclarationParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected *ContextDeclaration
+	}{
+		{
+			name:  "ctx: name and type",
+			input: `templ ctx Theme Theme`,
+			expected: &ContextDeclaration{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 21, Line: 0, Col: 21},
+				},
+				Name: Expression{
+					Value: "Theme",
+					Range: Range{
+						From: Position{Index: 10, Line: 0, Col: 10},
+						To:   Position{Index: 15, Line: 0, Col: 15},
+					},
+				},
+				Type: Expression{
+					Value: "Theme",
+					Range: Range{
+						From: Position{Index: 16, Line: 0, Col: 16},
+						To:   Position{Index: 21, Line: 0, Col: 21},
+					},
+				},
+			},
+		},
+		{
+			name:  "ctx: qualified pointer type with trailing space",
+			input: "templ ctx user  *models.User \n",
+			expected: &ContextDeclaration{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 29, Line: 0, Col: 29},
+				},
+				Name: Expression{
+					Value: "user",
+					Range: Range{
+						From: Position{Index: 10, Line: 0, Col: 10},
+						To:   Position{Index: 14, Line: 0, Col: 14},
+					},
+				},
+				Type: Expression{
+					Value: "*models.User",
+					Range: Range{
+						From: Position{Index: 16, Line: 0, Col: 16},
+						To:   Position{Index: 28, Line: 0, Col: 28},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, ok, err := contextDeclarationParser.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestContextDeclarationParserErrors(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+	}{
+		{name: "missing name", input: "templ ctx \n"},
+		{name: "missing type", input: "templ ctx Theme\n"},
+		{name: "invalid type", input: "templ ctx Theme []\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := contextDeclarationParser.Parse(parse.NewInput(tt.input))
+			if !ok {
+				t.Error("expected the declaration to match")
+			}
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestContextDeclarationParserIgnoresTemplates(t *testing.T) {
+	for _, input := range []string{"templ ctx() {\n}", "templ Name() {\n}"} {
+		_, ok, err := contextDeclarationParser.Parse(parse.NewInput(input))
+		if err != nil || ok {
+			t.Errorf("%q: expected no match, got ok=%v, err=%v", input, ok, err)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/a-h/parse"
 )
 
@@ -10,9 +12,7 @@ import (
 var cssParser = parse.Func(func(pi *parse.Input) (r *CSSTemplate, ok bool, err error) {
 	from := pi.Position()
 
-	r = &CSSTemplate{
-		Properties: []CSSProperty{},
-	}
+	r = &CSSTemplate{}
 	defer func() {
 		r.Range = NewRange(from, pi.Position())
 	}()
@@ -25,8 +25,29 @@ var cssParser = parse.Func(func(pi *parse.Input) (r *CSSTemplate, ok bool, err e
 	r.Name = exp.Name
 	r.Expression = exp.Expression
 
+	if r.Properties, err = parseCSSProperties(pi); err != nil {
+		return r, false, err
+	}
+	return r, true, nil
+})
+
+// parseCSSProperties parses CSS properties and nested rules until the closing brace.
+func parseCSSProperties(pi *parse.Input) (properties []CSSProperty, err error) {
+	properties = []CSSProperty{}
 	for {
 		var cssProperty CSSProperty
+		var ok bool
+
+		// Try for a nested rule.
+		// &:hover {
+		cssProperty, ok, err = parseCSSRule(pi)
+		if err != nil {
+			return
+		}
+		if ok {
+			properties = append(properties, cssProperty)
+			continue
+		}
 
 		// Try for an expression CSS declaration.
 		// background-color: { constants.BackgroundColor };
@@ -35,7 +56,7 @@ var cssParser = parse.Func(func(pi *parse.Input) (r *CSSTemplate, ok bool, err e
 			return
 		}
 		if ok {
-			r.Properties = append(r.Properties, cssProperty)
+			properties = append(properties, cssProperty)
 			continue
 		}
 
@@ -46,7 +67,7 @@ var cssParser = parse.Func(func(pi *parse.Input) (r *CSSTemplate, ok bool, err e
 			return
 		}
 		if ok {
-			r.Properties = append(r.Properties, cssProperty)
+			properties = append(properties, cssProperty)
 			continue
 		}
 
@@ -61,9 +82,46 @@ var cssParser = parse.Func(func(pi *parse.Input) (r *CSSTemplate, ok bool, err e
 			return
 		}
 
-		return r, true, nil
+		return properties, nil
 	}
-})
+}
+
+// A nested rule, with a selector or at-rule on a line that ends with an open brace.
+//
+//	&:hover {
+//	@media (max-width: 600px) {
+//	> li {
+func parseCSSRule(pi *parse.Input) (r *CSSRule, ok bool, err error) {
+	start := pi.Index()
+
+	// Optional whitespace.
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return
+	}
+	var line string
+	if line, ok, err = parse.StringUntil(parse.NewLine).Parse(pi); err != nil || !ok {
+		pi.Seek(start)
+		return nil, false, err
+	}
+	selector, isRule := strings.CutSuffix(strings.TrimSpace(line), "{")
+	selector = strings.TrimSpace(selector)
+	if !isRule || selector == "" || strings.ContainsAny(selector, "{};") {
+		pi.Seek(start)
+		return nil, false, nil
+	}
+	if _, _, err = parse.NewLine.Parse(pi); err != nil {
+		return
+	}
+	r = &CSSRule{Selector: selector}
+	if r.Properties, err = parseCSSProperties(pi); err != nil {
+		return r, false, err
+	}
+	// Eat the line break after the closing brace.
+	if _, _, err = parse.NewLine.Parse(pi); err != nil {
+		return
+	}
+	return r, true, nil
+}
 
 // css Func() {
 type cssExpression struct {
@@ -317,6 +317,54 @@ background-color: { prop };
 				},
 			},
 		},
+		{
+			name: "css: nested rules",
+			input: `css Name() {
+color: #000000;
+&:hover {
+color: #ffffff;
+}
+@media (max-width: 600px) {
+.icon {
+display: none;
+}
+}
+}`,
+			expected: &CSSTemplate{
+				Name: "Name",
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 113, Line: 10, Col: 1},
+				},
+				Expression: Expression{
+					Value: "Name()",
+					Range: Range{
+						From: Position{Index: 4, Line: 0, Col: 4},
+						To:   Position{Index: 10, Line: 0, Col: 10},
+					},
+				},
+				Properties: []CSSProperty{
+					&ConstantCSSProperty{Name: "color", Value: "#000000"},
+					&CSSRule{
+						Selector: "&:hover",
+						Properties: []CSSProperty{
+							&ConstantCSSProperty{Name: "color", Value: "#ffffff"},
+						},
+					},
+					&CSSRule{
+						Selector: "@media (max-width: 600px)",
+						Properties: []CSSProperty{
+							&CSSRule{
+								Selector: ".icon",
+								Properties: []CSSProperty{
+									&ConstantCSSProperty{Name: "display", Value: "none"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -2,6 +2,14 @@ package parser
 
 import (
 	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"html"
+	"strings"
+
+	"github.com/a-h/templ/internal/urlscheme"
 )
 
 type diagnoser func(Node) ([]Diagnostic, error)
@@ -34,11 +42,32 @@ func walkNodes(t []Node, f func(Node) bool) {
 
 var diagnosers = []diagnoser{
 	useOfLegacyCallSyntaxDiagnoser,
+	unsafeURLDiagnoser,
+}
+
+type templateDiagnoser func(*HTMLTemplate) ([]Diagnostic, error)
+
+var templateDiagnosers = []templateDiagnoser{
+	contextPropagationDiagnoser,
 }
 
 func Diagnose(t *TemplateFile) ([]Diagnostic, error) {
 	var diags []Diagnostic
 	var errs error
+	for _, n := range t.Nodes {
+		hn, ok := n.(*HTMLTemplate)
+		if !ok {
+			continue
+		}
+		for _, d := range templateDiagnosers {
+			diag, err := d(hn)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			diags = append(diags, diag...)
+		}
+	}
 	walkTemplate(t, func(n Node) bool {
 		for _, d := range diagnosers {
 			diag, err := d(n)
@@ -62,3 +91,232 @@ func useOfLegacyCallSyntaxDiagnoser(n Node) ([]Diagnostic, error) {
 	}
 	return nil, nil
 }
+
+// DiagnoseUnsafeURLs returns a diagnostic for each constant URL attribute that uses a URL
+// scheme that templ.URL would sanitize, e.g. <a href="javascript:alert(1)">. Constant
+// attributes are written to the output as they are, so they aren't sanitized at runtime.
+//
+// Schemes in allow are also accepted, e.g. "sms:" for projects that allow it with
+// templ.AllowURLSchemes.
+func DiagnoseUnsafeURLs(t *TemplateFile, allow ...string) (diags []Diagnostic) {
+	walkTemplate(t, func(n Node) bool {
+		if e, ok := n.(*Element); ok {
+			checkURLAttributes(e.Name, e.Attributes, allow, &diags)
+		}
+		return true
+	})
+	return diags
+}
+
+func unsafeURLDiagnoser(n Node) (diags []Diagnostic, err error) {
+	if e, ok := n.(*Element); ok {
+		checkURLAttributes(e.Name, e.Attributes, nil, &diags)
+	}
+	return diags, nil
+}
+
+// urlAttributes are the attributes that browsers navigate to, or load resources from.
+var urlAttributes = map[string]struct{}{
+	"href":       {},
+	"src":        {},
+	"action":     {},
+	"formaction": {},
+}
+
+func checkURLAttributes(elementName string, attrs []Attribute, allow []string, diags *[]Diagnostic) {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case *ConstantAttribute:
+			key, ok := attr.Key.(ConstantAttributeKey)
+			if !ok {
+				continue
+			}
+			name := strings.ToLower(key.Name)
+			if _, isURL := urlAttributes[name]; !isURL {
+				continue
+			}
+			if scheme, unsafe := unsafeURLScheme(name, attr.Value, allow); unsafe {
+				*diags = append(*diags, Diagnostic{
+					Message: fmt.Sprintf("<%s %s> uses the unsafe URL scheme %q, which isn't sanitized in constant attributes", elementName, key.Name, scheme+":"),
+					Range:   key.NameRange,
+				})
+			}
+		case *ConditionalAttribute:
+			checkURLAttributes(elementName, attr.Then, allow, diags)
+			checkURLAttributes(elementName, attr.Else, allow, diags)
+		}
+	}
+}
+
+// unsafeURLScheme returns the scheme of the URL, and whether it's a scheme that templ.URL
+// would sanitize, unless it's in allow. Data URLs of images are allowed in src attributes.
+func unsafeURLScheme(attrName, value string, allow []string) (scheme string, unsafe bool) {
+	// Browsers decode character references, ignore leading whitespace and control
+	// characters, and remove tabs and newlines, so "java&#x09;script:" is a javascript: URL.
+	value = strings.TrimLeftFunc(html.UnescapeString(value), func(r rune) bool { return r <= ' ' })
+	value = strings.NewReplacer("\t", "", "\n", "", "\r", "").Replace(value)
+	i := strings.IndexRune(value, ':')
+	if i < 0 || !isURLScheme(value[:i]) {
+		return "", false
+	}
+	scheme = strings.ToLower(value[:i])
+	if urlscheme.IsSafe(scheme, allow...) {
+		return scheme, false
+	}
+	if scheme == "data" {
+		return scheme, attrName != "src" || !strings.HasPrefix(strings.ToLower(value[i+1:]), "image/")
+	}
+	return scheme, true
+}
+
+// isURLScheme returns true if s is a valid scheme, so that "#a:b" and "./a:b" are relative
+// URLs.
+func isURLScheme(s string) bool {
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// contextPropagationDiagnoser warns when components are rendered with a context that
+// isn't derived from the template's ctx, e.g. because ctx was replaced with
+// context.Background() in a Go code block. Components rendered with such a context
+// lose their children, CSP nonces, and any other values stored in the context.
+func contextPropagationDiagnoser(t *HTMLTemplate) (diags []Diagnostic, err error) {
+	checkContextPropagation(t.Children, nil, &diags)
+	return diags, nil
+}
+
+// checkContextPropagation walks the nodes in order. replacedBy is the Go code block that
+// replaced ctx in the current scope, or nil if ctx is the template context.
+func checkContextPropagation(nodes []Node, replacedBy *GoCode, diags *[]Diagnostic) *GoCode {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *GoCode:
+			replacesCtx, usesBackground := analyzeContextUsage(n.Expression.Value)
+			if replacesCtx {
+				replacedBy = n
+				continue
+			}
+			if usesBackground {
+				*diags = append(*diags, Diagnostic{
+					Message: "context.Background() or context.TODO() discards the template context, use ctx instead",
+					Range:   n.Expression.Range,
+				})
+			}
+		case *TemplElementExpression:
+			checkContextPropagationCall(n.Expression, replacedBy, diags)
+			// The children of a templ element are rendered with the context passed to them.
+			checkContextPropagation(n.Children, nil, diags)
+		case *FragmentDefinition:
+			checkContextPropagation(n.Children, nil, diags)
+		case *CacheExpression:
+			checkContextPropagation(n.Children, nil, diags)
+		case *CallTemplateExpression:
+			checkContextPropagationCall(n.Expression, replacedBy, diags)
+		case *IfExpression:
+			checkContextPropagation(n.Then, replacedBy, diags)
+			for _, elseIf := range n.ElseIfs {
+				checkContextPropagation(elseIf.Then, replacedBy, diags)
+			}
+			checkContextPropagation(n.Else, replacedBy, diags)
+		case *SwitchExpression:
+			for _, c := range n.Cases {
+				checkContextPropagation(c.Children, replacedBy, diags)
+			}
+		case CompositeNode:
+			checkContextPropagation(n.ChildNodes(), replacedBy, diags)
+		}
+	}
+	return replacedBy
+}
+
+func checkContextPropagationCall(e Expression, replacedBy *GoCode, diags *[]Diagnostic) {
+	if _, usesBackground := analyzeContextUsage(e.Value); usesBackground {
+		*diags = append(*diags, Diagnostic{
+			Message: "context.Background() or context.TODO() discards the template context, use ctx instead",
+			Range:   e.Range,
+		})
+		return
+	}
+	if replacedBy == nil {
+		return
+	}
+	*diags = append(*diags, Diagnostic{
+		Message: fmt.Sprintf("@%s is rendered with a ctx that isn't derived from the template context (replaced at line %d), so it won't receive children, CSP nonces or other context values", e.Value, replacedBy.Expression.Range.From.Line+1),
+		Range:   e.Range,
+	})
+}
+
+// analyzeContextUsage parses Go statements or an expression, and reports whether ctx is
+// assigned a value that isn't derived from ctx, and whether a background context is used.
+func analyzeContextUsage(src string) (replacesCtx, usesBackground bool) {
+	f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\nfunc _() {\n"+src+"\n}", 0)
+	if err != nil {
+		return false, false
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if !isIdent(lhs, "ctx") {
+					continue
+				}
+				if !referencesIdent(assignedValue(n.Rhs, i), "ctx") {
+					replacesCtx = true
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if name.Name != "ctx" {
+					continue
+				}
+				if !referencesIdent(assignedValue(n.Values, i), "ctx") {
+					replacesCtx = true
+				}
+			}
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && isIdent(sel.X, "context") && (sel.Sel.Name == "Background" || sel.Sel.Name == "TODO") {
+				usesBackground = true
+			}
+		}
+		return true
+	})
+	return replacesCtx, usesBackground
+}
+
+// assignedValue returns the expression that the i-th name of an assignment is assigned from.
+// A single call may assign several names, e.g. ctx, cancel := context.WithCancel(ctx). It
+// returns nil if there isn't a value, e.g. var ctx context.Context.
+func assignedValue(values []ast.Expr, i int) ast.Expr {
+	if len(values) == 1 {
+		return values[0]
+	}
+	if i < len(values) {
+		return values[i]
+	}
+	return nil
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+func referencesIdent(e ast.Expr, name string) (ok bool) {
+	if e == nil {
+		return false
+	}
+	ast.Inspect(e, func(n ast.Node) bool {
+		if id, isIdent := n.(*ast.Ident); isIdent && id.Name == name {
+			ok = true
+		}
+		return !ok
+	})
+	return ok
+}
@@ -131,6 +131,146 @@ templ template () {
 	<div>
 		<input/>
 	</div>
+}`,
+			want: nil,
+		},
+
+		// contextPropagationDiagnoser
+
+		{
+			name: "contextPropagationDiagnoser: derived ctx",
+			template: `
+package main
+
+templ template () {
+	{{ ctx = context.WithValue(ctx, "key", "value") }}
+	@child()
+}`,
+			want: nil,
+		},
+		{
+			name: "contextPropagationDiagnoser: ctx derived with a call that returns several values",
+			template: `
+package main
+
+templ template () {
+	{{ ctx, cancel := context.WithTimeout(ctx, time.Second) }}
+	{{ defer cancel() }}
+	@child()
+}`,
+			want: nil,
+		},
+		{
+			name: "contextPropagationDiagnoser: ctx replaced by a call that returns several values",
+			template: `
+package main
+
+templ template () {
+	{{ ctx, cancel := context.WithCancel(context.Background()) }}
+	{{ defer cancel() }}
+	@child()
+}`,
+			want: []Diagnostic{{
+				Message: "@child() is rendered with a ctx that isn't derived from the template context (replaced at line 5), so it won't receive children, CSP nonces or other context values",
+				Range:   Range{Position{122, 6, 2}, Position{129, 6, 9}},
+			}},
+		},
+		{
+			name: "contextPropagationDiagnoser: ctx replaced with background context",
+			template: `
+package main
+
+templ template () {
+	{{ ctx := context.Background() }}
+	@child()
+}`,
+			want: []Diagnostic{{
+				Message: "@child() is rendered with a ctx that isn't derived from the template context (replaced at line 5), so it won't receive children, CSP nonces or other context values",
+				Range:   Range{Position{72, 5, 2}, Position{79, 5, 9}},
+			}},
+		},
+		{
+			name: "contextPropagationDiagnoser: replacement is scoped to block",
+			template: `
+package main
+
+templ template () {
+	if true {
+		{{ ctx := context.TODO() }}
+		<div>
+			@child()
+		</div>
+	}
+	@child()
+}`,
+			want: []Diagnostic{{
+				Message: "@child() is rendered with a ctx that isn't derived from the template context (replaced at line 6), so it won't receive children, CSP nonces or other context values",
+				Range:   Range{Position{88, 7, 4}, Position{95, 7, 11}},
+			}},
+		},
+		{
+			name: "contextPropagationDiagnoser: background context passed to component",
+			template: `
+package main
+
+templ template () {
+	@templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		return child().Render(context.Background(), w)
+	})
+}`,
+			want: []Diagnostic{{
+				Message: "context.Background() or context.TODO() discards the template context, use ctx instead",
+				Range:   Range{Position{37, 4, 2}, Position{154, 6, 3}},
+			}},
+		},
+
+		// unsafeURLDiagnoser
+
+		{
+			name: "unsafeURLDiagnoser: javascript: href",
+			template: `
+package main
+
+templ template () {
+	<a href="javascript:alert(1)">Click</a>
+}`,
+			want: []Diagnostic{{
+				Message: `<a href> uses the unsafe URL scheme "javascript:", which isn't sanitized in constant attributes`,
+				Range:   Range{Position{39, 4, 4}, Position{43, 4, 8}},
+			}},
+		},
+		{
+			name: "unsafeURLDiagnoser: obfuscated schemes and conditional attributes",
+			template: `
+package main
+
+templ template () {
+	<iframe src=" Java&#x09;Script:alert(1)"></iframe>
+	<form if ok { action="data:text/html,<script></script>" }></form>
+}`,
+			want: []Diagnostic{
+				{
+					Message: `<iframe src> uses the unsafe URL scheme "javascript:", which isn't sanitized in constant attributes`,
+					Range:   Range{Position{44, 4, 9}, Position{47, 4, 12}},
+				},
+				{
+					Message: `<form action> uses the unsafe URL scheme "data:", which isn't sanitized in constant attributes`,
+					Range:   Range{Position{102, 5, 15}, Position{108, 5, 21}},
+				},
+			},
+		},
+		{
+			name: "unsafeURLDiagnoser: safe and relative URLs are not reported",
+			template: `
+package main
+
+templ template () {
+	<a href="https://example.com">a</a>
+	<a href="mailto:a@example.com">a</a>
+	<a href="/a:b">a</a>
+	<a href="#a:b">a</a>
+	<img src="data:image/png;base64,AA"/>
+	<p title="javascript:alert(1)">a</p>
 }`,
 			want: nil,
 		},
@@ -151,3 +291,26 @@ templ template () {
 		})
 	}
 }
+
+func TestDiagnoseUnsafeURLsAllow(t *testing.T) {
+	tf, err := ParseString(`
+package main
+
+templ template () {
+	<a href="sms:+15550100">Text</a>
+	<a href="javascript:alert(1)">Click</a>
+}`)
+	if err != nil {
+		t.Fatalf("ParseTemplateFile() error = %v", err)
+	}
+	if got := DiagnoseUnsafeURLs(tf); len(got) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %v", got)
+	}
+	want := []Diagnostic{{
+		Message: `<a href> uses the unsafe URL scheme "javascript:", which isn't sanitized in constant attributes`,
+		Range:   Range{Position{73, 5, 4}, Position{77, 5, 8}},
+	}}
+	if diff := cmp.Diff(want, DiagnoseUnsafeURLs(tf, "SMS:")); diff != "" {
+		t.Errorf("DiagnoseUnsafeURLs() mismatch (-want +got):\n%s", diff)
+	}
+}
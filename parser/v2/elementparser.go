@@ -41,8 +41,8 @@ var elementOpenTagParser = parse.Func(func(pi *parse.Input) (e elementOpenTag, m
 		return e, true, err
 	}
 
-	// If any attribute is not on the same line as the element name, indent them.
-	if pi.Position().Line != l {
+	// If any attribute is not on the same line as the element name, or has comments, indent them.
+	if pi.Position().Line != l || attributesHaveComments(e.Attributes) {
 		e.IndentAttrs = true
 	}
 
@@ -401,20 +401,110 @@ var attribute attributeParser
 type attributesParser struct{}
 
 func (attributesParser) Parse(in *parse.Input) (attributes []Attribute, ok bool, err error) {
+	// pending are the comments before attributes that don't carry trivia, e.g. spread
+	// attributes, which are attached to the next attribute that does.
+	var pending []*GoComment
 	for {
+		start := in.Index()
+		var trivia Trivia
+		if trivia, err = parseAttributeTrivia(in); err != nil {
+			return attributes, false, err
+		}
 		var attr Attribute
 		attr, ok, err = attribute.Parse(in)
 		if err != nil {
 			return
 		}
 		if !ok {
+			if len(trivia.Comments) == 0 && len(pending) == 0 {
+				in.Seek(start)
+				break
+			}
+			// Comments after the last attribute are attached to it.
+			comments := append(pending, trivia.Comments...)
+			tc, isTriviaCarrier := lastTriviaCarrier(attributes)
+			if !isTriviaCarrier {
+				return attributes, false, parse.Error("comments must be next to an attribute", in.PositionAt(int(comments[0].Range.From.Index)))
+			}
+			tc.NodeTrivia().TrailingComments = append(tc.NodeTrivia().TrailingComments, comments...)
 			break
 		}
+		// Prevent an infinite loop if an attribute parser matches without consuming input.
+		if in.Index() == start {
+			return attributes, false, parse.Error("unexpected attribute", in.Position())
+		}
+		tc, isTriviaCarrier := attr.(TriviaCarrier)
+		if !isTriviaCarrier {
+			// Comments before the attribute are attached to the attribute before it, if it
+			// carries trivia, so that they stay in order, or else to the next attribute.
+			if prev, ok := previousTriviaCarrier(attributes); ok && len(pending) == 0 {
+				prev.NodeTrivia().TrailingComments = append(prev.NodeTrivia().TrailingComments, trivia.Comments...)
+			} else {
+				pending = append(pending, trivia.Comments...)
+			}
+			attributes = append(attributes, attr)
+			continue
+		}
+		// Blank lines before the first attribute aren't kept.
+		trivia.BlankLineBefore = trivia.BlankLineBefore && len(attributes) > 0
+		trivia.Comments = append(pending, trivia.Comments...)
+		pending = nil
+		*tc.NodeTrivia() = trivia
+		if tc.NodeTrivia().LineComment, err = parseAttributeLineComment(in); err != nil {
+			return attributes, false, err
+		}
 		attributes = append(attributes, attr)
 	}
 	return attributes, true, nil
 }
 
+// previousTriviaCarrier returns the last attribute, if it carries trivia.
+func previousTriviaCarrier(attributes []Attribute) (tc TriviaCarrier, ok bool) {
+	if len(attributes) == 0 {
+		return nil, false
+	}
+	tc, ok = attributes[len(attributes)-1].(TriviaCarrier)
+	return tc, ok
+}
+
+// lastTriviaCarrier returns the last of the attributes that carries trivia.
+func lastTriviaCarrier(attributes []Attribute) (tc TriviaCarrier, ok bool) {
+	for i := len(attributes) - 1; i >= 0; i-- {
+		if tc, ok = attributes[i].(TriviaCarrier); ok {
+			return tc, true
+		}
+	}
+	return nil, false
+}
+
+// parseAttributeLineComment parses a comment after an attribute, on the same line.
+func parseAttributeLineComment(pi *parse.Input) (c *GoComment, err error) {
+	start := pi.Index()
+	_, _, _ = horizontalWhitespace.Parse(pi)
+	n, ok, err := goComment.Parse(pi)
+	if err != nil || !ok {
+		pi.Seek(start)
+		return nil, err
+	}
+	return n.(*GoComment), nil
+}
+
+// parseAttributeTrivia parses the whitespace and comments before an attribute.
+func parseAttributeTrivia(pi *parse.Input) (t Trivia, err error) {
+	for {
+		ws, _, _ := parse.OptionalWhitespace.Parse(pi)
+		if len(t.Comments) == 0 && strings.Count(ws, "\n") > 1 {
+			t.BlankLineBefore = true
+		}
+		var n Node
+		var ok bool
+		if n, ok, err = goComment.Parse(pi); err != nil || !ok {
+			return t, err
+		}
+		t.Comments = append(t.Comments, n.(*GoComment))
+	}
+}
+
 // Element name.
 var (
 	elementNameFirst      = "abcdefghijklmnopqrstuvwxyz"
@@ -365,7 +365,7 @@ if test {` + " " + `
 			input:  ` { spread... }"`,
 			parser: StripType(spreadAttributesParser),
 			expected: &SpreadAttributes{
-				Expression{
+				Expression: Expression{
 					Value: "spread",
 					Range: Range{
 						From: Position{
@@ -1882,6 +1882,16 @@ func TestElementParserErrors(t *testing.T) {
 					Col:   0,
 				}),
 		},
+		{
+			name:  "element: comments must be next to an attribute",
+			input: "<div // note\n></div>",
+			expected: parse.Error("comments must be next to an attribute",
+				parse.Position{
+					Index: 5,
+					Line:  0,
+					Col:   5,
+				}),
+		},
 		{
 			name:  "element: names cannot be greater than 128 characters",
 			input: `<aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa></aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa>`,
@@ -1905,6 +1915,43 @@ func TestElementParserErrors(t *testing.T) {
 	}
 }
 
+func TestElementParserTrivia(t *testing.T) {
+	input := `<div
+	id="a"
+
+	// Styles.
+	class="b" // Primary.
+	// title="c"
+></div>`
+	result, ok, err := element.Parse(parse.NewInput(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	attrs := result.(*Element).Attributes
+	if len(attrs) != 2 {
+		t.Fatalf("expected 2 attributes, got %d", len(attrs))
+	}
+	expected := Trivia{
+		BlankLineBefore: true,
+		Comments: []*GoComment{
+			{Contents: " Styles.", Range: Range{From: Position{Index: 15, Line: 3, Col: 1}, To: Position{Index: 25, Line: 3, Col: 11}}},
+		},
+		LineComment: &GoComment{Contents: " Primary.", Range: Range{From: Position{Index: 37, Line: 4, Col: 11}, To: Position{Index: 48, Line: 4, Col: 22}}},
+		TrailingComments: []*GoComment{
+			{Contents: ` title="c"`, Range: Range{From: Position{Index: 50, Line: 5, Col: 1}, To: Position{Index: 62, Line: 5, Col: 13}}},
+		},
+	}
+	if diff := cmp.Diff(Trivia{}, attrs[0].(*ConstantAttribute).Trivia); diff != "" {
+		t.Errorf("expected the first attribute not to have trivia:\n%s", diff)
+	}
+	if diff := cmp.Diff(expected, attrs[1].(*ConstantAttribute).Trivia); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func TestBigElement(t *testing.T) {
 	sb := new(strings.Builder)
 	sb.WriteString("<div>")
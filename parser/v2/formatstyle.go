@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"io"
+	"strings"
+)
+
+// QuoteStyle is the quote character used to write the values of constant attributes.
+type QuoteStyle string
+
+const (
+	// QuotePreserve writes each value with the quote character that it was parsed with.
+	QuotePreserve QuoteStyle = ""
+	// QuoteDouble writes values with double quotes, e.g. class="a".
+	QuoteDouble QuoteStyle = "double"
+	// QuoteSingle writes values with single quotes, e.g. class='a'.
+	QuoteSingle QuoteStyle = "single"
+)
+
+// AttributeWrap is how the formatter decides whether the attributes of an element are written
+// on separate lines.
+type AttributeWrap string
+
+const (
+	// WrapPreserve keeps attributes that were written on separate lines on separate lines, and
+	// wraps the attributes of elements that exceed the limits of the style.
+	WrapPreserve AttributeWrap = ""
+	// WrapAuto wraps the attributes of elements that exceed the limits of the style, and joins
+	// the attributes of other elements onto one line. Attributes that have comments, or span
+	// multiple lines, e.g. conditional attributes, stay on separate lines.
+	WrapAuto AttributeWrap = "auto"
+)
+
+// FormatStyle configures how templ files are formatted, see TemplateFile.WriteStyle. The zero
+// value is the default style.
+type FormatStyle struct {
+	// IndentWidth is the number of spaces to indent each level with. If it's 0, tabs are used.
+	// Go code is formatted with gofmt, so it's always indented with tabs.
+	IndentWidth int
+	// MaxInlineAttributes is the maximum number of attributes that an element can have on a
+	// single line. Elements with more attributes are written with one attribute per line. If
+	// it's 0, there's no maximum.
+	MaxInlineAttributes int
+	// MaxLineLength is the maximum length of the start tag of an element. Elements with longer
+	// start tags are written with one attribute per line. Tabs count as 4 characters. If it's
+	// 0, there's no maximum.
+	MaxLineLength int
+	// AttributeQuote is the quote character used to write constant attribute values. Values
+	// that contain the quote character are written with the quote character they were parsed
+	// with.
+	AttributeQuote QuoteStyle
+	// AttributeWrap is how the attributes of elements that are within the limits of the style
+	// are written.
+	AttributeWrap AttributeWrap
+}
+
+// indent returns the indentation of a level.
+func (s FormatStyle) indent(level int) string {
+	if s.IndentWidth > 0 {
+		return strings.Repeat(" ", s.IndentWidth*level)
+	}
+	return strings.Repeat("\t", level)
+}
+
+// indentAttributes returns true if the attributes of the element are written on separate
+// lines.
+func (s FormatStyle) indentAttributes(e *Element, level int) bool {
+	if e.IndentAttrs && (s.AttributeWrap != WrapAuto || attributesHaveComments(e.Attributes)) {
+		return true
+	}
+	if s.MaxInlineAttributes > 0 && len(e.Attributes) > s.MaxInlineAttributes {
+		return true
+	}
+	length, multiline := s.startTagLength(e, level)
+	if multiline && s.AttributeWrap == WrapAuto {
+		return e.IndentAttrs
+	}
+	return s.MaxLineLength > 0 && length > s.MaxLineLength
+}
+
+// startTagLength returns the length of the first line of the start tag of the element, if its
+// attributes are written on one line, and whether any attribute spans multiple lines.
+func (s FormatStyle) startTagLength(e *Element, level int) (length int, multiline bool) {
+	width := s.IndentWidth
+	if width == 0 {
+		width = 4
+	}
+	// <div class="a">
+	length = width*level + len("<") + len(e.Name) + len(">")
+	for _, a := range e.Attributes {
+		var sb strings.Builder
+		if err := a.Write(&sb, 0); err != nil {
+			return length, true
+		}
+		attr, _, isMultiline := strings.Cut(sb.String(), "\n")
+		length += len(" ") + len(attr)
+		multiline = multiline || isMultiline
+	}
+	return length, multiline
+}
+
+// NewStyleWriter returns a writer that the Write methods of nodes write to using the style,
+// e.g. to format a single template.
+func NewStyleWriter(w io.Writer, style FormatStyle) io.Writer {
+	return styleWriter{Writer: w, style: style}
+}
+
+// styleWriter applies a FormatStyle to the output of the Write methods of nodes.
+type styleWriter struct {
+	io.Writer
+	style FormatStyle
+}
+
+// formatStyle returns the style that w writes nodes with.
+func formatStyle(w io.Writer) FormatStyle {
+	if sw, ok := w.(styleWriter); ok {
+		return sw.style
+	}
+	return FormatStyle{}
+}
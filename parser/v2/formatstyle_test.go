@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormatStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    FormatStyle
+		input    string
+		expected string
+	}{
+		{
+			name:  "levels can be indented with spaces",
+			style: FormatStyle{IndentWidth: 2},
+			input: `package main
+
+templ a() {
+<div><p>a</p>
+<p>b</p></div>
+}
+`,
+			expected: `package main
+
+templ a() {
+  <div>
+    <p>a</p>
+    <p>b</p>
+  </div>
+}
+`,
+		},
+		{
+			name:  "elements with more attributes than the maximum have one attribute per line",
+			style: FormatStyle{MaxInlineAttributes: 2},
+			input: `package main
+
+templ a() {
+<a href="/" class="a">a</a>
+<a href="/" class="a" id="a">a</a>
+}
+`,
+			expected: `package main
+
+templ a() {
+	<a href="/" class="a">a</a>
+	<a
+		href="/"
+		class="a"
+		id="a"
+	>a</a>
+}
+`,
+		},
+		{
+			name:  "elements with long start tags have one attribute per line",
+			style: FormatStyle{MaxLineLength: 30},
+			input: `package main
+
+templ a() {
+<a href="/">a</a>
+<a href="/" class="primary large">a</a>
+}
+`,
+			expected: `package main
+
+templ a() {
+	<a href="/">a</a>
+	<a
+		href="/"
+		class="primary large"
+	>a</a>
+}
+`,
+		},
+		{
+			name:  "constant attributes can be written with double quotes",
+			style: FormatStyle{AttributeQuote: QuoteDouble},
+			input: `package main
+
+templ a() {
+<a href='/' title='"a"'>a</a>
+}
+`,
+			expected: `package main
+
+templ a() {
+	<a href="/" title='"a"'>a</a>
+}
+`,
+		},
+		{
+			name:  "constant attributes can be written with single quotes",
+			style: FormatStyle{AttributeQuote: QuoteSingle},
+			input: `package main
+
+templ a() {
+<a href="/" title="it's">a</a>
+}
+`,
+			expected: `package main
+
+templ a() {
+	<a href='/' title="it's">a</a>
+}
+`,
+		},
+		{
+			name:  "attributes that fit within the maximum line length are joined",
+			style: FormatStyle{MaxLineLength: 50, AttributeWrap: WrapAuto},
+			input: `package main
+
+templ a() {
+<button
+	hx-post="/save"
+	hx-swap="none"
+>Save</button>
+<button
+	hx-post="/save"
+	hx-target="#result"
+	hx-swap="outerHTML"
+>Save</button>
+}
+`,
+			expected: `package main
+
+templ a() {
+	<button hx-post="/save" hx-swap="none">Save</button>
+	<button
+		hx-post="/save"
+		hx-target="#result"
+		hx-swap="outerHTML"
+	>Save</button>
+}
+`,
+		},
+		{
+			name:  "attributes with comments or conditions are not joined",
+			style: FormatStyle{MaxLineLength: 100, AttributeWrap: WrapAuto},
+			input: `package main
+
+templ a(ok bool) {
+<a
+	// Home.
+	href="/"
+>a</a>
+<a
+	href="/"
+	if ok {
+		class="ok"
+	}
+>a</a>
+}
+`,
+			expected: `package main
+
+templ a(ok bool) {
+	<a
+		// Home.
+		href="/"
+	>a</a>
+	<a
+		href="/"
+		if ok {
+			class="ok"
+		}
+	>a</a>
+}
+`,
+		},
+		{
+			name:  "attributes written on separate lines are preserved by default",
+			style: FormatStyle{MaxLineLength: 100},
+			input: `package main
+
+templ a() {
+<a
+	href="/"
+>a</a>
+}
+`,
+			expected: `package main
+
+templ a() {
+	<a
+		href="/"
+	>a</a>
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			var sb strings.Builder
+			if err = tf.WriteStyle(&sb, tt.style); err != nil {
+				t.Fatalf("failed to write: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, sb.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
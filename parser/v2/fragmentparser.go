@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/a-h/parse"
+)
+
+// @fragment "row" {
+var fragmentDefinitionStartParser = parse.All(
+	parse.String("@fragment"),
+	parse.Whitespace,
+)
+
+var fragmentDefinition parse.Parser[Node] = fragmentDefinitionParser{}
+
+type fragmentDefinitionParser struct{}
+
+func (fragmentDefinitionParser) Parse(pi *parse.Input) (n Node, matched bool, err error) {
+	start := pi.Index()
+	if _, matched, err = fragmentDefinitionStartParser.Parse(pi); err != nil || !matched {
+		pi.Seek(start)
+		return nil, false, err
+	}
+
+	// The fragment name must be a quoted string.
+	// If it isn't, this is a call to a component named fragment.
+	nameStart := pi.Index()
+	if next, ok := pi.Peek(1); !ok || next != `"` {
+		pi.Seek(start)
+		return nil, false, nil
+	}
+	quoted, ok, err := parse.StringFrom(
+		parse.Rune('"'),
+		parse.StringUntil(parse.Rune('"')),
+		parse.Rune('"'),
+	).Parse(pi)
+	if err != nil || !ok {
+		return nil, true, parse.Error(`fragment: expected quoted fragment name, e.g. @fragment "row" {`, pi.PositionAt(nameStart))
+	}
+	r := &FragmentDefinition{
+		NameRange: NewRange(pi.PositionAt(nameStart), pi.Position()),
+	}
+	if r.Name, err = strconv.Unquote(quoted); err != nil {
+		return r, true, parse.Error("fragment: invalid fragment name: "+err.Error(), pi.PositionAt(nameStart))
+	}
+	if _, matched, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("fragment: expected an open brace followed by a new line", pi.Position())
+	}
+
+	// Node contents.
+	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "fragment closing brace")
+	var nodes Nodes
+	if nodes, matched, err = tnp.Parse(pi); err != nil || !matched {
+		r.Children = nodes.Nodes
+		return r, true, parse.Error("fragment: expected nodes, but none were found", pi.Position())
+	}
+	r.Children = nodes.Nodes
+
+	// Read the required closing brace.
+	if _, matched, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("fragment: "+unterminatedMissingEnd, pi.Position())
+	}
+
+	return r, true, nil
+}
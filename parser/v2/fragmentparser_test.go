@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFragmentDefinitionParser(t *testing.T) {
+	input := `@fragment "row" {
+	<tr></tr>
+}`
+	pi := parse.NewInput(input)
+	result, ok, err := fragmentDefinition.Parse(pi)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", pi.Index())
+	}
+	fd, isFragment := result.(*FragmentDefinition)
+	if !isFragment {
+		t.Fatalf("expected *FragmentDefinition, got %T", result)
+	}
+	expectedRange := Range{
+		From: Position{Index: 10, Line: 0, Col: 10},
+		To:   Position{Index: 15, Line: 0, Col: 15},
+	}
+	if diff := cmp.Diff(expectedRange, fd.NameRange); diff != "" {
+		t.Error(diff)
+	}
+	if fd.Name != "row" {
+		t.Errorf("expected name %q, got %q", "row", fd.Name)
+	}
+	if len(stripWhitespaceNodes(fd.Children)) != 1 {
+		t.Errorf("expected a single child element, got %#v", fd.Children)
+	}
+}
+
+func TestFragmentDefinitionParserTemplElements(t *testing.T) {
+	for _, input := range []string{"@fragment()", "@fragment.Row()", "@fragments()", "@fragment row {\n}"} {
+		t.Run(input, func(t *testing.T) {
+			pi := parse.NewInput(input)
+			_, ok, err := fragmentDefinition.Parse(pi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok {
+				t.Error("expected a templ element not to be parsed as a fragment definition")
+			}
+			if pi.Index() != 0 {
+				t.Errorf("expected the input not to be consumed, got index %d", pi.Index())
+			}
+		})
+	}
+}
+
+func TestFragmentDefinitionParserErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "unterminated name",
+			input: `@fragment "row {`,
+		},
+		{
+			name:  "missing open brace",
+			input: `@fragment "row"`,
+		},
+		{
+			name:  "missing close brace",
+			input: "@fragment \"row\" {\n<tr></tr>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := fragmentDefinition.Parse(parse.NewInput(tt.input))
+			if !ok {
+				t.Error("expected a match")
+			}
+			if err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
@@ -3,3 +3,5 @@ echo Element
 go test -fuzz=FuzzElement -fuzztime=120s
 echo Script
 go test -fuzz=FuzzScript -fuzztime=120s
+echo ParseBytes
+go test -fuzz=FuzzParseBytes -fuzztime=120s
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func FuzzParseBytes(f *testing.F) {
+	seeds := []string{
+		"package main\n\ntempl Hello(name string) {\n\t<div>Hello, { name }</div>\n}\n",
+		"package main\n\ntempl Page() {\n\t<a href={ templ.URL(\"/\") } class={ \"a\", templ.KV(\"b\", true) } disabled?={ true }>Home</a>\n}\n",
+		"package main\n\ntempl List(items []string) {\n\tfor _, item := range items {\n\t\tif item != \"\" {\n\t\t\t<li>{ item }</li>\n\t\t} else {\n\t\t\t<li>Empty</li>\n\t\t}\n\t}\n}\n",
+		"package main\n\ncss red() {\n\tcolor: red;\n}\n\nscript alert(msg string) {\n\talert(msg);\n}\n",
+		"package main\n\ntempl Layout() {\n\t<div { attrs... } if ok { data-ok } >\n\t\t@header()\n\t\t{ children... }\n\t</div>\n}\n",
+		"package main\n\ntempl A() {\n\t<input value={ \"a\" b/>\n}\n",
+		"package main\n\ntempl A() {\n\t<div data-a={ }></div>\n}\n",
+	}
+	for _, s := range seeds {
+		f.Add([]byte(s))
+	}
+	// The templates of the generator tests cover most of the syntax.
+	fileNames, err := filepath.Glob("../../generator/test-*/*.templ")
+	if err != nil {
+		f.Fatalf("failed to find templates: %v", err)
+	}
+	for _, fileName := range fileNames {
+		b, err := os.ReadFile(fileName)
+		if err != nil {
+			f.Fatalf("failed to read template: %v", err)
+		}
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, input []byte) {
+		tf, err := ParseBytes(input)
+		if err != nil {
+			return
+		}
+		// Formatted templates must parse.
+		var formatted bytes.Buffer
+		if err = tf.Write(&formatted); err != nil {
+			return
+		}
+		tf, err = ParseBytes(formatted.Bytes())
+		if err != nil {
+			t.Fatalf("failed to parse formatted template: %v\ninput:\n%s\nformatted:\n%s", err, input, formatted.String())
+		}
+	})
+}
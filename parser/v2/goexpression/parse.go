@@ -112,7 +112,7 @@ func TemplExpression(src string) (start, end int, err error) {
 	errorHandler := func(pos token.Position, msg string) {
 		err = fmt.Errorf("error parsing expression: %v", msg)
 	}
-	s.Init(file, []byte(src), errorHandler, scanner.ScanComments)
+	s.Init(file, []byte(blankCarriageReturns(src)), errorHandler, scanner.ScanComments)
 
 	// Read chains of identifiers, e.g.:
 	// components.Variable
@@ -140,7 +140,7 @@ func Expression(src string) (start, end int, err error) {
 	errorHandler := func(pos token.Position, msg string) {
 		err = fmt.Errorf("error parsing expression: %v", msg)
 	}
-	s.Init(file, []byte(src), errorHandler, scanner.ScanComments)
+	s.Init(file, []byte(blankCarriageReturns(src)), errorHandler, scanner.ScanComments)
 
 	// Read chains of identifiers and constants up until RBRACE, e.g.:
 	// true
@@ -198,7 +198,7 @@ func SliceArgs(content string) (expr string, err error) {
 	prefix := "package main\nvar templ_args = []any{"
 	src := prefix + content + "}"
 
-	node, parseErr := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors)
+	node, parseErr := parser.ParseFile(token.NewFileSet(), "", blankCarriageReturns(src), parser.AllErrors)
 	if node == nil {
 		return expr, parseErr
 	}
@@ -239,7 +239,7 @@ func Func(content string) (name, expr string, err error) {
 	prefix := "package main\n"
 	src := prefix + content
 
-	node, parseErr := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors)
+	node, parseErr := parser.ParseFile(token.NewFileSet(), "", blankCarriageReturns(src), parser.AllErrors)
 	if node == nil {
 		return name, expr, parseErr
 	}
@@ -301,7 +301,7 @@ func extract(content string, extractor Extractor) (start, end int, err error) {
 	prefix := "package main\nfunc templ_container() {\n"
 	src := prefix + content
 
-	node, parseErr := parser.ParseFile(token.NewFileSet(), "", src, parser.AllErrors)
+	node, parseErr := parser.ParseFile(token.NewFileSet(), "", blankCarriageReturns(src), parser.AllErrors)
 	if node == nil {
 		return 0, 0, parseErr
 	}
@@ -341,3 +341,14 @@ func extract(content string, extractor Extractor) (start, end int, err error) {
 
 	return start, end, err
 }
+
+// blankCarriageReturns replaces carriage returns with spaces. The Go scanner removes carriage
+// returns from raw string literals and comments, so the positions it reports after them don't
+// match the source. Replacing them keeps the length of the source, and carriage returns
+// outside of literals and comments are whitespace either way.
+func blankCarriageReturns(src string) string {
+	if !strings.Contains(src, "\r") {
+		return src
+	}
+	return strings.ReplaceAll(src, "\r", " ")
+}
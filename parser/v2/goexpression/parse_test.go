@@ -8,6 +8,14 @@ import (
 )
 
 var ifTests = []testInput{
+	{
+		name:  "raw string literal containing carriage returns",
+		input: "x == `a\r\n}\r\nb`",
+	},
+	{
+		name:  "rune literal",
+		input: "r == '{'",
+	},
 	{
 		name:  "basic if",
 		input: `true`,
@@ -295,6 +303,22 @@ func FuzzCaseDefault(f *testing.F) {
 }
 
 var expressionTests = []testInput{
+	{
+		name:  "raw string literal containing braces and tags",
+		input: "fmt.Sprint(`}</div>{`)",
+	},
+	{
+		name:  "raw string literal containing carriage returns",
+		input: "fmt.Sprint(`a\r\n}\r\nb`, x)",
+	},
+	{
+		name:  "rune literals",
+		input: "strings.Trim(s, string('}')+string('`')+string('\\''))",
+	},
+	{
+		name:  "nested composite literals containing literals",
+		input: "[]struct{ A []string }{{A: []string{`}`, \"{\", string('}')}}}[0].A[0]",
+	},
 	{
 		name:  "string literal",
 		input: `"hello"`,
@@ -384,6 +408,22 @@ func TestExpression(t *testing.T) {
 }
 
 var templExpressionTests = []testInput{
+	{
+		name:  "raw string literal containing braces and tags",
+		input: "fmt.Sprint(`}</div>{`)",
+	},
+	{
+		name:  "raw string literal containing carriage returns",
+		input: "fmt.Sprint(`a\r\n}\r\nb`, x)",
+	},
+	{
+		name:  "rune literals",
+		input: "strings.Trim(s, string('}')+string('`')+string('\\''))",
+	},
+	{
+		name:  "nested composite literals containing literals",
+		input: "[]struct{ A []string }{{A: []string{`}`, \"{\", string('}')}}}[0].A[0]",
+	},
 	{
 		name:  "function call in package",
 		input: `components.Other()`,
@@ -570,6 +610,22 @@ func FuzzExpression(f *testing.F) {
 }
 
 var sliceArgsTests = []testInput{
+	{
+		name:  "raw string literal containing braces and tags",
+		input: "fmt.Sprint(`}</div>{`)",
+	},
+	{
+		name:  "raw string literal containing carriage returns",
+		input: "fmt.Sprint(`a\r\n}\r\nb`, x)",
+	},
+	{
+		name:  "rune literals",
+		input: "strings.Trim(s, string('}')+string('`')+string('\\''))",
+	},
+	{
+		name:  "nested composite literals containing literals",
+		input: "[]struct{ A []string }{{A: []string{`}`, \"{\", string('}')}}}[0].A[0]",
+	},
 	{
 		name:  "no input",
 		input: ``,
@@ -694,6 +750,10 @@ func TestChildren(t *testing.T) {
 }
 
 var funcTests = []testInput{
+	{
+		name:  "default values in raw strings aren't allowed, but carriage returns in comments are",
+		input: "myfunc(a string /* `\r\n` */)",
+	},
 	{
 		name:  "void func",
 		input: `myfunc()`,
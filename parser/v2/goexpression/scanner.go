@@ -108,10 +108,12 @@ func (ep *ExpressionParser) Insert(
 			if ep.Previous == token.RPAREN {
 				return true, nil
 			}
-			// Previous was ident that isn't a type.
+			// Previous was ident or index that isn't a type.
 			// In `name {`, `name` is considered to be a variable.
 			// In `name{`, `name` is considered to be a type name.
-			if ep.Previous == token.IDENT && ep.hasSpaceBeforeCurrentToken(pos) {
+			// In `names[0] {`, `names[0]` is considered to be an index expression.
+			// In `List[T]{`, `List[T]` is considered to be a generic type name.
+			if (ep.Previous == token.IDENT || ep.Previous == token.RBRACK) && ep.hasSpaceBeforeCurrentToken(pos) {
 				return true, nil
 			}
 		}
@@ -149,7 +151,9 @@ func (ep *ExpressionParser) Insert(
 	// e.g. "package.name", "typeName{field: value}.name()".
 	// or "call().name", "call().name()".
 	// But not "package .name" or "typeName{field: value} .name()".
-	if tok == token.IDENT && (ep.Previous == token.PERIOD || isCloser(ep.Previous)) {
+	// The keywords of composite literal types are allowed in the same places as identifiers,
+	// e.g. "map[string]templ.Component{}[name]", or "[]struct{ c templ.Component }{}[0].c".
+	if (tok == token.IDENT || isTypeKeyword(tok)) && (ep.Previous == token.PERIOD || isCloser(ep.Previous)) {
 		if isCloser(ep.Previous) && ep.hasSpaceBeforeCurrentToken(pos) {
 			// This token starts later than the last ending, which means
 			// there's a space.
@@ -178,3 +182,7 @@ func isCloser(tok token.Token) bool {
 	_, ok := goTokenCloseToOpen[tok]
 	return ok
 }
+
+func isTypeKeyword(tok token.Token) bool {
+	return tok == token.MAP || tok == token.STRUCT || tok == token.INTERFACE || tok == token.CHAN
+}
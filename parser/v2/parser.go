@@ -19,6 +19,7 @@ var template = parse.Func(func(pi *parse.Input) (r *HTMLTemplate, matched bool,
 	}
 	r = &HTMLTemplate{
 		Expression: te.Expression,
+		Extends:    te.Extends,
 	}
 	defer func() {
 		r.Range = NewRange(start, pi.Position())
@@ -39,6 +39,10 @@ func (p rawElementParser) Parse(pi *parse.Input) (n Node, ok bool, err error) {
 		pi.Seek(start)
 		return
 	}
+	if attributesHaveComments(e.Attributes) {
+		err = parse.Error(fmt.Sprintf("<%s>: comments aren't supported between the attributes of <script> and <style> elements", e.Name), pi.Position())
+		return
+	}
 
 	// Optional whitespace.
 	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
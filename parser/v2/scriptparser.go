@@ -45,6 +45,10 @@ func (p scriptElementParser) Parse(pi *parse.Input) (n Node, ok bool, err error)
 		pi.Seek(start)
 		return
 	}
+	if attributesHaveComments(e.Attributes) {
+		err = parse.Error("<script>: comments aren't supported between the attributes of <script> and <style> elements", pi.Position())
+		return
+	}
 
 	// Optional whitespace.
 	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
@@ -59,7 +63,8 @@ func (p scriptElementParser) Parse(pi *parse.Input) (n Node, ok bool, err error)
 	}
 
 	// If there's a type attribute and it's not a JS attribute (e.g. text/javascript), we need to parse the contents as raw text.
-	if !hasJavaScriptType(e.Attributes) {
+	// JSON data islands (e.g. application/json) are parsed like JS, so that Go expressions in them are JSON encoded.
+	if !hasJavaScriptType(e.Attributes) && !hasJSONType(e.Attributes) {
 		var contents string
 		if contents, ok, err = parse.StringUntil(jsEndTag).Parse(pi); err != nil || !ok {
 			return e, true, parse.Error("<script>: expected end tag not present", pi.Position())
@@ -174,6 +179,44 @@ var javaScriptTypeAttributeValues = []string{
 }
 
 func hasJavaScriptType(attrs []Attribute) bool {
+	typ, ok := scriptType(attrs)
+	if !ok {
+		// If there's no type attribute, it's JavaScript.
+		return true
+	}
+	for _, v := range javaScriptTypeAttributeValues {
+		if strings.EqualFold(typ, v) {
+			return true
+		}
+	}
+	// If there's a type attribute but the value doesn't match any
+	// known JavaScript type, it's not JavaScript.
+	return false
+}
+
+var jsonTypeAttributeValues = []string{
+	"application/json",
+	"importmap",
+	"speculationrules",
+}
+
+// hasJSONType returns true if the script element is a JSON data island, e.g. application/json,
+// or a JSON based format such as application/ld+json.
+func hasJSONType(attrs []Attribute) bool {
+	typ, ok := scriptType(attrs)
+	if !ok {
+		return false
+	}
+	for _, v := range jsonTypeAttributeValues {
+		if strings.EqualFold(typ, v) {
+			return true
+		}
+	}
+	return strings.HasSuffix(strings.ToLower(typ), "+json")
+}
+
+// scriptType returns the value of the constant type attribute of a script element.
+func scriptType(attrs []Attribute) (typ string, ok bool) {
 	for _, attr := range attrs {
 		ca, isCA := attr.(*ConstantAttribute)
 		if !isCA {
@@ -183,20 +226,11 @@ func hasJavaScriptType(attrs []Attribute) bool {
 		if !isCAKey {
 			continue
 		}
-		if !strings.EqualFold(caKey.Name, "type") {
-			continue
-		}
-		for _, v := range javaScriptTypeAttributeValues {
-			if strings.EqualFold(ca.Value, v) {
-				return true
-			}
+		if strings.EqualFold(caKey.Name, "type") {
+			return ca.Value, true
 		}
-		// If there's a type attribute but the value doesn't match any
-		// known JavaScript type, it's not JavaScript.
-		return false
 	}
-	// If there's no type attribute, it's JavaScript.
-	return true
+	return "", false
 }
 
 var (
@@ -313,6 +313,44 @@ but it's commented out */
 				},
 			},
 		},
+		{
+			name:  "script: go expression in a JSON data island",
+			input: `<script type="application/ld+json">{"name": "{{ name }}", "tags": {{ tags }}}</script>`,
+			expected: &ScriptElement{
+				Attributes: []Attribute{&ConstantAttribute{
+					Value: "application/ld+json",
+					Key: ConstantAttributeKey{
+						Name: "type", NameRange: Range{
+							From: Position{Index: 8, Line: 0, Col: 8},
+							To:   Position{Index: 12, Line: 0, Col: 12},
+						},
+					},
+				}},
+				Contents: []ScriptContents{
+					NewScriptContentsScriptCode(`{"name": "`),
+					NewScriptContentsGo(&GoCode{
+						Expression: Expression{
+							Value: "name",
+							Range: Range{
+								From: Position{Index: 48, Line: 0, Col: 48},
+								To:   Position{Index: 52, Line: 0, Col: 52},
+							},
+						},
+					}, true),
+					NewScriptContentsScriptCode(`", "tags": `),
+					NewScriptContentsGo(&GoCode{
+						Expression: Expression{
+							Value: "tags",
+							Range: Range{
+								From: Position{Index: 69, Line: 0, Col: 69},
+								To:   Position{Index: 73, Line: 0, Col: 73},
+							},
+						},
+					}, false),
+					NewScriptContentsScriptCode(`}`),
+				},
+			},
+		},
 		{
 			name: "script: non js content is parsed raw",
 			input: `<script type="text/hyperscript">
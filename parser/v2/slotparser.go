@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/a-h/parse"
+)
+
+// { slot "header" }
+var slotExpressionParser = parse.All(
+	openBraceWithOptionalPadding,
+	parse.OptionalWhitespace,
+	parse.String("slot "),
+	parse.OptionalWhitespace,
+)
+
+var slotExpression = parse.Func(func(pi *parse.Input) (n Node, matched bool, err error) {
+	start := pi.Index()
+	if _, matched, err = slotExpressionParser.Parse(pi); err != nil || !matched {
+		pi.Seek(start)
+		return nil, false, err
+	}
+
+	// Once we have the prefix, we must have a quoted slot name.
+	nameStart := pi.Index()
+	quoted, ok, err := parse.StringFrom(
+		parse.Rune('"'),
+		parse.StringUntil(parse.Rune('"')),
+		parse.Rune('"'),
+	).Parse(pi)
+	if err != nil || !ok {
+		return nil, true, parse.Error(`slot: expected quoted slot name, e.g. { slot "header" }`, pi.PositionAt(nameStart))
+	}
+	r := &SlotExpression{
+		NameRange: NewRange(pi.PositionAt(nameStart), pi.Position()),
+	}
+	if r.Name, err = strconv.Unquote(quoted); err != nil {
+		return r, true, parse.Error("slot: invalid slot name: "+err.Error(), pi.PositionAt(nameStart))
+	}
+
+	// }
+	if _, _, err = parse.OptionalWhitespace.Parse(pi); err != nil {
+		return r, true, err
+	}
+	if _, matched, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("slot: missing close brace", pi.Position())
+	}
+	return r, true, nil
+})
+
+// slot header {
+var slotDefinitionStartParser = parse.All(
+	parse.OptionalWhitespace,
+	parse.String("slot "),
+	parse.OptionalWhitespace,
+)
+
+var slotNameParser = parse.StringFrom(
+	parse.Letter,
+	parse.StringFrom(parse.AtMost(1000, parse.Any(parse.Letter, parse.ZeroToNine, parse.Rune('_')))),
+)
+
+var slotDefinition parse.Parser[Node] = slotDefinitionParser{}
+
+type slotDefinitionParser struct{}
+
+func (slotDefinitionParser) Parse(pi *parse.Input) (n Node, matched bool, err error) {
+	start := pi.Index()
+	if _, matched, err = slotDefinitionStartParser.Parse(pi); err != nil || !matched {
+		pi.Seek(start)
+		return nil, false, err
+	}
+
+	// The slot name must be a Go identifier, followed by an open brace.
+	// If it isn't, this is just text that starts with "slot".
+	nameStart := pi.Index()
+	name, ok, err := slotNameParser.Parse(pi)
+	if err != nil || !ok {
+		pi.Seek(start)
+		return nil, false, err
+	}
+	r := &SlotDefinition{
+		Name:      name,
+		NameRange: NewRange(pi.PositionAt(nameStart), pi.Position()),
+	}
+	if _, matched, err = parse.All(openBraceWithOptionalPadding, parse.NewLine).Parse(pi); err != nil || !matched {
+		pi.Seek(start)
+		return nil, false, err
+	}
+
+	// Node contents.
+	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "slot closing brace")
+	var nodes Nodes
+	if nodes, matched, err = tnp.Parse(pi); err != nil || !matched {
+		r.Children = nodes.Nodes
+		return r, true, parse.Error("slot: expected nodes, but none were found", pi.Position())
+	}
+	r.Children = nodes.Nodes
+
+	// Read the required closing brace.
+	if _, matched, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("slot: "+unterminatedMissingEnd, pi.Position())
+	}
+
+	return r, true, nil
+}
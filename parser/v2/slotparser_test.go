@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSlotExpressionParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *SlotExpression
+	}{
+		{
+			name:  "standard",
+			input: `{ slot "header" }`,
+			expected: &SlotExpression{
+				Name: "header",
+				NameRange: Range{
+					From: Position{Index: 7, Line: 0, Col: 7},
+					To:   Position{Index: 15, Line: 0, Col: 15},
+				},
+			},
+		},
+		{
+			name:  "condensed",
+			input: `{slot "header"}`,
+			expected: &SlotExpression{
+				Name: "header",
+				NameRange: Range{
+					From: Position{Index: 6, Line: 0, Col: 6},
+					To:   Position{Index: 14, Line: 0, Col: 14},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			result, ok, err := slotExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSlotExpressionParserErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "unquoted name",
+			input: `{ slot header }`,
+		},
+		{
+			name:  "missing close brace",
+			input: `{ slot "header" `,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := slotExpression.Parse(parse.NewInput(tt.input))
+			if !ok {
+				t.Error("expected a match")
+			}
+			if err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestSlotDefinitionParser(t *testing.T) {
+	input := `slot header {
+	<h1>Title</h1>
+}`
+	pi := parse.NewInput(input)
+	result, ok, err := slotDefinition.Parse(pi)
+	if err != nil {
+		t.Fatalf("parser error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("failed to parse at %d", pi.Index())
+	}
+	sd, isSlot := result.(*SlotDefinition)
+	if !isSlot {
+		t.Fatalf("expected *SlotDefinition, got %T", result)
+	}
+	expectedRange := Range{
+		From: Position{Index: 5, Line: 0, Col: 5},
+		To:   Position{Index: 11, Line: 0, Col: 11},
+	}
+	if diff := cmp.Diff(expectedRange, sd.NameRange); diff != "" {
+		t.Error(diff)
+	}
+	if sd.Name != "header" {
+		t.Errorf("expected name %q, got %q", "header", sd.Name)
+	}
+	if len(stripWhitespaceNodes(sd.Children)) != 1 {
+		t.Errorf("expected a single child element, got %#v", sd.Children)
+	}
+}
+
+func TestSlotDefinitionParserText(t *testing.T) {
+	for _, input := range []string{"slot machine", "slot", "slot 123 {\n}"} {
+		t.Run(input, func(t *testing.T) {
+			pi := parse.NewInput(input)
+			_, ok, err := slotDefinition.Parse(pi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok {
+				t.Error("expected text not to be parsed as a slot definition")
+			}
+			if pi.Index() != 0 {
+				t.Errorf("expected the input not to be consumed, got index %d", pi.Index())
+			}
+		})
+	}
+}
+
+// elementVisitor only implements the VisitElement method of Visitor, and doesn't implement
+// SlotVisitor.
+type elementVisitor struct {
+	Visitor
+	names []string
+}
+
+func (v *elementVisitor) VisitElement(e *Element) error {
+	v.names = append(v.names, e.Name)
+	return nil
+}
+
+func TestSlotVisitorIsOptional(t *testing.T) {
+	v := &elementVisitor{}
+	if err := (&SlotExpression{Name: "header"}).Visit(v); err != nil {
+		t.Fatalf("unexpected error visiting slot expression: %v", err)
+	}
+	sd := &SlotDefinition{Name: "header", Children: []Node{&Element{Name: "h1"}, &Element{Name: "p"}}}
+	if err := sd.Visit(v); err != nil {
+		t.Fatalf("unexpected error visiting slot definition: %v", err)
+	}
+	if diff := cmp.Diff([]string{"h1", "p"}, v.names); diff != "" {
+		t.Errorf("expected the children of the slot definition to be visited:\n%s", diff)
+	}
+}
+
+func stripWhitespaceNodes(nodes []Node) (output []Node) {
+	for _, n := range nodes {
+		if _, isWhitespace := n.(*Whitespace); !isWhitespace {
+			output = append(output, n)
+		}
+	}
+	return output
+}
@@ -132,3 +132,22 @@ func (sm *SourceMap) SourcePositionFromTarget(line, col uint32) (src Position, o
 		col--
 	}
 }
+
+// SourceRangeFromTarget looks up the source range of a target range, e.g. to map an edit of
+// the target back to the source. Unlike SourcePositionFromTarget, both ends of the range must
+// be mapped exactly, and the range must span the same number of lines and bytes in the source.
+func (sm *SourceMap) SourceRangeFromTarget(tgt Range) (src Range, ok bool) {
+	if src.From, ok = sm.TargetLinesToSource[tgt.From.Line][tgt.From.Col]; !ok {
+		return src, false
+	}
+	if src.To, ok = sm.TargetLinesToSource[tgt.To.Line][tgt.To.Col]; !ok {
+		return src, false
+	}
+	if src.To.Line-src.From.Line != tgt.To.Line-tgt.From.Line {
+		return src, false
+	}
+	if tgt.From.Line == tgt.To.Line && src.To.Col-src.From.Col != tgt.To.Col-tgt.From.Col {
+		return src, false
+	}
+	return src, true
+}
@@ -172,3 +172,46 @@ func keys[K comparable, V any](m map[K]V) (keys []K) {
 	}
 	return
 }
+
+func TestSourceMapRange(t *testing.T) {
+	sm := NewSourceMap()
+	// templ name() { -> func name() templ.Component {
+	sm.Add(NewExpression("name()", pos(6, 2, 6), pos(12, 2, 12)),
+		Range{From: NewPosition(100, 10, 5), To: NewPosition(106, 10, 11)})
+
+	tests := []struct {
+		name       string
+		target     Range
+		expected   Range
+		expectedOK bool
+	}{
+		{
+			name:       "ranges within an expression are mapped",
+			target:     Range{From: NewPosition(0, 10, 5), To: NewPosition(0, 10, 9)},
+			expected:   Range{From: NewPosition(6, 2, 6), To: NewPosition(10, 2, 10)},
+			expectedOK: true,
+		},
+		{
+			name:   "ranges that start outside of an expression are not mapped",
+			target: Range{From: NewPosition(0, 10, 0), To: NewPosition(0, 10, 9)},
+		},
+		{
+			name:   "ranges that end outside of an expression are not mapped",
+			target: Range{From: NewPosition(0, 10, 5), To: NewPosition(0, 10, 20)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := sm.SourceRangeFromTarget(tt.target)
+			if ok != tt.expectedOK {
+				t.Fatalf("expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -35,6 +35,27 @@ func TestStringExpressionParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "raw string literal containing carriage returns",
+			input: "{ `a\r\n}` }",
+			expected: &StringExpression{
+				Expression: Expression{
+					Value: "`a\r\n}`",
+					Range: Range{
+						From: Position{
+							Index: 2,
+							Line:  0,
+							Col:   2,
+						},
+						To: Position{
+							Index: 8,
+							Line:  1,
+							Col:   2,
+						},
+					},
+				},
+			},
+		},
 		{
 			name:  "no spaces",
 			input: `{"this"}`,
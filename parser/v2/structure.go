@@ -23,6 +23,8 @@ var (
 	_ Node = (*CallTemplateExpression)(nil)
 	_ Node = (*TemplElementExpression)(nil)
 	_ Node = (*ChildrenExpression)(nil)
+	_ Node = (*FragmentDefinition)(nil)
+	_ Node = (*CacheExpression)(nil)
 	_ Node = (*IfExpression)(nil)
 	_ Node = (*SwitchExpression)(nil)
 	_ Node = (*ForExpression)(nil)
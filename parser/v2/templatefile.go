@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -43,7 +46,72 @@ func isGoIdentifier(s string) bool {
 }
 
 func ParseString(template string) (*TemplateFile, error) {
-	tf, matched, err := NewTemplateFileParser("main").Parse(parse.NewInput(template))
+	return parseString(template, 0)
+}
+
+// ParseBytesMaxErrors is the number of errors that ParseBytes recovers from before it stops.
+const ParseBytesMaxErrors = 10
+
+// ParseBytes parses a template file. Unlike ParseString, parsing continues after an error in
+// a template, CSS template or script template, from the next declaration, so that up to
+// ParseBytesMaxErrors errors are returned together. The file contains the nodes that were
+// parsed, including those that have errors.
+//
+// ParseBytes is intended for tools, and fuzz tests.
+func ParseBytes(b []byte) (*TemplateFile, error) {
+	return parseString(string(b), ParseBytesMaxErrors)
+}
+
+// ParsePartial parses a template file, recovering from every syntax error, so that tools such
+// as the LSP can use the rest of the file while it's being edited. The source that couldn't be
+// parsed is added to the file as ErrorNode nodes, and a diagnostic is returned for each error.
+func ParsePartial(template string) (tf *TemplateFile, diagnostics []Diagnostic) {
+	tf, err := parseString(template, math.MaxInt)
+	if tf == nil {
+		tf = &TemplateFile{}
+	}
+	if err == nil {
+		return tf, nil
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	for _, err := range errs {
+		d := Diagnostic{Message: err.Error()}
+		if pe, ok := asParseError(err); ok {
+			d.Message = pe.Msg
+			d.Range = NewRange(pe.Pos, pe.Pos)
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return tf, diagnostics
+}
+
+func asParseError(err error) (pe parse.ParseError, ok bool) {
+	var unfe UntilNotFoundError
+	if errors.As(err, &unfe) {
+		return unfe.ParseError, true
+	}
+	ok = errors.As(err, &pe)
+	return pe, ok
+}
+
+func parseString(template string, maxErrors int) (*TemplateFile, error) {
+	// Parse without the byte order mark and carriage returns, so that positions are the same
+	// regardless of the editor used, and record them so that the formatter can restore them.
+	template, hasBOM := strings.CutPrefix(template, byteOrderMark)
+	crlf := usesCRLF(template)
+	if crlf {
+		template = strings.ReplaceAll(template, "\r\n", "\n")
+	}
+	p := NewTemplateFileParser("main")
+	p.MaxErrors = maxErrors
+	tf, matched, err := p.Parse(parse.NewInput(template))
+	if tf != nil {
+		tf.ByteOrderMark = hasBOM
+		tf.CRLF = crlf
+	}
 	if err != nil {
 		return tf, err
 	}
@@ -53,6 +121,14 @@ func ParseString(template string) (*TemplateFile, error) {
 	return tf, err
 }
 
+const byteOrderMark = "\ufeff"
+
+// usesCRLF returns true if the first line of s ends with a CRLF line ending.
+func usesCRLF(s string) bool {
+	i := strings.IndexByte(s, '\n')
+	return i > 0 && s[i-1] == '\r'
+}
+
 // NewTemplateFileParser creates a new TemplateFileParser.
 func NewTemplateFileParser(pkg string) TemplateFileParser {
 	return TemplateFileParser{
@@ -65,6 +141,9 @@ var ErrTemplateNotFound = errors.New("template not found")
 
 type TemplateFileParser struct {
 	DefaultPackage string
+	// MaxErrors is the number of errors in declarations that are recovered from, by skipping to
+	// the next declaration, before parsing stops. If it's zero, parsing stops at the first error.
+	MaxErrors int
 }
 
 var legacyPackageParser = parse.String("{% package")
@@ -109,15 +188,74 @@ func (p TemplateFileParser) Parse(pi *parse.Input) (tf *TemplateFile, matched bo
 	// Strip any whitespace between the template declaration and the first template.
 	_, _, _ = parse.OptionalWhitespace.Parse(pi)
 
+	// Read the whole input, to keep the source of error nodes.
+	index := pi.Index()
+	pi.Seek(0)
+	input, _ := pi.Peek(-1)
+	pi.Seek(index)
+	var errs []error
+	// fail records the error, and skips to the next declaration, keeping the skipped source
+	// from the index from as an error node.
+	fail := func(start, from int, err error) (stop bool) {
+		errs = append(errs, err)
+		stop = len(errs) > p.MaxErrors || !seekNextDeclaration(pi, start)
+		if stop {
+			pi.Seek(len(input))
+		}
+		to := pi.Index()
+		if from = min(from, to); from < to {
+			tf.Nodes = append(tf.Nodes, &ErrorNode{
+				Value: input[from:to],
+				Range: NewRange(pi.PositionAt(from), pi.PositionAt(to)),
+				Err:   err,
+			})
+		}
+		return stop
+	}
+	previous := -1
 outer:
 	for {
-		// Optional templates, CSS, and script templates.
+		start := pi.Index()
+		// Each declaration consumes input, so if nothing was consumed, parsing can't continue.
+		if start == previous {
+			if fail(start, start, parse.Error("unexpected declaration", pi.Position())) {
+				break
+			}
+			continue
+		}
+		previous = start
+
+		// Optional context declarations, templates, CSS, and script templates.
+		// templ ctx Name Type
+		var cd *ContextDeclaration
+		cd, matched, err = contextDeclarationParser.Parse(pi)
+		if err != nil {
+			if fail(start, start, err) {
+				break
+			}
+			continue
+		}
+		if matched {
+			tf.Nodes = append(tf.Nodes, cd)
+			_, _, _ = parse.OptionalWhitespace.Parse(pi)
+			continue
+		}
+
 		// templ Name(p Parameter)
 		var tn *HTMLTemplate
 		tn, matched, err = template.Parse(pi)
 		if err != nil {
-			tf.Nodes = append(tf.Nodes, tn)
-			return tf, false, err
+			// The template contains the nodes that were parsed before the error. If the
+			// signature couldn't be parsed, there's no template.
+			from := start
+			if tn != nil {
+				tf.Nodes = append(tf.Nodes, tn)
+				from = max(from, int(tn.Range.To.Index))
+			}
+			if fail(start, from, err) {
+				break
+			}
+			continue
 		}
 		if matched {
 			tf.Nodes = append(tf.Nodes, tn)
@@ -129,7 +267,10 @@ outer:
 		var cn *CSSTemplate
 		cn, matched, err = cssParser.Parse(pi)
 		if err != nil {
-			return tf, false, err
+			if fail(start, start, err) {
+				break
+			}
+			continue
 		}
 		if matched {
 			tf.Nodes = append(tf.Nodes, cn)
@@ -141,7 +282,10 @@ outer:
 		var sn *ScriptTemplate
 		sn, matched, err = scriptTemplateParser.Parse(pi)
 		if err != nil {
-			return tf, false, err
+			if fail(start, start, err) {
+				break
+			}
+			continue
 		}
 		if matched {
 			tf.Nodes = append(tf.Nodes, sn)
@@ -160,8 +304,7 @@ outer:
 			if l, matched, err = stringUntilNewLineOrEOF.Parse(pi); err != nil {
 				return
 			}
-			hasTemplatePrefix := strings.HasPrefix(l, "templ ") || strings.HasPrefix(l, "css ") || strings.HasPrefix(l, "script ")
-			if hasTemplatePrefix && strings.Contains(l, "(") {
+			if isDeclaration(l) {
 				// Unread the line.
 				pi.Seek(last)
 				// Take the code so far.
@@ -190,6 +333,50 @@ outer:
 			}
 		}
 	}
-
+	if len(errs) == 1 {
+		return tf, false, errs[0]
+	}
+	if len(errs) > 1 {
+		return tf, false, errors.Join(errs...)
+	}
 	return tf, true, nil
 }
+
+// isDeclaration returns true if the line starts a context declaration, template, CSS template
+// or script template.
+func isDeclaration(line string) bool {
+	hasTemplatePrefix := strings.HasPrefix(line, "templ ") || strings.HasPrefix(line, "css ") || strings.HasPrefix(line, "script ")
+	return hasTemplatePrefix && strings.Contains(line, "(") || strings.HasPrefix(line, "templ ctx ")
+}
+
+// seekNextDeclaration moves to the start of the first declaration after the line at start,
+// returning false if there isn't one.
+func seekNextDeclaration(pi *parse.Input, start int) bool {
+	pi.Seek(start)
+	rest, _ := pi.Peek(-1)
+	offset := 0
+	for {
+		i := strings.IndexByte(rest[offset:], '\n')
+		if i < 0 {
+			pi.Seek(start + len(rest))
+			return false
+		}
+		offset += i + 1
+		if isDeclaration(rest[offset:]) {
+			pi.Seek(start + offset)
+			return true
+		}
+	}
+}
+
+// crlfWriter converts LF line endings to CRLF.
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (cw crlfWriter) Write(p []byte) (n int, err error) {
+	if _, err = cw.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
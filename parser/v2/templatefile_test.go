@@ -2,6 +2,7 @@ package parser
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -203,6 +204,39 @@ templ Hello(name string) {
 			t.Errorf("expected range %v, got %v\n%s", expectedIfExpressionRange, ie.Expression.Range, diff)
 		}
 	})
+	t.Run("byte order marks and CRLF line endings don't change positions", func(t *testing.T) {
+		lf := "package main\n\ntempl Hello(name string) {\n\t<div>{ name }</div>\n}\n"
+		crlf := "\ufeff" + strings.ReplaceAll(lf, "\n", "\r\n")
+		expected, err := ParseString(lf)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		actual, err := ParseString(crlf)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		if !actual.ByteOrderMark || !actual.CRLF {
+			t.Errorf("expected the byte order mark and CRLF line endings to be recorded, got %v and %v", actual.ByteOrderMark, actual.CRLF)
+		}
+		actual.ByteOrderMark, actual.CRLF = false, false
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("byte order marks and CRLF line endings are preserved by Write", func(t *testing.T) {
+		input := "\ufeffpackage main\r\n\r\ntempl Hello() {\r\n\t<div>Hello</div>\r\n}\r\n"
+		tf, err := ParseString(input)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		var sb strings.Builder
+		if err = tf.Write(&sb); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+		if diff := cmp.Diff(input, sb.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
 }
 
 func TestDefaultPackageName(t *testing.T) {
@@ -241,3 +275,174 @@ func TestDefaultPackageName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBytes(t *testing.T) {
+	t.Run("valid templates are parsed", func(t *testing.T) {
+		tf, err := ParseBytes([]byte("package main\n\ntempl A() {\n\t<div></div>\n}\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tf.Nodes) != 1 {
+			t.Errorf("expected 1 node, got %d", len(tf.Nodes))
+		}
+	})
+	t.Run("parsing continues after errors at the next declaration", func(t *testing.T) {
+		input := `package main
+
+templ A() {
+	<div>
+}
+
+templ B() {
+	<span></span>
+}
+
+templ C() {
+	<input value={ "a" b/>
+}
+
+css red() {
+	color: red;
+}
+`
+		tf, err := ParseBytes([]byte(input))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if msg := err.Error(); !strings.Contains(msg, "<div>: close tag not found") || !strings.Contains(msg, "missing closing brace: line 12") {
+			t.Errorf("expected the errors of A and C, got %v", err)
+		}
+		var names []string
+		for _, n := range tf.Nodes {
+			switch n := n.(type) {
+			case *HTMLTemplate:
+				names = append(names, n.Expression.Value)
+			case *CSSTemplate:
+				names = append(names, n.Name)
+			}
+		}
+		if diff := cmp.Diff([]string{"A()", "B()", "C()", "red"}, names); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("parsing stops after the maximum number of errors", func(t *testing.T) {
+		var sb strings.Builder
+		sb.WriteString("package main\n")
+		for range ParseBytesMaxErrors + 5 {
+			sb.WriteString("\ntempl A() {\n\t<div>\n}\n")
+		}
+		_, err := ParseBytes([]byte(sb.String()))
+		if n := strings.Count(err.Error(), "close tag not found"); n != ParseBytesMaxErrors+1 {
+			t.Errorf("expected %d errors, got %d", ParseBytesMaxErrors+1, n)
+		}
+	})
+	t.Run("ParseString stops at the first error", func(t *testing.T) {
+		_, err := ParseString("package main\n\ntempl A() {\n\t<div>\n}\n\ntempl B() {\n\t<p>\n}\n")
+		if err == nil || strings.Contains(err.Error(), "<p>") {
+			t.Errorf("expected only the first error, got %v", err)
+		}
+	})
+}
+
+func TestParsePartial(t *testing.T) {
+	t.Run("files without errors have no diagnostics", func(t *testing.T) {
+		tf, diagnostics := ParsePartial("package main\n\ntempl A() {\n\t<div></div>\n}\n")
+		if len(diagnostics) != 0 {
+			t.Errorf("expected no diagnostics, got %v", diagnostics)
+		}
+		if len(tf.Nodes) != 1 {
+			t.Errorf("expected 1 node, got %d", len(tf.Nodes))
+		}
+	})
+	t.Run("source that can't be parsed is kept as error nodes", func(t *testing.T) {
+		input := `package main
+
+css red() {
+	color red
+}
+
+templ A() {
+	<span></span>
+}
+
+templ B() {
+	<input value={ "a" b/>
+}
+
+templ C() {
+	<div></div>
+}
+`
+		tf, diagnostics := ParsePartial(input)
+		if len(diagnostics) != 2 {
+			t.Fatalf("expected 2 diagnostics, got %v", diagnostics)
+		}
+		for _, d := range diagnostics {
+			if d.Range.From.Line == 0 {
+				t.Errorf("expected the diagnostic to have a position, got %+v", d)
+			}
+		}
+		var types []string
+		for _, n := range tf.Nodes {
+			types = append(types, reflect.TypeOf(n).Elem().Name())
+		}
+		expectedTypes := []string{"ErrorNode", "HTMLTemplate", "HTMLTemplate", "ErrorNode", "HTMLTemplate"}
+		if diff := cmp.Diff(expectedTypes, types); diff != "" {
+			t.Fatal(diff)
+		}
+		css := tf.Nodes[0].(*ErrorNode)
+		if !strings.HasPrefix(css.Value, "css red() {") || css.Range.From.Line != 2 || css.Err == nil {
+			t.Errorf("unexpected error node: %+v", css)
+		}
+		if c, ok := tf.Nodes[4].(*HTMLTemplate); !ok || c.Expression.Value != "C()" {
+			t.Errorf("expected template C, got %#v", tf.Nodes[4])
+		}
+	})
+	t.Run("the parsed part of a template is kept, followed by an error node", func(t *testing.T) {
+		input := `package main
+
+templ A(name string) {
+	<div>{ name }</div>
+	<span { name }>
+}
+
+templ B() {
+}
+`
+		tf, diagnostics := ParsePartial(input)
+		if len(diagnostics) != 1 {
+			t.Fatalf("expected 1 diagnostic, got %v", diagnostics)
+		}
+		if len(tf.Nodes) != 3 {
+			t.Fatalf("expected 3 nodes, got %d", len(tf.Nodes))
+		}
+		a, ok := tf.Nodes[0].(*HTMLTemplate)
+		if !ok || a.Expression.Value != "A(name string)" {
+			t.Fatalf("expected template A, got %#v", tf.Nodes[0])
+		}
+		if _, ok := tf.Nodes[1].(*ErrorNode); !ok {
+			t.Errorf("expected an error node, got %T", tf.Nodes[1])
+		}
+		if b, ok := tf.Nodes[2].(*HTMLTemplate); !ok || b.Expression.Value != "B()" {
+			t.Errorf("expected template B, got %#v", tf.Nodes[2])
+		}
+	})
+	t.Run("incomplete signatures are kept as error nodes", func(t *testing.T) {
+		for _, signature := range []string{"templ foo(", "templ foo() extends", "templ foo() extends {"} {
+			t.Run(signature, func(t *testing.T) {
+				tf, diagnostics := ParsePartial("package main\n\n" + signature + "\n")
+				if len(diagnostics) == 0 {
+					t.Error("expected a diagnostic")
+				}
+				for _, n := range tf.Nodes {
+					if tn, ok := n.(*HTMLTemplate); ok && tn == nil {
+						t.Fatal("expected no nil templates")
+					}
+					if _, ok := n.(*ErrorNode); !ok {
+						t.Errorf("expected an error node, got %T", n)
+					}
+				}
+			})
+		}
+	})
+}
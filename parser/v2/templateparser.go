@@ -2,8 +2,10 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/a-h/parse"
+	"github.com/a-h/templ/parser/v2/goexpression"
 )
 
 // TemplateExpression.
@@ -14,6 +16,7 @@ import (
 // templ (data []string) Func(p Parameter) {
 type templateExpression struct {
 	Expression Expression
+	Extends    *Expression
 }
 
 var templateExpressionParser = parse.Func(func(pi *parse.Input) (r templateExpression, matched bool, err error) {
@@ -32,6 +35,16 @@ var templateExpressionParser = parse.Func(func(pi *parse.Input) (r templateExpre
 		return r, true, err
 	}
 
+	// templ Page() extends Base() {
+	if extends, ok := peekExtends(pi); ok {
+		pi.Take(len(extends))
+		var e Expression
+		if e, err = parseGo("templ extends", pi, goexpression.TemplExpression); err != nil {
+			return r, true, err
+		}
+		r.Extends = &e
+	}
+
 	// Eat " {\n".
 	if _, matched, err = parse.All(openBraceWithOptionalPadding, parse.StringFrom(parse.Optional(parse.NewLine))).Parse(pi); err != nil || !matched {
 		return r, true, parse.Error("templ: malformed templ expression, expected `templ functionName() {`", pi.PositionAt(start))
@@ -40,6 +53,21 @@ var templateExpressionParser = parse.Func(func(pi *parse.Input) (r templateExpre
 	return r, true, nil
 })
 
+// peekExtends returns the " extends " keyword and its surrounding whitespace, if it's next.
+func peekExtends(pi *parse.Input) (s string, ok bool) {
+	src, _ := pi.Peek(-1)
+	trimmed := strings.TrimLeft(src, " \t")
+	if len(trimmed) == len(src) || !strings.HasPrefix(trimmed, "extends") {
+		return "", false
+	}
+	rest := strings.TrimPrefix(trimmed, "extends")
+	afterKeyword := strings.TrimLeft(rest, " \t")
+	if len(afterKeyword) == len(rest) {
+		return "", false
+	}
+	return src[:len(src)-len(afterKeyword)], true
+}
+
 const (
 	unterminatedMissingCurly = `unterminated (missing closing '{\n') - to escape "for", "if", "switch" etc. with braces, e.g. '{ "for" }' - https://templ.guide/syntax-and-usage/statements#ifswitchfor-within-text`
 	unterminatedMissingEnd   = `missing end (expected '}') - https://templ.guide/syntax-and-usage/statements#ifswitchfor-within-text`
@@ -73,12 +101,18 @@ var templateNodeParsers = []parse.Parser[Node]{
 	ifExpression,           // if {}
 	forExpression,          // for {}
 	switchExpression,       // switch {}
+	slotDefinition,         // slot header {}
+	blockDefinition,        // block title {}
+	fragmentDefinition,     // @fragment "row" {}
+	cacheExpression,        // cache(key, ttl) {}
 	callTemplateExpression, // {! TemplateName(a, b, c) }
 	templElementExpression, // @TemplateName(a, b, c) { <div>Children</div> }
 	childrenExpression,     // { children... }
+	slotExpression,         // { slot "header" }
 	goCode,                 // {{ myval := x.myval }}
 	stringExpression,       // { "abc" }
 	whitespaceExpression,   // { " " }
+	textBlock,              // """
 	textParser,             // anything &amp; everything accepted...
 }
 
@@ -99,12 +133,13 @@ outer:
 		}
 
 		// Skip any nodes that we don't care about.
+		start := pi.Index()
 		for _, p := range templateNodeSkipParsers {
 			_, didMatchSkipParser, err := p.Parse(pi)
 			if err != nil {
 				return op, false, err
 			}
-			if didMatchSkipParser {
+			if didMatchSkipParser && pi.Index() > start {
 				continue outer
 			}
 		}
@@ -124,10 +159,17 @@ outer:
 				return op, true, err
 			}
 			if didMatchTemplateNode {
+				if tc, isTriviaCarrier := node.(TriviaCarrier); isTriviaCarrier && containsNonWhitespaceNodes(op.Nodes) {
+					tc.NodeTrivia().BlankLineBefore = blankLineAt(pi, start)
+				}
 				op.Nodes = append(op.Nodes, node)
 				break
 			}
 		}
+		// Prevent an infinite loop if a node parser matches without consuming input.
+		if didMatchTemplateNode && pi.Index() == start {
+			return op, true, parse.Error("unexpected node", pi.Position())
+		}
 		if didMatchTemplateNode {
 			continue
 		}
@@ -148,6 +190,40 @@ outer:
 	return op, true, nil
 }
 
+func containsNonWhitespaceNodes(nodes []Node) bool {
+	for _, n := range nodes {
+		if _, isWhitespace := n.(*Whitespace); !isWhitespace {
+			return true
+		}
+	}
+	return false
+}
+
+// blankLineAt returns true if the whitespace around the index of the input contains a blank
+// line.
+func blankLineAt(pi *parse.Input, index int) bool {
+	current := pi.Index()
+	defer pi.Seek(current)
+	var newLines int
+	// Count the new lines before the index, and then after it.
+	for _, step := range []int{-1, 1} {
+		i := index
+		if step < 0 {
+			i--
+		}
+		for ; pi.Seek(i); i += step {
+			c, ok := pi.Peek(1)
+			if !ok || strings.TrimSpace(c) != "" {
+				break
+			}
+			if c == "\n" {
+				newLines++
+			}
+		}
+	}
+	return newLines > 1
+}
+
 type UntilNotFoundError struct {
 	parse.ParseError
 }
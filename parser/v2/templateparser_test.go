@@ -40,6 +40,31 @@ func TestTemplateParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "template: extends",
+			input: `templ Page() extends Base("x") {
+}`,
+			expected: &HTMLTemplate{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 34, Line: 1, Col: 1},
+				},
+				Expression: Expression{
+					Value: "Page()",
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 12, Line: 0, Col: 12},
+					},
+				},
+				Extends: &Expression{
+					Value: `Base("x")`,
+					Range: Range{
+						From: Position{Index: 21, Line: 0, Col: 21},
+						To:   Position{Index: 30, Line: 0, Col: 30},
+					},
+				},
+			},
+		},
 		{
 			name: "template: with receiver",
 			input: `templ (data Data) Name() {
@@ -66,6 +91,42 @@ func TestTemplateParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "template: with type parameters",
+			input: `templ List[T any](items []T) {
+}`,
+			expected: &HTMLTemplate{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 32, Line: 1, Col: 1},
+				},
+				Expression: Expression{
+					Value: "List[T any](items []T)",
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 28, Line: 0, Col: 28},
+					},
+				},
+			},
+		},
+		{
+			name: "template: with constrained type parameters",
+			input: `templ Table[K comparable, V interface{ ~int | ~string }](rows map[K]V) {
+}`,
+			expected: &HTMLTemplate{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 74, Line: 1, Col: 1},
+				},
+				Expression: Expression{
+					Value: "Table[K comparable, V interface{ ~int | ~string }](rows map[K]V)",
+					Range: Range{
+						From: Position{Index: 6, Line: 0, Col: 6},
+						To:   Position{Index: 70, Line: 0, Col: 70},
+					},
+				},
+			},
+		},
 		{
 			name: "template: no spaces",
 			input: `templ Name(){
@@ -823,7 +884,7 @@ func TestTemplateParser(t *testing.T) {
 							To:   Position{Index: 47, Line: 1, Col: 7},
 						},
 						Attributes: []Attribute{&SpreadAttributes{
-							Expression{
+							Expression: Expression{
 								Value: "children",
 								Range: Range{
 									From: Position{
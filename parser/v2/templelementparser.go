@@ -19,6 +19,11 @@ func (p templElementExpressionParser) Parse(pi *parse.Input) (n Node, matched bo
 		return r, true, err
 	}
 
+	// Check for a spread of a slice of components, e.g. @items...
+	if _, r.Spread, err = parse.String("...").Parse(pi); err != nil {
+		return r, true, err
+	}
+
 	// Once we've got a start expression, check to see if there's an open brace for children. {\n.
 	var hasOpenBrace bool
 	_, hasOpenBrace, err = openBraceWithOptionalPadding.Parse(pi)
@@ -28,6 +33,10 @@ func (p templElementExpressionParser) Parse(pi *parse.Input) (n Node, matched bo
 	if !hasOpenBrace {
 		return r, true, nil
 	}
+	if r.Spread {
+		err = parse.Error("@"+r.Expression.Value+"...: a spread of components can't have children", pi.Position())
+		return r, true, err
+	}
 
 	// Once we've had the start of an element's children, we must conclude the block.
 
@@ -434,6 +434,34 @@ func TestTemplElementExpressionParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "templelement: a slice of components can be spread",
+			input: `@items...` + "\n",
+			expected: &TemplElementExpression{
+				Expression: Expression{
+					Value: "items",
+					Range: Range{
+						From: Position{1, 0, 1},
+						To:   Position{6, 0, 6},
+					},
+				},
+				Spread: true,
+			},
+		},
+		{
+			name:  "templelement: the result of a call can be spread",
+			input: `@p.Items()...<div>`,
+			expected: &TemplElementExpression{
+				Expression: Expression{
+					Value: "p.Items()",
+					Range: Range{
+						From: Position{1, 0, 1},
+						To:   Position{10, 0, 10},
+					},
+				},
+				Spread: true,
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -470,6 +498,12 @@ func TestTemplElementExpressionParserFailures(t *testing.T) {
     },
 `,
 		},
+		{
+			name: "templelement: a spread can't have children",
+			input: `@items... {
+	<div>Child</div>
+}`,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/a-h/parse"
+)
+
+const textBlockDelimiter = `"""`
+
+// """
+// Text
+// """
+var textBlock = parse.Func(func(pi *parse.Input) (n Node, ok bool, err error) {
+	from := pi.Position()
+	src, _ := pi.Peek(-1)
+	if !strings.HasPrefix(src, textBlockDelimiter) {
+		return nil, false, nil
+	}
+
+	// The opening delimiter must be followed by a new line, otherwise it's just text.
+	firstLineEnd := strings.IndexByte(src, '\n')
+	if firstLineEnd < 0 || strings.TrimSpace(src[len(textBlockDelimiter):firstLineEnd]) != "" {
+		return nil, false, nil
+	}
+
+	// Read lines until the closing delimiter, which must be the first thing on its line.
+	var lines []string
+	offset := firstLineEnd + 1
+	for {
+		if offset >= len(src) {
+			return nil, true, parse.Error("text block: missing closing "+textBlockDelimiter, from)
+		}
+		line := src[offset:]
+		if end := strings.IndexByte(line, '\n'); end >= 0 {
+			line = line[:end]
+		}
+		if indent := len(line) - len(strings.TrimLeft(line, " \t")); strings.HasPrefix(line[indent:], textBlockDelimiter) {
+			lines = append(lines, line[:indent])
+			offset += indent + len(textBlockDelimiter)
+			break
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+		offset += len(line) + 1
+	}
+	pi.Take(offset)
+
+	tb := &TextBlock{Range: NewRange(from, pi.Position())}
+	tb.Value, tb.Indent = dedent(lines)
+
+	// Parse trailing whitespace.
+	ws, _, err := parse.Whitespace.Parse(pi)
+	if err != nil {
+		return tb, true, err
+	}
+	tb.TrailingSpace, err = NewTrailingSpace(ws)
+	if err != nil {
+		return tb, true, err
+	}
+	return tb, true, nil
+})
+
+// dedent removes the indentation shared by the lines, and joins them. The last line is the
+// indentation of the closing delimiter, so that text can be indented relative to it.
+func dedent(lines []string) (value string, shared int) {
+	shared = -1
+	for i, line := range lines {
+		if line == "" && i < len(lines)-1 {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if shared < 0 || indent < shared {
+			shared = indent
+		}
+	}
+	content := lines[:len(lines)-1]
+	for i, line := range content {
+		if line != "" {
+			content[i] = line[shared:]
+		}
+	}
+	return strings.Join(content, "\n"), shared
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTextBlockParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *TextBlock
+	}{
+		{
+			name:  "shared indentation is removed",
+			input: "\"\"\"\n\t\tone\n\t\t  two\n\t\t\"\"\"",
+			expected: &TextBlock{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 23, Line: 3, Col: 5},
+				},
+				Value:  "one\n  two",
+				Indent: 2,
+			},
+		},
+		{
+			name:  "text can be indented relative to the closing delimiter",
+			input: "\"\"\"\n\t\tone\n\t\"\"\"\n",
+			expected: &TextBlock{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 14, Line: 2, Col: 4},
+				},
+				Value:         "\tone",
+				Indent:        1,
+				TrailingSpace: SpaceVertical,
+			},
+		},
+		{
+			name:  "blank lines and trailing whitespace are normalised",
+			input: "\"\"\"\r\none  \r\n   \r\ntwo\r\n\"\"\"",
+			expected: &TextBlock{
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 25, Line: 4, Col: 3},
+				},
+				Value: "one\n\ntwo",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			result, ok, err := textBlock.Parse(input)
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("failed to parse at %d", input.Index())
+			}
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTextBlockParserText(t *testing.T) {
+	for _, input := range []string{`"""quoted"""`, `""`, "text"} {
+		t.Run(input, func(t *testing.T) {
+			_, ok, err := textBlock.Parse(parse.NewInput(input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok {
+				t.Error("expected text not to be parsed as a text block")
+			}
+		})
+	}
+}
+
+func TestTextBlockParserErrors(t *testing.T) {
+	_, ok, err := textBlock.Parse(parse.NewInput("\"\"\"\ntext\n"))
+	if !ok {
+		t.Error("expected a match")
+	}
+	if err == nil {
+		t.Error("expected an error, got nil")
+	}
+}
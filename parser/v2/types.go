@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"go/format"
 	"io"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -123,9 +125,29 @@ type TemplateFile struct {
 	Filepath string
 	// Nodes in the file.
 	Nodes []TemplateFileNode
+	// ByteOrderMark is true if the file started with a UTF-8 byte order mark. The parser
+	// removes it, and Write restores it.
+	ByteOrderMark bool
+	// CRLF is true if the file used CRLF line endings. The parser converts them to LF, and
+	// Write restores them.
+	CRLF bool
 }
 
 func (tf *TemplateFile) Write(w io.Writer) error {
+	return tf.WriteStyle(w, FormatStyle{})
+}
+
+// WriteStyle writes the formatted template file to w, using the style.
+func (tf *TemplateFile) WriteStyle(w io.Writer, style FormatStyle) error {
+	if tf.ByteOrderMark {
+		if _, err := io.WriteString(w, byteOrderMark); err != nil {
+			return err
+		}
+	}
+	if tf.CRLF {
+		w = crlfWriter{w: w}
+	}
+	w = styleWriter{Writer: w, style: style}
 	for _, n := range tf.Header {
 		if err := n.Write(w, 0); err != nil {
 			return err
@@ -157,6 +179,12 @@ func getNodeWhitespace(nodes []TemplateFileNode, i int) string {
 	if i == len(nodes)-1 {
 		return "\n"
 	}
+	// Group context declarations together, like Go var declarations.
+	if _, isContext := nodes[i].(*ContextDeclaration); isContext {
+		if _, nextIsContext := nodes[i+1].(*ContextDeclaration); nextIsContext {
+			return "\n"
+		}
+	}
 	if _, nextIsTemplate := nodes[i+1].(*HTMLTemplate); nextIsTemplate {
 		if e, isGo := nodes[i].(*TemplateFileGoExpression); isGo && endsWithComment(e.Expression.Value) {
 			return "\n"
@@ -170,7 +198,7 @@ func endsWithComment(s string) bool {
 	return strings.HasPrefix(lineSlice[len(lineSlice)-1], "//")
 }
 
-// TemplateFileNode can be a Template, CSS, Script or Go.
+// TemplateFileNode can be a Template, CSS, Script, context declaration or Go.
 type TemplateFileNode interface {
 	IsTemplateFileNode() bool
 	Write(w io.Writer, indent int) error
@@ -205,8 +233,29 @@ func (exp *TemplateFileGoExpression) Visit(v Visitor) error {
 	return v.VisitTemplateFileGoExpression(exp)
 }
 
+// ErrorNode is source that couldn't be parsed, because of a syntax error. The parser skips
+// from the error to the next declaration, so that the rest of the file can be parsed.
+type ErrorNode struct {
+	// Value is the source that was skipped.
+	Value string
+	Range Range
+	Err   error
+}
+
+func (n *ErrorNode) IsTemplateFileNode() bool { return true }
+
+// Write writes the source unchanged.
+func (n *ErrorNode) Write(w io.Writer, indent int) error {
+	_, err := io.WriteString(w, strings.TrimRight(n.Value, "\n"))
+	return err
+}
+
+func (n *ErrorNode) Visit(v Visitor) error {
+	return v.VisitErrorNode(n)
+}
+
 func writeIndent(w io.Writer, level int, s ...string) (err error) {
-	indent := strings.Repeat("\t", level)
+	indent := formatStyle(w).indent(level)
 	if _, err = io.WriteString(w, indent); err != nil {
 		return err
 	}
@@ -261,6 +310,27 @@ func (ws *Whitespace) Visit(v Visitor) error {
 	return v.VisitWhitespace(ws)
 }
 
+// ContextDeclaration declares a value that's shared with components through the context,
+// and generates typed functions to set and get it.
+//
+//	templ ctx Theme Theme
+//
+// generates SetTheme(ctx, Theme) and GetTheme(ctx).
+type ContextDeclaration struct {
+	Range Range
+	Name  Expression
+	Type  Expression
+}
+
+func (cd *ContextDeclaration) IsTemplateFileNode() bool { return true }
+func (cd *ContextDeclaration) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "templ ctx ", cd.Name.Value, " ", cd.Type.Value)
+}
+
+func (cd *ContextDeclaration) Visit(v Visitor) error {
+	return v.VisitContextDeclaration(cd)
+}
+
 // CSS definition.
 //
 //	css Name() {
@@ -361,9 +431,52 @@ func (c *ExpressionCSSProperty) Visit(v Visitor) error {
 	return v.VisitExpressionCSSProperty(c)
 }
 
+// A nested rule, scoped under the class of the CSS template.
+//
+//	&:hover {
+//	  color: #ff0000;
+//	}
+//	@media (max-width: 600px) {
+//	  display: none;
+//	}
+type CSSRule struct {
+	// Selector of the rule, e.g. "&:hover", "> li" or "@media (max-width: 600px)".
+	Selector   string
+	Properties []CSSProperty
+}
+
+func (r *CSSRule) IsCSSProperty() bool { return true }
+func (r *CSSRule) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, r.Selector, " {\n"); err != nil {
+		return err
+	}
+	for _, p := range r.Properties {
+		if err := p.Write(w, indent+1); err != nil {
+			return err
+		}
+	}
+	return writeIndent(w, indent, "}\n")
+}
+
+// IsAtRule returns true if the rule is an at-rule, such as @media, rather than a selector.
+func (r *CSSRule) IsAtRule() bool {
+	return strings.HasPrefix(r.Selector, "@")
+}
+
+// KeyframesName returns the name of the animation if the rule is a @keyframes rule.
+func (r *CSSRule) KeyframesName() (name string, ok bool) {
+	name, ok = strings.CutPrefix(r.Selector, "@keyframes ")
+	return strings.TrimSpace(name), ok
+}
+
+func (r *CSSRule) Visit(v Visitor) error {
+	return v.VisitCSSRule(r)
+}
+
 // <!DOCTYPE html>
 type DocType struct {
 	Value string
+	Trivia
 }
 
 func (dt *DocType) IsNode() bool { return true }
@@ -385,14 +498,30 @@ func (dt *DocType) Visit(v Visitor) error {
 type HTMLTemplate struct {
 	Range      Range
 	Expression Expression
-	Children   []Node
+	// Extends is the layout template that the template extends, if any.
+	//
+	//	templ Page() extends Base() {
+	//	  block title {
+	//	    Page
+	//	  }
+	//	}
+	Extends  *Expression
+	Children []Node
 }
 
 func (t *HTMLTemplate) IsTemplateFileNode() bool { return true }
 
 func (t *HTMLTemplate) Write(w io.Writer, indent int) error {
 	source := formatFunctionArguments(t.Expression.Value)
-	if err := writeIndent(w, indent, "templ ", string(source), " {\n"); err != nil {
+	if err := writeIndent(w, indent, "templ ", string(source)); err != nil {
+		return err
+	}
+	if t.Extends != nil {
+		if _, err := io.WriteString(w, " extends "+t.Extends.Value); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(w, " {\n"); err != nil {
 		return err
 	}
 	if err := writeNodesIndented(w, indent+1, t.Children); err != nil {
@@ -459,9 +588,111 @@ type WhitespaceTrailer interface {
 	Trailing() TrailingSpace
 }
 
+// Trivia is the source around a node or attribute that doesn't change the output, such as
+// blank lines, and comments between attributes. It's kept so that the formatter can write it
+// back.
+type Trivia struct {
+	// BlankLineBefore is true if the node is separated from the previous node by one or more
+	// blank lines.
+	BlankLineBefore bool
+	// Comments are the comments before an attribute. Comments between nodes are GoComment
+	// nodes.
+	Comments []*GoComment
+	// LineComment is the comment after an attribute, on the same line.
+	LineComment *GoComment
+	// TrailingComments are the comments after the last attribute of an element, or of the
+	// block of a conditional attribute.
+	TrailingComments []*GoComment
+}
+
+// NodeTrivia returns the trivia of the node or attribute.
+func (t *Trivia) NodeTrivia() *Trivia {
+	return t
+}
+
+// TriviaCarrier is implemented by the nodes and attributes that carry Trivia.
+type TriviaCarrier interface {
+	NodeTrivia() *Trivia
+}
+
+// copyTrivia returns a copy of the trivia that doesn't share comments with t.
+func (t Trivia) copyTrivia() Trivia {
+	t.Comments = slices.Clone(t.Comments)
+	t.TrailingComments = slices.Clone(t.TrailingComments)
+	return t
+}
+
+// writeLeadingAttributeTrivia writes the blank line and comments before an attribute, each
+// followed by a newline.
+func writeLeadingAttributeTrivia(w io.Writer, indent int, a Attribute) error {
+	tc, ok := a.(TriviaCarrier)
+	if !ok {
+		return nil
+	}
+	t := tc.NodeTrivia()
+	if t.BlankLineBefore {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	for _, c := range t.Comments {
+		if err := c.Write(w, indent); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTrailingAttributeTrivia writes the comment on the same line as an attribute, and the
+// comments after it, each preceded by a newline.
+func writeTrailingAttributeTrivia(w io.Writer, indent int, a Attribute) error {
+	tc, ok := a.(TriviaCarrier)
+	if !ok {
+		return nil
+	}
+	t := tc.NodeTrivia()
+	if t.LineComment != nil {
+		if _, err := io.WriteString(w, " "); err != nil {
+			return err
+		}
+		if err := t.LineComment.Write(w, 0); err != nil {
+			return err
+		}
+	}
+	for _, c := range t.TrailingComments {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+		if err := c.Write(w, indent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attributesHaveComments returns true if any of the attributes, or the attributes within
+// conditional attributes, have comments.
+func attributesHaveComments(attrs []Attribute) bool {
+	for _, a := range attrs {
+		if tc, ok := a.(TriviaCarrier); ok {
+			if t := tc.NodeTrivia(); len(t.Comments) > 0 || t.LineComment != nil || len(t.TrailingComments) > 0 {
+				return true
+			}
+		}
+		if ca, ok := a.(*ConditionalAttribute); ok && (attributesHaveComments(ca.Then) || attributesHaveComments(ca.Else)) {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	_ WhitespaceTrailer = (*Element)(nil)
 	_ WhitespaceTrailer = (*Text)(nil)
+	_ WhitespaceTrailer = (*TextBlock)(nil)
 	_ WhitespaceTrailer = (*StringExpression)(nil)
 )
 
@@ -473,6 +704,7 @@ type Text struct {
 	Value string
 	// TrailingSpace lists what happens after the text.
 	TrailingSpace TrailingSpace
+	Trivia
 }
 
 func (t Text) Trailing() TrailingSpace {
@@ -488,6 +720,51 @@ func (t *Text) Visit(v Visitor) error {
 	return v.VisitText(t)
 }
 
+// TextBlock is literal text that keeps its line breaks and indentation. The indentation
+// shared by the lines of the block, and the closing delimiter, isn't part of the text.
+//
+//	"""
+//	Roses are red,
+//	  violets are blue.
+//	"""
+type TextBlock struct {
+	Range Range
+	// Value of the text, without the shared indentation. Lines are separated by \n.
+	Value string
+	// Indent is the number of bytes of shared indentation that were removed from each line.
+	Indent int
+	// TrailingSpace lists what happens after the text block.
+	TrailingSpace TrailingSpace
+	Trivia
+}
+
+func (tb TextBlock) Trailing() TrailingSpace {
+	return tb.TrailingSpace
+}
+
+func (tb *TextBlock) IsNode() bool { return true }
+func (tb *TextBlock) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, `"""`+"\n"); err != nil {
+		return err
+	}
+	for _, line := range strings.Split(tb.Value, "\n") {
+		if line == "" {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeIndent(w, indent, line, "\n"); err != nil {
+			return err
+		}
+	}
+	return writeIndent(w, indent, `"""`)
+}
+
+func (tb *TextBlock) Visit(v Visitor) error {
+	return v.VisitTextBlock(tb)
+}
+
 // <a .../> or <div ...>...</div>
 type Element struct {
 	Name           string
@@ -497,6 +774,7 @@ type Element struct {
 	IndentChildren bool
 	TrailingSpace  TrailingSpace
 	NameRange      Range
+	Trivia
 }
 
 func (e Element) Trailing() TrailingSpace {
@@ -570,15 +848,19 @@ func (e *Element) Write(w io.Writer, indent int) error {
 	if err := writeIndent(w, indent, "<", e.Name); err != nil {
 		return err
 	}
+	indentAttrs := formatStyle(w).indentAttributes(e, indent)
 	for i := range e.Attributes {
 		a := e.Attributes[i]
 		// Only the conditional attributes get indented.
 		var attrIndent int
-		if e.IndentAttrs {
+		if indentAttrs {
 			if _, err := w.Write([]byte("\n")); err != nil {
 				return err
 			}
 			attrIndent = indent + 1
+			if err := writeLeadingAttributeTrivia(w, attrIndent, a); err != nil {
+				return err
+			}
 		} else {
 			if _, err := w.Write([]byte(" ")); err != nil {
 				return err
@@ -587,9 +869,14 @@ func (e *Element) Write(w io.Writer, indent int) error {
 		if err := a.Write(w, attrIndent); err != nil {
 			return err
 		}
+		if indentAttrs {
+			if err := writeTrailingAttributeTrivia(w, attrIndent, a); err != nil {
+				return err
+			}
+		}
 	}
 	var closeAngleBracketIndent int
-	if e.IndentAttrs {
+	if indentAttrs {
 		if _, err := w.Write([]byte("\n")); err != nil {
 			return err
 		}
@@ -641,17 +928,26 @@ func writeNodesIndented(w io.Writer, level int, nodes []Node) error {
 
 func writeNodes(w io.Writer, level int, nodes []Node, indent bool) error {
 	startLevel := level
+	// Blank lines are only kept between nodes that start on a new line.
+	var written bool
+	trailing := SpaceNone
 	for i, n := range nodes {
 		// Skip whitespace nodes.
 		if _, isWhitespace := n.(*Whitespace); isWhitespace {
 			continue
 		}
+		if tc, isTriviaCarrier := n.(TriviaCarrier); isTriviaCarrier && tc.NodeTrivia().BlankLineBefore && written && trailing == SpaceVertical {
+			if _, err := w.Write([]byte("\n")); err != nil {
+				return err
+			}
+		}
 		if err := n.Write(w, level); err != nil {
 			return err
 		}
+		written = true
 
 		// Apply trailing whitespace if present.
-		trailing := SpaceVertical
+		trailing = SpaceVertical
 		if wst, isWhitespaceTrailer := n.(WhitespaceTrailer); isWhitespaceTrailer {
 			trailing = wst.Trailing()
 		}
@@ -696,6 +992,14 @@ func isBlockNode(node Node) bool {
 		return true
 	case *ForExpression:
 		return true
+	case *SlotDefinition:
+		return true
+	case *BlockDefinition:
+		return true
+	case *FragmentDefinition:
+		return true
+	case *CacheExpression:
+		return true
 	case *Element:
 		return n.IsBlockElement() || n.IndentChildren
 	}
@@ -729,6 +1033,7 @@ type ScriptContents struct {
 type ScriptElement struct {
 	Attributes []Attribute
 	Contents   []ScriptContents
+	Trivia
 }
 
 func (se *ScriptElement) IsNode() bool { return true }
@@ -793,6 +1098,7 @@ type RawElement struct {
 	Name       string
 	Attributes []Attribute
 	Contents   string
+	Trivia
 }
 
 func (e *RawElement) IsNode() bool { return true }
@@ -859,6 +1165,7 @@ func (e ExpressionAttributeKey) String() string {
 // <hr noshade/>
 type BoolConstantAttribute struct {
 	Key AttributeKey
+	Trivia
 }
 
 func (bca *BoolConstantAttribute) String() string {
@@ -875,7 +1182,8 @@ func (bca *BoolConstantAttribute) Visit(v Visitor) error {
 
 func (bca *BoolConstantAttribute) Copy() Attribute {
 	return &BoolConstantAttribute{
-		Key: bca.Key,
+		Key:    bca.Key,
+		Trivia: bca.copyTrivia(),
 	}
 }
 
@@ -884,6 +1192,7 @@ type ConstantAttribute struct {
 	Key         AttributeKey
 	Value       string
 	SingleQuote bool
+	Trivia
 }
 
 func (ca *ConstantAttribute) String() string {
@@ -895,6 +1204,16 @@ func (ca *ConstantAttribute) String() string {
 }
 
 func (ca *ConstantAttribute) Write(w io.Writer, indent int) error {
+	switch formatStyle(w).AttributeQuote {
+	case QuoteDouble:
+		if ca.SingleQuote && !strings.Contains(ca.Value, `"`) {
+			return writeIndent(w, indent, (&ConstantAttribute{Key: ca.Key, Value: ca.Value}).String())
+		}
+	case QuoteSingle:
+		if !ca.SingleQuote && !strings.Contains(ca.Value, "'") {
+			return writeIndent(w, indent, (&ConstantAttribute{Key: ca.Key, Value: ca.Value, SingleQuote: true}).String())
+		}
+	}
 	return writeIndent(w, indent, ca.String())
 }
 
@@ -907,6 +1226,7 @@ func (ca *ConstantAttribute) Copy() Attribute {
 		Value:       ca.Value,
 		SingleQuote: ca.SingleQuote,
 		Key:         ca.Key,
+		Trivia:      ca.copyTrivia(),
 	}
 }
 
@@ -914,6 +1234,7 @@ func (ca *ConstantAttribute) Copy() Attribute {
 type BoolExpressionAttribute struct {
 	Key        AttributeKey
 	Expression Expression
+	Trivia
 }
 
 func (bea *BoolExpressionAttribute) String() string {
@@ -932,6 +1253,7 @@ func (bea *BoolExpressionAttribute) Copy() Attribute {
 	return &BoolExpressionAttribute{
 		Expression: bea.Expression,
 		Key:        bea.Key,
+		Trivia:     bea.copyTrivia(),
 	}
 }
 
@@ -939,6 +1261,7 @@ func (bea *BoolExpressionAttribute) Copy() Attribute {
 type ExpressionAttribute struct {
 	Key        AttributeKey
 	Expression Expression
+	Trivia
 }
 
 func (ea *ExpressionAttribute) String() string {
@@ -1001,10 +1324,14 @@ func (ea *ExpressionAttribute) Copy() Attribute {
 	return &ExpressionAttribute{
 		Expression: ea.Expression,
 		Key:        ea.Key,
+		Trivia:     ea.copyTrivia(),
 	}
 }
 
 // <a { spread... } />
+//
+// Spread attributes don't carry Trivia. Comments next to them are attached to the attributes
+// around them.
 type SpreadAttributes struct {
 	Expression Expression
 }
@@ -1035,6 +1362,7 @@ type ConditionalAttribute struct {
 	Expression Expression
 	Then       []Attribute
 	Else       []Attribute
+	Trivia
 }
 
 func (ca *ConditionalAttribute) String() string {
@@ -1056,9 +1384,15 @@ func (ca *ConditionalAttribute) Write(w io.Writer, indent int) error {
 	{
 		indent++
 		for _, attr := range ca.Then {
+			if err := writeLeadingAttributeTrivia(w, indent, attr); err != nil {
+				return err
+			}
 			if err := attr.Write(w, indent); err != nil {
 				return err
 			}
+			if err := writeTrailingAttributeTrivia(w, indent, attr); err != nil {
+				return err
+			}
 			if _, err := w.Write([]byte("\n")); err != nil {
 				return err
 			}
@@ -1078,9 +1412,15 @@ func (ca *ConditionalAttribute) Write(w io.Writer, indent int) error {
 	{
 		indent++
 		for _, attr := range ca.Else {
+			if err := writeLeadingAttributeTrivia(w, indent, attr); err != nil {
+				return err
+			}
 			if err := attr.Write(w, indent); err != nil {
 				return err
 			}
+			if err := writeTrailingAttributeTrivia(w, indent, attr); err != nil {
+				return err
+			}
 			if _, err := w.Write([]byte("\n")); err != nil {
 				return err
 			}
@@ -1102,6 +1442,7 @@ func (ca *ConditionalAttribute) Copy() Attribute {
 		Expression: ca.Expression,
 		Then:       CopyAttributes(ca.Then),
 		Else:       CopyAttributes(ca.Else),
+		Trivia:     ca.copyTrivia(),
 	}
 }
 
@@ -1118,6 +1459,7 @@ type GoComment struct {
 	Contents  string
 	Multiline bool
 	Range     Range
+	Trivia
 }
 
 func (c *GoComment) IsNode() bool { return true }
@@ -1136,6 +1478,7 @@ func (c *GoComment) Visit(v Visitor) error {
 type HTMLComment struct {
 	Contents string
 	Range    Range
+	Trivia
 }
 
 func (c *HTMLComment) IsNode() bool { return true }
@@ -1156,6 +1499,7 @@ func (c *HTMLComment) Visit(v Visitor) error {
 type CallTemplateExpression struct {
 	// Expression returns a template to execute.
 	Expression Expression
+	Trivia
 }
 
 func (cte *CallTemplateExpression) IsNode() bool { return true }
@@ -1172,11 +1516,16 @@ func (cte *CallTemplateExpression) Visit(v Visitor) error {
 // @Other(p.First, p.Last)
 // or it can be used to render a template parameter.
 // @v
+// or it can be used to render each component of a slice.
+// @items...
 type TemplElementExpression struct {
 	// Expression returns a template to execute.
 	Expression Expression
+	// Spread is true if the expression is a slice of components, e.g. @items...
+	Spread bool
 	// Children returns the elements in a block element.
 	Children []Node
+	Trivia
 }
 
 func (tee TemplElementExpression) ChildNodes() []Node {
@@ -1215,6 +1564,11 @@ func (tee *TemplElementExpression) Write(w io.Writer, indent int) error {
 			return err
 		}
 	}
+	if tee.Spread {
+		if _, err = io.WriteString(w, "..."); err != nil {
+			return err
+		}
+	}
 	if len(tee.Children) == 0 {
 		return nil
 	}
@@ -1236,6 +1590,9 @@ func (tee *TemplElementExpression) Visit(v Visitor) error {
 
 // ChildrenExpression can be used to rended the children of a templ element.
 // { children ... }
+//
+// It doesn't carry Trivia, so that parsing it doesn't allocate, and blank lines before it
+// aren't kept.
 type ChildrenExpression struct{}
 
 func (*ChildrenExpression) IsNode() bool { return true }
@@ -1250,6 +1607,164 @@ func (ce *ChildrenExpression) Visit(v Visitor) error {
 	return v.VisitChildrenExpression(ce)
 }
 
+// SlotExpression renders the named slot passed to a layout template.
+// { slot "header" }
+type SlotExpression struct {
+	Name string
+	// NameRange is the range of the quoted slot name.
+	NameRange Range
+	Trivia
+}
+
+func (*SlotExpression) IsNode() bool { return true }
+func (se *SlotExpression) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "{ slot ", strconv.Quote(se.Name), " }")
+}
+
+func (se *SlotExpression) Visit(v Visitor) error {
+	if sv, ok := v.(SlotVisitor); ok {
+		return sv.VisitSlotExpression(se)
+	}
+	return nil
+}
+
+// SlotDefinition provides the contents of a named slot when calling a layout template.
+//
+//	@layout.Page() {
+//	  slot header {
+//	    <h1>Title</h1>
+//	  }
+//	}
+type SlotDefinition struct {
+	Name      string
+	NameRange Range
+	Children  []Node
+	Trivia
+}
+
+func (sd SlotDefinition) ChildNodes() []Node {
+	return sd.Children
+}
+func (sd *SlotDefinition) IsNode() bool { return true }
+func (sd *SlotDefinition) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, "slot ", sd.Name, " {\n"); err != nil {
+		return err
+	}
+	if err := writeNodesIndented(w, indent+1, sd.Children); err != nil {
+		return err
+	}
+	return writeIndent(w, indent, "}")
+}
+
+func (sd *SlotDefinition) Visit(v Visitor) error {
+	if sv, ok := v.(SlotVisitor); ok {
+		return sv.VisitSlotDefinition(sd)
+	}
+	for _, child := range sd.Children {
+		if err := child.Visit(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BlockDefinition is a named block of a layout template.
+//
+// In a template that extends a layout, a block provides the contents of the layout's
+// block with the same name. Elsewhere, the block is replaced by the contents provided
+// for it, or its own contents if none were provided.
+//
+//	block title {
+//	  Default title
+//	}
+type BlockDefinition struct {
+	Name      string
+	NameRange Range
+	Children  []Node
+	Trivia
+}
+
+func (bd BlockDefinition) ChildNodes() []Node {
+	return bd.Children
+}
+func (bd *BlockDefinition) IsNode() bool { return true }
+func (bd *BlockDefinition) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, "block ", bd.Name, " {\n"); err != nil {
+		return err
+	}
+	if err := writeNodesIndented(w, indent+1, bd.Children); err != nil {
+		return err
+	}
+	return writeIndent(w, indent, "}")
+}
+
+func (bd *BlockDefinition) Visit(v Visitor) error {
+	return v.VisitBlockDefinition(bd)
+}
+
+// FragmentDefinition is a named region of a template that can be rendered on its own
+// with templ.RenderFragment.
+//
+//	@fragment "row" {
+//	  <tr><td>{ item.Name }</td></tr>
+//	}
+type FragmentDefinition struct {
+	Name string
+	// NameRange is the range of the quoted fragment name.
+	NameRange Range
+	Children  []Node
+	Trivia
+}
+
+func (fd FragmentDefinition) ChildNodes() []Node {
+	return fd.Children
+}
+func (fd *FragmentDefinition) IsNode() bool { return true }
+func (fd *FragmentDefinition) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, "@fragment ", strconv.Quote(fd.Name), " {\n"); err != nil {
+		return err
+	}
+	if err := writeNodesIndented(w, indent+1, fd.Children); err != nil {
+		return err
+	}
+	return writeIndent(w, indent, "}")
+}
+
+func (fd *FragmentDefinition) Visit(v Visitor) error {
+	return v.VisitFragmentDefinition(fd)
+}
+
+// CacheExpression is a region of a template whose output is cached with
+// templ.CachedFragment.
+//
+//	cache("products:" + category, time.Minute) {
+//	  <ul>...</ul>
+//	}
+type CacheExpression struct {
+	// Expression is the arguments of the cache block, the key and the time to live.
+	Expression Expression
+	Children   []Node
+	Trivia
+}
+
+func (ce CacheExpression) ChildNodes() []Node {
+	return ce.Children
+}
+func (ce *CacheExpression) IsNode() bool { return true }
+func (ce *CacheExpression) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, "cache(", ce.Expression.Value, ") {\n"); err != nil {
+		return err
+	}
+	if err := writeNodesIndented(w, indent+1, ce.Children); err != nil {
+		return err
+	}
+	return writeIndent(w, indent, "}")
+}
+
+func (ce *CacheExpression) Visit(v Visitor) error {
+	return v.VisitCacheExpression(ce)
+}
+
 // if p.Type == "test" && p.thing {
 // }
 type IfExpression struct {
@@ -1257,6 +1772,7 @@ type IfExpression struct {
 	Then       []Node
 	ElseIfs    []ElseIfExpression
 	Else       []Node
+	Trivia
 }
 
 type ElseIfExpression struct {
@@ -1317,6 +1833,7 @@ func (n *IfExpression) Visit(v Visitor) error {
 type SwitchExpression struct {
 	Expression Expression
 	Cases      []CaseExpression
+	Trivia
 }
 
 func (se SwitchExpression) ChildNodes() []Node {
@@ -1363,6 +1880,7 @@ type CaseExpression struct {
 type ForExpression struct {
 	Expression Expression
 	Children   []Node
+	Trivia
 }
 
 func (fe ForExpression) ChildNodes() []Node {
@@ -1393,6 +1911,7 @@ type GoCode struct {
 	// TrailingSpace lists what happens after the expression.
 	TrailingSpace TrailingSpace
 	Multiline     bool
+	Trivia
 }
 
 func (gc *GoCode) Trailing() TrailingSpace {
@@ -1427,6 +1946,7 @@ type StringExpression struct {
 	Expression Expression
 	// TrailingSpace lists what happens after the expression.
 	TrailingSpace TrailingSpace
+	Trivia
 }
 
 func (se *StringExpression) Trailing() TrailingSpace {
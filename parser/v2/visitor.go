@@ -9,9 +9,11 @@ type Visitor interface {
 	VisitCSSTemplate(*CSSTemplate) error
 	VisitConstantCSSProperty(*ConstantCSSProperty) error
 	VisitExpressionCSSProperty(*ExpressionCSSProperty) error
+	VisitCSSRule(*CSSRule) error
 	VisitDocType(*DocType) error
 	VisitHTMLTemplate(*HTMLTemplate) error
 	VisitText(*Text) error
+	VisitTextBlock(*TextBlock) error
 	VisitElement(*Element) error
 	VisitScriptElement(*ScriptElement) error
 	VisitRawElement(*RawElement) error
@@ -26,10 +28,24 @@ type Visitor interface {
 	VisitCallTemplateExpression(*CallTemplateExpression) error
 	VisitTemplElementExpression(*TemplElementExpression) error
 	VisitChildrenExpression(*ChildrenExpression) error
+	VisitBlockDefinition(*BlockDefinition) error
+	VisitFragmentDefinition(*FragmentDefinition) error
+	VisitCacheExpression(*CacheExpression) error
 	VisitIfExpression(*IfExpression) error
 	VisitSwitchExpression(*SwitchExpression) error
 	VisitForExpression(*ForExpression) error
 	VisitGoCode(*GoCode) error
 	VisitStringExpression(*StringExpression) error
 	VisitScriptTemplate(*ScriptTemplate) error
+	VisitContextDeclaration(*ContextDeclaration) error
+	VisitErrorNode(*ErrorNode) error
+}
+
+// SlotVisitor is implemented by visitors that visit slot expressions and slot definitions.
+// It's separate from Visitor, so that visitors written before slots were added still
+// implement Visitor. Visitors that don't implement it skip slot expressions, and visit the
+// children of slot definitions.
+type SlotVisitor interface {
+	VisitSlotExpression(*SlotExpression) error
+	VisitSlotDefinition(*SlotDefinition) error
 }
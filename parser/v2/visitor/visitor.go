@@ -41,6 +41,14 @@ func New() *Visitor {
 		}
 		return nil
 	}
+	v.CSSRule = func(n *parser.CSSRule) error {
+		for _, prop := range n.Properties {
+			if err := prop.Visit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	v.ConstantCSSProperty = func(n *parser.ConstantCSSProperty) error {
 		return nil
 	}
@@ -64,6 +72,9 @@ func New() *Visitor {
 	v.Text = func(n *parser.Text) error {
 		return nil
 	}
+	v.TextBlock = func(n *parser.TextBlock) error {
+		return nil
+	}
 	v.Element = func(n *parser.Element) error {
 		for _, attr := range n.Attributes {
 			if err := attr.Visit(v); err != nil {
@@ -141,6 +152,41 @@ func New() *Visitor {
 	v.ChildrenExpression = func(n *parser.ChildrenExpression) error {
 		return nil
 	}
+	v.SlotExpression = func(n *parser.SlotExpression) error {
+		return nil
+	}
+	v.SlotDefinition = func(n *parser.SlotDefinition) error {
+		for _, child := range n.Children {
+			if err := child.Visit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	v.BlockDefinition = func(n *parser.BlockDefinition) error {
+		for _, child := range n.Children {
+			if err := child.Visit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	v.FragmentDefinition = func(n *parser.FragmentDefinition) error {
+		for _, child := range n.Children {
+			if err := child.Visit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	v.CacheExpression = func(n *parser.CacheExpression) error {
+		for _, child := range n.Children {
+			if err := child.Visit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	v.IfExpression = func(n *parser.IfExpression) error {
 		for _, child := range n.Then {
 			if err := child.Visit(v); err != nil {
@@ -188,6 +234,12 @@ func New() *Visitor {
 	v.ScriptTemplate = func(n *parser.ScriptTemplate) error {
 		return nil
 	}
+	v.ContextDeclaration = func(n *parser.ContextDeclaration) error {
+		return nil
+	}
+	v.ErrorNode = func(n *parser.ErrorNode) error {
+		return nil
+	}
 
 	return v
 }
@@ -204,9 +256,11 @@ type Visitor struct {
 	CSSTemplate              func(n *parser.CSSTemplate) error
 	ConstantCSSProperty      func(n *parser.ConstantCSSProperty) error
 	ExpressionCSSProperty    func(n *parser.ExpressionCSSProperty) error
+	CSSRule                  func(n *parser.CSSRule) error
 	DocType                  func(n *parser.DocType) error
 	HTMLTemplate             func(n *parser.HTMLTemplate) error
 	Text                     func(n *parser.Text) error
+	TextBlock                func(n *parser.TextBlock) error
 	Element                  func(n *parser.Element) error
 	RawElement               func(n *parser.RawElement) error
 	ScriptElement            func(n *parser.ScriptElement) error
@@ -221,15 +275,23 @@ type Visitor struct {
 	CallTemplateExpression   func(n *parser.CallTemplateExpression) error
 	TemplElementExpression   func(n *parser.TemplElementExpression) error
 	ChildrenExpression       func(n *parser.ChildrenExpression) error
+	SlotExpression           func(n *parser.SlotExpression) error
+	SlotDefinition           func(n *parser.SlotDefinition) error
+	BlockDefinition          func(n *parser.BlockDefinition) error
+	FragmentDefinition       func(n *parser.FragmentDefinition) error
+	CacheExpression          func(n *parser.CacheExpression) error
 	IfExpression             func(n *parser.IfExpression) error
 	SwitchExpression         func(n *parser.SwitchExpression) error
 	ForExpression            func(n *parser.ForExpression) error
 	GoCode                   func(n *parser.GoCode) error
 	StringExpression         func(n *parser.StringExpression) error
 	ScriptTemplate           func(n *parser.ScriptTemplate) error
+	ContextDeclaration       func(n *parser.ContextDeclaration) error
+	ErrorNode                func(n *parser.ErrorNode) error
 }
 
 var _ parser.Visitor = (*Visitor)(nil)
+var _ parser.SlotVisitor = (*Visitor)(nil)
 
 func (v *Visitor) VisitTemplateFile(n *parser.TemplateFile) error {
 	return v.TemplateFile(n)
@@ -258,6 +320,10 @@ func (v *Visitor) VisitExpressionCSSProperty(n *parser.ExpressionCSSProperty) er
 	return v.ExpressionCSSProperty(n)
 }
 
+func (v *Visitor) VisitCSSRule(n *parser.CSSRule) error {
+	return v.CSSRule(n)
+}
+
 func (v *Visitor) VisitDocType(n *parser.DocType) error {
 	return v.DocType(n)
 }
@@ -266,6 +332,10 @@ func (v *Visitor) VisitHTMLTemplate(n *parser.HTMLTemplate) error {
 	return v.HTMLTemplate(n)
 }
 
+func (v *Visitor) VisitTextBlock(n *parser.TextBlock) error {
+	return v.TextBlock(n)
+}
+
 func (v *Visitor) VisitText(n *parser.Text) error {
 	return v.Text(n)
 }
@@ -326,6 +396,26 @@ func (v *Visitor) VisitChildrenExpression(n *parser.ChildrenExpression) error {
 	return v.ChildrenExpression(n)
 }
 
+func (v *Visitor) VisitSlotExpression(n *parser.SlotExpression) error {
+	return v.SlotExpression(n)
+}
+
+func (v *Visitor) VisitSlotDefinition(n *parser.SlotDefinition) error {
+	return v.SlotDefinition(n)
+}
+
+func (v *Visitor) VisitBlockDefinition(n *parser.BlockDefinition) error {
+	return v.BlockDefinition(n)
+}
+
+func (v *Visitor) VisitFragmentDefinition(n *parser.FragmentDefinition) error {
+	return v.FragmentDefinition(n)
+}
+
+func (v *Visitor) VisitCacheExpression(n *parser.CacheExpression) error {
+	return v.CacheExpression(n)
+}
+
 func (v *Visitor) VisitIfExpression(n *parser.IfExpression) error {
 	return v.IfExpression(n)
 }
@@ -349,3 +439,11 @@ func (v *Visitor) VisitStringExpression(n *parser.StringExpression) error {
 func (v *Visitor) VisitScriptTemplate(n *parser.ScriptTemplate) error {
 	return v.ScriptTemplate(n)
 }
+
+func (v *Visitor) VisitContextDeclaration(n *parser.ContextDeclaration) error {
+	return v.ContextDeclaration(n)
+}
+
+func (v *Visitor) VisitErrorNode(n *parser.ErrorNode) error {
+	return v.ErrorNode(n)
+}
@@ -0,0 +1,254 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Visitable is a part of a templ file: a *TemplateFile, TemplateFileNode, Node, Attribute or
+// CSSProperty.
+type Visitable interface {
+	Visit(v Visitor) error
+}
+
+// WalkFunc is called by Walk and Rewrite for each node. If it returns false, the children of
+// the node aren't walked.
+type WalkFunc func(c *Cursor) bool
+
+// Cursor describes the node being walked, and its position in the tree. A Cursor is only
+// valid during the call to the WalkFunc.
+type Cursor struct {
+	node    Visitable
+	parents []Visitable
+	// accept returns true if the node can be replaced with n. It's nil if the node can't be
+	// replaced, e.g. the node passed to Walk.
+	accept func(n Visitable) bool
+	// single is true if the node must be replaced with exactly one node, e.g. the value of a
+	// CSS property.
+	single       bool
+	rewrite      bool
+	replaced     bool
+	replacements []Visitable
+}
+
+// Node returns the node being walked.
+func (c *Cursor) Node() Visitable {
+	return c.node
+}
+
+// Parent returns the parent of the node, or nil if the node is the one passed to Walk. The
+// parent of the nodes within the else if branches of an if expression, or the cases of a
+// switch expression, is the *IfExpression or *SwitchExpression.
+func (c *Cursor) Parent() Visitable {
+	if len(c.parents) == 0 {
+		return nil
+	}
+	return c.parents[len(c.parents)-1]
+}
+
+// Parents returns the ancestors of the node, starting with the node passed to Walk.
+func (c *Cursor) Parents() []Visitable {
+	return slices.Clone(c.parents)
+}
+
+// Replace replaces the node with zero or more nodes of the same kind, e.g. an element can be
+// replaced with any Node, and an attribute with any Attribute. The replacements aren't walked.
+//
+// The first replacement takes the position of the node: if it doesn't have a Range or Trivia
+// of its own, they're moved from the replaced node, so that the formatter keeps blank lines
+// and comments.
+//
+// Replace panics if it's called from Walk, or if the node can't be replaced with n.
+func (c *Cursor) Replace(n ...Visitable) {
+	if !c.rewrite {
+		panic("parser: Replace called from Walk, use Rewrite")
+	}
+	if c.accept == nil {
+		panic(fmt.Sprintf("parser: %T can't be replaced", c.node))
+	}
+	if c.single && len(n) != 1 {
+		panic(fmt.Sprintf("parser: %T must be replaced with exactly one node", c.node))
+	}
+	for _, r := range n {
+		if !c.accept(r) {
+			panic(fmt.Sprintf("parser: %T can't be replaced with %T", c.node, r))
+		}
+	}
+	if len(n) > 0 && n[0] != c.node {
+		retainPosition(c.node, n[0])
+	}
+	c.replaced = true
+	c.replacements = n
+}
+
+// Delete removes the node. It's the same as calling Replace without any nodes.
+func (c *Cursor) Delete() {
+	c.Replace()
+}
+
+// Walk traverses n in depth-first order, calling fn for n, then for each of its children:
+// the header and nodes of a template file, the attributes and children of elements, the
+// properties of CSS templates, and the branches of conditional attributes, if, switch and
+// for expressions.
+//
+//	parser.Walk(tf, func(c *parser.Cursor) bool {
+//		if e, ok := c.Node().(*parser.Element); ok && e.Name == "img" {
+//			fmt.Printf("img in %T\n", c.Parent())
+//		}
+//		return true
+//	})
+func Walk(n Visitable, fn WalkFunc) {
+	w := &walker{fn: fn}
+	w.walkRoot(n)
+}
+
+// Rewrite walks n in the same order as Walk, and allows fn to replace or delete the nodes it's
+// called for, using the Cursor.
+//
+// The changes are made in place, but the slices of the tree are only updated after all of
+// their nodes have been walked, and new slices are allocated, so fn can change the tree
+// without affecting the walk, or other slices that share memory with the tree.
+//
+//	parser.Rewrite(tf, func(c *parser.Cursor) bool {
+//		if e, ok := c.Node().(*parser.Element); ok && e.Name == "center" {
+//			c.Replace(&parser.Element{Name: "div", Children: e.Children})
+//		}
+//		return true
+//	})
+func Rewrite(n Visitable, fn WalkFunc) {
+	w := &walker{fn: fn, rewrite: true}
+	w.walkRoot(n)
+}
+
+type walker struct {
+	fn      WalkFunc
+	rewrite bool
+	parents []Visitable
+}
+
+func (w *walker) walkRoot(n Visitable) {
+	c := &Cursor{node: n, rewrite: w.rewrite}
+	if w.fn(c) {
+		w.walkChildren(n)
+	}
+}
+
+func (w *walker) walkChildren(n Visitable) {
+	w.parents = append(w.parents, n)
+	defer func() { w.parents = w.parents[:len(w.parents)-1] }()
+	switch n := n.(type) {
+	case *TemplateFile:
+		n.Header = walkSlice(w, n.Header, false)
+		w.walkFixed(&n.Package)
+		n.Nodes = walkSlice(w, n.Nodes, false)
+	case *CSSTemplate:
+		n.Properties = walkSlice(w, n.Properties, false)
+	case *CSSRule:
+		n.Properties = walkSlice(w, n.Properties, false)
+	case *ExpressionCSSProperty:
+		if n.Value != nil {
+			n.Value = walkSlice(w, []*StringExpression{n.Value}, true)[0]
+		}
+	case *HTMLTemplate:
+		n.Children = walkSlice(w, n.Children, false)
+	case *Element:
+		n.Attributes = walkSlice(w, n.Attributes, false)
+		n.Children = walkSlice(w, n.Children, false)
+	case *ScriptElement:
+		n.Attributes = walkSlice(w, n.Attributes, false)
+	case *RawElement:
+		n.Attributes = walkSlice(w, n.Attributes, false)
+	case *ConditionalAttribute:
+		n.Then = walkSlice(w, n.Then, false)
+		n.Else = walkSlice(w, n.Else, false)
+	case *TemplElementExpression:
+		n.Children = walkSlice(w, n.Children, false)
+	case *SlotDefinition:
+		n.Children = walkSlice(w, n.Children, false)
+	case *BlockDefinition:
+		n.Children = walkSlice(w, n.Children, false)
+	case *FragmentDefinition:
+		n.Children = walkSlice(w, n.Children, false)
+	case *CacheExpression:
+		n.Children = walkSlice(w, n.Children, false)
+	case *IfExpression:
+		n.Then = walkSlice(w, n.Then, false)
+		for i := range n.ElseIfs {
+			n.ElseIfs[i].Then = walkSlice(w, n.ElseIfs[i].Then, false)
+		}
+		n.Else = walkSlice(w, n.Else, false)
+	case *SwitchExpression:
+		for i := range n.Cases {
+			n.Cases[i].Children = walkSlice(w, n.Cases[i].Children, false)
+		}
+	case *ForExpression:
+		n.Children = walkSlice(w, n.Children, false)
+	}
+}
+
+// walkFixed walks a child that can't be replaced, e.g. the package of a template file.
+func (w *walker) walkFixed(n Visitable) {
+	c := &Cursor{node: n, parents: w.parents, rewrite: w.rewrite}
+	if w.fn(c) {
+		w.walkChildren(n)
+	}
+}
+
+// walkSlice walks the nodes, and returns them with the replacements made by the WalkFunc. If
+// no nodes are replaced, the nodes are returned unchanged.
+func walkSlice[T Visitable](w *walker, nodes []T, single bool) []T {
+	accept := func(n Visitable) bool {
+		_, ok := n.(T)
+		return ok
+	}
+	var updated []T
+	for i, n := range nodes {
+		c := &Cursor{node: n, parents: w.parents, accept: accept, single: single, rewrite: w.rewrite}
+		walkChildren := w.fn(c)
+		if c.replaced {
+			if updated == nil {
+				updated = make([]T, i, len(nodes)+len(c.replacements))
+				copy(updated, nodes[:i])
+			}
+			for _, r := range c.replacements {
+				updated = append(updated, r.(T))
+			}
+			continue
+		}
+		if walkChildren {
+			w.walkChildren(n)
+		}
+		if updated != nil {
+			updated = append(updated, n)
+		}
+	}
+	if updated == nil {
+		return nodes
+	}
+	return updated
+}
+
+var rangeType = reflect.TypeOf(Range{})
+
+// retainPosition moves the Range and Trivia of the replaced node to its replacement, if the
+// replacement doesn't have its own.
+func retainPosition(replaced, replacement Visitable) {
+	from, to := reflect.ValueOf(replaced), reflect.ValueOf(replacement)
+	if from.Kind() == reflect.Pointer && to.Kind() == reflect.Pointer && !from.IsNil() && !to.IsNil() &&
+		from.Elem().Kind() == reflect.Struct && to.Elem().Kind() == reflect.Struct {
+		fromRange, toRange := from.Elem().FieldByName("Range"), to.Elem().FieldByName("Range")
+		if fromRange.IsValid() && toRange.IsValid() && fromRange.Type() == rangeType && toRange.Type() == rangeType && toRange.IsZero() {
+			toRange.Set(fromRange)
+		}
+	}
+	fromTrivia, ok := replaced.(TriviaCarrier)
+	if !ok {
+		return
+	}
+	toTrivia, ok := replacement.(TriviaCarrier)
+	if !ok || !reflect.ValueOf(*toTrivia.NodeTrivia()).IsZero() {
+		return
+	}
+	*toTrivia.NodeTrivia(), *fromTrivia.NodeTrivia() = *fromTrivia.NodeTrivia(), Trivia{}
+}
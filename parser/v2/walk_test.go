@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const walkInput = `package main
+
+templ Page(items []string, ok bool) {
+	<center class="title">Items</center>
+	if ok {
+		for _, item := range items {
+			<img src={ item }/>
+		}
+	} else {
+		<p>None</p>
+	}
+}
+`
+
+func TestWalk(t *testing.T) {
+	tf, err := ParseString(walkInput)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var actual []string
+	Walk(tf, func(c *Cursor) bool {
+		switch n := c.Node().(type) {
+		case *Element:
+			actual = append(actual, fmt.Sprintf("%s in %T at depth %d", n.Name, c.Parent(), len(c.Parents())))
+		case *ConstantAttribute:
+			actual = append(actual, fmt.Sprintf("%s in %T", n.Key, c.Parent()))
+		case *ExpressionAttribute:
+			return false
+		}
+		return true
+	})
+	expected := []string{
+		"center in *parser.HTMLTemplate at depth 2",
+		"class in *parser.Element",
+		"img in *parser.ForExpression at depth 4",
+		"p in *parser.IfExpression at depth 3",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+	t.Run("nodes can't be replaced during Walk", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		Walk(tf, func(c *Cursor) bool {
+			c.Delete()
+			return true
+		})
+	})
+}
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       WalkFunc
+		expected string
+	}{
+		{
+			name: "elements can be replaced",
+			fn: func(c *Cursor) bool {
+				if e, ok := c.Node().(*Element); ok && e.Name == "center" {
+					c.Replace(&Element{Name: "div", Attributes: e.Attributes, Children: e.Children})
+				}
+				return true
+			},
+			expected: `<div class="title">Items</div>`,
+		},
+		{
+			name: "elements can be wrapped without walking them again",
+			fn: func(c *Cursor) bool {
+				if e, ok := c.Node().(*Element); ok && e.Name == "img" {
+					c.Replace(&Element{Name: "picture", Children: []Node{e}})
+				}
+				return true
+			},
+			expected: "for _, item := range items {\n\t\t\t<picture><img src={ item }/>",
+		},
+		{
+			name: "nodes can be deleted",
+			fn: func(c *Cursor) bool {
+				if e, ok := c.Node().(*Element); ok && e.Name == "p" {
+					c.Delete()
+				}
+				return true
+			},
+			expected: "<img src={ item }/>\n\t\t}\n\t}\n}",
+		},
+		{
+			name: "attributes can be replaced with several attributes",
+			fn: func(c *Cursor) bool {
+				if a, ok := c.Node().(*ConstantAttribute); ok && a.Key.String() == "class" {
+					c.Replace(a, &ConstantAttribute{Key: ConstantAttributeKey{Name: "id"}, Value: "items"})
+				}
+				return true
+			},
+			expected: `<center class="title" id="items">Items</center>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := ParseString(walkInput)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			Rewrite(tf, tt.fn)
+			w := new(strings.Builder)
+			if err := tf.Write(w); err != nil {
+				t.Fatalf("failed to write template: %v", err)
+			}
+			if !strings.Contains(w.String(), tt.expected) {
+				t.Errorf("expected %q in output, got:\n%s", tt.expected, w.String())
+			}
+		})
+	}
+	t.Run("replacements retain the position of the replaced node", func(t *testing.T) {
+		tf, err := ParseString("package main\n\ntempl Page() {\n\t<br/>\n\n\tText\n}\n")
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		var replaced *Text
+		Rewrite(tf, func(c *Cursor) bool {
+			if n, ok := c.Node().(*Text); ok {
+				replaced = n
+				c.Replace(&Text{Value: "Replaced"})
+			}
+			return true
+		})
+		children := tf.Nodes[0].(*HTMLTemplate).Children
+		replacement := children[len(children)-1].(*Text)
+		if replacement.Range != replaced.Range {
+			t.Errorf("expected range %v, got %v", replaced.Range, replacement.Range)
+		}
+		if !replacement.BlankLineBefore {
+			t.Error("expected the blank line before the node to be retained")
+		}
+	})
+	t.Run("nodes can only be replaced with nodes of the same kind", func(t *testing.T) {
+		tf, err := ParseString(walkInput)
+		if err != nil {
+			t.Fatalf("failed to parse template: %v", err)
+		}
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		Rewrite(tf, func(c *Cursor) bool {
+			if _, ok := c.Node().(*Element); ok {
+				c.Replace(&ConstantAttribute{})
+			}
+			return true
+		})
+	})
+}
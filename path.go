@@ -0,0 +1,41 @@
+package templ
+
+import "context"
+
+const renderPathContextKey = contextKeyType(2)
+
+// renderPath is an element of the render path, see Path.
+type renderPath struct {
+	name   string
+	parent *renderPath
+}
+
+// WithRenderPath is used by generated code in development mode to add the name of the
+// component that's being rendered to the render path, see Path.
+func WithRenderPath(ctx context.Context, name string) context.Context {
+	parent, _ := ctx.Value(renderPathContextKey).(*renderPath)
+	return context.WithValue(ctx, renderPathContextKey, &renderPath{name: name, parent: parent})
+}
+
+// Path returns the names of the components that are being rendered, outermost first, e.g.
+// ["pages.Home", "layouts.Page", "components.Card"], so that shared components can log or
+// display where they were rendered from.
+//
+// The path is only populated in development mode, i.e. when the templ generate -watch
+// command is used, so Path returns nil in production.
+//
+//	templ card(title string) {
+//		<div class="card" data-path={ strings.Join(templ.Path(ctx), " > ") }>{ title }</div>
+//	}
+func Path(ctx context.Context) (path []string) {
+	if ctx == nil {
+		return nil
+	}
+	for p, _ := ctx.Value(renderPathContextKey).(*renderPath); p != nil; p = p.parent {
+		path = append(path, p.name)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
@@ -0,0 +1,29 @@
+package templ_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPath(t *testing.T) {
+	t.Run("the path is empty outside of development mode", func(t *testing.T) {
+		if path := templ.Path(context.Background()); path != nil {
+			t.Errorf("expected no path, got %v", path)
+		}
+	})
+	t.Run("components are listed outermost first", func(t *testing.T) {
+		ctx := templ.WithRenderPath(context.Background(), "pages.Home")
+		ctx = templ.WithRenderPath(ctx, "layouts.Page")
+		sibling := templ.WithRenderPath(ctx, "components.Nav")
+		ctx = templ.WithRenderPath(ctx, "components.Card")
+		if diff := cmp.Diff([]string{"pages.Home", "layouts.Page", "components.Card"}, templ.Path(ctx)); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]string{"pages.Home", "layouts.Page", "components.Nav"}, templ.Path(sibling)); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,272 @@
+package templ
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// NewPrettyWriter returns a writer that re-indents the HTML written to it, and writes the
+// result to w. It's intended for development, to make the output readable in view-source and
+// in the diffs of tests. Production output should be written without it.
+//
+// Block-level elements are written on their own lines, indented with tabs. Block-level
+// elements that only contain text and inline elements are kept on a single line. The content
+// of <pre>, <textarea>, <script> and <style> elements is written unchanged.
+//
+// The pretty-printed output isn't equivalent to the input, since whitespace is added and
+// removed between elements. Call Flush after the document has been written.
+func NewPrettyWriter(w io.Writer) *PrettyWriter {
+	return &PrettyWriter{w: w}
+}
+
+// PrettyWriter re-indents HTML, see NewPrettyWriter.
+type PrettyWriter struct {
+	w io.Writer
+	// depth of the block-level elements that are open.
+	depth int
+	// tok contains the incomplete text, tag, or comment.
+	tok    []byte
+	inTag  bool
+	quote  byte
+	rawTag string
+	// open is the start tag of the last block-level element, which hasn't been written yet,
+	// because it's not known whether its content fits on a single line.
+	open []byte
+	// line contains the inline content of the current line.
+	line []byte
+	// ws is whitespace at the end of text, that's written if it's followed by inline content.
+	ws  []byte
+	out []byte
+}
+
+func (pw *PrettyWriter) Write(p []byte) (n int, err error) {
+	for _, b := range p {
+		pw.writeByte(b)
+	}
+	if err = pw.writeOut(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered output.
+func (pw *PrettyWriter) Flush() error {
+	if !pw.inTag {
+		pw.text()
+	}
+	pw.endLine()
+	pw.out = append(pw.out, pw.tok...)
+	pw.tok, pw.inTag, pw.quote, pw.rawTag = pw.tok[:0], false, 0, ""
+	return pw.writeOut()
+}
+
+func (pw *PrettyWriter) writeOut() error {
+	if len(pw.out) == 0 {
+		return nil
+	}
+	_, err := pw.w.Write(pw.out)
+	pw.out = pw.out[:0]
+	return err
+}
+
+func (pw *PrettyWriter) writeByte(b byte) {
+	if !pw.inTag {
+		if b != '<' {
+			pw.tok = append(pw.tok, b)
+			return
+		}
+		pw.text()
+		pw.inTag = true
+	}
+	pw.tok = append(pw.tok, b)
+	switch {
+	case pw.rawTag != "":
+		if b == '>' && isRawEndTag(pw.tok, pw.rawTag) {
+			pw.element(pw.rawTag, pw.tok)
+		}
+	case bytes.HasPrefix(pw.tok, []byte("<!--")):
+		if b == '>' && bytes.HasSuffix(pw.tok, []byte("-->")) && len(pw.tok) >= 7 {
+			pw.inline(pw.tok)
+			pw.endTag()
+		}
+	case pw.quote != 0:
+		if b == pw.quote {
+			pw.quote = 0
+		}
+	case b == '"' || b == '\'':
+		pw.quote = b
+	case b == '>':
+		pw.tag()
+	}
+}
+
+// isRawEndTag returns true if tok ends with the end tag of the named element.
+func isRawEndTag(tok []byte, name string) bool {
+	i := bytes.LastIndex(tok, []byte("</"))
+	if i < 0 || len(tok)-i < len(name)+3 {
+		return false
+	}
+	if !strings.EqualFold(string(tok[i+2:i+2+len(name)]), name) {
+		return false
+	}
+	return len(bytes.TrimSpace(tok[i+2+len(name):len(tok)-1])) == 0
+}
+
+// text writes the text in tok.
+func (pw *PrettyWriter) text() {
+	if len(pw.tok) == 0 {
+		return
+	}
+	text := append(pw.ws, pw.tok...)
+	pw.ws = nil
+	trimmed := bytes.TrimRight(text, " \t\r\n\f")
+	if len(trimmed) > 0 {
+		pw.inline(trimmed)
+	}
+	pw.ws = append([]byte(nil), text[len(trimmed):]...)
+	pw.tok = pw.tok[:0]
+}
+
+func (pw *PrettyWriter) tag() {
+	tok := pw.tok
+	if bytes.HasPrefix(tok, []byte("<!")) || bytes.HasPrefix(tok, []byte("<?")) {
+		// Doctypes and processing instructions.
+		pw.endLine()
+		pw.writeLine(pw.depth, tok)
+		pw.endTag()
+		return
+	}
+	closing := bytes.HasPrefix(tok, []byte("</"))
+	name := tagName(tok)
+	_, block := prettyBlockElements[name]
+	switch {
+	case closing && !block:
+		pw.inline(tok)
+	case closing:
+		if pw.open != nil {
+			pw.writeLine(pw.depth-1, pw.open, pw.line, tok)
+			pw.open, pw.line, pw.ws = nil, pw.line[:0], nil
+		} else {
+			pw.endLine()
+			pw.writeLine(pw.depth-1, tok)
+		}
+		pw.depth = max(pw.depth-1, 0)
+	default:
+		if _, ok := prettyRawElements[name]; ok {
+			// Continue until the end tag.
+			pw.rawTag = name
+			return
+		}
+		_, void := prettyVoidElements[name]
+		void = void || bytes.HasSuffix(tok, []byte("/>"))
+		pw.element(name, tok)
+		if block && !void {
+			pw.open = append([]byte(nil), tok...)
+			pw.depth++
+		}
+	}
+	pw.endTag()
+}
+
+// element writes a complete element, or the start tag of a block-level element.
+func (pw *PrettyWriter) element(name string, tok []byte) {
+	defer pw.endTag()
+	if _, block := prettyBlockElements[name]; !block {
+		pw.inline(tok)
+		return
+	}
+	pw.endLine()
+	_, void := prettyVoidElements[name]
+	_, raw := prettyRawElements[name]
+	if void || raw || bytes.HasSuffix(tok, []byte("/>")) {
+		pw.writeLine(pw.depth, tok)
+	}
+}
+
+func (pw *PrettyWriter) endTag() {
+	pw.tok, pw.inTag, pw.quote, pw.rawTag = pw.tok[:0], false, 0, ""
+}
+
+// inline adds inline content to the current line.
+func (pw *PrettyWriter) inline(tok []byte) {
+	if len(pw.line) == 0 {
+		tok = bytes.TrimLeft(tok, " \t\r\n\f")
+	} else {
+		pw.line = append(pw.line, pw.ws...)
+	}
+	pw.ws = nil
+	pw.line = append(pw.line, tok...)
+}
+
+// endLine writes the pending start tag and current line.
+func (pw *PrettyWriter) endLine() {
+	if pw.open != nil {
+		pw.writeLine(pw.depth-1, pw.open)
+		pw.open = nil
+	}
+	if len(pw.line) > 0 {
+		pw.writeLine(pw.depth, pw.line)
+	}
+	pw.line, pw.ws = pw.line[:0], nil
+}
+
+func (pw *PrettyWriter) writeLine(depth int, parts ...[]byte) {
+	for range max(depth, 0) {
+		pw.out = append(pw.out, '\t')
+	}
+	for _, p := range parts {
+		pw.out = append(pw.out, p...)
+	}
+	pw.out = append(pw.out, '\n')
+}
+
+// tagName returns the lowercase name of the element in a start or end tag.
+func tagName(tok []byte) string {
+	tok = bytes.TrimPrefix(bytes.TrimPrefix(tok, []byte("<")), []byte("/"))
+	end := bytes.IndexAny(tok, " \t\r\n\f/>")
+	if end < 0 {
+		end = len(tok)
+	}
+	return strings.ToLower(string(tok[:end]))
+}
+
+func prettyElementSet(names string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, name := range strings.Fields(names) {
+		set[name] = struct{}{}
+	}
+	return set
+}
+
+var prettyBlockElements = prettyElementSet(`address article aside base blockquote body caption col
+colgroup dd details dialog div dl dt fieldset figcaption figure footer form h1 h2 h3 h4 h5 h6 head
+header hgroup hr html legend li link main menu meta nav noscript ol optgroup option p pre script
+search section select style summary table tbody td template tfoot th thead title tr ul`)
+
+var prettyVoidElements = prettyElementSet(`area base br col embed hr img input link meta source
+track wbr`)
+
+// prettyRawElements are the elements whose content is written unchanged.
+var prettyRawElements = prettyElementSet(`pre script style textarea`)
+
+// prettyResponseWriter pretty-prints the response written by a ComponentHandler.
+type prettyResponseWriter struct {
+	http.ResponseWriter
+	pw *PrettyWriter
+}
+
+func (w *prettyResponseWriter) Write(p []byte) (n int, err error) {
+	return w.pw.Write(p)
+}
+
+func (w *prettyResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *prettyResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
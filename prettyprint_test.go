@@ -0,0 +1,137 @@
+package templ_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPrettyWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "block-level elements are indented",
+			input: `<!DOCTYPE html><html><head><meta charset="utf-8"><title>Home</title></head><body><main><div><p>Hello</p></div></main></body></html>`,
+			expected: `<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="utf-8">
+		<title>Home</title>
+	</head>
+	<body>
+		<main>
+			<div>
+				<p>Hello</p>
+			</div>
+		</main>
+	</body>
+</html>
+`,
+		},
+		{
+			name:  "inline elements and text are kept on a single line",
+			input: `<div><h1>Hello, <b>world</b>!</h1>Some <a href="/">text</a><br>more text<ul><li>One</li></ul></div>`,
+			expected: `<div>
+	<h1>Hello, <b>world</b>!</h1>
+	Some <a href="/">text</a><br>more text
+	<ul>
+		<li>One</li>
+	</ul>
+</div>
+`,
+		},
+		{
+			name:  "whitespace between block-level elements is removed",
+			input: "<div>\n  <p> Hello </p>\n  <span>a</span> <span>b</span>\n</div>",
+			expected: `<div>
+	<p>Hello</p>
+	<span>a</span> <span>b</span>
+</div>
+`,
+		},
+		{
+			name:     "the content of raw text elements is unchanged",
+			input:    "<div><pre>  a\n<b>b</b></pre><script>if (a < b) { x = \"</div>\" }</script><textarea>\n x</textarea></div>",
+			expected: "<div>\n\t<pre>  a\n<b>b</b></pre>\n\t<script>if (a < b) { x = \"</div>\" }</script>\n\t<textarea>\n x</textarea>\n</div>\n",
+		},
+		{
+			name:  "attribute values can contain angle brackets",
+			input: `<div title="a > b" data-x='<p>'><p>x</p></div>`,
+			expected: `<div title="a > b" data-x='<p>'>
+	<p>x</p>
+</div>
+`,
+		},
+		{
+			name:  "comments are inline",
+			input: `<div><!-- <p> --><p>x</p></div>`,
+			expected: `<div>
+	<!-- <p> -->
+	<p>x</p>
+</div>
+`,
+		},
+		{
+			name:     "fragments are written",
+			input:    `Hello <b>world</b>`,
+			expected: "Hello <b>world</b>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			pw := templ.NewPrettyWriter(&buf)
+			if _, err := io.WriteString(pw, tt.input); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := pw.Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, buf.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+		t.Run(tt.name+" when written a byte at a time", func(t *testing.T) {
+			var buf bytes.Buffer
+			pw := templ.NewPrettyWriter(&buf)
+			for i := range len(tt.input) {
+				if _, err := io.WriteString(pw, tt.input[i:i+1]); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if err := pw.Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, buf.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestHandlerPrettyPrint(t *testing.T) {
+	page := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<div><p>Hello</p></div>")
+		return err
+	})
+	for _, streaming := range []bool{false, true} {
+		opts := []func(*templ.ComponentHandler){templ.WithPrettyPrint()}
+		if streaming {
+			opts = append(opts, templ.WithStreaming())
+		}
+		w := httptest.NewRecorder()
+		templ.Handler(page, opts...).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		expected := "<div>\n\t<p>Hello</p>\n</div>\n"
+		if diff := cmp.Diff(expected, w.Body.String()); diff != "" {
+			t.Errorf("streaming=%v: %s", streaming, diff)
+		}
+	}
+}
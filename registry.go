@@ -0,0 +1,132 @@
+package templ
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ/internal/funcname"
+)
+
+// TemplateParam is a parameter of a registered template.
+type TemplateParam struct {
+	Name string `json:"name"`
+	// Type of the parameter, as written in the template, e.g. "string" or "[]models.Item".
+	Type string `json:"type"`
+}
+
+// TemplateInfo describes a template that was registered by generated code, so that it can
+// be looked up and created by name, e.g. to render components selected in a CMS.
+type TemplateInfo struct {
+	// Package is the import path of the package that contains the template.
+	Package string `json:"package"`
+	// Name of the template.
+	Name string `json:"name"`
+	// Params of the template, in declaration order.
+	Params []TemplateParam `json:"params"`
+	// New creates the component from arguments in declaration order. An error is returned if
+	// the number or types of the arguments don't match the parameters.
+	New func(args ...any) (Component, error) `json:"-"`
+}
+
+// Bind creates the component from arguments keyed by parameter name. Parameters that don't
+// have a value are set to their zero value. An error is returned if a value doesn't match the
+// type of its parameter, or if values contains a name that isn't a parameter.
+func (t TemplateInfo) Bind(values map[string]any) (Component, error) {
+	args := make([]any, len(t.Params))
+	used := 0
+	for i, p := range t.Params {
+		if v, ok := values[p.Name]; ok {
+			args[i] = v
+			used++
+		}
+	}
+	if used != len(values) {
+		for name := range values {
+			if !slices.ContainsFunc(t.Params, func(p TemplateParam) bool { return p.Name == name }) {
+				return nil, fmt.Errorf("templ: %s has no parameter named %q", t.Name, name)
+			}
+		}
+	}
+	return t.New(args...)
+}
+
+var templateRegistry = struct {
+	sync.RWMutex
+	packages map[string]map[string]TemplateInfo
+}{
+	packages: map[string]map[string]TemplateInfo{},
+}
+
+// RegisterTemplate adds a template to the registry. It's called by generated code when
+// templates are generated with a registry.
+func RegisterTemplate(t TemplateInfo) {
+	templateRegistry.Lock()
+	defer templateRegistry.Unlock()
+	pkg, ok := templateRegistry.packages[t.Package]
+	if !ok {
+		pkg = map[string]TemplateInfo{}
+		templateRegistry.packages[t.Package] = pkg
+	}
+	pkg[t.Name] = t
+}
+
+// LookupTemplate returns the registered template with the name, in the package with the
+// import path pkg.
+func LookupTemplate(pkg, name string) (t TemplateInfo, ok bool) {
+	templateRegistry.RLock()
+	defer templateRegistry.RUnlock()
+	t, ok = templateRegistry.packages[pkg][name]
+	return t, ok
+}
+
+// Templates returns the registered templates in the package with the import path pkg,
+// sorted by name.
+func Templates(pkg string) []TemplateInfo {
+	templateRegistry.RLock()
+	defer templateRegistry.RUnlock()
+	templates := make([]TemplateInfo, 0, len(templateRegistry.packages[pkg]))
+	for _, t := range templateRegistry.packages[pkg] {
+		templates = append(templates, t)
+	}
+	slices.SortFunc(templates, func(a, b TemplateInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return templates
+}
+
+// TemplatePackage is the import path of a package that contains registered templates, so
+// that its templates can be looked up by name without repeating the import path.
+type TemplatePackage string
+
+// CallerTemplatePackage returns the TemplatePackage of the package that calls it, e.g. to
+// declare a lookup function next to the templates of a package.
+//
+//	var registry = templ.CallerTemplatePackage()
+//
+//	func Lookup(name string) (templ.TemplateInfo, bool) {
+//		return registry.Lookup(name)
+//	}
+func CallerTemplatePackage() TemplatePackage {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	return TemplatePackage(funcname.Package(fn.Name()))
+}
+
+// Lookup returns the registered template with the name in the package.
+func (p TemplatePackage) Lookup(name string) (t TemplateInfo, ok bool) {
+	return LookupTemplate(string(p), name)
+}
+
+// Templates returns the registered templates in the package, sorted by name.
+func (p TemplatePackage) Templates() []TemplateInfo {
+	return Templates(string(p))
+}
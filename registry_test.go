@@ -0,0 +1,79 @@
+package templ_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestTemplateRegistry(t *testing.T) {
+	const pkg = "example.com/registry_test"
+	greeting := templ.TemplateInfo{
+		Package: pkg,
+		Name:    "Greeting",
+		Params:  []templ.TemplateParam{{Name: "name", Type: "string"}, {Name: "excited", Type: "bool"}},
+		New: func(args ...any) (templ.Component, error) {
+			if len(args) != 2 {
+				return nil, fmt.Errorf("expected 2 arguments, got %d", len(args))
+			}
+			name, _ := args[0].(string)
+			excited, _ := args[1].(bool)
+			return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				s := "Hello, " + name
+				if excited {
+					s += "!"
+				}
+				_, err := io.WriteString(w, s)
+				return err
+			}), nil
+		},
+	}
+	templ.RegisterTemplate(greeting)
+	templ.RegisterTemplate(templ.TemplateInfo{Package: pkg, Name: "Footer", New: func(args ...any) (templ.Component, error) {
+		return templ.NopComponent, nil
+	}})
+
+	t.Run("templates can be looked up by name", func(t *testing.T) {
+		if _, ok := templ.LookupTemplate(pkg, "Greeting"); !ok {
+			t.Error("expected Greeting to be found")
+		}
+		if _, ok := templ.LookupTemplate(pkg, "Missing"); ok {
+			t.Error("expected Missing not to be found")
+		}
+		if _, ok := templ.LookupTemplate("example.com/other", "Greeting"); ok {
+			t.Error("expected templates to be registered by package")
+		}
+	})
+	t.Run("templates are listed by name", func(t *testing.T) {
+		var names []string
+		for _, info := range templ.Templates(pkg) {
+			names = append(names, info.Name)
+		}
+		if strings.Join(names, ",") != "Footer,Greeting" {
+			t.Errorf("expected Footer,Greeting, got %v", names)
+		}
+	})
+	t.Run("Bind passes values by parameter name", func(t *testing.T) {
+		c, err := greeting.Bind(map[string]any{"excited": true, "name": "World"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var sb strings.Builder
+		if err = c.Render(context.Background(), &sb); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		if sb.String() != "Hello, World!" {
+			t.Errorf("expected %q, got %q", "Hello, World!", sb.String())
+		}
+	})
+	t.Run("Bind returns an error for unknown parameters", func(t *testing.T) {
+		_, err := greeting.Bind(map[string]any{"title": "World"})
+		if err == nil || !strings.Contains(err.Error(), `Greeting has no parameter named "title"`) {
+			t.Errorf("expected unknown parameter error, got %v", err)
+		}
+	})
+}
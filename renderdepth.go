@@ -0,0 +1,76 @@
+package templ
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxRenderDepth is the maximum number of nested generated components, unless it's
+// changed with WithMaxRenderDepth.
+const DefaultMaxRenderDepth = 1000
+
+// renderDepthErrorComponents is the number of component names kept by a RenderDepthError.
+const renderDepthErrorComponents = 10
+
+// RenderDepthError is returned when generated components are nested more deeply than the
+// maximum render depth, which usually means that a component renders itself without a
+// condition that stops the recursion.
+type RenderDepthError struct {
+	// MaxDepth is the maximum render depth that was exceeded.
+	MaxDepth int
+	// Components are the names of the innermost components that were being rendered,
+	// innermost first, e.g. "pages.Tree".
+	Components []string
+}
+
+func (e *RenderDepthError) Error() string {
+	msg := fmt.Sprintf("templ: maximum render depth of %d exceeded, check for components that render themselves", e.MaxDepth)
+	if len(e.Components) == 0 {
+		return msg
+	}
+	return msg + ": " + strings.Join(e.Components, " < ")
+}
+
+// AddComponent is used by generated code to add the name of a component to the error as the
+// error is returned through the components that were being rendered.
+func (e *RenderDepthError) AddComponent(name string) {
+	if len(e.Components) < renderDepthErrorComponents {
+		e.Components = append(e.Components, name)
+	}
+}
+
+// WithMaxRenderDepth sets the maximum number of nested generated components. If n is zero or
+// less, the depth isn't limited.
+func WithMaxRenderDepth(ctx context.Context, n int) context.Context {
+	ctx, v := getContext(ctx)
+	if n <= 0 {
+		n = -1
+	}
+	v.maxRenderDepth = n
+	return ctx
+}
+
+// IncrementRenderDepth is used by generated code to track the number of nested components.
+// A RenderDepthError is returned if the maximum render depth is exceeded. Each call must be
+// followed by a call to DecrementRenderDepth with the returned context.
+func IncrementRenderDepth(ctx context.Context) (context.Context, error) {
+	ctx, v := getContext(ctx)
+	depth := int(v.renderDepth.Add(1))
+	maxDepth := v.maxRenderDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxRenderDepth
+	}
+	if maxDepth > 0 && depth > maxDepth {
+		return ctx, &RenderDepthError{MaxDepth: maxDepth}
+	}
+	return ctx, nil
+}
+
+// DecrementRenderDepth is used by generated code after a component has rendered, see
+// IncrementRenderDepth.
+func DecrementRenderDepth(ctx context.Context) {
+	if v, ok := ctx.Value(contextKey).(*contextValue); ok {
+		v.renderDepth.Add(-1)
+	}
+}
@@ -0,0 +1,25 @@
+package templ_test
+
+import (
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestRenderDepthError(t *testing.T) {
+	err := &templ.RenderDepthError{MaxDepth: 3}
+	if expected := "templ: maximum render depth of 3 exceeded, check for components that render themselves"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	for range 20 {
+		err.AddComponent("pages.Tree")
+	}
+	if len(err.Components) != 10 {
+		t.Errorf("expected the number of components to be limited to 10, got %d", len(err.Components))
+	}
+	expected := "templ: maximum render depth of 3 exceeded, check for components that render themselves: pages.Tree < pages.Tree < pages.Tree"
+	err.Components = err.Components[:3]
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
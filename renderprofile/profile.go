@@ -0,0 +1,273 @@
+// Package renderprofile reports the time and allocations spent rendering each component, and
+// each type of template node, from CPU and memory profiles, to find the parts of templates
+// where optimizations matter.
+//
+// Profiles can be written by a sample render with Render, or by runtime/pprof, e.g. with
+// go test -cpuprofile in a benchmark that renders components.
+//
+//	cpu, _, err := renderprofile.Render(ctx, pages.Home(data), time.Second)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	report, err := cpu.Report("")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	report.WriteText(os.Stdout)
+package renderprofile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Profile is a CPU or memory profile.
+type Profile struct {
+	// SampleTypes are the types of the values of each sample, e.g. cpu, or alloc_space.
+	SampleTypes []SampleType
+	// DefaultSampleType is the type of sample that's reported if none is given.
+	DefaultSampleType string
+	Samples           []Sample
+}
+
+// SampleType is the type of the values of a sample.
+type SampleType struct {
+	// Type of the value, e.g. cpu, or alloc_space.
+	Type string
+	// Unit of the value, e.g. nanoseconds, or bytes.
+	Unit string
+}
+
+// Sample is the stack of a sample, and its values.
+type Sample struct {
+	// Frames of the stack, starting with the innermost frame.
+	Frames []Frame
+	// Values of the sample, one for each of the sample types of the profile.
+	Values []int64
+}
+
+// Frame is a frame of a stack.
+type Frame struct {
+	// Function is the package qualified name of the function, e.g. "github.com/a-h/app.Home.func1".
+	Function string
+	File     string
+	Line     int
+}
+
+// Read reads a profile in the protocol buffer format written by runtime/pprof. The profile
+// may be gzipped.
+func Read(r io.Reader) (*Profile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(gz); err != nil {
+			return nil, fmt.Errorf("failed to decompress profile: %w", err)
+		}
+	}
+	p, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	return p, nil
+}
+
+type rawSample struct {
+	locationIDs []uint64
+	values      []uint64
+}
+
+type rawLine struct {
+	functionID uint64
+	line       int
+}
+
+type rawFunction struct {
+	name, file uint64
+}
+
+// decode decodes the fields of the profile.proto message that are used in reports.
+func decode(data []byte) (*Profile, error) {
+	var (
+		sampleTypes       [][2]uint64
+		defaultSampleType uint64
+		samples           []rawSample
+		locations         = map[uint64][]rawLine{}
+		functions         = map[uint64]rawFunction{}
+		stringTable       []string
+	)
+	err := readFields(data, func(num, wireType int, v uint64, b []byte) (err error) {
+		switch num {
+		case 1: // sample_type
+			var st [2]uint64
+			err = readFields(b, func(num, wireType int, v uint64, b []byte) error {
+				if num == 1 || num == 2 {
+					st[num-1] = v
+				}
+				return nil
+			})
+			sampleTypes = append(sampleTypes, st)
+		case 2: // sample
+			var s rawSample
+			err = readFields(b, func(num, wireType int, v uint64, b []byte) (err error) {
+				switch num {
+				case 1:
+					s.locationIDs, err = appendVarints(s.locationIDs, wireType, v, b)
+				case 2:
+					s.values, err = appendVarints(s.values, wireType, v, b)
+				}
+				return err
+			})
+			samples = append(samples, s)
+		case 4: // location
+			var id uint64
+			var lines []rawLine
+			err = readFields(b, func(num, wireType int, v uint64, b []byte) error {
+				switch num {
+				case 1:
+					id = v
+				case 4:
+					var l rawLine
+					err := readFields(b, func(num, wireType int, v uint64, b []byte) error {
+						switch num {
+						case 1:
+							l.functionID = v
+						case 2:
+							l.line = int(v)
+						}
+						return nil
+					})
+					lines = append(lines, l)
+					return err
+				}
+				return nil
+			})
+			locations[id] = lines
+		case 5: // function
+			var id uint64
+			var f rawFunction
+			err = readFields(b, func(num, wireType int, v uint64, b []byte) error {
+				switch num {
+				case 1:
+					id = v
+				case 2:
+					f.name = v
+				case 4:
+					f.file = v
+				}
+				return nil
+			})
+			functions[id] = f
+		case 6: // string_table
+			stringTable = append(stringTable, string(b))
+		case 14: // default_sample_type
+			defaultSampleType = v
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	str := func(i uint64) string {
+		if i >= uint64(len(stringTable)) {
+			return ""
+		}
+		return stringTable[i]
+	}
+	p := &Profile{
+		DefaultSampleType: str(defaultSampleType),
+		Samples:           make([]Sample, len(samples)),
+	}
+	for _, st := range sampleTypes {
+		p.SampleTypes = append(p.SampleTypes, SampleType{Type: str(st[0]), Unit: str(st[1])})
+	}
+	for i, s := range samples {
+		if len(s.values) != len(sampleTypes) {
+			return nil, fmt.Errorf("sample %d has %d values, expected %d", i, len(s.values), len(sampleTypes))
+		}
+		sample := Sample{Values: make([]int64, len(s.values))}
+		for j, v := range s.values {
+			sample.Values[j] = int64(v)
+		}
+		// The lines of a location are the functions inlined at it, starting with the innermost.
+		for _, id := range s.locationIDs {
+			for _, l := range locations[id] {
+				f := functions[l.functionID]
+				sample.Frames = append(sample.Frames, Frame{Function: str(f.name), File: str(f.file), Line: l.line})
+			}
+		}
+		p.Samples[i] = sample
+	}
+	return p, nil
+}
+
+var errInvalidProtobuf = errors.New("invalid protocol buffer")
+
+// readFields calls f with the number and value of each field of the protocol buffer message.
+// Varint and fixed size values are passed in v, and length delimited values in b.
+func readFields(data []byte, f func(num, wireType int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errInvalidProtobuf
+		}
+		data = data[n:]
+		num, wireType := int(key>>3), int(key&7)
+		var v uint64
+		var b []byte
+		switch wireType {
+		case 0:
+			if v, n = binary.Uvarint(data); n <= 0 {
+				return errInvalidProtobuf
+			}
+			data = data[n:]
+		case 1:
+			if len(data) < 8 {
+				return errInvalidProtobuf
+			}
+			v, data = binary.LittleEndian.Uint64(data), data[8:]
+		case 2:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || l > uint64(len(data)-n) {
+				return errInvalidProtobuf
+			}
+			b, data = data[n:n+int(l)], data[n+int(l):]
+		case 5:
+			if len(data) < 4 {
+				return errInvalidProtobuf
+			}
+			v, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		default:
+			return errInvalidProtobuf
+		}
+		if err := f(num, wireType, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendVarints appends the values of a repeated varint field, which may be packed.
+func appendVarints(dst []uint64, wireType int, v uint64, b []byte) ([]uint64, error) {
+	if wireType != 2 {
+		return append(dst, v), nil
+	}
+	for len(b) > 0 {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return dst, errInvalidProtobuf
+		}
+		dst, b = append(dst, v), b[n:]
+	}
+	return dst, nil
+}
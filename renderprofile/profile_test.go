@@ -0,0 +1,77 @@
+package renderprofile
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"runtime/pprof"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func hasFrame(p *Profile, function string) bool {
+	return slices.ContainsFunc(p.Samples, func(s Sample) bool {
+		return slices.ContainsFunc(s.Frames, func(f Frame) bool {
+			return strings.HasPrefix(f.Function, function)
+		})
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("profiles written by runtime/pprof can be read", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := pprof.Lookup("goroutine").WriteTo(&buf, 0); err != nil {
+			t.Fatalf("failed to write profile: %v", err)
+		}
+		p, err := Read(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]SampleType{{Type: "goroutine", Unit: "count"}}, p.SampleTypes); diff != "" {
+			t.Error(diff)
+		}
+		if !hasFrame(p, "github.com/a-h/templ/renderprofile.TestRead") {
+			t.Error("expected the stack of the test to be in the profile")
+		}
+	})
+	t.Run("invalid profiles are an error", func(t *testing.T) {
+		if _, err := Read(strings.NewReader("\x0a\xff")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestRender(t *testing.T) {
+	var sink []byte
+	c := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		sink = make([]byte, 1024)
+		_, err := w.Write(sink)
+		return err
+	})
+	cpu, allocs, err := Render(context.Background(), c, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = cpu.Report("cpu"); err != nil {
+		t.Errorf("expected a CPU profile: %v", err)
+	}
+	if _, err = allocs.Report("alloc_space"); err != nil {
+		t.Errorf("expected an allocation profile: %v", err)
+	}
+	if !hasFrame(allocs, "github.com/a-h/templ/renderprofile.TestRender") {
+		t.Error("expected the allocations of the component to be in the profile")
+	}
+	t.Run("render errors are returned", func(t *testing.T) {
+		c := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return io.ErrShortWrite
+		})
+		if _, _, err := Render(context.Background(), c, time.Millisecond); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package renderprofile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// Render renders the component repeatedly for at least d, and returns a CPU profile, and a
+// profile of the allocations made while rendering. Every allocation is recorded, so rendering
+// is slower than usual.
+//
+// Render uses the profilers of the runtime/pprof package, so it returns an error if CPU
+// profiling has already been started, and mustn't be called concurrently.
+func Render(ctx context.Context, c templ.Component, d time.Duration) (cpu, allocs *Profile, err error) {
+	// Record every allocation. The rate is also used to scale the allocations when the
+	// profiles are written, so it's kept until both have been written.
+	previousRate := runtime.MemProfileRate
+	runtime.MemProfileRate = 1
+	defer func() { runtime.MemProfileRate = previousRate }()
+
+	before, err := writeAllocsProfile()
+	if err != nil {
+		return nil, nil, err
+	}
+	var cpuProfile bytes.Buffer
+	if err = pprof.StartCPUProfile(&cpuProfile); err != nil {
+		return nil, nil, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+	for start, renders := time.Now(), 0; renders == 0 || time.Since(start) < d; renders++ {
+		if err = c.Render(ctx, io.Discard); err != nil {
+			break
+		}
+	}
+	pprof.StopCPUProfile()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render component: %w", err)
+	}
+	after, err := writeAllocsProfile()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cpu, err = Read(&cpuProfile); err != nil {
+		return nil, nil, err
+	}
+	if allocs, err = subtract(after, before); err != nil {
+		return nil, nil, err
+	}
+	return cpu, allocs, nil
+}
+
+func writeAllocsProfile() (*Profile, error) {
+	// Allocations are added to the profile by garbage collection.
+	runtime.GC()
+	var buf bytes.Buffer
+	if err := pprof.Lookup("allocs").WriteTo(&buf, 0); err != nil {
+		return nil, fmt.Errorf("failed to write allocs profile: %w", err)
+	}
+	return Read(&buf)
+}
+
+// subtract returns the samples of the cumulative profile a that were added since b.
+func subtract(a, b *Profile) (*Profile, error) {
+	if len(a.SampleTypes) != len(b.SampleTypes) {
+		return nil, fmt.Errorf("profiles have different sample types")
+	}
+	diff := &Profile{
+		SampleTypes:       a.SampleTypes,
+		DefaultSampleType: a.DefaultSampleType,
+	}
+	index := map[string]int{}
+	add := func(s Sample, sign int64) {
+		key := stackKey(s.Frames)
+		i, ok := index[key]
+		if !ok {
+			i = len(diff.Samples)
+			index[key] = i
+			diff.Samples = append(diff.Samples, Sample{Frames: s.Frames, Values: make([]int64, len(a.SampleTypes))})
+		}
+		for j, v := range s.Values {
+			diff.Samples[i].Values[j] += sign * v
+		}
+	}
+	for _, s := range a.Samples {
+		add(s, 1)
+	}
+	for _, s := range b.Samples {
+		add(s, -1)
+	}
+	diff.Samples = slices.DeleteFunc(diff.Samples, func(s Sample) bool {
+		return !slices.ContainsFunc(s.Values, func(v int64) bool { return v > 0 })
+	})
+	return diff, nil
+}
+
+func stackKey(frames []Frame) string {
+	var sb strings.Builder
+	for _, f := range frames {
+		sb.WriteString(f.Function)
+		sb.WriteByte(' ')
+		sb.WriteString(f.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(f.Line))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
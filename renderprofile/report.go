@@ -0,0 +1,402 @@
+package renderprofile
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/a-h/templ/parser/v2"
+	templruntime "github.com/a-h/templ/runtime"
+	"github.com/a-h/templ/runtime/stacktrace"
+)
+
+// Report is the time or allocations spent in each component, and each type of template node.
+//
+// Only the samples in code generated by templ are included. Node types are only reported for
+// code generated with templ generate -stack-trace-maps, see the stacktrace package, and the
+// .templ files must be available at the paths they were built from.
+type Report struct {
+	SampleType SampleType
+	// Total of the samples in generated code.
+	Total int64
+	// Components, sorted by their total, largest first.
+	Components []Entry
+	// NodeTypes, e.g. Element, StringExpression or CallTemplateExpression, sorted by their
+	// total, largest first. The node type of a sample is estimated from the line of the .templ
+	// file, so a sample may be reported against another node on the same line.
+	NodeTypes []Entry
+	// Stacks of components, followed by the node type, sorted by name.
+	Stacks []Stack
+}
+
+// Entry is the sum of the samples of a component or node type.
+type Entry struct {
+	Name string
+	// Self is the sum of the samples in the component itself.
+	Self int64
+	// Total is the sum of the samples in the component, and the components it renders.
+	Total int64
+}
+
+// Stack is the sum of the samples with the same stack of components and node type.
+type Stack struct {
+	// Frames are the names of the components, starting with the outermost, followed by the
+	// node type, e.g. ["pages.Home", "components.Card", "StringExpression"].
+	Frames []string
+	Value  int64
+}
+
+// Report sums the samples of the sample type, e.g. cpu or alloc_space. If sampleType is empty,
+// the default sample type of the profile is used.
+func (p *Profile) Report(sampleType string) (*Report, error) {
+	index, err := p.sampleTypeIndex(sampleType)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{SampleType: p.SampleTypes[index]}
+	components := map[string]*Entry{}
+	nodeTypes := map[string]*Entry{}
+	stacks := map[string]*Stack{}
+	res := newResolver()
+	for _, s := range p.Samples {
+		v := s.Values[index]
+		if v == 0 {
+			continue
+		}
+		names, nodeType, ok := res.stack(s.Frames)
+		if !ok {
+			continue
+		}
+		r.Total += v
+		for i, name := range names {
+			// Recursive components are only counted once.
+			if !slices.Contains(names[:i], name) {
+				entry(components, name).Total += v
+			}
+		}
+		entry(components, names[len(names)-1]).Self += v
+		nt := entry(nodeTypes, nodeType)
+		nt.Self += v
+		nt.Total += v
+		frames := append(names, nodeType)
+		key := strings.Join(frames, ";")
+		if stacks[key] == nil {
+			stacks[key] = &Stack{Frames: frames}
+		}
+		stacks[key].Value += v
+	}
+	r.Components = sortedEntries(components)
+	r.NodeTypes = sortedEntries(nodeTypes)
+	for _, s := range stacks {
+		r.Stacks = append(r.Stacks, *s)
+	}
+	slices.SortFunc(r.Stacks, func(a, b Stack) int {
+		return slices.Compare(a.Frames, b.Frames)
+	})
+	return r, nil
+}
+
+func (p *Profile) sampleTypeIndex(sampleType string) (int, error) {
+	if len(p.SampleTypes) == 0 {
+		return 0, fmt.Errorf("the profile has no sample types")
+	}
+	if sampleType == "" {
+		sampleType = p.DefaultSampleType
+	}
+	if sampleType == "" {
+		return len(p.SampleTypes) - 1, nil
+	}
+	types := make([]string, len(p.SampleTypes))
+	for i, st := range p.SampleTypes {
+		if st.Type == sampleType {
+			return i, nil
+		}
+		types[i] = st.Type
+	}
+	return 0, fmt.Errorf("sample type %q not found, expected one of: %s", sampleType, strings.Join(types, ", "))
+}
+
+func entry(m map[string]*Entry, name string) *Entry {
+	e, ok := m[name]
+	if !ok {
+		e = &Entry{Name: name}
+		m[name] = e
+	}
+	return e
+}
+
+func sortedEntries(m map[string]*Entry) (entries []Entry) {
+	for _, e := range m {
+		entries = append(entries, *e)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return entries
+}
+
+// WriteText writes the report as tables of components and node types.
+func (r *Report) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s: %s in templ components\n\n", r.SampleType.Type, r.format(r.Total)); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprint(tw, "Self\tSelf%\tTotal\tTotal%\t\tComponent\n")
+	for _, e := range r.Components {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\t%s\n", r.format(e.Self), r.percent(e.Self), r.format(e.Total), r.percent(e.Total), e.Name)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprint(tw, "Self\tSelf%\t\tNode type\n")
+	for _, e := range r.NodeTypes {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t\t%s\n", r.format(e.Self), r.percent(e.Self), e.Name)
+	}
+	return tw.Flush()
+}
+
+// WriteFolded writes the stacks in the folded format used by flame graph tools, such as
+// speedscope and flamegraph.pl, e.g. "pages.Home;components.Card;StringExpression 1500".
+func (r *Report) WriteFolded(w io.Writer) error {
+	for _, s := range r.Stacks {
+		if _, err := fmt.Fprintf(w, "%s %d\n", strings.Join(s.Frames, ";"), s.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Report) format(v int64) string {
+	switch r.SampleType.Unit {
+	case "nanoseconds":
+		return time.Duration(v).String()
+	case "bytes":
+		return formatBytes(v)
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+func (r *Report) percent(v int64) string {
+	if r.Total == 0 {
+		return "0.00%"
+	}
+	return fmt.Sprintf("%.2f%%", float64(v)*100/float64(r.Total))
+}
+
+func formatBytes(v int64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	f := float64(v)
+	i := 0
+	for ; (f >= 1000 || f <= -1000) && i < len(units)-1; i++ {
+		f /= 1000
+	}
+	if i == 0 {
+		return strconv.FormatInt(v, 10) + units[0]
+	}
+	return strconv.FormatFloat(f, 'f', 2, 64) + units[i]
+}
+
+// resolver finds the components and node type of a stack.
+type resolver struct {
+	// nodes are the nodes of each .templ file, in the order they appear.
+	nodes map[string][]nodeStart
+	// loaded are the generated files whose line maps have been read.
+	loaded map[string]bool
+}
+
+type nodeStart struct {
+	// line is the zero based line the node starts on.
+	line     int
+	nodeType string
+}
+
+func newResolver() *resolver {
+	return &resolver{nodes: map[string][]nodeStart{}, loaded: map[string]bool{}}
+}
+
+// stack returns the names of the components in the frames, starting with the outermost, and
+// the type of the node being rendered by the innermost component. It returns false if none of
+// the frames are in generated code.
+func (res *resolver) stack(frames []Frame) (names []string, nodeType string, ok bool) {
+	var innermost Frame
+	for i := len(frames) - 1; i >= 0; i-- {
+		f := frames[i]
+		if !strings.HasSuffix(f.File, "_templ.go") {
+			continue
+		}
+		name := componentName(f.Function)
+		if len(names) == 0 || names[len(names)-1] != name {
+			names = append(names, name)
+		}
+		innermost = f
+	}
+	if len(names) == 0 {
+		return nil, "", false
+	}
+	return names, res.nodeType(innermost.File, innermost.Line), true
+}
+
+func (res *resolver) nodeType(goFile string, line int) string {
+	templFile, templLine, ok := stacktrace.Lookup(goFile, line)
+	if !ok && !res.loaded[goFile] {
+		// The profile is usually of another program, so the line map is read from the file.
+		res.loaded[goFile] = true
+		if templruntime.LoadLineMap(goFile) == nil {
+			templFile, templLine, ok = stacktrace.Lookup(goFile, line)
+		}
+	}
+	if !ok {
+		return "unknown"
+	}
+	nodes, ok := res.nodes[templFile]
+	if !ok {
+		if tf, err := parser.Parse(templFile); err == nil {
+			nodes = appendTemplateFileNodes(nil, tf)
+		}
+		res.nodes[templFile] = nodes
+	}
+	nodeType := "unknown"
+	for _, n := range nodes {
+		if n.line > templLine-1 {
+			break
+		}
+		nodeType = n.nodeType
+	}
+	return nodeType
+}
+
+// componentName returns the name of the function that contains a frame, without its package
+// path, or the suffixes of function literals, e.g. "pages.Home" for
+// "github.com/example/app/pages.Home.func1.2".
+func componentName(function string) string {
+	name := function
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for {
+		i := strings.LastIndex(name, ".")
+		if i < 0 || !isFuncLiteralSuffix(name[i+1:]) {
+			return name
+		}
+		name = name[:i]
+	}
+}
+
+func isFuncLiteralSuffix(s string) bool {
+	s = strings.TrimPrefix(s, "func")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func appendTemplateFileNodes(dst []nodeStart, tf *parser.TemplateFile) []nodeStart {
+	for _, n := range tf.Nodes {
+		switch n := n.(type) {
+		case *parser.HTMLTemplate:
+			dst = append(dst, nodeStart{line: int(n.Range.From.Line), nodeType: "HTMLTemplate"})
+			dst = appendNodes(dst, n.Children)
+		case *parser.CSSTemplate:
+			dst = append(dst, nodeStart{line: int(n.Range.From.Line), nodeType: "CSSTemplate"})
+		case *parser.ScriptTemplate:
+			dst = append(dst, nodeStart{line: int(n.Range.From.Line), nodeType: "ScriptTemplate"})
+		}
+	}
+	return dst
+}
+
+func appendNodes(dst []nodeStart, nodes []parser.Node) []nodeStart {
+	for _, n := range nodes {
+		if r, ok := nodeRange(n); ok {
+			dst = append(dst, nodeStart{line: int(r.From.Line), nodeType: nodeTypeName(n)})
+		}
+		if e, ok := n.(*parser.Element); ok {
+			dst = appendAttributes(dst, e.Attributes)
+		}
+		if c, ok := n.(parser.CompositeNode); ok {
+			dst = appendNodes(dst, c.ChildNodes())
+		}
+	}
+	return dst
+}
+
+func appendAttributes(dst []nodeStart, attrs []parser.Attribute) []nodeStart {
+	for _, attr := range attrs {
+		var expr parser.Expression
+		switch attr := attr.(type) {
+		case *parser.ExpressionAttribute:
+			expr = attr.Expression
+		case *parser.BoolExpressionAttribute:
+			expr = attr.Expression
+		case *parser.SpreadAttributes:
+			expr = attr.Expression
+		case *parser.ConditionalAttribute:
+			dst = append(dst, nodeStart{line: int(attr.Expression.Range.From.Line), nodeType: "ConditionalAttribute"})
+			dst = appendAttributes(dst, attr.Then)
+			dst = appendAttributes(dst, attr.Else)
+			continue
+		default:
+			continue
+		}
+		dst = append(dst, nodeStart{line: int(expr.Range.From.Line), nodeType: nodeTypeName(attr)})
+	}
+	return dst
+}
+
+// nodeRange returns the range of the part of the node that's on its first line.
+func nodeRange(n parser.Node) (r parser.Range, ok bool) {
+	switch n := n.(type) {
+	case *parser.Element:
+		return n.NameRange, true
+	case *parser.Text:
+		return n.Range, true
+	case *parser.HTMLComment:
+		return n.Range, true
+	case *parser.StringExpression:
+		return n.Expression.Range, true
+	case *parser.CallTemplateExpression:
+		return n.Expression.Range, true
+	case *parser.TemplElementExpression:
+		return n.Expression.Range, true
+	case *parser.IfExpression:
+		return n.Expression.Range, true
+	case *parser.SwitchExpression:
+		return n.Expression.Range, true
+	case *parser.ForExpression:
+		return n.Expression.Range, true
+	case *parser.GoCode:
+		return n.Expression.Range, true
+	case *parser.SlotExpression:
+		return n.NameRange, true
+	case *parser.SlotDefinition:
+		return n.NameRange, true
+	case *parser.BlockDefinition:
+		return n.NameRange, true
+	case *parser.FragmentDefinition:
+		return n.NameRange, true
+	case *parser.CacheExpression:
+		return n.Expression.Range, true
+	}
+	return r, false
+}
+
+func nodeTypeName(v any) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", v), "*parser.")
+}
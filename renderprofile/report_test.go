@@ -0,0 +1,177 @@
+package renderprofile
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+const template = `package test
+
+templ Page(names []string) {
+	for _, name := range names {
+		@Card(name)
+	}
+}
+
+templ Card(name string) {
+	<div>
+		{ name }
+	</div>
+}
+`
+
+// writeTemplate writes the template, and the code generated from it, to dir, in the same way
+// that templ generate -stack-trace-maps does.
+func writeTemplate(t *testing.T, dir string) (goFile string, goLines []string) {
+	goFile = filepath.Join(dir, "template_templ.go")
+	tf, err := parser.ParseString(template)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var buf bytes.Buffer
+	op, err := generator.Generate(tf, &buf, generator.WithFileName("template.templ"))
+	if err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		t.Fatalf("failed to format: %v", err)
+	}
+	code = generator.AppendLineMap(code, buf.Bytes(), op.SourceMap, "template.templ")
+	if err = os.WriteFile(filepath.Join(dir, "template.templ"), []byte(template), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(goFile, code, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return goFile, strings.Split(string(code), "\n")
+}
+
+func lineContaining(t *testing.T, lines []string, s string) int {
+	for i, line := range lines {
+		if strings.Contains(line, s) {
+			return i + 1
+		}
+	}
+	t.Fatalf("no line contains %q", s)
+	return 0
+}
+
+func TestReport(t *testing.T) {
+	goFile, goLines := writeTemplate(t, t.TempDir())
+	callLine := lineContaining(t, goLines, "templruntime.NilSafe(Card(name)).Render(")
+	expressionLine := lineContaining(t, goLines, "templ.JoinStringErrs(name)")
+
+	page := Frame{Function: "github.com/example/app/test.Page.func1", File: goFile, Line: callLine}
+	card := Frame{Function: "github.com/example/app/test.Card.func1", File: goFile, Line: expressionLine}
+	generated := Frame{Function: "github.com/a-h/templ/runtime.GeneratedTemplate.func1", File: "/templ/runtime/runtime.go", Line: 30}
+	main := Frame{Function: "main.main", File: "/app/main.go", Line: 12}
+	p := &Profile{
+		SampleTypes: []SampleType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+		Samples: []Sample{
+			{
+				Frames: []Frame{
+					{Function: "github.com/a-h/templ.EscapeString", File: "/templ/runtime.go", Line: 100},
+					card, generated, page, generated, main,
+				},
+				Values: []int64{3, 3000},
+			},
+			{Frames: []Frame{page, generated, main}, Values: []int64{1, 1000}},
+			{Frames: []Frame{main}, Values: []int64{10, 10000}},
+		},
+	}
+
+	r, err := p.Report("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Total != 4000 {
+		t.Errorf("expected total of 4000, got %d", r.Total)
+	}
+	expectedComponents := []Entry{
+		{Name: "test.Page", Self: 1000, Total: 4000},
+		{Name: "test.Card", Self: 3000, Total: 3000},
+	}
+	if diff := cmp.Diff(expectedComponents, r.Components); diff != "" {
+		t.Error(diff)
+	}
+	expectedNodeTypes := []Entry{
+		{Name: "StringExpression", Self: 3000, Total: 3000},
+		{Name: "TemplElementExpression", Self: 1000, Total: 1000},
+	}
+	if diff := cmp.Diff(expectedNodeTypes, r.NodeTypes); diff != "" {
+		t.Error(diff)
+	}
+
+	t.Run("folded", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.WriteFolded(&buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "test.Page;TemplElementExpression 1000\n" +
+			"test.Page;test.Card;StringExpression 3000\n"
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.WriteText(&buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := `cpu: 4µs in templ components
+
+  Self   Self%  Total   Total%  Component
+   1µs  25.00%    4µs  100.00%  test.Page
+   3µs  75.00%    3µs   75.00%  test.Card
+
+  Self   Self%  Node type
+   3µs  75.00%  StringExpression
+   1µs  25.00%  TemplElementExpression
+`
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("other sample types can be reported", func(t *testing.T) {
+		r, err := p.Report("samples")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Total != 4 {
+			t.Errorf("expected total of 4, got %d", r.Total)
+		}
+	})
+	t.Run("unknown sample types are an error", func(t *testing.T) {
+		_, err := p.Report("alloc_space")
+		expected := `sample type "alloc_space" not found, expected one of: samples, cpu`
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected error %q, got %v", expected, err)
+		}
+	})
+}
+
+func TestComponentName(t *testing.T) {
+	tests := []struct {
+		function string
+		expected string
+	}{
+		{function: "github.com/example/app/pages.Home.func1", expected: "pages.Home"},
+		{function: "github.com/example/app/pages.Home.func1.2", expected: "pages.Home"},
+		{function: "github.com/example/app/pages.Home.Home.func1.1", expected: "pages.Home.Home"},
+		{function: "pages.(*Page).Render.func1", expected: "pages.(*Page).Render"},
+	}
+	for _, tt := range tests {
+		if actual := componentName(tt.function); actual != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.function, tt.expected, actual)
+		}
+	}
+}
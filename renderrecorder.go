@@ -0,0 +1,42 @@
+package templ
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// RenderRecorder receives the timings and output sizes of generated components as they're
+// rendered, see WithRenderRecorder.
+type RenderRecorder interface {
+	// RecordRender is called after a component has rendered. The duration and byte count
+	// include the output of any child components. name is the package qualified name of the
+	// component, e.g. "pages.Home".
+	RecordRender(name string, d time.Duration, bytes int64, err error)
+}
+
+// renderRecorderUsed is set when a recorder is first added to a context, so that rendering
+// doesn't need to check the context for a recorder unless one has been used.
+var renderRecorderUsed atomic.Bool
+
+// WithRenderRecorder adds a recorder to the context, that receives the timings of each
+// generated component that is rendered with the context. It's intended for local
+// performance investigation, and adds overhead to rendering.
+func WithRenderRecorder(ctx context.Context, r RenderRecorder) context.Context {
+	renderRecorderUsed.Store(true)
+	ctx, v := getContext(ctx)
+	v.renderRecorder = r
+	return ctx
+}
+
+// GetRenderRecorder returns the recorder in the context, or nil if there isn't one.
+func GetRenderRecorder(ctx context.Context) RenderRecorder {
+	if !renderRecorderUsed.Load() {
+		return nil
+	}
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	if !ok {
+		return nil
+	}
+	return v.renderRecorder
+}
@@ -0,0 +1,136 @@
+// Package renderstats collects the render timings and output sizes of the components used
+// by recent HTTP requests, and serves them on a debug page, for local performance
+// investigation without setting up tracing.
+//
+//	stats := renderstats.NewCollector(100)
+//	mux.Handle("/", stats.Middleware(appHandler))
+//	mux.Handle("/debug/templ", stats.Handler())
+package renderstats
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// Component is the render statistics of a component within a request.
+type Component struct {
+	// Name of the component, e.g. "pages.Home".
+	Name string `json:"name"`
+	// Count is the number of times the component was rendered.
+	Count int `json:"count"`
+	// Duration is the total time spent rendering the component, including its children.
+	Duration time.Duration `json:"duration"`
+	// Bytes is the total size of the output of the component, including its children.
+	Bytes int64 `json:"bytes"`
+	// Errors is the number of renders that returned an error.
+	Errors int `json:"errors"`
+}
+
+// Request is the render statistics of a request.
+type Request struct {
+	Method   string        `json:"method"`
+	Path     string        `json:"path"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	// Components rendered by the request, in the order that they first finished rendering.
+	Components []Component `json:"components"`
+}
+
+// Collector keeps the render statistics of the most recent requests.
+type Collector struct {
+	m        sync.Mutex
+	size     int
+	requests []Request
+	next     int
+}
+
+// NewCollector creates a collector that keeps the statistics of the last size requests.
+func NewCollector(size int) *Collector {
+	if size < 1 {
+		size = 1
+	}
+	return &Collector{
+		size:     size,
+		requests: make([]Request, 0, size),
+	}
+}
+
+// Middleware records the render statistics of the components rendered by next.
+func (c *Collector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &recorder{index: map[string]int{}}
+		start := time.Now()
+		next.ServeHTTP(w, r.WithContext(templ.WithRenderRecorder(r.Context(), rec)))
+		c.add(Request{
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Start:      start,
+			Duration:   time.Since(start),
+			Components: rec.result(),
+		})
+	})
+}
+
+func (c *Collector) add(r Request) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if len(c.requests) < c.size {
+		c.requests = append(c.requests, r)
+		return
+	}
+	c.requests[c.next] = r
+	c.next = (c.next + 1) % c.size
+}
+
+// Requests returns the statistics of the most recent requests, newest first.
+func (c *Collector) Requests() []Request {
+	c.m.Lock()
+	defer c.m.Unlock()
+	requests := make([]Request, 0, len(c.requests))
+	for i := range len(c.requests) {
+		// The oldest request is at c.next once the buffer is full.
+		idx := (c.next - 1 - i + 2*len(c.requests)) % len(c.requests)
+		requests = append(requests, c.requests[idx])
+	}
+	return requests
+}
+
+// Handler serves a page showing the statistics of the most recent requests.
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		templ.Handler(statsPage(c.Requests())).ServeHTTP(w, r)
+	})
+}
+
+type recorder struct {
+	m          sync.Mutex
+	index      map[string]int
+	components []Component
+}
+
+func (r *recorder) RecordRender(name string, d time.Duration, bytes int64, err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	i, ok := r.index[name]
+	if !ok {
+		i = len(r.components)
+		r.index[name] = i
+		r.components = append(r.components, Component{Name: name})
+	}
+	c := &r.components[i]
+	c.Count++
+	c.Duration += d
+	c.Bytes += bytes
+	if err != nil {
+		c.Errors++
+	}
+}
+
+func (r *recorder) result() []Component {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]Component(nil), r.components...)
+}
@@ -0,0 +1,70 @@
+package renderstats
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestCollector(t *testing.T) {
+	t.Run("the components rendered by a request are recorded", func(t *testing.T) {
+		c := NewCollector(10)
+		h := c.Middleware(templ.Handler(statsPage([]Request{{Method: "GET", Path: "/a"}, {Method: "GET", Path: "/b"}})))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/page", nil))
+
+		requests := c.Requests()
+		if len(requests) != 1 {
+			t.Fatalf("expected 1 request, got %d", len(requests))
+		}
+		r := requests[0]
+		if r.Method != http.MethodGet || r.Path != "/page" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Path)
+		}
+		if len(r.Components) != 2 {
+			t.Fatalf("expected 2 components, got %#v", r.Components)
+		}
+		// Children finish rendering before their parents.
+		child, parent := r.Components[0], r.Components[1]
+		if child.Name != "renderstats.requestStats" || child.Count != 2 {
+			t.Errorf("unexpected child component stats: %#v", child)
+		}
+		if parent.Name != "renderstats.statsPage" || parent.Count != 1 {
+			t.Errorf("unexpected parent component stats: %#v", parent)
+		}
+		if parent.Bytes <= child.Bytes {
+			t.Errorf("expected the parent bytes (%d) to include the child bytes (%d)", parent.Bytes, child.Bytes)
+		}
+	})
+	t.Run("only the most recent requests are kept, newest first", func(t *testing.T) {
+		c := NewCollector(3)
+		h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		for i := range 5 {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%d", i), nil))
+		}
+		var paths []string
+		for _, r := range c.Requests() {
+			paths = append(paths, r.Path)
+		}
+		if strings.Join(paths, ",") != "/4,/3,/2" {
+			t.Errorf("expected /4,/3,/2, got %v", paths)
+		}
+	})
+	t.Run("the handler renders the statistics", func(t *testing.T) {
+		c := NewCollector(10)
+		h := c.Middleware(templ.Handler(requestStats(Request{})))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/submit", nil))
+
+		w := httptest.NewRecorder()
+		c.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/templ", nil))
+		body := w.Body.String()
+		for _, expected := range []string{"POST /submit", "renderstats.requestStats"} {
+			if !strings.Contains(body, expected) {
+				t.Errorf("expected %q in the output, got:\n%s", expected, body)
+			}
+		}
+	})
+}
@@ -0,0 +1,253 @@
+package templ
+
+import (
+	"cmp"
+	"context"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RequestHints describe the client that made an HTTP request, from the headers of the request,
+// so that components can render differently for it, e.g. render a partial page for htmx
+// requests, without the request being passed to every component. See GetRequestHints.
+type RequestHints struct {
+	// Accept is the Accept header, e.g. "text/html,application/xhtml+xml".
+	Accept string
+	// Languages are the language tags of the Accept-Language header, most preferred first,
+	// e.g. ["en-GB", "en", "fr"].
+	Languages []string
+
+	// HTMX is true if the request was made by htmx, i.e. the HX-Request header is "true".
+	HTMX bool
+	// HTMXBoosted is true if the request was made by an element that uses hx-boost.
+	HTMXBoosted bool
+	// HTMXHistoryRestore is true if the request was made to restore the history, because the
+	// page wasn't in the history cache.
+	HTMXHistoryRestore bool
+	// HTMXTarget is the id of the target element of the request, if it has one.
+	HTMXTarget string
+	// HTMXTrigger is the id of the element that triggered the request, if it has one.
+	HTMXTrigger string
+
+	// Mobile is true if the Sec-CH-UA-Mobile client hint reports a mobile device.
+	Mobile bool
+	// Platform is the operating system from the Sec-CH-UA-Platform client hint, e.g. "Windows".
+	Platform string
+	// Brands are the browser brands from the Sec-CH-UA client hint.
+	Brands []Brand
+	// PrefersColorScheme is the Sec-CH-Prefers-Color-Scheme client hint, "light" or "dark".
+	PrefersColorScheme string
+	// PrefersReducedMotion is true if the Sec-CH-Prefers-Reduced-Motion client hint is "reduce".
+	PrefersReducedMotion bool
+	// SaveData is true if the Save-Data header is "on".
+	SaveData bool
+}
+
+// Brand is a browser brand and its significant version, e.g. "Chromium" and "124".
+type Brand struct {
+	Name    string
+	Version string
+}
+
+// NewRequestHints reads the hints from the headers of the request.
+//
+// Browsers only send most Sec-CH-* client hints if the server asks for them with the
+// Accept-CH response header.
+func NewRequestHints(r *http.Request) (h RequestHints) {
+	h.Accept = r.Header.Get("Accept")
+	h.Languages = parseAcceptLanguage(r.Header.Get("Accept-Language"))
+	h.HTMX = r.Header.Get("HX-Request") == "true"
+	h.HTMXBoosted = r.Header.Get("HX-Boosted") == "true"
+	h.HTMXHistoryRestore = r.Header.Get("HX-History-Restore-Request") == "true"
+	h.HTMXTarget = r.Header.Get("HX-Target")
+	h.HTMXTrigger = r.Header.Get("HX-Trigger")
+	h.Mobile = r.Header.Get("Sec-CH-UA-Mobile") == "?1"
+	h.Platform = unquote(r.Header.Get("Sec-CH-UA-Platform"))
+	h.Brands = parseBrands(r.Header.Get("Sec-CH-UA"))
+	h.PrefersColorScheme = unquote(r.Header.Get("Sec-CH-Prefers-Color-Scheme"))
+	h.PrefersReducedMotion = unquote(r.Header.Get("Sec-CH-Prefers-Reduced-Motion")) == "reduce"
+	h.SaveData = strings.EqualFold(r.Header.Get("Save-Data"), "on")
+	return h
+}
+
+// Partial returns true if the request was made by htmx to replace part of the page, so
+// the layout of the page shouldn't be rendered. Boosted requests and history restore
+// requests replace the whole page, so they aren't partial.
+func (h RequestHints) Partial() bool {
+	return h.HTMX && !h.HTMXBoosted && !h.HTMXHistoryRestore
+}
+
+// Accepts returns true if the Accept header includes the media type, e.g. "application/json".
+// All media types are accepted if there's no Accept header.
+func (h RequestHints) Accepts(mediaType string) bool {
+	if strings.TrimSpace(h.Accept) == "" {
+		return true
+	}
+	mediaType = strings.ToLower(mediaType)
+	typ, _, _ := strings.Cut(mediaType, "/")
+	for _, r := range strings.Split(h.Accept, ",") {
+		rangeType, params, err := mime.ParseMediaType(strings.TrimSpace(r))
+		if err != nil || quality(params) <= 0 {
+			continue
+		}
+		if rangeType == "*/*" || rangeType == mediaType || rangeType == typ+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Locale returns the most preferred language of the Accept-Language header that's in
+// supported, or the first supported language if none of them are. A language matches a
+// supported language with the same primary subtag, e.g. "en-GB" matches "en". If supported
+// is empty, the most preferred language is returned.
+func (h RequestHints) Locale(supported ...string) string {
+	if len(supported) == 0 {
+		if len(h.Languages) == 0 {
+			return ""
+		}
+		return h.Languages[0]
+	}
+	for _, lang := range h.Languages {
+		for _, s := range supported {
+			if strings.EqualFold(lang, s) {
+				return s
+			}
+		}
+		primary, _, _ := strings.Cut(lang, "-")
+		for _, s := range supported {
+			if strings.EqualFold(primary, s) {
+				return s
+			}
+		}
+	}
+	return supported[0]
+}
+
+// WithRequestHints adds the hints to the context, see GetRequestHints. ComponentHandler
+// adds the hints of the request it's handling if it's created with WithHints.
+func WithRequestHints(ctx context.Context, h RequestHints) context.Context {
+	ctx, v := getContext(ctx)
+	v.requestHints = h
+	return ctx
+}
+
+// GetRequestHints returns the hints in the context, or empty hints if there are none.
+//
+//	if templ.GetRequestHints(ctx).Partial() {
+//		@results(items)
+//	} else {
+//		@layout() {
+//			@results(items)
+//		}
+//	}
+func GetRequestHints(ctx context.Context) RequestHints {
+	if ctx == nil {
+		return RequestHints{}
+	}
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	if !ok {
+		return RequestHints{}
+	}
+	return v.requestHints
+}
+
+// RequestHintsMiddleware adds the hints of each request to its context, for components that
+// are rendered without a ComponentHandler, and adds the headers that they're read from to the
+// Vary header of the response.
+func RequestHintsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addRequestHintsVary(w.Header())
+		next.ServeHTTP(w, r.WithContext(WithRequestHints(r.Context(), NewRequestHints(r))))
+	})
+}
+
+// requestHintHeaders are the request headers that NewRequestHints reads.
+var requestHintHeaders = strings.Join([]string{
+	"Accept",
+	"Accept-Language",
+	"HX-Request",
+	"HX-Boosted",
+	"HX-History-Restore-Request",
+	"HX-Target",
+	"HX-Trigger",
+	"Sec-CH-UA-Mobile",
+	"Sec-CH-UA-Platform",
+	"Sec-CH-UA",
+	"Sec-CH-Prefers-Color-Scheme",
+	"Sec-CH-Prefers-Reduced-Motion",
+	"Save-Data",
+}, ", ")
+
+// addRequestHintsVary adds the request hint headers to the Vary header, so that caches don't
+// return responses rendered for one client to another.
+func addRequestHintsVary(h http.Header) {
+	h.Add("Vary", requestHintHeaders)
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
+
+func quality(params map[string]string) float64 {
+	q, ok := params["q"]
+	if !ok {
+		return 1
+	}
+	f, err := strconv.ParseFloat(q, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			var err error
+			if q, err = strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			languages = append(languages, language{tag: tag, q: q})
+		}
+	}
+	slices.SortStableFunc(languages, func(a, b language) int {
+		return cmp.Compare(b.q, a.q)
+	})
+	tags := make([]string, len(languages))
+	for i, l := range languages {
+		tags[i] = l.tag
+	}
+	return tags
+}
+
+// brand matches an item of the Sec-CH-UA header, e.g. `"Chromium";v="124"`.
+var brand = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*;\s*v\s*=\s*"((?:[^"\\]|\\.)*)"`)
+
+func parseBrands(header string) (brands []Brand) {
+	for _, m := range brand.FindAllStringSubmatch(header, -1) {
+		brands = append(brands, Brand{Name: m[1], Version: m[2]})
+	}
+	return brands
+}
@@ -0,0 +1,171 @@
+package templ_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewRequestHints(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+	r.Header.Set("Accept-Language", "fr;q=0.8, en-GB, en;q=0.9, *;q=0.5, de;q=0")
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Target", "results")
+	r.Header.Set("HX-Trigger", "search")
+	r.Header.Set("Sec-CH-UA", `"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`)
+	r.Header.Set("Sec-CH-UA-Mobile", "?1")
+	r.Header.Set("Sec-CH-UA-Platform", `"Android"`)
+	r.Header.Set("Sec-CH-Prefers-Color-Scheme", `"dark"`)
+	r.Header.Set("Sec-CH-Prefers-Reduced-Motion", `"reduce"`)
+	r.Header.Set("Save-Data", "on")
+
+	expected := templ.RequestHints{
+		Accept:      "text/html,application/xhtml+xml,*/*;q=0.8",
+		Languages:   []string{"en-GB", "en", "fr"},
+		HTMX:        true,
+		HTMXTarget:  "results",
+		HTMXTrigger: "search",
+		Mobile:      true,
+		Platform:    "Android",
+		Brands: []templ.Brand{
+			{Name: "Chromium", Version: "124"},
+			{Name: "Google Chrome", Version: "124"},
+			{Name: "Not-A.Brand", Version: "99"},
+		},
+		PrefersColorScheme:   "dark",
+		PrefersReducedMotion: true,
+		SaveData:             true,
+	}
+	if diff := cmp.Diff(expected, templ.NewRequestHints(r)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRequestHintsPartial(t *testing.T) {
+	tests := []struct {
+		name     string
+		hints    templ.RequestHints
+		expected bool
+	}{
+		{name: "full page requests aren't partial", hints: templ.RequestHints{}, expected: false},
+		{name: "htmx requests are partial", hints: templ.RequestHints{HTMX: true}, expected: true},
+		{name: "boosted requests aren't partial", hints: templ.RequestHints{HTMX: true, HTMXBoosted: true}, expected: false},
+		{name: "history restore requests aren't partial", hints: templ.RequestHints{HTMX: true, HTMXHistoryRestore: true}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.hints.Partial(); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRequestHintsAccepts(t *testing.T) {
+	tests := []struct {
+		accept    string
+		mediaType string
+		expected  bool
+	}{
+		{accept: "", mediaType: "application/json", expected: true},
+		{accept: "text/html", mediaType: "text/html", expected: true},
+		{accept: "text/html", mediaType: "application/json", expected: false},
+		{accept: "text/*", mediaType: "text/plain", expected: true},
+		{accept: "text/html, */*;q=0.1", mediaType: "application/json", expected: true},
+		{accept: "text/html, application/json;q=0", mediaType: "application/json", expected: false},
+		{accept: "Application/JSON", mediaType: "application/json", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept+" "+tt.mediaType, func(t *testing.T) {
+			h := templ.RequestHints{Accept: tt.accept}
+			if actual := h.Accepts(tt.mediaType); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRequestHintsLocale(t *testing.T) {
+	h := templ.RequestHints{Languages: []string{"de-CH", "fr", "en"}}
+	tests := []struct {
+		name      string
+		supported []string
+		expected  string
+	}{
+		{name: "the most preferred language is returned", supported: nil, expected: "de-CH"},
+		{name: "exact matches are used", supported: []string{"en", "de-CH"}, expected: "de-CH"},
+		{name: "primary subtags match", supported: []string{"en", "de"}, expected: "de"},
+		{name: "less preferred languages are used", supported: []string{"es", "fr"}, expected: "fr"},
+		{name: "the first supported language is the default", supported: []string{"es", "it"}, expected: "es"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := h.Locale(tt.supported...); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+	if actual := (templ.RequestHints{}).Locale(); actual != "" {
+		t.Errorf("expected no locale, got %q", actual)
+	}
+}
+
+func TestHandlerRequestHints(t *testing.T) {
+	page := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if templ.GetRequestHints(ctx).Partial() {
+			_, err := io.WriteString(w, "<p>partial</p>")
+			return err
+		}
+		_, err := io.WriteString(w, "<html><p>full</p></html>")
+		return err
+	})
+	tests := []struct {
+		name     string
+		handler  http.Handler
+		htmx     bool
+		expected string
+		noVary   bool
+	}{
+		{name: "handler full page", handler: templ.Handler(page, templ.WithHints()), expected: "<html><p>full</p></html>"},
+		{name: "handler partial", handler: templ.Handler(page, templ.WithHints()), htmx: true, expected: "<p>partial</p>"},
+		{name: "handler without hints", handler: templ.Handler(page), htmx: true, expected: "<html><p>full</p></html>", noVary: true},
+		{
+			name: "middleware partial",
+			handler: templ.RequestHintsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = page.Render(r.Context(), w)
+			})),
+			htmx:     true,
+			expected: "<p>partial</p>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.htmx {
+				r.Header.Set("HX-Request", "true")
+			}
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, r)
+			if diff := cmp.Diff(tt.expected, w.Body.String()); diff != "" {
+				t.Error(diff)
+			}
+			vary := w.Header().Get("Vary")
+			if tt.noVary && vary != "" {
+				t.Errorf("expected no Vary header, got %q", vary)
+			}
+			if !tt.noVary && !strings.Contains(vary, "HX-Request") {
+				t.Errorf("expected the Vary header to include HX-Request, got %q", vary)
+			}
+		})
+	}
+	if diff := cmp.Diff(templ.RequestHints{}, templ.GetRequestHints(context.Background())); diff != "" {
+		t.Errorf("expected empty hints without a request: %s", diff)
+	}
+}
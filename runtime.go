@@ -3,8 +3,6 @@ package templ
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"html"
@@ -13,9 +11,12 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
+	"github.com/a-h/templ/internal/cssid"
 	"github.com/a-h/templ/safehtml"
 )
 
@@ -43,6 +44,18 @@ func WithNonce(ctx context.Context, nonce string) context.Context {
 	return ctx
 }
 
+// RenderNonceAttribute writes a nonce attribute containing the CSP nonce set with
+// WithNonce. Nothing is written if a nonce hasn't been set.
+//
+// Generated code uses it to add the nonce to <script> and <style> elements.
+func RenderNonceAttribute(ctx context.Context, w io.Writer) (err error) {
+	nonce := GetNonce(ctx)
+	if nonce == "" {
+		return nil
+	}
+	return writeStrings(w, ` nonce="`, EscapeString(nonce), `"`)
+}
+
 // GetNonce returns the CSP nonce value set with WithNonce, or an
 // empty string if none has been set.
 func GetNonce(ctx context.Context) (nonce string) {
@@ -59,9 +72,12 @@ func WithChildren(ctx context.Context, children Component) context.Context {
 	return ctx
 }
 
+// ClearChildren removes the children and slots from the context, so that they aren't passed
+// to components further down the tree.
 func ClearChildren(ctx context.Context) context.Context {
 	_, v := getContext(ctx)
 	v.children = nil
+	v.slots = nil
 	return ctx
 }
 
@@ -242,6 +258,9 @@ type ComponentCSSClass struct {
 	ID string
 	// Definition of the CSS.
 	Class SafeCSS
+	// Keyframes declared in the CSS template, rendered with the class. It's a pointer, so
+	// that classes can be compared, and used as keys.
+	Keyframes *[]CSSKeyframes
 }
 
 // ClassName of the CSS class.
@@ -249,14 +268,40 @@ func (css ComponentCSSClass) ClassName() string {
 	return css.ID
 }
 
-// CSSID calculates an ID.
+// AnimationName returns the generated name of the @keyframes rule with the name used in the
+// CSS template, e.g. spin_1f5a2d4c for spin. If the class has no keyframes with the name, the
+// name is returned unchanged.
+func (css ComponentCSSClass) AnimationName(name string) string {
+	for _, k := range css.keyframes() {
+		if k.Name == name {
+			return k.ID
+		}
+	}
+	return name
+}
+
+func (css ComponentCSSClass) keyframes() []CSSKeyframes {
+	if css.Keyframes == nil {
+		return nil
+	}
+	return *css.Keyframes
+}
+
+// CSSKeyframes is a @keyframes rule declared in a CSS template.
+type CSSKeyframes struct {
+	// Name of the animation in the CSS template.
+	Name string
+	// ID of the animation, will be autogenerated. It's used as the animation name in the CSS.
+	ID string
+	// Definition of the @keyframes rule.
+	Rule SafeCSS
+}
+
+// CSSID calculates an ID from the name of a CSS class and its CSS. The ID is the name,
+// followed by an underscore and the first 8 characters of the hex encoded SHA-256 hash of
+// the CSS, so the same CSS always results in the same ID.
 func CSSID(name string, css string) string {
-	sum := sha256.Sum256([]byte(css))
-	hs := hex.EncodeToString(sum[:])[0:8] // NOTE: See issue #978. Minimum recommended hs length is 6.
-	// Benchmarking showed this was fastest, and with fewest allocations (1).
-	// Using strings.Builder (2 allocs).
-	// Using fmt.Sprintf (3 allocs).
-	return name + "_" + hs
+	return cssid.Calculate(name, css)
 }
 
 // NewCSSMiddleware creates HTTP middleware that renders a global stylesheet of ComponentCSSClass
@@ -287,6 +332,9 @@ func (cssm CSSMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx, v := getContext(r.Context())
 	for _, c := range cssm.CSSHandler.Classes {
 		v.addClass(c.ID)
+		for _, k := range c.keyframes() {
+			v.addKeyframes(k.ID)
+		}
 	}
 	// Serve the request. Templ components will use the updated context
 	// to know to skip rendering <style> elements for any component CSS
@@ -319,7 +367,18 @@ type CSSHandler struct {
 
 func (cssh CSSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/css")
+	keyframes := map[string]struct{}{}
 	for _, c := range cssh.Classes {
+		for _, k := range c.keyframes() {
+			if _, ok := keyframes[k.ID]; ok {
+				continue
+			}
+			keyframes[k.ID] = struct{}{}
+			_, err := w.Write([]byte(k.Rule))
+			if err != nil && cssh.Logger != nil {
+				cssh.Logger(err)
+			}
+		}
 		_, err := w.Write([]byte(c.Class))
 		if err != nil && cssh.Logger != nil {
 			cssh.Logger(err)
@@ -353,10 +412,18 @@ func renderCSSItemsToBuilder(sb *strings.Builder, v *contextValue, classes ...an
 	for _, c := range classes {
 		switch ccc := c.(type) {
 		case ComponentCSSClass:
-			if !v.hasClassBeenRendered(ccc.ID) {
-				sb.WriteString(string(ccc.Class))
-				v.addClass(ccc.ID)
+			if v.hasClassBeenRendered(ccc.ID) {
+				continue
 			}
+			// Keyframes can be shared by classes, so they're only rendered once.
+			for _, k := range ccc.keyframes() {
+				if !v.haveKeyframesBeenRendered(k.ID) {
+					sb.WriteString(string(k.Rule))
+					v.addKeyframes(k.ID)
+				}
+			}
+			sb.WriteString(string(ccc.Class))
+			v.addClass(ccc.ID)
 		case KeyValue[ComponentCSSClass, bool]:
 			if !ccc.Value {
 				continue
@@ -438,6 +505,38 @@ func (a Attributes) Items() []KeyValue[string, any] {
 	return items
 }
 
+// HasAttribute returns true if the attributes include the named attribute, and it's rendered
+// by RenderAttributes. Attributes that aren't rendered, such as boolean attributes with a false
+// value, or nil pointers, are absent. Names are compared case-insensitively, as in HTML.
+func HasAttribute(attributes Attributer, name string) bool {
+	if attributes == nil {
+		return false
+	}
+	for _, item := range attributes.Items() {
+		if strings.EqualFold(item.Key, name) && isRenderedAttribute(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRenderedAttribute returns true if RenderAttributes writes the attribute.
+func isRenderedAttribute(item KeyValue[string, any]) bool {
+	var w writtenWriter
+	_ = RenderAttributes(context.Background(), &w, OrderedAttributes{item})
+	return bool(w)
+}
+
+// writtenWriter records whether anything has been written to it.
+type writtenWriter bool
+
+func (w *writtenWriter) Write(p []byte) (n int, err error) {
+	if len(p) > 0 {
+		*w = true
+	}
+	return len(p), nil
+}
+
 // OrderedAttributes stores attributes in order of insertion.
 type OrderedAttributes []KeyValue[string, any]
 
@@ -456,6 +555,9 @@ func writeStrings(w io.Writer, ss ...string) (err error) {
 	return nil
 }
 
+// RenderAttributes writes the attributes in the order returned by Items. Attributes are
+// sorted by key, and OrderedAttributes are written in insertion order, so the output is
+// deterministic and can be used in golden tests and ETags.
 func RenderAttributes(ctx context.Context, w io.Writer, attributes Attributer) (err error) {
 	for _, item := range attributes.Items() {
 		key := item.Key
@@ -523,11 +625,41 @@ func RenderAttributes(ctx context.Context, w io.Writer, attributes Attributer) (
 			if err = writeStrings(w, ` `, EscapeString(key)); err != nil {
 				return err
 			}
+		default:
+			if err = writeNamedTypeAttribute(w, key, value); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// writeNamedTypeAttribute writes attributes whose values are of named types based on the basic
+// types, e.g. type Disabled bool, or pointers to them, in the same way as the basic types.
+// Values of other types aren't written.
+func writeNamedTypeAttribute(w io.Writer, key string, value any) error {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		if !rv.Bool() {
+			return nil
+		}
+		return writeStrings(w, ` `, EscapeString(key))
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return writeStrings(w, ` `, EscapeString(key), `="`, EscapeString(formatAttrValue(rv.Interface())), `"`)
+	}
+	return nil
+}
+
 func ptrValue(v any) any {
 	if v == nil {
 		return nil
@@ -552,7 +684,46 @@ type contextValue struct {
 	ss          map[string]struct{}
 	onceHandles map[*OnceHandle]struct{}
 	children    *Component
+	slots       Slots
 	nonce       string
+	// capabilities of the browser, see WithCapability.
+	capabilities map[string]bool
+	// textTransformers applied to template text, see WithTextTransformers.
+	textTransformers []TextTransformer
+	// urlSanitizer replaces URL for URL attributes, see WithURLSanitizer.
+	urlSanitizer URLSanitizer
+	// renderRecorder receives component render timings, see WithRenderRecorder.
+	renderRecorder RenderRecorder
+	// renderTracer starts a span for each component render, see WithTracing.
+	renderTracer RenderTracer
+	// renderDepth is the number of nested generated components being rendered, and
+	// maxRenderDepth is its limit, see WithMaxRenderDepth. The depth is shared with the
+	// contexts of template elements, so it's a pointer.
+	renderDepth    *atomic.Int32
+	maxRenderDepth int
+	// charset that the output is transcoded to, see WithRenderCharset.
+	charset Charset
+	// idCounts are the number of IDs created with each prefix, see NewID.
+	idCounts map[string]int
+	// requestHints describe the client of the HTTP request, see WithRequestHints.
+	requestHints RequestHints
+	// inputRecorder records the inputs of sampled renders, see WithInputRecorder.
+	inputRecorder *InputRecorder
+	// deferredScripts collects the scripts registered with DeferScript, see DeferredScripts.
+	deferredScripts *deferredScripts
+	// flagProvider resolves feature flags, and flags are the values it returned, see
+	// WithFlagProvider.
+	flagProvider FlagProvider
+	flags        map[string]bool
+	// abandonedComponent is the name of the component that returned a context error, see
+	// AbandonedRenderError.
+	abandonedComponent string
+	// fragmentCache stores the output of cache blocks, see WithFragmentCache. It's only used
+	// if fragmentCacheSet is true, so that caching can be disabled with a nil cache.
+	fragmentCache    FragmentCache
+	fragmentCacheSet bool
+	// headManager collects the assets registered with Preload, see HeadManager.
+	headManager *headManager
 }
 
 func (v *contextValue) setHasBeenRendered(h *OnceHandle) {
@@ -600,16 +771,54 @@ func (v *contextValue) hasClassBeenRendered(s string) (ok bool) {
 	return
 }
 
+func (v *contextValue) addKeyframes(s string) {
+	if v.ss == nil {
+		v.ss = map[string]struct{}{}
+	}
+	v.ss["keyframes_"+s] = struct{}{}
+}
+
+func (v *contextValue) haveKeyframesBeenRendered(s string) (ok bool) {
+	if v.ss == nil {
+		v.ss = map[string]struct{}{}
+	}
+	_, ok = v.ss["keyframes_"+s]
+	return
+}
+
 // InitializeContext initializes context used to store internal state used during rendering.
 func InitializeContext(ctx context.Context) context.Context {
 	if _, ok := ctx.Value(contextKey).(*contextValue); ok {
 		return ctx
 	}
-	v := &contextValue{}
+	v := &contextValue{renderDepth: new(atomic.Int32)}
 	ctx = context.WithValue(ctx, contextKey, v)
 	return ctx
 }
 
+// WithTemplateScope returns a context for rendering the contents of a <template> element. It's
+// used by generated code.
+//
+// The contents of a template are inert until they're cloned into the document, or attached
+// as a declarative shadow root, so CSS, scripts and OnceHandle content that has already been
+// rendered in the document is rendered again within the template, and content rendered within
+// the template is rendered again after it. Scripts registered with DeferScript are rendered
+// in place. Other context values are shared.
+func WithTemplateScope(ctx context.Context) context.Context {
+	ctx, v := getContext(ctx)
+	// IDs must be unique in the document, so the template shares the counts.
+	if v.idCounts == nil {
+		v.idCounts = map[string]int{}
+	}
+	scoped := *v
+	// CSS, scripts and OnceHandle content are rendered again within the template.
+	scoped.ss, scoped.onceHandles = nil, nil
+	// Deferred scripts would run when the document loads, instead of when the template is
+	// used, so they're rendered in place.
+	scoped.deferredScripts = nil
+	return context.WithValue(ctx, contextKey, &scoped)
+}
+
 func getContext(ctx context.Context) (context.Context, *contextValue) {
 	v, ok := ctx.Value(contextKey).(*contextValue)
 	if !ok {
@@ -659,6 +868,66 @@ func JoinStringErrs[T stringable](s T, errs ...error) (string, error) {
 	return fmt.Sprint(s), errors.Join(errs...)
 }
 
+// JoinAttrErrs formats an attribute value as a string, and joins an optional list of errors.
+// Strings, integers, floats, complex numbers, bools, and types based on them are supported,
+// e.g. <input value={ count }>. Types based on them that implement fmt.Stringer, e.g.
+// enums, are formatted with their String method. Other values must be converted to a
+// string, e.g. with their String method.
+func JoinAttrErrs[T stringable](v T, errs ...error) (string, error) {
+	// Strings are the most common attribute values, so avoid converting them to an interface.
+	if s, ok := any(v).(string); ok {
+		return s, errors.Join(errs...)
+	}
+	return formatAttrValue(v), errors.Join(errs...)
+}
+
+// formatAttrValue formats values of the basic types, and types based on them.
+func formatAttrValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case fmt.Stringer:
+		return v.String()
+	}
+	// Types based on the basic types, e.g. type Count int.
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	case reflect.Complex64:
+		return strconv.FormatComplex(rv.Complex(), 'g', -1, 64)
+	case reflect.Complex128:
+		return strconv.FormatComplex(rv.Complex(), 'g', -1, 128)
+	}
+	return fmt.Sprint(v)
+}
+
+// ErrNilComponent is returned when rendering a nil component, if the template was generated
+// with the -strict-nil-components flag.
+var ErrNilComponent = errors.New("templ: nil component")
+
 // Error returned during template rendering.
 type Error struct {
 	Err error
@@ -36,3 +36,11 @@ func ReleaseBuffer(w io.Writer) (err error) {
 	bufferPool.Put(b)
 	return err
 }
+
+// GetNewBuffer returns a new buffer that wraps w, even if w is already a buffer.
+// The buffer must be released with ReleaseBuffer to flush it to w.
+func GetNewBuffer(w io.Writer) (b *Buffer) {
+	b = bufferPool.Get().(*Buffer)
+	b.Reset(w)
+	return b
+}
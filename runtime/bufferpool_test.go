@@ -57,3 +57,20 @@ func TestBufferPool(t *testing.T) {
 		}
 	})
 }
+
+func TestGetNewBuffer(t *testing.T) {
+	w, _ := GetBuffer(new(bytes.Buffer))
+	b := GetNewBuffer(w)
+	if b == w {
+		t.Error("expected a new buffer, got the existing buffer")
+	}
+	if b.Underlying != w {
+		t.Error("expected the new buffer to wrap the existing buffer")
+	}
+	if err := ReleaseBuffer(b); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ReleaseBuffer(w); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
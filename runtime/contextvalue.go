@@ -0,0 +1,23 @@
+package runtime
+
+import "context"
+
+// contextValueKey is the key of a value declared with `templ ctx`. Each declaration has its
+// own key, so it isn't zero-sized, to make sure that pointers to keys are distinct.
+type contextValueKey struct {
+	_ byte
+}
+
+// ContextValue is used by generated code to create the functions that set and get a value
+// declared with `templ ctx`. Get returns the zero value of T if the value isn't set.
+func ContextValue[T any]() (set func(ctx context.Context, value T) context.Context, get func(ctx context.Context) T) {
+	key := &contextValueKey{}
+	set = func(ctx context.Context, value T) context.Context {
+		return context.WithValue(ctx, key, value)
+	}
+	get = func(ctx context.Context) (value T) {
+		value, _ = ctx.Value(key).(T)
+		return value
+	}
+	return set, get
+}
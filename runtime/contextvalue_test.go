@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextValue(t *testing.T) {
+	setTheme, getTheme := ContextValue[string]()
+	setOther, getOther := ContextValue[string]()
+
+	t.Run("unset values are the zero value", func(t *testing.T) {
+		if v := getTheme(context.Background()); v != "" {
+			t.Errorf("expected empty string, got %q", v)
+		}
+	})
+	t.Run("values can be set and retrieved", func(t *testing.T) {
+		ctx := setTheme(context.Background(), "dark")
+		if v := getTheme(ctx); v != "dark" {
+			t.Errorf("expected %q, got %q", "dark", v)
+		}
+	})
+	t.Run("each declaration has its own key", func(t *testing.T) {
+		ctx := setTheme(context.Background(), "dark")
+		ctx = setOther(ctx, "other")
+		if v := getTheme(ctx); v != "dark" {
+			t.Errorf("expected %q, got %q", "dark", v)
+		}
+		if v := getOther(ctx); v != "other" {
+			t.Errorf("expected %q, got %q", "other", v)
+		}
+	})
+}
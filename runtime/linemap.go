@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// lineMaps are the line maps of generated files, by the path of the generated file.
+var lineMaps sync.Map
+
+// lineMap maps the lines of a generated file to the lines of the templ file.
+type lineMap struct {
+	templFile string
+	// goLines are the first lines of runs of generated code, in ascending order, and templLines
+	// are the lines of the templ file that they were generated from, or 0.
+	goLines    []int
+	templLines []int
+}
+
+// RegisterLineMap is used by generated code to register the map of the lines of the generated
+// file to the lines of the templ file, see LookupLine. The generated file is the file of the
+// caller.
+func RegisterLineMap(templFile, lines string) struct{} {
+	if _, goFile, _, ok := runtime.Caller(1); ok {
+		registerLineMap(goFile, filepath.Join(filepath.Dir(goFile), templFile), lines)
+	}
+	return struct{}{}
+}
+
+// lineMapCall matches the call to RegisterLineMap in generated code.
+var lineMapCall = regexp.MustCompile(`(?m)^var _ = templruntime\.RegisterLineMap\(("[^"]*"), ("[^"]*")\)$`)
+
+// LoadLineMap reads the line map of a generated file, so that LookupLine can be used for files
+// that aren't part of the program, e.g. by tools that analyse the profiles of other programs.
+func LoadLineMap(goFile string) error {
+	code, err := os.ReadFile(goFile)
+	if err != nil {
+		return err
+	}
+	m := lineMapCall.FindSubmatch(code)
+	if m == nil {
+		return fmt.Errorf("%s doesn't have a line map, generate it with templ generate -stack-trace-maps", goFile)
+	}
+	templFile, err := strconv.Unquote(string(m[1]))
+	if err != nil {
+		return fmt.Errorf("%s: invalid line map: %w", goFile, err)
+	}
+	lines, err := strconv.Unquote(string(m[2]))
+	if err != nil {
+		return fmt.Errorf("%s: invalid line map: %w", goFile, err)
+	}
+	registerLineMap(goFile, filepath.Join(filepath.Dir(goFile), templFile), lines)
+	return nil
+}
+
+func registerLineMap(goFile, templFile, lines string) {
+	lm := &lineMap{templFile: templFile}
+	for _, entry := range strings.Split(lines, ",") {
+		goLine, templLine, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+		g, err := strconv.Atoi(goLine)
+		if err != nil {
+			continue
+		}
+		t, err := strconv.Atoi(templLine)
+		if err != nil {
+			continue
+		}
+		lm.goLines = append(lm.goLines, g)
+		lm.templLines = append(lm.templLines, t)
+	}
+	lineMaps.Store(goFile, lm)
+}
+
+// LookupLine returns the templ file and line that the line of a generated file was generated
+// from, if the file was generated with a line map, e.g. with templ generate -stack-trace-maps.
+// goFile is the path of the generated file, as reported in stack traces. Lines are numbered
+// from 1.
+func LookupLine(goFile string, line int) (templFile string, templLine int, ok bool) {
+	v, ok := lineMaps.Load(goFile)
+	if !ok {
+		return "", 0, false
+	}
+	lm := v.(*lineMap)
+	i := sort.SearchInts(lm.goLines, line+1) - 1
+	if i < 0 || lm.templLines[i] == 0 {
+		return "", 0, false
+	}
+	return lm.templFile, lm.templLines[i], true
+}
@@ -0,0 +1,60 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLookupLine(t *testing.T) {
+	registerLineMap("/src/app/page_templ.go", "/src/app/page.templ", "3:1,4:0,10:3,12:0")
+	tests := []struct {
+		line         int
+		expectedLine int
+		expectedOK   bool
+	}{
+		{line: 1},
+		{line: 3, expectedLine: 1, expectedOK: true},
+		{line: 4},
+		{line: 10, expectedLine: 3, expectedOK: true},
+		{line: 11, expectedLine: 3, expectedOK: true},
+		{line: 12},
+		{line: 100},
+	}
+	for _, tt := range tests {
+		templFile, line, ok := LookupLine("/src/app/page_templ.go", tt.line)
+		if ok != tt.expectedOK || line != tt.expectedLine {
+			t.Errorf("line %d: expected %d, %v, got %d, %v", tt.line, tt.expectedLine, tt.expectedOK, line, ok)
+		}
+		if ok && templFile != "/src/app/page.templ" {
+			t.Errorf("line %d: expected the templ file to be /src/app/page.templ, got %q", tt.line, templFile)
+		}
+	}
+	if _, _, ok := LookupLine("/src/app/other_templ.go", 10); ok {
+		t.Error("expected files without a line map not to be found")
+	}
+}
+
+func TestLoadLineMap(t *testing.T) {
+	dir := t.TempDir()
+	goFile := filepath.Join(dir, "page_templ.go")
+	code := "package page\n\nfunc Page() {}\n\nvar _ = templruntime.RegisterLineMap(\"page.templ\", \"3:5\")\n"
+	if err := os.WriteFile(goFile, []byte(code), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadLineMap(goFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	templFile, line, ok := LookupLine(goFile, 3)
+	if !ok || templFile != filepath.Join(dir, "page.templ") || line != 5 {
+		t.Errorf("expected page.templ:5, got %s:%d", templFile, line)
+	}
+
+	noMap := filepath.Join(dir, "other_templ.go")
+	if err := os.WriteFile(noMap, []byte("package page\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadLineMap(noMap); err == nil {
+		t.Error("expected an error for a file without a line map")
+	}
+}
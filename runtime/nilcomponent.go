@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// NilSafe returns c, or a component that renders nothing if c is nil. It's used by generated
+// code, so that @component renders nothing instead of panicking if component is nil.
+func NilSafe(c templ.Component) templ.Component {
+	if c == nil {
+		return templ.NopComponent
+	}
+	return c
+}
+
+// NilStrict returns c, or a component that returns templ.ErrNilComponent, naming the
+// expression of the @component and its position, if c is nil. It's used by generated code
+// in strict mode.
+func NilStrict(c templ.Component, expression, fileName string, line, col int) templ.Component {
+	if c == nil {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			err := fmt.Errorf("%w: @%s", templ.ErrNilComponent, expression)
+			return templ.Error{Err: err, FileName: fileName, Line: line, Col: col}
+		})
+	}
+	return c
+}
+
+// Spread returns a component that renders each of the components in order, skipping nil
+// components. It's used by generated code for @components..., which accepts a slice of any
+// type that implements templ.Component.
+func Spread[S ~[]C, C templ.Component](components S) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for _, c := range components {
+			if err := NilSafe(c).Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SpreadStrict is like Spread, but returns templ.ErrNilComponent if any of the components
+// are nil, naming the expression of @components... and the index of the component. It's used
+// by generated code in strict mode.
+func SpreadStrict[S ~[]C, C templ.Component](components S, expression, fileName string, line, col int) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		for i, c := range components {
+			if templ.Component(c) == nil {
+				return NilStrict(nil, fmt.Sprintf("%s...[%d]", expression, i), fileName, line, col).Render(ctx, w)
+			}
+			if err := c.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestNilSafe(t *testing.T) {
+	t.Run("nil components render nothing", func(t *testing.T) {
+		var b strings.Builder
+		if err := NilSafe(nil).Render(context.Background(), &b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.String() != "" {
+			t.Errorf("expected no output, got %q", b.String())
+		}
+	})
+	t.Run("other components are rendered", func(t *testing.T) {
+		var b strings.Builder
+		if err := NilSafe(templ.Raw("<p>")).Render(context.Background(), &b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.String() != "<p>" {
+			t.Errorf("expected %q, got %q", "<p>", b.String())
+		}
+	})
+}
+
+func TestNilStrict(t *testing.T) {
+	err := NilStrict(nil, "header", "card.templ", 5, 3).Render(context.Background(), &strings.Builder{})
+	if !errors.Is(err, templ.ErrNilComponent) {
+		t.Fatalf("expected ErrNilComponent, got %v", err)
+	}
+	expected := "card.templ: error at line 5, col 3: templ: nil component: @header"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+type spreadItem string
+
+func (i spreadItem) Render(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, string(i))
+	return err
+}
+
+func TestSpread(t *testing.T) {
+	t.Run("nil components are skipped", func(t *testing.T) {
+		var b strings.Builder
+		if err := Spread([]templ.Component{templ.Raw("a"), nil, templ.Raw("b")}).Render(context.Background(), &b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.String() != "ab" {
+			t.Errorf("expected %q, got %q", "ab", b.String())
+		}
+	})
+	t.Run("slices of types that implement templ.Component are rendered", func(t *testing.T) {
+		var b strings.Builder
+		if err := Spread([]spreadItem{"a", "b"}).Render(context.Background(), &b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.String() != "ab" {
+			t.Errorf("expected %q, got %q", "ab", b.String())
+		}
+	})
+}
+
+func TestSpreadStrict(t *testing.T) {
+	var b strings.Builder
+	err := SpreadStrict([]templ.Component{templ.Raw("a"), nil}, "items", "list.templ", 4, 2).Render(context.Background(), &b)
+	if !errors.Is(err, templ.ErrNilComponent) {
+		t.Fatalf("expected ErrNilComponent, got %v", err)
+	}
+	expected := "list.templ: error at line 4, col 2: templ: nil component: @items...[1]"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if b.String() != "a" {
+		t.Errorf("expected the components before the nil component to be rendered, got %q", b.String())
+	}
+}
@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/internal/funcname"
+)
+
+// RegisterTemplate is used by generated code to add a template to the registry, see
+// templ.LookupTemplate. The package of the template is the package of the caller.
+func RegisterTemplate(t templ.TemplateInfo) {
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			// The caller is an init function, e.g. "github.com/example/app/pages.init.0".
+			t.Package = funcname.Package(fn.Name())
+		}
+	}
+	templ.RegisterTemplate(t)
+}
+
+// CheckArgCount is used by generated code to check the number of arguments passed to a
+// registered template.
+func CheckArgCount(template string, args []any, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("templ: %s expects %d arguments, got %d", template, n, len(args))
+	}
+	return nil
+}
+
+// Arg is used by generated code to convert an argument passed to a registered template to
+// the type of the parameter. A nil argument is converted to the zero value of the type, and
+// a json.RawMessage argument is unmarshalled into the type, e.g. when a recording is replayed,
+// see templ.InputRecording.
+func Arg[T any](template, param string, arg any) (v T, err error) {
+	if arg == nil {
+		return v, nil
+	}
+	if raw, ok := arg.(json.RawMessage); ok {
+		if _, isRaw := any(v).(json.RawMessage); !isRaw {
+			if err = json.Unmarshal(raw, &v); err != nil {
+				return v, fmt.Errorf("templ: %s parameter %q: %w", template, param, err)
+			}
+			return v, nil
+		}
+	}
+	v, ok := arg.(T)
+	if !ok {
+		return v, fmt.Errorf("templ: %s parameter %q must be of type %v, got %T", template, param, reflect.TypeFor[T](), arg)
+	}
+	return v, nil
+}
@@ -0,0 +1,93 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func init() {
+	RegisterTemplate(templ.TemplateInfo{
+		Name: "RuntimeRegistryTest",
+		New: func(args ...any) (templ.Component, error) {
+			return templ.NopComponent, nil
+		},
+	})
+}
+
+func TestRegisterTemplate(t *testing.T) {
+	if _, ok := templ.LookupTemplate("github.com/a-h/templ/runtime", "RuntimeRegistryTest"); !ok {
+		t.Error("expected the template to be registered in the package of the caller")
+	}
+}
+
+func TestCallerTemplatePackage(t *testing.T) {
+	registry := templ.CallerTemplatePackage()
+	if registry != "github.com/a-h/templ/runtime" {
+		t.Fatalf("expected the package of the caller, got %q", registry)
+	}
+	if _, ok := registry.Lookup("RuntimeRegistryTest"); !ok {
+		t.Error("expected the template to be found in the package")
+	}
+	if templates := registry.Templates(); len(templates) != 1 || templates[0].Name != "RuntimeRegistryTest" {
+		t.Errorf("expected the registered template, got %v", templates)
+	}
+}
+
+func TestCheckArgCount(t *testing.T) {
+	if err := CheckArgCount("Card", []any{"a", 1}, 2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := CheckArgCount("Card", []any{"a"}, 2)
+	if err == nil || !strings.Contains(err.Error(), "Card expects 2 arguments, got 1") {
+		t.Errorf("expected argument count error, got %v", err)
+	}
+}
+
+func TestArg(t *testing.T) {
+	t.Run("arguments of the parameter type are returned", func(t *testing.T) {
+		v, err := Arg[string]("Card", "title", "Hello")
+		if err != nil || v != "Hello" {
+			t.Errorf("expected %q, got %q, %v", "Hello", v, err)
+		}
+	})
+	t.Run("nil arguments are the zero value", func(t *testing.T) {
+		v, err := Arg[[]string]("Card", "tags", nil)
+		if err != nil || v != nil {
+			t.Errorf("expected nil, got %v, %v", v, err)
+		}
+	})
+	t.Run("arguments can implement an interface parameter type", func(t *testing.T) {
+		v, err := Arg[context.Context]("Card", "ctx", context.Background())
+		if err != nil || v == nil {
+			t.Errorf("expected context, got %v, %v", v, err)
+		}
+	})
+	t.Run("JSON arguments are unmarshalled into the parameter type", func(t *testing.T) {
+		v, err := Arg[[]string]("Card", "tags", json.RawMessage(`["a","b"]`))
+		if err != nil || len(v) != 2 || v[1] != "b" {
+			t.Errorf("expected [a b], got %v, %v", v, err)
+		}
+	})
+	t.Run("JSON arguments that don't match the parameter type return an error", func(t *testing.T) {
+		_, err := Arg[int]("Card", "count", json.RawMessage(`"a"`))
+		if err == nil || !strings.Contains(err.Error(), `Card parameter "count"`) {
+			t.Errorf("expected unmarshal error, got %v", err)
+		}
+	})
+	t.Run("JSON arguments are returned unchanged for JSON parameters", func(t *testing.T) {
+		v, err := Arg[json.RawMessage]("Card", "data", json.RawMessage(`{}`))
+		if err != nil || string(v) != "{}" {
+			t.Errorf("expected {}, got %s, %v", v, err)
+		}
+	})
+	t.Run("arguments of the wrong type return an error", func(t *testing.T) {
+		_, err := Arg[string]("Card", "title", 1)
+		if err == nil || !strings.Contains(err.Error(), `Card parameter "title" must be of type string, got int`) {
+			t.Errorf("expected type error, got %v", err)
+		}
+	})
+}
@@ -2,7 +2,12 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/a-h/templ"
 )
@@ -15,7 +20,141 @@ type GeneratedComponentInput struct {
 
 // GeneratedTemplate is used to avoid generated code needing to import the `context` and `io` packages.
 func GeneratedTemplate(f func(GeneratedComponentInput) error) templ.Component {
-	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
-		return f(GeneratedComponentInput{ctx, w})
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		if developmentMode && !isChildren(f) {
+			ctx = templ.WithRenderPath(ctx, componentName(f))
+		}
+		if developmentMode {
+			defer recoverAssertion(f, &err)
+		}
+		ctx, err = templ.IncrementRenderDepth(ctx)
+		defer templ.DecrementRenderDepth(ctx)
+		if err == nil {
+			if r := templ.GetRenderRecorder(ctx); r != nil {
+				err = recordRender(r, f, ctx, w)
+			} else {
+				err = f(GeneratedComponentInput{ctx, w})
+			}
+		}
+		// Add the name of the component, so that the error shows which components recursed.
+		var depthErr *templ.RenderDepthError
+		if errors.As(err, &depthErr) {
+			depthErr.AddComponent(componentName(f))
+		}
+		var assertErr *templ.AssertionError
+		if errors.As(err, &assertErr) && !isChildren(f) {
+			assertErr.AddComponent(componentName(f))
+		}
+		// Record the component that found the context was cancelled, to report which
+		// component was abandoned.
+		if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			templ.SetAbandonedComponent(ctx, componentName(f))
+		}
+		return err
 	})
 }
+
+// recoverAssertion returns the panic of a failed templ.Assert or templ.Require as the error
+// of rendering f. Other panics are re-raised.
+func recoverAssertion(f func(GeneratedComponentInput) error, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	assertErr, ok := r.(*templ.AssertionError)
+	if !ok {
+		panic(r)
+	}
+	if !isChildren(f) {
+		assertErr.AddComponent(componentName(f))
+	}
+	*err = assertErr
+}
+
+func recordRender(r templ.RenderRecorder, f func(GeneratedComponentInput) error, ctx context.Context, w io.Writer) error {
+	cw := &countingWriter{w: w}
+	start := time.Now()
+	err := f(GeneratedComponentInput{ctx, cw})
+	r.RecordRender(componentName(f), time.Since(start), cw.n, err)
+	return err
+}
+
+// componentName returns the name of the template function that created f, e.g. "pages.Home"
+// for the function literal "github.com/example/app/pages.Home.func1".
+func componentName(f func(GeneratedComponentInput) error) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	// Remove the suffixes of function literals, e.g. ".func1", or ".1" if the template was inlined.
+	for {
+		i := strings.LastIndex(name, ".")
+		if i < 0 || !isFuncLiteralSuffix(name[i+1:]) {
+			return name
+		}
+		name = name[:i]
+	}
+}
+
+// isChildren returns true if f is the children of a component call, rather than a template,
+// e.g. "github.com/example/app/pages.Home.func1.1" is the children of a component that's
+// called by the pages.Home template.
+func isChildren(f func(GeneratedComponentInput) error) bool {
+	fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fn == nil {
+		return false
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	var literals int
+	for {
+		i := strings.LastIndex(name, ".")
+		if i < 0 || !isFuncLiteralSuffix(name[i+1:]) {
+			return literals > 1
+		}
+		name = name[:i]
+		literals++
+	}
+}
+
+func isFuncLiteralSuffix(s string) bool {
+	s = strings.TrimPrefix(s, "func")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// Flush passes flushes through to the underlying writer, so that recording doesn't affect
+// templ.Flush.
+func (cw *countingWriter) Flush() error {
+	switch w := cw.w.(type) {
+	case interface{ Flush() error }:
+		return w.Flush()
+	case interface{ Flush() }:
+		w.Flush()
+	}
+	return nil
+}
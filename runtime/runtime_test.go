@@ -2,8 +2,14 @@ package runtime
 
 import (
 	"context"
+	"errors"
+	"io"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestGeneratedTemplate(t *testing.T) {
@@ -20,3 +26,178 @@ func TestGeneratedTemplate(t *testing.T) {
 		t.Errorf("expected \"Hello, World!\", got %q", sb.String())
 	}
 }
+
+type testRecorder struct {
+	name  string
+	bytes int64
+	err   error
+}
+
+func (r *testRecorder) RecordRender(name string, d time.Duration, bytes int64, err error) {
+	r.name, r.bytes, r.err = name, bytes, err
+}
+
+func helloTemplate() templ.Component {
+	return GeneratedTemplate(func(input GeneratedComponentInput) error {
+		_, err := input.Writer.Write([]byte("Hello, World!"))
+		return err
+	})
+}
+
+func TestGeneratedTemplateRecordsRenders(t *testing.T) {
+	r := &testRecorder{}
+	ctx := templ.WithRenderRecorder(context.Background(), r)
+	sb := new(strings.Builder)
+	if err := helloTemplate().Render(ctx, sb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sb.String() != "Hello, World!" {
+		t.Errorf("expected \"Hello, World!\", got %q", sb.String())
+	}
+	if r.name != "runtime.helloTemplate" {
+		t.Errorf("expected name %q, got %q", "runtime.helloTemplate", r.name)
+	}
+	if r.bytes != int64(len("Hello, World!")) {
+		t.Errorf("expected %d bytes, got %d", len("Hello, World!"), r.bytes)
+	}
+}
+
+// treeTemplate renders itself depth times.
+func treeTemplate(depth int) templ.Component {
+	return GeneratedTemplate(func(input GeneratedComponentInput) error {
+		if depth == 0 {
+			return nil
+		}
+		return treeTemplate(depth-1).Render(input.Context, input.Writer)
+	})
+}
+
+func TestGeneratedTemplateRenderDepth(t *testing.T) {
+	t.Run("components nested within the maximum depth are rendered", func(t *testing.T) {
+		if err := treeTemplate(templ.DefaultMaxRenderDepth-1).Render(context.Background(), io.Discard); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("components nested beyond the maximum depth return an error", func(t *testing.T) {
+		ctx := templ.WithMaxRenderDepth(context.Background(), 50)
+		err := treeTemplate(100).Render(ctx, io.Discard)
+		var depthErr *templ.RenderDepthError
+		if !errors.As(err, &depthErr) {
+			t.Fatalf("expected RenderDepthError, got %v", err)
+		}
+		if depthErr.MaxDepth != 50 {
+			t.Errorf("expected max depth 50, got %d", depthErr.MaxDepth)
+		}
+		if len(depthErr.Components) != 10 || depthErr.Components[0] != "runtime.treeTemplate" {
+			t.Errorf("expected 10 component names, got %v", depthErr.Components)
+		}
+	})
+	t.Run("the depth is reset after rendering", func(t *testing.T) {
+		ctx := templ.WithMaxRenderDepth(context.Background(), 50)
+		for range 3 {
+			if err := treeTemplate(40).Render(ctx, io.Discard); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	})
+	t.Run("the depth can be unlimited", func(t *testing.T) {
+		ctx := templ.WithMaxRenderDepth(context.Background(), 0)
+		if err := treeTemplate(templ.DefaultMaxRenderDepth+1).Render(ctx, io.Discard); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+// layoutTemplate renders its children.
+func layoutTemplate() templ.Component {
+	return GeneratedTemplate(func(input GeneratedComponentInput) error {
+		return templ.GetChildren(input.Context).Render(templ.ClearChildren(input.Context), input.Writer)
+	})
+}
+
+// pathTemplate writes the render path.
+func pathTemplate() templ.Component {
+	return GeneratedTemplate(func(input GeneratedComponentInput) error {
+		_, err := io.WriteString(input.Writer, strings.Join(templ.Path(input.Context), " > "))
+		return err
+	})
+}
+
+// pageTemplate renders pathTemplate as the children of layoutTemplate.
+func pageTemplate() templ.Component {
+	return GeneratedTemplate(func(input GeneratedComponentInput) error {
+		children := GeneratedTemplate(func(input GeneratedComponentInput) error {
+			return pathTemplate().Render(input.Context, input.Writer)
+		})
+		return layoutTemplate().Render(templ.WithChildren(input.Context, children), input.Writer)
+	})
+}
+
+func TestGeneratedTemplatePath(t *testing.T) {
+	t.Run("the path isn't populated outside of development mode", func(t *testing.T) {
+		sb := new(strings.Builder)
+		if err := pageTemplate().Render(context.Background(), sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sb.String() != "" {
+			t.Errorf("expected an empty path, got %q", sb.String())
+		}
+	})
+	t.Run("the path contains the templates being rendered in development mode", func(t *testing.T) {
+		developmentMode = true
+		defer func() { developmentMode = false }()
+		sb := new(strings.Builder)
+		if err := pageTemplate().Render(context.Background(), sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "runtime.pageTemplate > runtime.layoutTemplate > runtime.pathTemplate"
+		if sb.String() != expected {
+			t.Errorf("expected %q, got %q", expected, sb.String())
+		}
+	})
+}
+
+// assertTemplate fails an assertion.
+func assertTemplate() templ.Component {
+	return GeneratedTemplate(func(input GeneratedComponentInput) error {
+		panic(&templ.AssertionError{Message: "title is required"})
+	})
+}
+
+// assertPageTemplate renders assertTemplate as the children of layoutTemplate.
+func assertPageTemplate() templ.Component {
+	return GeneratedTemplate(func(input GeneratedComponentInput) error {
+		children := GeneratedTemplate(func(input GeneratedComponentInput) error {
+			return assertTemplate().Render(input.Context, input.Writer)
+		})
+		return layoutTemplate().Render(templ.WithChildren(input.Context, children), input.Writer)
+	})
+}
+
+func TestGeneratedTemplateAssertions(t *testing.T) {
+	t.Run("failed assertions are returned as errors in development mode", func(t *testing.T) {
+		developmentMode = true
+		defer func() { developmentMode = false }()
+		err := assertPageTemplate().Render(context.Background(), io.Discard)
+		var assertErr *templ.AssertionError
+		if !errors.As(err, &assertErr) {
+			t.Fatalf("expected an assertion error, got %v", err)
+		}
+		expected := []string{"runtime.assertTemplate", "runtime.layoutTemplate", "runtime.assertPageTemplate"}
+		if diff := cmp.Diff(expected, assertErr.Components); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("other panics aren't recovered", func(t *testing.T) {
+		developmentMode = true
+		defer func() { developmentMode = false }()
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		_ = GeneratedTemplate(func(input GeneratedComponentInput) error {
+			panic("unexpected")
+		}).Render(context.Background(), io.Discard)
+	})
+}
@@ -0,0 +1,48 @@
+// Package stacktrace rewrites stack traces, so that frames in code generated by templ are
+// reported with the file and line of the .templ file that the code was generated from.
+//
+// The lines are mapped with the line maps that templ generate -stack-trace-maps adds to the
+// generated code, so the .templ files don't need to be available when the program runs.
+// Frames in files that were generated without line maps are left unchanged.
+package stacktrace
+
+import (
+	"regexp"
+	"runtime/debug"
+	"strconv"
+
+	templruntime "github.com/a-h/templ/runtime"
+)
+
+// Stack is like debug.Stack, but frames in generated code are rewritten, see Rewrite. Call it
+// in a deferred function that recovers from a panic to get the stack trace of the panic.
+func Stack() []byte {
+	return Rewrite(debug.Stack())
+}
+
+// frame matches the file and line of a frame in a stack trace, e.g.
+// "\t/src/app/components/page_templ.go:42 +0x1d".
+var frame = regexp.MustCompile(`(?m)^(\t)(.+_templ\.go):(\d+)`)
+
+// Rewrite returns the stack trace, with the file and line of frames in generated _templ.go
+// files replaced by the file and line in the .templ file.
+func Rewrite(stack []byte) []byte {
+	return frame.ReplaceAllFunc(stack, func(m []byte) []byte {
+		parts := frame.FindSubmatch(m)
+		line, err := strconv.Atoi(string(parts[3]))
+		if err != nil {
+			return m
+		}
+		templFile, templLine, ok := Lookup(string(parts[2]), line)
+		if !ok {
+			return m
+		}
+		return []byte(string(parts[1]) + templFile + ":" + strconv.Itoa(templLine))
+	})
+}
+
+// Lookup returns the .templ file and line that the line of a generated _templ.go file was
+// generated from. Lines are numbered from 1, like the lines in stack traces.
+func Lookup(goFile string, line int) (templFile string, templLine int, ok bool) {
+	return templruntime.LookupLine(goFile, line)
+}
@@ -0,0 +1,85 @@
+package stacktrace
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/runtime/stacktrace/internal/testtemplates"
+	"github.com/google/go-cmp/cmp"
+)
+
+// generatedFiles returns the files of the testtemplates package, which is generated with
+// templ generate -stack-trace-maps.
+func generatedFiles(t *testing.T) (templFile, goFile string) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("failed to get the file of the test")
+	}
+	dir := filepath.Join(filepath.Dir(file), "internal", "testtemplates")
+	return filepath.Join(dir, "template.templ"), filepath.Join(dir, "template_templ.go")
+}
+
+func TestStack(t *testing.T) {
+	templFile, _ := generatedFiles(t)
+	var stack string
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				stack = string(Stack())
+			}
+		}()
+		_ = testtemplates.Greeting(nil).Render(context.Background(), io.Discard)
+	}()
+	if !strings.Contains(stack, "\t"+templFile+":9 ") {
+		t.Errorf("expected the frame of the expression to be reported at %s:9, got:\n%s", templFile, stack)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	templFile, goFile := generatedFiles(t)
+	stack := "goroutine 1 [running]:\n" +
+		"test.Greeting.func1({0x0, 0x0})\n" +
+		"\t" + goFile + ":40 +0x1d\n" +
+		"test.Greeting(...)\n" +
+		"\t" + goFile + ":15 +0x2a\n" +
+		"main.main()\n" +
+		"\t/src/app/main.go:12 +0x3b\n" +
+		"other.Page()\n" +
+		"\t/src/missing/page_templ.go:10 +0x4c\n"
+	expected := "goroutine 1 [running]:\n" +
+		"test.Greeting.func1({0x0, 0x0})\n" +
+		"\t" + templFile + ":9 +0x1d\n" +
+		"test.Greeting(...)\n" +
+		"\t" + templFile + ":7 +0x2a\n" +
+		"main.main()\n" +
+		"\t/src/app/main.go:12 +0x3b\n" +
+		"other.Page()\n" +
+		"\t/src/missing/page_templ.go:10 +0x4c\n"
+	if diff := cmp.Diff(expected, string(Rewrite([]byte(stack)))); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	_, goFile := generatedFiles(t)
+	t.Run("lines of generated code that aren't from the template aren't found", func(t *testing.T) {
+		if _, _, ok := Lookup(goFile, 1); ok {
+			t.Error("expected the line not to be found")
+		}
+	})
+	t.Run("files without a line map aren't found", func(t *testing.T) {
+		if _, _, ok := Lookup(filepath.Join(filepath.Dir(goFile), "other_templ.go"), 40); ok {
+			t.Error("expected the line not to be found")
+		}
+	})
+	t.Run("lines are found", func(t *testing.T) {
+		templFile, line, ok := Lookup(goFile, 40)
+		if !ok || filepath.Base(templFile) != "template.templ" || line != 9 {
+			t.Errorf("expected template.templ:9, got %s:%d", templFile, line)
+		}
+	})
+}
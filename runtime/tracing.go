@@ -0,0 +1,23 @@
+//go:build templ_trace
+
+package runtime
+
+import (
+	"context"
+
+	"github.com/a-h/templ"
+)
+
+// StartRender is used by code generated with the -tracing flag to start a span for a
+// component, using the tracer in the context, see templ.WithTracing.
+//
+// Tracing is enabled because the program was built with the templ_trace build tag.
+func StartRender(ctx context.Context, name, fileName string, line int) (context.Context, func(err error)) {
+	t := templ.GetRenderTracer(ctx)
+	if t == nil {
+		return ctx, endRenderNop
+	}
+	return t.StartRender(ctx, templ.RenderSpan{Name: name, FileName: fileName, Line: line})
+}
+
+func endRenderNop(err error) {}
@@ -0,0 +1,16 @@
+//go:build !templ_trace
+
+package runtime
+
+import "context"
+
+// StartRender is used by code generated with the -tracing flag to start a span for a
+// component, using the tracer in the context, see templ.WithTracing.
+//
+// Tracing is disabled, because the program wasn't built with the templ_trace build tag, so
+// no spans are started.
+func StartRender(ctx context.Context, name, fileName string, line int) (context.Context, func(err error)) {
+	return ctx, endRenderNop
+}
+
+func endRenderNop(err error) {}
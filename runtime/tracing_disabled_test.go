@@ -0,0 +1,25 @@
+//go:build !templ_trace
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+type panicTracer struct{}
+
+func (panicTracer) StartRender(ctx context.Context, span templ.RenderSpan) (context.Context, func(err error)) {
+	panic("tracer should not be called without the templ_trace build tag")
+}
+
+func TestStartRenderDisabled(t *testing.T) {
+	ctx := templ.WithTracing(context.Background(), panicTracer{})
+	spanCtx, end := StartRender(ctx, "pages.Home", "home.templ", 3)
+	if spanCtx != ctx {
+		t.Error("expected the context to be unchanged")
+	}
+	end(nil)
+}
@@ -0,0 +1,53 @@
+//go:build templ_trace
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+type spanKey struct{}
+
+type testTracer struct {
+	spans []templ.RenderSpan
+	errs  []error
+}
+
+func (t *testTracer) StartRender(ctx context.Context, span templ.RenderSpan) (context.Context, func(err error)) {
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span.Name), func(err error) {
+		t.errs = append(t.errs, err)
+	}
+}
+
+func TestStartRender(t *testing.T) {
+	t.Run("without a tracer, the context is unchanged", func(t *testing.T) {
+		ctx := context.Background()
+		spanCtx, end := StartRender(ctx, "pages.Home", "home.templ", 3)
+		if spanCtx != ctx {
+			t.Error("expected the context to be unchanged")
+		}
+		end(nil)
+	})
+	t.Run("with a tracer, a span is started and ended", func(t *testing.T) {
+		tracer := &testTracer{}
+		errRender := errors.New("render failed")
+		ctx := templ.WithTracing(context.Background(), tracer)
+		spanCtx, end := StartRender(ctx, "pages.Home", "home.templ", 3)
+		if spanCtx.Value(spanKey{}) != "pages.Home" {
+			t.Error("expected the span context to be returned")
+		}
+		end(errRender)
+		expected := templ.RenderSpan{Name: "pages.Home", FileName: "home.templ", Line: 3}
+		if len(tracer.spans) != 1 || tracer.spans[0] != expected {
+			t.Errorf("expected span %+v, got %+v", expected, tracer.spans)
+		}
+		if len(tracer.errs) != 1 || !errors.Is(tracer.errs[0], errRender) {
+			t.Errorf("expected the render error to be passed to end, got %v", tracer.errs)
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package runtime
+
+import "io"
+
+// UnbufferedWriter writes directly to the underlying io.Writer. It's used by templates
+// that opt out of buffering with the //templ:buffer off directive.
+type UnbufferedWriter struct {
+	Underlying io.Writer
+}
+
+// Write the contents of p to the underlying io.Writer.
+func (u *UnbufferedWriter) Write(p []byte) (n int, err error) {
+	return u.Underlying.Write(p)
+}
+
+// WriteString writes the contents of s to the underlying io.Writer.
+func (u *UnbufferedWriter) WriteString(s string) (n int, err error) {
+	return io.WriteString(u.Underlying, s)
+}
+
+// GetUnbufferedWriter returns a writer that writes directly to w.
+func GetUnbufferedWriter(w io.Writer) *UnbufferedWriter {
+	if u, ok := w.(*UnbufferedWriter); ok {
+		return u
+	}
+	return &UnbufferedWriter{Underlying: w}
+}
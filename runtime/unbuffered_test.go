@@ -0,0 +1,20 @@
+package runtime
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnbufferedWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := GetUnbufferedWriter(&b)
+	if _, err := w.WriteString("Hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.String() != "Hello" {
+		t.Errorf("expected writes to go directly to the underlying writer, got %q", b.String())
+	}
+	if GetUnbufferedWriter(w) != w {
+		t.Error("expected an existing unbuffered writer to be reused")
+	}
+}
@@ -57,33 +57,54 @@ func normalizePath(p string) string {
 // s is replaced with the string at the index in the _templ.txt file.
 func WriteString(w io.Writer, index int, s string) (err error) {
 	if developmentMode {
-		_, path, _, _ := runtime.Caller(1)
-		if !strings.HasSuffix(path, "_templ.go") {
-			return errors.New("templ: attempt to use WriteString from a non templ file")
-		}
-		path, err := filepath.EvalSymlinks(path)
-		if err != nil {
-			return fmt.Errorf("templ: failed to eval symlinks for %q: %w", path, err)
-		}
-
-		txtFilePath := GetDevModeTextFileName(path)
-		literals, err := getWatchedStrings(txtFilePath)
-		if err != nil {
-			return fmt.Errorf("templ: failed to get watched strings for %q: %w", path, err)
-		}
-		if index > len(literals) {
-			return fmt.Errorf("templ: failed to find line %d in %s", index, txtFilePath)
+		if s, err = getWatchedString(index); err != nil {
+			return err
 		}
+	}
+	_, err = io.WriteString(w, s)
+	return err
+}
 
-		s, err = strconv.Unquote(`"` + literals[index-1] + `"`)
+// WriteBytes writes the bytes of a literal to the writer. It's used instead of WriteString
+// when literals are generated as package-level byte slices. If development mode is enabled
+// the literal is replaced with the string at the index in the _templ.txt file.
+func WriteBytes(w io.Writer, index int, b []byte) (err error) {
+	if developmentMode {
+		s, err := getWatchedString(index)
 		if err != nil {
 			return err
 		}
+		_, err = io.WriteString(w, s)
+		return err
 	}
-	_, err = io.WriteString(w, s)
+	_, err = w.Write(b)
 	return err
 }
 
+// getWatchedString returns the string at the index in the _templ.txt file of the generated
+// file that called WriteString or WriteBytes.
+func getWatchedString(index int) (s string, err error) {
+	_, path, _, _ := runtime.Caller(2)
+	if !strings.HasSuffix(path, "_templ.go") {
+		return "", errors.New("templ: attempt to use WriteString from a non templ file")
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("templ: failed to eval symlinks for %q: %w", path, err)
+	}
+
+	txtFilePath := GetDevModeTextFileName(path)
+	literals, err := getWatchedStrings(txtFilePath)
+	if err != nil {
+		return "", fmt.Errorf("templ: failed to get watched strings for %q: %w", path, err)
+	}
+	if index > len(literals) {
+		return "", fmt.Errorf("templ: failed to find line %d in %s", index, txtFilePath)
+	}
+
+	return strconv.Unquote(`"` + literals[index-1] + `"`)
+}
+
 var (
 	watchModeCache  = map[string]watchState{}
 	watchStateMutex sync.Mutex
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -35,4 +36,20 @@ func TestWatchMode(t *testing.T) {
 			t.Errorf("got %q, want %q", actual, expected)
 		}
 	})
+	t.Run("WriteBytes writes the bytes when development mode is disabled", func(t *testing.T) {
+		sb := new(strings.Builder)
+		if err := WriteBytes(sb, 1, []byte("<div>")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sb.String() != "<div>" {
+			t.Errorf("got %q, want %q", sb.String(), "<div>")
+		}
+	})
+	t.Run("WriteBytes can only be used from generated files in development mode", func(t *testing.T) {
+		developmentMode = true
+		defer func() { developmentMode = false }()
+		if err := WriteBytes(new(strings.Builder), 1, []byte("<div>")); err == nil {
+			t.Error("expected an error")
+		}
+	})
 }
@@ -37,6 +37,18 @@ func TestCSSID(t *testing.T) {
 			t.Errorf("hash collision: %s == %s", id1, id2)
 		}
 	})
+	t.Run("IDs are the name and a prefix of the SHA-256 hash of the CSS", func(t *testing.T) {
+		// IDs are calculated during code generation with templ generate -static-css-ids,
+		// so the algorithm must not change.
+		actual := templ.CSSID("classA", "grid-column:1;grid-row:1;")
+		if expected := "classA_f781266f"; actual != expected {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	})
+}
+
+var spinKeyframes = &[]templ.CSSKeyframes{
+	{Name: "spin", ID: "spin_1", Rule: "@keyframes spin_1{to{rotate:360deg;}}"},
 }
 
 func TestCSSHandler(t *testing.T) {
@@ -67,6 +79,15 @@ func TestCSSHandler(t *testing.T) {
 			expectedMIMEType: "text/css",
 			expectedBody:     ".classA{background-color:white;}.classB{background-color:green;}",
 		},
+		{
+			name: "keyframes are rendered once",
+			input: []templ.CSSClass{
+				templ.ComponentCSSClass{ID: "classA", Class: templ.SafeCSS(".classA{animation:spin_1 1s;}"), Keyframes: spinKeyframes},
+				templ.ComponentCSSClass{ID: "classB", Class: templ.SafeCSS(".classB{animation:spin_1 2s;}"), Keyframes: spinKeyframes},
+			},
+			expectedMIMEType: "text/css",
+			expectedBody:     "@keyframes spin_1{to{rotate:360deg;}}.classA{animation:spin_1 1s;}.classB{animation:spin_1 2s;}",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -194,6 +215,16 @@ func TestRenderCSS(t *testing.T) {
 		ID:    "c2",
 		Class: ".c2{color:blue}",
 	}
+	k1 := templ.ComponentCSSClass{
+		ID:        "k1",
+		Class:     ".k1{animation:spin_1 1s;}",
+		Keyframes: spinKeyframes,
+	}
+	k2 := templ.ComponentCSSClass{
+		ID:        "k2",
+		Class:     ".k2{animation:spin_1 2s;}",
+		Keyframes: spinKeyframes,
+	}
 
 	tests := []struct {
 		name     string
@@ -243,6 +274,18 @@ func TestRenderCSS(t *testing.T) {
 			toRender: cssInputs,
 			expected: `<style type="text/css">.e{color:red}.j{color:red}</style>`,
 		},
+		{
+			name:     "keyframes are rendered before the class",
+			toIgnore: nil,
+			toRender: []any{k1},
+			expected: `<style type="text/css">@keyframes spin_1{to{rotate:360deg;}}.k1{animation:spin_1 1s;}</style>`,
+		},
+		{
+			name:     "keyframes shared by classes are only rendered once",
+			toIgnore: []any{k1},
+			toRender: []any{k1, k2},
+			expected: `<style type="text/css">.k2{animation:spin_1 2s;}</style>`,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -271,6 +314,16 @@ func TestRenderCSS(t *testing.T) {
 	}
 }
 
+func TestAnimationName(t *testing.T) {
+	c := templ.ComponentCSSClass{ID: "c1", Keyframes: spinKeyframes}
+	if actual := c.AnimationName("spin"); actual != "spin_1" {
+		t.Errorf("expected spin_1, got %q", actual)
+	}
+	if actual := c.AnimationName("fade"); actual != "fade" {
+		t.Errorf("expected unknown names to be returned unchanged, got %q", actual)
+	}
+}
+
 func TestRenderCSSItemsWithNonce(t *testing.T) {
 	ctx := templ.WithNonce(context.Background(), "testnonce")
 	b := new(bytes.Buffer)
@@ -462,6 +515,70 @@ func TestErrorWrapping(t *testing.T) {
 	})
 }
 
+type attrCount int
+
+type attrFlag bool
+
+type attrID string
+
+// attrColor is an enum that implements fmt.Stringer.
+type attrColor int
+
+func (c attrColor) String() string { return [...]string{"red", "green"}[c] }
+
+func TestJoinAttrErrs(t *testing.T) {
+	format := func(s string, err error) string {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return s
+	}
+	tests := []struct {
+		name     string
+		input    any
+		actual   string
+		expected string
+	}{
+		{name: "strings", input: "text", actual: format(templ.JoinAttrErrs("text")), expected: "text"},
+		{name: "ints", input: 42, actual: format(templ.JoinAttrErrs(42)), expected: "42"},
+		{name: "negative int64s", input: int64(-7), actual: format(templ.JoinAttrErrs(int64(-7))), expected: "-7"},
+		{name: "uint8s", input: uint8(255), actual: format(templ.JoinAttrErrs(uint8(255))), expected: "255"},
+		{name: "float64s", input: 1.5, actual: format(templ.JoinAttrErrs(1.5)), expected: "1.5"},
+		{name: "large float64s", input: 1e21, actual: format(templ.JoinAttrErrs(1e21)), expected: "1e+21"},
+		{name: "float32s", input: float32(0.1), actual: format(templ.JoinAttrErrs(float32(0.1))), expected: "0.1"},
+		{name: "complex numbers", input: complex(1, 2), actual: format(templ.JoinAttrErrs(complex(1, 2))), expected: "(1+2i)"},
+		{name: "bools", input: true, actual: format(templ.JoinAttrErrs(true)), expected: "true"},
+		{name: "types based on basic types", input: attrCount(3), actual: format(templ.JoinAttrErrs(attrCount(3))), expected: "3"},
+		{name: "types based on bools", input: attrFlag(true), actual: format(templ.JoinAttrErrs(attrFlag(true))), expected: "true"},
+		{name: "types based on strings", input: attrID("a"), actual: format(templ.JoinAttrErrs(attrID("a"))), expected: "a"},
+		{name: "fmt.Stringers", input: attrColor(1), actual: format(templ.JoinAttrErrs(attrColor(1))), expected: "green"},
+		{name: "safe URLs", input: templ.SafeURL("/a"), actual: format(templ.JoinAttrErrs(templ.SafeURL("/a"))), expected: "/a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, tt.actual)
+			}
+			if expected := fmt.Sprint(tt.input); tt.actual != expected {
+				t.Errorf("expected the same output as fmt.Sprint %q, got %q", expected, tt.actual)
+			}
+		})
+	}
+	t.Run("errors are joined", func(t *testing.T) {
+		expectedErr := errors.New("failed")
+		_, err := templ.JoinAttrErrs(1, expectedErr)
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("expected %v, got %v", expectedErr, err)
+		}
+	})
+	t.Run("strings are formatted without allocating", func(t *testing.T) {
+		s := "text"
+		if allocs := testing.AllocsPerRun(100, func() { _, _ = templ.JoinAttrErrs(s) }); allocs != 0 {
+			t.Errorf("expected no allocations, got %v", allocs)
+		}
+	})
+}
+
 func TestRawComponent(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -613,6 +730,93 @@ func TestNonce(t *testing.T) {
 	})
 }
 
+func TestWithTemplateScope(t *testing.T) {
+	handle := templ.NewOnceHandle()
+	script := handle.Once()
+	ctx := templ.WithChildren(context.Background(), templ.Raw("<script></script>"))
+	ctx = templ.WithNonce(ctx, "abc123")
+	if err := script.Render(ctx, io.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scoped := templ.WithTemplateScope(ctx)
+	if nonce := templ.GetNonce(scoped); nonce != "abc123" {
+		t.Errorf("expected the nonce to be shared, got %q", nonce)
+	}
+	var sb bytes.Buffer
+	if err := script.Render(scoped, &sb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sb.String() != "<script></script>" {
+		t.Errorf("expected content that was rendered in the document to be rendered in the template, got %q", sb.String())
+	}
+	if id, scopedID := templ.NewID(ctx, "item"), templ.NewID(scoped, "item"); id == scopedID {
+		t.Errorf("expected IDs to be unique across the template, got %q twice", id)
+	}
+	t.Run("other context values are shared", func(t *testing.T) {
+		ctx := templ.WithFlagProvider(context.Background(), templ.FlagProviderFunc(func(ctx context.Context, flag string) (bool, error) {
+			return true, nil
+		}))
+		ctx = templ.WithTextTransformers(ctx, func(s string) string { return s + "!" })
+		scoped := templ.WithTemplateScope(ctx)
+		if enabled, err := templ.FlagEnabled(scoped, "flag"); err != nil || !enabled {
+			t.Errorf("expected the flag provider to be shared, got %v, %v", enabled, err)
+		}
+		if actual := templ.TransformText(scoped, "a"); actual != "a!" {
+			t.Errorf("expected the text transformers to be shared, got %q", actual)
+		}
+	})
+}
+
+func TestRenderNonceAttribute(t *testing.T) {
+	t.Run("renders nothing if a nonce has not been set", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := templ.RenderNonceAttribute(context.Background(), &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("expected no output, got %q", buf.String())
+		}
+	})
+	t.Run("renders an escaped nonce attribute if a nonce has been set", func(t *testing.T) {
+		var buf bytes.Buffer
+		ctx := templ.WithNonce(context.Background(), `abc"123`)
+		if err := templ.RenderNonceAttribute(ctx, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := ` nonce="abc&#34;123"`
+		if buf.String() != expected {
+			t.Errorf("expected %q, got %q", expected, buf.String())
+		}
+	})
+}
+
+func TestHasAttribute(t *testing.T) {
+	var nilNonce *string
+	attrs := templ.Attributes{"Nonce": "abc", "defer": false, "async": true, "integrity": nilNonce}
+	if !templ.HasAttribute(attrs, "nonce") {
+		t.Error("expected attribute names to be compared case-insensitively")
+	}
+	if templ.HasAttribute(attrs, "defer") {
+		t.Error("expected attributes with false values not to be found, because they aren't rendered")
+	}
+	if !templ.HasAttribute(attrs, "async") {
+		t.Error("expected attributes with true values to be found")
+	}
+	if templ.HasAttribute(attrs, "integrity") {
+		t.Error("expected attributes with nil values not to be found, because they aren't rendered")
+	}
+	if templ.HasAttribute(templ.Attributes{"nonce": false}, "nonce") {
+		t.Error("expected a nonce with a false value not to be found")
+	}
+	if templ.HasAttribute(attrs, "src") {
+		t.Error("expected attributes that aren't set not to be found")
+	}
+	if templ.HasAttribute(nil, "nonce") {
+		t.Error("expected nil attributes not to have attributes")
+	}
+}
+
 func TestRenderAttributes(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -766,6 +970,43 @@ func TestRenderAttributes(t *testing.T) {
 			},
 			expected: ` active data-name="value" dynamic`,
 		},
+		{
+			name: "named boolean types follow the boolean attribute rules",
+			attributes: templ.Attributes{
+				"disabled": attrFlag(false),
+				"hidden":   attrFlag(true),
+				"open":     ptr(attrFlag(false)),
+				"required": ptr(attrFlag(true)),
+				"checked":  (*attrFlag)(nil),
+			},
+			expected: ` hidden required`,
+		},
+		{
+			name: "named string and numeric types are rendered as strings",
+			attributes: templ.Attributes{
+				"href":   templ.SafeURL("/home"),
+				"id":     ptr(attrID("main")),
+				"count":  attrCount(3),
+				"nil-id": (*attrID)(nil),
+			},
+			expected: ` count="3" href="/home" id="main"`,
+		},
+		{
+			name: "pointers to pointers are dereferenced",
+			attributes: templ.Attributes{
+				"disabled": ptr(ptr(false)),
+				"hidden":   ptr(ptr(true)),
+			},
+			expected: ` hidden`,
+		},
+		{
+			name: "values of unsupported types are not rendered",
+			attributes: templ.Attributes{
+				"items": []string{"a"},
+				"nil":   nil,
+			},
+			expected: ``,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -784,6 +1025,22 @@ func TestRenderAttributes(t *testing.T) {
 	}
 }
 
+func TestRenderOrderedAttributes(t *testing.T) {
+	attributes := templ.OrderedAttributes{
+		{Key: "id", Value: "test-id"},
+		{Key: "class", Value: "test-class"},
+		{Key: "disabled", Value: true},
+	}
+	var buf bytes.Buffer
+	if err := templ.RenderAttributes(context.Background(), &buf, attributes); err != nil {
+		t.Fatalf("RenderAttributes failed: %v", err)
+	}
+	expected := ` id="test-id" class="test-class" disabled`
+	if actual := buf.String(); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
 func ptr[T any](x T) *T {
 	return &x
 }
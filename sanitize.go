@@ -0,0 +1,203 @@
+package templ
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// SanitizePolicy is an allowlist of the HTML elements and attributes that Sanitized keeps.
+//
+// Event handler attributes (on*) and style attributes are always removed. URL attributes,
+// such as href and src, are removed if the URL fails the same checks as templ.URL.
+type SanitizePolicy struct {
+	// Elements that are kept, mapped to the attributes that are kept on each element.
+	Elements map[string][]string
+	// Attributes that are kept on all allowed elements.
+	Attributes []string
+}
+
+// RichTextPolicy returns a policy that allows the elements and attributes commonly used in
+// user-generated rich text, such as paragraphs, emphasis, lists, links and images. The policy
+// can be modified to allow or remove elements.
+func RichTextPolicy() SanitizePolicy {
+	return SanitizePolicy{
+		Elements: map[string][]string{
+			"a":          {"href"},
+			"b":          nil,
+			"blockquote": {"cite"},
+			"br":         nil,
+			"code":       nil,
+			"del":        nil,
+			"em":         nil,
+			"h1":         nil,
+			"h2":         nil,
+			"h3":         nil,
+			"h4":         nil,
+			"h5":         nil,
+			"h6":         nil,
+			"hr":         nil,
+			"i":          nil,
+			"img":        {"src", "alt", "width", "height"},
+			"ins":        nil,
+			"li":         nil,
+			"mark":       nil,
+			"ol":         {"start"},
+			"p":          nil,
+			"pre":        nil,
+			"s":          nil,
+			"small":      nil,
+			"span":       nil,
+			"strong":     nil,
+			"sub":        nil,
+			"sup":        nil,
+			"table":      nil,
+			"tbody":      nil,
+			"td":         {"colspan", "rowspan"},
+			"th":         {"colspan", "rowspan"},
+			"thead":      nil,
+			"tr":         nil,
+			"u":          nil,
+			"ul":         nil,
+		},
+		Attributes: []string{"title"},
+	}
+}
+
+// Sanitized renders untrusted HTML, such as user-generated rich text, keeping only the
+// elements and attributes allowed by the policy.
+//
+// Elements that aren't allowed are removed, but their text content is kept. The content of
+// elements that can run code or contain raw text, such as script, style and iframe, is
+// removed. Unclosed elements are closed, so that the HTML can't affect the rest of the page.
+func Sanitized(html string, policy SanitizePolicy) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		return sanitizeHTML(w, html, policy)
+	})
+}
+
+// sanitizeRemovedContentElements are removed with their content, even if the policy allows them.
+var sanitizeRemovedContentElements = map[string]struct{}{
+	"script": {}, "style": {}, "template": {}, "iframe": {}, "object": {}, "embed": {},
+	"noscript": {}, "noembed": {}, "noframes": {}, "textarea": {}, "title": {}, "xmp": {},
+	"plaintext": {}, "select": {}, "svg": {}, "math": {},
+}
+
+var sanitizeVoidElements = map[string]struct{}{
+	"area": {}, "base": {}, "br": {}, "col": {}, "embed": {}, "hr": {}, "img": {}, "input": {},
+	"link": {}, "meta": {}, "source": {}, "track": {}, "wbr": {},
+}
+
+var sanitizeURLAttributes = map[string]struct{}{
+	"action": {}, "background": {}, "cite": {}, "formaction": {}, "href": {}, "longdesc": {},
+	"poster": {}, "src": {}, "srcset": {},
+}
+
+func sanitizeHTML(w io.Writer, s string, policy SanitizePolicy) (err error) {
+	z := html.NewTokenizer(strings.NewReader(s))
+	var open []string
+	// removing is the name of the element whose content is being removed, and depth is the
+	// number of nested elements with the same name.
+	var removing string
+	var depth int
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err = z.Err(); !errors.Is(err, io.EOF) {
+				return err
+			}
+			break
+		}
+		t := z.Token()
+		if removing != "" {
+			switch {
+			case tt == html.StartTagToken && t.Data == removing:
+				depth++
+			case tt == html.EndTagToken && t.Data == removing:
+				depth--
+				if depth == 0 {
+					removing = ""
+				}
+			}
+			continue
+		}
+		switch tt {
+		case html.TextToken:
+			if _, err = io.WriteString(w, EscapeString(t.Data)); err != nil {
+				return err
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if _, ok := sanitizeRemovedContentElements[t.Data]; ok {
+				if tt == html.StartTagToken {
+					if _, void := sanitizeVoidElements[t.Data]; !void {
+						removing, depth = t.Data, 1
+					}
+				}
+				continue
+			}
+			allowedAttrs, ok := policy.Elements[t.Data]
+			if !ok {
+				continue
+			}
+			if err = writeSanitizedStartTag(w, t, allowedAttrs, policy.Attributes); err != nil {
+				return err
+			}
+			if _, void := sanitizeVoidElements[t.Data]; void {
+				continue
+			}
+			if tt == html.SelfClosingTagToken {
+				if err = writeStrings(w, "</", t.Data, ">"); err != nil {
+					return err
+				}
+				continue
+			}
+			open = append(open, t.Data)
+		case html.EndTagToken:
+			i := slices.Index(open, t.Data)
+			if i < 0 {
+				continue
+			}
+			// Close the element, and any elements within it that weren't closed.
+			for len(open) > i {
+				if err = writeStrings(w, "</", open[len(open)-1], ">"); err != nil {
+					return err
+				}
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	for len(open) > 0 {
+		if err = writeStrings(w, "</", open[len(open)-1], ">"); err != nil {
+			return err
+		}
+		open = open[:len(open)-1]
+	}
+	return nil
+}
+
+func writeSanitizedStartTag(w io.Writer, t html.Token, elementAttrs, globalAttrs []string) (err error) {
+	if err = writeStrings(w, "<", t.Data); err != nil {
+		return err
+	}
+	for _, attr := range t.Attr {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || strings.HasPrefix(key, "on") || key == "style" {
+			continue
+		}
+		if !slices.Contains(elementAttrs, key) && !slices.Contains(globalAttrs, key) {
+			continue
+		}
+		if _, isURL := sanitizeURLAttributes[key]; isURL && URL(strings.TrimSpace(attr.Val)) == FailedSanitizationURL {
+			continue
+		}
+		if err = writeStrings(w, " ", key, `="`, EscapeString(attr.Val), `"`); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, ">")
+	return err
+}
@@ -0,0 +1,131 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestSanitized(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		policy   templ.SanitizePolicy
+		expected string
+	}{
+		{
+			name:     "allowed elements and attributes are kept",
+			input:    `<p title="Greeting">Hello <strong>world</strong><br/></p>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p title="Greeting">Hello <strong>world</strong><br></p>`,
+		},
+		{
+			name:     "elements that are not allowed are removed, but their text is kept",
+			input:    `<div><p>Hello <blink>world</blink></p></div>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p>Hello world</p>`,
+		},
+		{
+			name:     "attributes that are not allowed are removed",
+			input:    `<p class="x" id="y">Hello</p>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p>Hello</p>`,
+		},
+		{
+			name:     "script elements are removed with their content",
+			input:    `<p>Hello</p><script>alert("xss")</script><p>World</p>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p>Hello</p><p>World</p>`,
+		},
+		{
+			name:     "script elements are removed even if they are allowed",
+			input:    `<script>alert("xss")</script>`,
+			policy:   templ.SanitizePolicy{Elements: map[string][]string{"script": nil}},
+			expected: ``,
+		},
+		{
+			name:     "nested elements whose content is removed are handled",
+			input:    `<svg><svg><a href="/">x</a></svg><p>removed</p></svg><p>kept</p>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p>kept</p>`,
+		},
+		{
+			name:     "event handlers and style attributes are always removed",
+			input:    `<p onclick="alert(1)" style="color: red">Hello</p>`,
+			policy:   templ.SanitizePolicy{Elements: map[string][]string{"p": {"onclick", "style"}}},
+			expected: `<p>Hello</p>`,
+		},
+		{
+			name:     "safe URLs are kept",
+			input:    `<a href="https://example.com/?a=1&amp;b=2">Link</a>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<a href="https://example.com/?a=1&amp;b=2">Link</a>`,
+		},
+		{
+			name:     "unsafe URLs are removed",
+			input:    `<a href="javascript:alert(1)">Link</a><a href=" java&#x09;script:alert(1)">Link</a><img src="data:text/html,x">`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<a>Link</a><a>Link</a><img>`,
+		},
+		{
+			name:     "text is escaped",
+			input:    `<p>1 &lt; 2 &amp;&amp; "quoted"</p>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p>1 &lt; 2 &amp;&amp; &#34;quoted&#34;</p>`,
+		},
+		{
+			name:     "attribute values are escaped",
+			input:    `<p title='"><script>alert(1)</script>'>Hello</p>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">Hello</p>`,
+		},
+		{
+			name:     "unclosed elements are closed",
+			input:    `<p><strong>Hello`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p><strong>Hello</strong></p>`,
+		},
+		{
+			name:     "end tags close unclosed nested elements",
+			input:    `<p><em>Hello</p>World`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p><em>Hello</em></p>World`,
+		},
+		{
+			name:     "unmatched end tags are removed",
+			input:    `Hello</div></p>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `Hello`,
+		},
+		{
+			name:     "comments are removed",
+			input:    `<p>Hello<!-- comment --></p>`,
+			policy:   templ.RichTextPolicy(),
+			expected: `<p>Hello</p>`,
+		},
+		{
+			name:     "global attributes are kept on all allowed elements",
+			input:    `<p lang="en">Hello</p><em lang="fr">Bonjour</em>`,
+			policy:   templ.SanitizePolicy{Elements: map[string][]string{"p": nil, "em": nil}, Attributes: []string{"lang"}},
+			expected: `<p lang="en">Hello</p><em lang="fr">Bonjour</em>`,
+		},
+		{
+			name:     "an empty policy removes all elements",
+			input:    `<p>Hello <b>world</b></p>`,
+			expected: `Hello world`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := templ.Sanitized(tt.input, tt.policy).Render(context.Background(), &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, sb.String())
+			}
+		})
+	}
+}
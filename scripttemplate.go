@@ -121,6 +121,24 @@ func SafeScript(functionName string, params ...any) string {
 	return sb.String()
 }
 
+// SafeScriptBind is like SafeScript, but the arguments of the event handler that makes the
+// call are passed to the function after the params. It's used to partially apply scripts.
+//
+// Given:
+//
+//	functionName("some string")
+//
+// It would render:
+//
+//	functionName(&#34;some string&#34;,...arguments)
+func SafeScriptBind(functionName string, params ...any) string {
+	call := strings.TrimSuffix(SafeScript(functionName, params...), ")")
+	if len(params) > 0 {
+		call += ","
+	}
+	return call + "...arguments)"
+}
+
 // SafeScript encodes unknown parameters for safety for inline scripts.
 func SafeScriptInline(functionName string, params ...any) string {
 	if !jsFunctionName.MatchString(functionName) {
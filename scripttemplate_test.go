@@ -96,3 +96,30 @@ func TestJSExpression(t *testing.T) {
 		t.Fatalf("TestJSExpression: expected %q, got %q", expected, actual)
 	}
 }
+
+func TestSafeScriptBind(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   []any
+		expected string
+	}{
+		{
+			name:     "arguments are passed after the params",
+			params:   []any{"StringValue", 123},
+			expected: "myJSFunction(&#34;StringValue&#34;,123,...arguments)",
+		},
+		{
+			name:     "arguments are passed if there are no params",
+			params:   nil,
+			expected: "myJSFunction(...arguments)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := templ.SafeScriptBind("myJSFunction", tt.params...)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
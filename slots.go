@@ -0,0 +1,62 @@
+package templ
+
+import "context"
+
+// Slots maps slot names to the components that are rendered in place of a
+// { slot "name" } expression in a layout template.
+type Slots map[string]Component
+
+// Get returns the component for the named slot, or NopComponent if the slot
+// has not been provided.
+func (s Slots) Get(name string) Component {
+	if c, ok := s[name]; ok && c != nil {
+		return c
+	}
+	return NopComponent
+}
+
+// Has returns true if the named slot has been provided.
+func (s Slots) Has(name string) bool {
+	c, ok := s[name]
+	return ok && c != nil
+}
+
+// Merge returns slots that contain the slots in s, and the slots in defaults that
+// aren't in s. It's used to pass the blocks of a template that extends a layout to the
+// layout, so that the blocks of templates that extend it take precedence.
+func (s Slots) Merge(defaults Slots) Slots {
+	merged := make(Slots, len(s)+len(defaults))
+	for name, c := range defaults {
+		merged[name] = c
+	}
+	for name, c := range s {
+		if c != nil {
+			merged[name] = c
+		}
+	}
+	return merged
+}
+
+// WithSlots sets the slots that are available to the next component that is rendered.
+func WithSlots(ctx context.Context, slots Slots) context.Context {
+	ctx, v := getContext(ctx)
+	v.slots = slots
+	return ctx
+}
+
+// ClearSlots removes the slots from the context, so that they aren't passed
+// to components further down the tree.
+func ClearSlots(ctx context.Context) context.Context {
+	_, v := getContext(ctx)
+	v.slots = nil
+	return ctx
+}
+
+// GetSlots from the context.
+func GetSlots(ctx context.Context) Slots {
+	_, v := getContext(ctx)
+	if v.slots == nil {
+		return Slots{}
+	}
+	return v.slots
+}
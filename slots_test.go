@@ -0,0 +1,64 @@
+package templ_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestSlots(t *testing.T) {
+	t.Run("missing slots render nothing", func(t *testing.T) {
+		var sb strings.Builder
+		if err := templ.GetSlots(context.Background()).Get("header").Render(context.Background(), &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sb.String() != "" {
+			t.Errorf("expected empty output, got %q", sb.String())
+		}
+	})
+	t.Run("slots can be retrieved by name", func(t *testing.T) {
+		ctx := templ.WithSlots(context.Background(), templ.Slots{
+			"header": templ.Raw("<h1>Header</h1>"),
+		})
+		var sb strings.Builder
+		if err := templ.GetSlots(ctx).Get("header").Render(ctx, &sb); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sb.String() != "<h1>Header</h1>" {
+			t.Errorf("unexpected output %q", sb.String())
+		}
+	})
+	t.Run("slots can be cleared", func(t *testing.T) {
+		ctx := templ.WithSlots(context.Background(), templ.Slots{
+			"header": templ.Raw("<h1>Header</h1>"),
+		})
+		ctx = templ.ClearSlots(ctx)
+		if len(templ.GetSlots(ctx)) != 0 {
+			t.Errorf("expected no slots, got %v", templ.GetSlots(ctx))
+		}
+	})
+	t.Run("Has returns true for provided slots", func(t *testing.T) {
+		slots := templ.Slots{"header": templ.Raw("<h1>Header</h1>"), "footer": nil}
+		if !slots.Has("header") {
+			t.Error("expected header to be provided")
+		}
+		if slots.Has("footer") || slots.Has("nav") {
+			t.Error("expected nil and missing slots not to be provided")
+		}
+	})
+	t.Run("Merge keeps slots over defaults", func(t *testing.T) {
+		header, footer, defaultHeader := templ.Raw("header"), templ.Raw("footer"), templ.Raw("default")
+		merged := templ.Slots{"header": header, "nav": nil}.Merge(templ.Slots{"header": defaultHeader, "footer": footer})
+		var sb strings.Builder
+		for _, name := range []string{"header", "footer", "nav"} {
+			if err := merged.Get(name).Render(context.Background(), &sb); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if sb.String() != "headerfooter" {
+			t.Errorf("unexpected output %q", sb.String())
+		}
+	})
+}
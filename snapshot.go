@@ -0,0 +1,89 @@
+package templ
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrorSnapshot is a snapshot of the inputs of a component that failed to render.
+type ErrorSnapshot struct {
+	// Component name, e.g. "Card", or "Page.Render" for a method.
+	Component string `json:"component"`
+	// FileName of the template file.
+	FileName string `json:"fileName"`
+	// Params of the component, including the receiver of a method, in declaration order.
+	Params []SnapshotParam `json:"params"`
+}
+
+// SnapshotParam is the JSON serialized value of a component parameter.
+type SnapshotParam struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value,omitempty"`
+	// Error is set if the value could not be serialized to JSON.
+	Error string `json:"error,omitempty"`
+}
+
+// Param unmarshals the value of the named parameter into v, so that the component can be
+// rendered again with the same inputs.
+func (s ErrorSnapshot) Param(name string, v any) error {
+	for _, p := range s.Params {
+		if p.Name != name {
+			continue
+		}
+		if p.Error != "" {
+			return fmt.Errorf("templ: parameter %q was not captured: %s", name, p.Error)
+		}
+		return json.Unmarshal(p.Value, v)
+	}
+	return fmt.Errorf("templ: parameter %q not found in snapshot of %s", name, s.Component)
+}
+
+// SnapshotError is returned when a component fails to render, if the component was generated
+// with error snapshots enabled. It contains a snapshot of the inputs of the innermost component
+// that failed.
+type SnapshotError struct {
+	Snapshot ErrorSnapshot
+	Err      error
+}
+
+func (e *SnapshotError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SnapshotError) Unwrap() error {
+	return e.Err
+}
+
+// WithErrorSnapshot is used by generated code to attach the inputs of a component to a
+// rendering error. params are pairs of parameter names and values.
+//
+// If err already has a snapshot attached by a nested component, or the context was cancelled,
+// err is returned unchanged.
+func WithErrorSnapshot(err error, component, fileName string, params ...any) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	var se *SnapshotError
+	if errors.As(err, &se) {
+		return err
+	}
+	s := ErrorSnapshot{
+		Component: component,
+		FileName:  fileName,
+		Params:    make([]SnapshotParam, 0, len(params)/2),
+	}
+	for i := 0; i+1 < len(params); i += 2 {
+		p := SnapshotParam{Name: fmt.Sprint(params[i])}
+		var jsonErr error
+		if p.Value, jsonErr = json.Marshal(params[i+1]); jsonErr != nil {
+			p.Error = jsonErr.Error()
+		}
+		s.Params = append(s.Params, p)
+	}
+	return &SnapshotError{Snapshot: s, Err: err}
+}
@@ -0,0 +1,81 @@
+package templ_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestWithErrorSnapshot(t *testing.T) {
+	type item struct {
+		Name string
+	}
+	errRender := errors.New("render failed")
+
+	t.Run("nil errors are not wrapped", func(t *testing.T) {
+		if err := templ.WithErrorSnapshot(nil, "Card", "card.templ", "title", "Hello"); err != nil {
+			t.Errorf("expected nil, got %v", err)
+		}
+	})
+	t.Run("errors are wrapped with a snapshot of the params", func(t *testing.T) {
+		err := templ.WithErrorSnapshot(errRender, "Card", "card.templ", "title", "Hello", "item", item{Name: "Pen"})
+		if !errors.Is(err, errRender) {
+			t.Errorf("expected the original error to be wrapped, got %v", err)
+		}
+		if err.Error() != errRender.Error() {
+			t.Errorf("expected the error message to be unchanged, got %q", err.Error())
+		}
+		var se *templ.SnapshotError
+		if !errors.As(err, &se) {
+			t.Fatalf("expected a SnapshotError, got %T", err)
+		}
+		if se.Snapshot.Component != "Card" || se.Snapshot.FileName != "card.templ" {
+			t.Errorf("unexpected snapshot: %+v", se.Snapshot)
+		}
+		var title string
+		if err := se.Snapshot.Param("title", &title); err != nil || title != "Hello" {
+			t.Errorf("expected title %q, got %q, %v", "Hello", title, err)
+		}
+		var i item
+		if err := se.Snapshot.Param("item", &i); err != nil || i.Name != "Pen" {
+			t.Errorf("expected item %q, got %q, %v", "Pen", i.Name, err)
+		}
+		if err := se.Snapshot.Param("missing", &i); err == nil {
+			t.Error("expected an error for a missing param")
+		}
+	})
+	t.Run("params that can't be serialized record an error", func(t *testing.T) {
+		err := templ.WithErrorSnapshot(errRender, "Card", "card.templ", "ch", make(chan int))
+		var se *templ.SnapshotError
+		if !errors.As(err, &se) {
+			t.Fatalf("expected a SnapshotError, got %T", err)
+		}
+		if se.Snapshot.Params[0].Error == "" {
+			t.Error("expected the param to have an error")
+		}
+		var v any
+		if err := se.Snapshot.Param("ch", &v); err == nil {
+			t.Error("expected an error for a param that wasn't captured")
+		}
+	})
+	t.Run("the innermost snapshot is kept", func(t *testing.T) {
+		inner := templ.WithErrorSnapshot(errRender, "Row", "row.templ", "index", 1)
+		outer := templ.WithErrorSnapshot(fmt.Errorf("page: %w", inner), "Page", "page.templ")
+		var se *templ.SnapshotError
+		if !errors.As(outer, &se) {
+			t.Fatalf("expected a SnapshotError, got %T", outer)
+		}
+		if se.Snapshot.Component != "Row" {
+			t.Errorf("expected the snapshot of Row, got %q", se.Snapshot.Component)
+		}
+	})
+	t.Run("context cancellation is not wrapped", func(t *testing.T) {
+		err := templ.WithErrorSnapshot(context.Canceled, "Card", "card.templ")
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package templtest
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/html"
+)
+
+// Diff returns a line based diff of the expected and actual HTML, or an empty string if they're
+// equivalent.
+//
+// Differences that don't affect the document are ignored. Runs of whitespace in text are
+// collapsed to a single space, and whitespace next to the tags of block-level elements is
+// removed, as browsers display them, except within <pre>, <textarea>, <script> and <style>
+// elements. Attributes are compared without depending on their order or quoting, and
+// the same character references are used for both.
+func Diff(expected, actual string) (diff string, err error) {
+	e, err := normalize(expected)
+	if err != nil {
+		return "", err
+	}
+	a, err := normalize(actual)
+	if err != nil {
+		return "", err
+	}
+	return cmp.Diff(e, a), nil
+}
+
+// normalize writes each tag, comment and text of the HTML on its own line, indented by its
+// depth.
+func normalize(s string) (string, error) {
+	var sb strings.Builder
+	var depth int
+	// raw is the name of the element whose text is written unchanged.
+	var raw string
+	// text is collapsed text that hasn't been written yet, since whitespace at its end is
+	// removed if it's followed by a block-level element.
+	var text string
+	// afterBlock is true if the last tag was a block-level element, so whitespace at the start
+	// of the following text is removed.
+	afterBlock := true
+	writeLine := func(s string) {
+		sb.WriteString(strings.Repeat("  ", depth))
+		sb.WriteString(s)
+		sb.WriteByte('\n')
+	}
+	writeText := func(block bool) {
+		if block {
+			text = strings.TrimSuffix(text, " ")
+		}
+		if text != "" {
+			writeLine(html.EscapeString(text))
+		}
+		text = ""
+		afterBlock = block
+	}
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return "", err
+			}
+			writeText(true)
+			return sb.String(), nil
+		case html.TextToken:
+			if raw != "" {
+				writeLine(string(z.Text()))
+				continue
+			}
+			t := collapseWhitespace(string(z.Text()))
+			if afterBlock || strings.HasSuffix(text, " ") {
+				t = strings.TrimPrefix(t, " ")
+			}
+			text += t
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			_, block := blockElements[t.Data]
+			writeText(block)
+			for i := range t.Attr {
+				if t.Attr[i].Key == "class" {
+					t.Attr[i].Val = strings.Join(strings.Fields(t.Attr[i].Val), " ")
+				}
+			}
+			slices.SortStableFunc(t.Attr, func(a, b html.Attribute) int {
+				return strings.Compare(a.Key, b.Key)
+			})
+			t.Type = html.StartTagToken
+			writeLine(t.String())
+			if _, ok := voidElements[t.Data]; ok || tt == html.SelfClosingTagToken {
+				continue
+			}
+			if _, ok := rawElements[t.Data]; ok {
+				raw = t.Data
+			}
+			depth++
+		case html.EndTagToken:
+			t := z.Token()
+			_, block := blockElements[t.Data]
+			writeText(block)
+			if raw == t.Data {
+				raw = ""
+			}
+			depth = max(depth-1, 0)
+			writeLine(t.String())
+		case html.DoctypeToken:
+			writeText(true)
+			writeLine(z.Token().String())
+		default:
+			// Comments don't change whether whitespace is removed.
+			writeText(afterBlock && text == "")
+			writeLine(string(z.Raw()))
+		}
+	}
+}
+
+// collapseWhitespace replaces each run of whitespace in s with a single space.
+func collapseWhitespace(s string) string {
+	var sb strings.Builder
+	var space bool
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			space = true
+			continue
+		}
+		if space {
+			sb.WriteByte(' ')
+			space = false
+		}
+		sb.WriteRune(r)
+	}
+	if space {
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+var voidElements = elementSet("area base br col embed hr img input link meta source track wbr")
+
+var blockElements = elementSet(`address article aside base blockquote body caption col colgroup
+dd details dialog div dl dt fieldset figcaption figure footer form h1 h2 h3 h4 h5 h6 head header
+hgroup hr html legend li link main menu meta nav noscript ol optgroup option p pre script search
+section select style summary table tbody td template tfoot th thead title tr ul`)
+
+var rawElements = elementSet("pre script style textarea")
+
+func elementSet(names string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, name := range strings.Fields(names) {
+		set[name] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,110 @@
+package templtest
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name       string
+		expected   string
+		actual     string
+		equivalent bool
+	}{
+		{
+			name:       "whitespace between elements is ignored",
+			expected:   "<div>\n\t<p>Hello</p>\n</div>",
+			actual:     "<div><p>Hello</p></div>",
+			equivalent: true,
+		},
+		{
+			name:       "whitespace in text is collapsed",
+			expected:   "<p>Hello,\n\t world</p>",
+			actual:     "<p> Hello, world </p>",
+			equivalent: true,
+		},
+		{
+			name:     "whitespace between inline elements is significant",
+			expected: "<p><span>a</span> <span>b</span></p>",
+			actual:   "<p><span>a</span><span>b</span></p>",
+		},
+		{
+			name:     "whitespace between text and inline elements is significant",
+			expected: "<p><b>Hello</b>, world</p>",
+			actual:   "<p><b>Hello</b> , world</p>",
+		},
+		{
+			name:       "whitespace between inline elements is collapsed",
+			expected:   "<p><span>a</span>\n\t<span>b</span></p>",
+			actual:     "<p><span>a</span> <span>b</span></p>",
+			equivalent: true,
+		},
+		{
+			name:       "attribute order and quoting are ignored",
+			expected:   `<input type="text" name='a' disabled>`,
+			actual:     `<input disabled="" name="a" type=text>`,
+			equivalent: true,
+		},
+		{
+			name:       "whitespace between classes is ignored",
+			expected:   `<div class="a  b"></div>`,
+			actual:     `<div class="a b"></div>`,
+			equivalent: true,
+		},
+		{
+			name:       "character references are ignored",
+			expected:   `<p>&#39;a&#39; &amp; b</p>`,
+			actual:     `<p>'a' &amp; b</p>`,
+			equivalent: true,
+		},
+		{
+			name:       "self-closing void elements are ignored",
+			expected:   `<br/><img src="a.png" />`,
+			actual:     `<br><img src="a.png">`,
+			equivalent: true,
+		},
+		{
+			name:       "doctype case is ignored",
+			expected:   `<!doctype html>`,
+			actual:     `<!DOCTYPE html>`,
+			equivalent: true,
+		},
+		{
+			name:     "text changes are reported",
+			expected: `<p>Hello</p>`,
+			actual:   `<p>Goodbye</p>`,
+		},
+		{
+			name:     "attribute changes are reported",
+			expected: `<a href="/a">a</a>`,
+			actual:   `<a href="/b">a</a>`,
+		},
+		{
+			name:     "structure changes are reported",
+			expected: `<div><p>a</p></div>`,
+			actual:   `<div></div><p>a</p>`,
+		},
+		{
+			name:     "whitespace in pre elements is significant",
+			expected: "<pre>a  b</pre>",
+			actual:   "<pre>a b</pre>",
+		},
+		{
+			name:     "whitespace in scripts is significant",
+			expected: "<script>let s = 'a  b';</script>",
+			actual:   "<script>let s = 'a b';</script>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := Diff(tt.expected, tt.actual)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.equivalent && diff != "" {
+				t.Errorf("expected no diff, got:\n%s", diff)
+			}
+			if !tt.equivalent && diff == "" {
+				t.Error("expected a diff")
+			}
+		})
+	}
+}
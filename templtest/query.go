@@ -0,0 +1,149 @@
+package templtest
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Element is an element of rendered HTML, see Query.
+type Element struct {
+	node *html.Node
+}
+
+// Query renders the component, and returns the elements that match the CSS selector, in
+// document order. The test fails if the component returns an error, or the selector is
+// invalid.
+//
+//	links := templtest.Query(t, nav(), "ul > li > a[href]")
+//	if len(links) != 3 {
+//		t.Fatalf("expected 3 links, got %d", len(links))
+//	}
+//	if href := links[0].Attr("href"); href != "/home" {
+//		t.Errorf("expected the first link to be /home, got %q", href)
+//	}
+//
+// The output is parsed in the same way as browsers parse it, so it must be valid HTML, e.g.
+// <tr> elements outside a <table> element are dropped unless they're the root elements.
+func Query(t testing.TB, c templ.Component, selector string) []Element {
+	t.Helper()
+	return QueryHTML(t, RenderString(t, c), selector)
+}
+
+// QueryHTML returns the elements of the HTML that match the CSS selector, see Query.
+func QueryHTML(t testing.TB, s, selector string) []Element {
+	t.Helper()
+	roots, err := parse(s)
+	if err != nil {
+		t.Fatalf("failed to parse HTML: %v", err)
+	}
+	return query(t, roots, selector)
+}
+
+// Query returns the descendants of the element that match the CSS selector, see Query.
+func (e Element) Query(t testing.TB, selector string) []Element {
+	t.Helper()
+	var children []*html.Node
+	for c := e.node.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, c)
+	}
+	return query(t, children, selector)
+}
+
+func query(t testing.TB, roots []*html.Node, selector string) (elements []Element) {
+	t.Helper()
+	sel, err := parseSelector(selector)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if sel.match(n) {
+			elements = append(elements, Element{node: n})
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range roots {
+		walk(n)
+	}
+	return elements
+}
+
+// document matches the start of a complete HTML document.
+var document = regexp.MustCompile(`(?i)^\s*(<!--.*?-->\s*)*<(!doctype|html[\s>])`)
+
+// parse parses a complete HTML document, or a fragment of HTML, such as the output of a
+// component that isn't a page.
+func parse(s string) ([]*html.Node, error) {
+	if document.MatchString(s) {
+		doc, err := html.Parse(strings.NewReader(s))
+		if err != nil {
+			return nil, err
+		}
+		return []*html.Node{doc}, nil
+	}
+	// A <template> element can contain any element, e.g. the <tr> elements rendered by a
+	// table row component.
+	context := &html.Node{Type: html.ElementNode, Data: "template", DataAtom: atom.Template}
+	nodes, err := html.ParseFragment(strings.NewReader(s), context)
+	if err != nil {
+		return nil, err
+	}
+	// Join the nodes as siblings, so that sibling combinators and pseudo-classes work.
+	for i, n := range nodes {
+		if i > 0 {
+			n.PrevSibling = nodes[i-1]
+			nodes[i-1].NextSibling = n
+		}
+	}
+	return nodes, nil
+}
+
+// Name returns the name of the element, e.g. "div".
+func (e Element) Name() string {
+	return e.node.Data
+}
+
+// Attr returns the value of the attribute, or an empty string if the element doesn't have it.
+func (e Element) Attr(name string) string {
+	v, _ := attr(e.node, name)
+	return v
+}
+
+// HasAttr returns true if the element has the attribute, e.g. a boolean attribute such as
+// disabled.
+func (e Element) HasAttr(name string) bool {
+	_, ok := attr(e.node, name)
+	return ok
+}
+
+// Text returns the text content of the element and its descendants, with whitespace
+// collapsed, and leading and trailing whitespace removed.
+func (e Element) Text() string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(e.node)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// HTML returns the HTML of the element, including the element itself.
+func (e Element) HTML() string {
+	var sb strings.Builder
+	// Render only fails if the writer fails.
+	_ = html.Render(&sb, e.node)
+	return sb.String()
+}
@@ -0,0 +1,120 @@
+package templtest
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+const queryHTML = `<nav id="main" class="nav primary">
+	<ul>
+		<li class="active"><a href="/home">Home</a></li>
+		<li><a href="https://example.com/docs" target="_blank">Docs</a></li>
+		<li><a href="/about" hx-on:click="go()">About <b>us</b></a></li>
+	</ul>
+	<p lang="en-GB">Links: <span>3</span></p>
+	<button disabled>Menu</button>
+	<div></div>
+</nav>`
+
+func names(elements []Element) (names []string) {
+	for _, e := range elements {
+		names = append(names, e.Name()+":"+e.Text())
+	}
+	return names
+}
+
+func TestQueryHTML(t *testing.T) {
+	tests := []struct {
+		selector string
+		expected []string
+	}{
+		{selector: "a", expected: []string{"a:Home", "a:Docs", "a:About us"}},
+		{selector: "#main > p", expected: []string{"p:Links: 3"}},
+		{selector: "nav.primary.nav span", expected: []string{"span:3"}},
+		{selector: "li.active a", expected: []string{"a:Home"}},
+		{selector: "a[target]", expected: []string{"a:Docs"}},
+		{selector: `a[href="/about"]`, expected: []string{"a:About us"}},
+		{selector: "a[href^=https]", expected: []string{"a:Docs"}},
+		{selector: "a[href$='/home']", expected: []string{"a:Home"}},
+		{selector: "a[href*=example]", expected: []string{"a:Docs"}},
+		{selector: "p[lang|=en]", expected: []string{"p:Links: 3"}},
+		{selector: "nav[class~=primary]", expected: []string{"nav:Home Docs About us Links: 3 Menu"}},
+		{selector: `a[HREF="/HOME" i]`, expected: []string{"a:Home"}},
+		{selector: `a[hx-on\:click]`, expected: []string{"a:About us"}},
+		{selector: "li:first-child a, li:last-child a", expected: []string{"a:Home", "a:About us"}},
+		{selector: "li:nth-child(2) > a", expected: []string{"a:Docs"}},
+		{selector: "li:nth-child(odd)", expected: []string{"li:Home", "li:About us"}},
+		{selector: "li:not(.active)", expected: []string{"li:Docs", "li:About us"}},
+		{selector: "li.active + li", expected: []string{"li:Docs"}},
+		{selector: "ul ~ button", expected: []string{"button:Menu"}},
+		{selector: "b:only-child", expected: []string{"b:us"}},
+		{selector: "span:only-child", expected: []string{"span:3"}},
+		{selector: "div:empty", expected: []string{"div:"}},
+		{selector: "button[disabled]", expected: []string{"button:Menu"}},
+		{selector: "table", expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, names(QueryHTML(t, queryHTML, tt.selector))); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestQueryHTMLInvalidSelectors(t *testing.T) {
+	for _, selector := range []string{"", "a >", "a[href", "a[href!=x]", "li:hover", "a..b", `a[href="x]`} {
+		t.Run(selector, func(t *testing.T) {
+			ft := run("TestQuery", func(t *fakeT) { QueryHTML(t, queryHTML, selector) })
+			if !ft.failed {
+				t.Error("expected the test to fail")
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	row := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<tr><td>a</td><td class="n">1</td></tr><tr><td>b</td><td class="n">2</td></tr>`)
+		return err
+	})
+	t.Run("fragments can contain any element", func(t *testing.T) {
+		if diff := cmp.Diff([]string{"td:1", "td:2"}, names(Query(t, row, "tr > td.n"))); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]string{"tr:b2"}, names(Query(t, row, "tr + tr"))); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("documents are parsed", func(t *testing.T) {
+		page := `<!DOCTYPE html><html><head><title>Home</title></head><body><h1>Hello</h1></body></html>`
+		if diff := cmp.Diff([]string{"title:Home"}, names(QueryHTML(t, page, "head > title"))); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]string{"h1:Hello"}, names(QueryHTML(t, page, "body h1"))); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("elements can be queried", func(t *testing.T) {
+		rows := Query(t, row, "tr")
+		if diff := cmp.Diff([]string{"td:b"}, names(rows[1].Query(t, "td:first-child"))); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff(`<td class="n">2</td>`, rows[1].Query(t, ".n")[0].HTML()); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("attributes can be read", func(t *testing.T) {
+		buttons := QueryHTML(t, queryHTML, "button")
+		if !buttons[0].HasAttr("disabled") || buttons[0].HasAttr("type") {
+			t.Error("expected the button to have the disabled attribute only")
+		}
+		if href := QueryHTML(t, queryHTML, "a")[1].Attr("href"); href != "https://example.com/docs" {
+			t.Errorf("unexpected href %q", href)
+		}
+	})
+}
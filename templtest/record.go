@@ -0,0 +1,149 @@
+package templtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// Story is a component rendered with example data, e.g. a button in its disabled state.
+type Story struct {
+	// Name of the story, e.g. "Button/Disabled". Names are converted to file names, so they
+	// must be unique within a recording.
+	Name string
+	// Component to render. It's rendered as-is, so wrap it in a page layout if the stylesheets
+	// of the site are needed to take screenshots of it.
+	Component templ.Component
+}
+
+// Manifest lists the files written by Record. It's written to manifest.json in the output
+// directory, so that screenshot and visual diff tools can find the pages to load.
+type Manifest struct {
+	Stories []ManifestStory `json:"stories"`
+}
+
+// ManifestStory is a file written by Record.
+type ManifestStory struct {
+	// Name of the story.
+	Name string `json:"name"`
+	// File is the path of the HTML file, relative to the output directory.
+	File string `json:"file"`
+	// SHA256 of the contents of the file, so that unchanged stories can be skipped.
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestFileName is the name of the manifest written by Record.
+const ManifestFileName = "manifest.json"
+
+// Record renders the stories to HTML files in dir, and writes a manifest that lists them,
+// sorted by name. The test fails if a story fails to render, or two stories have the same
+// file name.
+//
+//	func TestRecordStories(t *testing.T) {
+//		templtest.Record(t, "screenshots", []templtest.Story{
+//			{Name: "Button/Primary", Component: Layout(Button("Save", true))},
+//			{Name: "Button/Disabled", Component: Layout(Button("Save", false))},
+//		})
+//	}
+//
+// File names are derived from the story names, e.g. button-primary.html, so they're stable
+// between runs. Files listed in a previous manifest in dir that aren't written by this run
+// are removed, so that deleted stories don't leave stale screenshots behind.
+func Record(t testing.TB, dir string, stories []Story) Manifest {
+	t.Helper()
+	m := Manifest{Stories: make([]ManifestStory, 0, len(stories))}
+	names := map[string]string{}
+	for _, s := range stories {
+		file := storyFileName(s.Name)
+		if other, ok := names[file]; ok {
+			t.Fatalf("stories %q and %q have the same file name %s", other, s.Name, file)
+		}
+		names[file] = s.Name
+		m.Stories = append(m.Stories, ManifestStory{Name: s.Name, File: file})
+	}
+	slices.SortFunc(m.Stories, func(a, b ManifestStory) int { return strings.Compare(a.Name, b.Name) })
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("failed to create output directory: %v", err)
+	}
+	if err := removeStaleStories(dir, names); err != nil {
+		t.Fatalf("failed to remove stale stories: %v", err)
+	}
+	components := make(map[string]templ.Component, len(stories))
+	for _, s := range stories {
+		components[s.Name] = s.Component
+	}
+	for i, ms := range m.Stories {
+		html := RenderString(t, components[ms.Name])
+		if err := os.WriteFile(filepath.Join(dir, ms.File), []byte(html), 0o644); err != nil {
+			t.Fatalf("failed to write story %q: %v", ms.Name, err)
+		}
+		hash := sha256.Sum256([]byte(html))
+		m.Stories[i].SHA256 = hex.EncodeToString(hash[:])
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, ManifestFileName), append(manifest, '\n'), 0o644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	return m
+}
+
+// storyFileName returns the file name of a story, e.g. "Button/Primary" is written to
+// button-primary.html.
+func storyFileName(name string) string {
+	var sb strings.Builder
+	var dash bool
+	for _, r := range strings.ToLower(name) {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("story")
+	}
+	return sb.String() + ".html"
+}
+
+// removeStaleStories removes the files listed in the previous manifest in dir, other than
+// those in keep.
+func removeStaleStories(dir string, keep map[string]string) error {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var previous Manifest
+	if err = json.Unmarshal(data, &previous); err != nil {
+		return err
+	}
+	for _, s := range previous.Stories {
+		if _, ok := keep[s.File]; ok || s.File != filepath.Base(s.File) {
+			continue
+		}
+		if err = os.Remove(filepath.Join(dir, s.File)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package templtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecord(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "stories")
+	stories := []Story{
+		{Name: "Page/Home", Component: page},
+		{Name: "Button / Disabled", Component: templ.Raw(`<button disabled>Save</button>`)},
+	}
+	m := Record(t, dir, stories)
+
+	expected := Manifest{
+		Stories: []ManifestStory{
+			{Name: "Button / Disabled", File: "button-disabled.html", SHA256: sha256Hex(`<button disabled>Save</button>`)},
+			{Name: "Page/Home", File: "page-home.html", SHA256: sha256Hex(RenderString(t, page))},
+		},
+	}
+	if diff := cmp.Diff(expected, m); diff != "" {
+		t.Error(diff)
+	}
+	html, err := os.ReadFile(filepath.Join(dir, "page-home.html"))
+	if err != nil {
+		t.Fatalf("failed to read story: %v", err)
+	}
+	if diff := cmp.Diff(RenderString(t, page), string(html)); diff != "" {
+		t.Error(diff)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var written Manifest
+	if err = json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("failed to unmarshal manifest: %v", err)
+	}
+	if diff := cmp.Diff(m, written); diff != "" {
+		t.Error(diff)
+	}
+
+	t.Run("stories that are no longer recorded are removed", func(t *testing.T) {
+		Record(t, dir, stories[:1])
+		if _, err := os.Stat(filepath.Join(dir, "button-disabled.html")); !os.IsNotExist(err) {
+			t.Errorf("expected button-disabled.html to be removed, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "page-home.html")); err != nil {
+			t.Errorf("expected page-home.html to be kept, got %v", err)
+		}
+	})
+	t.Run("duplicate file names fail the test", func(t *testing.T) {
+		dir := t.TempDir()
+		ft := run("TestRecord", func(t *fakeT) {
+			Record(t, dir, []Story{{Name: "Page/Home", Component: page}, {Name: "page home", Component: page}})
+		})
+		expected := []string{`stories "Page/Home" and "page home" have the same file name page-home.html`}
+		if diff := cmp.Diff(expected, ft.errors); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func sha256Hex(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,481 @@
+package templtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// selector is a list of complex selectors, separated by commas, e.g. "ul > li, p.note".
+type selector []complexSelector
+
+func (s selector) match(n *html.Node) bool {
+	for _, c := range s {
+		if c.match(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// complexSelector is a list of compound selectors, separated by combinators.
+type complexSelector struct {
+	compounds []compoundSelector
+	// combinators[i] is the combinator between compounds[i] and compounds[i+1]: ' ', '>', '+'
+	// or '~'.
+	combinators []byte
+}
+
+func (c complexSelector) match(n *html.Node) bool {
+	return c.matchAt(n, len(c.compounds)-1)
+}
+
+func (c complexSelector) matchAt(n *html.Node, i int) bool {
+	if !c.compounds[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch c.combinators[i-1] {
+	case ' ':
+		for p := parentElement(n); p != nil; p = parentElement(p) {
+			if c.matchAt(p, i-1) {
+				return true
+			}
+		}
+	case '>':
+		p := parentElement(n)
+		return p != nil && c.matchAt(p, i-1)
+	case '+':
+		s := previousElement(n)
+		return s != nil && c.matchAt(s, i-1)
+	case '~':
+		for s := previousElement(n); s != nil; s = previousElement(s) {
+			if c.matchAt(s, i-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compoundSelector matches an element with the type and all of the conditions, e.g.
+// "a.external[href]".
+type compoundSelector struct {
+	// tag is the element name, or empty to match any element.
+	tag        string
+	conditions []func(n *html.Node) bool
+}
+
+func (c compoundSelector) match(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if c.tag != "" && !strings.EqualFold(c.tag, n.Data) {
+		return false
+	}
+	for _, cond := range c.conditions {
+		if !cond(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func parentElement(n *html.Node) *html.Node {
+	if n.Parent != nil && n.Parent.Type == html.ElementNode {
+		return n.Parent
+	}
+	return nil
+}
+
+func previousElement(n *html.Node) *html.Node {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func nextElement(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, name string) (value string, ok bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// parseSelector parses a CSS selector. It supports type, universal, id, class and attribute
+// selectors, the descendant, child, next sibling and subsequent sibling combinators, and the
+// :first-child, :last-child, :only-child, :nth-child(), :empty and :not() pseudo-classes.
+func parseSelector(s string) (selector, error) {
+	p := &selectorParser{s: s}
+	sel, err := p.parseList()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", s, err)
+	}
+	if p.i < len(p.s) {
+		return nil, fmt.Errorf("invalid selector %q: unexpected %q at position %d", s, p.s[p.i], p.i)
+	}
+	return sel, nil
+}
+
+type selectorParser struct {
+	s string
+	i int
+}
+
+func (p *selectorParser) peek() byte {
+	if p.i < len(p.s) {
+		return p.s[p.i]
+	}
+	return 0
+}
+
+func (p *selectorParser) skipSpace() (skipped bool) {
+	for p.i < len(p.s) && strings.IndexByte(" \t\r\n\f", p.s[p.i]) >= 0 {
+		p.i++
+		skipped = true
+	}
+	return skipped
+}
+
+func (p *selectorParser) parseList() (sel selector, err error) {
+	for {
+		p.skipSpace()
+		c, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, c)
+		p.skipSpace()
+		if p.peek() != ',' {
+			return sel, nil
+		}
+		p.i++
+	}
+}
+
+func (p *selectorParser) parseComplex() (c complexSelector, err error) {
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return c, err
+		}
+		c.compounds = append(c.compounds, compound)
+		combinator := byte(' ')
+		if !p.skipSpace() {
+			combinator = 0
+		}
+		switch p.peek() {
+		case '>', '+', '~':
+			combinator = p.peek()
+			p.i++
+			p.skipSpace()
+		case 0, ',', ')':
+			return c, nil
+		}
+		if combinator == 0 {
+			return c, fmt.Errorf("unexpected %q at position %d", p.peek(), p.i)
+		}
+		c.combinators = append(c.combinators, combinator)
+	}
+}
+
+func (p *selectorParser) parseCompound() (c compoundSelector, err error) {
+	start := p.i
+	if p.peek() == '*' {
+		p.i++
+	} else if isIdentStart(p.peek()) {
+		if c.tag, err = p.parseIdent(); err != nil {
+			return c, err
+		}
+	}
+	for {
+		var cond func(n *html.Node) bool
+		switch p.peek() {
+		case '#':
+			p.i++
+			id, err := p.parseIdent()
+			if err != nil {
+				return c, err
+			}
+			cond = attributeCondition("id", "=", id, false)
+		case '.':
+			p.i++
+			class, err := p.parseIdent()
+			if err != nil {
+				return c, err
+			}
+			cond = attributeCondition("class", "~=", class, false)
+		case '[':
+			if cond, err = p.parseAttribute(); err != nil {
+				return c, err
+			}
+		case ':':
+			if cond, err = p.parsePseudo(); err != nil {
+				return c, err
+			}
+		default:
+			if p.i == start {
+				if p.i >= len(p.s) {
+					return c, fmt.Errorf("expected a selector at the end")
+				}
+				return c, fmt.Errorf("unexpected %q at position %d", p.peek(), p.i)
+			}
+			return c, nil
+		}
+		c.conditions = append(c.conditions, cond)
+	}
+}
+
+func isIdentStart(b byte) bool {
+	return b == '-' || b == '_' || b == '\\' || b >= 0x80 || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isIdentChar(b byte) bool {
+	return isIdentStart(b) || (b >= '0' && b <= '9')
+}
+
+// parseIdent parses a CSS identifier, in which characters can be escaped with a backslash,
+// e.g. "hx-on\:click".
+func (p *selectorParser) parseIdent() (string, error) {
+	var sb strings.Builder
+	for p.i < len(p.s) && isIdentChar(p.s[p.i]) {
+		if p.s[p.i] == '\\' {
+			p.i++
+			if p.i >= len(p.s) {
+				return "", fmt.Errorf("unexpected end of selector after \\")
+			}
+		}
+		sb.WriteByte(p.s[p.i])
+		p.i++
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("expected a name at position %d", p.i)
+	}
+	return sb.String(), nil
+}
+
+func (p *selectorParser) parseString() (string, error) {
+	quote := p.peek()
+	p.i++
+	var sb strings.Builder
+	for p.i < len(p.s) && p.s[p.i] != quote {
+		if p.s[p.i] == '\\' && p.i+1 < len(p.s) {
+			p.i++
+		}
+		sb.WriteByte(p.s[p.i])
+		p.i++
+	}
+	if p.i >= len(p.s) {
+		return "", fmt.Errorf("unterminated string")
+	}
+	p.i++
+	return sb.String(), nil
+}
+
+// parseAttribute parses an attribute selector, e.g. [href^="https:" i].
+func (p *selectorParser) parseAttribute() (func(n *html.Node) bool, error) {
+	p.i++
+	p.skipSpace()
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.i++
+		return func(n *html.Node) bool {
+			_, ok := attr(n, name)
+			return ok
+		}, nil
+	}
+	var op string
+	for _, candidate := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.s[p.i:], candidate) {
+			op = candidate
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("expected an attribute operator at position %d", p.i)
+	}
+	p.i += len(op)
+	p.skipSpace()
+	var value string
+	if q := p.peek(); q == '"' || q == '\'' {
+		value, err = p.parseString()
+	} else {
+		value, err = p.parseIdent()
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	ignoreCase := false
+	if b := p.peek(); b == 'i' || b == 'I' {
+		ignoreCase = true
+		p.i++
+		p.skipSpace()
+	}
+	if p.peek() != ']' {
+		return nil, fmt.Errorf("expected ] at position %d", p.i)
+	}
+	p.i++
+	return attributeCondition(name, op, value, ignoreCase), nil
+}
+
+func attributeCondition(name, op, value string, ignoreCase bool) func(n *html.Node) bool {
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+	return func(n *html.Node) bool {
+		actual, ok := attr(n, name)
+		if !ok {
+			return false
+		}
+		if ignoreCase {
+			actual = strings.ToLower(actual)
+		}
+		switch op {
+		case "=":
+			return actual == value
+		case "~=":
+			for _, word := range strings.Fields(actual) {
+				if word == value {
+					return true
+				}
+			}
+			return false
+		case "|=":
+			return actual == value || strings.HasPrefix(actual, value+"-")
+		case "^=":
+			return value != "" && strings.HasPrefix(actual, value)
+		case "$=":
+			return value != "" && strings.HasSuffix(actual, value)
+		case "*=":
+			return value != "" && strings.Contains(actual, value)
+		}
+		return false
+	}
+}
+
+func (p *selectorParser) parsePseudo() (func(n *html.Node) bool, error) {
+	p.i++
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(name) {
+	case "first-child":
+		return func(n *html.Node) bool { return previousElement(n) == nil }, nil
+	case "last-child":
+		return func(n *html.Node) bool { return nextElement(n) == nil }, nil
+	case "only-child":
+		return func(n *html.Node) bool { return previousElement(n) == nil && nextElement(n) == nil }, nil
+	case "empty":
+		return func(n *html.Node) bool {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode || (c.Type == html.TextNode && c.Data != "") {
+					return false
+				}
+			}
+			return true
+		}, nil
+	case "nth-child":
+		arg, err := p.parseArgument()
+		if err != nil {
+			return nil, err
+		}
+		a, b, err := parseNth(arg)
+		if err != nil {
+			return nil, err
+		}
+		return func(n *html.Node) bool {
+			index := 1
+			for s := previousElement(n); s != nil; s = previousElement(s) {
+				index++
+			}
+			if a == 0 {
+				return index == b
+			}
+			return (index-b)%a == 0 && (index-b)/a >= 0
+		}, nil
+	case "not":
+		if p.peek() != '(' {
+			return nil, fmt.Errorf("expected ( after :not at position %d", p.i)
+		}
+		p.i++
+		sel, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("expected ) at position %d", p.i)
+		}
+		p.i++
+		return func(n *html.Node) bool { return !sel.match(n) }, nil
+	}
+	return nil, fmt.Errorf("unsupported pseudo-class :%s", name)
+}
+
+// parseArgument returns the text between parentheses.
+func (p *selectorParser) parseArgument() (string, error) {
+	if p.peek() != '(' {
+		return "", fmt.Errorf("expected ( at position %d", p.i)
+	}
+	end := strings.IndexByte(p.s[p.i:], ')')
+	if end < 0 {
+		return "", fmt.Errorf("expected ) after position %d", p.i)
+	}
+	arg := p.s[p.i+1 : p.i+end]
+	p.i += end + 1
+	return strings.TrimSpace(arg), nil
+}
+
+// parseNth parses the an+b argument of :nth-child, e.g. "2n+1", "odd" or "3".
+func parseNth(arg string) (a, b int, err error) {
+	arg = strings.ToLower(strings.ReplaceAll(arg, " ", ""))
+	switch arg {
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+	before, after, hasN := strings.Cut(arg, "n")
+	if !hasN {
+		b, err = strconv.Atoi(arg)
+		return 0, b, err
+	}
+	switch before {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		if a, err = strconv.Atoi(before); err != nil {
+			return 0, 0, fmt.Errorf("invalid :nth-child argument %q", arg)
+		}
+	}
+	if after != "" {
+		if b, err = strconv.Atoi(after); err != nil {
+			return 0, 0, fmt.Errorf("invalid :nth-child argument %q", arg)
+		}
+	}
+	return a, b, nil
+}
@@ -0,0 +1,123 @@
+// Package templtest provides helpers for testing components, by rendering them to strings,
+// querying the output with CSS selectors, comparing the output with golden files, and
+// recording stories for visual regression tools.
+//
+//	func TestPage(t *testing.T) {
+//		templtest.AssertSnapshot(t, Page("Alice"))
+//	}
+//
+// Golden files are stored in the testdata directory of the package, named after the test,
+// e.g. testdata/TestPage.html. Run the tests with the TEMPLTEST_UPDATE environment variable set
+// to create or update them.
+//
+//	TEMPLTEST_UPDATE=1 go test ./...
+//
+// The output is compared with the golden files using Diff, so changes to insignificant
+// whitespace, and to the order of attributes, don't fail the tests.
+//
+// If the tests define their own -update flag, it also updates the golden files.
+package templtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// updating returns true if the TEMPLTEST_UPDATE environment variable is set to a true value,
+// or the tests define an -update flag, and it's set.
+func updating() bool {
+	if update, err := strconv.ParseBool(os.Getenv("TEMPLTEST_UPDATE")); err == nil {
+		return update
+	}
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// RenderString renders the component, and returns its output. The test fails if the
+// component returns an error.
+func RenderString(t testing.TB, c templ.Component) string {
+	t.Helper()
+	return RenderStringContext(t, context.Background(), c)
+}
+
+// RenderStringContext renders the component with the context, and returns its output. The
+// test fails if the component returns an error.
+func RenderStringContext(t testing.TB, ctx context.Context, c templ.Component) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := c.Render(ctx, &sb); err != nil {
+		t.Fatalf("failed to render component: %v", err)
+	}
+	return sb.String()
+}
+
+// AssertHTML fails the test if the HTML isn't equivalent to the expected HTML, see Diff.
+func AssertHTML(t testing.TB, expected, actual string) {
+	t.Helper()
+	diff, err := Diff(expected, actual)
+	if err != nil {
+		t.Fatalf("failed to compare HTML: %v", err)
+	}
+	if diff != "" {
+		t.Errorf("HTML mismatch (-expected +actual):\n%s", diff)
+	}
+}
+
+// AssertSnapshot renders the component, and compares its output with the golden file named
+// after the test, e.g. testdata/TestPage/empty.html for the subtest "empty" of TestPage.
+func AssertSnapshot(t testing.TB, c templ.Component) {
+	t.Helper()
+	AssertGolden(t, filepath.Join("testdata", filepath.FromSlash(t.Name())+".html"), RenderString(t, c))
+}
+
+// AssertGolden fails the test if the HTML isn't equivalent to the contents of the golden file,
+// see Diff. If TEMPLTEST_UPDATE is set, the golden file is written instead.
+// The HTML is indented when it's written, to make changes to it easier to review.
+func AssertGolden(t testing.TB, path, actual string) {
+	t.Helper()
+	if updating() {
+		if err := writeGolden(path, actual); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden file %s not found, run the test with TEMPLTEST_UPDATE=1 to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	diff, err := Diff(string(expected), actual)
+	if err != nil {
+		t.Fatalf("failed to compare HTML: %v", err)
+	}
+	if diff != "" {
+		t.Errorf("output doesn't match golden file %s, run the test with TEMPLTEST_UPDATE=1 to update it (-expected +actual):\n%s", path, diff)
+	}
+}
+
+func writeGolden(path, html string) error {
+	var buf bytes.Buffer
+	pw := templ.NewPrettyWriter(&buf)
+	if _, err := pw.Write([]byte(html)); err != nil {
+		return err
+	}
+	if err := pw.Flush(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
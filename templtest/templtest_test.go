@@ -0,0 +1,138 @@
+package templtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeT records the failures of a test.
+type fakeT struct {
+	testing.TB
+	name   string
+	errors []string
+	failed bool
+}
+
+func (t *fakeT) Helper()      {}
+func (t *fakeT) Name() string { return t.name }
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+	t.failed = true
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// run calls f with a fakeT in a new goroutine, so that Fatalf can stop it.
+func run(name string, f func(t *fakeT)) *fakeT {
+	t := &fakeT{name: name}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(t)
+	}()
+	<-done
+	return t
+}
+
+func setUpdate(t *testing.T, value bool) {
+	t.Setenv("TEMPLTEST_UPDATE", fmt.Sprint(value))
+}
+
+var page = templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, `<div class="page"><h1>Hello</h1><p>World</p></div>`)
+	return err
+})
+
+func TestRenderString(t *testing.T) {
+	if diff := cmp.Diff(`<div class="page"><h1>Hello</h1><p>World</p></div>`, RenderString(t, page)); diff != "" {
+		t.Error(diff)
+	}
+	t.Run("render errors fail the test", func(t *testing.T) {
+		failing := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return errors.New("render failed")
+		})
+		ft := run("TestFailing", func(t *fakeT) { RenderString(t, failing) })
+		if diff := cmp.Diff([]string{"failed to render component: render failed"}, ft.errors); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestAssertSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	t.Run("missing golden files fail the test", func(t *testing.T) {
+		ft := run("TestPage/home", func(t *fakeT) { AssertSnapshot(t, page) })
+		expected := []string{"golden file " + filepath.Join("testdata", "TestPage", "home.html") + " not found, run the test with TEMPLTEST_UPDATE=1 to create it"}
+		if diff := cmp.Diff(expected, ft.errors); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("golden files are written with TEMPLTEST_UPDATE", func(t *testing.T) {
+		setUpdate(t, true)
+		ft := run("TestPage/home", func(t *fakeT) { AssertSnapshot(t, page) })
+		if ft.failed {
+			t.Fatalf("unexpected failure: %v", ft.errors)
+		}
+		actual, err := os.ReadFile(filepath.Join("testdata", "TestPage", "home.html"))
+		if err != nil {
+			t.Fatalf("failed to read golden file: %v", err)
+		}
+		expected := "<div class=\"page\">\n\t<h1>Hello</h1>\n\t<p>World</p>\n</div>\n"
+		if diff := cmp.Diff(expected, string(actual)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("matching output passes", func(t *testing.T) {
+		ft := run("TestPage/home", func(t *fakeT) { AssertSnapshot(t, page) })
+		if ft.failed {
+			t.Errorf("unexpected failure: %v", ft.errors)
+		}
+	})
+	t.Run("changed output fails the test", func(t *testing.T) {
+		changed := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, `<div class="page"><h1>Goodbye</h1><p>World</p></div>`)
+			return err
+		})
+		ft := run("TestPage/home", func(t *fakeT) { AssertSnapshot(t, changed) })
+		if len(ft.errors) != 1 {
+			t.Fatalf("expected 1 error, got %v", ft.errors)
+		}
+		if !strings.Contains(ft.errors[0], "Hello") || !strings.Contains(ft.errors[0], "Goodbye") {
+			t.Errorf("expected the diff to show the changed text, got:\n%s", ft.errors[0])
+		}
+	})
+}
+
+func TestAssertHTML(t *testing.T) {
+	ft := run("TestHTML", func(t *fakeT) { AssertHTML(t, "<p>\n\tHello\n</p>", "<p>Hello</p>") })
+	if ft.failed {
+		t.Errorf("unexpected failure: %v", ft.errors)
+	}
+	ft = run("TestHTML", func(t *fakeT) { AssertHTML(t, "<p>Hello</p>", "<p>World</p>") })
+	if !ft.failed {
+		t.Error("expected the test to fail")
+	}
+}
@@ -0,0 +1,30 @@
+package templ
+
+import "context"
+
+// TextTransformer transforms text before it's HTML escaped and written to the output, e.g.
+// to replace straight quotes with typographic quotes, or emoji shortcodes with emoji.
+type TextTransformer func(s string) string
+
+// WithTextTransformers adds transformers that are applied, in order, to the text of
+// templates that were generated with text transformation enabled.
+//
+// Transformers receive unescaped text, and their output is escaped, so they can't be
+// used to add HTML elements to the output.
+func WithTextTransformers(ctx context.Context, transformers ...TextTransformer) context.Context {
+	ctx, v := getContext(ctx)
+	v.textTransformers = append(v.textTransformers, transformers...)
+	return ctx
+}
+
+// TransformText applies the text transformers in the context to s.
+func TransformText(ctx context.Context, s string) string {
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	if !ok {
+		return s
+	}
+	for _, t := range v.textTransformers {
+		s = t(s)
+	}
+	return s
+}
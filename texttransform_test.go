@@ -0,0 +1,49 @@
+package templ_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestTransformText(t *testing.T) {
+	upper := func(s string) string { return strings.ToUpper(s) }
+	exclaim := func(s string) string { return s + "!" }
+
+	t.Run("text is unchanged if no transformers are set", func(t *testing.T) {
+		if got := templ.TransformText(context.Background(), "hello"); got != "hello" {
+			t.Errorf("expected %q, got %q", "hello", got)
+		}
+	})
+	t.Run("transformers are applied in order", func(t *testing.T) {
+		ctx := templ.WithTextTransformers(context.Background(), exclaim, upper)
+		if got := templ.TransformText(ctx, "hello"); got != "HELLO!" {
+			t.Errorf("expected %q, got %q", "HELLO!", got)
+		}
+	})
+	t.Run("transformers can be added to", func(t *testing.T) {
+		ctx := templ.WithTextTransformers(context.Background(), upper)
+		ctx = templ.WithTextTransformers(ctx, exclaim)
+		if got := templ.TransformText(ctx, "hello"); got != "HELLO!" {
+			t.Errorf("expected %q, got %q", "HELLO!", got)
+		}
+	})
+	t.Run("transformers are available to components", func(t *testing.T) {
+		c := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, templ.EscapeString(templ.TransformText(ctx, "<hello>")))
+			return err
+		})
+		ctx := templ.WithTextTransformers(context.Background(), upper)
+		var buf bytes.Buffer
+		if err := c.Render(ctx, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "&lt;HELLO&gt;" {
+			t.Errorf("expected %q, got %q", "&lt;HELLO&gt;", buf.String())
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package templ
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// RenderSpan describes a generated component that is being rendered, see RenderTracer.
+type RenderSpan struct {
+	// Name is the package qualified name of the component, e.g. "pages.Home".
+	Name string
+	// FileName is the templ file that contains the component.
+	FileName string
+	// Line is the line of the templ file that the component is declared on, starting at 1.
+	Line int
+}
+
+// RenderTracer starts a span for each generated component that is rendered, e.g. to
+// create OpenTelemetry spans. See WithTracing.
+type RenderTracer interface {
+	// StartRender is called before a component renders. The returned context is passed to
+	// the component, so that the spans of child components are nested within it. end is
+	// called with the result of rendering once the component has rendered.
+	StartRender(ctx context.Context, span RenderSpan) (spanCtx context.Context, end func(err error))
+}
+
+// renderTracerUsed is set when a tracer is first added to a context, so that rendering
+// doesn't need to check the context for a tracer unless one has been used.
+var renderTracerUsed atomic.Bool
+
+// WithTracing adds a tracer to the context, that starts a span for each generated component
+// that is rendered with the context.
+//
+// Components only call the tracer if they're generated with the -tracing flag, and the
+// program is built with the templ_trace build tag.
+func WithTracing(ctx context.Context, t RenderTracer) context.Context {
+	renderTracerUsed.Store(true)
+	ctx, v := getContext(ctx)
+	v.renderTracer = t
+	return ctx
+}
+
+// GetRenderTracer returns the tracer in the context, or nil if there isn't one.
+func GetRenderTracer(ctx context.Context) RenderTracer {
+	if !renderTracerUsed.Load() {
+		return nil
+	}
+	v, ok := ctx.Value(contextKey).(*contextValue)
+	if !ok {
+		return nil
+	}
+	return v.renderTracer
+}
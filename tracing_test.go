@@ -0,0 +1,24 @@
+package templ_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+type nopTracer struct{}
+
+func (nopTracer) StartRender(ctx context.Context, span templ.RenderSpan) (context.Context, func(err error)) {
+	return ctx, func(err error) {}
+}
+
+func TestWithTracing(t *testing.T) {
+	if tracer := templ.GetRenderTracer(context.Background()); tracer != nil {
+		t.Errorf("expected no tracer, got %v", tracer)
+	}
+	ctx := templ.WithTracing(context.Background(), nopTracer{})
+	if _, ok := templ.GetRenderTracer(ctx).(nopTracer); !ok {
+		t.Errorf("expected the tracer to be returned, got %v", templ.GetRenderTracer(ctx))
+	}
+}
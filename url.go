@@ -1,8 +1,11 @@
 package templ
 
 import (
+	"context"
 	"errors"
 	"strings"
+
+	"github.com/a-h/templ/internal/urlscheme"
 )
 
 // FailedSanitizationURL is returned if a URL fails sanitization checks.
@@ -10,15 +13,22 @@ const FailedSanitizationURL = SafeURL("about:invalid#TemplFailedSanitizationURL"
 
 // URL sanitizes the input string s and returns a SafeURL.
 func URL(s string) SafeURL {
-	if i := strings.IndexRune(s, ':'); i >= 0 && !strings.ContainsRune(s[:i], '/') {
-		protocol := s[:i]
-		if !strings.EqualFold(protocol, "http") && !strings.EqualFold(protocol, "https") && !strings.EqualFold(protocol, "mailto") && !strings.EqualFold(protocol, "tel") && !strings.EqualFold(protocol, "ftp") && !strings.EqualFold(protocol, "ftps") {
+	if protocol, ok := urlScheme(s); ok {
+		if !urlscheme.IsSafe(protocol) {
 			return FailedSanitizationURL
 		}
 	}
 	return SafeURL(s)
 }
 
+// urlScheme returns the scheme of s, if it has one.
+func urlScheme(s string) (scheme string, ok bool) {
+	if i := strings.IndexRune(s, ':'); i >= 0 && !strings.ContainsRune(s[:i], '/') {
+		return s[:i], true
+	}
+	return "", false
+}
+
 // SafeURL is a URL that has been sanitized.
 type SafeURL string
 
@@ -29,3 +39,64 @@ func JoinURLErrs[T ~string](s T, errs ...error) (SafeURL, error) {
 	}
 	return URL(string(s)), errors.Join(errs...)
 }
+
+// URLSanitizer returns s as a SafeURL, or FailedSanitizationURL if it isn't safe to use.
+type URLSanitizer func(s string) SafeURL
+
+// WithURLSanitizer replaces URL as the sanitizer of the href, src, action and formaction
+// attributes of templates, e.g. to allow additional schemes with AllowURLSchemes, or to
+// apply a stricter policy.
+//
+// The sanitizer is only used by templates that were generated with the -url-sanitizer flag
+// of templ generate. Templates generated without it always sanitize URLs with URL, and
+// ignore the sanitizer in the context.
+//
+// Values of type SafeURL aren't passed to the sanitizer.
+func WithURLSanitizer(ctx context.Context, sanitizer URLSanitizer) context.Context {
+	ctx, v := getContext(ctx)
+	v.urlSanitizer = sanitizer
+	return ctx
+}
+
+// AllowURLSchemes returns a URLSanitizer that accepts the schemes that URL accepts, and the
+// additional schemes, e.g. "magnet".
+func AllowURLSchemes(schemes ...string) URLSanitizer {
+	return func(s string) SafeURL {
+		if scheme, ok := urlScheme(s); ok {
+			for _, allowed := range schemes {
+				if strings.EqualFold(scheme, strings.TrimSuffix(allowed, ":")) {
+					return SafeURL(s)
+				}
+			}
+		}
+		return URL(s)
+	}
+}
+
+// ContextURL is a URL that's sanitized when it's rendered, with the URLSanitizer in the
+// render context. It's used by templates that were generated with the -url-sanitizer flag.
+type ContextURL struct {
+	url  string
+	safe bool
+}
+
+// Sanitize returns the URL sanitized by the URLSanitizer in the context, or by URL if there
+// isn't one. URLs that were created from a SafeURL are returned unchanged.
+func (u ContextURL) Sanitize(ctx context.Context) SafeURL {
+	if u.safe {
+		return SafeURL(u.url)
+	}
+	if v, ok := ctx.Value(contextKey).(*contextValue); ok && v.urlSanitizer != nil {
+		return v.urlSanitizer(u.url)
+	}
+	return URL(u.url)
+}
+
+// JoinContextURLErrs joins an optional list of errors and returns a ContextURL, which is
+// sanitized when it's rendered.
+func JoinContextURLErrs[T ~string](s T, errs ...error) (ContextURL, error) {
+	if safeURL, ok := any(s).(SafeURL); ok {
+		return ContextURL{url: string(safeURL), safe: true}, errors.Join(errs...)
+	}
+	return ContextURL{url: string(s)}, errors.Join(errs...)
+}
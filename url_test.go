@@ -1,6 +1,7 @@
 package templ
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -114,3 +115,67 @@ func TestJoinURLErrs(t *testing.T) {
 		checkResult(t, result, err, SafeURL("https://example.com"))
 	})
 }
+
+func TestJoinContextURLErrs(t *testing.T) {
+	type CustomString string
+	urlOf := func(s string) (string, error) { return s, nil }
+	join := func(u ContextURL, err error) ContextURL {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return u
+	}
+
+	t.Run("URL is used by default", func(t *testing.T) {
+		if result := join(JoinContextURLErrs("magnet:?xt=urn:btih:1")).Sanitize(context.Background()); result != FailedSanitizationURL {
+			t.Errorf("expected %q, got %q", FailedSanitizationURL, result)
+		}
+	})
+	t.Run("the sanitizer in the context is used", func(t *testing.T) {
+		ctx := WithURLSanitizer(context.Background(), AllowURLSchemes("magnet", "myapp:"))
+		tests := []struct {
+			url      ContextURL
+			expected SafeURL
+		}{
+			{url: ContextURL{url: "magnet:?xt=urn:btih:1"}, expected: "magnet:?xt=urn:btih:1"},
+			{url: ContextURL{url: "https://example.com"}, expected: "https://example.com"},
+			{url: ContextURL{url: "javascript:alert(1)"}, expected: FailedSanitizationURL},
+		}
+		for _, tt := range tests {
+			if result := tt.url.Sanitize(ctx); result != tt.expected {
+				t.Errorf("%v: expected %q, got %q", tt.url, tt.expected, result)
+			}
+		}
+		if result := join(JoinContextURLErrs(CustomString("MyApp:open"))).Sanitize(ctx); result != "MyApp:open" {
+			t.Errorf("expected custom string types to be sanitized, got %q", result)
+		}
+	})
+	t.Run("stricter sanitizers can be used", func(t *testing.T) {
+		ctx := WithURLSanitizer(context.Background(), func(s string) SafeURL {
+			if !strings.HasPrefix(s, "https://") {
+				return FailedSanitizationURL
+			}
+			return SafeURL(s)
+		})
+		if result := join(JoinContextURLErrs("http://example.com")).Sanitize(ctx); result != FailedSanitizationURL {
+			t.Errorf("expected %q, got %q", FailedSanitizationURL, result)
+		}
+	})
+	t.Run("SafeURLs and errors are passed through", func(t *testing.T) {
+		ctx := WithURLSanitizer(context.Background(), func(s string) SafeURL { return FailedSanitizationURL })
+		expectedErr := errors.New("error")
+		u, err := JoinContextURLErrs(SafeURL("javascript:alert(1)"), expectedErr)
+		if result := u.Sanitize(ctx); result != "javascript:alert(1)" {
+			t.Errorf("expected the SafeURL to be returned, got %q", result)
+		}
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("expected the error to be returned, got %v", err)
+		}
+	})
+	t.Run("multiple return values can be passed", func(t *testing.T) {
+		if result := join(JoinContextURLErrs(urlOf("/a"))).Sanitize(context.Background()); result != "/a" {
+			t.Errorf("expected /a, got %q", result)
+		}
+	})
+}